@@ -25,6 +25,7 @@ import (
 	pubsub1 "github.com/devtron-labs/common-lib/pubsub-lib"
 	util4 "github.com/devtron-labs/common-lib/utils/k8s"
 	"github.com/devtron-labs/devtron/api/apiToken"
+	"github.com/devtron-labs/devtron/api/appSnapshot"
 	appStoreRestHandler "github.com/devtron-labs/devtron/api/appStore"
 	chartGroup2 "github.com/devtron-labs/devtron/api/appStore/chartGroup"
 	chartProvider "github.com/devtron-labs/devtron/api/appStore/chartProvider"
@@ -32,19 +33,32 @@ import (
 	appStoreDiscover "github.com/devtron-labs/devtron/api/appStore/discover"
 	appStoreValues "github.com/devtron-labs/devtron/api/appStore/values"
 	"github.com/devtron-labs/devtron/api/argoApplication"
+	"github.com/devtron-labs/devtron/api/auditLog"
+	"github.com/devtron-labs/devtron/api/auth/jitAccess"
+	"github.com/devtron-labs/devtron/api/auth/rbacExplainer"
+	"github.com/devtron-labs/devtron/api/auth/scim"
 	"github.com/devtron-labs/devtron/api/auth/sso"
 	"github.com/devtron-labs/devtron/api/auth/user"
 	chartRepo "github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
+	"github.com/devtron-labs/devtron/api/clusterCredential"
+	"github.com/devtron-labs/devtron/api/clusterOnboarding"
 	"github.com/devtron-labs/devtron/api/connector"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
 	"github.com/devtron-labs/devtron/api/deployment"
+	"github.com/devtron-labs/devtron/api/deploymentApproval"
 	"github.com/devtron-labs/devtron/api/devtronResource"
+	"github.com/devtron-labs/devtron/api/eventArchive"
 	"github.com/devtron-labs/devtron/api/externalLink"
 	fluxApplication "github.com/devtron-labs/devtron/api/fluxApplication"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	"github.com/devtron-labs/devtron/api/hibernationSchedule"
 	"github.com/devtron-labs/devtron/api/k8s"
 	"github.com/devtron-labs/devtron/api/module"
+	"github.com/devtron-labs/devtron/api/namespaceTemplate"
+	"github.com/devtron-labs/devtron/api/previewEnvironment"
+	"github.com/devtron-labs/devtron/api/projectGuardrail"
+	"github.com/devtron-labs/devtron/api/releaseTrain"
 	"github.com/devtron-labs/devtron/api/resourceScan"
 	"github.com/devtron-labs/devtron/api/restHandler"
 	"github.com/devtron-labs/devtron/api/restHandler/app/appInfo"
@@ -58,6 +72,7 @@ import (
 	"github.com/devtron-labs/devtron/api/restHandler/app/pipeline/webhook"
 	"github.com/devtron-labs/devtron/api/restHandler/app/workflow"
 	"github.com/devtron-labs/devtron/api/restHandler/scopedVariable"
+	"github.com/devtron-labs/devtron/api/rightsizing"
 	"github.com/devtron-labs/devtron/api/router"
 	app3 "github.com/devtron-labs/devtron/api/router/app"
 	appInfo2 "github.com/devtron-labs/devtron/api/router/app/appInfo"
@@ -212,6 +227,21 @@ func InitializeApp() (*App, error) {
 		devtronResource.DevtronResourceWireSet,
 		policyGovernance.PolicyGovernanceWireSet,
 		resourceScan.ScanningResultWireSet,
+		releaseTrain.ReleaseTrainWireSet,
+		previewEnvironment.PreviewEnvironmentWireSet,
+		hibernationSchedule.HibernationScheduleWireSet,
+		appSnapshot.AppSnapshotWireSet,
+		scim.ScimWireSet,
+		jitAccess.JitAccessWireSet,
+		rbacExplainer.RbacExplainerWireSet,
+		auditLog.AuditLogWireSet,
+		projectGuardrail.ProjectGuardrailWireSet,
+		deploymentApproval.DeploymentApprovalWireSet,
+		clusterOnboarding.ClusterOnboardingWireSet,
+		clusterCredential.ClusterCredentialWireSet,
+		rightsizing.RightsizingWireSet,
+		eventArchive.EventArchiveWireSet,
+		namespaceTemplate.NamespaceTemplateWireSet,
 
 		// -------wireset end ----------
 		// -------
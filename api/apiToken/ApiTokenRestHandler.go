@@ -38,6 +38,9 @@ type ApiTokenRestHandler interface {
 	UpdateApiToken(w http.ResponseWriter, r *http.Request)
 	DeleteApiToken(w http.ResponseWriter, r *http.Request)
 	GetAllApiTokensForWebhook(w http.ResponseWriter, r *http.Request)
+	GetApiTokenAccess(w http.ResponseWriter, r *http.Request)
+	UpdateApiTokenAccessPolicy(w http.ResponseWriter, r *http.Request)
+	RotateApiToken(w http.ResponseWriter, r *http.Request)
 }
 
 type ApiTokenRestHandlerImpl struct {
@@ -250,3 +253,135 @@ func (handler ApiTokenRestHandlerImpl) CheckAuthorizationForWebhook(token string
 	}
 	return true
 }
+
+func (impl ApiTokenRestHandlerImpl) GetApiTokenAccess(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+
+	// handle super-admin RBAC
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	// get api-token Id
+	vars := mux.Vars(r)
+	apiTokenId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		impl.logger.Errorw("request err in getting apiTokenId in GetApiTokenAccess", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	res, err := impl.apiTokenService.GetApiTokenAccess(apiTokenId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetApiTokenAccess", "err", err, "apiTokenId", apiTokenId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl ApiTokenRestHandlerImpl) UpdateApiTokenAccessPolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+
+	// handle super-admin RBAC
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	// get api-token Id
+	vars := mux.Vars(r)
+	apiTokenId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		impl.logger.Errorw("request err in getting apiTokenId in UpdateApiTokenAccessPolicy", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	// decode request
+	decoder := json.NewDecoder(r.Body)
+	var request *apiToken.ApiTokenAccessPolicy
+	err = decoder.Decode(&request)
+	if err != nil {
+		impl.logger.Errorw("err in decoding request, UpdateApiTokenAccessPolicy", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	// validate request
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err in UpdateApiTokenAccessPolicy", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	res, err := impl.apiTokenService.UpdateApiTokenAccessPolicy(apiTokenId, request, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, UpdateApiTokenAccessPolicy", "err", err, "apiTokenId", apiTokenId, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl ApiTokenRestHandlerImpl) RotateApiToken(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+
+	// handle super-admin RBAC
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	// get api-token Id
+	vars := mux.Vars(r)
+	apiTokenId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		impl.logger.Errorw("request err in getting apiTokenId in RotateApiToken", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	// decode request
+	decoder := json.NewDecoder(r.Body)
+	var request *apiToken.RotateApiTokenRequest
+	err = decoder.Decode(&request)
+	if err != nil {
+		impl.logger.Errorw("err in decoding request, RotateApiToken", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	// validate request
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err in RotateApiToken", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	res, err := impl.apiTokenService.RotateApiToken(apiTokenId, request, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, RotateApiToken", "err", err, "apiTokenId", apiTokenId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
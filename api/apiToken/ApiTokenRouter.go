@@ -38,4 +38,7 @@ func (impl ApiTokenRouterImpl) InitApiTokenRouter(configRouter *mux.Router) {
 	configRouter.Path("/{id}").HandlerFunc(impl.apiTokenRestHandler.UpdateApiToken).Methods("PUT")
 	configRouter.Path("/{id}").HandlerFunc(impl.apiTokenRestHandler.DeleteApiToken).Methods("DELETE")
 	configRouter.Path("/webhook").HandlerFunc(impl.apiTokenRestHandler.GetAllApiTokensForWebhook).Methods("GET")
+	configRouter.Path("/{id}/access").HandlerFunc(impl.apiTokenRestHandler.GetApiTokenAccess).Methods("GET")
+	configRouter.Path("/{id}/access").HandlerFunc(impl.apiTokenRestHandler.UpdateApiTokenAccessPolicy).Methods("PUT")
+	configRouter.Path("/{id}/rotate").HandlerFunc(impl.apiTokenRestHandler.RotateApiToken).Methods("POST")
 }
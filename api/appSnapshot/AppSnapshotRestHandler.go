@@ -0,0 +1,211 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appSnapshot
+
+import (
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/appSnapshot"
+	"github.com/devtron-labs/devtron/pkg/appSnapshot/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"io/ioutil"
+	"net/http"
+	"sigs.k8s.io/yaml"
+	"strconv"
+)
+
+type AppSnapshotRestHandler interface {
+	Export(w http.ResponseWriter, r *http.Request)
+	Plan(w http.ResponseWriter, r *http.Request)
+	Import(w http.ResponseWriter, r *http.Request)
+}
+
+type AppSnapshotRestHandlerImpl struct {
+	logger             *zap.SugaredLogger
+	userService        user.UserService
+	appSnapshotService appSnapshot.AppSnapshotService
+	enforcer           casbin.Enforcer
+	enforcerUtil       rbac.EnforcerUtil
+	validator          *validator.Validate
+}
+
+func NewAppSnapshotRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	appSnapshotService appSnapshot.AppSnapshotService, enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil, validator *validator.Validate) *AppSnapshotRestHandlerImpl {
+	return &AppSnapshotRestHandlerImpl{
+		logger:             logger,
+		userService:        userService,
+		appSnapshotService: appSnapshotService,
+		enforcer:           enforcer,
+		enforcerUtil:       enforcerUtil,
+		validator:          validator,
+	}
+}
+
+func (handler *AppSnapshotRestHandlerImpl) Export(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := strconv.Atoi(mux.Vars(r)["appId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// a snapshot carries the complete configuration of the app, so exporting needs edit access
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceApplications, casbin.ActionUpdate, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	snapshot, err := handler.appSnapshotService.Export(r.Context(), appId)
+	if err != nil {
+		handler.logger.Errorw("service err, Export", "err", err, "appId", appId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	content, err := yaml.Marshal(snapshot)
+	if err != nil {
+		handler.logger.Errorw("error in marshalling app snapshot", "err", err, "appId", appId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteOctetStreamResp(w, r, content, snapshot.Metadata.Name+bean.SnapshotFileExt)
+}
+
+func (handler *AppSnapshotRestHandlerImpl) Plan(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	snapshot, ok := handler.decodeAndAuthorise(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.appSnapshotService.Plan(r.Context(), snapshot)
+	if err != nil {
+		handler.logger.Errorw("service err, Plan", "err", err, "appName", snapshot.Metadata.Name)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *AppSnapshotRestHandlerImpl) Import(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	snapshot, ok := handler.decodeAndAuthorise(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.appSnapshotService.Import(r.Context(), snapshot, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, Import", "err", err, "appName", snapshot.Metadata.Name)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// decodeAndAuthorise reads a yaml or json snapshot from the body, and checks that the user can create the app
+// in its project or edit the existing app in both its current and snapshot project, and edit every environment
+// the snapshot overrides or deploys to
+func (handler *AppSnapshotRestHandlerImpl) decodeAndAuthorise(w http.ResponseWriter, r *http.Request) (*bean.AppSnapshot, bool) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	var snapshot bean.AppSnapshot
+	err = yaml.Unmarshal(body, &snapshot)
+	if err != nil {
+		handler.logger.Errorw("request err, app snapshot", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = handler.validator.Struct(snapshot)
+	if err != nil {
+		handler.logger.Errorw("validation err, app snapshot", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	appId, err := handler.appSnapshotService.GetAppId(snapshot.Metadata.Name)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	token := r.Header.Get("token")
+	if appId > 0 {
+		object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionUpdate, object); !ok {
+			common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+			return nil, false
+		}
+		// the snapshot may move the app to another project, which needs edit access there as well
+		teamId, err := handler.appSnapshotService.GetTeamId(snapshot.Metadata.Project)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return nil, false
+		}
+		object = handler.enforcerUtil.GetAppRBACNameByTeamIdAndAppId(teamId, appId)
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionUpdate, object); !ok {
+			common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+			return nil, false
+		}
+	} else if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionCreate, snapshot.Metadata.Project+"/*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return nil, false
+	}
+	for _, environment := range getEnvironmentNames(snapshot.Spec) {
+		object := handler.enforcerUtil.GetEnvRBACNameByAppAndEnvName(snapshot.Metadata.Name, environment)
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionUpdate, object); !ok {
+			common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+			return nil, false
+		}
+	}
+	return &snapshot, true
+}
+
+func getEnvironmentNames(spec *bean.SnapshotSpec) []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, env := range spec.Environments {
+		add(env.Name)
+	}
+	for _, wf := range spec.Workflows {
+		for _, cd := range wf.CdPipelines {
+			add(cd.Environment)
+		}
+	}
+	return names
+}
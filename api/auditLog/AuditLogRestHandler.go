@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditLog
+
+import (
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	"github.com/devtron-labs/devtron/pkg/auditLog/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"go.uber.org/zap"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const maxPageSize = 500
+
+type AuditLogRestHandler interface {
+	GetEvents(w http.ResponseWriter, r *http.Request)
+	ExportEvents(w http.ResponseWriter, r *http.Request)
+}
+
+type AuditLogRestHandlerImpl struct {
+	logger          *zap.SugaredLogger
+	userService     user.UserService
+	auditLogService auditLog.AuditLogService
+	enforcer        casbin.Enforcer
+}
+
+func NewAuditLogRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	auditLogService auditLog.AuditLogService, enforcer casbin.Enforcer) *AuditLogRestHandlerImpl {
+	return &AuditLogRestHandlerImpl{
+		logger:          logger,
+		userService:     userService,
+		auditLogService: auditLogService,
+		enforcer:        enforcer,
+	}
+}
+
+func (handler *AuditLogRestHandlerImpl) GetEvents(w http.ResponseWriter, r *http.Request) {
+	if !handler.isSuperAdmin(w, r) {
+		return
+	}
+	filter, err := getFilter(r.URL.Query())
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.auditLogService.GetEvents(filter)
+	if err != nil {
+		handler.logger.Errorw("service err, GetEvents", "err", err, "filter", filter)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// ExportEvents writes matching events as a json or csv file, paging parameters other than offset are ignored
+func (handler *AuditLogRestHandlerImpl) ExportEvents(w http.ResponseWriter, r *http.Request) {
+	if !handler.isSuperAdmin(w, r) {
+		return
+	}
+	query := r.URL.Query()
+	filter, err := getFilter(query)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	format := bean.ExportFormat(query.Get("format"))
+	if len(format) == 0 {
+		format = bean.ExportFormatJson
+	}
+	if format != bean.ExportFormatJson && format != bean.ExportFormatCsv {
+		common.WriteJsonResp(w, fmt.Errorf("unsupported export format %s, use json or csv", format), nil, http.StatusBadRequest)
+		return
+	}
+	contentType := "application/json"
+	if format == bean.ExportFormatCsv {
+		contentType = "text/csv"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=audit-events.%s", format))
+	err = handler.auditLogService.ExportEvents(filter, format, w)
+	if err != nil {
+		// the response may be partially written, the error can only be logged
+		handler.logger.Errorw("service err, ExportEvents", "err", err, "filter", filter)
+	}
+}
+
+func (handler *AuditLogRestHandlerImpl) isSuperAdmin(w http.ResponseWriter, r *http.Request) bool {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return false
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func getFilter(query url.Values) (*bean.AuditEventFilter, error) {
+	filter := &bean.AuditEventFilter{
+		EmailId:  query.Get("emailId"),
+		Resource: query.Get("resource"),
+		Action:   bean.Action(query.Get("action")),
+		Outcome:  bean.Outcome(query.Get("outcome")),
+		Size:     20,
+	}
+	var err error
+	if value := query.Get("userId"); len(value) > 0 {
+		userId, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid userId %s", value)
+		}
+		filter.UserId = int32(userId)
+	}
+	if filter.From, err = getTime(query, "from"); err != nil {
+		return nil, err
+	}
+	if filter.To, err = getTime(query, "to"); err != nil {
+		return nil, err
+	}
+	if value := query.Get("offset"); len(value) > 0 {
+		filter.Offset, err = strconv.Atoi(value)
+		if err != nil || filter.Offset < 0 {
+			return nil, fmt.Errorf("invalid offset %s", value)
+		}
+	}
+	if value := query.Get("size"); len(value) > 0 {
+		filter.Size, err = strconv.Atoi(value)
+		if err != nil || filter.Size <= 0 || filter.Size > maxPageSize {
+			return nil, fmt.Errorf("invalid size %s, it should be between 1 and %d", value, maxPageSize)
+		}
+	}
+	return filter, nil
+}
+
+func getTime(query url.Values, key string) (*time.Time, error) {
+	value := query.Get(key)
+	if len(value) == 0 {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %s, it should be in RFC3339 format", key, value)
+	}
+	return &parsed, nil
+}
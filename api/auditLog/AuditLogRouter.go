@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditLog
+
+import "github.com/gorilla/mux"
+
+type AuditLogRouter interface {
+	InitAuditLogRouter(auditLogRouter *mux.Router)
+}
+
+type AuditLogRouterImpl struct {
+	auditLogRestHandler AuditLogRestHandler
+}
+
+func NewAuditLogRouterImpl(auditLogRestHandler AuditLogRestHandler) *AuditLogRouterImpl {
+	return &AuditLogRouterImpl{
+		auditLogRestHandler: auditLogRestHandler,
+	}
+}
+
+func (router *AuditLogRouterImpl) InitAuditLogRouter(auditLogRouter *mux.Router) {
+	auditLogRouter.Path("/events").HandlerFunc(router.auditLogRestHandler.GetEvents).Methods("GET")
+	auditLogRouter.Path("/events/export").HandlerFunc(router.auditLogRestHandler.ExportEvents).Methods("GET")
+}
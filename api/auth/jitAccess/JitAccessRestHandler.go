@@ -0,0 +1,254 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jitAccess
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/jitAccess"
+	jitAccessBean "github.com/devtron-labs/devtron/pkg/auth/jitAccess/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type JitAccessRestHandler interface {
+	CreateRequest(w http.ResponseWriter, r *http.Request)
+	GetRequests(w http.ResponseWriter, r *http.Request)
+	GetRequest(w http.ResponseWriter, r *http.Request)
+	GetAudit(w http.ResponseWriter, r *http.Request)
+	Approve(w http.ResponseWriter, r *http.Request)
+	Reject(w http.ResponseWriter, r *http.Request)
+	Cancel(w http.ResponseWriter, r *http.Request)
+	Revoke(w http.ResponseWriter, r *http.Request)
+}
+
+type JitAccessRestHandlerImpl struct {
+	logger            *zap.SugaredLogger
+	userService       user.UserService
+	userCommonService user.UserCommonService
+	jitAccessService  jitAccess.JitAccessService
+	enforcer          casbin.Enforcer
+	validator         *validator.Validate
+}
+
+func NewJitAccessRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	userCommonService user.UserCommonService, jitAccessService jitAccess.JitAccessService,
+	enforcer casbin.Enforcer, validator *validator.Validate) *JitAccessRestHandlerImpl {
+	return &JitAccessRestHandlerImpl{
+		logger:            logger,
+		userService:       userService,
+		userCommonService: userCommonService,
+		jitAccessService:  jitAccessService,
+		enforcer:          enforcer,
+		validator:         validator,
+	}
+}
+
+func (handler *JitAccessRestHandlerImpl) CreateRequest(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request jitAccessBean.JitAccessRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, CreateRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, CreateRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.jitAccessService.CreateRequest(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// GetRequests lists requests of the user and requests the user can approve, only requests of the user when self is set
+func (handler *JitAccessRestHandlerImpl) GetRequests(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	query := r.URL.Query()
+	filter := &jitAccessBean.ListFilter{Status: jitAccessBean.RequestStatus(query.Get("status"))}
+	if self, _ := strconv.ParseBool(query.Get("self")); self {
+		filter.UserId = userId
+	}
+	requests, err := handler.jitAccessService.GetAll(filter)
+	if err != nil {
+		handler.logger.Errorw("service err, GetRequests", "err", err, "filter", filter)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	res := make([]*jitAccessBean.JitAccessRequestDto, 0, len(requests))
+	for _, request := range requests {
+		if request.UserId == userId || handler.isApprover(token, request.RoleFilter) {
+			res = append(res, request)
+		}
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *JitAccessRestHandlerImpl) GetRequest(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request, ok := handler.getAuthorisedRequest(w, r, userId, true)
+	if !ok {
+		return
+	}
+	common.WriteJsonResp(w, nil, request, http.StatusOK)
+}
+
+func (handler *JitAccessRestHandlerImpl) GetAudit(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request, ok := handler.getAuthorisedRequest(w, r, userId, true)
+	if !ok {
+		return
+	}
+	res, err := handler.jitAccessService.GetAudit(request.Id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAudit", "err", err, "id", request.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *JitAccessRestHandlerImpl) Approve(w http.ResponseWriter, r *http.Request) {
+	handler.performAction(w, r, false, handler.jitAccessService.Approve)
+}
+
+func (handler *JitAccessRestHandlerImpl) Reject(w http.ResponseWriter, r *http.Request) {
+	handler.performAction(w, r, false, handler.jitAccessService.Reject)
+}
+
+func (handler *JitAccessRestHandlerImpl) Cancel(w http.ResponseWriter, r *http.Request) {
+	handler.performAction(w, r, true, handler.jitAccessService.Cancel)
+}
+
+// Revoke can be done by an approver, or by the requester giving the access up early
+func (handler *JitAccessRestHandlerImpl) Revoke(w http.ResponseWriter, r *http.Request) {
+	handler.performAction(w, r, true, handler.jitAccessService.Revoke)
+}
+
+func (handler *JitAccessRestHandlerImpl) performAction(w http.ResponseWriter, r *http.Request, allowRequester bool,
+	action func(request *jitAccessBean.ActionRequest) (*jitAccessBean.JitAccessRequestDto, error)) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request jitAccessBean.ActionRequest
+	if r.ContentLength != 0 {
+		err = json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			handler.logger.Errorw("request err, jit access action", "err", err, "payload", request)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, jit access action", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	accessRequest, ok := handler.getAuthorisedRequest(w, r, userId, allowRequester)
+	if !ok {
+		return
+	}
+	request.Id = accessRequest.Id
+	request.UserId = userId
+	res, err := action(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, jit access action", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// getAuthorisedRequest returns the request of the path if the user can approve it, or is its requester and allowRequester is set
+func (handler *JitAccessRestHandlerImpl) getAuthorisedRequest(w http.ResponseWriter, r *http.Request, userId int32, allowRequester bool) (*jitAccessBean.JitAccessRequestDto, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid id", http.StatusBadRequest)
+		return nil, false
+	}
+	request, err := handler.jitAccessService.GetById(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetById", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	if allowRequester && request.UserId == userId {
+		return request, true
+	}
+	if !handler.isApprover(r.Header.Get("token"), request.RoleFilter) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return nil, false
+	}
+	return request, true
+}
+
+// isApprover checks the user could grant the role filter through user management, as checked on user create
+func (handler *JitAccessRestHandlerImpl) isApprover(token string, filter *bean.RoleFilter) bool {
+	if handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*") {
+		return true
+	}
+	switch {
+	case filter.AccessType == userBean.APP_ACCESS_TYPE_HELM || filter.Entity == userBean.EntityJobs:
+		return false
+	case len(filter.Team) > 0:
+		return handler.enforcer.Enforce(token, casbin.ResourceUser, casbin.ActionCreate, filter.Team)
+	case filter.Entity == userBean.CLUSTER_ENTITIY:
+		return handler.userCommonService.CheckRbacForClusterEntity(filter.Cluster, filter.Namespace, filter.Group, filter.Kind, filter.Resource, token, handler.checkManagerAuth)
+	default:
+		return false
+	}
+}
+
+func (handler *JitAccessRestHandlerImpl) checkManagerAuth(resource, token string, object string) bool {
+	return handler.enforcer.Enforce(token, resource, casbin.ActionUpdate, object)
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jitAccess
+
+import "github.com/gorilla/mux"
+
+type JitAccessRouter interface {
+	InitJitAccessRouter(jitAccessRouter *mux.Router)
+}
+
+type JitAccessRouterImpl struct {
+	jitAccessRestHandler JitAccessRestHandler
+}
+
+func NewJitAccessRouterImpl(jitAccessRestHandler JitAccessRestHandler) *JitAccessRouterImpl {
+	return &JitAccessRouterImpl{
+		jitAccessRestHandler: jitAccessRestHandler,
+	}
+}
+
+func (router *JitAccessRouterImpl) InitJitAccessRouter(jitAccessRouter *mux.Router) {
+	jitAccessRouter.Path("").HandlerFunc(router.jitAccessRestHandler.CreateRequest).Methods("POST")
+	jitAccessRouter.Path("").HandlerFunc(router.jitAccessRestHandler.GetRequests).Methods("GET")
+	jitAccessRouter.Path("/{id}").HandlerFunc(router.jitAccessRestHandler.GetRequest).Methods("GET")
+	jitAccessRouter.Path("/{id}/audit").HandlerFunc(router.jitAccessRestHandler.GetAudit).Methods("GET")
+	jitAccessRouter.Path("/{id}/approve").HandlerFunc(router.jitAccessRestHandler.Approve).Methods("PUT")
+	jitAccessRouter.Path("/{id}/reject").HandlerFunc(router.jitAccessRestHandler.Reject).Methods("PUT")
+	jitAccessRouter.Path("/{id}/cancel").HandlerFunc(router.jitAccessRestHandler.Cancel).Methods("PUT")
+	jitAccessRouter.Path("/{id}/revoke").HandlerFunc(router.jitAccessRestHandler.Revoke).Methods("PUT")
+}
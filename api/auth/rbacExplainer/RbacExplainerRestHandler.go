@@ -0,0 +1,147 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacExplainer
+
+import (
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/rbacExplainer"
+	"github.com/devtron-labs/devtron/pkg/auth/rbacExplainer/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strings"
+)
+
+type RbacExplainerRestHandler interface {
+	GetPermissions(w http.ResponseWriter, r *http.Request)
+	Check(w http.ResponseWriter, r *http.Request)
+	GetSubjects(w http.ResponseWriter, r *http.Request)
+}
+
+type RbacExplainerRestHandlerImpl struct {
+	logger               *zap.SugaredLogger
+	userService          user.UserService
+	rbacExplainerService rbacExplainer.RbacExplainerService
+	enforcer             casbin.Enforcer
+	validator            *validator.Validate
+}
+
+func NewRbacExplainerRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	rbacExplainerService rbacExplainer.RbacExplainerService, enforcer casbin.Enforcer,
+	validator *validator.Validate) *RbacExplainerRestHandlerImpl {
+	return &RbacExplainerRestHandlerImpl{
+		logger:               logger,
+		userService:          userService,
+		rbacExplainerService: rbacExplainerService,
+		enforcer:             enforcer,
+		validator:            validator,
+	}
+}
+
+func (handler *RbacExplainerRestHandlerImpl) GetPermissions(w http.ResponseWriter, r *http.Request) {
+	emailId, ok := handler.getAuthorisedEmailId(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.rbacExplainerService.GetPermissions(emailId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPermissions", "err", err, "emailId", emailId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *RbacExplainerRestHandlerImpl) Check(w http.ResponseWriter, r *http.Request) {
+	emailId, ok := handler.getAuthorisedEmailId(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	request := &bean.CheckRequest{
+		EmailId:  emailId,
+		Resource: query.Get("resource"),
+		Action:   query.Get("action"),
+		Object:   query.Get("object"),
+	}
+	err := handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, Check", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.rbacExplainerService.Check(request)
+	if err != nil {
+		handler.logger.Errorw("service err, Check", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *RbacExplainerRestHandlerImpl) GetSubjects(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	query := r.URL.Query()
+	resource, action, object := query.Get("resource"), query.Get("action"), query.Get("object")
+	if len(resource) == 0 || len(action) == 0 || len(object) == 0 {
+		common.WriteJsonResp(w, errors.New("resource, action and object are required"), nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.rbacExplainerService.GetSubjects(resource, action, object)
+	if err != nil {
+		handler.logger.Errorw("service err, GetSubjects", "err", err, "resource", resource, "action", action, "object", object)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// getAuthorisedEmailId returns the emailId of the query, or of the logged in user when not set.
+// Only super admins can explain the permissions of other users
+func (handler *RbacExplainerRestHandlerImpl) getAuthorisedEmailId(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return "", false
+	}
+	loggedInEmailId, err := handler.userService.GetActiveEmailById(userId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetActiveEmailById", "err", err, "userId", userId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return "", false
+	}
+	emailId := r.URL.Query().Get("emailId")
+	if len(emailId) == 0 || strings.EqualFold(emailId, loggedInEmailId) {
+		return loggedInEmailId, true
+	}
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return "", false
+	}
+	return emailId, true
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacExplainer
+
+import "github.com/gorilla/mux"
+
+type RbacExplainerRouter interface {
+	InitRbacExplainerRouter(rbacExplainerRouter *mux.Router)
+}
+
+type RbacExplainerRouterImpl struct {
+	rbacExplainerRestHandler RbacExplainerRestHandler
+}
+
+func NewRbacExplainerRouterImpl(rbacExplainerRestHandler RbacExplainerRestHandler) *RbacExplainerRouterImpl {
+	return &RbacExplainerRouterImpl{
+		rbacExplainerRestHandler: rbacExplainerRestHandler,
+	}
+}
+
+func (router *RbacExplainerRouterImpl) InitRbacExplainerRouter(rbacExplainerRouter *mux.Router) {
+	rbacExplainerRouter.Path("/permissions").HandlerFunc(router.rbacExplainerRestHandler.GetPermissions).Methods("GET")
+	rbacExplainerRouter.Path("/check").HandlerFunc(router.rbacExplainerRestHandler.Check).Methods("GET")
+	rbacExplainerRouter.Path("/subjects").HandlerFunc(router.rbacExplainerRestHandler.GetSubjects).Methods("GET")
+}
@@ -0,0 +1,363 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/scim"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ScimRestHandler serves RFC 7644 requests of identity providers. Requests are authenticated with the scim
+// api token sent as a bearer token, and responses use scim errors instead of the orchestrator response format
+type ScimRestHandler interface {
+	GetServiceProviderConfig(w http.ResponseWriter, r *http.Request)
+	GetResourceTypes(w http.ResponseWriter, r *http.Request)
+
+	ListUsers(w http.ResponseWriter, r *http.Request)
+	CreateUser(w http.ResponseWriter, r *http.Request)
+	GetUser(w http.ResponseWriter, r *http.Request)
+	ReplaceUser(w http.ResponseWriter, r *http.Request)
+	PatchUser(w http.ResponseWriter, r *http.Request)
+	DeleteUser(w http.ResponseWriter, r *http.Request)
+
+	ListGroups(w http.ResponseWriter, r *http.Request)
+	CreateGroup(w http.ResponseWriter, r *http.Request)
+	GetGroup(w http.ResponseWriter, r *http.Request)
+	ReplaceGroup(w http.ResponseWriter, r *http.Request)
+	PatchGroup(w http.ResponseWriter, r *http.Request)
+	DeleteGroup(w http.ResponseWriter, r *http.Request)
+}
+
+type ScimRestHandlerImpl struct {
+	logger      *zap.SugaredLogger
+	scimService scim.ScimService
+	userService user.UserService
+	validator   *validator.Validate
+}
+
+func NewScimRestHandlerImpl(logger *zap.SugaredLogger, scimService scim.ScimService, userService user.UserService,
+	validator *validator.Validate) *ScimRestHandlerImpl {
+	return &ScimRestHandlerImpl{
+		logger:      logger,
+		scimService: scimService,
+		userService: userService,
+		validator:   validator,
+	}
+}
+
+const bearerPrefix = "bearer "
+
+func (handler *ScimRestHandlerImpl) authenticate(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	authorization := r.Header.Get("Authorization")
+	token := ""
+	if len(authorization) > len(bearerPrefix) && strings.EqualFold(authorization[:len(bearerPrefix)], bearerPrefix) {
+		token = strings.TrimSpace(authorization[len(bearerPrefix):])
+	}
+	userId, err := handler.scimService.Authenticate(r.Context(), token, handler.userService.GetClientIp(r))
+	if err != nil {
+		handler.writeError(w, err)
+		return 0, false
+	}
+	return userId, true
+}
+
+func (handler *ScimRestHandlerImpl) writeResponse(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", bean.ContentType)
+	w.WriteHeader(status)
+	if body == nil {
+		return
+	}
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		handler.logger.Errorw("error in writing scim response", "err", err)
+	}
+}
+
+func (handler *ScimRestHandlerImpl) writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	scimType := ""
+	detail := err.Error()
+	var scimErr *bean.ScimError
+	var apiErr *util.ApiError
+	if errors.As(err, &scimErr) {
+		status, scimType = scimErr.Status, scimErr.ScimType
+	} else if errors.As(err, &apiErr) {
+		if apiErr.HttpStatusCode > 0 {
+			status = apiErr.HttpStatusCode
+		}
+		if userMessage, ok := apiErr.UserMessage.(string); ok && len(userMessage) > 0 {
+			detail = userMessage
+		}
+	}
+	if status == http.StatusInternalServerError {
+		handler.logger.Errorw("service err, scim", "err", err)
+	}
+	handler.writeResponse(w, status, &bean.Error{
+		Schemas:  []string{bean.ErrorSchema},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+func (handler *ScimRestHandlerImpl) writeInvalidSyntax(w http.ResponseWriter, err error) {
+	handler.writeError(w, bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidSyntax, err.Error()))
+}
+
+func getListRequest(r *http.Request) *bean.ListRequest {
+	query := r.URL.Query()
+	request := &bean.ListRequest{
+		Filter:     query.Get("filter"),
+		StartIndex: 1,
+		Count:      bean.MaxResults,
+	}
+	if startIndex, err := strconv.Atoi(query.Get("startIndex")); err == nil {
+		request.StartIndex = startIndex
+	}
+	if count, err := strconv.Atoi(query.Get("count")); err == nil {
+		request.Count = count
+	}
+	for _, attribute := range strings.Split(query.Get("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attribute), "members") {
+			request.ExcludeMembers = true
+		}
+	}
+	return request
+}
+
+func (handler *ScimRestHandlerImpl) GetServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	handler.writeResponse(w, http.StatusOK, handler.scimService.GetServiceProviderConfig())
+}
+
+func (handler *ScimRestHandlerImpl) GetResourceTypes(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	resourceTypes := handler.scimService.GetResourceTypes()
+	handler.writeResponse(w, http.StatusOK, &bean.ListResponse{
+		Schemas:      []string{bean.ListResponseSchema},
+		TotalResults: len(resourceTypes),
+		StartIndex:   1,
+		ItemsPerPage: len(resourceTypes),
+		Resources:    resourceTypes,
+	})
+}
+
+func (handler *ScimRestHandlerImpl) ListUsers(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	res, err := handler.scimService.ListUsers(getListRequest(r))
+	if err != nil {
+		handler.writeError(w, err)
+		return
+	}
+	handler.writeResponse(w, http.StatusOK, res)
+}
+
+func (handler *ScimRestHandlerImpl) CreateUser(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	var request bean.User
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		handler.writeInvalidSyntax(w, err)
+		return
+	}
+	res, err := handler.scimService.CreateUser(&request, userId)
+	if err != nil {
+		handler.writeError(w, err)
+		return
+	}
+	w.Header().Set("Location", res.Meta.Location)
+	handler.writeResponse(w, http.StatusCreated, res)
+}
+
+func (handler *ScimRestHandlerImpl) GetUser(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	res, err := handler.scimService.GetUser(mux.Vars(r)["id"])
+	if err != nil {
+		handler.writeError(w, err)
+		return
+	}
+	handler.writeResponse(w, http.StatusOK, res)
+}
+
+func (handler *ScimRestHandlerImpl) ReplaceUser(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	var request bean.User
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		handler.writeInvalidSyntax(w, err)
+		return
+	}
+	res, err := handler.scimService.ReplaceUser(mux.Vars(r)["id"], &request, userId)
+	if err != nil {
+		handler.writeError(w, err)
+		return
+	}
+	handler.writeResponse(w, http.StatusOK, res)
+}
+
+func (handler *ScimRestHandlerImpl) PatchUser(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	request, ok := handler.decodePatchRequest(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.scimService.PatchUser(mux.Vars(r)["id"], request, userId)
+	if err != nil {
+		handler.writeError(w, err)
+		return
+	}
+	handler.writeResponse(w, http.StatusOK, res)
+}
+
+func (handler *ScimRestHandlerImpl) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	if err := handler.scimService.DeleteUser(mux.Vars(r)["id"], userId); err != nil {
+		handler.writeError(w, err)
+		return
+	}
+	handler.writeResponse(w, http.StatusNoContent, nil)
+}
+
+func (handler *ScimRestHandlerImpl) ListGroups(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	res, err := handler.scimService.ListGroups(getListRequest(r))
+	if err != nil {
+		handler.writeError(w, err)
+		return
+	}
+	handler.writeResponse(w, http.StatusOK, res)
+}
+
+func (handler *ScimRestHandlerImpl) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	var request bean.Group
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		handler.writeInvalidSyntax(w, err)
+		return
+	}
+	res, err := handler.scimService.CreateGroup(&request, userId)
+	if err != nil {
+		handler.writeError(w, err)
+		return
+	}
+	w.Header().Set("Location", res.Meta.Location)
+	handler.writeResponse(w, http.StatusCreated, res)
+}
+
+func (handler *ScimRestHandlerImpl) GetGroup(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	res, err := handler.scimService.GetGroup(mux.Vars(r)["id"], getListRequest(r).ExcludeMembers)
+	if err != nil {
+		handler.writeError(w, err)
+		return
+	}
+	handler.writeResponse(w, http.StatusOK, res)
+}
+
+func (handler *ScimRestHandlerImpl) ReplaceGroup(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	var request bean.Group
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		handler.writeInvalidSyntax(w, err)
+		return
+	}
+	res, err := handler.scimService.ReplaceGroup(mux.Vars(r)["id"], &request, userId)
+	if err != nil {
+		handler.writeError(w, err)
+		return
+	}
+	handler.writeResponse(w, http.StatusOK, res)
+}
+
+func (handler *ScimRestHandlerImpl) PatchGroup(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	request, ok := handler.decodePatchRequest(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.scimService.PatchGroup(mux.Vars(r)["id"], request, userId)
+	if err != nil {
+		handler.writeError(w, err)
+		return
+	}
+	handler.writeResponse(w, http.StatusOK, res)
+}
+
+func (handler *ScimRestHandlerImpl) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	if err := handler.scimService.DeleteGroup(mux.Vars(r)["id"], userId); err != nil {
+		handler.writeError(w, err)
+		return
+	}
+	handler.writeResponse(w, http.StatusNoContent, nil)
+}
+
+func (handler *ScimRestHandlerImpl) decodePatchRequest(w http.ResponseWriter, r *http.Request) (*bean.PatchRequest, bool) {
+	var request bean.PatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		handler.writeInvalidSyntax(w, err)
+		return nil, false
+	}
+	if err := handler.validator.Struct(request); err != nil {
+		handler.writeInvalidSyntax(w, err)
+		return nil, false
+	}
+	return &request, true
+}
@@ -38,6 +38,7 @@ type SsoLoginRestHandler interface {
 	GetAllSSOLoginConfig(w http.ResponseWriter, r *http.Request)
 	GetSSOLoginConfig(w http.ResponseWriter, r *http.Request)
 	GetSSOLoginConfigByName(w http.ResponseWriter, r *http.Request)
+	DeactivateSSOLoginConfig(w http.ResponseWriter, r *http.Request)
 }
 
 type SsoLoginRestHandlerImpl struct {
@@ -70,6 +71,12 @@ func (handler SsoLoginRestHandlerImpl) CreateSSOLoginConfig(w http.ResponseWrite
 		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
 		return
 	}
+	err = handler.validator.Struct(dto)
+	if err != nil {
+		handler.logger.Errorw("validation err, CreateSSOLoginConfig", "err", err, "payload", dto)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
 
 	token := r.Header.Get("token")
 	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !ok {
@@ -103,6 +110,12 @@ func (handler SsoLoginRestHandlerImpl) UpdateSSOLoginConfig(w http.ResponseWrite
 		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
 		return
 	}
+	err = handler.validator.Struct(dto)
+	if err != nil {
+		handler.logger.Errorw("validation err, UpdateSSOLoginConfig", "err", err, "payload", dto)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
 
 	token := r.Header.Get("token")
 	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
@@ -184,3 +197,32 @@ func (handler SsoLoginRestHandlerImpl) GetSSOLoginConfigByName(w http.ResponseWr
 	}
 	common.WriteJsonResp(w, nil, res, http.StatusOK)
 }
+
+func (handler SsoLoginRestHandlerImpl) DeactivateSSOLoginConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		handler.logger.Errorw("request err, DeactivateSSOLoginConfig", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	res, err := handler.ssoLoginService.DeactivateSSOLogin(int32(id), userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeactivateSSOLoginConfig", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
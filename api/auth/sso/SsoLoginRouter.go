@@ -44,6 +44,8 @@ func (router SsoLoginRouterImpl) InitSsoLoginRouter(userAuthRouter *mux.Router)
 		HandlerFunc(router.handler.GetAllSSOLoginConfig).Methods("GET")
 	userAuthRouter.Path("/{id}").
 		HandlerFunc(router.handler.GetSSOLoginConfig).Methods("GET")
+	userAuthRouter.Path("/{id}/deactivate").
+		HandlerFunc(router.handler.DeactivateSSOLoginConfig).Methods("PUT")
 	userAuthRouter.Path("").Methods("GET").
 		Queries("name", "{name}").HandlerFunc(router.handler.GetSSOLoginConfigByName)
 }
@@ -18,6 +18,7 @@ package sso
 
 import (
 	sso2 "github.com/devtron-labs/devtron/pkg/auth/sso"
+	"github.com/devtron-labs/devtron/pkg/auth/sso/repository"
 	"github.com/google/wire"
 )
 
@@ -26,8 +27,8 @@ import (
 var SsoConfigWireSet = wire.NewSet(
 	sso2.NewSSOLoginServiceImpl,
 	wire.Bind(new(sso2.SSOLoginService), new(*sso2.SSOLoginServiceImpl)),
-	sso2.NewSSOLoginRepositoryImpl,
-	wire.Bind(new(sso2.SSOLoginRepository), new(*sso2.SSOLoginRepositoryImpl)),
+	repository.NewSSOLoginRepositoryImpl,
+	wire.Bind(new(repository.SSOLoginRepository), new(*repository.SSOLoginRepositoryImpl)),
 
 	NewSsoLoginRouterImpl,
 	wire.Bind(new(SsoLoginRouter), new(*SsoLoginRouterImpl)),
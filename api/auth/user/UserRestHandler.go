@@ -31,6 +31,7 @@ import (
 	"github.com/devtron-labs/devtron/api/bean"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
 	user2 "github.com/devtron-labs/devtron/pkg/auth/user"
 	bean2 "github.com/devtron-labs/devtron/pkg/auth/user/bean"
@@ -192,12 +193,14 @@ func (handler UserRestHandlerImpl) UpdateUser(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	before := handler.getUserState(userInfo.Id)
 	res, err := handler.userService.UpdateUser(&userInfo, token, handler.checkRBACForUserUpdate, handler.CheckManagerAuth)
 	if err != nil {
 		handler.logger.Errorw("service err, UpdateUser", "err", err, "payload", userInfo)
 		common.WriteJsonResp(w, err, "", http.StatusInternalServerError)
 		return
 	}
+	auditLog.RecordStateChange(r.Context(), before, handler.getUserState(userInfo.Id))
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 	//if len(restrictedGroups) == 0 {
 	//	common.WriteJsonResp(w, err, res, http.StatusOK)
@@ -217,6 +220,16 @@ func (handler UserRestHandlerImpl) UpdateUser(w http.ResponseWriter, r *http.Req
 	//}
 }
 
+// getUserState returns the user with its roles and groups for the audit of its update
+func (handler UserRestHandlerImpl) getUserState(userId int32) *bean.UserInfo {
+	userInfo, err := handler.userService.GetById(userId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching user state for audit", "userId", userId, "err", err)
+		return nil
+	}
+	return userInfo
+}
+
 func (handler UserRestHandlerImpl) GetById(w http.ResponseWriter, r *http.Request) {
 	userId, err := handler.userService.GetLoggedInUser(r)
 	if userId == 0 || err != nil {
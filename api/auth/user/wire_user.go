@@ -49,6 +49,7 @@ var UserWireSet = wire.NewSet(
 	wire.Bind(new(user2.UserService), new(*user2.UserServiceImpl)),
 	repository2.NewUserRepositoryImpl,
 	wire.Bind(new(repository2.UserRepository), new(*repository2.UserRepositoryImpl)),
+	wire.Bind(new(casbin.ApiTokenScopeReader), new(*repository2.UserRepositoryImpl)),
 	user2.NewRoleGroupServiceImpl,
 	wire.Bind(new(user2.RoleGroupService), new(*user2.RoleGroupServiceImpl)),
 	repository2.NewRoleGroupRepositoryImpl,
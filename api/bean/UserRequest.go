@@ -108,13 +108,29 @@ type RoleData struct {
 }
 
 type SSOLoginDto struct {
-	Id     int32           `json:"id"`
-	Name   string          `json:"name,omitempty"`
-	Label  string          `json:"label,omitempty"`
-	Url    string          `json:"url,omitempty"`
-	Config json.RawMessage `json:"config,omitempty"`
-	Active bool            `json:"active"`
-	UserId int32           `json:"-"`
+	Id                 int32                  `json:"id"`
+	Name               string                 `json:"name,omitempty"`
+	Label              string                 `json:"label,omitempty"`
+	Url                string                 `json:"url,omitempty"`
+	Config             json.RawMessage        `json:"config,omitempty"`
+	Active             bool                   `json:"active"`
+	ConnectorId        string                 `json:"connectorId,omitempty"`
+	ProvisioningPolicy *SsoProvisioningPolicy `json:"provisioningPolicy,omitempty"`
+	UserId             int32                  `json:"-"`
+}
+
+// SsoProvisioningPolicy decides how users signing in through an sso connector are mapped to role groups
+type SsoProvisioningPolicy struct {
+	// GroupsClaim is the token claim holding the user's groups, defaults to groups
+	GroupsClaim   string             `json:"groupsClaim,omitempty"`
+	GroupMappings []*SsoGroupMapping `json:"groupMappings,omitempty" validate:"dive"`
+	// AutoProvision creates unknown users on login even if self registration is disabled
+	AutoProvision bool `json:"autoProvision"`
+}
+
+type SsoGroupMapping struct {
+	Group      string   `json:"group" validate:"required"`
+	RoleGroups []string `json:"roleGroups" validate:"required,min=1"`
 }
 
 const (
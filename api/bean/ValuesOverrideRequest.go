@@ -92,6 +92,7 @@ type ValuesOverrideRequest struct {
 	Namespace                             string                      `json:"-"`
 	ReleaseName                           string                      `json:"-"`
 	Image                                 string                      `json:"-"`
+	IsDryRun                              bool                        `json:"-"` // renders values without persisting pipeline override or syncing cluster resources
 }
 
 type BulkCdDeployEvent struct {
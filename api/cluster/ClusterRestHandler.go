@@ -34,6 +34,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/genericNotes/repository"
 
 	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/cluster"
 	delete2 "github.com/devtron-labs/devtron/pkg/delete"
 	util2 "github.com/devtron-labs/devtron/util"
@@ -60,6 +61,7 @@ type ClusterRestHandler interface {
 	GetClusterNamespaces(w http.ResponseWriter, r *http.Request)
 	GetAllClusterNamespaces(w http.ResponseWriter, r *http.Request)
 	FindAllForClusterPermission(w http.ResponseWriter, r *http.Request)
+	GetConnectionHealth(w http.ResponseWriter, r *http.Request)
 }
 
 type ClusterRestHandlerImpl struct {
@@ -407,16 +409,28 @@ func (impl ClusterRestHandlerImpl) Update(w http.ResponseWriter, r *http.Request
 	if util2.IsBaseStack() {
 		ctx = context.WithValue(ctx, "token", token)
 	}
+	before := impl.getClusterState(bean.Id)
 	_, err = impl.clusterService.Update(ctx, &bean, userId)
 	if err != nil {
 		impl.logger.Errorw("service err, Update", "error", err, "payload", bean)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	auditLog.RecordStateChange(r.Context(), before, impl.getClusterState(bean.Id))
 
 	common.WriteJsonResp(w, err, bean, http.StatusOK)
 }
 
+// getClusterState returns the cluster with its config for the audit of its update, only hashes of it are kept
+func (impl ClusterRestHandlerImpl) getClusterState(clusterId int) *bean2.ClusterBean {
+	cluster, err := impl.clusterService.FindById(clusterId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster state for audit", "clusterId", clusterId, "err", err)
+		return nil
+	}
+	return cluster
+}
+
 func (impl ClusterRestHandlerImpl) UpdateClusterDescription(w http.ResponseWriter, r *http.Request) {
 	token := r.Header.Get("token")
 	decoder := json.NewDecoder(r.Body)
@@ -710,3 +724,45 @@ func (impl ClusterRestHandlerImpl) FindAllForClusterPermission(w http.ResponseWr
 	}
 	common.WriteJsonResp(w, err, clusterList, http.StatusOK)
 }
+
+// GetConnectionHealth returns the connectivity health of the clusters over the last hours, flapping clusters are marked
+func (impl ClusterRestHandlerImpl) GetConnectionHealth(w http.ResponseWriter, r *http.Request) {
+	clusterIds, err := common.ExtractIntArrayFromQueryParam(r, "clusterIds")
+	if err != nil {
+		impl.logger.Errorw("request err, GetConnectionHealth", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	hours, err := common.ExtractIntQueryParam(w, r, "hours", 24)
+	if err != nil {
+		return
+	}
+	if hours <= 0 {
+		common.WriteJsonResp(w, errors.New("hours should be positive"), nil, http.StatusBadRequest)
+		return
+	}
+	withHistory, err := common.ExtractBoolQueryParam(r, "history")
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	from := time.Now().Add(-time.Duration(hours) * time.Hour)
+	summaries, err := impl.clusterService.GetConnectionHealth(clusterIds, from, withHistory)
+	if err != nil {
+		impl.logger.Errorw("service err, GetConnectionHealth", "err", err, "clusterIds", clusterIds)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	result := make([]*bean2.ClusterConnectionHealthSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		if ok := impl.enforcer.Enforce(token, casbin.ResourceCluster, casbin.ActionGet, summary.ClusterName); ok {
+			result = append(result, summary)
+		}
+	}
+	//RBAC enforcer Ends
+
+	common.WriteJsonResp(w, nil, result, http.StatusOK)
+}
@@ -47,6 +47,10 @@ func (impl ClusterRouterImpl) InitClusterRouter(clusterRouter *mux.Router) {
 		Methods("POST").
 		HandlerFunc(impl.clusterRestHandler.ValidateKubeconfig)
 
+	clusterRouter.Path("/health").
+		Methods("GET").
+		HandlerFunc(impl.clusterRestHandler.GetConnectionHealth)
+
 	clusterRouter.Path("").
 		Methods("GET").
 		Queries("id", "{id}").
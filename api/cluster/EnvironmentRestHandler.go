@@ -38,6 +38,7 @@ import (
 	"github.com/devtron-labs/devtron/api/bean"
 
 	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	delete2 "github.com/devtron-labs/devtron/pkg/delete"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
@@ -266,7 +267,12 @@ func (impl EnvironmentRestHandlerImpl) Update(w http.ResponseWriter, r *http.Req
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
-	common.WriteJsonResp(w, err, res, http.StatusOK)
+	updatedEnvironment, err := impl.environmentClusterMappingsService.FindById(bean.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment state for audit", "envId", bean.Id, "err", err)
+	}
+	auditLog.RecordStateChange(r.Context(), modifiedEnvironment, updatedEnvironment)
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
 }
 
 func (impl EnvironmentRestHandlerImpl) FindById(w http.ResponseWriter, r *http.Request) {
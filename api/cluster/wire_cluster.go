@@ -34,6 +34,8 @@ import (
 var ClusterWireSet = wire.NewSet(
 	repository.NewClusterRepositoryImpl,
 	wire.Bind(new(repository.ClusterRepository), new(*repository.ClusterRepositoryImpl)),
+	repository.NewClusterConnectionHealthRepositoryImpl,
+	wire.Bind(new(repository.ClusterConnectionHealthRepository), new(*repository.ClusterConnectionHealthRepositoryImpl)),
 	cluster.NewClusterServiceImpl,
 	cluster.NewClusterServiceImplExtended,
 	wire.Bind(new(cluster.ClusterService), new(*cluster.ClusterServiceImplExtended)),
@@ -63,7 +65,7 @@ var ClusterWireSet = wire.NewSet(
 
 	repository3.NewEnvironmentRepositoryImpl,
 	wire.Bind(new(repository3.EnvironmentRepository), new(*repository3.EnvironmentRepositoryImpl)),
-	environment.NewEnvironmentServiceImpl,
+	environment.NewEnvironmentServiceImplWithNamespaceProvisioner,
 	wire.Bind(new(environment.EnvironmentService), new(*environment.EnvironmentServiceImpl)),
 	read2.NewEnvironmentReadServiceImpl,
 	wire.Bind(new(read2.EnvironmentReadService), new(*read2.EnvironmentReadServiceImpl)),
@@ -77,6 +79,8 @@ var ClusterWireSet = wire.NewSet(
 var ClusterWireSetEa = wire.NewSet(
 	repository.NewClusterRepositoryImpl,
 	wire.Bind(new(repository.ClusterRepository), new(*repository.ClusterRepositoryImpl)),
+	repository.NewClusterConnectionHealthRepositoryImpl,
+	wire.Bind(new(repository.ClusterConnectionHealthRepository), new(*repository.ClusterConnectionHealthRepositoryImpl)),
 	rbac.NewClusterRbacServiceImpl,
 	wire.Bind(new(rbac.ClusterRbacService), new(*rbac.ClusterRbacServiceImpl)),
 	cluster.NewClusterServiceImpl,
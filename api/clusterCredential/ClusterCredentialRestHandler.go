@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterCredential
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/clusterCredential"
+	"github.com/devtron-labs/devtron/pkg/clusterCredential/bean"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+type ClusterCredentialRestHandler interface {
+	GetExpiry(w http.ResponseWriter, r *http.Request)
+	RotateCredentials(w http.ResponseWriter, r *http.Request)
+}
+
+type ClusterCredentialRestHandlerImpl struct {
+	logger                   *zap.SugaredLogger
+	userService              user.UserService
+	clusterCredentialService clusterCredential.ClusterCredentialService
+	clusterService           cluster.ClusterService
+	enforcer                 casbin.Enforcer
+}
+
+func NewClusterCredentialRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	clusterCredentialService clusterCredential.ClusterCredentialService,
+	clusterService cluster.ClusterService, enforcer casbin.Enforcer) *ClusterCredentialRestHandlerImpl {
+	return &ClusterCredentialRestHandlerImpl{
+		logger:                   logger,
+		userService:              userService,
+		clusterCredentialService: clusterCredentialService,
+		clusterService:           clusterService,
+		enforcer:                 enforcer,
+	}
+}
+
+// GetExpiry returns the credential expiry of the clusters the user can view
+func (handler *ClusterCredentialRestHandlerImpl) GetExpiry(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	clusterIds, err := common.ExtractIntArrayFromQueryParam(r, "clusterIds")
+	if err != nil {
+		handler.logger.Errorw("request err, GetExpiry", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	expiries, err := handler.clusterCredentialService.GetExpiry(clusterIds)
+	if err != nil {
+		handler.logger.Errorw("service err, GetExpiry", "err", err, "clusterIds", clusterIds)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	res := make([]*bean.ClusterCredentialExpiry, 0, len(expiries))
+	for _, expiry := range expiries {
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceCluster, casbin.ActionGet, expiry.ClusterName); ok {
+			res = append(res, expiry)
+		}
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ClusterCredentialRestHandlerImpl) RotateCredentials(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	clusterId, err := strconv.Atoi(mux.Vars(r)["clusterId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request bean.RotateCredentialsRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, RotateCredentials", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.ClusterId = clusterId
+	request.UserId = userId
+	existingCluster, err := handler.clusterService.FindByIdWithoutConfig(clusterId)
+	if err != nil {
+		handler.logger.Errorw("service err, RotateCredentials", "err", err, "clusterId", clusterId)
+		if util.IsErrNoRows(err) {
+			common.WriteJsonResp(w, err, "cluster not found", http.StatusNotFound)
+			return
+		}
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceCluster, casbin.ActionUpdate, existingCluster.ClusterName); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	ctx := context.WithValue(r.Context(), "token", token)
+	res, err := handler.clusterCredentialService.RotateCredentials(ctx, &request)
+	if err != nil {
+		handler.logger.Errorw("service err, RotateCredentials", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterCredential
+
+import "github.com/gorilla/mux"
+
+type ClusterCredentialRouter interface {
+	InitClusterCredentialRouter(clusterCredentialRouter *mux.Router)
+}
+
+type ClusterCredentialRouterImpl struct {
+	clusterCredentialRestHandler ClusterCredentialRestHandler
+}
+
+func NewClusterCredentialRouterImpl(clusterCredentialRestHandler ClusterCredentialRestHandler) *ClusterCredentialRouterImpl {
+	return &ClusterCredentialRouterImpl{
+		clusterCredentialRestHandler: clusterCredentialRestHandler,
+	}
+}
+
+func (router *ClusterCredentialRouterImpl) InitClusterCredentialRouter(clusterCredentialRouter *mux.Router) {
+	clusterCredentialRouter.Path("/expiry").HandlerFunc(router.clusterCredentialRestHandler.GetExpiry).Methods("GET")
+	clusterCredentialRouter.Path("/{clusterId}/rotate").HandlerFunc(router.clusterCredentialRestHandler.RotateCredentials).Methods("PUT")
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterOnboarding
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/clusterOnboarding"
+	"github.com/devtron-labs/devtron/pkg/clusterOnboarding/bean"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+)
+
+type ClusterOnboardingRestHandler interface {
+	BulkOnboard(w http.ResponseWriter, r *http.Request)
+}
+
+type ClusterOnboardingRestHandlerImpl struct {
+	logger                   *zap.SugaredLogger
+	userService              user.UserService
+	clusterOnboardingService clusterOnboarding.ClusterOnboardingService
+	enforcer                 casbin.Enforcer
+	validator                *validator.Validate
+}
+
+func NewClusterOnboardingRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	clusterOnboardingService clusterOnboarding.ClusterOnboardingService,
+	enforcer casbin.Enforcer, validator *validator.Validate) *ClusterOnboardingRestHandlerImpl {
+	return &ClusterOnboardingRestHandlerImpl{
+		logger:                   logger,
+		userService:              userService,
+		clusterOnboardingService: clusterOnboardingService,
+		enforcer:                 enforcer,
+		validator:                validator,
+	}
+}
+
+func (handler *ClusterOnboardingRestHandlerImpl) BulkOnboard(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.BulkOnboardRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, BulkOnboard", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, BulkOnboard", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// onboarding creates clusters, environments and changes project guardrails
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	ctx := context.WithValue(r.Context(), "token", token)
+	res, err := handler.clusterOnboardingService.BulkOnboard(ctx, &request)
+	if err != nil {
+		handler.logger.Errorw("service err, BulkOnboard", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
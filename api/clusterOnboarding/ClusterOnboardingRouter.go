@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterOnboarding
+
+import "github.com/gorilla/mux"
+
+type ClusterOnboardingRouter interface {
+	InitClusterOnboardingRouter(clusterOnboardingRouter *mux.Router)
+}
+
+type ClusterOnboardingRouterImpl struct {
+	clusterOnboardingRestHandler ClusterOnboardingRestHandler
+}
+
+func NewClusterOnboardingRouterImpl(clusterOnboardingRestHandler ClusterOnboardingRestHandler) *ClusterOnboardingRouterImpl {
+	return &ClusterOnboardingRouterImpl{
+		clusterOnboardingRestHandler: clusterOnboardingRestHandler,
+	}
+}
+
+func (router *ClusterOnboardingRouterImpl) InitClusterOnboardingRouter(clusterOnboardingRouter *mux.Router) {
+	clusterOnboardingRouter.Path("/bulk").HandlerFunc(router.clusterOnboardingRestHandler.BulkOnboard).Methods("POST")
+}
@@ -0,0 +1,309 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentApproval
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type DeploymentApprovalRestHandler interface {
+	GetAllRules(w http.ResponseWriter, r *http.Request)
+	GetRule(w http.ResponseWriter, r *http.Request)
+	SaveRule(w http.ResponseWriter, r *http.Request)
+	DeleteRule(w http.ResponseWriter, r *http.Request)
+	GetRequests(w http.ResponseWriter, r *http.Request)
+	GetRequest(w http.ResponseWriter, r *http.Request)
+	Approve(w http.ResponseWriter, r *http.Request)
+	Reject(w http.ResponseWriter, r *http.Request)
+	Cancel(w http.ResponseWriter, r *http.Request)
+}
+
+type DeploymentApprovalRestHandlerImpl struct {
+	logger                           *zap.SugaredLogger
+	userService                      user.UserService
+	deploymentApprovalService        deploymentApproval.DeploymentApprovalService
+	approvedDeploymentTriggerService devtronApps.ApprovedDeploymentTriggerService
+	enforcer                         casbin.Enforcer
+	enforcerUtil                     rbac.EnforcerUtil
+	validator                        *validator.Validate
+}
+
+func NewDeploymentApprovalRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	deploymentApprovalService deploymentApproval.DeploymentApprovalService,
+	approvedDeploymentTriggerService devtronApps.ApprovedDeploymentTriggerService,
+	enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate) *DeploymentApprovalRestHandlerImpl {
+	return &DeploymentApprovalRestHandlerImpl{
+		logger:                           logger,
+		userService:                      userService,
+		deploymentApprovalService:        deploymentApprovalService,
+		approvedDeploymentTriggerService: approvedDeploymentTriggerService,
+		enforcer:                         enforcer,
+		enforcerUtil:                     enforcerUtil,
+		validator:                        validator,
+	}
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) GetAllRules(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkSuperAdmin(w, r); !ok {
+		return
+	}
+	res, err := handler.deploymentApprovalService.GetAllRules()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAllRules", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) GetRule(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkSuperAdmin(w, r); !ok {
+		return
+	}
+	envId, err := strconv.Atoi(mux.Vars(r)["envId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.deploymentApprovalService.GetRule(envId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetRule", "envId", envId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) SaveRule(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkSuperAdmin(w, r)
+	if !ok {
+		return
+	}
+	envId, err := strconv.Atoi(mux.Vars(r)["envId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request bean.ProtectionRuleDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveRule", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.EnvironmentId = envId
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveRule", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.deploymentApprovalService.SaveRule(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveRule", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkSuperAdmin(w, r)
+	if !ok {
+		return
+	}
+	envId, err := strconv.Atoi(mux.Vars(r)["envId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.deploymentApprovalService.DeleteRule(envId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteRule", "envId", envId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, envId, http.StatusOK)
+}
+
+// GetRequests lists the approval requests of the apps the user can view
+func (handler *DeploymentApprovalRestHandlerImpl) GetRequests(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	query := r.URL.Query()
+	filter := &bean.ListFilter{Status: bean.RequestStatus(query.Get("status"))}
+	if pipelineId := query.Get("pipelineId"); len(pipelineId) > 0 {
+		filter.PipelineId, err = strconv.Atoi(pipelineId)
+		if err != nil {
+			common.WriteJsonResp(w, err, "invalid pipelineId", http.StatusBadRequest)
+			return
+		}
+	}
+	if envId := query.Get("envId"); len(envId) > 0 {
+		filter.EnvironmentId, err = strconv.Atoi(envId)
+		if err != nil {
+			common.WriteJsonResp(w, err, "invalid envId", http.StatusBadRequest)
+			return
+		}
+	}
+	requests, err := handler.deploymentApprovalService.GetAll(filter)
+	if err != nil {
+		handler.logger.Errorw("service err, GetRequests", "err", err, "filter", filter)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	authorisedApps := make(map[int]bool)
+	res := make([]*bean.ApprovalRequestDto, 0, len(requests))
+	for _, request := range requests {
+		authorised, ok := authorisedApps[request.AppId]
+		if !ok {
+			authorised = handler.canViewApp(token, request.AppId)
+			authorisedApps[request.AppId] = authorised
+		}
+		if authorised {
+			res = append(res, request)
+		}
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) GetRequest(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request, ok := handler.getAuthorisedRequest(w, r)
+	if !ok {
+		return
+	}
+	common.WriteJsonResp(w, nil, request, http.StatusOK)
+}
+
+// Approve adds the approval of the user, the deployment is triggered once the request is approved
+func (handler *DeploymentApprovalRestHandlerImpl) Approve(w http.ResponseWriter, r *http.Request) {
+	res, ok := handler.performAction(w, r, handler.deploymentApprovalService.Approve)
+	if ok && res.Status == bean.RequestStatusApproved {
+		go func() {
+			_ = handler.approvedDeploymentTriggerService.TriggerApproved(res.Id)
+		}()
+	}
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) Reject(w http.ResponseWriter, r *http.Request) {
+	handler.performAction(w, r, handler.deploymentApprovalService.Reject)
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) Cancel(w http.ResponseWriter, r *http.Request) {
+	handler.performAction(w, r, handler.deploymentApprovalService.Cancel)
+}
+
+// performAction writes the response of the action, approvers and requesters are checked by the service
+func (handler *DeploymentApprovalRestHandlerImpl) performAction(w http.ResponseWriter, r *http.Request,
+	action func(request *bean.ActionRequest) (*bean.ApprovalRequestDto, error)) (*bean.ApprovalRequestDto, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return nil, false
+	}
+	var request bean.ActionRequest
+	if r.ContentLength != 0 {
+		err = json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			handler.logger.Errorw("request err, deployment approval action", "err", err, "payload", request)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, deployment approval action", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	approvalRequest, ok := handler.getAuthorisedRequest(w, r)
+	if !ok {
+		return nil, false
+	}
+	request.Id = approvalRequest.Id
+	request.UserId = userId
+	res, err := action(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, deployment approval action", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+	return res, true
+}
+
+// getAuthorisedRequest returns the request of the path if the user can view its app
+func (handler *DeploymentApprovalRestHandlerImpl) getAuthorisedRequest(w http.ResponseWriter, r *http.Request) (*bean.ApprovalRequestDto, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid id", http.StatusBadRequest)
+		return nil, false
+	}
+	request, err := handler.deploymentApprovalService.GetById(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetById", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	if !handler.canViewApp(r.Header.Get("token"), request.AppId) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return nil, false
+	}
+	return request, true
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) canViewApp(token string, appId int) bool {
+	return handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, handler.enforcerUtil.GetAppRBACNameByAppId(appId))
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) checkSuperAdmin(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
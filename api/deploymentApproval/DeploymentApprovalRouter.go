@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentApproval
+
+import "github.com/gorilla/mux"
+
+type DeploymentApprovalRouter interface {
+	InitDeploymentApprovalRouter(deploymentApprovalRouter *mux.Router)
+}
+
+type DeploymentApprovalRouterImpl struct {
+	deploymentApprovalRestHandler DeploymentApprovalRestHandler
+}
+
+func NewDeploymentApprovalRouterImpl(deploymentApprovalRestHandler DeploymentApprovalRestHandler) *DeploymentApprovalRouterImpl {
+	return &DeploymentApprovalRouterImpl{
+		deploymentApprovalRestHandler: deploymentApprovalRestHandler,
+	}
+}
+
+func (router *DeploymentApprovalRouterImpl) InitDeploymentApprovalRouter(deploymentApprovalRouter *mux.Router) {
+	deploymentApprovalRouter.Path("/rule").HandlerFunc(router.deploymentApprovalRestHandler.GetAllRules).Methods("GET")
+	deploymentApprovalRouter.Path("/rule/{envId}").HandlerFunc(router.deploymentApprovalRestHandler.GetRule).Methods("GET")
+	deploymentApprovalRouter.Path("/rule/{envId}").HandlerFunc(router.deploymentApprovalRestHandler.SaveRule).Methods("PUT")
+	deploymentApprovalRouter.Path("/rule/{envId}").HandlerFunc(router.deploymentApprovalRestHandler.DeleteRule).Methods("DELETE")
+	deploymentApprovalRouter.Path("/request").HandlerFunc(router.deploymentApprovalRestHandler.GetRequests).Methods("GET")
+	deploymentApprovalRouter.Path("/request/{id}").HandlerFunc(router.deploymentApprovalRestHandler.GetRequest).Methods("GET")
+	deploymentApprovalRouter.Path("/request/{id}/approve").HandlerFunc(router.deploymentApprovalRestHandler.Approve).Methods("PUT")
+	deploymentApprovalRouter.Path("/request/{id}/reject").HandlerFunc(router.deploymentApprovalRestHandler.Reject).Methods("PUT")
+	deploymentApprovalRouter.Path("/request/{id}/cancel").HandlerFunc(router.deploymentApprovalRestHandler.Cancel).Methods("PUT")
+}
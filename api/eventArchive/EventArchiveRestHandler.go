@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventArchive
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/eventArchive"
+	"github.com/devtron-labs/devtron/pkg/eventArchive/bean"
+	rbac2 "github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+)
+
+type EventArchiveRestHandler interface {
+	GetTimeline(w http.ResponseWriter, r *http.Request)
+}
+
+type EventArchiveRestHandlerImpl struct {
+	logger              *zap.SugaredLogger
+	userService         user.UserService
+	eventArchiveService eventArchive.EventArchiveService
+	enforcer            casbin.Enforcer
+	enforcerUtil        rbac2.EnforcerUtil
+}
+
+func NewEventArchiveRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	eventArchiveService eventArchive.EventArchiveService, enforcer casbin.Enforcer,
+	enforcerUtil rbac2.EnforcerUtil) *EventArchiveRestHandlerImpl {
+	return &EventArchiveRestHandlerImpl{
+		logger:              logger,
+		userService:         userService,
+		eventArchiveService: eventArchiveService,
+		enforcer:            enforcer,
+		enforcerUtil:        enforcerUtil,
+	}
+}
+
+// GetTimeline returns the archived events of an app on an environment merged with its deployments, from and to are
+// RFC3339 times and default to the last day
+func (handler *EventArchiveRestHandlerImpl) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := common.ExtractIntQueryParam(w, r, "appId", 0)
+	if err != nil {
+		return
+	}
+	envId, err := common.ExtractIntQueryParam(w, r, "envId", 0)
+	if err != nil {
+		return
+	}
+	if appId <= 0 || envId <= 0 {
+		common.WriteJsonResp(w, errors.New("invalid appId or envId"), nil, http.StatusBadRequest)
+		return
+	}
+	to := time.Now()
+	if value := r.URL.Query().Get("to"); len(value) > 0 {
+		if to, err = time.Parse(time.RFC3339, value); err != nil {
+			common.WriteJsonResp(w, err, "invalid to, expected an RFC3339 time", http.StatusBadRequest)
+			return
+		}
+	}
+	from := to.Add(-bean.DefaultTimelineWindow)
+	if value := r.URL.Query().Get("from"); len(value) > 0 {
+		if from, err = time.Parse(time.RFC3339, value); err != nil {
+			common.WriteJsonResp(w, err, "invalid from, expected an RFC3339 time", http.StatusBadRequest)
+			return
+		}
+	}
+	if !from.Before(to) {
+		common.WriteJsonResp(w, errors.New("from has to be before to"), nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	// RBAC enforcer applying
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), nil, http.StatusForbidden)
+		return
+	}
+	object = handler.enforcerUtil.GetEnvRBACNameByAppId(appId, envId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	timeline, err := handler.eventArchiveService.GetTimeline(appId, envId, from, to)
+	if err != nil {
+		handler.logger.Errorw("service err, GetTimeline", "err", err, "appId", appId, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, timeline, http.StatusOK)
+}
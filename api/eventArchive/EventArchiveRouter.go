@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventArchive
+
+import "github.com/gorilla/mux"
+
+type EventArchiveRouter interface {
+	InitEventArchiveRouter(eventArchiveRouter *mux.Router)
+}
+
+type EventArchiveRouterImpl struct {
+	eventArchiveRestHandler EventArchiveRestHandler
+}
+
+func NewEventArchiveRouterImpl(eventArchiveRestHandler EventArchiveRestHandler) *EventArchiveRouterImpl {
+	return &EventArchiveRouterImpl{
+		eventArchiveRestHandler: eventArchiveRestHandler,
+	}
+}
+
+func (router *EventArchiveRouterImpl) InitEventArchiveRouter(eventArchiveRouter *mux.Router) {
+	eventArchiveRouter.Path("/timeline").HandlerFunc(router.eventArchiveRestHandler.GetTimeline).Methods("GET")
+}
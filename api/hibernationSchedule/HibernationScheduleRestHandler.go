@@ -0,0 +1,302 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hibernationSchedule
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/hibernationSchedule"
+	"github.com/devtron-labs/devtron/pkg/hibernationSchedule/bean"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type HibernationScheduleRestHandler interface {
+	SaveSchedule(w http.ResponseWriter, r *http.Request)
+	GetSchedules(w http.ResponseWriter, r *http.Request)
+	GetSchedule(w http.ResponseWriter, r *http.Request)
+	DeleteSchedule(w http.ResponseWriter, r *http.Request)
+	KeepAwake(w http.ResponseWriter, r *http.Request)
+	GetExecutions(w http.ResponseWriter, r *http.Request)
+}
+
+type HibernationScheduleRestHandlerImpl struct {
+	logger                     *zap.SugaredLogger
+	userService                user.UserService
+	hibernationScheduleService hibernationSchedule.HibernationScheduleService
+	enforcer                   casbin.Enforcer
+	validator                  *validator.Validate
+}
+
+func NewHibernationScheduleRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	hibernationScheduleService hibernationSchedule.HibernationScheduleService, enforcer casbin.Enforcer,
+	validator *validator.Validate) *HibernationScheduleRestHandlerImpl {
+	return &HibernationScheduleRestHandlerImpl{
+		logger:                     logger,
+		userService:                userService,
+		hibernationScheduleService: hibernationScheduleService,
+		enforcer:                   enforcer,
+		validator:                  validator,
+	}
+}
+
+func (handler *HibernationScheduleRestHandlerImpl) SaveSchedule(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.HibernationScheduleRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveSchedule", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveSchedule", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if request.Id > 0 {
+		// the environments the schedule is moved away from must be updatable too
+		existing, err := handler.hibernationScheduleService.GetById(request.Id)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
+		if !handler.isAuthorisedForEnvironments(token, existing.EnvironmentIdentifiers, casbin.ActionUpdate) {
+			common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+			return
+		}
+	}
+	environmentIdentifiers, err := handler.hibernationScheduleService.GetEnvironmentIdentifiers(request.EnvironmentId, request.ResourceGroupId)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveSchedule", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.isAuthorisedForEnvironments(token, environmentIdentifiers, casbin.ActionUpdate) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	res, err := handler.hibernationScheduleService.CreateOrUpdate(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveSchedule", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *HibernationScheduleRestHandlerImpl) GetSchedules(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	envId := 0
+	if envIdParam := r.URL.Query().Get("envId"); len(envIdParam) > 0 {
+		envId, err = strconv.Atoi(envIdParam)
+		if err != nil {
+			common.WriteJsonResp(w, err, "invalid envId", http.StatusBadRequest)
+			return
+		}
+	}
+	schedules, err := handler.hibernationScheduleService.GetAll(envId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetSchedules", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	res := make([]*bean.HibernationScheduleDto, 0, len(schedules))
+	for _, schedule := range schedules {
+		if handler.isAuthorisedForEnvironments(token, schedule.EnvironmentIdentifiers, casbin.ActionGet) {
+			res = append(res, schedule)
+		}
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *HibernationScheduleRestHandlerImpl) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.hibernationScheduleService.GetById(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetSchedule", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.isAuthorisedForEnvironments(r.Header.Get("token"), res.EnvironmentIdentifiers, casbin.ActionGet) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *HibernationScheduleRestHandlerImpl) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	schedule, err := handler.hibernationScheduleService.GetById(id)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteSchedule", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.isAuthorisedForEnvironments(r.Header.Get("token"), schedule.EnvironmentIdentifiers, casbin.ActionUpdate) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	err = handler.hibernationScheduleService.Delete(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteSchedule", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, true, http.StatusOK)
+}
+
+func (handler *HibernationScheduleRestHandlerImpl) KeepAwake(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request bean.KeepAwakeRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, KeepAwake", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.Id = id
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, KeepAwake", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	schedule, err := handler.hibernationScheduleService.GetById(id)
+	if err != nil {
+		handler.logger.Errorw("service err, KeepAwake", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.isAuthorisedForEnvironments(r.Header.Get("token"), schedule.EnvironmentIdentifiers, casbin.ActionUpdate) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	res, err := handler.hibernationScheduleService.KeepAwake(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, KeepAwake", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *HibernationScheduleRestHandlerImpl) GetExecutions(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	offset, limit := 0, bean.DefaultExecutionLimit
+	if offsetQueryParam := r.URL.Query().Get("offset"); len(offsetQueryParam) > 0 {
+		offset, err = strconv.Atoi(offsetQueryParam)
+		if err != nil || offset < 0 {
+			common.WriteJsonResp(w, err, "invalid offset", http.StatusBadRequest)
+			return
+		}
+	}
+	if sizeQueryParam := r.URL.Query().Get("size"); len(sizeQueryParam) > 0 {
+		limit, err = strconv.Atoi(sizeQueryParam)
+		if err != nil {
+			common.WriteJsonResp(w, err, "invalid size", http.StatusBadRequest)
+			return
+		}
+	}
+	schedule, err := handler.hibernationScheduleService.GetById(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetExecutions", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.isAuthorisedForEnvironments(r.Header.Get("token"), schedule.EnvironmentIdentifiers, casbin.ActionGet) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	res, err := handler.hibernationScheduleService.GetExecutions(id, offset, limit)
+	if err != nil {
+		handler.logger.Errorw("service err, GetExecutions", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// isAuthorisedForEnvironments checks the action on every environment hibernated by a schedule,
+// schedules left without any environment are managed by super admins only
+func (handler *HibernationScheduleRestHandlerImpl) isAuthorisedForEnvironments(token string, environmentIdentifiers []string, action string) bool {
+	if len(environmentIdentifiers) == 0 {
+		return handler.enforcer.Enforce(token, casbin.ResourceGlobal, action, "*")
+	}
+	for _, environmentIdentifier := range environmentIdentifiers {
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobalEnvironment, action, strings.ToLower(environmentIdentifier)); !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hibernationSchedule
+
+import "github.com/gorilla/mux"
+
+type HibernationScheduleRouter interface {
+	InitHibernationScheduleRouter(hibernationScheduleRouter *mux.Router)
+}
+
+type HibernationScheduleRouterImpl struct {
+	hibernationScheduleRestHandler HibernationScheduleRestHandler
+}
+
+func NewHibernationScheduleRouterImpl(hibernationScheduleRestHandler HibernationScheduleRestHandler) *HibernationScheduleRouterImpl {
+	return &HibernationScheduleRouterImpl{
+		hibernationScheduleRestHandler: hibernationScheduleRestHandler,
+	}
+}
+
+func (router *HibernationScheduleRouterImpl) InitHibernationScheduleRouter(hibernationScheduleRouter *mux.Router) {
+	hibernationScheduleRouter.Path("").HandlerFunc(router.hibernationScheduleRestHandler.SaveSchedule).Methods("POST")
+	hibernationScheduleRouter.Path("").HandlerFunc(router.hibernationScheduleRestHandler.GetSchedules).Methods("GET")
+	hibernationScheduleRouter.Path("/{id}").HandlerFunc(router.hibernationScheduleRestHandler.GetSchedule).Methods("GET")
+	hibernationScheduleRouter.Path("/{id}").HandlerFunc(router.hibernationScheduleRestHandler.DeleteSchedule).Methods("DELETE")
+	hibernationScheduleRouter.Path("/{id}/keep-awake").HandlerFunc(router.hibernationScheduleRestHandler.KeepAwake).Methods("PUT")
+	hibernationScheduleRouter.Path("/{id}/execution").HandlerFunc(router.hibernationScheduleRestHandler.GetExecutions).Methods("GET")
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/debugProfile"
+	"github.com/devtron-labs/devtron/pkg/debugProfile/bean"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type DebugProfileRestHandler interface {
+	GetProfiles(w http.ResponseWriter, r *http.Request)
+	GetApplicableProfiles(w http.ResponseWriter, r *http.Request)
+	CreateProfile(w http.ResponseWriter, r *http.Request)
+	UpdateProfile(w http.ResponseWriter, r *http.Request)
+	DeleteProfile(w http.ResponseWriter, r *http.Request)
+	GetLingeringContainers(w http.ResponseWriter, r *http.Request)
+}
+
+type DebugProfileRestHandlerImpl struct {
+	logger              *zap.SugaredLogger
+	userService         user.UserService
+	debugProfileService debugProfile.DebugProfileService
+	enforcer            casbin.Enforcer
+	validator           *validator.Validate
+}
+
+func NewDebugProfileRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	debugProfileService debugProfile.DebugProfileService, enforcer casbin.Enforcer,
+	validator *validator.Validate) *DebugProfileRestHandlerImpl {
+	return &DebugProfileRestHandlerImpl{
+		logger:              logger,
+		userService:         userService,
+		debugProfileService: debugProfileService,
+		enforcer:            enforcer,
+		validator:           validator,
+	}
+}
+
+func (handler *DebugProfileRestHandlerImpl) GetProfiles(w http.ResponseWriter, r *http.Request) {
+	if ok := handler.checkSuperAdmin(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := handler.debugProfileService.GetAll()
+	if err != nil {
+		handler.logger.Errorw("service err, GetProfiles", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// GetApplicableProfiles lists the profiles users can create debug containers with on the pods of a namespace
+func (handler *DebugProfileRestHandlerImpl) GetApplicableProfiles(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	clusterId, err := common.ExtractIntQueryParam(w, r, "clusterId", 0)
+	if err != nil {
+		return
+	}
+	namespace := r.URL.Query().Get("namespace")
+	if clusterId == 0 || len(namespace) == 0 {
+		common.WriteJsonResp(w, errors.New("clusterId and namespace are required"), nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.debugProfileService.GetApplicable(clusterId, namespace)
+	if err != nil {
+		handler.logger.Errorw("service err, GetApplicableProfiles", "err", err, "clusterId", clusterId, "namespace", namespace)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DebugProfileRestHandlerImpl) CreateProfile(w http.ResponseWriter, r *http.Request) {
+	request, ok := handler.decodeProfile(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.debugProfileService.Create(request)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateProfile", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DebugProfileRestHandlerImpl) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	request, ok := handler.decodeProfile(w, r)
+	if !ok {
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	request.Id = id
+	res, err := handler.debugProfileService.Update(request)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdateProfile", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DebugProfileRestHandlerImpl) DeleteProfile(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	if ok := handler.checkSuperAdmin(w, r, casbin.ActionDelete); !ok {
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	err = handler.debugProfileService.Delete(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteProfile", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "debug profile deleted", http.StatusOK)
+}
+
+// GetLingeringContainers returns the report of the last reconcile of debug containers
+func (handler *DebugProfileRestHandlerImpl) GetLingeringContainers(w http.ResponseWriter, r *http.Request) {
+	if ok := handler.checkSuperAdmin(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	common.WriteJsonResp(w, nil, handler.debugProfileService.GetLingeringContainers(), http.StatusOK)
+}
+
+// checkSuperAdmin writes the response when the logged-in user is not a super admin
+func (handler *DebugProfileRestHandlerImpl) checkSuperAdmin(w http.ResponseWriter, r *http.Request, action string) bool {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return false
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, action, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// decodeProfile reads and validates a profile saved by a super admin, ok is false when the response is already written
+func (handler *DebugProfileRestHandlerImpl) decodeProfile(w http.ResponseWriter, r *http.Request) (*bean.DebugProfileDto, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return nil, false
+	}
+	if ok := handler.checkSuperAdmin(w, r, casbin.ActionUpdate); !ok {
+		return nil, false
+	}
+	var request bean.DebugProfileDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, decodeProfile", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, decodeProfile", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	request.UserId = userId
+	return &request, true
+}
@@ -44,6 +44,7 @@ import (
 	application2 "github.com/devtron-labs/devtron/pkg/k8s/application"
 	bean2 "github.com/devtron-labs/devtron/pkg/k8s/application/bean"
 	bean3 "github.com/devtron-labs/devtron/pkg/k8s/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/podLogs"
 	"github.com/devtron-labs/devtron/pkg/terminal"
 	"github.com/devtron-labs/devtron/util"
 	"github.com/devtron-labs/devtron/util/rbac"
@@ -53,8 +54,12 @@ import (
 	"go.uber.org/zap"
 	"gopkg.in/go-playground/validator.v9"
 	"io"
+	corev1 "k8s.io/api/core/v1"
 	errors3 "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -70,6 +75,7 @@ type K8sApplicationRestHandler interface {
 	ListEvents(w http.ResponseWriter, r *http.Request)
 	GetPodLogs(w http.ResponseWriter, r *http.Request)
 	DownloadPodLogs(w http.ResponseWriter, r *http.Request)
+	GetAggregatedPodLogs(w http.ResponseWriter, r *http.Request)
 	GetTerminalSession(w http.ResponseWriter, r *http.Request)
 	GetResourceInfo(w http.ResponseWriter, r *http.Request)
 	GetHostUrlsByBatch(w http.ResponseWriter, r *http.Request)
@@ -97,10 +103,11 @@ type K8sApplicationRestHandlerImpl struct {
 	terminalEnvVariables       *util.TerminalEnvVariables
 	fluxAppService             fluxApplication.FluxApplicationService
 	argoApplicationReadService read.ArgoApplicationReadService
+	podLogsService             podLogs.PodLogsService
 }
 
 func NewK8sApplicationRestHandlerImpl(logger *zap.SugaredLogger, k8sApplicationService application2.K8sApplicationService, pump connector.Pump, terminalSessionHandler terminal.TerminalSessionHandler, enforcer casbin.Enforcer, enforcerUtilHelm rbac.EnforcerUtilHelm, enforcerUtil rbac.EnforcerUtil, helmAppService client.HelmAppService, userService user.UserService, k8sCommonService k8s.K8sCommonService, validator *validator.Validate, envVariables *util.EnvironmentVariables, fluxAppService fluxApplication.FluxApplicationService, argoApplicationReadService read.ArgoApplicationReadService,
-) *K8sApplicationRestHandlerImpl {
+	podLogsService podLogs.PodLogsService) *K8sApplicationRestHandlerImpl {
 	return &K8sApplicationRestHandlerImpl{
 		logger:                     logger,
 		k8sApplicationService:      k8sApplicationService,
@@ -116,6 +123,7 @@ func NewK8sApplicationRestHandlerImpl(logger *zap.SugaredLogger, k8sApplicationS
 		terminalEnvVariables:       envVariables.TerminalEnvVariables,
 		fluxAppService:             fluxAppService,
 		argoApplicationReadService: argoApplicationReadService,
+		podLogsService:             podLogsService,
 	}
 }
 
@@ -593,6 +601,50 @@ func (handler *K8sApplicationRestHandlerImpl) GetPodLogs(w http.ResponseWriter,
 	handler.pump.StartK8sStreamWithHeartBeat(w, isReconnect, stream, err)
 }
 
+// GetAggregatedPodLogs streams the logs of every container of the pods matching a label selector or a workload,
+// only the pods the user can read are streamed
+func (handler *K8sApplicationRestHandlerImpl) GetAggregatedPodLogs(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("token")
+	request, err := handler.podLogsService.ValidateAggregatedLogsRequestQuery(r)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	handler.logger.Infow("get aggregated pod logs request", "request", request)
+	lastEventId := r.Header.Get(bean2.LastEventID)
+	isReconnect := false
+	if len(lastEventId) > 0 {
+		lastSeenMsgId, err := strconv.ParseInt(lastEventId, bean2.IntegerBase, bean2.IntegerBitSize)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+		lastSeenMsgId = lastSeenMsgId + bean2.TimestampOffsetToAvoidDuplicateLogs //increased by one ns to avoid duplicate
+		t := v1.Unix(0, lastSeenMsgId)
+		request.SinceTime = &t
+		request.SinceSeconds, request.TailLines = 0, 0
+		isReconnect = true
+	}
+	podGvk := schema.GroupVersionKind{Version: "v1", Kind: k8sCommonBean.PodKind}
+	rbacCallback := handler.getRbacCallbackForResource(token, casbin.ActionGet)
+	authorise := func(pod *corev1.Pod) bool {
+		manifest, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+		if err != nil {
+			handler.logger.Errorw("error in converting pod", "pod", pod.Name, "err", err)
+			return false
+		}
+		return handler.k8sApplicationService.ValidateClusterResourceBean(r.Context(), request.ClusterId, unstructured.Unstructured{Object: manifest}, podGvk, rbacCallback)
+	}
+	stream, err := handler.podLogsService.GetAggregatedLogs(r.Context(), request, authorise)
+	if err != nil {
+		handler.logger.Errorw("error in getting aggregated pod logs", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	defer util.Close(stream, handler.logger)
+	handler.pump.StartK8sStreamWithHeartBeat(w, isReconnect, stream, nil)
+}
+
 func (handler *K8sApplicationRestHandlerImpl) DownloadPodLogs(w http.ResponseWriter, r *http.Request) {
 	token := r.Header.Get("token")
 	request, err := handler.k8sApplicationService.ValidatePodLogsRequestQuery(r)
@@ -25,12 +25,27 @@ type K8sApplicationRouter interface {
 	InitK8sApplicationRouter(helmRouter *mux.Router)
 }
 type K8sApplicationRouterImpl struct {
-	k8sApplicationRestHandler K8sApplicationRestHandler
+	k8sApplicationRestHandler    K8sApplicationRestHandler
+	terminalRecordingRestHandler TerminalRecordingRestHandler
+	terminalPolicyRestHandler    TerminalPolicyRestHandler
+	portForwardRestHandler       PortForwardRestHandler
+	resourceSearchRestHandler    ResourceSearchRestHandler
+	debugProfileRestHandler      DebugProfileRestHandler
 }
 
-func NewK8sApplicationRouterImpl(k8sApplicationRestHandler K8sApplicationRestHandler) *K8sApplicationRouterImpl {
+func NewK8sApplicationRouterImpl(k8sApplicationRestHandler K8sApplicationRestHandler,
+	terminalRecordingRestHandler TerminalRecordingRestHandler,
+	terminalPolicyRestHandler TerminalPolicyRestHandler,
+	portForwardRestHandler PortForwardRestHandler,
+	resourceSearchRestHandler ResourceSearchRestHandler,
+	debugProfileRestHandler DebugProfileRestHandler) *K8sApplicationRouterImpl {
 	return &K8sApplicationRouterImpl{
-		k8sApplicationRestHandler: k8sApplicationRestHandler,
+		k8sApplicationRestHandler:    k8sApplicationRestHandler,
+		terminalRecordingRestHandler: terminalRecordingRestHandler,
+		terminalPolicyRestHandler:    terminalPolicyRestHandler,
+		portForwardRestHandler:       portForwardRestHandler,
+		resourceSearchRestHandler:    resourceSearchRestHandler,
+		debugProfileRestHandler:      debugProfileRestHandler,
 	}
 }
 
@@ -67,6 +82,9 @@ func (impl *K8sApplicationRouterImpl) InitK8sApplicationRouter(k8sAppRouter *mux
 		Queries("containerName", "{containerName}").
 		HandlerFunc(impl.k8sApplicationRestHandler.DownloadPodLogs).Methods("GET")
 
+	k8sAppRouter.Path("/pods/aggregated-logs").
+		HandlerFunc(impl.k8sApplicationRestHandler.GetAggregatedPodLogs).Methods("GET")
+
 	k8sAppRouter.Path("/pod/exec/session/{identifier}/{namespace}/{pod}/{shell}/{container}").
 		HandlerFunc(impl.k8sApplicationRestHandler.GetTerminalSession).Methods("GET")
 	k8sAppRouter.PathPrefix("/pod/exec/sockjs/ws").Handler(terminal.CreateAttachHandler("/pod/exec/sockjs/ws"))
@@ -74,6 +92,31 @@ func (impl *K8sApplicationRouterImpl) InitK8sApplicationRouter(k8sAppRouter *mux
 	/*k8sAppRouter.Path("/pod/exec/sockjs/ws/").
 	Handler(terminal.CreateAttachHandler("/api/v1/applications/pod/exec/sockjs/ws/"))*/
 
+	k8sAppRouter.Path("/terminal/recording").
+		HandlerFunc(impl.terminalRecordingRestHandler.GetRecordings).Methods("GET")
+	k8sAppRouter.Path("/terminal/recording/{id}").
+		HandlerFunc(impl.terminalRecordingRestHandler.GetRecording).Methods("GET")
+	k8sAppRouter.Path("/terminal/recording/{id}/cast").
+		HandlerFunc(impl.terminalRecordingRestHandler.DownloadRecording).Methods("GET")
+
+	k8sAppRouter.Path("/terminal/policy").
+		HandlerFunc(impl.terminalPolicyRestHandler.GetPolicies).Methods("GET")
+	k8sAppRouter.Path("/terminal/policy").
+		HandlerFunc(impl.terminalPolicyRestHandler.CreatePolicy).Methods("POST")
+	k8sAppRouter.Path("/terminal/policy/{id}").
+		HandlerFunc(impl.terminalPolicyRestHandler.UpdatePolicy).Methods("PUT")
+	k8sAppRouter.Path("/terminal/policy/{id}").
+		HandlerFunc(impl.terminalPolicyRestHandler.DeletePolicy).Methods("DELETE")
+
+	k8sAppRouter.Path("/port-forward/session").
+		HandlerFunc(impl.portForwardRestHandler.CreateSession).Methods("POST")
+	k8sAppRouter.Path("/port-forward/session").
+		HandlerFunc(impl.portForwardRestHandler.GetSessions).Methods("GET")
+	k8sAppRouter.Path("/port-forward/session/{sessionId}").
+		HandlerFunc(impl.portForwardRestHandler.CloseSession).Methods("DELETE")
+	k8sAppRouter.Path("/port-forward/session/{sessionId}/tunnel").
+		HandlerFunc(impl.portForwardRestHandler.Tunnel).Methods("GET")
+
 	k8sAppRouter.Path("/resource/inception/info").
 		HandlerFunc(impl.k8sApplicationRestHandler.GetResourceInfo).Methods("GET")
 
@@ -83,6 +126,19 @@ func (impl *K8sApplicationRouterImpl) InitK8sApplicationRouter(k8sAppRouter *mux
 	k8sAppRouter.Path("/resource/list").
 		HandlerFunc(impl.k8sApplicationRestHandler.GetResourceList).Methods("POST")
 
+	k8sAppRouter.Path("/resource/search").
+		HandlerFunc(impl.resourceSearchRestHandler.Search).Methods("POST")
+	k8sAppRouter.Path("/resource/search/saved").
+		HandlerFunc(impl.resourceSearchRestHandler.GetSavedQueries).Methods("GET")
+	k8sAppRouter.Path("/resource/search/saved").
+		HandlerFunc(impl.resourceSearchRestHandler.CreateSavedQuery).Methods("POST")
+	k8sAppRouter.Path("/resource/search/saved/{id}").
+		HandlerFunc(impl.resourceSearchRestHandler.UpdateSavedQuery).Methods("PUT")
+	k8sAppRouter.Path("/resource/search/saved/{id}").
+		HandlerFunc(impl.resourceSearchRestHandler.DeleteSavedQuery).Methods("DELETE")
+	k8sAppRouter.Path("/resource/search/saved/{id}/run").
+		HandlerFunc(impl.resourceSearchRestHandler.RunSavedQuery).Methods("POST")
+
 	k8sAppRouter.Path("/resources/apply").
 		HandlerFunc(impl.k8sApplicationRestHandler.ApplyResources).Methods("POST")
 
@@ -94,6 +150,19 @@ func (impl *K8sApplicationRouterImpl) InitK8sApplicationRouter(k8sAppRouter *mux
 		Queries("identifier", "{identifier}").
 		HandlerFunc(impl.k8sApplicationRestHandler.DeleteEphemeralContainer).Methods("DELETE")
 
+	k8sAppRouter.Path("/debug-profile").
+		HandlerFunc(impl.debugProfileRestHandler.GetProfiles).Methods("GET")
+	k8sAppRouter.Path("/debug-profile").
+		HandlerFunc(impl.debugProfileRestHandler.CreateProfile).Methods("POST")
+	k8sAppRouter.Path("/debug-profile/applicable").
+		HandlerFunc(impl.debugProfileRestHandler.GetApplicableProfiles).Methods("GET")
+	k8sAppRouter.Path("/debug-profile/lingering-containers").
+		HandlerFunc(impl.debugProfileRestHandler.GetLingeringContainers).Methods("GET")
+	k8sAppRouter.Path("/debug-profile/{id}").
+		HandlerFunc(impl.debugProfileRestHandler.UpdateProfile).Methods("PUT")
+	k8sAppRouter.Path("/debug-profile/{id}").
+		HandlerFunc(impl.debugProfileRestHandler.DeleteProfile).Methods("DELETE")
+
 	k8sAppRouter.Path("/api-resources/gvk/{clusterId}").
 		HandlerFunc(impl.k8sApplicationRestHandler.GetAllApiResourceGVKWithoutAuthorization).Methods("GET")
 
@@ -0,0 +1,248 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	util3 "github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	application2 "github.com/devtron-labs/devtron/pkg/k8s/application"
+	bean2 "github.com/devtron-labs/devtron/pkg/k8s/application/bean"
+	bean3 "github.com/devtron-labs/devtron/pkg/k8s/bean"
+	"github.com/devtron-labs/devtron/pkg/portForward"
+	"github.com/devtron-labs/devtron/pkg/portForward/bean"
+	"github.com/devtron-labs/devtron/util"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type PortForwardRestHandler interface {
+	CreateSession(w http.ResponseWriter, r *http.Request)
+	GetSessions(w http.ResponseWriter, r *http.Request)
+	CloseSession(w http.ResponseWriter, r *http.Request)
+	// Tunnel upgrades to a websocket carrying one tcp connection to the forwarded port in binary messages
+	Tunnel(w http.ResponseWriter, r *http.Request)
+}
+
+type PortForwardRestHandlerImpl struct {
+	logger                *zap.SugaredLogger
+	userService           user.UserService
+	portForwardService    portForward.PortForwardService
+	k8sApplicationService application2.K8sApplicationService
+	enforcer              casbin.Enforcer
+	enforcerUtil          rbac.EnforcerUtil
+	validator             *validator.Validate
+	terminalEnvVariables  *util.TerminalEnvVariables
+	upgrader              websocket.Upgrader
+}
+
+func NewPortForwardRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	portForwardService portForward.PortForwardService, k8sApplicationService application2.K8sApplicationService,
+	enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil, validator *validator.Validate,
+	envVariables *util.EnvironmentVariables) *PortForwardRestHandlerImpl {
+	return &PortForwardRestHandlerImpl{
+		logger:                logger,
+		userService:           userService,
+		portForwardService:    portForwardService,
+		k8sApplicationService: k8sApplicationService,
+		enforcer:              enforcer,
+		enforcerUtil:          enforcerUtil,
+		validator:             validator,
+		terminalEnvVariables:  envVariables.TerminalEnvVariables,
+	}
+}
+
+// CreateSession opens a port-forward session, it needs the same access as an exec into the pod
+func (handler *PortForwardRestHandlerImpl) CreateSession(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.SessionRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, CreateSession", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, CreateSession", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.isAuthorised(w, r, &request); !ok {
+		return
+	}
+	request.UserId = userId
+	res, err := handler.portForwardService.CreateSession(r.Context(), &request)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateSession", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// GetSessions lists the open sessions of the logged in user
+func (handler *PortForwardRestHandlerImpl) GetSessions(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	common.WriteJsonResp(w, nil, handler.portForwardService.GetSessions(userId), http.StatusOK)
+}
+
+func (handler *PortForwardRestHandlerImpl) CloseSession(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	sessionId := mux.Vars(r)["sessionId"]
+	err = handler.portForwardService.CloseSession(sessionId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, CloseSession", "err", err, "sessionId", sessionId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "port-forward session closed", http.StatusOK)
+}
+
+func (handler *PortForwardRestHandlerImpl) Tunnel(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	sessionId := mux.Vars(r)["sessionId"]
+	// the session is checked before the upgrade so that errors are still plain http responses
+	err = handler.portForwardService.ValidateSession(sessionId, userId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	conn, err := handler.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has written the error response
+		handler.logger.Errorw("error in upgrading port-forward tunnel", "err", err, "sessionId", sessionId)
+		return
+	}
+	defer conn.Close()
+	err = handler.portForwardService.Tunnel(sessionId, &webSocketStream{conn: conn})
+	if err != nil {
+		_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, err.Error()), time.Now().Add(time.Second))
+	}
+}
+
+// isAuthorised applies the rbac of an exec into the pod, or into the pods of the service, from the resource browser
+func (handler *PortForwardRestHandlerImpl) isAuthorised(w http.ResponseWriter, r *http.Request, request *bean.SessionRequest) bool {
+	token := r.Header.Get("token")
+	if handler.terminalEnvVariables.RestrictTerminalAccessForNonSuperUser {
+		if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !isSuperAdmin {
+			common.WriteJsonResp(w, errors.New("unauthorized, only super-admins can port-forward"), nil, http.StatusForbidden)
+			return false
+		}
+	}
+	resource, object := handler.enforcerUtil.GetRbacResourceAndObjectForNodeByClusterId(request.ClusterId, bean2.ALL)
+	if handler.enforcer.Enforce(token, resource, casbin.ActionUpdate, object) {
+		return true
+	}
+	name, kind := request.PodName, "Pod"
+	if len(request.ServiceName) > 0 {
+		name, kind = request.ServiceName, "Service"
+	}
+	resourceRequest := &bean3.ResourceRequestBean{
+		ClusterId: request.ClusterId,
+		K8sRequest: &util3.K8sRequestBean{
+			ResourceIdentifier: util3.ResourceIdentifier{
+				Name:             name,
+				Namespace:        request.Namespace,
+				GroupVersionKind: schema.GroupVersionKind{Group: "", Version: "v1", Kind: kind},
+			},
+		},
+	}
+	allowed, err := handler.k8sApplicationService.ValidateClusterResourceRequest(r.Context(), resourceRequest, func(clusterName string, resourceIdentifier util3.ResourceIdentifier) bool {
+		resourceName, objectName := handler.enforcerUtil.GetRBACNameForClusterEntity(clusterName, resourceIdentifier)
+		return handler.enforcer.Enforce(token, strings.ToLower(resourceName), casbin.ActionUpdate, objectName)
+	})
+	if err != nil {
+		handler.logger.Errorw("error in validating port-forward target", "err", err, "request", request)
+		common.WriteJsonResp(w, errors.New("invalid request"), nil, http.StatusBadRequest)
+		return false
+	}
+	if !allowed {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+	}
+	return allowed
+}
+
+// webSocketStream reads and writes the bytes of a tunneled connection as websocket messages
+type webSocketStream struct {
+	conn   *websocket.Conn
+	reader io.Reader
+}
+
+func (stream *webSocketStream) Read(p []byte) (int, error) {
+	for {
+		if stream.reader == nil {
+			messageType, reader, err := stream.conn.NextReader()
+			if err != nil {
+				return 0, err
+			}
+			if messageType != websocket.BinaryMessage && messageType != websocket.TextMessage {
+				continue
+			}
+			stream.reader = reader
+		}
+		n, err := stream.reader.Read(p)
+		if err == io.EOF {
+			stream.reader = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (stream *webSocketStream) Write(p []byte) (int, error) {
+	err := stream.conn.WriteMessage(websocket.BinaryMessage, p)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (stream *webSocketStream) Close() error {
+	return stream.conn.Close()
+}
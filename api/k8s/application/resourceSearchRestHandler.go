@@ -0,0 +1,236 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	k8sUtil "github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/resourceSearch"
+	"github.com/devtron-labs/devtron/pkg/resourceSearch/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type ResourceSearchRestHandler interface {
+	Search(w http.ResponseWriter, r *http.Request)
+	RunSavedQuery(w http.ResponseWriter, r *http.Request)
+	GetSavedQueries(w http.ResponseWriter, r *http.Request)
+	CreateSavedQuery(w http.ResponseWriter, r *http.Request)
+	UpdateSavedQuery(w http.ResponseWriter, r *http.Request)
+	DeleteSavedQuery(w http.ResponseWriter, r *http.Request)
+}
+
+type ResourceSearchRestHandlerImpl struct {
+	logger                *zap.SugaredLogger
+	userService           user.UserService
+	resourceSearchService resourceSearch.ResourceSearchService
+	enforcer              casbin.Enforcer
+	enforcerUtil          rbac.EnforcerUtil
+	validator             *validator.Validate
+}
+
+func NewResourceSearchRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	resourceSearchService resourceSearch.ResourceSearchService, enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil, validator *validator.Validate) *ResourceSearchRestHandlerImpl {
+	return &ResourceSearchRestHandlerImpl{
+		logger:                logger,
+		userService:           userService,
+		resourceSearchService: resourceSearchService,
+		enforcer:              enforcer,
+		enforcerUtil:          enforcerUtil,
+		validator:             validator,
+	}
+}
+
+func (handler *ResourceSearchRestHandlerImpl) Search(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var query bean.ResourceSearchQuery
+	err = json.NewDecoder(r.Body).Decode(&query)
+	if err != nil {
+		handler.logger.Errorw("request err, Search", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(query)
+	if err != nil {
+		handler.logger.Errorw("validation err, Search", "err", err, "query", query)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	handler.search(w, r, &query)
+}
+
+func (handler *ResourceSearchRestHandlerImpl) RunSavedQuery(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	savedQuery, err := handler.resourceSearchService.GetSavedQuery(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, RunSavedQuery", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	handler.search(w, r, savedQuery.Query)
+}
+
+// search runs the query with the resource rbac of the user and writes the result in the format asked for,
+// csv responses are sent as a file download
+func (handler *ResourceSearchRestHandlerImpl) search(w http.ResponseWriter, r *http.Request, query *bean.ResourceSearchQuery) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if len(format) == 0 {
+		format = bean.ExportFormatJson
+	}
+	if format != bean.ExportFormatJson && format != bean.ExportFormatCsv {
+		common.WriteJsonResp(w, fmt.Errorf("unsupported format %s, supported formats are json and csv", format), nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	authorise := func(clusterName string, resourceIdentifier k8sUtil.ResourceIdentifier) bool {
+		resourceName, objectName := handler.enforcerUtil.GetRBACNameForClusterEntity(clusterName, resourceIdentifier)
+		return handler.enforcer.Enforce(token, strings.ToLower(resourceName), casbin.ActionGet, objectName)
+	}
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); ok {
+		authorise = nil
+	}
+	response, err := handler.resourceSearchService.Search(r.Context(), query, authorise)
+	if err != nil {
+		handler.logger.Errorw("service err, Search", "err", err, "query", query)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if format == bean.ExportFormatJson {
+		common.WriteJsonResp(w, nil, response, http.StatusOK)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", resourceSearch.CsvFileName(query)))
+	w.WriteHeader(http.StatusOK)
+	err = resourceSearch.WriteCsv(w, response.Items)
+	if err != nil {
+		handler.logger.Errorw("error in writing resource search csv", "err", err)
+	}
+}
+
+func (handler *ResourceSearchRestHandlerImpl) GetSavedQueries(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	res, err := handler.resourceSearchService.GetSavedQueries(userId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetSavedQueries", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ResourceSearchRestHandlerImpl) CreateSavedQuery(w http.ResponseWriter, r *http.Request) {
+	request, ok := handler.decodeSavedQuery(w, r)
+	if !ok {
+		return
+	}
+	request.Id = 0
+	res, err := handler.resourceSearchService.SaveQuery(request)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateSavedQuery", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ResourceSearchRestHandlerImpl) UpdateSavedQuery(w http.ResponseWriter, r *http.Request) {
+	request, ok := handler.decodeSavedQuery(w, r)
+	if !ok {
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	request.Id = id
+	res, err := handler.resourceSearchService.SaveQuery(request)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdateSavedQuery", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ResourceSearchRestHandlerImpl) DeleteSavedQuery(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	err = handler.resourceSearchService.DeleteSavedQuery(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteSavedQuery", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "saved query deleted", http.StatusOK)
+}
+
+// decodeSavedQuery reads and validates a query saved by the logged-in user, ok is false when the response is already written
+func (handler *ResourceSearchRestHandlerImpl) decodeSavedQuery(w http.ResponseWriter, r *http.Request) (*bean.SavedQueryDto, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return nil, false
+	}
+	var request bean.SavedQueryDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, decodeSavedQuery", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, decodeSavedQuery", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	request.UserId = userId
+	return &request, true
+}
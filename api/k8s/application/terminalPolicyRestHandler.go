@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/terminalPolicy"
+	"github.com/devtron-labs/devtron/pkg/terminalPolicy/bean"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type TerminalPolicyRestHandler interface {
+	GetPolicies(w http.ResponseWriter, r *http.Request)
+	CreatePolicy(w http.ResponseWriter, r *http.Request)
+	UpdatePolicy(w http.ResponseWriter, r *http.Request)
+	DeletePolicy(w http.ResponseWriter, r *http.Request)
+}
+
+type TerminalPolicyRestHandlerImpl struct {
+	logger                *zap.SugaredLogger
+	userService           user.UserService
+	terminalPolicyService terminalPolicy.TerminalPolicyService
+	enforcer              casbin.Enforcer
+	validator             *validator.Validate
+}
+
+func NewTerminalPolicyRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	terminalPolicyService terminalPolicy.TerminalPolicyService, enforcer casbin.Enforcer,
+	validator *validator.Validate) *TerminalPolicyRestHandlerImpl {
+	return &TerminalPolicyRestHandlerImpl{
+		logger:                logger,
+		userService:           userService,
+		terminalPolicyService: terminalPolicyService,
+		enforcer:              enforcer,
+		validator:             validator,
+	}
+}
+
+func (handler *TerminalPolicyRestHandlerImpl) GetPolicies(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := handler.terminalPolicyService.GetAll()
+	if err != nil {
+		handler.logger.Errorw("service err, GetPolicies", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *TerminalPolicyRestHandlerImpl) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	request, ok := handler.decodePolicy(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.terminalPolicyService.Create(request)
+	if err != nil {
+		handler.logger.Errorw("service err, CreatePolicy", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *TerminalPolicyRestHandlerImpl) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	request, ok := handler.decodePolicy(w, r)
+	if !ok {
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	request.Id = id
+	res, err := handler.terminalPolicyService.Update(request)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdatePolicy", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *TerminalPolicyRestHandlerImpl) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionDelete, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	err = handler.terminalPolicyService.Delete(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeletePolicy", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "terminal command policy deleted", http.StatusOK)
+}
+
+// decodePolicy reads and validates a policy saved by a super admin, ok is false when the response is already written
+func (handler *TerminalPolicyRestHandlerImpl) decodePolicy(w http.ResponseWriter, r *http.Request) (*bean.CommandPolicyDto, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return nil, false
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return nil, false
+	}
+	var request bean.CommandPolicyDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, decodePolicy", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, decodePolicy", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	request.UserId = userId
+	return &request, true
+}
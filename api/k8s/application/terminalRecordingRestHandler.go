@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package application
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/terminalRecording"
+	"github.com/devtron-labs/devtron/pkg/terminalRecording/bean"
+	"go.uber.org/zap"
+)
+
+type TerminalRecordingRestHandler interface {
+	GetRecordings(w http.ResponseWriter, r *http.Request)
+	GetRecording(w http.ResponseWriter, r *http.Request)
+	DownloadRecording(w http.ResponseWriter, r *http.Request)
+}
+
+type TerminalRecordingRestHandlerImpl struct {
+	logger                   *zap.SugaredLogger
+	userService              user.UserService
+	terminalRecordingService terminalRecording.TerminalRecordingService
+	enforcer                 casbin.Enforcer
+}
+
+func NewTerminalRecordingRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	terminalRecordingService terminalRecording.TerminalRecordingService, enforcer casbin.Enforcer) *TerminalRecordingRestHandlerImpl {
+	return &TerminalRecordingRestHandlerImpl{
+		logger:                   logger,
+		userService:              userService,
+		terminalRecordingService: terminalRecordingService,
+		enforcer:                 enforcer,
+	}
+}
+
+// GetRecordings lists the recorded terminal sessions, commands typed in a session can be searched with the command filter
+func (handler *TerminalRecordingRestHandlerImpl) GetRecordings(w http.ResponseWriter, r *http.Request) {
+	if ok := handler.isAuthorised(w, r); !ok {
+		return
+	}
+	filter, err := handler.getRecordingFilter(w, r)
+	if err != nil {
+		return
+	}
+	res, err := handler.terminalRecordingService.GetAll(filter)
+	if err != nil {
+		handler.logger.Errorw("service err, GetRecordings", "err", err, "filter", filter)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *TerminalRecordingRestHandlerImpl) GetRecording(w http.ResponseWriter, r *http.Request) {
+	if ok := handler.isAuthorised(w, r); !ok {
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	res, err := handler.terminalRecordingService.GetById(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetRecording", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// DownloadRecording streams the asciicast recording of a session, it can be played with any asciicast v2 player
+func (handler *TerminalRecordingRestHandlerImpl) DownloadRecording(w http.ResponseWriter, r *http.Request) {
+	if ok := handler.isAuthorised(w, r); !ok {
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	recording, err := handler.terminalRecordingService.GetRecording(id)
+	if err != nil {
+		handler.logger.Errorw("service err, DownloadRecording", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	defer recording.Close()
+	w.Header().Set("Content-Type", bean.AsciicastContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"terminal-recording-%d%s\"", id, bean.AsciicastExtension))
+	_, err = io.Copy(w, recording)
+	if err != nil {
+		handler.logger.Errorw("error in streaming terminal recording", "err", err, "id", id)
+	}
+}
+
+// isAuthorised allows only super admins as recordings contain everything typed and shown in the terminals of all users
+func (handler *TerminalRecordingRestHandlerImpl) isAuthorised(w http.ResponseWriter, r *http.Request) bool {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return false
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (handler *TerminalRecordingRestHandlerImpl) getRecordingFilter(w http.ResponseWriter, r *http.Request) (*bean.RecordingFilter, error) {
+	query := r.URL.Query()
+	filter := &bean.RecordingFilter{
+		Namespace: query.Get("namespace"),
+		PodName:   query.Get("podName"),
+		Command:   query.Get("command"),
+	}
+	userId, err := common.ExtractIntQueryParam(w, r, "userId", 0)
+	if err != nil {
+		return nil, err
+	}
+	filter.UserId = int32(userId)
+	if filter.ClusterId, err = common.ExtractIntQueryParam(w, r, "clusterId", 0); err != nil {
+		return nil, err
+	}
+	if filter.Offset, err = common.ExtractIntQueryParam(w, r, "offset", 0); err != nil {
+		return nil, err
+	}
+	if filter.Limit, err = common.ExtractIntQueryParam(w, r, "limit", bean.DefaultListLimit); err != nil {
+		return nil, err
+	}
+	if filter.From, err = extractTimeQueryParam(w, r, "from"); err != nil {
+		return nil, err
+	}
+	if filter.To, err = extractTimeQueryParam(w, r, "to"); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+func extractTimeQueryParam(w http.ResponseWriter, r *http.Request, paramName string) (*time.Time, error) {
+	paramValue := r.URL.Query().Get(paramName)
+	if len(paramValue) == 0 {
+		return nil, nil
+	}
+	paramTime, err := time.Parse(time.RFC3339, paramValue)
+	if err != nil {
+		common.WriteJsonResp(w, fmt.Errorf("invalid %s, expected RFC3339 time: %w", paramName, err), nil, http.StatusBadRequest)
+		return nil, err
+	}
+	return &paramTime, nil
+}
@@ -50,6 +50,9 @@ type K8sCapacityRestHandler interface {
 	CordonOrUnCordonNode(w http.ResponseWriter, r *http.Request)
 	DrainNode(w http.ResponseWriter, r *http.Request)
 	EditNodeTaints(w http.ResponseWriter, r *http.Request)
+	PlanNodeDrain(w http.ResponseWriter, r *http.Request)
+	StartBatchDrain(w http.ResponseWriter, r *http.Request)
+	GetBatchDrain(w http.ResponseWriter, r *http.Request)
 }
 type K8sCapacityRestHandlerImpl struct {
 	logger             *zap.SugaredLogger
@@ -461,3 +464,107 @@ func (handler *K8sCapacityRestHandlerImpl) EditNodeTaints(w http.ResponseWriter,
 	}
 	common.WriteJsonResp(w, nil, resp, http.StatusOK)
 }
+
+func (handler *K8sCapacityRestHandlerImpl) PlanNodeDrain(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var planReq bean.NodeDrainPlanRequest
+	err := decoder.Decode(&planReq)
+	if err != nil {
+		handler.logger.Errorw("error in decoding request body", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if ok := handler.checkAuthorisationForDrainNodes(w, token, &planReq, casbin.ActionGet); !ok {
+		return
+	}
+	resp, err := handler.k8sCapacityService.PlanNodeDrain(r.Context(), &planReq)
+	if err != nil {
+		handler.logger.Errorw("error in planning node drain", "err", err, "req", planReq)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) StartBatchDrain(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var batchDrainReq bean.BatchDrainRequest
+	err := decoder.Decode(&batchDrainReq)
+	if err != nil {
+		handler.logger.Errorw("error in decoding request body", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if ok := handler.checkAuthorisationForDrainNodes(w, token, &batchDrainReq.NodeDrainPlanRequest, casbin.ActionUpdate); !ok {
+		return
+	}
+	resp, err := handler.k8sCapacityService.StartBatchDrain(r.Context(), &batchDrainReq, userId)
+	if err != nil {
+		handler.logger.Errorw("error in starting batch node drain", "err", err, "req", batchDrainReq)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetBatchDrain(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	resp, err := handler.k8sCapacityService.GetBatchDrain(mux.Vars(r)["id"])
+	if err != nil {
+		handler.logger.Errorw("error in getting batch node drain", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	authenticated, err := handler.clusterRbacService.CheckAuthorisationForNodeWithClusterId(token, resp.ClusterId, "", casbin.ActionGet)
+	if err != nil {
+		handler.logger.Errorw("error in checking rbac for cluster", "err", err, "clusterId", resp.ClusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !authenticated {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// checkAuthorisationForDrainNodes checks access to each of the nodes, draining a node group needs access to all the nodes
+func (handler *K8sCapacityRestHandlerImpl) checkAuthorisationForDrainNodes(w http.ResponseWriter, token string, request *bean.NodeDrainPlanRequest, action string) bool {
+	nodeNames := request.NodeNames
+	if len(nodeNames) == 0 {
+		nodeNames = []string{""}
+	}
+	for _, nodeName := range nodeNames {
+		authenticated, err := handler.clusterRbacService.CheckAuthorisationForNodeWithClusterId(token, request.ClusterId, nodeName, action)
+		if err != nil {
+			handler.logger.Errorw("error in checking rbac for cluster", "err", err, "clusterId", request.ClusterId)
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return false
+		}
+		if !authenticated {
+			common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+			return false
+		}
+	}
+	return true
+}
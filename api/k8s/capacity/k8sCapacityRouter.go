@@ -61,6 +61,15 @@ func (impl *K8sCapacityRouterImpl) InitK8sCapacityRouter(k8sCapacityRouter *mux.
 	k8sCapacityRouter.Path("/node/drain").
 		HandlerFunc(impl.k8sCapacityRestHandler.DrainNode).Methods("PUT")
 
+	k8sCapacityRouter.Path("/node/drain/plan").
+		HandlerFunc(impl.k8sCapacityRestHandler.PlanNodeDrain).Methods("POST")
+
+	k8sCapacityRouter.Path("/node/drain/batch").
+		HandlerFunc(impl.k8sCapacityRestHandler.StartBatchDrain).Methods("POST")
+
+	k8sCapacityRouter.Path("/node/drain/batch/{id}").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetBatchDrain).Methods("GET")
+
 	k8sCapacityRouter.Path("/node/taints/edit").
 		HandlerFunc(impl.k8sCapacityRestHandler.EditNodeTaints).Methods("PUT")
 }
@@ -21,11 +21,21 @@ import (
 	"github.com/devtron-labs/devtron/api/k8s/capacity"
 	"github.com/devtron-labs/devtron/pkg/cluster"
 	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/debugProfile"
+	debugProfileRepository "github.com/devtron-labs/devtron/pkg/debugProfile/repository"
 	"github.com/devtron-labs/devtron/pkg/k8s"
 	application2 "github.com/devtron-labs/devtron/pkg/k8s/application"
 	capacity2 "github.com/devtron-labs/devtron/pkg/k8s/capacity"
 	"github.com/devtron-labs/devtron/pkg/k8s/informer"
+	"github.com/devtron-labs/devtron/pkg/k8s/podLogs"
+	"github.com/devtron-labs/devtron/pkg/portForward"
+	"github.com/devtron-labs/devtron/pkg/resourceSearch"
+	resourceSearchRepository "github.com/devtron-labs/devtron/pkg/resourceSearch/repository"
 	"github.com/devtron-labs/devtron/pkg/terminal"
+	"github.com/devtron-labs/devtron/pkg/terminalPolicy"
+	terminalPolicyRepository "github.com/devtron-labs/devtron/pkg/terminalPolicy/repository"
+	"github.com/devtron-labs/devtron/pkg/terminalRecording"
+	terminalRecordingRepository "github.com/devtron-labs/devtron/pkg/terminalRecording/repository"
 	"github.com/google/wire"
 )
 
@@ -42,8 +52,38 @@ var K8sApplicationWireSet = wire.NewSet(
 	wire.Bind(new(clusterRepository.EphemeralContainersRepository), new(*clusterRepository.EphemeralContainersRepositoryImpl)),
 	cluster.NewEphemeralContainerServiceImpl,
 	wire.Bind(new(cluster.EphemeralContainerService), new(*cluster.EphemeralContainerServiceImpl)),
+	debugProfileRepository.NewDebugProfileRepositoryImpl,
+	wire.Bind(new(debugProfileRepository.DebugProfileRepository), new(*debugProfileRepository.DebugProfileRepositoryImpl)),
+	debugProfile.NewDebugProfileServiceImpl,
+	wire.Bind(new(debugProfile.DebugProfileService), new(*debugProfile.DebugProfileServiceImpl)),
+	application.NewDebugProfileRestHandlerImpl,
+	wire.Bind(new(application.DebugProfileRestHandler), new(*application.DebugProfileRestHandlerImpl)),
 	terminal.NewTerminalSessionHandlerImpl,
 	wire.Bind(new(terminal.TerminalSessionHandler), new(*terminal.TerminalSessionHandlerImpl)),
+	terminalRecordingRepository.NewTerminalSessionRecordingRepositoryImpl,
+	wire.Bind(new(terminalRecordingRepository.TerminalSessionRecordingRepository), new(*terminalRecordingRepository.TerminalSessionRecordingRepositoryImpl)),
+	terminalRecording.NewTerminalRecordingServiceImpl,
+	wire.Bind(new(terminalRecording.TerminalRecordingService), new(*terminalRecording.TerminalRecordingServiceImpl)),
+	application.NewTerminalRecordingRestHandlerImpl,
+	wire.Bind(new(application.TerminalRecordingRestHandler), new(*application.TerminalRecordingRestHandlerImpl)),
+	terminalPolicyRepository.NewTerminalCommandPolicyRepositoryImpl,
+	wire.Bind(new(terminalPolicyRepository.TerminalCommandPolicyRepository), new(*terminalPolicyRepository.TerminalCommandPolicyRepositoryImpl)),
+	terminalPolicy.NewTerminalPolicyServiceImpl,
+	wire.Bind(new(terminalPolicy.TerminalPolicyService), new(*terminalPolicy.TerminalPolicyServiceImpl)),
+	application.NewTerminalPolicyRestHandlerImpl,
+	wire.Bind(new(application.TerminalPolicyRestHandler), new(*application.TerminalPolicyRestHandlerImpl)),
+	podLogs.NewPodLogsServiceImpl,
+	wire.Bind(new(podLogs.PodLogsService), new(*podLogs.PodLogsServiceImpl)),
+	portForward.NewPortForwardServiceImpl,
+	wire.Bind(new(portForward.PortForwardService), new(*portForward.PortForwardServiceImpl)),
+	application.NewPortForwardRestHandlerImpl,
+	wire.Bind(new(application.PortForwardRestHandler), new(*application.PortForwardRestHandlerImpl)),
+	resourceSearchRepository.NewSavedResourceQueryRepositoryImpl,
+	wire.Bind(new(resourceSearchRepository.SavedResourceQueryRepository), new(*resourceSearchRepository.SavedResourceQueryRepositoryImpl)),
+	resourceSearch.NewResourceSearchServiceImpl,
+	wire.Bind(new(resourceSearch.ResourceSearchService), new(*resourceSearch.ResourceSearchServiceImpl)),
+	application.NewResourceSearchRestHandlerImpl,
+	wire.Bind(new(application.ResourceSearchRestHandler), new(*application.ResourceSearchRestHandlerImpl)),
 	capacity.NewK8sCapacityRouterImpl,
 	wire.Bind(new(capacity.K8sCapacityRouter), new(*capacity.K8sCapacityRouterImpl)),
 	capacity.NewK8sCapacityRestHandlerImpl,
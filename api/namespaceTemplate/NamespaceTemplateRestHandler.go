@@ -0,0 +1,256 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespaceTemplate
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/namespaceTemplate"
+	"github.com/devtron-labs/devtron/pkg/namespaceTemplate/bean"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type NamespaceTemplateRestHandler interface {
+	GetAll(w http.ResponseWriter, r *http.Request)
+	GetById(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	GetEnvironments(w http.ResponseWriter, r *http.Request)
+	Preview(w http.ResponseWriter, r *http.Request)
+	Apply(w http.ResponseWriter, r *http.Request)
+}
+
+type NamespaceTemplateRestHandlerImpl struct {
+	logger                   *zap.SugaredLogger
+	userService              user.UserService
+	namespaceTemplateService namespaceTemplate.NamespaceTemplateService
+	enforcer                 casbin.Enforcer
+	validator                *validator.Validate
+}
+
+func NewNamespaceTemplateRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	namespaceTemplateService namespaceTemplate.NamespaceTemplateService, enforcer casbin.Enforcer,
+	validator *validator.Validate) *NamespaceTemplateRestHandlerImpl {
+	return &NamespaceTemplateRestHandlerImpl{
+		logger:                   logger,
+		userService:              userService,
+		namespaceTemplateService: namespaceTemplateService,
+		enforcer:                 enforcer,
+		validator:                validator,
+	}
+}
+
+// authorize checks the logged-in user for the global access namespace templates need, as they are applied across
+// every cluster, and returns the user id
+func (handler *NamespaceTemplateRestHandlerImpl) authorize(w http.ResponseWriter, r *http.Request, action string) (int32, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, action, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
+
+func (handler *NamespaceTemplateRestHandlerImpl) GetAll(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.authorize(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := handler.namespaceTemplateService.GetAll()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAll", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *NamespaceTemplateRestHandlerImpl) GetById(w http.ResponseWriter, r *http.Request) {
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	if _, ok := handler.authorize(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := handler.namespaceTemplateService.GetById(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetById", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *NamespaceTemplateRestHandlerImpl) decodeTemplate(w http.ResponseWriter, r *http.Request, userId int32) (*bean.NamespaceTemplateDto, bool) {
+	var request bean.NamespaceTemplateDto
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, decodeTemplate", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, decodeTemplate", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return &request, true
+}
+
+func (handler *NamespaceTemplateRestHandlerImpl) Create(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authorize(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	request, ok := handler.decodeTemplate(w, r, userId)
+	if !ok {
+		return
+	}
+	request.Id = 0
+	res, err := handler.namespaceTemplateService.Create(request)
+	if err != nil {
+		handler.logger.Errorw("service err, Create", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *NamespaceTemplateRestHandlerImpl) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	userId, ok := handler.authorize(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	request, ok := handler.decodeTemplate(w, r, userId)
+	if !ok {
+		return
+	}
+	request.Id = id
+	res, err := handler.namespaceTemplateService.Update(request)
+	if err != nil {
+		handler.logger.Errorw("service err, Update", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *NamespaceTemplateRestHandlerImpl) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	userId, ok := handler.authorize(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	err = handler.namespaceTemplateService.Delete(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, Delete", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, true, http.StatusOK)
+}
+
+func (handler *NamespaceTemplateRestHandlerImpl) GetEnvironments(w http.ResponseWriter, r *http.Request) {
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	if _, ok := handler.authorize(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := handler.namespaceTemplateService.GetEnvironments(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetEnvironments", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *NamespaceTemplateRestHandlerImpl) Preview(w http.ResponseWriter, r *http.Request) {
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	envId, err := common.ExtractIntQueryParam(w, r, "envId", 0)
+	if err != nil {
+		return
+	}
+	if envId <= 0 {
+		common.WriteJsonResp(w, errors.New("invalid envId"), nil, http.StatusBadRequest)
+		return
+	}
+	if _, ok := handler.authorize(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := handler.namespaceTemplateService.Preview(id, envId)
+	if err != nil {
+		handler.logger.Errorw("service err, Preview", "err", err, "id", id, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *NamespaceTemplateRestHandlerImpl) Apply(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authorize(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	var request bean.ApplyTemplateRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, Apply", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, Apply", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.namespaceTemplateService.Apply(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, Apply", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespaceTemplate
+
+import "github.com/gorilla/mux"
+
+type NamespaceTemplateRouter interface {
+	InitNamespaceTemplateRouter(namespaceTemplateRouter *mux.Router)
+}
+
+type NamespaceTemplateRouterImpl struct {
+	namespaceTemplateRestHandler NamespaceTemplateRestHandler
+}
+
+func NewNamespaceTemplateRouterImpl(namespaceTemplateRestHandler NamespaceTemplateRestHandler) *NamespaceTemplateRouterImpl {
+	return &NamespaceTemplateRouterImpl{
+		namespaceTemplateRestHandler: namespaceTemplateRestHandler,
+	}
+}
+
+func (router *NamespaceTemplateRouterImpl) InitNamespaceTemplateRouter(namespaceTemplateRouter *mux.Router) {
+	namespaceTemplateRouter.Path("").HandlerFunc(router.namespaceTemplateRestHandler.GetAll).Methods("GET")
+	namespaceTemplateRouter.Path("").HandlerFunc(router.namespaceTemplateRestHandler.Create).Methods("POST")
+	namespaceTemplateRouter.Path("/apply").HandlerFunc(router.namespaceTemplateRestHandler.Apply).Methods("POST")
+	namespaceTemplateRouter.Path("/{id}").HandlerFunc(router.namespaceTemplateRestHandler.GetById).Methods("GET")
+	namespaceTemplateRouter.Path("/{id}").HandlerFunc(router.namespaceTemplateRestHandler.Update).Methods("PUT")
+	namespaceTemplateRouter.Path("/{id}").HandlerFunc(router.namespaceTemplateRestHandler.Delete).Methods("DELETE")
+	namespaceTemplateRouter.Path("/{id}/environments").HandlerFunc(router.namespaceTemplateRestHandler.GetEnvironments).Methods("GET")
+	namespaceTemplateRouter.Path("/{id}/preview").HandlerFunc(router.namespaceTemplateRestHandler.Preview).Methods("GET")
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package previewEnvironment
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/previewEnvironment"
+	"github.com/devtron-labs/devtron/pkg/previewEnvironment/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type PreviewEnvironmentRestHandler interface {
+	SaveConfig(w http.ResponseWriter, r *http.Request)
+	GetConfigs(w http.ResponseWriter, r *http.Request)
+	DeleteConfig(w http.ResponseWriter, r *http.Request)
+	GetPreviewEnvironments(w http.ResponseWriter, r *http.Request)
+	DeletePreviewEnvironment(w http.ResponseWriter, r *http.Request)
+}
+
+type PreviewEnvironmentRestHandlerImpl struct {
+	logger                    *zap.SugaredLogger
+	userService               user.UserService
+	previewEnvironmentService previewEnvironment.PreviewEnvironmentService
+	enforcer                  casbin.Enforcer
+	enforcerUtil              rbac.EnforcerUtil
+	validator                 *validator.Validate
+}
+
+func NewPreviewEnvironmentRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	previewEnvironmentService previewEnvironment.PreviewEnvironmentService, enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil, validator *validator.Validate) *PreviewEnvironmentRestHandlerImpl {
+	return &PreviewEnvironmentRestHandlerImpl{
+		logger:                    logger,
+		userService:               userService,
+		previewEnvironmentService: previewEnvironmentService,
+		enforcer:                  enforcer,
+		enforcerUtil:              enforcerUtil,
+		validator:                 validator,
+	}
+}
+
+func (handler *PreviewEnvironmentRestHandlerImpl) SaveConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := strconv.Atoi(mux.Vars(r)["appId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request bean.PreviewEnvironmentConfigRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.AppId = appId
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.isAuthorisedForApp(r.Header.Get("token"), appId, casbin.ActionCreate) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	res, err := handler.previewEnvironmentService.CreateOrUpdateConfig(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *PreviewEnvironmentRestHandlerImpl) GetConfigs(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := strconv.Atoi(mux.Vars(r)["appId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.isAuthorisedForApp(r.Header.Get("token"), appId, casbin.ActionGet) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	res, err := handler.previewEnvironmentService.GetConfigs(appId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetConfigs", "err", err, "appId", appId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *PreviewEnvironmentRestHandlerImpl) DeleteConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	appId, err := strconv.Atoi(vars["appId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.isAuthorisedForApp(r.Header.Get("token"), appId, casbin.ActionDelete) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	err = handler.previewEnvironmentService.DeleteConfig(appId, id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteConfig", "err", err, "appId", appId, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, true, http.StatusOK)
+}
+
+func (handler *PreviewEnvironmentRestHandlerImpl) GetPreviewEnvironments(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := strconv.Atoi(mux.Vars(r)["appId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.isAuthorisedForApp(r.Header.Get("token"), appId, casbin.ActionGet) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	res, err := handler.previewEnvironmentService.GetPreviewEnvironments(appId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPreviewEnvironments", "err", err, "appId", appId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *PreviewEnvironmentRestHandlerImpl) DeletePreviewEnvironment(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	appId, err := strconv.Atoi(vars["appId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.isAuthorisedForApp(r.Header.Get("token"), appId, casbin.ActionDelete) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	err = handler.previewEnvironmentService.DeletePreviewEnvironment(appId, id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeletePreviewEnvironment", "err", err, "appId", appId, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, true, http.StatusOK)
+}
+
+func (handler *PreviewEnvironmentRestHandlerImpl) isAuthorisedForApp(token string, appId int, action string) bool {
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	return handler.enforcer.Enforce(token, casbin.ResourceApplications, action, object)
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package previewEnvironment
+
+import "github.com/gorilla/mux"
+
+type PreviewEnvironmentRouter interface {
+	InitPreviewEnvironmentRouter(previewEnvironmentRouter *mux.Router)
+}
+
+type PreviewEnvironmentRouterImpl struct {
+	previewEnvironmentRestHandler PreviewEnvironmentRestHandler
+}
+
+func NewPreviewEnvironmentRouterImpl(previewEnvironmentRestHandler PreviewEnvironmentRestHandler) *PreviewEnvironmentRouterImpl {
+	return &PreviewEnvironmentRouterImpl{
+		previewEnvironmentRestHandler: previewEnvironmentRestHandler,
+	}
+}
+
+func (router *PreviewEnvironmentRouterImpl) InitPreviewEnvironmentRouter(previewEnvironmentRouter *mux.Router) {
+	previewEnvironmentRouter.Path("/app/{appId}/config").HandlerFunc(router.previewEnvironmentRestHandler.SaveConfig).Methods("POST")
+	previewEnvironmentRouter.Path("/app/{appId}/config").HandlerFunc(router.previewEnvironmentRestHandler.GetConfigs).Methods("GET")
+	previewEnvironmentRouter.Path("/app/{appId}/config/{id}").HandlerFunc(router.previewEnvironmentRestHandler.DeleteConfig).Methods("DELETE")
+	previewEnvironmentRouter.Path("/app/{appId}").HandlerFunc(router.previewEnvironmentRestHandler.GetPreviewEnvironments).Methods("GET")
+	previewEnvironmentRouter.Path("/app/{appId}/{id}").HandlerFunc(router.previewEnvironmentRestHandler.DeletePreviewEnvironment).Methods("DELETE")
+}
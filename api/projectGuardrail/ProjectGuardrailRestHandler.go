@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package projectGuardrail
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail/bean"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type ProjectGuardrailRestHandler interface {
+	GetAllUsage(w http.ResponseWriter, r *http.Request)
+	GetUsage(w http.ResponseWriter, r *http.Request)
+	SaveGuardrail(w http.ResponseWriter, r *http.Request)
+	DeleteGuardrail(w http.ResponseWriter, r *http.Request)
+}
+
+type ProjectGuardrailRestHandlerImpl struct {
+	logger                  *zap.SugaredLogger
+	userService             user.UserService
+	projectGuardrailService projectGuardrail.ProjectGuardrailService
+	enforcer                casbin.Enforcer
+	validator               *validator.Validate
+}
+
+func NewProjectGuardrailRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	projectGuardrailService projectGuardrail.ProjectGuardrailService, enforcer casbin.Enforcer,
+	validator *validator.Validate) *ProjectGuardrailRestHandlerImpl {
+	return &ProjectGuardrailRestHandlerImpl{
+		logger:                  logger,
+		userService:             userService,
+		projectGuardrailService: projectGuardrailService,
+		enforcer:                enforcer,
+		validator:               validator,
+	}
+}
+
+func (handler *ProjectGuardrailRestHandlerImpl) GetAllUsage(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkSuperAdmin(w, r); !ok {
+		return
+	}
+	res, err := handler.projectGuardrailService.GetAllUsage()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAllUsage", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ProjectGuardrailRestHandlerImpl) GetUsage(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkSuperAdmin(w, r); !ok {
+		return
+	}
+	teamId, err := strconv.Atoi(mux.Vars(r)["teamId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.projectGuardrailService.GetUsage(teamId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetUsage", "teamId", teamId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ProjectGuardrailRestHandlerImpl) SaveGuardrail(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkSuperAdmin(w, r)
+	if !ok {
+		return
+	}
+	teamId, err := strconv.Atoi(mux.Vars(r)["teamId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request bean.ProjectGuardrailDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveGuardrail", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.TeamId = teamId
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveGuardrail", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.projectGuardrailService.SaveGuardrail(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveGuardrail", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ProjectGuardrailRestHandlerImpl) DeleteGuardrail(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkSuperAdmin(w, r)
+	if !ok {
+		return
+	}
+	teamId, err := strconv.Atoi(mux.Vars(r)["teamId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.projectGuardrailService.DeleteGuardrail(teamId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteGuardrail", "teamId", teamId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, teamId, http.StatusOK)
+}
+
+func (handler *ProjectGuardrailRestHandlerImpl) checkSuperAdmin(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
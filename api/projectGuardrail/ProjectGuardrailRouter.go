@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package projectGuardrail
+
+import "github.com/gorilla/mux"
+
+type ProjectGuardrailRouter interface {
+	InitProjectGuardrailRouter(projectGuardrailRouter *mux.Router)
+}
+
+type ProjectGuardrailRouterImpl struct {
+	projectGuardrailRestHandler ProjectGuardrailRestHandler
+}
+
+func NewProjectGuardrailRouterImpl(projectGuardrailRestHandler ProjectGuardrailRestHandler) *ProjectGuardrailRouterImpl {
+	return &ProjectGuardrailRouterImpl{
+		projectGuardrailRestHandler: projectGuardrailRestHandler,
+	}
+}
+
+func (router *ProjectGuardrailRouterImpl) InitProjectGuardrailRouter(projectGuardrailRouter *mux.Router) {
+	projectGuardrailRouter.Path("/usage").HandlerFunc(router.projectGuardrailRestHandler.GetAllUsage).Methods("GET")
+	projectGuardrailRouter.Path("/{teamId}/usage").HandlerFunc(router.projectGuardrailRestHandler.GetUsage).Methods("GET")
+	projectGuardrailRouter.Path("/{teamId}").HandlerFunc(router.projectGuardrailRestHandler.SaveGuardrail).Methods("PUT")
+	projectGuardrailRouter.Path("/{teamId}").HandlerFunc(router.projectGuardrailRestHandler.DeleteGuardrail).Methods("DELETE")
+}
@@ -0,0 +1,390 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package releaseTrain
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type ReleaseTrainRestHandler interface {
+	CreateReleaseTrain(w http.ResponseWriter, r *http.Request)
+	ListReleaseTrains(w http.ResponseWriter, r *http.Request)
+	GetReleaseTrain(w http.ResponseWriter, r *http.Request)
+	DeleteReleaseTrain(w http.ResponseWriter, r *http.Request)
+	DiffReleaseTrains(w http.ResponseWriter, r *http.Request)
+	PromoteReleaseTrain(w http.ResponseWriter, r *http.Request)
+	RollbackEnvironment(w http.ResponseWriter, r *http.Request)
+	GetReleaseTrainDeployment(w http.ResponseWriter, r *http.Request)
+	GetEnvironmentReleaseHistory(w http.ResponseWriter, r *http.Request)
+}
+
+type ReleaseTrainRestHandlerImpl struct {
+	logger              *zap.SugaredLogger
+	userService         user.UserService
+	releaseTrainService releaseTrain.ReleaseTrainService
+	enforcer            casbin.Enforcer
+	enforcerUtil        rbac.EnforcerUtil
+	validator           *validator.Validate
+}
+
+func NewReleaseTrainRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	releaseTrainService releaseTrain.ReleaseTrainService, enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil, validator *validator.Validate) *ReleaseTrainRestHandlerImpl {
+	return &ReleaseTrainRestHandlerImpl{
+		logger:              logger,
+		userService:         userService,
+		releaseTrainService: releaseTrainService,
+		enforcer:            enforcer,
+		enforcerUtil:        enforcerUtil,
+		validator:           validator,
+	}
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) CreateReleaseTrain(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.ReleaseTrainRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, CreateReleaseTrain", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, CreateReleaseTrain", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	appIds := make([]int, 0, len(request.Apps))
+	for _, app := range request.Apps {
+		appIds = append(appIds, app.AppId)
+	}
+	if !handler.isAuthorisedForApps(token, appIds, casbin.ActionCreate) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	res, err := handler.releaseTrainService.CreateReleaseTrain(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateReleaseTrain", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) ListReleaseTrains(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	releaseTrains, err := handler.releaseTrainService.ListReleaseTrains()
+	if err != nil {
+		handler.logger.Errorw("service err, ListReleaseTrains", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// only release trains whose apps are all visible to the user are returned
+	token := r.Header.Get("token")
+	res := make([]*bean.ReleaseTrainDto, 0, len(releaseTrains))
+	for _, releaseTrain := range releaseTrains {
+		if handler.isAuthorisedForApps(token, getAppIds(releaseTrain), casbin.ActionGet) {
+			res = append(res, releaseTrain)
+		}
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) GetReleaseTrain(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.releaseTrainService.GetReleaseTrain(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetReleaseTrain", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.isAuthorisedForApps(r.Header.Get("token"), getAppIds(res), casbin.ActionGet) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) DeleteReleaseTrain(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	releaseTrain, err := handler.releaseTrainService.GetReleaseTrain(id)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteReleaseTrain", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.isAuthorisedForApps(r.Header.Get("token"), getAppIds(releaseTrain), casbin.ActionDelete) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	err = handler.releaseTrainService.DeleteReleaseTrain(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteReleaseTrain", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, true, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) DiffReleaseTrains(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	baseId, err := common.ExtractIntQueryParam(w, r, "baseId", 0)
+	if err != nil {
+		return
+	}
+	targetId, err := common.ExtractIntQueryParam(w, r, "targetId", 0)
+	if err != nil {
+		return
+	}
+	res, err := handler.releaseTrainService.DiffReleaseTrains(baseId, targetId)
+	if err != nil {
+		handler.logger.Errorw("service err, DiffReleaseTrains", "err", err, "baseId", baseId, "targetId", targetId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.isAuthorisedForApps(token, getAppIds(res.Base), casbin.ActionGet) ||
+		!handler.isAuthorisedForApps(token, getAppIds(res.Target), casbin.ActionGet) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) PromoteReleaseTrain(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.ReleaseTrainPromoteRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, PromoteReleaseTrain", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, PromoteReleaseTrain", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	releaseTrain, err := handler.releaseTrainService.GetReleaseTrain(request.ReleaseTrainId)
+	if err != nil {
+		handler.logger.Errorw("service err, PromoteReleaseTrain", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.isAuthorisedToTrigger(r.Header.Get("token"), getAppIds(releaseTrain), request.EnvironmentId) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	res, err := handler.releaseTrainService.PromoteReleaseTrain(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, PromoteReleaseTrain", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) RollbackEnvironment(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.ReleaseTrainRollbackRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, RollbackEnvironment", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, RollbackEnvironment", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	history, err := handler.releaseTrainService.GetEnvironmentReleaseHistory(request.EnvironmentId)
+	if err != nil {
+		handler.logger.Errorw("service err, RollbackEnvironment", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// rollback touches every app deployed by the release trains in this environment
+	appIdSet := make(map[int]bool)
+	appIds := make([]int, 0)
+	for _, deployment := range history {
+		for _, app := range deployment.Apps {
+			if !appIdSet[app.AppId] {
+				appIdSet[app.AppId] = true
+				appIds = append(appIds, app.AppId)
+			}
+		}
+	}
+	if !handler.isAuthorisedToTrigger(r.Header.Get("token"), appIds, request.EnvironmentId) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	res, err := handler.releaseTrainService.RollbackEnvironment(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, RollbackEnvironment", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) GetReleaseTrainDeployment(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	deploymentId, err := strconv.Atoi(mux.Vars(r)["deploymentId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.releaseTrainService.GetReleaseTrainDeployment(deploymentId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetReleaseTrainDeployment", "err", err, "deploymentId", deploymentId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.isAuthorisedForApps(r.Header.Get("token"), getDeploymentAppIds(res), casbin.ActionGet) {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) GetEnvironmentReleaseHistory(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	envId, err := strconv.Atoi(mux.Vars(r)["envId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	history, err := handler.releaseTrainService.GetEnvironmentReleaseHistory(envId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetEnvironmentReleaseHistory", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	res := make([]*bean.ReleaseTrainDeploymentDto, 0, len(history))
+	for _, deployment := range history {
+		if handler.isAuthorisedForApps(token, getDeploymentAppIds(deployment), casbin.ActionGet) {
+			res = append(res, deployment)
+		}
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) isAuthorisedForApps(token string, appIds []int, action string) bool {
+	rbacObjects := handler.enforcerUtil.GetRbacObjectsByAppIds(appIds)
+	for _, appId := range appIds {
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, action, rbacObjects[appId]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) isAuthorisedToTrigger(token string, appIds []int, envId int) bool {
+	if !handler.isAuthorisedForApps(token, appIds, casbin.ActionTrigger) {
+		return false
+	}
+	for _, appId := range appIds {
+		object := handler.enforcerUtil.GetEnvRBACNameByAppId(appId, envId)
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionTrigger, object); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func getAppIds(releaseTrain *bean.ReleaseTrainDto) []int {
+	appIds := make([]int, 0, len(releaseTrain.Apps))
+	for _, app := range releaseTrain.Apps {
+		appIds = append(appIds, app.AppId)
+	}
+	return appIds
+}
+
+func getDeploymentAppIds(deployment *bean.ReleaseTrainDeploymentDto) []int {
+	appIds := make([]int, 0, len(deployment.Apps))
+	for _, app := range deployment.Apps {
+		appIds = append(appIds, app.AppId)
+	}
+	return appIds
+}
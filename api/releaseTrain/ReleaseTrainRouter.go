@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package releaseTrain
+
+import "github.com/gorilla/mux"
+
+type ReleaseTrainRouter interface {
+	InitReleaseTrainRouter(releaseTrainRouter *mux.Router)
+}
+
+type ReleaseTrainRouterImpl struct {
+	releaseTrainRestHandler ReleaseTrainRestHandler
+}
+
+func NewReleaseTrainRouterImpl(releaseTrainRestHandler ReleaseTrainRestHandler) *ReleaseTrainRouterImpl {
+	return &ReleaseTrainRouterImpl{
+		releaseTrainRestHandler: releaseTrainRestHandler,
+	}
+}
+
+func (router *ReleaseTrainRouterImpl) InitReleaseTrainRouter(releaseTrainRouter *mux.Router) {
+	releaseTrainRouter.Path("").HandlerFunc(router.releaseTrainRestHandler.CreateReleaseTrain).Methods("POST")
+	releaseTrainRouter.Path("").HandlerFunc(router.releaseTrainRestHandler.ListReleaseTrains).Methods("GET")
+	releaseTrainRouter.Path("/diff").
+		Queries("baseId", "{baseId}", "targetId", "{targetId}").
+		HandlerFunc(router.releaseTrainRestHandler.DiffReleaseTrains).Methods("GET")
+	releaseTrainRouter.Path("/promote").HandlerFunc(router.releaseTrainRestHandler.PromoteReleaseTrain).Methods("POST")
+	releaseTrainRouter.Path("/rollback").HandlerFunc(router.releaseTrainRestHandler.RollbackEnvironment).Methods("POST")
+	releaseTrainRouter.Path("/deployment/{deploymentId}").HandlerFunc(router.releaseTrainRestHandler.GetReleaseTrainDeployment).Methods("GET")
+	releaseTrainRouter.Path("/env/{envId}/history").HandlerFunc(router.releaseTrainRestHandler.GetEnvironmentReleaseHistory).Methods("GET")
+	releaseTrainRouter.Path("/{id}").HandlerFunc(router.releaseTrainRestHandler.GetReleaseTrain).Methods("GET")
+	releaseTrainRouter.Path("/{id}").HandlerFunc(router.releaseTrainRestHandler.DeleteReleaseTrain).Methods("DELETE")
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/build/git/gitWebhook"
 	"github.com/devtron-labs/devtron/pkg/eventProcessor/out"
 	"github.com/devtron-labs/devtron/pkg/eventProcessor/out/bean"
+	"github.com/devtron-labs/devtron/pkg/previewEnvironment"
 	"io/ioutil"
 	"net/http"
 	"strconv"
@@ -44,12 +45,14 @@ type WebhookEventHandlerImpl struct {
 	webhookSecretValidator        gitWebhook.WebhookSecretValidator
 	webhookEventDataConfig        pipeline.WebhookEventDataConfig
 	ciPipelineEventPublishService out.CIPipelineEventPublishService
+	previewEnvironmentService     previewEnvironment.PreviewEnvironmentService
 }
 
 func NewWebhookEventHandlerImpl(logger *zap.SugaredLogger, eventClient client.EventClient,
 	webhookSecretValidator gitWebhook.WebhookSecretValidator, webhookEventDataConfig pipeline.WebhookEventDataConfig,
 	ciPipelineEventPublishService out.CIPipelineEventPublishService,
-	gitHostReadService read.GitHostReadService) *WebhookEventHandlerImpl {
+	gitHostReadService read.GitHostReadService,
+	previewEnvironmentService previewEnvironment.PreviewEnvironmentService) *WebhookEventHandlerImpl {
 	return &WebhookEventHandlerImpl{
 		logger:                        logger,
 		eventClient:                   eventClient,
@@ -57,6 +60,7 @@ func NewWebhookEventHandlerImpl(logger *zap.SugaredLogger, eventClient client.Ev
 		webhookEventDataConfig:        webhookEventDataConfig,
 		ciPipelineEventPublishService: ciPipelineEventPublishService,
 		gitHostReadService:            gitHostReadService,
+		previewEnvironmentService:     previewEnvironmentService,
 	}
 }
 
@@ -137,6 +141,9 @@ func (impl WebhookEventHandlerImpl) OnWebhookEvent(w http.ResponseWriter, r *htt
 		return
 	}
 
+	// tear down preview environments of closed or merged pull requests
+	impl.previewEnvironmentService.HandleGitWebhookEvent(webhookEvent.RequestPayloadJson)
+
 	// write event
 	err = impl.ciPipelineEventPublishService.PublishGitWebhookEvent(webhookEvent)
 	if err != nil {
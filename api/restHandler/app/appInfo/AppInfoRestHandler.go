@@ -26,6 +26,7 @@ import (
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/internal/sql/repository/helper"
 	"github.com/devtron-labs/devtron/pkg/app"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
 	"github.com/devtron-labs/devtron/pkg/auth/user"
 	"github.com/devtron-labs/devtron/pkg/bean"
@@ -232,15 +233,27 @@ func (handler AppInfoRestHandlerImpl) UpdateApp(w http.ResponseWriter, r *http.R
 	}
 	//rbac implementation ends here
 
+	before := handler.getAppState(request.Id)
 	res, err := handler.appService.UpdateApp(&request)
 	if err != nil {
 		handler.logger.Errorw("service err, UpdateApp", "err", err)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	auditLog.RecordStateChange(r.Context(), before, handler.getAppState(request.Id))
 	common.WriteJsonResp(w, nil, res, http.StatusOK)
 }
 
+// getAppState returns the project, labels and description of the app for the audit of its update
+func (handler AppInfoRestHandlerImpl) getAppState(appId int) *bean.AppMetaInfoDto {
+	appMetaInfo, err := handler.appService.GetAppMetaInfo(appId, app.ZERO_INSTALLED_APP_ID, app.ZERO_ENVIRONMENT_ID)
+	if err != nil {
+		handler.logger.Errorw("error in fetching app state for audit", "appId", appId, "err", err)
+		return nil
+	}
+	return appMetaInfo
+}
+
 func (handler AppInfoRestHandlerImpl) UpdateProjectForApps(w http.ResponseWriter, r *http.Request) {
 	userId, err := handler.userAuthService.GetLoggedInUser(r)
 	if userId == 0 || err != nil {
@@ -22,6 +22,8 @@ import (
 	util2 "github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/deployment/deployedApp"
 	bean2 "github.com/devtron-labs/devtron/pkg/deployment/deployedApp/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/dryRun"
+	dryRunBean "github.com/devtron-labs/devtron/pkg/deployment/dryRun/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps"
 	bean3 "github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
 	"github.com/devtron-labs/devtron/pkg/eventProcessor/out"
@@ -48,6 +50,7 @@ import (
 
 type PipelineTriggerRestHandler interface {
 	OverrideConfig(w http.ResponseWriter, r *http.Request)
+	DryRunDeployment(w http.ResponseWriter, r *http.Request)
 	ReleaseStatusUpdate(w http.ResponseWriter, r *http.Request)
 	StartStopApp(w http.ResponseWriter, r *http.Request)
 	StartStopDeploymentGroup(w http.ResponseWriter, r *http.Request)
@@ -68,6 +71,7 @@ type PipelineTriggerRestHandlerImpl struct {
 	deployedAppService          deployedApp.DeployedAppService
 	cdTriggerService            devtronApps.TriggerService
 	workflowEventPublishService out.WorkflowEventPublishService
+	dryRunService               dryRun.DryRunService
 }
 
 func NewPipelineRestHandler(appService app.AppService, userAuthService user.UserService, validator *validator.Validate,
@@ -76,7 +80,8 @@ func NewPipelineRestHandler(appService app.AppService, userAuthService user.User
 	deploymentConfigService pipeline.PipelineDeploymentConfigService,
 	deployedAppService deployedApp.DeployedAppService,
 	cdTriggerService devtronApps.TriggerService,
-	workflowEventPublishService out.WorkflowEventPublishService) *PipelineTriggerRestHandlerImpl {
+	workflowEventPublishService out.WorkflowEventPublishService,
+	dryRunService dryRun.DryRunService) *PipelineTriggerRestHandlerImpl {
 	pipelineHandler := &PipelineTriggerRestHandlerImpl{
 		appService:                  appService,
 		userAuthService:             userAuthService,
@@ -90,6 +95,7 @@ func NewPipelineRestHandler(appService app.AppService, userAuthService user.User
 		deployedAppService:          deployedAppService,
 		cdTriggerService:            cdTriggerService,
 		workflowEventPublishService: workflowEventPublishService,
+		dryRunService:               dryRunService,
 	}
 	return pipelineHandler
 }
@@ -151,6 +157,45 @@ func (handler PipelineTriggerRestHandlerImpl) OverrideConfig(w http.ResponseWrit
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
+func (handler PipelineTriggerRestHandlerImpl) DryRunDeployment(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var dryRunRequest dryRunBean.DryRunRequest
+	err = decoder.Decode(&dryRunRequest)
+	if err != nil {
+		handler.logger.Errorw("request err, DryRunDeployment", "err", err, "payload", dryRunRequest)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	dryRunRequest.UserId = userId
+	err = handler.validator.Struct(dryRunRequest)
+	if err != nil {
+		handler.logger.Errorw("validation err, DryRunDeployment", "err", err, "payload", dryRunRequest)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// dry run renders the merged values including secrets, hence same access as trigger is required
+	token := r.Header.Get("token")
+	if rbacErr := handler.validateCdTriggerRBAC(token, dryRunRequest.AppId, dryRunRequest.PipelineId); rbacErr != nil {
+		common.WriteJsonResp(w, rbacErr, nil, http.StatusForbidden)
+		return
+	}
+	ctx := r.Context()
+	_, span := otel.Tracer("orchestrator").Start(ctx, "dryRunService.DryRun")
+	resp, err := handler.dryRunService.DryRun(ctx, &dryRunRequest)
+	span.End()
+	if err != nil {
+		handler.logger.Errorw("service err, DryRunDeployment", "err", err, "payload", dryRunRequest)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
 func (handler PipelineTriggerRestHandlerImpl) RotatePods(w http.ResponseWriter, r *http.Request) {
 	decoder := json.NewDecoder(r.Body)
 	userId, err := handler.userAuthService.GetLoggedInUser(r)
@@ -0,0 +1,253 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rightsizing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/cluster/rbac"
+	"github.com/devtron-labs/devtron/pkg/cluster/read"
+	"github.com/devtron-labs/devtron/pkg/rightsizing"
+	"github.com/devtron-labs/devtron/pkg/rightsizing/bean"
+	rbac2 "github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type RightsizingRestHandler interface {
+	GetReport(w http.ResponseWriter, r *http.Request)
+	ApplyRecommendation(w http.ResponseWriter, r *http.Request)
+	GetPrices(w http.ResponseWriter, r *http.Request)
+	SavePrice(w http.ResponseWriter, r *http.Request)
+	DeletePrice(w http.ResponseWriter, r *http.Request)
+}
+
+type RightsizingRestHandlerImpl struct {
+	logger             *zap.SugaredLogger
+	userService        user.UserService
+	rightsizingService rightsizing.RightsizingService
+	enforcer           casbin.Enforcer
+	enforcerUtil       rbac2.EnforcerUtil
+	clusterRbacService rbac.ClusterRbacService
+	clusterReadService read.ClusterReadService
+	validator          *validator.Validate
+}
+
+func NewRightsizingRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	rightsizingService rightsizing.RightsizingService, enforcer casbin.Enforcer, enforcerUtil rbac2.EnforcerUtil,
+	clusterRbacService rbac.ClusterRbacService, clusterReadService read.ClusterReadService,
+	validator *validator.Validate) *RightsizingRestHandlerImpl {
+	return &RightsizingRestHandlerImpl{
+		logger:             logger,
+		userService:        userService,
+		rightsizingService: rightsizingService,
+		enforcer:           enforcer,
+		enforcerUtil:       enforcerUtil,
+		clusterRbacService: clusterRbacService,
+		clusterReadService: clusterReadService,
+		validator:          validator,
+	}
+}
+
+func (handler *RightsizingRestHandlerImpl) GetReport(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	clusterId, err := common.ExtractIntQueryParam(w, r, "clusterId", 0)
+	if err != nil {
+		return
+	}
+	if clusterId <= 0 {
+		common.WriteJsonResp(w, errors.New("invalid clusterId"), nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	// RBAC enforcer applying
+	cluster, err := handler.clusterReadService.FindById(clusterId)
+	if err != nil {
+		handler.logger.Errorw("error in getting cluster by id", "err", err, "clusterId", clusterId)
+		if util.IsErrNoRows(err) {
+			common.WriteJsonResp(w, err, "cluster not found", http.StatusNotFound)
+			return
+		}
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	authenticated, err := handler.clusterRbacService.CheckAuthorization(cluster.ClusterName, cluster.Id, token, userId, true)
+	if err != nil {
+		handler.logger.Errorw("error in checking rbac for cluster", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !authenticated {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	report, err := handler.rightsizingService.GetReport(clusterId, r.URL.Query().Get("namespace"))
+	if err != nil {
+		handler.logger.Errorw("service err, GetReport", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, report, http.StatusOK)
+}
+
+// ApplyRecommendation sets the recommended resources of a workload in the deployment template of its devtron app,
+// the user needs the same access as for editing the template
+func (handler *RightsizingRestHandlerImpl) ApplyRecommendation(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.WorkloadIdentifier
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, ApplyRecommendation", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, ApplyRecommendation", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	recommendation, err := handler.rightsizingService.GetRecommendation(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, ApplyRecommendation", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if recommendation.AppId == 0 || recommendation.EnvId == 0 {
+		common.WriteJsonResp(w, errors.New("workload is not deployed by a devtron app"), nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	// RBAC enforcer applying
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(recommendation.AppId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionUpdate, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), nil, http.StatusForbidden)
+		return
+	}
+	object = handler.enforcerUtil.GetEnvRBACNameByAppId(recommendation.AppId, recommendation.EnvId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionUpdate, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	res, err := handler.rightsizingService.ApplyRecommendation(r.Context(), recommendation, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, ApplyRecommendation", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *RightsizingRestHandlerImpl) GetPrices(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	clusterId, err := common.ExtractIntQueryParam(w, r, "clusterId", 0)
+	if err != nil {
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := handler.rightsizingService.GetPrices(clusterId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPrices", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *RightsizingRestHandlerImpl) SavePrice(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.NodeGroupPriceDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SavePrice", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SavePrice", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := handler.rightsizingService.SavePrice(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SavePrice", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *RightsizingRestHandlerImpl) DeletePrice(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	err = handler.rightsizingService.DeletePrice(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeletePrice", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, true, http.StatusOK)
+}
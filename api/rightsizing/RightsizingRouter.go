@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rightsizing
+
+import "github.com/gorilla/mux"
+
+type RightsizingRouter interface {
+	InitRightsizingRouter(rightsizingRouter *mux.Router)
+}
+
+type RightsizingRouterImpl struct {
+	rightsizingRestHandler RightsizingRestHandler
+}
+
+func NewRightsizingRouterImpl(rightsizingRestHandler RightsizingRestHandler) *RightsizingRouterImpl {
+	return &RightsizingRouterImpl{
+		rightsizingRestHandler: rightsizingRestHandler,
+	}
+}
+
+func (router *RightsizingRouterImpl) InitRightsizingRouter(rightsizingRouter *mux.Router) {
+	rightsizingRouter.Path("/report").HandlerFunc(router.rightsizingRestHandler.GetReport).Methods("GET")
+	rightsizingRouter.Path("/apply").HandlerFunc(router.rightsizingRestHandler.ApplyRecommendation).Methods("POST")
+	rightsizingRouter.Path("/price").HandlerFunc(router.rightsizingRestHandler.GetPrices).Methods("GET")
+	rightsizingRouter.Path("/price").HandlerFunc(router.rightsizingRestHandler.SavePrice).Methods("POST")
+	rightsizingRouter.Path("/price/{id}").HandlerFunc(router.rightsizingRestHandler.DeletePrice).Methods("DELETE")
+}
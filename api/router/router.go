@@ -19,26 +19,41 @@ package router
 import (
 	"encoding/json"
 	"github.com/devtron-labs/devtron/api/apiToken"
+	"github.com/devtron-labs/devtron/api/appSnapshot"
 	"github.com/devtron-labs/devtron/api/appStore"
 	"github.com/devtron-labs/devtron/api/appStore/chartGroup"
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
 	"github.com/devtron-labs/devtron/api/argoApplication"
+	"github.com/devtron-labs/devtron/api/auditLog"
+	"github.com/devtron-labs/devtron/api/auth/jitAccess"
+	"github.com/devtron-labs/devtron/api/auth/rbacExplainer"
+	"github.com/devtron-labs/devtron/api/auth/scim"
 	"github.com/devtron-labs/devtron/api/auth/sso"
 	"github.com/devtron-labs/devtron/api/auth/user"
 	"github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
+	"github.com/devtron-labs/devtron/api/clusterCredential"
+	"github.com/devtron-labs/devtron/api/clusterOnboarding"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
 	"github.com/devtron-labs/devtron/api/deployment"
+	"github.com/devtron-labs/devtron/api/deploymentApproval"
 	"github.com/devtron-labs/devtron/api/devtronResource"
+	"github.com/devtron-labs/devtron/api/eventArchive"
 	"github.com/devtron-labs/devtron/api/externalLink"
 	fluxApplication2 "github.com/devtron-labs/devtron/api/fluxApplication"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	"github.com/devtron-labs/devtron/api/hibernationSchedule"
 	"github.com/devtron-labs/devtron/api/infraConfig"
 	"github.com/devtron-labs/devtron/api/k8s/application"
 	"github.com/devtron-labs/devtron/api/k8s/capacity"
 	"github.com/devtron-labs/devtron/api/module"
+	"github.com/devtron-labs/devtron/api/namespaceTemplate"
+	"github.com/devtron-labs/devtron/api/previewEnvironment"
+	"github.com/devtron-labs/devtron/api/projectGuardrail"
+	"github.com/devtron-labs/devtron/api/releaseTrain"
 	"github.com/devtron-labs/devtron/api/resourceScan"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/api/rightsizing"
 	"github.com/devtron-labs/devtron/api/router/app"
 	"github.com/devtron-labs/devtron/api/router/app/configDiff"
 	"github.com/devtron-labs/devtron/api/server"
@@ -122,6 +137,21 @@ type MuxRouter struct {
 	fluxApplicationRouter              fluxApplication2.FluxApplicationRouter
 	devtronResourceRouter              devtronResource.DevtronResourceRouter
 	scanningResultRouter               resourceScan.ScanningResultRouter
+	releaseTrainRouter                 releaseTrain.ReleaseTrainRouter
+	previewEnvironmentRouter           previewEnvironment.PreviewEnvironmentRouter
+	hibernationScheduleRouter          hibernationSchedule.HibernationScheduleRouter
+	appSnapshotRouter                  appSnapshot.AppSnapshotRouter
+	scimRouter                         scim.ScimRouter
+	jitAccessRouter                    jitAccess.JitAccessRouter
+	rbacExplainerRouter                rbacExplainer.RbacExplainerRouter
+	auditLogRouter                     auditLog.AuditLogRouter
+	projectGuardrailRouter             projectGuardrail.ProjectGuardrailRouter
+	deploymentApprovalRouter           deploymentApproval.DeploymentApprovalRouter
+	clusterOnboardingRouter            clusterOnboarding.ClusterOnboardingRouter
+	clusterCredentialRouter            clusterCredential.ClusterCredentialRouter
+	rightsizingRouter                  rightsizing.RightsizingRouter
+	eventArchiveRouter                 eventArchive.EventArchiveRouter
+	namespaceTemplateRouter            namespaceTemplate.NamespaceTemplateRouter
 }
 
 func NewMuxRouter(logger *zap.SugaredLogger,
@@ -156,6 +186,21 @@ func NewMuxRouter(logger *zap.SugaredLogger,
 	devtronResourceRouter devtronResource.DevtronResourceRouter,
 	fluxApplicationRouter fluxApplication2.FluxApplicationRouter,
 	scanningResultRouter resourceScan.ScanningResultRouter,
+	releaseTrainRouter releaseTrain.ReleaseTrainRouter,
+	previewEnvironmentRouter previewEnvironment.PreviewEnvironmentRouter,
+	hibernationScheduleRouter hibernationSchedule.HibernationScheduleRouter,
+	appSnapshotRouter appSnapshot.AppSnapshotRouter,
+	scimRouter scim.ScimRouter,
+	jitAccessRouter jitAccess.JitAccessRouter,
+	rbacExplainerRouter rbacExplainer.RbacExplainerRouter,
+	auditLogRouter auditLog.AuditLogRouter,
+	projectGuardrailRouter projectGuardrail.ProjectGuardrailRouter,
+	deploymentApprovalRouter deploymentApproval.DeploymentApprovalRouter,
+	clusterOnboardingRouter clusterOnboarding.ClusterOnboardingRouter,
+	clusterCredentialRouter clusterCredential.ClusterCredentialRouter,
+	rightsizingRouter rightsizing.RightsizingRouter,
+	eventArchiveRouter eventArchive.EventArchiveRouter,
+	namespaceTemplateRouter namespaceTemplate.NamespaceTemplateRouter,
 ) *MuxRouter {
 	r := &MuxRouter{
 		Router:                             mux.NewRouter(),
@@ -222,6 +267,21 @@ func NewMuxRouter(logger *zap.SugaredLogger,
 		devtronResourceRouter:              devtronResourceRouter,
 		fluxApplicationRouter:              fluxApplicationRouter,
 		scanningResultRouter:               scanningResultRouter,
+		releaseTrainRouter:                 releaseTrainRouter,
+		previewEnvironmentRouter:           previewEnvironmentRouter,
+		hibernationScheduleRouter:          hibernationScheduleRouter,
+		appSnapshotRouter:                  appSnapshotRouter,
+		scimRouter:                         scimRouter,
+		jitAccessRouter:                    jitAccessRouter,
+		rbacExplainerRouter:                rbacExplainerRouter,
+		auditLogRouter:                     auditLogRouter,
+		projectGuardrailRouter:             projectGuardrailRouter,
+		deploymentApprovalRouter:           deploymentApprovalRouter,
+		clusterOnboardingRouter:            clusterOnboardingRouter,
+		clusterCredentialRouter:            clusterCredentialRouter,
+		rightsizingRouter:                  rightsizingRouter,
+		eventArchiveRouter:                 eventArchiveRouter,
+		namespaceTemplateRouter:            namespaceTemplateRouter,
 	}
 	return r
 }
@@ -437,4 +497,49 @@ func (r MuxRouter) Init() {
 	fluxApplicationRouter := r.Router.PathPrefix("/orchestrator/flux-application").Subrouter()
 	r.fluxApplicationRouter.InitFluxApplicationRouter(fluxApplicationRouter)
 
+	releaseTrainRouter := r.Router.PathPrefix("/orchestrator/release-train").Subrouter()
+	r.releaseTrainRouter.InitReleaseTrainRouter(releaseTrainRouter)
+
+	previewEnvironmentRouter := r.Router.PathPrefix("/orchestrator/preview-environment").Subrouter()
+	r.previewEnvironmentRouter.InitPreviewEnvironmentRouter(previewEnvironmentRouter)
+
+	hibernationScheduleRouter := r.Router.PathPrefix("/orchestrator/hibernation-schedule").Subrouter()
+	r.hibernationScheduleRouter.InitHibernationScheduleRouter(hibernationScheduleRouter)
+
+	appSnapshotRouter := r.Router.PathPrefix("/orchestrator/app-snapshot").Subrouter()
+	r.appSnapshotRouter.InitAppSnapshotRouter(appSnapshotRouter)
+
+	scimRouter := r.Router.PathPrefix("/orchestrator/scim/v2").Subrouter()
+	r.scimRouter.InitScimRouter(scimRouter)
+
+	jitAccessRouter := r.Router.PathPrefix("/orchestrator/jit-access").Subrouter()
+	r.jitAccessRouter.InitJitAccessRouter(jitAccessRouter)
+
+	rbacExplainerRouter := r.Router.PathPrefix("/orchestrator/rbac/explain").Subrouter()
+	r.rbacExplainerRouter.InitRbacExplainerRouter(rbacExplainerRouter)
+
+	auditLogRouter := r.Router.PathPrefix("/orchestrator/audit-log").Subrouter()
+	r.auditLogRouter.InitAuditLogRouter(auditLogRouter)
+
+	projectGuardrailRouter := r.Router.PathPrefix("/orchestrator/project-guardrail").Subrouter()
+	r.projectGuardrailRouter.InitProjectGuardrailRouter(projectGuardrailRouter)
+
+	deploymentApprovalRouter := r.Router.PathPrefix("/orchestrator/deployment-approval").Subrouter()
+	r.deploymentApprovalRouter.InitDeploymentApprovalRouter(deploymentApprovalRouter)
+
+	clusterOnboardingRouter := r.Router.PathPrefix("/orchestrator/cluster-onboarding").Subrouter()
+	r.clusterOnboardingRouter.InitClusterOnboardingRouter(clusterOnboardingRouter)
+
+	clusterCredentialRouter := r.Router.PathPrefix("/orchestrator/cluster-credential").Subrouter()
+	r.clusterCredentialRouter.InitClusterCredentialRouter(clusterCredentialRouter)
+
+	rightsizingRouter := r.Router.PathPrefix("/orchestrator/rightsizing").Subrouter()
+	r.rightsizingRouter.InitRightsizingRouter(rightsizingRouter)
+
+	eventArchiveRouter := r.Router.PathPrefix("/orchestrator/event-archive").Subrouter()
+	r.eventArchiveRouter.InitEventArchiveRouter(eventArchiveRouter)
+
+	namespaceTemplateRouter := r.Router.PathPrefix("/orchestrator/namespace-template").Subrouter()
+	r.namespaceTemplateRouter.InitNamespaceTemplateRouter(namespaceTemplateRouter)
+
 }
@@ -18,13 +18,18 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"log"
 	"net/http"
 	"time"
 
 	"github.com/devtron-labs/devtron/internal/middleware"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	auditLogBean "github.com/devtron-labs/devtron/pkg/auditLog/bean"
 	"github.com/devtron-labs/devtron/pkg/auth/user"
+	util2 "github.com/devtron-labs/devtron/util"
+	"github.com/gorilla/mux"
 )
 
 type AuditLoggerDTO struct {
@@ -38,12 +43,14 @@ type AuditLoggerDTO struct {
 }
 
 type LoggingMiddlewareImpl struct {
-	userService user.UserService
+	userService     user.UserService
+	auditLogService auditLog.AuditLogService
 }
 
-func NewLoggingMiddlewareImpl(userService user.UserService) *LoggingMiddlewareImpl {
+func NewLoggingMiddlewareImpl(userService user.UserService, auditLogService auditLog.AuditLogService) *LoggingMiddlewareImpl {
 	return &LoggingMiddlewareImpl{
-		userService: userService,
+		userService:     userService,
+		auditLogService: auditLogService,
 	}
 }
 
@@ -51,7 +58,8 @@ type LoggingMiddleware interface {
 	LoggingMiddleware(next http.Handler) http.Handler
 }
 
-// LoggingMiddleware is a middleware function that logs the incoming request.
+// LoggingMiddleware is a middleware function that logs the incoming request. Mutating requests are
+// additionally recorded as audit events.
 func (impl LoggingMiddlewareImpl) LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		d := middleware.NewDelegator(w, nil)
@@ -80,14 +88,52 @@ func (impl LoggingMiddlewareImpl) LoggingMiddleware(next http.Handler) http.Hand
 			RequestPayload: bodyBuffer.Bytes(),
 			RequestMethod:  r.Method,
 		}
+		action, isMutating := auditLog.GetAction(r.Method)
+		var stateChange *auditLogBean.StateChange
+		if isMutating {
+			var ctx context.Context
+			ctx, stateChange = auditLog.WithStateChange(r.Context())
+			r = r.WithContext(ctx)
+		}
+		startTime := time.Now()
 		// Call the next handler in the chain.
 		next.ServeHTTP(d, r)
 
 		auditLogDto.ApiResponseCode = d.Status()
 		LogRequest(auditLogDto)
+		if isMutating {
+			impl.auditLogService.Record(&auditLogBean.AuditEvent{
+				EmailId:      userEmail,
+				Method:       r.Method,
+				Path:         r.URL.Path,
+				Resource:     getRouteTemplate(r),
+				ResourceIds:  mux.Vars(r),
+				Action:       action,
+				Outcome:      auditLog.GetOutcome(d.Status()),
+				StatusCode:   d.Status(),
+				ClientIp:     util2.GetClientIP(r),
+				RequestHash:  auditLog.HashPayload(auditLogDto.RequestPayload),
+				BeforeHash:   stateChange.BeforeHash,
+				AfterHash:    stateChange.AfterHash,
+				DurationInMs: time.Since(startTime).Milliseconds(),
+				CreatedOn:    startTime,
+			})
+		}
 	})
 }
 
+func getRouteTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return r.URL.Path
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return r.URL.Path
+	}
+	return template
+}
+
 func LogRequest(auditLogDto *AuditLoggerDTO) {
 	log.Printf("AUDIT_LOG: requestMethod: %s, urlPath: %s, queryParams: %s, updatedBy: %s, updatedOn: %s, apiResponseCode: %d, requestPayload: %s", auditLogDto.RequestMethod, auditLogDto.UrlPath, auditLogDto.QueryParams, auditLogDto.UserEmail, auditLogDto.UpdatedOn, auditLogDto.ApiResponseCode, auditLogDto.RequestPayload)
 }
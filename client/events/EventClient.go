@@ -93,6 +93,9 @@ type Payload struct {
 	BuildHistoryLink      string               `json:"buildHistoryLink"`
 	MaterialTriggerInfo   *MaterialTriggerInfo `json:"material"`
 	FailureReason         string               `json:"failureReason"`
+	// ProvidedRecipients are notified in addition to the configured recipients, used for approval requests
+	ProvidedRecipients []string `json:"providedRecipients,omitempty"`
+	ImageApprovalLink  string   `json:"imageApprovalLink,omitempty"`
 }
 
 type CiPipelineMaterialResponse struct {
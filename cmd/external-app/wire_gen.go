@@ -45,6 +45,7 @@ import (
 	team2 "github.com/devtron-labs/devtron/api/team"
 	terminal2 "github.com/devtron-labs/devtron/api/terminal"
 	webhookHelm2 "github.com/devtron-labs/devtron/api/webhook/helm"
+	"github.com/devtron-labs/devtron/cel"
 	"github.com/devtron-labs/devtron/client/argocdServer"
 	"github.com/devtron-labs/devtron/client/argocdServer/bean"
 	"github.com/devtron-labs/devtron/client/argocdServer/config"
@@ -77,9 +78,12 @@ import (
 	read6 "github.com/devtron-labs/devtron/pkg/argoApplication/read"
 	config3 "github.com/devtron-labs/devtron/pkg/argoApplication/read/config"
 	"github.com/devtron-labs/devtron/pkg/attributes"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	repository16 "github.com/devtron-labs/devtron/pkg/auditLog/repository"
 	"github.com/devtron-labs/devtron/pkg/auth/authentication"
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
 	"github.com/devtron-labs/devtron/pkg/auth/sso"
+	repository13 "github.com/devtron-labs/devtron/pkg/auth/sso/repository"
 	"github.com/devtron-labs/devtron/pkg/auth/user"
 	"github.com/devtron-labs/devtron/pkg/auth/user/repository"
 	read7 "github.com/devtron-labs/devtron/pkg/build/git/gitMaterial/read"
@@ -94,6 +98,8 @@ import (
 	read2 "github.com/devtron-labs/devtron/pkg/cluster/read"
 	repository3 "github.com/devtron-labs/devtron/pkg/cluster/repository"
 	"github.com/devtron-labs/devtron/pkg/clusterTerminalAccess"
+	"github.com/devtron-labs/devtron/pkg/debugProfile"
+	repository18 "github.com/devtron-labs/devtron/pkg/debugProfile/repository"
 	delete2 "github.com/devtron-labs/devtron/pkg/delete"
 	"github.com/devtron-labs/devtron/pkg/deployment/common"
 	config2 "github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
@@ -106,6 +112,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/k8s/application"
 	"github.com/devtron-labs/devtron/pkg/k8s/capacity"
 	"github.com/devtron-labs/devtron/pkg/k8s/informer"
+	"github.com/devtron-labs/devtron/pkg/k8s/podLogs"
 	"github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs"
 	repository10 "github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs/repository"
 	"github.com/devtron-labs/devtron/pkg/module"
@@ -114,6 +121,9 @@ import (
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/scanTool"
 	repository11 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/scanTool/repository"
+	"github.com/devtron-labs/devtron/pkg/portForward"
+	"github.com/devtron-labs/devtron/pkg/resourceSearch"
+	repository17 "github.com/devtron-labs/devtron/pkg/resourceSearch/repository"
 	"github.com/devtron-labs/devtron/pkg/server"
 	"github.com/devtron-labs/devtron/pkg/server/config"
 	"github.com/devtron-labs/devtron/pkg/server/store"
@@ -122,6 +132,10 @@ import (
 	"github.com/devtron-labs/devtron/pkg/team/read"
 	repository2 "github.com/devtron-labs/devtron/pkg/team/repository"
 	"github.com/devtron-labs/devtron/pkg/terminal"
+	"github.com/devtron-labs/devtron/pkg/terminalPolicy"
+	repository15 "github.com/devtron-labs/devtron/pkg/terminalPolicy/repository"
+	"github.com/devtron-labs/devtron/pkg/terminalRecording"
+	repository14 "github.com/devtron-labs/devtron/pkg/terminalRecording/repository"
 	util3 "github.com/devtron-labs/devtron/pkg/util"
 	"github.com/devtron-labs/devtron/pkg/webhook/helm"
 	util2 "github.com/devtron-labs/devtron/util"
@@ -174,14 +188,14 @@ func InitializeApp() (*App, error) {
 	if err != nil {
 		return nil, err
 	}
-	enforcerImpl, err := casbin.NewEnforcerImpl(syncedEnforcer, casbinSyncedEnforcer, sessionManager, sugaredLogger)
+	userRepositoryImpl := repository.NewUserRepositoryImpl(db, sugaredLogger)
+	enforcerImpl, err := casbin.NewEnforcerImpl(syncedEnforcer, casbinSyncedEnforcer, sessionManager, userRepositoryImpl, sugaredLogger)
 	if err != nil {
 		return nil, err
 	}
 	defaultAuthPolicyRepositoryImpl := repository.NewDefaultAuthPolicyRepositoryImpl(db, sugaredLogger)
 	defaultAuthRoleRepositoryImpl := repository.NewDefaultAuthRoleRepositoryImpl(db, sugaredLogger)
 	userAuthRepositoryImpl := repository.NewUserAuthRepositoryImpl(db, sugaredLogger, defaultAuthPolicyRepositoryImpl, defaultAuthRoleRepositoryImpl)
-	userRepositoryImpl := repository.NewUserRepositoryImpl(db, sugaredLogger)
 	roleGroupRepositoryImpl := repository.NewRoleGroupRepositoryImpl(db, sugaredLogger)
 	rbacPolicyDataRepositoryImpl := repository.NewRbacPolicyDataRepositoryImpl(sugaredLogger, db)
 	rbacRoleDataRepositoryImpl := repository.NewRbacRoleDataRepositoryImpl(sugaredLogger, db)
@@ -192,8 +206,10 @@ func InitializeApp() (*App, error) {
 	}
 	userAuditRepositoryImpl := repository.NewUserAuditRepositoryImpl(db)
 	userAuditServiceImpl := user.NewUserAuditServiceImpl(sugaredLogger, userAuditRepositoryImpl)
-	userServiceImpl := user.NewUserServiceImpl(userAuthRepositoryImpl, sugaredLogger, userRepositoryImpl, roleGroupRepositoryImpl, sessionManager, userCommonServiceImpl, userAuditServiceImpl)
-	ssoLoginRepositoryImpl := sso.NewSSOLoginRepositoryImpl(db, sugaredLogger)
+	userServiceImpl, err := user.NewUserServiceImpl(userAuthRepositoryImpl, sugaredLogger, userRepositoryImpl, roleGroupRepositoryImpl, sessionManager, userCommonServiceImpl, userAuditServiceImpl)
+	if err != nil {
+		return nil, err
+	}
 	k8sRuntimeConfig, err := k8s.GetRuntimeConfig()
 	if err != nil {
 		return nil, err
@@ -204,12 +220,13 @@ func InitializeApp() (*App, error) {
 		return nil, err
 	}
 	selfRegistrationRolesRepositoryImpl := repository.NewSelfRegistrationRolesRepositoryImpl(db, sugaredLogger)
-	userSelfRegistrationServiceImpl := user.NewUserSelfRegistrationServiceImpl(sugaredLogger, selfRegistrationRolesRepositoryImpl, userServiceImpl)
+	ssoLoginRepositoryImpl := repository13.NewSSOLoginRepositoryImpl(db, sugaredLogger)
+	userSelfRegistrationServiceImpl := user.NewUserSelfRegistrationServiceImpl(sugaredLogger, selfRegistrationRolesRepositoryImpl, userServiceImpl, ssoLoginRepositoryImpl)
 	userAuthOidcHelperImpl, err := authentication.NewUserAuthOidcHelperImpl(sugaredLogger, userSelfRegistrationServiceImpl, dexConfig, settings, sessionManager)
 	if err != nil {
 		return nil, err
 	}
-	ssoLoginServiceImpl := sso.NewSSOLoginServiceImpl(sugaredLogger, ssoLoginRepositoryImpl, k8sServiceImpl, environmentVariables, userAuthOidcHelperImpl)
+	ssoLoginServiceImpl := sso.NewSSOLoginServiceImpl(sugaredLogger, ssoLoginRepositoryImpl, k8sServiceImpl, environmentVariables, userAuthOidcHelperImpl, roleGroupRepositoryImpl)
 	ssoLoginRestHandlerImpl := sso2.NewSsoLoginRestHandlerImpl(validate, sugaredLogger, enforcerImpl, userServiceImpl, ssoLoginServiceImpl)
 	ssoLoginRouterImpl := sso2.NewSsoLoginRouterImpl(ssoLoginRestHandlerImpl)
 	teamRepositoryImpl := repository2.NewTeamRepositoryImpl(db)
@@ -222,7 +239,8 @@ func InitializeApp() (*App, error) {
 	k8sInformerFactoryImpl := informer.NewK8sInformerFactoryImpl(sugaredLogger, syncMap, k8sServiceImpl)
 	cronLoggerImpl := cron.NewCronLoggerImpl(sugaredLogger)
 	clusterReadServiceImpl := read2.NewClusterReadServiceImpl(sugaredLogger, clusterRepositoryImpl)
-	clusterServiceImpl, err := cluster.NewClusterServiceImpl(clusterRepositoryImpl, sugaredLogger, k8sServiceImpl, k8sInformerFactoryImpl, userAuthRepositoryImpl, userRepositoryImpl, roleGroupRepositoryImpl, environmentVariables, cronLoggerImpl, clusterReadServiceImpl)
+	clusterConnectionHealthRepositoryImpl := repository3.NewClusterConnectionHealthRepositoryImpl(db, sugaredLogger)
+	clusterServiceImpl, err := cluster.NewClusterServiceImpl(clusterRepositoryImpl, sugaredLogger, k8sServiceImpl, k8sInformerFactoryImpl, userAuthRepositoryImpl, userRepositoryImpl, roleGroupRepositoryImpl, environmentVariables, cronLoggerImpl, clusterReadServiceImpl, clusterConnectionHealthRepositoryImpl)
 	if err != nil {
 		return nil, err
 	}
@@ -332,8 +350,26 @@ func InitializeApp() (*App, error) {
 	k8sCommonServiceImpl := k8s2.NewK8sCommonServiceImpl(sugaredLogger, k8sServiceImpl, argoApplicationConfigServiceImpl, clusterReadServiceImpl)
 	ephemeralContainersRepositoryImpl := repository3.NewEphemeralContainersRepositoryImpl(db, transactionUtilImpl)
 	ephemeralContainerServiceImpl := cluster.NewEphemeralContainerServiceImpl(ephemeralContainersRepositoryImpl, sugaredLogger)
-	terminalSessionHandlerImpl := terminal.NewTerminalSessionHandlerImpl(environmentServiceImpl, sugaredLogger, k8sServiceImpl, ephemeralContainerServiceImpl, argoApplicationConfigServiceImpl, clusterReadServiceImpl)
-	k8sApplicationServiceImpl, err := application.NewK8sApplicationServiceImpl(sugaredLogger, clusterServiceImpl, pumpImpl, helmAppServiceImpl, k8sServiceImpl, acdAuthConfig, k8sResourceHistoryServiceImpl, k8sCommonServiceImpl, terminalSessionHandlerImpl, ephemeralContainerServiceImpl, ephemeralContainersRepositoryImpl, fluxApplicationServiceImpl, clusterReadServiceImpl)
+	terminalSessionRecordingRepositoryImpl := repository14.NewTerminalSessionRecordingRepositoryImpl(db, sugaredLogger)
+	terminalRecordingServiceImpl, err := terminalRecording.NewTerminalRecordingServiceImpl(sugaredLogger, terminalSessionRecordingRepositoryImpl, userRepositoryImpl, cronLoggerImpl)
+	if err != nil {
+		return nil, err
+	}
+	apiTokenRepositoryImpl := apiToken.NewApiTokenRepositoryImpl(db)
+	auditEventRepositoryImpl := repository16.NewAuditEventRepositoryImpl(db, sugaredLogger)
+	auditLogServiceImpl, err := auditLog.NewAuditLogServiceImpl(sugaredLogger, auditEventRepositoryImpl, userRepositoryImpl, apiTokenRepositoryImpl)
+	if err != nil {
+		return nil, err
+	}
+	terminalCommandPolicyRepositoryImpl := repository15.NewTerminalCommandPolicyRepositoryImpl(db, sugaredLogger)
+	terminalPolicyServiceImpl := terminalPolicy.NewTerminalPolicyServiceImpl(sugaredLogger, terminalCommandPolicyRepositoryImpl, environmentRepositoryImpl, clusterReadServiceImpl, auditLogServiceImpl)
+	terminalSessionHandlerImpl := terminal.NewTerminalSessionHandlerImpl(environmentServiceImpl, sugaredLogger, k8sServiceImpl, ephemeralContainerServiceImpl, argoApplicationConfigServiceImpl, clusterReadServiceImpl, terminalRecordingServiceImpl, terminalPolicyServiceImpl)
+	debugProfileRepositoryImpl := repository18.NewDebugProfileRepositoryImpl(db, sugaredLogger)
+	debugProfileServiceImpl, err := debugProfile.NewDebugProfileServiceImpl(sugaredLogger, debugProfileRepositoryImpl, environmentRepositoryImpl, ephemeralContainersRepositoryImpl, k8sCommonServiceImpl, cronLoggerImpl)
+	if err != nil {
+		return nil, err
+	}
+	k8sApplicationServiceImpl, err := application.NewK8sApplicationServiceImpl(sugaredLogger, clusterServiceImpl, pumpImpl, helmAppServiceImpl, k8sServiceImpl, acdAuthConfig, k8sResourceHistoryServiceImpl, k8sCommonServiceImpl, terminalSessionHandlerImpl, ephemeralContainerServiceImpl, ephemeralContainersRepositoryImpl, fluxApplicationServiceImpl, clusterReadServiceImpl, debugProfileServiceImpl)
 	if err != nil {
 		return nil, err
 	}
@@ -344,8 +380,27 @@ func InitializeApp() (*App, error) {
 	environmentRestHandlerImpl := cluster2.NewEnvironmentRestHandlerImpl(environmentServiceImpl, environmentReadServiceImpl, sugaredLogger, userServiceImpl, validate, enforcerImpl, deleteServiceImpl, k8sServiceImpl, k8sCommonServiceImpl)
 	environmentRouterImpl := cluster2.NewEnvironmentRouterImpl(environmentRestHandlerImpl)
 	argoApplicationReadServiceImpl := read6.NewArgoApplicationReadServiceImpl(sugaredLogger, clusterRepositoryImpl, k8sServiceImpl, helmAppClientImpl, helmAppServiceImpl)
-	k8sApplicationRestHandlerImpl := application2.NewK8sApplicationRestHandlerImpl(sugaredLogger, k8sApplicationServiceImpl, pumpImpl, terminalSessionHandlerImpl, enforcerImpl, enforcerUtilHelmImpl, enforcerUtilImpl, helmAppServiceImpl, userServiceImpl, k8sCommonServiceImpl, validate, environmentVariables, fluxApplicationServiceImpl, argoApplicationReadServiceImpl)
-	k8sApplicationRouterImpl := application2.NewK8sApplicationRouterImpl(k8sApplicationRestHandlerImpl)
+	podLogsServiceImpl, err := podLogs.NewPodLogsServiceImpl(sugaredLogger, clusterReadServiceImpl, k8sServiceImpl)
+	if err != nil {
+		return nil, err
+	}
+	k8sApplicationRestHandlerImpl := application2.NewK8sApplicationRestHandlerImpl(sugaredLogger, k8sApplicationServiceImpl, pumpImpl, terminalSessionHandlerImpl, enforcerImpl, enforcerUtilHelmImpl, enforcerUtilImpl, helmAppServiceImpl, userServiceImpl, k8sCommonServiceImpl, validate, environmentVariables, fluxApplicationServiceImpl, argoApplicationReadServiceImpl, podLogsServiceImpl)
+	terminalRecordingRestHandlerImpl := application2.NewTerminalRecordingRestHandlerImpl(sugaredLogger, userServiceImpl, terminalRecordingServiceImpl, enforcerImpl)
+	terminalPolicyRestHandlerImpl := application2.NewTerminalPolicyRestHandlerImpl(sugaredLogger, userServiceImpl, terminalPolicyServiceImpl, enforcerImpl, validate)
+	portForwardServiceImpl, err := portForward.NewPortForwardServiceImpl(sugaredLogger, clusterReadServiceImpl, k8sServiceImpl, auditLogServiceImpl)
+	if err != nil {
+		return nil, err
+	}
+	portForwardRestHandlerImpl := application2.NewPortForwardRestHandlerImpl(sugaredLogger, userServiceImpl, portForwardServiceImpl, k8sApplicationServiceImpl, enforcerImpl, enforcerUtilImpl, validate, environmentVariables)
+	savedResourceQueryRepositoryImpl := repository17.NewSavedResourceQueryRepositoryImpl(db, sugaredLogger)
+	evaluatorServiceImpl := cel.NewCELServiceImpl(sugaredLogger)
+	resourceSearchServiceImpl, err := resourceSearch.NewResourceSearchServiceImpl(sugaredLogger, savedResourceQueryRepositoryImpl, clusterServiceImpl, k8sCommonServiceImpl, k8sServiceImpl, evaluatorServiceImpl)
+	if err != nil {
+		return nil, err
+	}
+	resourceSearchRestHandlerImpl := application2.NewResourceSearchRestHandlerImpl(sugaredLogger, userServiceImpl, resourceSearchServiceImpl, enforcerImpl, enforcerUtilImpl, validate)
+	debugProfileRestHandlerImpl := application2.NewDebugProfileRestHandlerImpl(sugaredLogger, userServiceImpl, debugProfileServiceImpl, enforcerImpl, validate)
+	k8sApplicationRouterImpl := application2.NewK8sApplicationRouterImpl(k8sApplicationRestHandlerImpl, terminalRecordingRestHandlerImpl, terminalPolicyRestHandlerImpl, portForwardRestHandlerImpl, resourceSearchRestHandlerImpl, debugProfileRestHandlerImpl)
 	chartRepositoryRestHandlerImpl := chartRepo2.NewChartRepositoryRestHandlerImpl(sugaredLogger, userServiceImpl, chartRepositoryServiceImpl, enforcerImpl, validate, deleteServiceImpl, attributesServiceImpl)
 	chartRepositoryRouterImpl := chartRepo2.NewChartRepositoryRouterImpl(chartRepositoryRestHandlerImpl)
 	appStoreServiceImpl := service3.NewAppStoreServiceImpl(sugaredLogger, appStoreApplicationVersionRepositoryImpl)
@@ -415,8 +470,10 @@ func InitializeApp() (*App, error) {
 	if err != nil {
 		return nil, err
 	}
-	apiTokenRepositoryImpl := apiToken.NewApiTokenRepositoryImpl(db)
-	apiTokenServiceImpl := apiToken.NewApiTokenServiceImpl(sugaredLogger, apiTokenSecretServiceImpl, userServiceImpl, userAuditServiceImpl, apiTokenRepositoryImpl)
+	apiTokenServiceImpl, err := apiToken.NewApiTokenServiceImpl(sugaredLogger, apiTokenSecretServiceImpl, userServiceImpl, userAuditServiceImpl, apiTokenRepositoryImpl, cronLoggerImpl)
+	if err != nil {
+		return nil, err
+	}
 	apiTokenRestHandlerImpl := apiToken2.NewApiTokenRestHandlerImpl(sugaredLogger, apiTokenServiceImpl, userServiceImpl, enforcerImpl, validate)
 	apiTokenRouterImpl := apiToken2.NewApiTokenRouterImpl(apiTokenRestHandlerImpl)
 	k8sCapacityServiceImpl := capacity.NewK8sCapacityServiceImpl(sugaredLogger, k8sApplicationServiceImpl, k8sServiceImpl, k8sCommonServiceImpl)
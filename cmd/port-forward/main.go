@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// port-forward binds a devtron port-forward session to a local port, tunnelling every
+// accepted connection over the session websocket.
+//
+//	port-forward -url https://devtron.example.com -cluster-id 1 -namespace default -service web -port 80 -local-port 8080
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	sessionPath = "/orchestrator/k8s/port-forward/session"
+	tokenHeader = "token"
+)
+
+type sessionRequest struct {
+	ClusterId   int    `json:"clusterId"`
+	Namespace   string `json:"namespace"`
+	PodName     string `json:"podName,omitempty"`
+	ServiceName string `json:"serviceName,omitempty"`
+	Port        int    `json:"port"`
+}
+
+type session struct {
+	SessionId  string `json:"sessionId"`
+	PodName    string `json:"podName"`
+	TargetPort int    `json:"targetPort"`
+}
+
+type response struct {
+	Code   int             `json:"code"`
+	Status string          `json:"status"`
+	Result json.RawMessage `json:"result"`
+	Errors []struct {
+		UserMessage interface{} `json:"userMessage"`
+	} `json:"errors"`
+}
+
+type client struct {
+	baseUrl string
+	token   string
+}
+
+func main() {
+	baseUrl := flag.String("url", "", "devtron dashboard url")
+	token := flag.String("token", os.Getenv("DEVTRON_TOKEN"), "devtron api token, defaults to $DEVTRON_TOKEN")
+	clusterId := flag.Int("cluster-id", 0, "id of the cluster")
+	namespace := flag.String("namespace", "", "namespace of the pod or service")
+	podName := flag.String("pod", "", "pod to forward to")
+	serviceName := flag.String("service", "", "service to forward to")
+	port := flag.Int("port", 0, "remote port, a service port when -service is set")
+	localPort := flag.Int("local-port", 0, "local port to listen on, defaults to -port")
+	localAddress := flag.String("local-address", "127.0.0.1", "local address to listen on")
+	flag.Parse()
+
+	if len(*baseUrl) == 0 || len(*token) == 0 || *clusterId == 0 || len(*namespace) == 0 || *port == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *localPort == 0 {
+		*localPort = *port
+	}
+	c := &client{baseUrl: strings.TrimSuffix(*baseUrl, "/"), token: *token}
+	s, err := c.createSession(&sessionRequest{
+		ClusterId:   *clusterId,
+		Namespace:   *namespace,
+		PodName:     *podName,
+		ServiceName: *serviceName,
+		Port:        *port,
+	})
+	if err != nil {
+		log.Fatalf("error in creating port-forward session: %v", err)
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort(*localAddress, fmt.Sprint(*localPort)))
+	if err != nil {
+		c.closeSession(s.SessionId)
+		log.Fatalf("error in listening on local port: %v", err)
+	}
+	log.Printf("forwarding %s -> %s/%s:%d", listener.Addr(), *namespace, s.PodName, s.TargetPort)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals
+		listener.Close()
+	}()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			break
+		}
+		go c.forward(s.SessionId, conn)
+	}
+	c.closeSession(s.SessionId)
+}
+
+func (c *client) createSession(request *sessionRequest) (*session, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	s := &session{}
+	err = c.do(http.MethodPost, sessionPath, bytes.NewReader(body), s)
+	return s, err
+}
+
+func (c *client) closeSession(sessionId string) {
+	if err := c.do(http.MethodDelete, sessionPath+"/"+sessionId, nil, nil); err != nil {
+		log.Printf("error in closing port-forward session %s: %v", sessionId, err)
+	}
+}
+
+func (c *client) do(method string, path string, body io.Reader, result interface{}) error {
+	req, err := http.NewRequest(method, c.baseUrl+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(tokenHeader, c.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	res := &response{}
+	if err = json.NewDecoder(resp.Body).Decode(res); err != nil {
+		return fmt.Errorf("unexpected response, status %s", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if len(res.Errors) > 0 {
+			return fmt.Errorf("%s: %v", resp.Status, res.Errors[0].UserMessage)
+		}
+		return fmt.Errorf("unexpected response, status %s", resp.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(res.Result, result)
+}
+
+func (c *client) tunnelUrl(sessionId string) (string, error) {
+	u, err := url.Parse(c.baseUrl + sessionPath + "/" + sessionId + "/tunnel")
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	return u.String(), nil
+}
+
+func (c *client) forward(sessionId string, conn net.Conn) {
+	defer conn.Close()
+	tunnelUrl, err := c.tunnelUrl(sessionId)
+	if err != nil {
+		log.Printf("error in building tunnel url: %v", err)
+		return
+	}
+	header := http.Header{}
+	header.Set(tokenHeader, c.token)
+	ws, _, err := websocket.DefaultDialer.Dial(tunnelUrl, header)
+	if err != nil {
+		log.Printf("error in opening tunnel: %v", err)
+		return
+	}
+	defer ws.Close()
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// closing the local connection unblocks the copy below once the tunnel ends
+		defer conn.Close()
+		for {
+			_, reader, err := ws.NextReader()
+			if err != nil {
+				return
+			}
+			if _, err = io.Copy(conn, reader); err != nil {
+				return
+			}
+		}
+	}()
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			if writeErr := ws.WriteMessage(websocket.BinaryMessage, buf[:n]); writeErr != nil {
+				break
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	wg.Wait()
+}
@@ -92,6 +92,15 @@ const (
 	// feasibility errors
 	OperationPerformError string = "10001"
 	VulnerabilityFound    string = "10002"
+	ApprovalRequired      string = "10003"
+
+	// project guardrail errors
+	ProjectGuardrailMaxAppsExceeded          string = "11001"
+	ProjectGuardrailMaxEnvironmentsExceeded  string = "11002"
+	ProjectGuardrailEnvironmentNotAllowed    string = "11003"
+	ProjectGuardrailChartRefNotAllowed       string = "11004"
+	ProjectGuardrailDockerRegistryNotAllowed string = "11005"
+	ProjectGuardrailMandatoryLabelsMissing   string = "11006"
 )
 
 const (
@@ -117,6 +117,16 @@ var TerminalSessionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 	Help: "duration of each terminal session",
 }, []string{"podName", "namespace", "clusterId"})
 
+var ClusterConnectionHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "cluster_connection_healthy",
+	Help: "1 if the last connectivity check of the cluster succeeded, 0 otherwise",
+}, []string{"clusterName"})
+
+var ClusterConnectionLatency = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "cluster_connection_latency_seconds",
+	Help: "latency of the last connectivity check of the cluster",
+}, []string{"clusterName"})
+
 // prometheusMiddleware implements mux.MiddlewareFunc.
 func PrometheusMiddleware(next http.Handler) http.Handler {
 	//	prometheus.MustRegister(requestCounter)
@@ -142,3 +152,12 @@ func IncTerminalSessionRequestCounter(sessionAction string, isError string) {
 func RecordTerminalSessionDurationMetrics(podName, namespace, clusterId string, sessionDuration float64) {
 	TerminalSessionDuration.WithLabelValues(podName, namespace, clusterId).Observe(sessionDuration)
 }
+
+func RecordClusterConnectionHealthMetrics(clusterName string, healthy bool, latency float64) {
+	healthyValue := 0.0
+	if healthy {
+		healthyValue = 1
+	}
+	ClusterConnectionHealthy.WithLabelValues(clusterName).Set(healthyValue)
+	ClusterConnectionLatency.WithLabelValues(clusterName).Set(latency)
+}
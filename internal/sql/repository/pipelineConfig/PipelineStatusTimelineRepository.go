@@ -30,6 +30,9 @@ type PipelineStatusTimelineRepository interface {
 	UpdateTimelines(timelines []*PipelineStatusTimeline) error
 	UpdateTimelinesWithTxn(timelines []*PipelineStatusTimeline, tx *pg.Tx) error
 	FetchTimelinesByPipelineId(pipelineId int) ([]*PipelineStatusTimeline, error)
+	// FetchTimelinesByPipelineIdAndTimeRange - Gets the exposed timelines of the deployments of a pipeline
+	// with a status time in [from, to), ordered by status time
+	FetchTimelinesByPipelineIdAndTimeRange(pipelineId int, from, to time.Time) ([]*PipelineStatusTimeline, error)
 	// FetchTimelinesByWfrId - Gets the exposed timelines for Helm Applications,
 	// ignoring internalTimelineStatusList in sql query as it is not handled at FE
 	FetchTimelinesByWfrId(wfrId int) ([]*PipelineStatusTimeline, error)
@@ -124,6 +127,23 @@ func (impl *PipelineStatusTimelineRepositoryImpl) FetchTimelinesByPipelineId(pip
 	return timelines, nil
 }
 
+func (impl *PipelineStatusTimelineRepositoryImpl) FetchTimelinesByPipelineIdAndTimeRange(pipelineId int, from, to time.Time) ([]*PipelineStatusTimeline, error) {
+	var timelines []*PipelineStatusTimeline
+	err := impl.dbConnection.Model(&timelines).
+		Join("INNER JOIN cd_workflow_runner wfr ON wfr.id = pipeline_status_timeline.cd_workflow_runner_id").
+		Join("INNER JOIN cd_workflow cw ON cw.id=wfr.cd_workflow_id").
+		Where("cw.pipeline_id = ?", pipelineId).
+		Where("pipeline_status_timeline.status_time >= ?", from).
+		Where("pipeline_status_timeline.status_time < ?", to).
+		Where("pipeline_status_timeline.status NOT IN (?)", pg.In(timelineStatus.InternalTimelineStatusList)).
+		Order("pipeline_status_timeline.status_time ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting timelines by pipelineId and time range", "err", err, "pipelineId", pipelineId, "from", from, "to", to)
+		return nil, err
+	}
+	return timelines, nil
+}
+
 func (impl *PipelineStatusTimelineRepositoryImpl) FetchTimelinesByWfrId(wfrId int) ([]*PipelineStatusTimeline, error) {
 	var timelines []*PipelineStatusTimeline
 	err := impl.dbConnection.Model(&timelines).
@@ -8,6 +8,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	pipelineConfig "github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+
+	time "time"
 )
 
 // PipelineStatusTimelineRepository is an autogenerated mock type for the PipelineStatusTimelineRepository type
@@ -156,6 +158,29 @@ func (_m *PipelineStatusTimelineRepository) FetchTimelinesByPipelineId(pipelineI
 	return r0, r1
 }
 
+// FetchTimelinesByPipelineIdAndTimeRange provides a mock function with given fields: pipelineId, from, to
+func (_m *PipelineStatusTimelineRepository) FetchTimelinesByPipelineIdAndTimeRange(pipelineId int, from time.Time, to time.Time) ([]*pipelineConfig.PipelineStatusTimeline, error) {
+	ret := _m.Called(pipelineId, from, to)
+
+	var r0 []*pipelineConfig.PipelineStatusTimeline
+	if rf, ok := ret.Get(0).(func(int, time.Time, time.Time) []*pipelineConfig.PipelineStatusTimeline); ok {
+		r0 = rf(pipelineId, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*pipelineConfig.PipelineStatusTimeline)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, time.Time, time.Time) error); ok {
+		r1 = rf(pipelineId, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FetchTimelinesByWfrId provides a mock function with given fields: wfrId
 func (_m *PipelineStatusTimelineRepository) FetchTimelinesByWfrId(wfrId int) ([]*pipelineConfig.PipelineStatusTimeline, error) {
 	ret := _m.Called(wfrId)
@@ -16,10 +16,46 @@
 
 package apiToken
 
-import "github.com/golang-jwt/jwt/v4"
+import (
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/golang-jwt/jwt/v4"
+	"time"
+)
 
 type ApiTokenCustomClaims struct {
 	Email   string `json:"email"`
 	Version string `json:"version"`
 	jwt.RegisteredClaims
 }
+
+type ApiTokenConfig struct {
+	CronSchedule            string `env:"API_TOKEN_MAINTENANCE_CRON" envDefault:"*/15 * * * *" description:"Schedule of the job disabling unused api tokens"`
+	InactivityDisableDays   int    `env:"API_TOKEN_INACTIVITY_DISABLE_DAYS" envDefault:"0" description:"Api tokens not used for these many days are disabled, 0 keeps unused tokens enabled"`
+	MaxRotationOverlapHours int    `env:"API_TOKEN_MAX_ROTATION_OVERLAP_HOURS" envDefault:"72" description:"Longest time the previous token stays valid after a rotation"`
+}
+
+// ApiTokenAccessPolicy restricts a token further than the roles of its user, empty fields do not restrict the token
+type ApiTokenAccessPolicy struct {
+	Scopes       []casbin.ApiTokenScope `json:"scopes" validate:"dive"`
+	AllowedCidrs []string               `json:"allowedCidrs" validate:"dive,cidr"`
+}
+
+type ApiTokenAccessDetail struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	ApiTokenAccessPolicy
+	PreviousTokenValidUntil *time.Time `json:"previousTokenValidUntil,omitempty"`
+	LastUsedAt              *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedByIp            string     `json:"lastUsedByIp,omitempty"`
+	DisabledOn              *time.Time `json:"disabledOn,omitempty"`
+}
+
+type RotateApiTokenRequest struct {
+	// OverlapHours is the time the current token stays valid along with the new one
+	OverlapHours int `json:"overlapHours" validate:"min=0"`
+}
+
+type RotateApiTokenResponse struct {
+	Token                   string     `json:"token"`
+	PreviousTokenValidUntil *time.Time `json:"previousTokenValidUntil,omitempty"`
+}
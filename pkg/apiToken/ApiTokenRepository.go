@@ -18,10 +18,12 @@ package apiToken
 
 import (
 	"fmt"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
 	"github.com/devtron-labs/devtron/pkg/auth/user/repository"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
 	"github.com/go-pg/pg/orm"
+	"time"
 )
 
 type ApiToken struct {
@@ -33,7 +35,16 @@ type ApiToken struct {
 	Description  string   `sql:"description, notnull"`
 	ExpireAtInMs int64    `sql:"expire_at_in_ms"`
 	Token        string   `sql:"token, notnull"`
-	User         *repository.UserModel
+	// Scopes and AllowedCidrs restrict the token further than the roles of its user
+	Scopes       []casbin.ApiTokenScope `sql:"scopes"`
+	AllowedCidrs []string               `sql:"allowed_cidrs" pg:",array"`
+	// PreviousVersion stays valid till PreviousVersionValidUntil after a rotation
+	PreviousVersion           int        `sql:"previous_version"`
+	PreviousVersionValidUntil *time.Time `sql:"previous_version_valid_until"`
+	LastUsedAt                *time.Time `sql:"last_used_at"`
+	LastUsedByIp              string     `sql:"last_used_by_ip"`
+	DisabledOn                *time.Time `sql:"disabled_on"`
+	User                      *repository.UserModel
 	sql.AuditLog
 }
 
@@ -44,6 +55,8 @@ type ApiTokenRepository interface {
 	FindActiveById(id int) (*ApiToken, error)
 	FindByName(name string) (*ApiToken, error)
 	UpdateIf(apiToken *ApiToken, previousTokenVersion int) error
+	FindActiveUnusedSince(since time.Time) ([]*ApiToken, error)
+	DisableByIds(ids []int, disabledOn time.Time) error
 }
 
 type ApiTokenRepositoryImpl struct {
@@ -107,3 +120,26 @@ func (impl ApiTokenRepositoryImpl) FindByName(name string) (*ApiToken, error) {
 		Select()
 	return apiToken, err
 }
+
+// FindActiveUnusedSince returns enabled tokens neither used nor updated after since
+func (impl ApiTokenRepositoryImpl) FindActiveUnusedSince(since time.Time) ([]*ApiToken, error) {
+	var apiTokens []*ApiToken
+	err := impl.dbConnection.Model(&apiTokens).
+		Column("api_token.*", "User").
+		Relation("User", func(q *orm.Query) (query *orm.Query, err error) {
+			return q.Where("active IS TRUE"), nil
+		}).
+		Where("api_token.disabled_on IS NULL").
+		Where("COALESCE(api_token.last_used_at, api_token.updated_on) < ?", since).
+		Select()
+	return apiTokens, err
+}
+
+func (impl ApiTokenRepositoryImpl) DisableByIds(ids []int, disabledOn time.Time) error {
+	_, err := impl.dbConnection.Model(&ApiToken{}).
+		Set("disabled_on = ?", disabledOn).
+		Where("id IN (?)", pg.In(ids)).
+		Where("disabled_on IS NULL").
+		Update()
+	return err
+}
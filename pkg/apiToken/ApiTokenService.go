@@ -19,7 +19,12 @@ package apiToken
 import (
 	"errors"
 	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/util"
 	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
@@ -41,6 +46,12 @@ type ApiTokenService interface {
 	UpdateApiToken(apiTokenId int, request *openapi.UpdateApiTokenRequest, updatedBy int32) (*openapi.UpdateApiTokenResponse, error)
 	DeleteApiToken(apiTokenId int, deletedBy int32) (*openapi.ActionResponse, error)
 	GetAllApiTokensForWebhook(projectName string, environmentName string, appName string, auth func(token string, projectObject string, envObject string) bool) ([]*openapi.ApiToken, error)
+	GetApiTokenAccess(apiTokenId int) (*ApiTokenAccessDetail, error)
+	UpdateApiTokenAccessPolicy(apiTokenId int, policy *ApiTokenAccessPolicy, updatedBy int32) (*ApiTokenAccessDetail, error)
+	// RotateApiToken issues a new token, the current token stays valid for the requested overlap
+	RotateApiToken(apiTokenId int, request *RotateApiTokenRequest, updatedBy int32) (*RotateApiTokenResponse, error)
+	// DisableUnusedApiTokens disables tokens not used for the configured number of days
+	DisableUnusedApiTokens()
 }
 
 type ApiTokenServiceImpl struct {
@@ -49,17 +60,33 @@ type ApiTokenServiceImpl struct {
 	userService           user2.UserService
 	userAuditService      user2.UserAuditService
 	apiTokenRepository    ApiTokenRepository
+	config                *ApiTokenConfig
 }
 
 func NewApiTokenServiceImpl(logger *zap.SugaredLogger, apiTokenSecretService ApiTokenSecretService, userService user2.UserService, userAuditService user2.UserAuditService,
-	apiTokenRepository ApiTokenRepository) *ApiTokenServiceImpl {
-	return &ApiTokenServiceImpl{
+	apiTokenRepository ApiTokenRepository, cronLogger *cron2.CronLoggerImpl) (*ApiTokenServiceImpl, error) {
+	config := &ApiTokenConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing api token config", "err", err)
+		return nil, err
+	}
+	impl := &ApiTokenServiceImpl{
 		logger:                logger,
 		apiTokenSecretService: apiTokenSecretService,
 		userService:           userService,
 		userAuditService:      userAuditService,
 		apiTokenRepository:    apiTokenRepository,
+		config:                config,
 	}
+	maintenanceCron := cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	_, err = maintenanceCron.AddFunc(config.CronSchedule, impl.DisableUnusedApiTokens)
+	if err != nil {
+		logger.Errorw("error in adding api token maintenance cron", "schedule", config.CronSchedule, "err", err)
+		return nil, err
+	}
+	maintenanceCron.Start()
+	return impl, nil
 }
 
 var invalidCharsInApiTokenName = regexp.MustCompile("[,\\s]")
@@ -126,12 +153,6 @@ func (impl ApiTokenServiceImpl) GetAllActiveApiTokens() ([]*openapi.ApiToken, er
 	var apiTokens []*openapi.ApiToken
 	for _, apiTokenFromDb := range apiTokensFromDb {
 		userId := apiTokenFromDb.User.Id
-		latestAuditLog, err := impl.userAuditService.GetLatestByUserId(userId)
-		if err != nil {
-			impl.logger.Errorw("error while getting latest audit log", "error", err)
-			return nil, err
-		}
-
 		apiTokenIdI32 := int32(apiTokenFromDb.Id)
 		updatedAtStr := apiTokenFromDb.UpdatedOn.String()
 		apiToken := &openapi.ApiToken{
@@ -144,10 +165,22 @@ func (impl ApiTokenServiceImpl) GetAllActiveApiTokens() ([]*openapi.ApiToken, er
 			Token:          &apiTokenFromDb.Token,
 			UpdatedAt:      &updatedAtStr,
 		}
-		if latestAuditLog != nil {
-			lastUsedAtStr := latestAuditLog.CreatedOn.String()
+		if apiTokenFromDb.LastUsedAt != nil {
+			lastUsedAtStr := apiTokenFromDb.LastUsedAt.String()
 			apiToken.LastUsedAt = &lastUsedAtStr
-			apiToken.LastUsedByIp = &latestAuditLog.ClientIp
+			apiToken.LastUsedByIp = &apiTokenFromDb.LastUsedByIp
+		} else {
+			// tokens not used since their last use is tracked on the token
+			latestAuditLog, err := impl.userAuditService.GetLatestByUserId(userId)
+			if err != nil {
+				impl.logger.Errorw("error while getting latest audit log", "error", err)
+				return nil, err
+			}
+			if latestAuditLog != nil {
+				lastUsedAtStr := latestAuditLog.CreatedOn.String()
+				apiToken.LastUsedAt = &lastUsedAtStr
+				apiToken.LastUsedByIp = &latestAuditLog.ClientIp
+			}
 		}
 		apiTokens = append(apiTokens, apiToken)
 	}
@@ -291,6 +324,9 @@ func (impl ApiTokenServiceImpl) UpdateApiToken(apiTokenId int, request *openapi.
 		}
 		apiToken.Token = token
 		apiToken.Version = tokenVersion
+		// tokens regenerated for a new expiry are not rotations, the previous token is invalidated right away
+		apiToken.PreviousVersion = 0
+		apiToken.PreviousVersionValidUntil = nil
 	}
 
 	// step-3 - update in DB
@@ -353,6 +389,116 @@ func (impl ApiTokenServiceImpl) DeleteApiToken(apiTokenId int, deletedBy int32)
 
 }
 
+func (impl ApiTokenServiceImpl) GetApiTokenAccess(apiTokenId int) (*ApiTokenAccessDetail, error) {
+	apiToken, err := impl.getActiveApiToken(apiTokenId)
+	if err != nil {
+		return nil, err
+	}
+	return toAccessDetail(apiToken, time.Now()), nil
+}
+
+func (impl ApiTokenServiceImpl) UpdateApiTokenAccessPolicy(apiTokenId int, policy *ApiTokenAccessPolicy, updatedBy int32) (*ApiTokenAccessDetail, error) {
+	impl.logger.Infow("Updating API token access policy", "policy", policy, "updatedBy", updatedBy, "apiTokenId", apiTokenId)
+	apiToken, err := impl.getActiveApiToken(apiTokenId)
+	if err != nil {
+		return nil, err
+	}
+	if len(policy.AllowedCidrs) > 0 && isUnversionedToken(apiToken.Token) {
+		errMsg := fmt.Sprintf("api token '%s' was issued without a version and its ip is not checked, rotate it before setting allowed cidrs", apiToken.Name)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	apiToken.Scopes = policy.Scopes
+	apiToken.AllowedCidrs = policy.AllowedCidrs
+	apiToken.UpdatedBy = updatedBy
+	apiToken.UpdatedOn = time.Now()
+	err = impl.apiTokenRepository.UpdateIf(apiToken, apiToken.Version)
+	if err != nil {
+		impl.logger.Errorw("error while updating api-token access policy", "apiTokenId", apiTokenId, "error", err)
+		if err.Error() == TokenVersionMismatch {
+			return nil, fmt.Errorf(ConcurrentTokenUpdateRequest)
+		}
+		return nil, err
+	}
+	return toAccessDetail(apiToken, time.Now()), nil
+}
+
+func (impl ApiTokenServiceImpl) RotateApiToken(apiTokenId int, request *RotateApiTokenRequest, updatedBy int32) (*RotateApiTokenResponse, error) {
+	impl.logger.Infow("Rotating API token", "request", request, "updatedBy", updatedBy, "apiTokenId", apiTokenId)
+	apiToken, err := impl.getActiveApiToken(apiTokenId)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	err = validateRotation(apiToken, request, impl.config.MaxRotationOverlapHours, now)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	previousTokenVersion := apiToken.Version
+	rotateVersion(apiToken, request.OverlapHours, now)
+	token, err := impl.createApiJwtToken(apiToken.User.EmailId, apiToken.Version, apiToken.ExpireAtInMs)
+	if err != nil {
+		return nil, err
+	}
+	apiToken.Token = token
+	apiToken.UpdatedBy = updatedBy
+	apiToken.UpdatedOn = now
+	// update api-token only if `previousTokenVersion` is same as version stored in DB
+	// we are checking this to ensure that two users are not rotating the same token at the same time
+	err = impl.apiTokenRepository.UpdateIf(apiToken, previousTokenVersion)
+	if err != nil {
+		impl.logger.Errorw("error while rotating api-token", "apiTokenId", apiTokenId, "error", err)
+		if err.Error() == TokenVersionMismatch {
+			return nil, fmt.Errorf(ConcurrentTokenUpdateRequest)
+		}
+		return nil, err
+	}
+	return &RotateApiTokenResponse{
+		Token:                   token,
+		PreviousTokenValidUntil: apiToken.PreviousVersionValidUntil,
+	}, nil
+}
+
+func (impl ApiTokenServiceImpl) DisableUnusedApiTokens() {
+	if impl.config.InactivityDisableDays <= 0 {
+		return
+	}
+	now := time.Now()
+	apiTokens, err := impl.apiTokenRepository.FindActiveUnusedSince(now.AddDate(0, 0, -impl.config.InactivityDisableDays))
+	if err != nil {
+		impl.logger.Errorw("error while getting unused api tokens", "error", err)
+		return
+	}
+	var ids []int
+	for _, apiToken := range apiTokens {
+		// tokens of deleted users are already expired
+		if apiToken.User != nil && apiToken.User.Id > 0 {
+			ids = append(ids, apiToken.Id)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	err = impl.apiTokenRepository.DisableByIds(ids, now)
+	if err != nil {
+		impl.logger.Errorw("error while disabling unused api tokens", "ids", ids, "error", err)
+		return
+	}
+	impl.logger.Infow("disabled unused api tokens", "ids", ids, "inactivityDays", impl.config.InactivityDisableDays)
+}
+
+func (impl ApiTokenServiceImpl) getActiveApiToken(apiTokenId int) (*ApiToken, error) {
+	apiToken, err := impl.apiTokenRepository.FindActiveById(apiTokenId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while getting api token by id", "apiTokenId", apiTokenId, "error", err)
+		return nil, err
+	}
+	if apiToken == nil || apiToken.Id == 0 || apiToken.User == nil || apiToken.User.Id == 0 {
+		errMsg := fmt.Sprintf("api-token corresponds to apiTokenId '%d' is not found", apiTokenId)
+		return nil, util.NewApiError(http.StatusNotFound, errMsg, errMsg)
+	}
+	return apiToken, nil
+}
+
 func (impl ApiTokenServiceImpl) createApiJwtToken(email string, tokenVersion int, expireAtInMs int64) (string, error) {
 	registeredClaims, secretByteArr, err := impl.setRegisteredClaims(expireAtInMs)
 	if err != nil {
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apiToken
+
+import (
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"time"
+)
+
+// rotateVersion moves the token to its next version, the current version stays valid for overlapHours. A disabled
+// token is enabled again by the rotation and its current version is not kept valid
+func rotateVersion(apiToken *ApiToken, overlapHours int, now time.Time) {
+	apiToken.PreviousVersion = 0
+	apiToken.PreviousVersionValidUntil = nil
+	if overlapHours > 0 && apiToken.DisabledOn == nil {
+		validUntil := now.Add(time.Duration(overlapHours) * time.Hour)
+		apiToken.PreviousVersion = apiToken.Version
+		apiToken.PreviousVersionValidUntil = &validUntil
+	}
+	apiToken.Version = apiToken.Version + 1
+	apiToken.DisabledOn = nil
+}
+
+func validateRotation(apiToken *ApiToken, request *RotateApiTokenRequest, maxOverlapHours int, now time.Time) error {
+	if request.OverlapHours > maxOverlapHours {
+		return fmt.Errorf("overlap of %d hours is more than the allowed %d hours", request.OverlapHours, maxOverlapHours)
+	}
+	if apiToken.ExpireAtInMs > 0 && apiToken.ExpireAtInMs <= now.UnixMilli() {
+		return fmt.Errorf("api token '%s' has expired, update its expiry instead", apiToken.Name)
+	}
+	return nil
+}
+
+func toAccessDetail(apiToken *ApiToken, now time.Time) *ApiTokenAccessDetail {
+	detail := &ApiTokenAccessDetail{
+		Id:   apiToken.Id,
+		Name: apiToken.Name,
+		ApiTokenAccessPolicy: ApiTokenAccessPolicy{
+			Scopes:       apiToken.Scopes,
+			AllowedCidrs: apiToken.AllowedCidrs,
+		},
+		LastUsedAt:   apiToken.LastUsedAt,
+		LastUsedByIp: apiToken.LastUsedByIp,
+		DisabledOn:   apiToken.DisabledOn,
+	}
+	if apiToken.PreviousVersionValidUntil != nil && now.Before(*apiToken.PreviousVersionValidUntil) {
+		detail.PreviousTokenValidUntil = apiToken.PreviousVersionValidUntil
+	}
+	return detail
+}
+
+// isUnversionedToken tells if the token was issued before tokens carried a version, such tokens are not checked
+// against their allowed cidrs until they are rotated
+func isUnversionedToken(token string) bool {
+	claims := &ApiTokenCustomClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(token, claims)
+	return err != nil || len(claims.Version) == 0
+}
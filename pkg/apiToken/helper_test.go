@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apiToken
+
+import (
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRotateVersion(t *testing.T) {
+	now := time.Now()
+	apiToken := &ApiToken{Version: 2}
+	rotateVersion(apiToken, 4, now)
+	assert.Equal(t, 3, apiToken.Version)
+	assert.Equal(t, 2, apiToken.PreviousVersion)
+	assert.Equal(t, now.Add(4*time.Hour), *apiToken.PreviousVersionValidUntil)
+
+	rotateVersion(apiToken, 0, now)
+	assert.Equal(t, 4, apiToken.Version)
+	assert.Equal(t, 0, apiToken.PreviousVersion)
+	assert.Nil(t, apiToken.PreviousVersionValidUntil)
+
+	// a disabled token is enabled by the rotation without keeping the unused token valid
+	apiToken = &ApiToken{Version: 5, DisabledOn: &now}
+	rotateVersion(apiToken, 4, now)
+	assert.Equal(t, 6, apiToken.Version)
+	assert.Equal(t, 0, apiToken.PreviousVersion)
+	assert.Nil(t, apiToken.DisabledOn)
+}
+
+func TestValidateRotation(t *testing.T) {
+	now := time.Now()
+	assert.NoError(t, validateRotation(&ApiToken{}, &RotateApiTokenRequest{OverlapHours: 24}, 72, now))
+	assert.Error(t, validateRotation(&ApiToken{}, &RotateApiTokenRequest{OverlapHours: 96}, 72, now))
+	assert.Error(t, validateRotation(&ApiToken{ExpireAtInMs: now.Add(-time.Minute).UnixMilli()}, &RotateApiTokenRequest{}, 72, now))
+}
+
+func TestIsUnversionedToken(t *testing.T) {
+	sign := func(claims jwt.Claims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+		assert.NoError(t, err)
+		return token
+	}
+	assert.False(t, isUnversionedToken(sign(&ApiTokenCustomClaims{Email: "API-TOKEN:ci", Version: "2"})))
+	assert.True(t, isUnversionedToken(sign(&ApiTokenCustomClaims{Email: "API-TOKEN:ci"})))
+	assert.True(t, isUnversionedToken("not-a-token"))
+}
@@ -35,6 +35,7 @@ import (
 type PipelineStatusTimelineService interface {
 	SaveTimeline(timeline *pipelineConfig.PipelineStatusTimeline, tx *pg.Tx) error
 	FetchTimelines(appId, envId, wfrId int, showTimeline bool) (*PipelineTimelineDetailDto, error)
+	FetchTimelinesForPipeline(pipelineId int, from, to time.Time) ([]*PipelineStatusTimelineDto, error)
 	FetchTimelinesForAppStore(installedAppId, envId, installedAppVersionHistoryId int, showTimeline bool) (*PipelineTimelineDetailDto, error)
 	NewDevtronAppPipelineStatusTimelineDbObject(cdWorkflowRunnerId int, timelineStatus timelineStatus.TimelineStatus, timelineDescription string, userId int32) *pipelineConfig.PipelineStatusTimeline
 	NewHelmAppDeploymentStatusTimelineDbObject(installedAppVersionHistoryId int, timelineStatus timelineStatus.TimelineStatus, timelineDescription string, userId int32) *pipelineConfig.PipelineStatusTimeline
@@ -291,6 +292,25 @@ func (impl *PipelineStatusTimelineServiceImpl) FetchTimelines(appId, envId, wfrI
 	return timelineDetail, nil
 }
 
+func (impl *PipelineStatusTimelineServiceImpl) FetchTimelinesForPipeline(pipelineId int, from, to time.Time) ([]*PipelineStatusTimelineDto, error) {
+	timelines, err := impl.pipelineStatusTimelineRepository.FetchTimelinesByPipelineIdAndTimeRange(pipelineId, from, to)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting timelines by pipelineId and time range", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	timelineDtos := make([]*PipelineStatusTimelineDto, 0, len(timelines))
+	for _, timeline := range timelines {
+		timelineDtos = append(timelineDtos, &PipelineStatusTimelineDto{
+			Id:                 timeline.Id,
+			CdWorkflowRunnerId: timeline.CdWorkflowRunnerId,
+			Status:             timeline.Status,
+			StatusTime:         timeline.StatusTime,
+			StatusDetail:       timeline.StatusDetail,
+		})
+	}
+	return timelineDtos, nil
+}
+
 func (impl *PipelineStatusTimelineServiceImpl) FetchTimelinesForAppStore(installedAppId, envId, installedAppVersionHistoryId int, showTimeline bool) (*PipelineTimelineDetailDto, error) {
 	var deploymentStartedOn time.Time
 	var deploymentFinishedOn time.Time
@@ -37,6 +37,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	bean3 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
 	"strings"
@@ -44,6 +45,9 @@ import (
 
 type AppCloneService interface {
 	CloneApp(createReq *bean.CreateAppDTO, context context.Context) (*bean.CreateAppDTO, error)
+	// CloneCdPipelineToEnvironment creates a manual cd pipeline in the target environment of the same app,
+	// copying deployment template, configmap and secret overrides of the ref pipeline's environment
+	CloneCdPipelineToEnvironment(req *CdPipelineEnvCloneRequest, ctx context.Context) (*bean.CdPipelines, error)
 }
 type AppCloneServiceImpl struct {
 	logger                  *zap.SugaredLogger
@@ -61,6 +65,7 @@ type AppCloneServiceImpl struct {
 	pipelineRepository      pipelineConfig.PipelineRepository
 	ciPipelineConfigService pipeline.CiPipelineConfigService
 	gitOpsConfigReadService config.GitOpsConfigReadService
+	projectGuardrailService projectGuardrail.ProjectGuardrailService
 }
 
 func NewAppCloneServiceImpl(logger *zap.SugaredLogger,
@@ -75,7 +80,8 @@ func NewAppCloneServiceImpl(logger *zap.SugaredLogger,
 	appRepository app2.AppRepository, ciPipelineRepository pipelineConfig.CiPipelineRepository,
 	pipelineRepository pipelineConfig.PipelineRepository,
 	ciPipelineConfigService pipeline.CiPipelineConfigService,
-	gitOpsConfigReadService config.GitOpsConfigReadService) *AppCloneServiceImpl {
+	gitOpsConfigReadService config.GitOpsConfigReadService,
+	projectGuardrailService projectGuardrail.ProjectGuardrailService) *AppCloneServiceImpl {
 	return &AppCloneServiceImpl{
 		logger:                  logger,
 		pipelineBuilder:         pipelineBuilder,
@@ -92,6 +98,7 @@ func NewAppCloneServiceImpl(logger *zap.SugaredLogger,
 		pipelineRepository:      pipelineRepository,
 		ciPipelineConfigService: ciPipelineConfigService,
 		gitOpsConfigReadService: gitOpsConfigReadService,
+		projectGuardrailService: projectGuardrailService,
 	}
 }
 
@@ -132,6 +139,11 @@ func (impl *AppCloneServiceImpl) CloneApp(createReq *bean.CreateAppDTO, context
 		}
 		return nil, err
 	}
+	err = impl.projectGuardrailService.ValidateAppClone(createReq.TemplateId, createReq.TeamId)
+	if err != nil {
+		impl.logger.Errorw("error in validating project guardrail on app clone", "templateId", createReq.TemplateId, "teamId", createReq.TeamId, "err", err)
+		return nil, err
+	}
 	//create new app
 	cloneReq := &CloneRequest{
 		RefAppId:    createReq.TemplateId,
@@ -393,41 +405,49 @@ func (impl *AppCloneServiceImpl) CreateEnvCm(ctx context.Context, oldAppId, newA
 	}
 	for _, refEnv := range refEnvs {
 		impl.logger.Debugw("cloning cfg for env", "env", refEnv)
-		refCm, err := impl.configMapService.CMEnvironmentFetch(oldAppId, refEnv.EnvironmentId)
-		if err != nil {
-			return nil, err
-		}
-		thisCm, err := impl.configMapService.CMEnvironmentFetch(newAppId, refEnv.EnvironmentId)
+		err = impl.cloneEnvCm(oldAppId, newAppId, refEnv.EnvironmentId, refEnv.EnvironmentId, userId)
 		if err != nil {
 			return nil, err
 		}
+	}
+	return nil, nil
+}
 
-		var refEnvCm []*bean3.ConfigData
-		for _, refCmData := range refCm.ConfigData {
-			if !refCmData.Global || refCmData.Data != nil {
-				refEnvCm = append(refEnvCm, refCmData)
-			}
+func (impl *AppCloneServiceImpl) cloneEnvCm(oldAppId, newAppId, refEnvId, targetEnvId int, userId int32) error {
+	refCm, err := impl.configMapService.CMEnvironmentFetch(oldAppId, refEnvId)
+	if err != nil {
+		return err
+	}
+	thisCm, err := impl.configMapService.CMEnvironmentFetch(newAppId, targetEnvId)
+	if err != nil {
+		return err
+	}
+
+	var refEnvCm []*bean3.ConfigData
+	for _, refCmData := range refCm.ConfigData {
+		if !refCmData.Global || refCmData.Data != nil {
+			refEnvCm = append(refEnvCm, refCmData)
 		}
-		if len(refEnvCm) == 0 {
-			impl.logger.Debug("no env cm")
-			continue
+	}
+	if len(refEnvCm) == 0 {
+		impl.logger.Debug("no env cm")
+		return nil
+	}
+	cfgDatas := impl.configDataClone(refEnvCm)
+	for _, cfgData := range cfgDatas {
+		newCm := &bean3.ConfigDataRequest{
+			AppId:         newAppId,
+			EnvironmentId: targetEnvId,
+			ConfigData:    []*bean3.ConfigData{cfgData},
+			UserId:        userId,
+			Id:            thisCm.Id,
 		}
-		cfgDatas := impl.configDataClone(refEnvCm)
-		for _, cfgData := range cfgDatas {
-			newCm := &bean3.ConfigDataRequest{
-				AppId:         newAppId,
-				EnvironmentId: refEnv.EnvironmentId,
-				ConfigData:    []*bean3.ConfigData{cfgData},
-				UserId:        userId,
-				Id:            thisCm.Id,
-			}
-			thisCm, err = impl.configMapService.CMEnvironmentAddUpdate(newCm)
-			if err != nil {
-				return nil, err
-			}
+		thisCm, err = impl.configMapService.CMEnvironmentAddUpdate(newCm)
+		if err != nil {
+			return err
 		}
 	}
-	return nil, nil
+	return nil
 }
 
 func (impl *AppCloneServiceImpl) CreateEnvSecret(ctx context.Context, oldAppId, newAppId int, userId int32) (interface{}, error) {
@@ -437,43 +457,51 @@ func (impl *AppCloneServiceImpl) CreateEnvSecret(ctx context.Context, oldAppId,
 	}
 	for _, refEnv := range refEnvs {
 		impl.logger.Debugw("cloning cfg for env", "env", refEnv)
-		refCm, err := impl.configMapService.CSEnvironmentFetch(oldAppId, refEnv.EnvironmentId)
-		if err != nil {
-			return nil, err
-		}
-		thisCm, err := impl.configMapService.CSEnvironmentFetch(newAppId, refEnv.EnvironmentId)
+		err = impl.cloneEnvSecret(oldAppId, newAppId, refEnv.EnvironmentId, refEnv.EnvironmentId, userId)
 		if err != nil {
 			return nil, err
 		}
+	}
+	return nil, nil
+}
 
-		var refEnvCm []*bean3.ConfigData
-		for _, refCmData := range refCm.ConfigData {
-			if !refCmData.Global || refCmData.Data != nil {
-				refEnvCm = append(refEnvCm, refCmData)
-			}
+func (impl *AppCloneServiceImpl) cloneEnvSecret(oldAppId, newAppId, refEnvId, targetEnvId int, userId int32) error {
+	refCm, err := impl.configMapService.CSEnvironmentFetch(oldAppId, refEnvId)
+	if err != nil {
+		return err
+	}
+	thisCm, err := impl.configMapService.CSEnvironmentFetch(newAppId, targetEnvId)
+	if err != nil {
+		return err
+	}
+
+	var refEnvCm []*bean3.ConfigData
+	for _, refCmData := range refCm.ConfigData {
+		if !refCmData.Global || refCmData.Data != nil {
+			refEnvCm = append(refEnvCm, refCmData)
 		}
-		if len(refEnvCm) == 0 {
-			impl.logger.Debug("no env cm")
-			continue
+	}
+	if len(refEnvCm) == 0 {
+		impl.logger.Debug("no env cm")
+		return nil
+	}
+	cfgDatas := impl.configDataClone(refEnvCm)
+	for _, cfgData := range cfgDatas {
+		var configData []*bean3.ConfigData
+		configData = append(configData, cfgData)
+		newCm := &bean3.ConfigDataRequest{
+			AppId:         newAppId,
+			EnvironmentId: targetEnvId,
+			ConfigData:    configData,
+			UserId:        userId,
+			Id:            thisCm.Id,
 		}
-		cfgDatas := impl.configDataClone(refEnvCm)
-		for _, cfgData := range cfgDatas {
-			var configData []*bean3.ConfigData
-			configData = append(configData, cfgData)
-			newCm := &bean3.ConfigDataRequest{
-				AppId:         newAppId,
-				EnvironmentId: refEnv.EnvironmentId,
-				ConfigData:    configData,
-				UserId:        userId,
-				Id:            thisCm.Id,
-			}
-			thisCm, err = impl.configMapService.CSEnvironmentAddUpdate(newCm)
-			if err != nil {
-				return nil, err
-			}
+		thisCm, err = impl.configMapService.CSEnvironmentAddUpdate(newCm)
+		if err != nil {
+			return err
 		}
 	}
-	return nil, nil
+	return nil
 }
 
 func (impl *AppCloneServiceImpl) createEnvOverride(oldAppId, newAppId int, userId int32, ctx context.Context) (interface{}, error) {
@@ -981,31 +1009,10 @@ func (impl *AppCloneServiceImpl) CreateCdPipeline(req *cloneCdPipelineRequest, c
 	if strings.HasPrefix(pipelineName, req.refAppName) {
 		pipelineName = strings.Replace(pipelineName, req.refAppName+"-", "", 1)
 	}
-	// by default all deployment types are allowed
-	AllowedDeploymentAppTypes := map[string]bool{
-		util.PIPELINE_DEPLOYMENT_TYPE_ACD:  true,
-		util.PIPELINE_DEPLOYMENT_TYPE_HELM: true,
-	}
-	DeploymentAppConfigForEnvironment, err := impl.attributesService.GetDeploymentEnforcementConfig(refCdPipeline.EnvironmentId)
+	deploymentAppType, err := impl.getDeploymentAppTypeForClone(refCdPipeline, refCdPipeline.EnvironmentId)
 	if err != nil {
-		impl.logger.Errorw("error in fetching deployment config for environment", "err", err)
-	}
-	for deploymentType, allowed := range DeploymentAppConfigForEnvironment {
-		AllowedDeploymentAppTypes[deploymentType] = allowed
-	}
-	gitOpsConfigurationStatus, err := impl.gitOpsConfigReadService.IsGitOpsConfigured()
-	if err != nil {
-		impl.logger.Errorw("error in checking if gitOps configured", "err", err)
 		return nil, err
 	}
-	var deploymentAppType string
-	if AllowedDeploymentAppTypes[util.PIPELINE_DEPLOYMENT_TYPE_ACD] && AllowedDeploymentAppTypes[util.PIPELINE_DEPLOYMENT_TYPE_HELM] {
-		deploymentAppType = refCdPipeline.DeploymentAppType
-	} else if AllowedDeploymentAppTypes[util.PIPELINE_DEPLOYMENT_TYPE_ACD] && gitOpsConfigurationStatus.IsGitOpsConfigured {
-		deploymentAppType = util.PIPELINE_DEPLOYMENT_TYPE_ACD
-	} else if AllowedDeploymentAppTypes[util.PIPELINE_DEPLOYMENT_TYPE_HELM] {
-		deploymentAppType = util.PIPELINE_DEPLOYMENT_TYPE_HELM
-	}
 
 	cdPipeline := &bean.CDPipelineConfigObject{
 		Id:                            0,
@@ -1047,3 +1054,146 @@ func (impl *AppCloneServiceImpl) CreateCdPipeline(req *cloneCdPipelineRequest, c
 	return cdPipelineRes, err
 
 }
+
+type CdPipelineEnvCloneRequest struct {
+	RefCdPipelineId     int
+	TargetEnvironmentId int
+	TargetNamespace     string
+	PipelineName        string
+	UserId              int32
+}
+
+func (impl *AppCloneServiceImpl) CloneCdPipelineToEnvironment(req *CdPipelineEnvCloneRequest, ctx context.Context) (*bean.CdPipelines, error) {
+	refPipeline, err := impl.pipelineRepository.FindById(req.RefCdPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ref cd pipeline", "refCdPipelineId", req.RefCdPipelineId, "err", err)
+		return nil, err
+	}
+	appId := refPipeline.AppId
+	// overrides are copied before creating the pipeline so that they are not replaced by app level defaults
+	err = impl.cloneEnvOverride(appId, refPipeline.EnvironmentId, req.TargetEnvironmentId, req.TargetNamespace, req.UserId, ctx)
+	if err != nil {
+		impl.logger.Errorw("error in cloning env override", "refCdPipelineId", req.RefCdPipelineId, "targetEnvId", req.TargetEnvironmentId, "err", err)
+		return nil, err
+	}
+	err = impl.cloneEnvCm(appId, appId, refPipeline.EnvironmentId, req.TargetEnvironmentId, req.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in cloning env configmaps", "refCdPipelineId", req.RefCdPipelineId, "targetEnvId", req.TargetEnvironmentId, "err", err)
+		return nil, err
+	}
+	err = impl.cloneEnvSecret(appId, appId, refPipeline.EnvironmentId, req.TargetEnvironmentId, req.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in cloning env secrets", "refCdPipelineId", req.RefCdPipelineId, "targetEnvId", req.TargetEnvironmentId, "err", err)
+		return nil, err
+	}
+	refPipelines, err := impl.pipelineBuilder.GetCdPipelinesForApp(appId)
+	if err != nil {
+		return nil, err
+	}
+	var refCdPipeline *bean.CDPipelineConfigObject
+	for _, cdPipelineObj := range refPipelines.Pipelines {
+		if cdPipelineObj.Id == req.RefCdPipelineId {
+			refCdPipeline = cdPipelineObj
+			break
+		}
+	}
+	if refCdPipeline == nil {
+		return nil, fmt.Errorf("no cd pipeline found")
+	}
+	deploymentAppType, err := impl.getDeploymentAppTypeForClone(refCdPipeline, req.TargetEnvironmentId)
+	if err != nil {
+		return nil, err
+	}
+	// cloned pipeline is always attached directly to the ci pipeline and triggered explicitly,
+	// so that builds of other branches are never auto deployed in it
+	cdPipeline := &bean.CDPipelineConfigObject{
+		EnvironmentId:                 req.TargetEnvironmentId,
+		CiPipelineId:                  refCdPipeline.CiPipelineId,
+		TriggerType:                   pipelineConfig.TRIGGER_TYPE_MANUAL,
+		Name:                          req.PipelineName,
+		Strategies:                    refCdPipeline.Strategies,
+		Namespace:                     req.TargetNamespace,
+		AppWorkflowId:                 refCdPipeline.AppWorkflowId,
+		DeploymentTemplate:            refCdPipeline.DeploymentTemplate,
+		PreStage:                      refCdPipeline.PreStage,
+		PostStage:                     refCdPipeline.PostStage,
+		PreStageConfigMapSecretNames:  refCdPipeline.PreStageConfigMapSecretNames,
+		PostStageConfigMapSecretNames: refCdPipeline.PostStageConfigMapSecretNames,
+		RunPostStageInEnv:             refCdPipeline.RunPostStageInEnv,
+		RunPreStageInEnv:              refCdPipeline.RunPreStageInEnv,
+		DeploymentAppType:             deploymentAppType,
+		PreDeployStage:                refCdPipeline.PreDeployStage,
+		PostDeployStage:               refCdPipeline.PostDeployStage,
+		ParentPipelineType:            bean4.CI_PIPELINE_TYPE,
+		IsDigestEnforcedForPipeline:   refCdPipeline.IsDigestEnforcedForPipeline,
+	}
+	cdPipelineReq := &bean.CdPipelines{
+		Pipelines: []*bean.CDPipelineConfigObject{cdPipeline},
+		AppId:     appId,
+		UserId:    req.UserId,
+	}
+	return impl.pipelineBuilder.CreateCdPipelines(cdPipelineReq, ctx)
+}
+
+func (impl *AppCloneServiceImpl) cloneEnvOverride(appId, refEnvId, targetEnvId int, targetNamespace string, userId int32, ctx context.Context) error {
+	chartRefRes, err := impl.chartService.ChartRefAutocompleteForAppOrEnv(appId, refEnvId)
+	if err != nil {
+		return err
+	}
+	refEnvProperties, err := impl.propertiesConfigService.GetEnvironmentProperties(appId, refEnvId, chartRefRes.LatestEnvChartRef)
+	if err != nil {
+		return err
+	}
+	if !refEnvProperties.IsOverride {
+		impl.logger.Debugw("no env override", "env", refEnvId)
+		return nil
+	}
+	envPropertiesReq := &bean3.EnvironmentProperties{
+		EnvOverrideValues: refEnvProperties.EnvironmentConfig.EnvOverrideValues,
+		Status:            refEnvProperties.EnvironmentConfig.Status,
+		ManualReviewed:    refEnvProperties.EnvironmentConfig.ManualReviewed,
+		Active:            refEnvProperties.EnvironmentConfig.Active,
+		Namespace:         targetNamespace,
+		EnvironmentId:     targetEnvId,
+		Latest:            refEnvProperties.EnvironmentConfig.Latest,
+		UserId:            userId,
+		AppMetrics:        refEnvProperties.EnvironmentConfig.AppMetrics,
+		ChartRefId:        refEnvProperties.EnvironmentConfig.ChartRefId,
+		IsOverride:        refEnvProperties.EnvironmentConfig.IsOverride,
+		IsBasicViewLocked: refEnvProperties.EnvironmentConfig.IsBasicViewLocked,
+		CurrentViewEditor: refEnvProperties.EnvironmentConfig.CurrentViewEditor,
+	}
+	_, err = impl.propertiesConfigService.CreateEnvironmentProperties(appId, envPropertiesReq)
+	return err
+}
+
+// getDeploymentAppTypeForClone keeps the deployment app type of the ref pipeline unless the environment the pipeline
+// is cloned into enforces otherwise
+func (impl *AppCloneServiceImpl) getDeploymentAppTypeForClone(refCdPipeline *bean.CDPipelineConfigObject, environmentId int) (string, error) {
+	// by default all deployment types are allowed
+	AllowedDeploymentAppTypes := map[string]bool{
+		util.PIPELINE_DEPLOYMENT_TYPE_ACD:  true,
+		util.PIPELINE_DEPLOYMENT_TYPE_HELM: true,
+	}
+	DeploymentAppConfigForEnvironment, err := impl.attributesService.GetDeploymentEnforcementConfig(environmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment config for environment", "err", err)
+	}
+	for deploymentType, allowed := range DeploymentAppConfigForEnvironment {
+		AllowedDeploymentAppTypes[deploymentType] = allowed
+	}
+	gitOpsConfigurationStatus, err := impl.gitOpsConfigReadService.IsGitOpsConfigured()
+	if err != nil {
+		impl.logger.Errorw("error in checking if gitOps configured", "err", err)
+		return "", err
+	}
+	var deploymentAppType string
+	if AllowedDeploymentAppTypes[util.PIPELINE_DEPLOYMENT_TYPE_ACD] && AllowedDeploymentAppTypes[util.PIPELINE_DEPLOYMENT_TYPE_HELM] {
+		deploymentAppType = refCdPipeline.DeploymentAppType
+	} else if AllowedDeploymentAppTypes[util.PIPELINE_DEPLOYMENT_TYPE_ACD] && gitOpsConfigurationStatus.IsGitOpsConfigured {
+		deploymentAppType = util.PIPELINE_DEPLOYMENT_TYPE_ACD
+	} else if AllowedDeploymentAppTypes[util.PIPELINE_DEPLOYMENT_TYPE_HELM] {
+		deploymentAppType = util.PIPELINE_DEPLOYMENT_TYPE_HELM
+	}
+	return deploymentAppType, nil
+}
@@ -0,0 +1,1420 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appSnapshot
+
+import (
+	"context"
+	"fmt"
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	app2 "github.com/devtron-labs/devtron/internal/sql/repository/app"
+	appWorkflow2 "github.com/devtron-labs/devtron/internal/sql/repository/appWorkflow"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/sql/repository/helper"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/app"
+	"github.com/devtron-labs/devtron/pkg/appSnapshot/bean"
+	"github.com/devtron-labs/devtron/pkg/appWorkflow"
+	bean4 "github.com/devtron-labs/devtron/pkg/appWorkflow/bean"
+	"github.com/devtron-labs/devtron/pkg/attributes"
+	bean3 "github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/build/git/gitProvider/read"
+	buildBean "github.com/devtron-labs/devtron/pkg/build/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/chart"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	read2 "github.com/devtron-labs/devtron/pkg/team/read"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+const (
+	materialStage = "MATERIAL"
+	templateStage = "TEMPLATE"
+	chartStage    = "CHART"
+	webhookParent = "WEBHOOK"
+)
+
+type AppSnapshotService interface {
+	// Export returns the snapshot of a devtron app, secret values are masked
+	Export(ctx context.Context, appId int) (*bean.AppSnapshot, error)
+	// Plan lists what importing the snapshot would create and update, without changing anything
+	Plan(ctx context.Context, snapshot *bean.AppSnapshot) (*bean.ImportPlan, error)
+	// Import creates the app of the snapshot, or updates it to match the snapshot. The project guardrail and the
+	// chart schemas are checked before anything is written, items are then applied in the order of the plan and the
+	// import stops at the first failure, importing again resumes as applied items have no change
+	Import(ctx context.Context, snapshot *bean.AppSnapshot, userId int32) (*bean.ImportPlan, error)
+	// GetAppId returns the id of the active app with the given name, 0 when there is none
+	GetAppId(appName string) (int, error)
+	// GetTeamId returns the id of the project with the given name
+	GetTeamId(projectName string) (int, error)
+}
+
+type AppSnapshotServiceImpl struct {
+	logger                              *zap.SugaredLogger
+	pipelineBuilder                     pipeline.PipelineBuilder
+	chartService                        chart.ChartService
+	chartRefService                     chartRef.ChartRefService
+	configMapService                    pipeline.ConfigMapService
+	propertiesConfigService             pipeline.PropertiesConfigService
+	pipelineStageService                pipeline.PipelineStageService
+	ciPipelineConfigService             pipeline.CiPipelineConfigService
+	appWorkflowService                  appWorkflow.AppWorkflowService
+	appListingService                   app.AppListingService
+	appCrudOperationService             app.AppCrudOperationService
+	attributesService                   attributes.AttributesService
+	gitOpsConfigReadService             config.GitOpsConfigReadService
+	gitProviderReadService              read.GitProviderReadService
+	teamReadService                     read2.TeamReadService
+	appRepository                       app2.AppRepository
+	appLabelRepository                  pipelineConfig.AppLabelRepository
+	pipelineRepository                  pipelineConfig.PipelineRepository
+	environmentRepository               repository.EnvironmentRepository
+	dockerArtifactStoreRepository       dockerRegistryRepository.DockerArtifactStoreRepository
+	projectGuardrailService             projectGuardrail.ProjectGuardrailService
+	deploymentTemplateValidationService deploymentTemplate.DeploymentTemplateValidationService
+}
+
+func NewAppSnapshotServiceImpl(logger *zap.SugaredLogger,
+	pipelineBuilder pipeline.PipelineBuilder,
+	chartService chart.ChartService,
+	chartRefService chartRef.ChartRefService,
+	configMapService pipeline.ConfigMapService,
+	propertiesConfigService pipeline.PropertiesConfigService,
+	pipelineStageService pipeline.PipelineStageService,
+	ciPipelineConfigService pipeline.CiPipelineConfigService,
+	appWorkflowService appWorkflow.AppWorkflowService,
+	appListingService app.AppListingService,
+	appCrudOperationService app.AppCrudOperationService,
+	attributesService attributes.AttributesService,
+	gitOpsConfigReadService config.GitOpsConfigReadService,
+	gitProviderReadService read.GitProviderReadService,
+	teamReadService read2.TeamReadService,
+	appRepository app2.AppRepository,
+	appLabelRepository pipelineConfig.AppLabelRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	environmentRepository repository.EnvironmentRepository,
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository,
+	projectGuardrailService projectGuardrail.ProjectGuardrailService,
+	deploymentTemplateValidationService deploymentTemplate.DeploymentTemplateValidationService) *AppSnapshotServiceImpl {
+	return &AppSnapshotServiceImpl{
+		logger:                              logger,
+		pipelineBuilder:                     pipelineBuilder,
+		chartService:                        chartService,
+		chartRefService:                     chartRefService,
+		configMapService:                    configMapService,
+		propertiesConfigService:             propertiesConfigService,
+		pipelineStageService:                pipelineStageService,
+		ciPipelineConfigService:             ciPipelineConfigService,
+		appWorkflowService:                  appWorkflowService,
+		appListingService:                   appListingService,
+		appCrudOperationService:             appCrudOperationService,
+		attributesService:                   attributesService,
+		gitOpsConfigReadService:             gitOpsConfigReadService,
+		gitProviderReadService:              gitProviderReadService,
+		teamReadService:                     teamReadService,
+		appRepository:                       appRepository,
+		appLabelRepository:                  appLabelRepository,
+		pipelineRepository:                  pipelineRepository,
+		environmentRepository:               environmentRepository,
+		dockerArtifactStoreRepository:       dockerArtifactStoreRepository,
+		projectGuardrailService:             projectGuardrailService,
+		deploymentTemplateValidationService: deploymentTemplateValidationService,
+	}
+}
+
+// snapshotReferences holds the ids of the entities a snapshot refers by name on this devtron instance
+type snapshotReferences struct {
+	teamId         int
+	gitProviderIds map[string]int
+	chartRefIds    map[string]int
+	environments   map[string]*repository.Environment
+}
+
+func (impl *AppSnapshotServiceImpl) GetAppId(appName string) (int, error) {
+	app, err := impl.appRepository.FindActiveByName(appName)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching app by name", "appName", appName, "err", err)
+		return 0, err
+	}
+	if util.IsErrNoRows(err) || app == nil {
+		return 0, nil
+	}
+	return app.Id, nil
+}
+
+func (impl *AppSnapshotServiceImpl) GetTeamId(projectName string) (int, error) {
+	team, err := impl.teamReadService.FindByTeamName(projectName)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching project", "project", projectName, "err", err)
+		return 0, err
+	}
+	if util.IsErrNoRows(err) || team == nil || team.Id == 0 {
+		return 0, badRequest(fmt.Sprintf("project %s not found", projectName))
+	}
+	return team.Id, nil
+}
+
+func (impl *AppSnapshotServiceImpl) Export(ctx context.Context, appId int) (*bean.AppSnapshot, error) {
+	app, err := impl.appRepository.FindById(appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching app", "appId", appId, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) || app == nil || !app.Active {
+		return nil, util.NewApiError(http.StatusNotFound, "app not found", "app not found")
+	}
+	if app.AppType != helper.CustomApp {
+		return nil, badRequest("only devtron apps can be exported")
+	}
+	return impl.getSnapshot(ctx, app, true)
+}
+
+func (impl *AppSnapshotServiceImpl) Plan(ctx context.Context, snapshot *bean.AppSnapshot) (*bean.ImportPlan, error) {
+	plan, _, err := impl.plan(ctx, snapshot)
+	return plan, err
+}
+
+func (impl *AppSnapshotServiceImpl) Import(ctx context.Context, snapshot *bean.AppSnapshot, userId int32) (*bean.ImportPlan, error) {
+	plan, refs, err := impl.plan(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Summary.Unsupported > 0 {
+		return nil, badRequest(fmt.Sprintf("snapshot has %d changes that can not be imported, check the plan", plan.Summary.Unsupported))
+	}
+	actions := getPlanActions(plan)
+	err = impl.validateImport(ctx, snapshot, plan.AppId, refs, actions)
+	if err != nil {
+		impl.logger.Errorw("error in validating app import", "appName", snapshot.Metadata.Name, "err", err)
+		return nil, err
+	}
+	appId, err := impl.applyApp(plan.AppId, snapshot, refs, actions, userId)
+	if err != nil {
+		impl.logger.Errorw("error in importing app", "appName", snapshot.Metadata.Name, "err", err)
+		return nil, err
+	}
+	plan.AppId = appId
+	materialIds, err := impl.applyGitMaterials(appId, snapshot.Spec.GitMaterials, refs, actions, userId)
+	if err != nil {
+		impl.logger.Errorw("error in importing git materials", "appId", appId, "err", err)
+		return nil, err
+	}
+	err = impl.applyBuildConfig(appId, snapshot, materialIds, actions, userId)
+	if err != nil {
+		impl.logger.Errorw("error in importing build configuration", "appId", appId, "err", err)
+		return nil, err
+	}
+	err = impl.applyDeploymentTemplate(ctx, appId, snapshot.Spec.DeploymentTemplate, refs, actions, userId)
+	if err != nil {
+		impl.logger.Errorw("error in importing deployment template", "appId", appId, "err", err)
+		return nil, err
+	}
+	err = impl.applyGlobalConfigData(appId, snapshot.Spec, actions, userId)
+	if err != nil {
+		impl.logger.Errorw("error in importing configmaps and secrets", "appId", appId, "err", err)
+		return nil, err
+	}
+	for _, env := range snapshot.Spec.Environments {
+		err = impl.applyEnvironment(ctx, appId, env, refs, actions, userId)
+		if err != nil {
+			impl.logger.Errorw("error in importing environment overrides", "appId", appId, "env", env.Name, "err", err)
+			return nil, err
+		}
+	}
+	err = impl.applyWorkflows(ctx, appId, snapshot.Spec, materialIds, refs, actions, userId)
+	if err != nil {
+		impl.logger.Errorw("error in importing workflows", "appId", appId, "err", err)
+		return nil, err
+	}
+	return plan, nil
+}
+
+// validateImport runs the checks of the apis behind the import steps before anything is written, so that a snapshot
+// rejected by the project guardrail or by a chart schema does not leave a partially imported app behind
+func (impl *AppSnapshotServiceImpl) validateImport(ctx context.Context, snapshot *bean.AppSnapshot, appId int, refs *snapshotReferences, actions map[string]bean.PlanAction) error {
+	spec := snapshot.Spec
+	err := impl.projectGuardrailService.ValidateAppImport(appId, refs.teamId, getLabelKeys(snapshot.Metadata.Labels),
+		getNewCdPipelineEnvironmentIds(spec, refs.environments, actions), getImportedChartRefIds(spec, refs.chartRefIds, actions),
+		getImportedDockerRegistries(spec, actions))
+	if err != nil {
+		return err
+	}
+	if template := spec.DeploymentTemplate; template != nil && isApplicable(actions[planItemKey(bean.ItemKindDeploymentTemplate, "", "", "")]) {
+		err = impl.validateDeploymentTemplate(ctx, template, refs, resourceQualifiers.Scope{AppId: appId})
+		if err != nil {
+			return err
+		}
+	}
+	for _, envSpec := range spec.Environments {
+		template := envSpec.DeploymentTemplate
+		if template == nil || !isApplicable(actions[planItemKey(bean.ItemKindDeploymentTemplate, envSpec.Name, "", "")]) {
+			continue
+		}
+		env := refs.environments[envSpec.Name]
+		err = impl.validateDeploymentTemplate(ctx, template, refs, resourceQualifiers.Scope{AppId: appId, EnvId: env.Id, ClusterId: env.ClusterId})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *AppSnapshotServiceImpl) validateDeploymentTemplate(ctx context.Context, template *bean.DeploymentTemplateSpec, refs *snapshotReferences, scope resourceQualifiers.Scope) error {
+	chartRefId := refs.chartRefIds[chartKey(template.ChartName, template.ChartVersion)]
+	valid, err := impl.deploymentTemplateValidationService.DeploymentTemplateValidate(ctx, template.Values, chartRefId, scope)
+	if !valid {
+		impl.logger.Errorw("deployment template of snapshot is invalid", "chartRefId", chartRefId, "scope", scope, "err", err)
+		return badRequest(fmt.Sprintf("deployment template of chart %s version %s is invalid: %v", template.ChartName, template.ChartVersion, err))
+	}
+	return nil
+}
+
+func (impl *AppSnapshotServiceImpl) plan(ctx context.Context, snapshot *bean.AppSnapshot) (*bean.ImportPlan, *snapshotReferences, error) {
+	err := validateSnapshot(snapshot)
+	if err != nil {
+		return nil, nil, err
+	}
+	refs, err := impl.resolveReferences(snapshot)
+	if err != nil {
+		return nil, nil, err
+	}
+	var current *bean.AppSnapshot
+	appId, err := impl.GetAppId(snapshot.Metadata.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if appId > 0 {
+		app, err := impl.appRepository.FindById(appId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching app", "appId", appId, "err", err)
+			return nil, nil, err
+		}
+		if app.AppType != helper.CustomApp {
+			return nil, nil, badRequest(fmt.Sprintf("app %s already exists and is not a devtron app", app.AppName))
+		}
+		current, err = impl.getSnapshot(ctx, app, false)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	plan, err := buildPlan(current, snapshot)
+	if err != nil {
+		impl.logger.Errorw("error in building import plan", "appName", snapshot.Metadata.Name, "err", err)
+		return nil, nil, err
+	}
+	plan.AppId = appId
+	return plan, refs, nil
+}
+
+func (impl *AppSnapshotServiceImpl) resolveReferences(snapshot *bean.AppSnapshot) (*snapshotReferences, error) {
+	refs := &snapshotReferences{
+		gitProviderIds: make(map[string]int),
+		chartRefIds:    make(map[string]int),
+		environments:   make(map[string]*repository.Environment),
+	}
+	teamId, err := impl.GetTeamId(snapshot.Metadata.Project)
+	if err != nil {
+		return nil, err
+	}
+	refs.teamId = teamId
+
+	spec := snapshot.Spec
+	if len(spec.GitMaterials) > 0 {
+		gitProviders, err := impl.gitProviderReadService.GetAll()
+		if err != nil {
+			impl.logger.Errorw("error in fetching git providers", "err", err)
+			return nil, err
+		}
+		for _, gitProvider := range gitProviders {
+			refs.gitProviderIds[gitProvider.Name] = gitProvider.Id
+		}
+		for _, material := range spec.GitMaterials {
+			if _, ok := refs.gitProviderIds[material.GitProvider]; !ok {
+				return nil, badRequest(fmt.Sprintf("git account %s not found", material.GitProvider))
+			}
+		}
+	}
+
+	registries := make([]string, 0)
+	if spec.BuildConfig != nil {
+		registries = append(registries, spec.BuildConfig.DockerRegistry)
+	}
+	templates := make([]*bean.DeploymentTemplateSpec, 0)
+	if spec.DeploymentTemplate != nil {
+		templates = append(templates, spec.DeploymentTemplate)
+	}
+	environmentNames := make([]string, 0)
+	for _, env := range spec.Environments {
+		environmentNames = append(environmentNames, env.Name)
+		if env.DeploymentTemplate != nil {
+			templates = append(templates, env.DeploymentTemplate)
+		}
+	}
+	for _, wf := range spec.Workflows {
+		if wf.CiPipeline != nil && wf.CiPipeline.BuildConfigOverride != nil {
+			registries = append(registries, wf.CiPipeline.BuildConfigOverride.DockerRegistry)
+		}
+		for _, cd := range wf.CdPipelines {
+			environmentNames = append(environmentNames, cd.Environment)
+		}
+	}
+	for _, registry := range registries {
+		_, err = impl.dockerArtifactStoreRepository.FindOne(registry)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching container registry", "registry", registry, "err", err)
+			return nil, err
+		}
+		if util.IsErrNoRows(err) {
+			return nil, badRequest(fmt.Sprintf("container registry %s not found", registry))
+		}
+	}
+	for _, template := range templates {
+		key := chartKey(template.ChartName, template.ChartVersion)
+		if _, ok := refs.chartRefIds[key]; ok {
+			continue
+		}
+		chartRefDto, err := impl.chartRefService.FindByVersionAndName(template.ChartVersion, template.ChartName)
+		if err != nil && !util.IsErrNoRows(err) {
+			return nil, err
+		}
+		if util.IsErrNoRows(err) || chartRefDto == nil || chartRefDto.Id == 0 {
+			return nil, badRequest(fmt.Sprintf("chart %s version %s not found", template.ChartName, template.ChartVersion))
+		}
+		refs.chartRefIds[key] = chartRefDto.Id
+	}
+	for _, environmentName := range environmentNames {
+		if _, ok := refs.environments[environmentName]; ok {
+			continue
+		}
+		env, err := impl.environmentRepository.FindByName(environmentName)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching environment", "env", environmentName, "err", err)
+			return nil, err
+		}
+		if util.IsErrNoRows(err) || env == nil || env.Id == 0 {
+			return nil, badRequest(fmt.Sprintf("environment %s not found", environmentName))
+		}
+		refs.environments[environmentName] = env
+	}
+	return refs, nil
+}
+
+// getSnapshot reads the configuration of the app, linked and job build pipelines are left out along with their workflows
+func (impl *AppSnapshotServiceImpl) getSnapshot(ctx context.Context, app *app2.App, maskSecrets bool) (*bean.AppSnapshot, error) {
+	team, err := impl.teamReadService.FindOne(app.TeamId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching project of app", "appId", app.Id, "err", err)
+		return nil, err
+	}
+	appLabels, err := impl.appLabelRepository.FindAllByAppId(app.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching app labels", "appId", app.Id, "err", err)
+		return nil, err
+	}
+	labels := make([]*bean3.Label, 0, len(appLabels))
+	for _, appLabel := range appLabels {
+		labels = append(labels, &bean3.Label{Key: appLabel.Key, Value: appLabel.Value, Propagate: appLabel.Propagate})
+	}
+	snapshot := &bean.AppSnapshot{
+		ApiVersion: bean.SnapshotApiVersion,
+		Kind:       bean.SnapshotKind,
+		Metadata: &bean.SnapshotMetadata{
+			Name:        app.AppName,
+			Project:     team.Name,
+			Description: app.Description,
+			Labels:      labels,
+		},
+		Spec: &bean.SnapshotSpec{},
+	}
+	appStageStatus, err := impl.appListingService.FetchAppStageStatus(app.Id, int(helper.CustomApp))
+	if err != nil {
+		impl.logger.Errorw("error in fetching app stage status", "appId", app.Id, "err", err)
+		return nil, err
+	}
+	stages := make(map[string]bool, len(appStageStatus))
+	for _, stageStatus := range appStageStatus {
+		stages[stageStatus.StageName] = stageStatus.Status
+	}
+	if !stages[materialStage] {
+		return snapshot, nil
+	}
+	checkoutPaths, err := impl.exportGitMaterials(app.Id, snapshot.Spec)
+	if err != nil {
+		return nil, err
+	}
+	ciPipelines := make(map[int]*bean3.CiPipeline)
+	if stages[templateStage] {
+		ciConfig, err := impl.pipelineBuilder.GetCiPipeline(app.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching build configuration", "appId", app.Id, "err", err)
+			return nil, err
+		}
+		snapshot.Spec.BuildConfig = toBuildConfigSpec(ciConfig.DockerRegistry, ciConfig.DockerRepository, ciConfig.CiBuildConfig, checkoutPaths)
+		for _, ciPipeline := range ciConfig.CiPipelines {
+			ciPipelines[ciPipeline.Id] = ciPipeline
+		}
+	}
+	if stages[chartStage] {
+		snapshot.Spec.DeploymentTemplate, err = impl.exportDeploymentTemplate(app.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	configMaps, err := impl.configMapService.CMGlobalFetch(app.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching configmaps", "appId", app.Id, "err", err)
+		return nil, err
+	}
+	snapshot.Spec.ConfigMaps, err = toConfigDataSpecs(configMaps.ConfigData, false, false)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := impl.configMapService.CSGlobalFetch(app.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching secrets", "appId", app.Id, "err", err)
+		return nil, err
+	}
+	snapshot.Spec.Secrets, err = toConfigDataSpecs(secrets.ConfigData, false, maskSecrets)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Spec.Environments, err = impl.exportEnvironments(ctx, app.Id, maskSecrets)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Spec.Workflows, err = impl.exportWorkflows(app.Id, ciPipelines, checkoutPaths)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func (impl *AppSnapshotServiceImpl) exportGitMaterials(appId int, spec *bean.SnapshotSpec) (map[int]string, error) {
+	appDetail, err := impl.pipelineBuilder.GetApp(appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching git materials", "appId", appId, "err", err)
+		return nil, err
+	}
+	gitProviders, err := impl.gitProviderReadService.GetAll()
+	if err != nil {
+		impl.logger.Errorw("error in fetching git providers", "err", err)
+		return nil, err
+	}
+	gitProviderNames := make(map[int]string, len(gitProviders))
+	for _, gitProvider := range gitProviders {
+		gitProviderNames[gitProvider.Id] = gitProvider.Name
+	}
+	checkoutPaths := make(map[int]string, len(appDetail.Material))
+	for _, material := range appDetail.Material {
+		checkoutPaths[material.Id] = material.CheckoutPath
+		spec.GitMaterials = append(spec.GitMaterials, &bean.GitMaterialSpec{
+			CheckoutPath:    material.CheckoutPath,
+			Url:             material.Url,
+			GitProvider:     gitProviderNames[material.GitProviderId],
+			FetchSubmodules: material.FetchSubmodules,
+			FilterPattern:   material.FilterPattern,
+		})
+	}
+	return checkoutPaths, nil
+}
+
+func toBuildConfigSpec(dockerRegistry, dockerRepository string, ciBuildConfig *buildBean.CiBuildConfigBean, checkoutPaths map[int]string) *bean.BuildConfigSpec {
+	buildConfig := &bean.BuildConfigSpec{
+		DockerRegistry:   dockerRegistry,
+		DockerRepository: dockerRepository,
+	}
+	if ciBuildConfig != nil {
+		buildConfig.GitMaterialCheckoutPath = checkoutPaths[ciBuildConfig.GitMaterialId]
+		buildConfig.BuildContextCheckoutPath = checkoutPaths[ciBuildConfig.BuildContextGitMaterialId]
+		buildConfig.UseRootBuildContext = ciBuildConfig.UseRootBuildContext
+		buildConfig.CiBuildType = ciBuildConfig.CiBuildType
+		buildConfig.DockerBuildConfig = ciBuildConfig.DockerBuildConfig
+		buildConfig.BuildPackConfig = ciBuildConfig.BuildPackConfig
+	}
+	return buildConfig
+}
+
+func toCiBuildConfig(buildConfig *bean.BuildConfigSpec, materialIds map[string]int, defaultMaterialId int) *buildBean.CiBuildConfigBean {
+	gitMaterialId := materialIds[buildConfig.GitMaterialCheckoutPath]
+	if gitMaterialId == 0 {
+		gitMaterialId = defaultMaterialId
+	}
+	buildContextGitMaterialId := materialIds[buildConfig.BuildContextCheckoutPath]
+	if buildContextGitMaterialId == 0 {
+		buildContextGitMaterialId = gitMaterialId
+	}
+	return &buildBean.CiBuildConfigBean{
+		GitMaterialId:             gitMaterialId,
+		BuildContextGitMaterialId: buildContextGitMaterialId,
+		UseRootBuildContext:       buildConfig.UseRootBuildContext,
+		CiBuildType:               buildConfig.CiBuildType,
+		DockerBuildConfig:         buildConfig.DockerBuildConfig,
+		BuildPackConfig:           buildConfig.BuildPackConfig,
+	}
+}
+
+func (impl *AppSnapshotServiceImpl) exportDeploymentTemplate(appId int) (*bean.DeploymentTemplateSpec, error) {
+	template, err := impl.chartService.FindLatestChartForAppByAppId(appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment template", "appId", appId, "err", err)
+		return nil, err
+	}
+	chartRefDto, err := impl.chartRefService.FindById(template.ChartRefId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching chart of deployment template", "chartRefId", template.ChartRefId, "err", err)
+		return nil, err
+	}
+	return &bean.DeploymentTemplateSpec{
+		ChartName:         chartRefDto.Name,
+		ChartVersion:      chartRefDto.Version,
+		Values:            template.DefaultAppOverride,
+		IsBasicViewLocked: template.IsBasicViewLocked,
+		CurrentViewEditor: template.CurrentViewEditor,
+	}, nil
+}
+
+func (impl *AppSnapshotServiceImpl) exportEnvironments(ctx context.Context, appId int, maskSecrets bool) ([]*bean.EnvironmentSpec, error) {
+	environments, err := impl.appListingService.FetchOtherEnvironment(ctx, appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environments of app", "appId", appId, "err", err)
+		return nil, err
+	}
+	envSpecs := make([]*bean.EnvironmentSpec, 0, len(environments))
+	for _, env := range environments {
+		envSpec := &bean.EnvironmentSpec{Name: env.EnvironmentName}
+		chartRefResponse, err := impl.chartService.ChartRefAutocompleteForAppOrEnv(appId, env.EnvironmentId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching chart of environment", "appId", appId, "envId", env.EnvironmentId, "err", err)
+			return nil, err
+		}
+		properties, err := impl.propertiesConfigService.GetEnvironmentProperties(appId, env.EnvironmentId, chartRefResponse.LatestEnvChartRef)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment override", "appId", appId, "envId", env.EnvironmentId, "err", err)
+			return nil, err
+		}
+		if properties.IsOverride {
+			chartRefDto, err := impl.chartRefService.FindById(properties.EnvironmentConfig.ChartRefId)
+			if err != nil {
+				impl.logger.Errorw("error in fetching chart of environment override", "chartRefId", properties.EnvironmentConfig.ChartRefId, "err", err)
+				return nil, err
+			}
+			envSpec.DeploymentTemplate = &bean.DeploymentTemplateSpec{
+				ChartName:         chartRefDto.Name,
+				ChartVersion:      chartRefDto.Version,
+				Values:            properties.EnvironmentConfig.EnvOverrideValues,
+				MergeStrategy:     properties.EnvironmentConfig.MergeStrategy,
+				IsBasicViewLocked: properties.EnvironmentConfig.IsBasicViewLocked,
+				CurrentViewEditor: properties.EnvironmentConfig.CurrentViewEditor,
+			}
+		}
+		configMaps, err := impl.configMapService.CMEnvironmentFetch(appId, env.EnvironmentId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment configmaps", "appId", appId, "envId", env.EnvironmentId, "err", err)
+			return nil, err
+		}
+		envSpec.ConfigMaps, err = toConfigDataSpecs(configMaps.ConfigData, true, false)
+		if err != nil {
+			return nil, err
+		}
+		secrets, err := impl.configMapService.CSEnvironmentFetch(appId, env.EnvironmentId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment secrets", "appId", appId, "envId", env.EnvironmentId, "err", err)
+			return nil, err
+		}
+		envSpec.Secrets, err = toConfigDataSpecs(secrets.ConfigData, true, maskSecrets)
+		if err != nil {
+			return nil, err
+		}
+		if envSpec.DeploymentTemplate == nil && len(envSpec.ConfigMaps) == 0 && len(envSpec.Secrets) == 0 {
+			continue
+		}
+		envSpecs = append(envSpecs, envSpec)
+	}
+	return envSpecs, nil
+}
+
+// toConfigDataSpecs converts saved configmaps or secrets, inherited global entries are skipped for an environment
+func toConfigDataSpecs(configData []*pipelineBean.ConfigData, environmentOnly, maskSecrets bool) ([]*bean.ConfigDataSpec, error) {
+	specs := make([]*bean.ConfigDataSpec, 0, len(configData))
+	for _, data := range configData {
+		if environmentOnly && data.Global && data.Data == nil {
+			continue
+		}
+		spec := &bean.ConfigDataSpec{
+			Name:               data.Name,
+			Type:               data.Type,
+			External:           data.External,
+			ExternalSecretType: data.ExternalSecretType,
+			MountPath:          data.MountPath,
+			SubPath:            data.SubPath,
+			FilePermission:     data.FilePermission,
+			MergeStrategy:      data.MergeStrategy,
+			Data:               data.Data,
+			ExternalSecret:     data.ExternalSecret,
+			ESOSubPath:         data.ESOSubPath,
+			RoleARN:            data.RoleARN,
+		}
+		if data.IsESOExternalSecretType() {
+			esoSecretData := data.ESOSecretData
+			spec.ESOSecretData = &esoSecretData
+		}
+		if maskSecrets {
+			masked, err := maskSecretData(data.Data)
+			if err != nil {
+				return nil, err
+			}
+			spec.Data = masked
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func toConfigData(spec *bean.ConfigDataSpec, global bool) *pipelineBean.ConfigData {
+	data := &pipelineBean.ConfigData{
+		Name:               spec.Name,
+		Type:               spec.Type,
+		External:           spec.External,
+		ExternalSecretType: spec.ExternalSecretType,
+		MountPath:          spec.MountPath,
+		SubPath:            spec.SubPath,
+		FilePermission:     spec.FilePermission,
+		MergeStrategy:      spec.MergeStrategy,
+		Data:               spec.Data,
+		Global:             global,
+		ExternalSecret:     spec.ExternalSecret,
+		ESOSubPath:         spec.ESOSubPath,
+		RoleARN:            spec.RoleARN,
+	}
+	if spec.ESOSecretData != nil {
+		data.ESOSecretData = *spec.ESOSecretData
+	}
+	return data
+}
+
+func (impl *AppSnapshotServiceImpl) exportWorkflows(appId int, ciPipelines map[int]*bean3.CiPipeline, checkoutPaths map[int]string) ([]*bean.WorkflowSpec, error) {
+	workflows, err := impl.appWorkflowService.FindAppWorkflows(appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching workflows", "appId", appId, "err", err)
+		return nil, err
+	}
+	cdPipelines := make(map[int]*bean3.CDPipelineConfigObject)
+	if len(workflows) > 0 {
+		cdPipelineConfig, err := impl.pipelineBuilder.GetCdPipelinesForApp(appId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching deployment pipelines", "appId", appId, "err", err)
+			return nil, err
+		}
+		if cdPipelineConfig != nil {
+			for _, cdPipeline := range cdPipelineConfig.Pipelines {
+				cdPipelines[cdPipeline.Id] = cdPipeline
+			}
+		}
+	}
+	workflowSpecs := make([]*bean.WorkflowSpec, 0, len(workflows))
+	for _, wf := range workflows {
+		wfSpec := &bean.WorkflowSpec{Name: wf.Name}
+		supported := true
+		for _, mapping := range appWorkflow.LevelWiseSort(wf.AppWorkflowMappingDto) {
+			switch mapping.Type {
+			case appWorkflow2.WEBHOOK:
+				wfSpec.Webhook = true
+			case appWorkflow2.CIPIPELINE:
+				ciPipeline, ok := ciPipelines[mapping.ComponentId]
+				if !ok || !isExportableCiPipeline(ciPipeline) {
+					supported = false
+					break
+				}
+				wfSpec.CiPipeline, err = impl.exportCiPipeline(ciPipeline, checkoutPaths)
+				if err != nil {
+					return nil, err
+				}
+			case appWorkflow2.CDPIPELINE:
+				cdPipeline, ok := cdPipelines[mapping.ComponentId]
+				if !ok {
+					continue
+				}
+				cdSpec, err := impl.exportCdPipeline(cdPipeline, cdPipelines)
+				if err != nil {
+					return nil, err
+				}
+				wfSpec.CdPipelines = append(wfSpec.CdPipelines, cdSpec)
+			}
+		}
+		if !supported {
+			impl.logger.Infow("skipping workflow of linked or job build pipeline in app snapshot", "appId", appId, "workflow", wf.Name)
+			continue
+		}
+		workflowSpecs = append(workflowSpecs, wfSpec)
+	}
+	return workflowSpecs, nil
+}
+
+func isExportableCiPipeline(ciPipeline *bean3.CiPipeline) bool {
+	if ciPipeline.ParentCiPipeline != 0 || ciPipeline.IsExternal {
+		return false
+	}
+	switch ciPipeline.PipelineType {
+	case buildBean.LINKED, buildBean.LINKED_CD, buildBean.CI_JOB:
+		return false
+	}
+	return true
+}
+
+func (impl *AppSnapshotServiceImpl) exportCiPipeline(ciPipeline *bean3.CiPipeline, checkoutPaths map[int]string) (*bean.CiPipelineSpec, error) {
+	preBuild, postBuild, err := impl.pipelineStageService.GetCiPipelineStageDataDeepCopy(ciPipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching build pipeline stages", "ciPipelineId", ciPipeline.Id, "err", err)
+		return nil, err
+	}
+	ciSpec := &bean.CiPipelineSpec{
+		Name:        ciPipeline.Name,
+		IsManual:    ciPipeline.IsManual,
+		ScanEnabled: ciPipeline.ScanEnabled,
+		DockerArgs:  ciPipeline.DockerArgs,
+		PreBuild:    normalizeStage(preBuild),
+		PostBuild:   normalizeStage(postBuild),
+	}
+	for _, material := range ciPipeline.CiMaterial {
+		materialSpec := &bean.CiMaterialSpec{CheckoutPath: checkoutPaths[material.GitMaterialId]}
+		if material.Source != nil {
+			materialSpec.Type = material.Source.Type
+			materialSpec.Value = material.Source.Value
+			materialSpec.Regex = material.Source.Regex
+		}
+		ciSpec.Materials = append(ciSpec.Materials, materialSpec)
+	}
+	if ciPipeline.IsDockerConfigOverridden {
+		override := ciPipeline.DockerConfigOverride
+		ciSpec.BuildConfigOverride = toBuildConfigSpec(override.DockerRegistry, override.DockerRepository, override.CiBuildConfig, checkoutPaths)
+	}
+	return ciSpec, nil
+}
+
+func (impl *AppSnapshotServiceImpl) exportCdPipeline(cdPipeline *bean3.CDPipelineConfigObject, cdPipelines map[int]*bean3.CDPipelineConfigObject) (*bean.CdPipelineSpec, error) {
+	dbPipeline, err := impl.pipelineRepository.FindById(cdPipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment pipeline", "cdPipelineId", cdPipeline.Id, "err", err)
+		return nil, err
+	}
+	preDeploy, postDeploy, err := impl.pipelineStageService.GetCdPipelineStageDataDeepCopy(dbPipeline)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment pipeline stages", "cdPipelineId", cdPipeline.Id, "err", err)
+		return nil, err
+	}
+	cdSpec := &bean.CdPipelineSpec{
+		Name:                          cdPipeline.Name,
+		Environment:                   cdPipeline.EnvironmentName,
+		Namespace:                     cdPipeline.Namespace,
+		TriggerType:                   cdPipeline.TriggerType,
+		DeploymentAppType:             cdPipeline.DeploymentAppType,
+		Strategies:                    cdPipeline.Strategies,
+		PreDeploy:                     normalizeStage(preDeploy),
+		PostDeploy:                    normalizeStage(postDeploy),
+		PreStageConfigMapSecretNames:  cdPipeline.PreStageConfigMapSecretNames,
+		PostStageConfigMapSecretNames: cdPipeline.PostStageConfigMapSecretNames,
+		RunPreStageInEnv:              cdPipeline.RunPreStageInEnv,
+		RunPostStageInEnv:             cdPipeline.RunPostStageInEnv,
+	}
+	if cdPipeline.ParentPipelineType == bean4.CD_PIPELINE_TYPE {
+		if parent, ok := cdPipelines[cdPipeline.ParentPipelineId]; ok {
+			cdSpec.ParentEnvironment = parent.EnvironmentName
+		}
+	}
+	return cdSpec, nil
+}
+
+func (impl *AppSnapshotServiceImpl) applyApp(appId int, snapshot *bean.AppSnapshot, refs *snapshotReferences, actions map[string]bean.PlanAction, userId int32) (int, error) {
+	metadata := snapshot.Metadata
+	switch actions[planItemKey(bean.ItemKindApp, "", "", metadata.Name)] {
+	case bean.PlanActionCreate:
+		app, err := impl.pipelineBuilder.CreateApp(&bean3.CreateAppDTO{
+			AppName:     metadata.Name,
+			Description: metadata.Description,
+			TeamId:      refs.teamId,
+			AppLabels:   metadata.Labels,
+			AppType:     helper.CustomApp,
+			UserId:      userId,
+		})
+		if err != nil {
+			return 0, err
+		}
+		return app.Id, nil
+	case bean.PlanActionUpdate:
+		_, err := impl.appCrudOperationService.UpdateApp(&bean3.CreateAppDTO{
+			Id:          appId,
+			AppName:     metadata.Name,
+			Description: metadata.Description,
+			TeamId:      refs.teamId,
+			AppLabels:   metadata.Labels,
+			UserId:      userId,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return appId, nil
+}
+
+// applyGitMaterials returns the ids of the git materials of the app by checkout path
+func (impl *AppSnapshotServiceImpl) applyGitMaterials(appId int, materials []*bean.GitMaterialSpec, refs *snapshotReferences, actions map[string]bean.PlanAction, userId int32) (map[string]*bean3.GitMaterial, error) {
+	appDetail, err := impl.pipelineBuilder.GetApp(appId)
+	if err != nil {
+		return nil, err
+	}
+	savedMaterials := make(map[string]*bean3.GitMaterial, len(appDetail.Material))
+	for _, material := range appDetail.Material {
+		savedMaterials[material.CheckoutPath] = material
+	}
+	for _, material := range materials {
+		gitMaterial := &bean3.GitMaterial{
+			Url:             material.Url,
+			GitProviderId:   refs.gitProviderIds[material.GitProvider],
+			CheckoutPath:    material.CheckoutPath,
+			FetchSubmodules: material.FetchSubmodules,
+			FilterPattern:   material.FilterPattern,
+		}
+		switch actions[planItemKey(bean.ItemKindGitMaterial, "", "", material.CheckoutPath)] {
+		case bean.PlanActionCreate:
+			created, err := impl.pipelineBuilder.CreateMaterialsForApp(&bean3.CreateMaterialDTO{
+				AppId:    appId,
+				Material: []*bean3.GitMaterial{gitMaterial},
+				UserId:   userId,
+			})
+			if err != nil {
+				return nil, err
+			}
+			savedMaterials[material.CheckoutPath] = created.Material[0]
+		case bean.PlanActionUpdate:
+			savedMaterial := savedMaterials[material.CheckoutPath]
+			gitMaterial.Id = savedMaterial.Id
+			gitMaterial.Name = savedMaterial.Name
+			_, err = impl.pipelineBuilder.UpdateMaterialsForApp(&bean3.UpdateMaterialDTO{
+				AppId:    appId,
+				Material: gitMaterial,
+				UserId:   userId,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return savedMaterials, nil
+}
+
+func getMaterialIds(savedMaterials map[string]*bean3.GitMaterial) map[string]int {
+	materialIds := make(map[string]int, len(savedMaterials))
+	for checkoutPath, material := range savedMaterials {
+		materialIds[checkoutPath] = material.Id
+	}
+	return materialIds
+}
+
+// getDefaultMaterialId returns the first git material of the snapshot, which builds use when no git material is set
+func getDefaultMaterialId(spec *bean.SnapshotSpec, materialIds map[string]int) int {
+	if len(spec.GitMaterials) > 0 {
+		return materialIds[spec.GitMaterials[0].CheckoutPath]
+	}
+	for _, id := range materialIds {
+		return id
+	}
+	return 0
+}
+
+func (impl *AppSnapshotServiceImpl) applyBuildConfig(appId int, snapshot *bean.AppSnapshot, savedMaterials map[string]*bean3.GitMaterial, actions map[string]bean.PlanAction, userId int32) error {
+	buildConfig := snapshot.Spec.BuildConfig
+	if buildConfig == nil {
+		return nil
+	}
+	materialIds := getMaterialIds(savedMaterials)
+	ciBuildConfig := toCiBuildConfig(buildConfig, materialIds, getDefaultMaterialId(snapshot.Spec, materialIds))
+	switch actions[planItemKey(bean.ItemKindBuildConfig, "", "", "")] {
+	case bean.PlanActionCreate:
+		_, err := impl.pipelineBuilder.CreateCiPipeline(&bean3.CiConfigRequest{
+			AppId:            appId,
+			DockerRegistry:   buildConfig.DockerRegistry,
+			DockerRepository: buildConfig.DockerRepository,
+			CiBuildConfig:    ciBuildConfig,
+			UserId:           userId,
+		})
+		return err
+	case bean.PlanActionUpdate:
+		ciConfig, err := impl.pipelineBuilder.GetCiPipeline(appId)
+		if err != nil {
+			return err
+		}
+		if ciConfig.CiBuildConfig != nil {
+			ciBuildConfig.Id = ciConfig.CiBuildConfig.Id
+		}
+		ciConfig.DockerRegistry = buildConfig.DockerRegistry
+		ciConfig.DockerRepository = buildConfig.DockerRepository
+		ciConfig.CiBuildConfig = ciBuildConfig
+		ciConfig.UserId = userId
+		_, err = impl.pipelineBuilder.UpdateCiTemplate(ciConfig)
+		return err
+	}
+	return nil
+}
+
+func (impl *AppSnapshotServiceImpl) applyDeploymentTemplate(ctx context.Context, appId int, template *bean.DeploymentTemplateSpec, refs *snapshotReferences, actions map[string]bean.PlanAction, userId int32) error {
+	if template == nil {
+		return nil
+	}
+	switch actions[planItemKey(bean.ItemKindDeploymentTemplate, "", "", "")] {
+	case bean.PlanActionCreate:
+		_, err := impl.chartService.Create(chart.TemplateRequest{
+			AppId:             appId,
+			Latest:            true,
+			ValuesOverride:    template.Values,
+			ChartRefId:        refs.chartRefIds[chartKey(template.ChartName, template.ChartVersion)],
+			UserId:            userId,
+			IsBasicViewLocked: template.IsBasicViewLocked,
+			CurrentViewEditor: template.CurrentViewEditor,
+		}, ctx)
+		return err
+	case bean.PlanActionUpdate:
+		latestTemplate, err := impl.chartService.FindLatestChartForAppByAppId(appId)
+		if err != nil {
+			return err
+		}
+		_, err = impl.chartService.UpdateAppOverride(ctx, &chart.TemplateRequest{
+			Id:                latestTemplate.Id,
+			AppId:             appId,
+			Latest:            true,
+			ValuesOverride:    template.Values,
+			ChartRefId:        latestTemplate.ChartRefId,
+			UserId:            userId,
+			IsBasicViewLocked: template.IsBasicViewLocked,
+			CurrentViewEditor: template.CurrentViewEditor,
+		})
+		return err
+	}
+	return nil
+}
+
+func (impl *AppSnapshotServiceImpl) applyGlobalConfigData(appId int, spec *bean.SnapshotSpec, actions map[string]bean.PlanAction, userId int32) error {
+	configMaps, err := impl.configMapService.CMGlobalFetch(appId)
+	if err != nil {
+		return err
+	}
+	configMapsId := configMaps.Id
+	for _, configMap := range spec.ConfigMaps {
+		if !isApplicable(actions[planItemKey(bean.ItemKindConfigMap, "", "", configMap.Name)]) {
+			continue
+		}
+		saved, err := impl.configMapService.CMGlobalAddUpdate(&pipelineBean.ConfigDataRequest{
+			Id:         configMapsId,
+			AppId:      appId,
+			ConfigData: []*pipelineBean.ConfigData{toConfigData(configMap, true)},
+			UserId:     userId,
+		})
+		if err != nil {
+			return err
+		}
+		configMapsId = saved.Id
+	}
+	secrets, err := impl.configMapService.CSGlobalFetch(appId)
+	if err != nil {
+		return err
+	}
+	secretsId := secrets.Id
+	for _, secret := range spec.Secrets {
+		if !isApplicable(actions[planItemKey(bean.ItemKindSecret, "", "", secret.Name)]) {
+			continue
+		}
+		saved, err := impl.configMapService.CSGlobalAddUpdate(&pipelineBean.ConfigDataRequest{
+			Id:         secretsId,
+			AppId:      appId,
+			ConfigData: []*pipelineBean.ConfigData{toConfigData(secret, true)},
+			UserId:     userId,
+		})
+		if err != nil {
+			return err
+		}
+		secretsId = saved.Id
+	}
+	return nil
+}
+
+func (impl *AppSnapshotServiceImpl) applyEnvironment(ctx context.Context, appId int, envSpec *bean.EnvironmentSpec, refs *snapshotReferences, actions map[string]bean.PlanAction, userId int32) error {
+	env := refs.environments[envSpec.Name]
+	if template := envSpec.DeploymentTemplate; template != nil {
+		action := actions[planItemKey(bean.ItemKindDeploymentTemplate, envSpec.Name, "", "")]
+		if isApplicable(action) {
+			err := impl.applyEnvironmentOverride(ctx, appId, env, template, refs.chartRefIds[chartKey(template.ChartName, template.ChartVersion)], userId)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	configMaps, err := impl.configMapService.CMEnvironmentFetch(appId, env.Id)
+	if err != nil {
+		return err
+	}
+	globalConfigMaps := getGlobalNames(configMaps.ConfigData)
+	configMapsId := configMaps.Id
+	for _, configMap := range envSpec.ConfigMaps {
+		if !isApplicable(actions[planItemKey(bean.ItemKindConfigMap, envSpec.Name, "", configMap.Name)]) {
+			continue
+		}
+		saved, err := impl.configMapService.CMEnvironmentAddUpdate(&pipelineBean.ConfigDataRequest{
+			Id:            configMapsId,
+			AppId:         appId,
+			EnvironmentId: env.Id,
+			ConfigData:    []*pipelineBean.ConfigData{toConfigData(configMap, globalConfigMaps[configMap.Name])},
+			UserId:        userId,
+		})
+		if err != nil {
+			return err
+		}
+		configMapsId = saved.Id
+	}
+	secrets, err := impl.configMapService.CSEnvironmentFetch(appId, env.Id)
+	if err != nil {
+		return err
+	}
+	globalSecrets := getGlobalNames(secrets.ConfigData)
+	secretsId := secrets.Id
+	for _, secret := range envSpec.Secrets {
+		if !isApplicable(actions[planItemKey(bean.ItemKindSecret, envSpec.Name, "", secret.Name)]) {
+			continue
+		}
+		saved, err := impl.configMapService.CSEnvironmentAddUpdate(&pipelineBean.ConfigDataRequest{
+			Id:            secretsId,
+			AppId:         appId,
+			EnvironmentId: env.Id,
+			ConfigData:    []*pipelineBean.ConfigData{toConfigData(secret, globalSecrets[secret.Name])},
+			UserId:        userId,
+		})
+		if err != nil {
+			return err
+		}
+		secretsId = saved.Id
+	}
+	return nil
+}
+
+// getGlobalNames returns names of the app level entries in an environment fetch, an environment entry with
+// the same name overrides them
+func getGlobalNames(configData []*pipelineBean.ConfigData) map[string]bool {
+	names := make(map[string]bool, len(configData))
+	for _, data := range configData {
+		if data.Global {
+			names[data.Name] = true
+		}
+	}
+	return names
+}
+
+// applyEnvironmentOverride updates the override of the environment when it has one saved, which happens once
+// a deployment pipeline exists, and creates it otherwise
+func (impl *AppSnapshotServiceImpl) applyEnvironmentOverride(ctx context.Context, appId int, env *repository.Environment, template *bean.DeploymentTemplateSpec, chartRefId int, userId int32) error {
+	properties, err := impl.propertiesConfigService.GetEnvironmentProperties(appId, env.Id, chartRefId)
+	if err != nil {
+		return err
+	}
+	if properties.EnvironmentConfig.Id > 0 {
+		request := properties.EnvironmentConfig
+		request.EnvOverrideValues = template.Values
+		request.ChartRefId = chartRefId
+		request.IsOverride = true
+		request.MergeStrategy = template.MergeStrategy
+		request.IsBasicViewLocked = template.IsBasicViewLocked
+		request.CurrentViewEditor = template.CurrentViewEditor
+		request.UserId = userId
+		_, err = impl.propertiesConfigService.UpdateEnvironmentProperties(appId, &request, userId)
+		return err
+	}
+	request := &pipelineBean.EnvironmentProperties{
+		EnvOverrideValues: template.Values,
+		ManualReviewed:    true,
+		Active:            true,
+		Namespace:         env.Namespace,
+		EnvironmentId:     env.Id,
+		ChartRefId:        chartRefId,
+		IsOverride:        true,
+		IsBasicViewLocked: template.IsBasicViewLocked,
+		CurrentViewEditor: template.CurrentViewEditor,
+		MergeStrategy:     template.MergeStrategy,
+		UserId:            userId,
+	}
+	_, err = impl.propertiesConfigService.CreateEnvironmentProperties(appId, request)
+	if err != nil && err.Error() == bean2.NOCHARTEXIST {
+		_, err = impl.chartService.CreateChartFromEnvOverride(chart.TemplateRequest{
+			AppId:             appId,
+			ChartRefId:        chartRefId,
+			ValuesOverride:    []byte("{}"),
+			UserId:            userId,
+			IsBasicViewLocked: template.IsBasicViewLocked,
+			CurrentViewEditor: template.CurrentViewEditor,
+		}, ctx)
+		if err != nil {
+			return err
+		}
+		_, err = impl.propertiesConfigService.CreateEnvironmentProperties(appId, request)
+	}
+	return err
+}
+
+func (impl *AppSnapshotServiceImpl) applyWorkflows(ctx context.Context, appId int, spec *bean.SnapshotSpec, savedMaterials map[string]*bean3.GitMaterial, refs *snapshotReferences, actions map[string]bean.PlanAction, userId int32) error {
+	if len(spec.Workflows) == 0 {
+		return nil
+	}
+	workflows, err := impl.appWorkflowService.FindAppWorkflows(appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		return err
+	}
+	savedWorkflows := make(map[string]bean4.AppWorkflowDto, len(workflows))
+	for _, wf := range workflows {
+		savedWorkflows[wf.Name] = wf
+	}
+	cdPipelines := make(map[int]*bean3.CDPipelineConfigObject)
+	cdPipelineConfig, err := impl.pipelineBuilder.GetCdPipelinesForApp(appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		return err
+	}
+	if cdPipelineConfig != nil {
+		for _, cdPipeline := range cdPipelineConfig.Pipelines {
+			cdPipelines[cdPipeline.EnvironmentId] = cdPipeline
+		}
+	}
+	materialIds := getMaterialIds(savedMaterials)
+	for _, wfSpec := range spec.Workflows {
+		wf, ok := savedWorkflows[wfSpec.Name]
+		if !ok {
+			wf, err = impl.appWorkflowService.CreateAppWorkflow(bean4.AppWorkflowDto{Name: wfSpec.Name, AppId: appId, UserId: userId})
+			if err != nil {
+				return err
+			}
+		}
+		externalCiPipelineId := 0
+		if wfSpec.Webhook {
+			externalCiPipelineId, err = impl.getOrCreateExternalCiPipeline(appId, wf, userId)
+			if err != nil {
+				return err
+			}
+		}
+		ciPipelineId := 0
+		if wfSpec.CiPipeline != nil {
+			ciPipelineId, err = impl.applyCiPipeline(appId, wf, wfSpec.CiPipeline, materialIds, getDefaultMaterialId(spec, materialIds), actions, userId)
+			if err != nil {
+				return err
+			}
+		}
+		for _, cdSpec := range wfSpec.CdPipelines {
+			action := actions[planItemKey(bean.ItemKindCdPipeline, cdSpec.Environment, wfSpec.Name, cdSpec.Name)]
+			if !isApplicable(action) {
+				continue
+			}
+			env := refs.environments[cdSpec.Environment]
+			if action == bean.PlanActionUpdate {
+				cdPipeline := cdPipelines[env.Id]
+				setCdPipelineFields(cdPipeline, cdSpec)
+				_, err = impl.pipelineBuilder.PatchCdPipelines(&bean3.CDPatchRequest{
+					Pipeline: cdPipeline,
+					AppId:    appId,
+					Action:   bean3.CD_UPDATE,
+					UserId:   userId,
+				}, ctx)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			deploymentAppType, err := impl.getDeploymentAppType(env.Id, cdSpec.DeploymentAppType)
+			if err != nil {
+				return err
+			}
+			cdPipeline := &bean3.CDPipelineConfigObject{
+				EnvironmentId:      env.Id,
+				Name:               cdSpec.Name,
+				Namespace:          cdSpec.Namespace,
+				AppWorkflowId:      wf.Id,
+				CiPipelineId:       ciPipelineId,
+				ParentPipelineType: bean4.CI_PIPELINE_TYPE,
+				DeploymentAppType:  deploymentAppType,
+			}
+			if len(cdPipeline.Namespace) == 0 {
+				cdPipeline.Namespace = env.Namespace
+			}
+			setCdPipelineFields(cdPipeline, cdSpec)
+			if len(cdSpec.ParentEnvironment) > 0 {
+				cdPipeline.ParentPipelineType = bean4.CD_PIPELINE_TYPE
+				cdPipeline.ParentPipelineId = cdPipelines[refs.environments[cdSpec.ParentEnvironment].Id].Id
+			} else if wfSpec.Webhook {
+				cdPipeline.CiPipelineId = 0
+				cdPipeline.ParentPipelineType = webhookParent
+				cdPipeline.ParentPipelineId = externalCiPipelineId
+			}
+			created, err := impl.pipelineBuilder.CreateCdPipelines(&bean3.CdPipelines{
+				Pipelines: []*bean3.CDPipelineConfigObject{cdPipeline},
+				AppId:     appId,
+				UserId:    userId,
+			}, ctx)
+			if err != nil {
+				return err
+			}
+			cdPipelines[env.Id] = created.Pipelines[0]
+		}
+	}
+	return nil
+}
+
+func setCdPipelineFields(cdPipeline *bean3.CDPipelineConfigObject, cdSpec *bean.CdPipelineSpec) {
+	cdPipeline.TriggerType = cdSpec.TriggerType
+	cdPipeline.Strategies = cdSpec.Strategies
+	cdPipeline.PreDeployStage = cdSpec.PreDeploy
+	cdPipeline.PostDeployStage = cdSpec.PostDeploy
+	cdPipeline.PreStageConfigMapSecretNames = cdSpec.PreStageConfigMapSecretNames
+	cdPipeline.PostStageConfigMapSecretNames = cdSpec.PostStageConfigMapSecretNames
+	cdPipeline.RunPreStageInEnv = cdSpec.RunPreStageInEnv
+	cdPipeline.RunPostStageInEnv = cdSpec.RunPostStageInEnv
+}
+
+func (impl *AppSnapshotServiceImpl) getOrCreateExternalCiPipeline(appId int, wf bean4.AppWorkflowDto, userId int32) (int, error) {
+	for _, mapping := range wf.AppWorkflowMappingDto {
+		if mapping.Type == appWorkflow2.WEBHOOK {
+			return mapping.ComponentId, nil
+		}
+	}
+	tx, err := impl.pipelineRepository.GetConnection().Begin()
+	if err != nil {
+		return 0, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	externalCiPipelineId, _, err := impl.ciPipelineConfigService.CreateExternalCiAndAppWorkflowMapping(appId, wf.Id, userId, tx)
+	if err != nil {
+		return 0, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return externalCiPipelineId, nil
+}
+
+// applyCiPipeline returns the id of the build pipeline of the workflow after creating or updating it
+func (impl *AppSnapshotServiceImpl) applyCiPipeline(appId int, wf bean4.AppWorkflowDto, ciSpec *bean.CiPipelineSpec, materialIds map[string]int, defaultMaterialId int, actions map[string]bean.PlanAction, userId int32) (int, error) {
+	action := actions[planItemKey(bean.ItemKindCiPipeline, "", wf.Name, ciSpec.Name)]
+	var ciPipeline *bean3.CiPipeline
+	if action != bean.PlanActionCreate {
+		ciConfig, err := impl.pipelineBuilder.GetCiPipeline(appId)
+		if err != nil {
+			return 0, err
+		}
+		for _, saved := range ciConfig.CiPipelines {
+			if saved.Name == ciSpec.Name {
+				ciPipeline = saved
+				break
+			}
+		}
+		if ciPipeline == nil {
+			return 0, fmt.Errorf("build pipeline %s not found", ciSpec.Name)
+		}
+		if !isApplicable(action) {
+			return ciPipeline.Id, nil
+		}
+	} else {
+		ciPipeline = &bean3.CiPipeline{Name: ciSpec.Name}
+	}
+	ciPipeline.IsManual = ciSpec.IsManual
+	ciPipeline.ScanEnabled = ciSpec.ScanEnabled
+	ciPipeline.DockerArgs = ciSpec.DockerArgs
+	ciPipeline.PreBuildStage = ciSpec.PreBuild
+	ciPipeline.PostBuildStage = ciSpec.PostBuild
+	ciPipeline.IsDockerConfigOverridden = ciSpec.BuildConfigOverride != nil
+	ciPipeline.DockerConfigOverride = bean3.DockerConfigOverride{}
+	if override := ciSpec.BuildConfigOverride; override != nil {
+		ciPipeline.DockerConfigOverride = bean3.DockerConfigOverride{
+			DockerRegistry:   override.DockerRegistry,
+			DockerRepository: override.DockerRepository,
+			CiBuildConfig:    toCiBuildConfig(override, materialIds, defaultMaterialId),
+		}
+	}
+	savedMaterials := make(map[int]*bean3.CiMaterial, len(ciPipeline.CiMaterial))
+	for _, material := range ciPipeline.CiMaterial {
+		savedMaterials[material.GitMaterialId] = material
+	}
+	ciMaterials := make([]*bean3.CiMaterial, 0, len(ciSpec.Materials))
+	for _, materialSpec := range ciSpec.Materials {
+		gitMaterialId := materialIds[materialSpec.CheckoutPath]
+		ciMaterial := &bean3.CiMaterial{GitMaterialId: gitMaterialId}
+		if saved, ok := savedMaterials[gitMaterialId]; ok {
+			ciMaterial.Id = saved.Id
+		}
+		ciMaterial.Source = &bean3.SourceTypeConfig{Type: materialSpec.Type, Value: materialSpec.Value, Regex: materialSpec.Regex}
+		ciMaterials = append(ciMaterials, ciMaterial)
+	}
+	ciPipeline.CiMaterial = ciMaterials
+	request := &bean3.CiPatchRequest{
+		CiPipeline:    ciPipeline,
+		AppId:         appId,
+		Action:        bean3.UPDATE_SOURCE,
+		AppWorkflowId: wf.Id,
+		UserId:        userId,
+	}
+	if action == bean.PlanActionCreate {
+		request.Action = bean3.CREATE
+	}
+	saved, err := impl.pipelineBuilder.PatchCiPipeline(request)
+	if err != nil {
+		return 0, err
+	}
+	if action == bean.PlanActionCreate {
+		return saved.CiPipelines[0].Id, nil
+	}
+	return ciPipeline.Id, nil
+}
+
+// getDeploymentAppType keeps the requested deployment type when the environment allows it,
+// and falls back to a type the environment allows otherwise
+func (impl *AppSnapshotServiceImpl) getDeploymentAppType(envId int, requested string) (string, error) {
+	allowedDeploymentAppTypes := map[string]bool{
+		util.PIPELINE_DEPLOYMENT_TYPE_ACD:  true,
+		util.PIPELINE_DEPLOYMENT_TYPE_HELM: true,
+	}
+	deploymentConfig, err := impl.attributesService.GetDeploymentEnforcementConfig(envId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment config for environment", "envId", envId, "err", err)
+	}
+	for deploymentType, allowed := range deploymentConfig {
+		allowedDeploymentAppTypes[deploymentType] = allowed
+	}
+	if len(requested) > 0 && allowedDeploymentAppTypes[requested] {
+		return requested, nil
+	}
+	gitOpsConfigurationStatus, err := impl.gitOpsConfigReadService.IsGitOpsConfigured()
+	if err != nil {
+		impl.logger.Errorw("error in checking if gitOps configured", "err", err)
+		return "", err
+	}
+	if allowedDeploymentAppTypes[util.PIPELINE_DEPLOYMENT_TYPE_ACD] && gitOpsConfigurationStatus.IsGitOpsConfigured {
+		return util.PIPELINE_DEPLOYMENT_TYPE_ACD, nil
+	}
+	if allowedDeploymentAppTypes[util.PIPELINE_DEPLOYMENT_TYPE_HELM] {
+		return util.PIPELINE_DEPLOYMENT_TYPE_HELM, nil
+	}
+	return "", badRequest(fmt.Sprintf("no deployment type is allowed for environment %d", envId))
+}
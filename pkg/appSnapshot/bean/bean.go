@@ -0,0 +1,222 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/internal/sql/constants"
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	buildBean "github.com/devtron-labs/devtron/pkg/build/pipeline/bean"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+)
+
+const (
+	SnapshotApiVersion = "devtron.ai/v1"
+	SnapshotKind       = "Application"
+	// MaskedSecretValue replaces secret data values in exported snapshots, a masked value
+	// in an imported snapshot keeps the value already saved for the key
+	MaskedSecretValue = "********"
+	SnapshotFileExt   = ".yaml"
+)
+
+// AppSnapshot is the declarative definition of a devtron app. Entities are referred by name, never by id,
+// so that a snapshot can be applied on any devtron instance having the same projects, environments,
+// git providers, container registries and charts
+type AppSnapshot struct {
+	ApiVersion string            `json:"apiVersion" validate:"required"`
+	Kind       string            `json:"kind" validate:"required"`
+	Metadata   *SnapshotMetadata `json:"metadata" validate:"required"`
+	Spec       *SnapshotSpec     `json:"spec" validate:"required"`
+}
+
+type SnapshotMetadata struct {
+	Name        string        `json:"name" validate:"required,name-component,max=100"`
+	Project     string        `json:"project" validate:"required"`
+	Description string        `json:"description,omitempty"`
+	Labels      []*bean.Label `json:"labels,omitempty" validate:"dive"`
+}
+
+type SnapshotSpec struct {
+	GitMaterials       []*GitMaterialSpec      `json:"gitMaterials,omitempty" validate:"dive"`
+	BuildConfig        *BuildConfigSpec        `json:"buildConfig,omitempty"`
+	DeploymentTemplate *DeploymentTemplateSpec `json:"deploymentTemplate,omitempty"`
+	ConfigMaps         []*ConfigDataSpec       `json:"configMaps,omitempty" validate:"dive"`
+	Secrets            []*ConfigDataSpec       `json:"secrets,omitempty" validate:"dive"`
+	Environments       []*EnvironmentSpec      `json:"environments,omitempty" validate:"dive"`
+	Workflows          []*WorkflowSpec         `json:"workflows,omitempty" validate:"dive"`
+}
+
+// GitMaterialSpec is identified by its checkout path, which is unique within an app
+type GitMaterialSpec struct {
+	CheckoutPath    string   `json:"checkoutPath" validate:"required"`
+	Url             string   `json:"url" validate:"required"`
+	GitProvider     string   `json:"gitProvider" validate:"required"`
+	FetchSubmodules bool     `json:"fetchSubmodules,omitempty"`
+	FilterPattern   []string `json:"filterPattern,omitempty"`
+}
+
+// BuildConfigSpec is the build configuration of the app, or the build configuration override of a ci pipeline.
+// Git materials are referred by their checkout path
+type BuildConfigSpec struct {
+	DockerRegistry           string                       `json:"dockerRegistry" validate:"required"`
+	DockerRepository         string                       `json:"dockerRepository,omitempty"`
+	GitMaterialCheckoutPath  string                       `json:"gitMaterialCheckoutPath,omitempty"`
+	BuildContextCheckoutPath string                       `json:"buildContextCheckoutPath,omitempty"`
+	UseRootBuildContext      bool                         `json:"useRootBuildContext,omitempty"`
+	CiBuildType              buildBean.CiBuildType        `json:"ciBuildType,omitempty"`
+	DockerBuildConfig        *buildBean.DockerBuildConfig `json:"dockerBuildConfig,omitempty"`
+	BuildPackConfig          *buildBean.BuildPackConfig   `json:"buildPackConfig,omitempty"`
+}
+
+type DeploymentTemplateSpec struct {
+	ChartName         string                      `json:"chartName,omitempty"`
+	ChartVersion      string                      `json:"chartVersion" validate:"required"`
+	Values            json.RawMessage             `json:"values" validate:"required"`
+	MergeStrategy     models.MergeStrategy        `json:"mergeStrategy,omitempty"`
+	IsBasicViewLocked bool                        `json:"isBasicViewLocked,omitempty"`
+	CurrentViewEditor models.ChartsViewEditorType `json:"currentViewEditor,omitempty"`
+}
+
+// ConfigDataSpec is a configmap or a secret, identified by its name
+type ConfigDataSpec struct {
+	Name               string                        `json:"name" validate:"required"`
+	Type               string                        `json:"type" validate:"oneof=environment volume"`
+	External           bool                          `json:"external,omitempty"`
+	ExternalSecretType string                        `json:"externalType,omitempty"`
+	MountPath          string                        `json:"mountPath,omitempty"`
+	SubPath            bool                          `json:"subPath,omitempty"`
+	FilePermission     string                        `json:"filePermission,omitempty"`
+	MergeStrategy      models.MergeStrategy          `json:"mergeStrategy,omitempty"`
+	Data               json.RawMessage               `json:"data,omitempty"`
+	ESOSecretData      *pipelineBean.ESOSecretData   `json:"esoSecretData,omitempty"`
+	ExternalSecret     []pipelineBean.ExternalSecret `json:"secretData,omitempty"`
+	ESOSubPath         []string                      `json:"esoSubPath,omitempty"`
+	RoleARN            string                        `json:"roleARN,omitempty"`
+}
+
+// EnvironmentSpec holds the overrides of an environment, an empty deployment template inherits the app one
+type EnvironmentSpec struct {
+	Name               string                  `json:"name" validate:"required"`
+	DeploymentTemplate *DeploymentTemplateSpec `json:"deploymentTemplate,omitempty"`
+	ConfigMaps         []*ConfigDataSpec       `json:"configMaps,omitempty" validate:"dive"`
+	Secrets            []*ConfigDataSpec       `json:"secrets,omitempty" validate:"dive"`
+}
+
+// WorkflowSpec is a build pipeline, or a webhook for images built outside devtron, followed by cd pipelines
+type WorkflowSpec struct {
+	Name        string            `json:"name" validate:"required"`
+	Webhook     bool              `json:"webhook,omitempty"`
+	CiPipeline  *CiPipelineSpec   `json:"ciPipeline,omitempty"`
+	CdPipelines []*CdPipelineSpec `json:"cdPipelines,omitempty" validate:"dive"`
+}
+
+type CiPipelineSpec struct {
+	Name        string                         `json:"name" validate:"required"`
+	IsManual    bool                           `json:"isManual,omitempty"`
+	ScanEnabled bool                           `json:"scanEnabled,omitempty"`
+	DockerArgs  map[string]string              `json:"dockerArgs,omitempty"`
+	Materials   []*CiMaterialSpec              `json:"materials" validate:"min=1,dive"`
+	PreBuild    *pipelineBean.PipelineStageDto `json:"preBuildStage,omitempty"`
+	PostBuild   *pipelineBean.PipelineStageDto `json:"postBuildStage,omitempty"`
+	// BuildConfigOverride overrides the build configuration of the app for this pipeline
+	BuildConfigOverride *BuildConfigSpec `json:"buildConfigOverride,omitempty"`
+}
+
+type CiMaterialSpec struct {
+	CheckoutPath string               `json:"checkoutPath" validate:"required"`
+	Type         constants.SourceType `json:"type" validate:"required"`
+	Value        string               `json:"value,omitempty"`
+	Regex        string               `json:"regex,omitempty"`
+}
+
+// CdPipelineSpec is identified by its environment, an app has at most one cd pipeline per environment
+type CdPipelineSpec struct {
+	Name        string `json:"name" validate:"required"`
+	Environment string `json:"environment" validate:"required"`
+	Namespace   string `json:"namespace,omitempty"`
+	// ParentEnvironment is the environment of the cd pipeline this one is deployed after,
+	// the pipeline deploys images of the build pipeline or webhook of its workflow when empty
+	ParentEnvironment             string                             `json:"parentEnvironment,omitempty"`
+	TriggerType                   pipelineConfig.TriggerType         `json:"triggerType" validate:"oneof=AUTOMATIC MANUAL"`
+	DeploymentAppType             string                             `json:"deploymentAppType,omitempty"`
+	Strategies                    []bean.Strategy                    `json:"strategies,omitempty"`
+	PreDeploy                     *pipelineBean.PipelineStageDto     `json:"preDeployStage,omitempty"`
+	PostDeploy                    *pipelineBean.PipelineStageDto     `json:"postDeployStage,omitempty"`
+	PreStageConfigMapSecretNames  bean.PreStageConfigMapSecretNames  `json:"preStageConfigMapSecretNames,omitempty"`
+	PostStageConfigMapSecretNames bean.PostStageConfigMapSecretNames `json:"postStageConfigMapSecretNames,omitempty"`
+	RunPreStageInEnv              bool                               `json:"runPreStageInEnv,omitempty"`
+	RunPostStageInEnv             bool                               `json:"runPostStageInEnv,omitempty"`
+}
+
+type ItemKind string
+
+const (
+	ItemKindApp                ItemKind = "APP"
+	ItemKindGitMaterial        ItemKind = "GIT_MATERIAL"
+	ItemKindBuildConfig        ItemKind = "BUILD_CONFIG"
+	ItemKindDeploymentTemplate ItemKind = "DEPLOYMENT_TEMPLATE"
+	ItemKindConfigMap          ItemKind = "CONFIG_MAP"
+	ItemKindSecret             ItemKind = "SECRET"
+	ItemKindWorkflow           ItemKind = "WORKFLOW"
+	ItemKindCiPipeline         ItemKind = "CI_PIPELINE"
+	ItemKindCdPipeline         ItemKind = "CD_PIPELINE"
+)
+
+type PlanAction string
+
+const (
+	PlanActionCreate      PlanAction = "CREATE"
+	PlanActionUpdate      PlanAction = "UPDATE"
+	PlanActionNoChange    PlanAction = "NO_CHANGE"
+	PlanActionUnsupported PlanAction = "UNSUPPORTED"
+)
+
+// ImportPlan lists what importing a snapshot creates and updates, in the order it is applied.
+// Items of the app missing in the snapshot are left as they are, an import never deletes anything
+type ImportPlan struct {
+	// AppId is 0 in the plan of an app not created yet
+	AppId   int          `json:"appId"`
+	AppName string       `json:"appName"`
+	Summary *PlanSummary `json:"summary"`
+	Items   []*PlanItem  `json:"items"`
+}
+
+type PlanSummary struct {
+	Create      int `json:"create"`
+	Update      int `json:"update"`
+	NoChange    int `json:"noChange"`
+	Unsupported int `json:"unsupported"`
+}
+
+type PlanItem struct {
+	Kind        ItemKind       `json:"kind"`
+	Name        string         `json:"name,omitempty"`
+	Environment string         `json:"environment,omitempty"`
+	Workflow    string         `json:"workflow,omitempty"`
+	Action      PlanAction     `json:"action"`
+	Changes     []*FieldChange `json:"changes,omitempty"`
+	// Message tells why an item is unsupported
+	Message string `json:"message,omitempty"`
+}
+
+type FieldChange struct {
+	Path     string      `json:"path"`
+	OldValue interface{} `json:"oldValue"`
+	NewValue interface{} `json:"newValue"`
+}
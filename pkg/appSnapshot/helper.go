@@ -0,0 +1,606 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appSnapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appSnapshot/bean"
+	bean3 "github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const secretDataPath = "data"
+
+func planItemKey(kind bean.ItemKind, environment, workflow, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", kind, environment, workflow, name)
+}
+
+func chartKey(name, version string) string {
+	return fmt.Sprintf("%s/%s", name, version)
+}
+
+// validateSnapshot checks that names used as keys are unique and that references between items of the snapshot resolve
+func validateSnapshot(snapshot *bean.AppSnapshot) error {
+	if snapshot.ApiVersion != bean.SnapshotApiVersion || snapshot.Kind != bean.SnapshotKind {
+		return badRequest(fmt.Sprintf("unsupported snapshot, expected apiVersion %s and kind %s", bean.SnapshotApiVersion, bean.SnapshotKind))
+	}
+	spec := snapshot.Spec
+	checkoutPaths := make(map[string]bool, len(spec.GitMaterials))
+	for _, material := range spec.GitMaterials {
+		if checkoutPaths[material.CheckoutPath] {
+			return badRequest(fmt.Sprintf("git material checkout path %s is used more than once", material.CheckoutPath))
+		}
+		checkoutPaths[material.CheckoutPath] = true
+	}
+	if err := validateBuildConfig(spec.BuildConfig, checkoutPaths); err != nil {
+		return err
+	}
+	if err := validateConfigDataNames(spec.ConfigMaps, spec.Secrets, ""); err != nil {
+		return err
+	}
+	environments := make(map[string]bool, len(spec.Environments))
+	for _, env := range spec.Environments {
+		if environments[env.Name] {
+			return badRequest(fmt.Sprintf("environment %s is defined more than once", env.Name))
+		}
+		environments[env.Name] = true
+		if err := validateConfigDataNames(env.ConfigMaps, env.Secrets, env.Name); err != nil {
+			return err
+		}
+	}
+	workflows := make(map[string]bool, len(spec.Workflows))
+	cdEnvironments := make(map[string]bool)
+	for _, wf := range spec.Workflows {
+		if workflows[wf.Name] {
+			return badRequest(fmt.Sprintf("workflow %s is defined more than once", wf.Name))
+		}
+		workflows[wf.Name] = true
+		if wf.Webhook && wf.CiPipeline != nil {
+			return badRequest(fmt.Sprintf("workflow %s can not have both a webhook and a build pipeline", wf.Name))
+		}
+		if !wf.Webhook && wf.CiPipeline == nil && len(wf.CdPipelines) > 0 {
+			return badRequest(fmt.Sprintf("workflow %s needs a build pipeline or a webhook to deploy", wf.Name))
+		}
+		if wf.CiPipeline != nil {
+			for _, material := range wf.CiPipeline.Materials {
+				if !checkoutPaths[material.CheckoutPath] {
+					return badRequest(fmt.Sprintf("build pipeline %s uses unknown git material %s", wf.CiPipeline.Name, material.CheckoutPath))
+				}
+			}
+			if err := validateBuildConfig(wf.CiPipeline.BuildConfigOverride, checkoutPaths); err != nil {
+				return err
+			}
+		}
+		workflowEnvironments := make(map[string]bool, len(wf.CdPipelines))
+		for _, cd := range wf.CdPipelines {
+			if cdEnvironments[cd.Environment] {
+				return badRequest(fmt.Sprintf("environment %s has more than one deployment pipeline", cd.Environment))
+			}
+			// parents are listed before their children so that they are created first
+			if len(cd.ParentEnvironment) > 0 && !workflowEnvironments[cd.ParentEnvironment] {
+				return badRequest(fmt.Sprintf("deployment pipeline %s must be listed after its parent %s in workflow %s", cd.Name, cd.ParentEnvironment, wf.Name))
+			}
+			cdEnvironments[cd.Environment] = true
+			workflowEnvironments[cd.Environment] = true
+		}
+	}
+	return nil
+}
+
+func validateBuildConfig(buildConfig *bean.BuildConfigSpec, checkoutPaths map[string]bool) error {
+	if buildConfig == nil {
+		return nil
+	}
+	for _, checkoutPath := range []string{buildConfig.GitMaterialCheckoutPath, buildConfig.BuildContextCheckoutPath} {
+		if len(checkoutPath) > 0 && !checkoutPaths[checkoutPath] {
+			return badRequest(fmt.Sprintf("build configuration uses unknown git material %s", checkoutPath))
+		}
+	}
+	return nil
+}
+
+func validateConfigDataNames(configMaps, secrets []*bean.ConfigDataSpec, environment string) error {
+	for kind, configData := range map[string][]*bean.ConfigDataSpec{"configmap": configMaps, "secret": secrets} {
+		names := make(map[string]bool, len(configData))
+		for _, data := range configData {
+			if names[data.Name] {
+				if len(environment) > 0 {
+					return badRequest(fmt.Sprintf("%s %s is defined more than once in environment %s", kind, data.Name, environment))
+				}
+				return badRequest(fmt.Sprintf("%s %s is defined more than once", kind, data.Name))
+			}
+			names[data.Name] = true
+		}
+	}
+	return nil
+}
+
+func badRequest(message string) error {
+	return util.NewApiError(http.StatusBadRequest, message, message)
+}
+
+// buildPlan compares the snapshot of the app saved in devtron with the desired one, current is nil for a new app.
+// Masked secret values of the desired snapshot are replaced in place with the saved values so that it can be applied.
+func buildPlan(current, desired *bean.AppSnapshot) (*bean.ImportPlan, error) {
+	currentSpec := &bean.SnapshotSpec{}
+	var currentMetadata *bean.SnapshotMetadata
+	if current != nil {
+		currentSpec = current.Spec
+		currentMetadata = current.Metadata
+	}
+	desiredSpec := desired.Spec
+	items := make([]*bean.PlanItem, 0)
+	appItem, err := newPlanItem(bean.ItemKindApp, desired.Metadata.Name, "", "", currentMetadata != nil, currentMetadata, desired.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, appItem)
+
+	currentMaterials := make(map[string]*bean.GitMaterialSpec, len(currentSpec.GitMaterials))
+	for _, material := range currentSpec.GitMaterials {
+		currentMaterials[material.CheckoutPath] = material
+	}
+	for _, material := range desiredSpec.GitMaterials {
+		currentMaterial, ok := currentMaterials[material.CheckoutPath]
+		item, err := newPlanItem(bean.ItemKindGitMaterial, material.CheckoutPath, "", "", ok, currentMaterial, material)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if desiredSpec.BuildConfig != nil {
+		item, err := newPlanItem(bean.ItemKindBuildConfig, "", "", "", currentSpec.BuildConfig != nil, currentSpec.BuildConfig, desiredSpec.BuildConfig)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if desiredSpec.DeploymentTemplate != nil {
+		item, err := planDeploymentTemplate("", currentSpec.DeploymentTemplate, desiredSpec.DeploymentTemplate)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	configDataItems, err := planConfigData(bean.ItemKindConfigMap, "", currentSpec.ConfigMaps, desiredSpec.ConfigMaps)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, configDataItems...)
+	configDataItems, err = planConfigData(bean.ItemKindSecret, "", currentSpec.Secrets, desiredSpec.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, configDataItems...)
+
+	currentEnvironments := make(map[string]*bean.EnvironmentSpec, len(currentSpec.Environments))
+	for _, env := range currentSpec.Environments {
+		currentEnvironments[env.Name] = env
+	}
+	for _, env := range desiredSpec.Environments {
+		currentEnv, ok := currentEnvironments[env.Name]
+		if !ok {
+			currentEnv = &bean.EnvironmentSpec{Name: env.Name}
+		}
+		if env.DeploymentTemplate != nil {
+			item, err := planDeploymentTemplate(env.Name, currentEnv.DeploymentTemplate, env.DeploymentTemplate)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		configDataItems, err = planConfigData(bean.ItemKindConfigMap, env.Name, currentEnv.ConfigMaps, env.ConfigMaps)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, configDataItems...)
+		configDataItems, err = planConfigData(bean.ItemKindSecret, env.Name, currentEnv.Secrets, env.Secrets)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, configDataItems...)
+	}
+
+	workflowItems, err := planWorkflows(currentSpec.Workflows, desiredSpec.Workflows)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, workflowItems...)
+	return &bean.ImportPlan{
+		AppName: desired.Metadata.Name,
+		Summary: getPlanSummary(items),
+		Items:   items,
+	}, nil
+}
+
+func planDeploymentTemplate(environment string, current, desired *bean.DeploymentTemplateSpec) (*bean.PlanItem, error) {
+	item, err := newPlanItem(bean.ItemKindDeploymentTemplate, "", environment, "", current != nil, current, desired)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && (current.ChartName != desired.ChartName || current.ChartVersion != desired.ChartVersion) {
+		markUnsupported(item, fmt.Sprintf("changing the chart from %s %s to %s %s is not supported by import, change it from the deployment template first",
+			current.ChartName, current.ChartVersion, desired.ChartName, desired.ChartVersion))
+	}
+	return item, nil
+}
+
+func planConfigData(kind bean.ItemKind, environment string, current, desired []*bean.ConfigDataSpec) ([]*bean.PlanItem, error) {
+	currentByName := make(map[string]*bean.ConfigDataSpec, len(current))
+	for _, data := range current {
+		currentByName[data.Name] = data
+	}
+	items := make([]*bean.PlanItem, 0, len(desired))
+	for _, data := range desired {
+		currentData, ok := currentByName[data.Name]
+		var unresolvedKeys []string
+		if kind == bean.ItemKindSecret {
+			var currentValues json.RawMessage
+			if ok {
+				currentValues = currentData.Data
+			}
+			resolved, keys, err := resolveMaskedSecretData(data.Data, currentValues)
+			if err != nil {
+				return nil, err
+			}
+			data.Data, unresolvedKeys = resolved, keys
+		}
+		item, err := newPlanItem(kind, data.Name, environment, "", ok, currentData, data)
+		if err != nil {
+			return nil, err
+		}
+		if kind == bean.ItemKindSecret {
+			maskSecretFieldChanges(item.Changes)
+		}
+		if len(unresolvedKeys) > 0 {
+			markUnsupported(item, fmt.Sprintf("masked values of keys %s have no saved value to keep, set their values", strings.Join(unresolvedKeys, ", ")))
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+type currentCdPipeline struct {
+	workflow string
+	pipeline *bean.CdPipelineSpec
+}
+
+func planWorkflows(current, desired []*bean.WorkflowSpec) ([]*bean.PlanItem, error) {
+	currentWorkflows := make(map[string]*bean.WorkflowSpec, len(current))
+	currentCdPipelines := make(map[string]*currentCdPipeline)
+	for _, wf := range current {
+		currentWorkflows[wf.Name] = wf
+		for _, cd := range wf.CdPipelines {
+			currentCdPipelines[cd.Environment] = &currentCdPipeline{workflow: wf.Name, pipeline: cd}
+		}
+	}
+	items := make([]*bean.PlanItem, 0)
+	for _, wf := range desired {
+		currentWf, ok := currentWorkflows[wf.Name]
+		item := &bean.PlanItem{Kind: bean.ItemKindWorkflow, Name: wf.Name, Workflow: wf.Name, Action: bean.PlanActionCreate}
+		if ok {
+			item.Action = bean.PlanActionNoChange
+			if currentWf.Webhook != wf.Webhook {
+				markUnsupported(item, "switching a workflow between webhook and build pipeline is not supported by import")
+			}
+		} else {
+			currentWf = &bean.WorkflowSpec{Name: wf.Name}
+		}
+		items = append(items, item)
+		if wf.CiPipeline != nil {
+			wf.CiPipeline.PreBuild = normalizeStage(wf.CiPipeline.PreBuild)
+			wf.CiPipeline.PostBuild = normalizeStage(wf.CiPipeline.PostBuild)
+			ciExists := currentWf.CiPipeline != nil
+			ciItem, err := newPlanItem(bean.ItemKindCiPipeline, wf.CiPipeline.Name, "", wf.Name, ciExists, currentWf.CiPipeline, wf.CiPipeline)
+			if err != nil {
+				return nil, err
+			}
+			if ciExists && currentWf.CiPipeline.Name != wf.CiPipeline.Name {
+				markUnsupported(ciItem, fmt.Sprintf("workflow already has build pipeline %s, renaming build pipelines is not supported by import", currentWf.CiPipeline.Name))
+			}
+			items = append(items, ciItem)
+		}
+		for _, cd := range wf.CdPipelines {
+			cd.PreDeploy = normalizeStage(cd.PreDeploy)
+			cd.PostDeploy = normalizeStage(cd.PostDeploy)
+			existing, cdExists := currentCdPipelines[cd.Environment]
+			var currentCd *bean.CdPipelineSpec
+			if cdExists {
+				currentCd = existing.pipeline
+			}
+			cdItem, err := newPlanItem(bean.ItemKindCdPipeline, cd.Name, cd.Environment, wf.Name, cdExists, currentCd, cd)
+			if err != nil {
+				return nil, err
+			}
+			if cdExists {
+				if existing.workflow != wf.Name {
+					markUnsupported(cdItem, fmt.Sprintf("deployment pipeline of the environment is in workflow %s, moving it across workflows is not supported by import", existing.workflow))
+				} else if currentCd.ParentEnvironment != cd.ParentEnvironment {
+					markUnsupported(cdItem, "changing the parent of a deployment pipeline is not supported by import")
+				} else if len(cd.DeploymentAppType) > 0 && currentCd.DeploymentAppType != cd.DeploymentAppType {
+					markUnsupported(cdItem, fmt.Sprintf("changing the deployment type from %s to %s is not supported by import", currentCd.DeploymentAppType, cd.DeploymentAppType))
+				}
+			}
+			items = append(items, cdItem)
+		}
+	}
+	return items, nil
+}
+
+// newPlanItem returns a create item when the current value does not exist, an update item listing field changes otherwise
+func newPlanItem(kind bean.ItemKind, name, environment, workflow string, exists bool, current, desired interface{}) (*bean.PlanItem, error) {
+	item := &bean.PlanItem{Kind: kind, Name: name, Environment: environment, Workflow: workflow}
+	if !exists {
+		item.Action = bean.PlanActionCreate
+		return item, nil
+	}
+	changes, err := diffValues(current, desired)
+	if err != nil {
+		return nil, err
+	}
+	item.Changes = changes
+	if len(changes) > 0 {
+		item.Action = bean.PlanActionUpdate
+	} else {
+		item.Action = bean.PlanActionNoChange
+	}
+	return item, nil
+}
+
+func markUnsupported(item *bean.PlanItem, message string) {
+	item.Action = bean.PlanActionUnsupported
+	item.Message = message
+}
+
+// diffValues compares the json form of both values so that yaml and go defaults compare equal
+func diffValues(current, desired interface{}) ([]*bean.FieldChange, error) {
+	currentValue, err := toGeneric(current)
+	if err != nil {
+		return nil, err
+	}
+	desiredValue, err := toGeneric(desired)
+	if err != nil {
+		return nil, err
+	}
+	return diffFields("", currentValue, desiredValue), nil
+}
+
+func toGeneric(value interface{}) (interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	err = json.Unmarshal(raw, &generic)
+	return generic, err
+}
+
+// diffFields walks both values and records leaf level changes with their dotted path, lists are compared as a whole
+func diffFields(path string, oldValue, newValue interface{}) []*bean.FieldChange {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if !oldIsMap || !newIsMap {
+		if reflect.DeepEqual(oldValue, newValue) {
+			return nil
+		}
+		return []*bean.FieldChange{{Path: path, OldValue: oldValue, NewValue: newValue}}
+	}
+	keys := make([]string, 0, len(oldMap)+len(newMap))
+	for key := range oldMap {
+		keys = append(keys, key)
+	}
+	for key := range newMap {
+		if _, ok := oldMap[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	changes := make([]*bean.FieldChange, 0)
+	for _, key := range keys {
+		childPath := key
+		if len(path) > 0 {
+			childPath = fmt.Sprintf("%s.%s", path, key)
+		}
+		changes = append(changes, diffFields(childPath, oldMap[key], newMap[key])...)
+	}
+	return changes
+}
+
+func maskSecretFieldChanges(changes []*bean.FieldChange) {
+	for _, change := range changes {
+		if change.Path != secretDataPath && !strings.HasPrefix(change.Path, secretDataPath+".") {
+			continue
+		}
+		if change.OldValue != nil {
+			change.OldValue = bean.MaskedSecretValue
+		}
+		if change.NewValue != nil {
+			change.NewValue = bean.MaskedSecretValue
+		}
+	}
+}
+
+// maskSecretData replaces every value of the secret data with the masked value
+func maskSecretData(data json.RawMessage) (json.RawMessage, error) {
+	if len(data) == 0 {
+		return data, nil
+	}
+	values := make(map[string]interface{})
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	for key := range values {
+		values[key] = bean.MaskedSecretValue
+	}
+	return json.Marshal(values)
+}
+
+// resolveMaskedSecretData replaces masked values of the desired secret data with the saved values of the same keys,
+// keys having no saved value are returned sorted
+func resolveMaskedSecretData(desired, current json.RawMessage) (json.RawMessage, []string, error) {
+	if len(desired) == 0 {
+		return desired, nil, nil
+	}
+	desiredValues := make(map[string]interface{})
+	if err := json.Unmarshal(desired, &desiredValues); err != nil {
+		return nil, nil, badRequest("secret data must be a map of keys to values")
+	}
+	currentValues := make(map[string]interface{})
+	if len(current) > 0 {
+		if err := json.Unmarshal(current, &currentValues); err != nil {
+			return nil, nil, err
+		}
+	}
+	masked := false
+	unresolvedKeys := make([]string, 0)
+	for key, value := range desiredValues {
+		if value != bean.MaskedSecretValue {
+			continue
+		}
+		masked = true
+		currentValue, ok := currentValues[key]
+		if !ok {
+			unresolvedKeys = append(unresolvedKeys, key)
+			continue
+		}
+		desiredValues[key] = currentValue
+	}
+	if !masked {
+		return desired, nil, nil
+	}
+	sort.Strings(unresolvedKeys)
+	resolved, err := json.Marshal(desiredValues)
+	return resolved, unresolvedKeys, err
+}
+
+// normalizeStage returns nil for a stage without steps, and resets ids of the stage otherwise
+// so that stages of different pipelines compare equal and are saved as new
+func normalizeStage(stage *pipelineBean.PipelineStageDto) *pipelineBean.PipelineStageDto {
+	if stage == nil || len(stage.Steps) == 0 {
+		return nil
+	}
+	stage.Id = 0
+	for _, step := range stage.Steps {
+		step.Id = 0
+		if step.InlineStepDetail != nil {
+			clearVariableIds(step.InlineStepDetail.InputVariables, step.InlineStepDetail.OutputVariables, step.InlineStepDetail.ConditionDetails)
+		}
+		if step.RefPluginStepDetail != nil {
+			clearVariableIds(step.RefPluginStepDetail.InputVariables, step.RefPluginStepDetail.OutputVariables, step.RefPluginStepDetail.ConditionDetails)
+		}
+	}
+	return stage
+}
+
+func clearVariableIds(inputVariables, outputVariables []*pipelineBean.StepVariableDto, conditions []*pipelineBean.ConditionDetailDto) {
+	for _, variables := range [][]*pipelineBean.StepVariableDto{inputVariables, outputVariables} {
+		for _, variable := range variables {
+			variable.Id = 0
+		}
+	}
+	for _, condition := range conditions {
+		condition.Id = 0
+	}
+}
+
+func getPlanSummary(items []*bean.PlanItem) *bean.PlanSummary {
+	summary := &bean.PlanSummary{}
+	for _, item := range items {
+		switch item.Action {
+		case bean.PlanActionCreate:
+			summary.Create++
+		case bean.PlanActionUpdate:
+			summary.Update++
+		case bean.PlanActionNoChange:
+			summary.NoChange++
+		case bean.PlanActionUnsupported:
+			summary.Unsupported++
+		}
+	}
+	return summary
+}
+
+// getPlanActions indexes the action of each plan item by its key
+func getPlanActions(plan *bean.ImportPlan) map[string]bean.PlanAction {
+	actions := make(map[string]bean.PlanAction, len(plan.Items))
+	for _, item := range plan.Items {
+		actions[planItemKey(item.Kind, item.Environment, item.Workflow, item.Name)] = item.Action
+	}
+	return actions
+}
+
+func isApplicable(action bean.PlanAction) bool {
+	return action == bean.PlanActionCreate || action == bean.PlanActionUpdate
+}
+
+func getLabelKeys(labels []*bean3.Label) []string {
+	keys := make([]string, 0, len(labels))
+	for _, label := range labels {
+		keys = append(keys, label.Key)
+	}
+	return keys
+}
+
+// getNewCdPipelineEnvironmentIds returns the environments of the cd pipelines the import creates
+func getNewCdPipelineEnvironmentIds(spec *bean.SnapshotSpec, environments map[string]*repository.Environment, actions map[string]bean.PlanAction) []int {
+	envIds := make([]int, 0)
+	for _, wf := range spec.Workflows {
+		for _, cd := range wf.CdPipelines {
+			if actions[planItemKey(bean.ItemKindCdPipeline, cd.Environment, wf.Name, cd.Name)] == bean.PlanActionCreate {
+				envIds = append(envIds, environments[cd.Environment].Id)
+			}
+		}
+	}
+	return envIds
+}
+
+// getImportedChartRefIds returns the charts of the deployment templates the import creates or updates
+func getImportedChartRefIds(spec *bean.SnapshotSpec, chartRefIds map[string]int, actions map[string]bean.PlanAction) []int {
+	ids := make([]int, 0)
+	add := func(template *bean.DeploymentTemplateSpec, environment string) {
+		if template != nil && isApplicable(actions[planItemKey(bean.ItemKindDeploymentTemplate, environment, "", "")]) {
+			ids = append(ids, chartRefIds[chartKey(template.ChartName, template.ChartVersion)])
+		}
+	}
+	add(spec.DeploymentTemplate, "")
+	for _, env := range spec.Environments {
+		add(env.DeploymentTemplate, env.Name)
+	}
+	return ids
+}
+
+// getImportedDockerRegistries returns the container registries of the build configurations the import creates or updates
+func getImportedDockerRegistries(spec *bean.SnapshotSpec, actions map[string]bean.PlanAction) []string {
+	registries := make([]string, 0)
+	if spec.BuildConfig != nil && isApplicable(actions[planItemKey(bean.ItemKindBuildConfig, "", "", "")]) {
+		registries = append(registries, spec.BuildConfig.DockerRegistry)
+	}
+	for _, wf := range spec.Workflows {
+		ciPipeline := wf.CiPipeline
+		if ciPipeline != nil && ciPipeline.BuildConfigOverride != nil && isApplicable(actions[planItemKey(bean.ItemKindCiPipeline, "", wf.Name, ciPipeline.Name)]) {
+			registries = append(registries, ciPipeline.BuildConfigOverride.DockerRegistry)
+		}
+	}
+	return registries
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appSnapshot
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/appSnapshot/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newSnapshot(spec *bean.SnapshotSpec) *bean.AppSnapshot {
+	return &bean.AppSnapshot{
+		ApiVersion: bean.SnapshotApiVersion,
+		Kind:       bean.SnapshotKind,
+		Metadata:   &bean.SnapshotMetadata{Name: "app", Project: "default"},
+		Spec:       spec,
+	}
+}
+
+func TestValidateSnapshot(t *testing.T) {
+	materials := []*bean.GitMaterialSpec{{CheckoutPath: "./"}}
+	tests := []struct {
+		name     string
+		snapshot *bean.AppSnapshot
+		wantErr  bool
+	}{
+		{name: "valid", snapshot: newSnapshot(&bean.SnapshotSpec{
+			GitMaterials: materials,
+			Workflows: []*bean.WorkflowSpec{{Name: "wf", CiPipeline: &bean.CiPipelineSpec{Name: "ci", Materials: []*bean.CiMaterialSpec{{CheckoutPath: "./"}}},
+				CdPipelines: []*bean.CdPipelineSpec{{Name: "dev", Environment: "dev"}, {Name: "prod", Environment: "prod", ParentEnvironment: "dev"}}}},
+		})},
+		{name: "wrong kind", snapshot: &bean.AppSnapshot{ApiVersion: bean.SnapshotApiVersion, Kind: "Job", Spec: &bean.SnapshotSpec{}}, wantErr: true},
+		{name: "duplicate checkout path", snapshot: newSnapshot(&bean.SnapshotSpec{GitMaterials: []*bean.GitMaterialSpec{{CheckoutPath: "./"}, {CheckoutPath: "./"}}}), wantErr: true},
+		{name: "unknown build context", snapshot: newSnapshot(&bean.SnapshotSpec{GitMaterials: materials, BuildConfig: &bean.BuildConfigSpec{BuildContextCheckoutPath: "./other"}}), wantErr: true},
+		{name: "duplicate secret", snapshot: newSnapshot(&bean.SnapshotSpec{Environments: []*bean.EnvironmentSpec{{Name: "dev", Secrets: []*bean.ConfigDataSpec{{Name: "s"}, {Name: "s"}}}}}), wantErr: true},
+		{name: "webhook and build pipeline", snapshot: newSnapshot(&bean.SnapshotSpec{GitMaterials: materials,
+			Workflows: []*bean.WorkflowSpec{{Name: "wf", Webhook: true, CiPipeline: &bean.CiPipelineSpec{Name: "ci", Materials: []*bean.CiMaterialSpec{{CheckoutPath: "./"}}}}}}), wantErr: true},
+		{name: "deployment without source", snapshot: newSnapshot(&bean.SnapshotSpec{
+			Workflows: []*bean.WorkflowSpec{{Name: "wf", CdPipelines: []*bean.CdPipelineSpec{{Name: "dev", Environment: "dev"}}}}}), wantErr: true},
+		{name: "unknown ci material", snapshot: newSnapshot(&bean.SnapshotSpec{GitMaterials: materials,
+			Workflows: []*bean.WorkflowSpec{{Name: "wf", CiPipeline: &bean.CiPipelineSpec{Name: "ci", Materials: []*bean.CiMaterialSpec{{CheckoutPath: "./other"}}}}}}), wantErr: true},
+		{name: "environment deployed twice", snapshot: newSnapshot(&bean.SnapshotSpec{Workflows: []*bean.WorkflowSpec{
+			{Name: "wf1", Webhook: true, CdPipelines: []*bean.CdPipelineSpec{{Name: "dev", Environment: "dev"}}},
+			{Name: "wf2", Webhook: true, CdPipelines: []*bean.CdPipelineSpec{{Name: "dev2", Environment: "dev"}}}}}), wantErr: true},
+		{name: "child before parent", snapshot: newSnapshot(&bean.SnapshotSpec{Workflows: []*bean.WorkflowSpec{{Name: "wf", Webhook: true,
+			CdPipelines: []*bean.CdPipelineSpec{{Name: "prod", Environment: "prod", ParentEnvironment: "dev"}, {Name: "dev", Environment: "dev"}}}}}), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSnapshot(tt.snapshot)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBuildPlan(t *testing.T) {
+	current := newSnapshot(&bean.SnapshotSpec{
+		GitMaterials:       []*bean.GitMaterialSpec{{CheckoutPath: "./", Url: "https://github.com/org/app.git"}},
+		DeploymentTemplate: &bean.DeploymentTemplateSpec{ChartName: "Deployment", ChartVersion: "4.18.0", Values: json.RawMessage(`{"replicaCount":1}`)},
+		ConfigMaps:         []*bean.ConfigDataSpec{{Name: "cm", Type: "environment", Data: json.RawMessage(`{"LOG_LEVEL":"info"}`)}},
+	})
+	desired := newSnapshot(&bean.SnapshotSpec{
+		GitMaterials:       []*bean.GitMaterialSpec{{CheckoutPath: "./", Url: "https://github.com/org/app.git"}},
+		DeploymentTemplate: &bean.DeploymentTemplateSpec{ChartName: "Deployment", ChartVersion: "4.18.0", Values: json.RawMessage(`{"replicaCount":2}`)},
+		ConfigMaps:         []*bean.ConfigDataSpec{{Name: "cm", Type: "environment", Data: json.RawMessage(`{"LOG_LEVEL":"info"}`)}},
+		Workflows:          []*bean.WorkflowSpec{{Name: "wf", Webhook: true, CdPipelines: []*bean.CdPipelineSpec{{Name: "dev", Environment: "dev"}}}},
+	})
+	plan, err := buildPlan(current, desired)
+	assert.NoError(t, err)
+	actions := getPlanActions(plan)
+	assert.Equal(t, bean.PlanActionNoChange, actions[planItemKey(bean.ItemKindApp, "", "", "app")])
+	assert.Equal(t, bean.PlanActionNoChange, actions[planItemKey(bean.ItemKindGitMaterial, "", "", "./")])
+	assert.Equal(t, bean.PlanActionUpdate, actions[planItemKey(bean.ItemKindDeploymentTemplate, "", "", "")])
+	assert.Equal(t, bean.PlanActionNoChange, actions[planItemKey(bean.ItemKindConfigMap, "", "", "cm")])
+	assert.Equal(t, bean.PlanActionCreate, actions[planItemKey(bean.ItemKindWorkflow, "", "wf", "wf")])
+	assert.Equal(t, bean.PlanActionCreate, actions[planItemKey(bean.ItemKindCdPipeline, "dev", "wf", "dev")])
+	assert.Equal(t, &bean.PlanSummary{Create: 2, Update: 1, NoChange: 3}, plan.Summary)
+	for _, item := range plan.Items {
+		if item.Kind == bean.ItemKindDeploymentTemplate {
+			assert.Equal(t, []*bean.FieldChange{{Path: "values.replicaCount", OldValue: float64(1), NewValue: float64(2)}}, item.Changes)
+		}
+	}
+
+	plan, err = buildPlan(nil, desired)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, plan.Summary.NoChange+plan.Summary.Update+plan.Summary.Unsupported)
+}
+
+func TestBuildPlanChartChange(t *testing.T) {
+	current := newSnapshot(&bean.SnapshotSpec{DeploymentTemplate: &bean.DeploymentTemplateSpec{ChartName: "Deployment", ChartVersion: "4.18.0"}})
+	desired := newSnapshot(&bean.SnapshotSpec{DeploymentTemplate: &bean.DeploymentTemplateSpec{ChartName: "Rollout Deployment", ChartVersion: "4.18.0"}})
+	plan, err := buildPlan(current, desired)
+	assert.NoError(t, err)
+	assert.Equal(t, bean.PlanActionUnsupported, getPlanActions(plan)[planItemKey(bean.ItemKindDeploymentTemplate, "", "", "")])
+	assert.Equal(t, 1, plan.Summary.Unsupported)
+}
+
+func TestBuildPlanMaskedSecrets(t *testing.T) {
+	current := newSnapshot(&bean.SnapshotSpec{Secrets: []*bean.ConfigDataSpec{{Name: "db", Type: "environment", Data: json.RawMessage(`{"PASSWORD":"s3cret","USER":"admin"}`)}}})
+	desired := newSnapshot(&bean.SnapshotSpec{Secrets: []*bean.ConfigDataSpec{
+		{Name: "db", Type: "environment", Data: json.RawMessage(`{"PASSWORD":"********","USER":"root"}`)},
+		{Name: "api", Type: "environment", Data: json.RawMessage(`{"TOKEN":"********"}`)},
+	}})
+	plan, err := buildPlan(current, desired)
+	assert.NoError(t, err)
+
+	// the masked password keeps its saved value and the changed user is reported without its values
+	assert.JSONEq(t, `{"PASSWORD":"s3cret","USER":"root"}`, string(desired.Spec.Secrets[0].Data))
+	actions := getPlanActions(plan)
+	assert.Equal(t, bean.PlanActionUpdate, actions[planItemKey(bean.ItemKindSecret, "", "", "db")])
+	assert.Equal(t, bean.PlanActionUnsupported, actions[planItemKey(bean.ItemKindSecret, "", "", "api")])
+	for _, item := range plan.Items {
+		if item.Kind == bean.ItemKindSecret && item.Name == "db" {
+			assert.Equal(t, []*bean.FieldChange{{Path: "data.USER", OldValue: bean.MaskedSecretValue, NewValue: bean.MaskedSecretValue}}, item.Changes)
+		}
+	}
+}
+
+func TestMaskSecretData(t *testing.T) {
+	masked, err := maskSecretData(json.RawMessage(`{"A":"1","B":"2"}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"A":"********","B":"********"}`, string(masked))
+
+	masked, err = maskSecretData(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, masked)
+}
+
+func TestNormalizeStage(t *testing.T) {
+	assert.Nil(t, normalizeStage(nil))
+	assert.Nil(t, normalizeStage(&pipelineBean.PipelineStageDto{Id: 3}))
+
+	stage := &pipelineBean.PipelineStageDto{Id: 3, Steps: []*pipelineBean.PipelineStageStepDto{{
+		Id: 4,
+		InlineStepDetail: &pipelineBean.InlineStepDetailDto{
+			InputVariables:   []*pipelineBean.StepVariableDto{{Id: 5, Name: "in"}},
+			ConditionDetails: []*pipelineBean.ConditionDetailDto{{Id: 6}},
+		},
+	}}}
+	stage = normalizeStage(stage)
+	assert.Equal(t, 0, stage.Id)
+	assert.Equal(t, 0, stage.Steps[0].Id)
+	assert.Equal(t, 0, stage.Steps[0].InlineStepDetail.InputVariables[0].Id)
+	assert.Equal(t, 0, stage.Steps[0].InlineStepDetail.ConditionDetails[0].Id)
+}
+
+func TestGetImportedGuardrailReferences(t *testing.T) {
+	spec := &bean.SnapshotSpec{
+		BuildConfig:        &bean.BuildConfigSpec{DockerRegistry: "docker-hub"},
+		DeploymentTemplate: &bean.DeploymentTemplateSpec{ChartName: "Deployment", ChartVersion: "4.20.0"},
+		Environments: []*bean.EnvironmentSpec{
+			{Name: "dev", DeploymentTemplate: &bean.DeploymentTemplateSpec{ChartName: "Deployment", ChartVersion: "4.19.0"}},
+			{Name: "prod", DeploymentTemplate: &bean.DeploymentTemplateSpec{ChartName: "Deployment", ChartVersion: "4.18.0"}},
+		},
+		Workflows: []*bean.WorkflowSpec{{Name: "wf",
+			CiPipeline:  &bean.CiPipelineSpec{Name: "ci", BuildConfigOverride: &bean.BuildConfigSpec{DockerRegistry: "ecr"}},
+			CdPipelines: []*bean.CdPipelineSpec{{Name: "dev", Environment: "dev"}, {Name: "prod", Environment: "prod"}}}},
+	}
+	actions := map[string]bean.PlanAction{
+		planItemKey(bean.ItemKindBuildConfig, "", "", ""):            bean.PlanActionNoChange,
+		planItemKey(bean.ItemKindCiPipeline, "", "wf", "ci"):         bean.PlanActionUpdate,
+		planItemKey(bean.ItemKindDeploymentTemplate, "", "", ""):     bean.PlanActionUpdate,
+		planItemKey(bean.ItemKindDeploymentTemplate, "dev", "", ""):  bean.PlanActionNoChange,
+		planItemKey(bean.ItemKindDeploymentTemplate, "prod", "", ""): bean.PlanActionCreate,
+		planItemKey(bean.ItemKindCdPipeline, "dev", "wf", "dev"):     bean.PlanActionNoChange,
+		planItemKey(bean.ItemKindCdPipeline, "prod", "wf", "prod"):   bean.PlanActionCreate,
+	}
+	chartRefIds := map[string]int{chartKey("Deployment", "4.20.0"): 20, chartKey("Deployment", "4.19.0"): 19, chartKey("Deployment", "4.18.0"): 18}
+	environments := map[string]*repository.Environment{"dev": {Id: 1}, "prod": {Id: 2}}
+	assert.Equal(t, []int{20, 18}, getImportedChartRefIds(spec, chartRefIds, actions))
+	assert.Equal(t, []string{"ecr"}, getImportedDockerRegistries(spec, actions))
+	assert.Equal(t, []int{2}, getNewCdPipelineEnvironmentIds(spec, environments, actions))
+}
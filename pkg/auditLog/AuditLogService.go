@@ -0,0 +1,191 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditLog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/apiToken"
+	"github.com/devtron-labs/devtron/pkg/auditLog/bean"
+	"github.com/devtron-labs/devtron/pkg/auditLog/repository"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userHelper "github.com/devtron-labs/devtron/pkg/auth/user/helper"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type AuditLogService interface {
+	// Record queues an event to be saved and streamed, it never blocks the audited request
+	Record(event *bean.AuditEvent)
+	GetEvents(filter *bean.AuditEventFilter) (*bean.AuditEventList, error)
+	ExportEvents(filter *bean.AuditEventFilter, format bean.ExportFormat, writer io.Writer) error
+}
+
+type AuditLogServiceImpl struct {
+	logger               *zap.SugaredLogger
+	auditEventRepository repository.AuditEventRepository
+	userRepository       userRepository.UserRepository
+	apiTokenRepository   apiToken.ApiTokenRepository
+	config               *bean.AuditLogConfig
+	events               chan *bean.AuditEvent
+	sinks                []auditSink
+}
+
+func NewAuditLogServiceImpl(logger *zap.SugaredLogger,
+	auditEventRepository repository.AuditEventRepository,
+	userRepository userRepository.UserRepository,
+	apiTokenRepository apiToken.ApiTokenRepository) (*AuditLogServiceImpl, error) {
+	config := &bean.AuditLogConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing audit log config", "err", err)
+		return nil, err
+	}
+	impl := &AuditLogServiceImpl{
+		logger:               logger,
+		auditEventRepository: auditEventRepository,
+		userRepository:       userRepository,
+		apiTokenRepository:   apiTokenRepository,
+		config:               config,
+		events:               make(chan *bean.AuditEvent, config.BufferSize),
+	}
+	if len(config.SyslogAddress) > 0 {
+		sink, err := newSyslogSink(config)
+		if err != nil {
+			logger.Errorw("error in connecting to audit log syslog", "address", config.SyslogAddress, "err", err)
+			return nil, err
+		}
+		impl.sinks = append(impl.sinks, sink)
+	}
+	if len(config.WebhookUrl) > 0 {
+		sink, err := newWebhookSink(config)
+		if err != nil {
+			logger.Errorw("error in creating audit log webhook", "err", err)
+			return nil, err
+		}
+		impl.sinks = append(impl.sinks, sink)
+	}
+	if config.Enabled {
+		go impl.processEvents()
+	}
+	return impl, nil
+}
+
+func (impl *AuditLogServiceImpl) Record(event *bean.AuditEvent) {
+	if !impl.config.Enabled {
+		return
+	}
+	select {
+	case impl.events <- event:
+	default:
+		impl.logger.Errorw("audit log buffer is full, dropping event", "method", event.Method, "path", event.Path, "emailId", event.EmailId)
+	}
+}
+
+func (impl *AuditLogServiceImpl) processEvents() {
+	for event := range impl.events {
+		impl.resolveActor(event)
+		model := toModel(event)
+		err := impl.auditEventRepository.Save(model)
+		if err != nil {
+			impl.logger.Errorw("error in saving audit event", "method", event.Method, "path", event.Path, "err", err)
+		}
+		event.Id = model.Id
+		for _, sink := range impl.sinks {
+			err = sink.Send(event)
+			if err != nil {
+				impl.logger.Errorw("error in streaming audit event", "sink", sink.Name(), "eventId", event.Id, "err", err)
+			}
+		}
+	}
+}
+
+// resolveActor sets the user and api token of the event from its email, off the request path. Events recorded
+// outside of an api call, like blocked terminal commands, come with the user and get its email instead
+func (impl *AuditLogServiceImpl) resolveActor(event *bean.AuditEvent) {
+	if len(event.EmailId) == 0 {
+		if event.UserId > 0 {
+			user, err := impl.userRepository.GetByIdIncludeDeleted(event.UserId)
+			if err == nil {
+				event.EmailId = user.EmailId
+			}
+		}
+		return
+	}
+	user, err := impl.userRepository.FetchActiveUserByEmail(event.EmailId)
+	if err == nil {
+		event.UserId = user.Id
+	}
+	if !strings.HasPrefix(strings.ToLower(event.EmailId), strings.ToLower(userBean.API_TOKEN_USER_EMAIL_PREFIX)) {
+		return
+	}
+	tokenName, err := userHelper.ExtractTokenNameFromEmail(event.EmailId)
+	if err != nil {
+		return
+	}
+	token, err := impl.apiTokenRepository.FindByName(tokenName)
+	if err == nil {
+		event.ApiTokenId = token.Id
+	}
+}
+
+func (impl *AuditLogServiceImpl) GetEvents(filter *bean.AuditEventFilter) (*bean.AuditEventList, error) {
+	models, totalCount, err := impl.auditEventRepository.FindByFilter(filter)
+	if err != nil {
+		impl.logger.Errorw("error in getting audit events", "filter", filter, "err", err)
+		return nil, err
+	}
+	events := make([]*bean.AuditEvent, 0, len(models))
+	for _, model := range models {
+		events = append(events, toDto(model))
+	}
+	return &bean.AuditEventList{Events: events, TotalCount: totalCount}, nil
+}
+
+func (impl *AuditLogServiceImpl) ExportEvents(filter *bean.AuditEventFilter, format bean.ExportFormat, writer io.Writer) error {
+	if format != bean.ExportFormatJson && format != bean.ExportFormatCsv {
+		errMsg := fmt.Sprintf("unsupported export format %s, use json or csv", format)
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	filter.Size = impl.config.ExportMaxRows
+	eventList, err := impl.GetEvents(filter)
+	if err != nil {
+		return err
+	}
+	if format == bean.ExportFormatJson {
+		return json.NewEncoder(writer).Encode(eventList.Events)
+	}
+	csvWriter := csv.NewWriter(writer)
+	err = csvWriter.Write(csvHeader)
+	if err != nil {
+		return err
+	}
+	for _, event := range eventList.Events {
+		err = csvWriter.Write(toCsvRow(event))
+		if err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
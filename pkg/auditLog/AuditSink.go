@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditLog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/auditLog/bean"
+	"log/syslog"
+	"net/http"
+	"time"
+)
+
+// auditSink streams audit events out of devtron, e.g. to a SIEM
+type auditSink interface {
+	Name() string
+	Send(event *bean.AuditEvent) error
+}
+
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+func newSyslogSink(config *bean.AuditLogConfig) (*syslogSink, error) {
+	writer, err := syslog.Dial(config.SyslogNetwork, config.SyslogAddress, syslog.LOG_INFO|syslog.LOG_AUTH, config.SyslogTag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{writer: writer}, nil
+}
+
+func (impl *syslogSink) Name() string {
+	return "syslog"
+}
+
+func (impl *syslogSink) Send(event *bean.AuditEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return impl.writer.Info(string(payload))
+}
+
+type webhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newWebhookSink(config *bean.AuditLogConfig) (*webhookSink, error) {
+	headers := make(map[string]string)
+	if len(config.WebhookHeaders) > 0 {
+		err := json.Unmarshal([]byte(config.WebhookHeaders), &headers)
+		if err != nil {
+			return nil, fmt.Errorf("invalid audit log webhook headers, %v", err)
+		}
+	}
+	return &webhookSink{url: config.WebhookUrl, headers: headers, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+func (impl *webhookSink) Name() string {
+	return "webhook"
+}
+
+func (impl *webhookSink) Send(event *bean.AuditEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, impl.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range impl.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := impl.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("audit log webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
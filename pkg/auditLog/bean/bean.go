@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type AuditLogConfig struct {
+	Enabled        bool   `env:"AUDIT_LOG_ENABLED" envDefault:"true" description:"Record an audit event for every mutating api call"`
+	BufferSize     int    `env:"AUDIT_LOG_BUFFER_SIZE" envDefault:"1000" description:"Audit events waiting to be saved, events are dropped when the buffer is full"`
+	ExportMaxRows  int    `env:"AUDIT_LOG_EXPORT_MAX_ROWS" envDefault:"10000" description:"Most audit events returned by an export"`
+	SyslogNetwork  string `env:"AUDIT_LOG_SYSLOG_NETWORK" envDefault:"udp" description:"Network of the syslog server audit events are streamed to, udp or tcp"`
+	SyslogAddress  string `env:"AUDIT_LOG_SYSLOG_ADDRESS" envDefault:"" description:"Address of the syslog server audit events are streamed to, events are not streamed to syslog when empty"`
+	SyslogTag      string `env:"AUDIT_LOG_SYSLOG_TAG" envDefault:"devtron-audit" description:"Tag of audit events streamed to syslog"`
+	WebhookUrl     string `env:"AUDIT_LOG_WEBHOOK_URL" envDefault:"" description:"Url audit events are posted to as json, events are not posted when empty"`
+	WebhookHeaders string `env:"AUDIT_LOG_WEBHOOK_HEADERS" envDefault:"" description:"Headers sent with audit events posted to the webhook, as a json object"`
+}
+
+type Action string
+
+const (
+	ActionCreate Action = "CREATE"
+	ActionUpdate Action = "UPDATE"
+	ActionDelete Action = "DELETE"
+	// ActionExec is a command run in a web terminal, only blocked commands are audited
+	ActionExec Action = "EXEC"
+)
+
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "SUCCESS"
+	OutcomeFailure Outcome = "FAILURE"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatJson ExportFormat = "json"
+	ExportFormatCsv  ExportFormat = "csv"
+)
+
+// AuditEvent is a mutating api call. Resource is the route template of the call and ResourceIds its path variables,
+// BeforeHash and AfterHash are set by handlers which know the state of the resource around the change
+type AuditEvent struct {
+	Id           int               `json:"id"`
+	UserId       int32             `json:"userId"`
+	EmailId      string            `json:"emailId"`
+	ApiTokenId   int               `json:"apiTokenId,omitempty"`
+	Method       string            `json:"method"`
+	Path         string            `json:"path"`
+	Resource     string            `json:"resource"`
+	ResourceIds  map[string]string `json:"resourceIds,omitempty"`
+	Action       Action            `json:"action"`
+	Outcome      Outcome           `json:"outcome"`
+	StatusCode   int               `json:"statusCode"`
+	ClientIp     string            `json:"clientIp"`
+	RequestHash  string            `json:"requestHash,omitempty"`
+	BeforeHash   string            `json:"beforeHash,omitempty"`
+	AfterHash    string            `json:"afterHash,omitempty"`
+	DurationInMs int64             `json:"durationInMs"`
+	CreatedOn    time.Time         `json:"createdOn"`
+}
+
+// AuditEventFilter matches events of all the set fields, Resource matches part of the route template or path
+type AuditEventFilter struct {
+	UserId   int32
+	EmailId  string
+	Resource string
+	Action   Action
+	Outcome  Outcome
+	From     *time.Time
+	To       *time.Time
+	Offset   int
+	Size     int
+}
+
+type AuditEventList struct {
+	Events     []*AuditEvent `json:"events"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// StateChange is attached to the context of audited requests for handlers to record the state around the change
+type StateChange struct {
+	BeforeHash string
+	AfterHash  string
+}
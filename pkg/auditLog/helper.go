@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditLog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/auditLog/bean"
+	"github.com/devtron-labs/devtron/pkg/auditLog/repository"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type stateChangeKey struct{}
+
+var csvHeader = []string{"id", "createdOn", "userId", "emailId", "apiTokenId", "method", "path", "resource", "action",
+	"outcome", "statusCode", "clientIp", "requestHash", "beforeHash", "afterHash", "durationInMs"}
+
+// GetAction returns the action of mutating http methods, other methods are not audited
+func GetAction(method string) (bean.Action, bool) {
+	switch method {
+	case http.MethodPost:
+		return bean.ActionCreate, true
+	case http.MethodPut, http.MethodPatch:
+		return bean.ActionUpdate, true
+	case http.MethodDelete:
+		return bean.ActionDelete, true
+	}
+	return "", false
+}
+
+func GetOutcome(statusCode int) bean.Outcome {
+	if statusCode >= http.StatusBadRequest {
+		return bean.OutcomeFailure
+	}
+	return bean.OutcomeSuccess
+}
+
+func HashPayload(payload []byte) string {
+	if len(payload) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// WithStateChange attaches a state change to the context of an audited request, the middleware reads it after the handler
+func WithStateChange(ctx context.Context) (context.Context, *bean.StateChange) {
+	stateChange := &bean.StateChange{}
+	return context.WithValue(ctx, stateChangeKey{}, stateChange), stateChange
+}
+
+// RecordStateChange records hashes of the resource before and after the change of an audited request,
+// nil states are left out. It does nothing for requests which are not audited
+func RecordStateChange(ctx context.Context, before interface{}, after interface{}) {
+	stateChange, ok := ctx.Value(stateChangeKey{}).(*bean.StateChange)
+	if !ok {
+		return
+	}
+	stateChange.BeforeHash = hashState(before)
+	stateChange.AfterHash = hashState(after)
+}
+
+func hashState(state interface{}) string {
+	if state == nil {
+		return ""
+	}
+	payload, err := json.Marshal(state)
+	// a nil pointer is a state that could not be read
+	if err != nil || string(payload) == "null" {
+		return ""
+	}
+	return HashPayload(payload)
+}
+
+func toModel(event *bean.AuditEvent) *repository.AuditEvent {
+	return &repository.AuditEvent{
+		UserId:       event.UserId,
+		EmailId:      event.EmailId,
+		ApiTokenId:   event.ApiTokenId,
+		Method:       event.Method,
+		Path:         event.Path,
+		Resource:     event.Resource,
+		ResourceIds:  event.ResourceIds,
+		Action:       string(event.Action),
+		Outcome:      string(event.Outcome),
+		StatusCode:   event.StatusCode,
+		ClientIp:     event.ClientIp,
+		RequestHash:  event.RequestHash,
+		BeforeHash:   event.BeforeHash,
+		AfterHash:    event.AfterHash,
+		DurationInMs: event.DurationInMs,
+		CreatedOn:    event.CreatedOn,
+	}
+}
+
+func toDto(model *repository.AuditEvent) *bean.AuditEvent {
+	return &bean.AuditEvent{
+		Id:           model.Id,
+		UserId:       model.UserId,
+		EmailId:      model.EmailId,
+		ApiTokenId:   model.ApiTokenId,
+		Method:       model.Method,
+		Path:         model.Path,
+		Resource:     model.Resource,
+		ResourceIds:  model.ResourceIds,
+		Action:       bean.Action(model.Action),
+		Outcome:      bean.Outcome(model.Outcome),
+		StatusCode:   model.StatusCode,
+		ClientIp:     model.ClientIp,
+		RequestHash:  model.RequestHash,
+		BeforeHash:   model.BeforeHash,
+		AfterHash:    model.AfterHash,
+		DurationInMs: model.DurationInMs,
+		CreatedOn:    model.CreatedOn,
+	}
+}
+
+func toCsvRow(event *bean.AuditEvent) []string {
+	return []string{
+		strconv.Itoa(event.Id),
+		event.CreatedOn.Format(time.RFC3339),
+		strconv.Itoa(int(event.UserId)),
+		event.EmailId,
+		strconv.Itoa(event.ApiTokenId),
+		event.Method,
+		event.Path,
+		event.Resource,
+		string(event.Action),
+		string(event.Outcome),
+		strconv.Itoa(event.StatusCode),
+		event.ClientIp,
+		event.RequestHash,
+		event.BeforeHash,
+		event.AfterHash,
+		strconv.FormatInt(event.DurationInMs, 10),
+	}
+}
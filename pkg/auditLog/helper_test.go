@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditLog
+
+import (
+	"context"
+	"github.com/devtron-labs/devtron/pkg/auditLog/bean"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestGetAction(t *testing.T) {
+	tests := []struct {
+		method       string
+		wantAction   bean.Action
+		wantMutating bool
+	}{
+		{method: http.MethodGet},
+		{method: http.MethodPost, wantAction: bean.ActionCreate, wantMutating: true},
+		{method: http.MethodPut, wantAction: bean.ActionUpdate, wantMutating: true},
+		{method: http.MethodPatch, wantAction: bean.ActionUpdate, wantMutating: true},
+		{method: http.MethodDelete, wantAction: bean.ActionDelete, wantMutating: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			action, mutating := GetAction(tt.method)
+			assert.Equal(t, tt.wantAction, action)
+			assert.Equal(t, tt.wantMutating, mutating)
+		})
+	}
+}
+
+func TestGetOutcome(t *testing.T) {
+	assert.Equal(t, bean.OutcomeSuccess, GetOutcome(0))
+	assert.Equal(t, bean.OutcomeSuccess, GetOutcome(http.StatusCreated))
+	assert.Equal(t, bean.OutcomeFailure, GetOutcome(http.StatusForbidden))
+	assert.Equal(t, bean.OutcomeFailure, GetOutcome(http.StatusInternalServerError))
+}
+
+func TestRecordStateChange(t *testing.T) {
+	// requests which are not audited are ignored
+	RecordStateChange(context.Background(), "before", "after")
+
+	ctx, stateChange := WithStateChange(context.Background())
+	RecordStateChange(ctx, nil, map[string]string{"name": "app"})
+	assert.Empty(t, stateChange.BeforeHash)
+	assert.Equal(t, HashPayload([]byte(`{"name":"app"}`)), stateChange.AfterHash)
+	assert.Len(t, stateChange.AfterHash, 64)
+	assert.Empty(t, HashPayload(nil))
+
+	var missing *bean.AuditEvent
+	RecordStateChange(ctx, missing, nil)
+	assert.Empty(t, stateChange.BeforeHash)
+	assert.Empty(t, stateChange.AfterHash)
+}
+
+func TestToCsvRow(t *testing.T) {
+	row := toCsvRow(&bean.AuditEvent{Id: 1, UserId: 2, Method: http.MethodDelete, Action: bean.ActionDelete, Outcome: bean.OutcomeSuccess, StatusCode: 200})
+	assert.Len(t, row, len(csvHeader))
+	assert.Equal(t, "1", row[0])
+	assert.Equal(t, "DELETE", row[8])
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auditLog/bean"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+	"time"
+)
+
+// AuditEvent is never updated, it has no audit columns of its own
+type AuditEvent struct {
+	tableName    struct{}          `sql:"audit_event" pg:",discard_unknown_columns"`
+	Id           int               `sql:"id,pk"`
+	UserId       int32             `sql:"user_id"`
+	EmailId      string            `sql:"email_id"`
+	ApiTokenId   int               `sql:"api_token_id"`
+	Method       string            `sql:"method,notnull"`
+	Path         string            `sql:"path,notnull"`
+	Resource     string            `sql:"resource,notnull"`
+	ResourceIds  map[string]string `sql:"resource_ids"`
+	Action       string            `sql:"action,notnull"`
+	Outcome      string            `sql:"outcome,notnull"`
+	StatusCode   int               `sql:"status_code"`
+	ClientIp     string            `sql:"client_ip"`
+	RequestHash  string            `sql:"request_hash"`
+	BeforeHash   string            `sql:"before_hash"`
+	AfterHash    string            `sql:"after_hash"`
+	DurationInMs int64             `sql:"duration_in_ms"`
+	CreatedOn    time.Time         `sql:"created_on,notnull"`
+}
+
+type AuditEventRepository interface {
+	Save(model *AuditEvent) error
+	// FindByFilter returns a page of events, latest first, along with the count of all matching events
+	FindByFilter(filter *bean.AuditEventFilter) ([]*AuditEvent, int, error)
+}
+
+type AuditEventRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewAuditEventRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *AuditEventRepositoryImpl {
+	return &AuditEventRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *AuditEventRepositoryImpl) Save(model *AuditEvent) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *AuditEventRepositoryImpl) FindByFilter(filter *bean.AuditEventFilter) ([]*AuditEvent, int, error) {
+	var models []*AuditEvent
+	query := impl.dbConnection.Model(&models)
+	applyFilter(query, filter)
+	query = query.Order("id DESC").Offset(filter.Offset)
+	if filter.Size > 0 {
+		query = query.Limit(filter.Size)
+	}
+	totalCount, err := query.SelectAndCount()
+	return models, totalCount, err
+}
+
+func applyFilter(query *orm.Query, filter *bean.AuditEventFilter) {
+	if filter.UserId > 0 {
+		query.Where("user_id = ?", filter.UserId)
+	}
+	if len(filter.EmailId) > 0 {
+		query.Where("email_id = ?", filter.EmailId)
+	}
+	if len(filter.Resource) > 0 {
+		resource := "%" + filter.Resource + "%"
+		query.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.WhereOr("resource ILIKE ?", resource).WhereOr("path ILIKE ?", resource), nil
+		})
+	}
+	if len(filter.Action) > 0 {
+		query.Where("action = ?", filter.Action)
+	}
+	if len(filter.Outcome) > 0 {
+		query.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.From != nil {
+		query.Where("created_on >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query.Where("created_on < ?", *filter.To)
+	}
+}
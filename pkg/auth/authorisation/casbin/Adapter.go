@@ -292,6 +292,22 @@ func GetUserByRole(role string) ([]string, error) {
 	return e.GetUsersForRole(role)
 }
 
+// GetAllPolicies returns every p policy as sub, res, act, obj, eft
+func GetAllPolicies() ([][]string, error) {
+	if isV2() {
+		return e2.GetPolicy()
+	}
+	return e.GetPolicy(), nil
+}
+
+// GetAllGroupingPolicies returns every g policy as sub, role
+func GetAllGroupingPolicies() ([][]string, error) {
+	if isV2() {
+		return e2.GetGroupingPolicy()
+	}
+	return e.GetGroupingPolicy(), nil
+}
+
 func RemovePoliciesByRoles(roles string) bool {
 	roles = strings.ToLower(roles)
 	var policyResponse bool
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package casbin
+
+import (
+	"strings"
+)
+
+// ApiTokenScope narrows down what an api token can do. A token with scopes is allowed an action only when
+// its roles and at least one of its scopes allow it, objects are matched by part like policies e.g. "team/*"
+type ApiTokenScope struct {
+	Resource string `json:"resource" validate:"required"`
+	Action   string `json:"action" validate:"required"`
+	Object   string `json:"object" validate:"required"`
+}
+
+// ApiTokenScopeReader reads the scopes saved on the token row of an api token user on every enforcement,
+// users other than api tokens and tokens without scopes have none
+type ApiTokenScopeReader interface {
+	GetApiTokenScopes(emailId string) ([]ApiTokenScope, error)
+}
+
+// IsAllowedByApiTokenScopes returns true when there are no scopes
+func IsAllowedByApiTokenScopes(scopes []ApiTokenScope, resource string, action string, resourceItem string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, scope := range scopes {
+		if matchScopeValue(resource, scope.Resource) && matchScopeValue(action, scope.Action) &&
+			MatchKeyByPart(strings.ToLower(resourceItem), strings.ToLower(scope.Object)) {
+			return true
+		}
+	}
+	return false
+}
+
+func filterByApiTokenScopes(scopes []ApiTokenScope, resource string, action string, result map[string]bool) map[string]bool {
+	if len(scopes) == 0 {
+		return result
+	}
+	filtered := make(map[string]bool, len(result))
+	for resourceItem, allowed := range result {
+		filtered[resourceItem] = allowed && IsAllowedByApiTokenScopes(scopes, resource, action, resourceItem)
+	}
+	return filtered
+}
+
+func matchScopeValue(value string, scopeValue string) bool {
+	return scopeValue == "*" || strings.EqualFold(value, scopeValue)
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package casbin
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+type apiTokenScopeReaderStub struct {
+	scopes []ApiTokenScope
+	err    error
+}
+
+func (impl apiTokenScopeReaderStub) GetApiTokenScopes(emailId string) ([]ApiTokenScope, error) {
+	return impl.scopes, impl.err
+}
+
+func TestIsAllowedByApiTokenScopes(t *testing.T) {
+	scopes := []ApiTokenScope{
+		{Resource: ResourceApplications, Action: ActionTrigger, Object: "Team/app-x"},
+		{Resource: "*", Action: ActionGet, Object: "team/*"},
+	}
+
+	tests := []struct {
+		name         string
+		scopes       []ApiTokenScope
+		resource     string
+		action       string
+		resourceItem string
+		want         bool
+	}{
+		{name: "scoped trigger", scopes: scopes, resource: ResourceApplications, action: ActionTrigger, resourceItem: "team/app-x", want: true},
+		{name: "trigger of another app", scopes: scopes, resource: ResourceApplications, action: ActionTrigger, resourceItem: "team/app-y", want: false},
+		{name: "action not in scopes", scopes: scopes, resource: ResourceApplications, action: ActionCreate, resourceItem: "team/app-x", want: false},
+		{name: "wildcard resource", scopes: scopes, resource: ResourceGlobal, action: ActionGet, resourceItem: "team/app-y", want: true},
+		{name: "token without scopes", scopes: nil, resource: ResourceApplications, action: ActionCreate, resourceItem: "team/app-x", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsAllowedByApiTokenScopes(tt.scopes, tt.resource, tt.action, tt.resourceItem))
+		})
+	}
+
+	filtered := filterByApiTokenScopes(scopes, ResourceApplications, ActionTrigger, map[string]bool{"team/app-x": true, "team/app-y": true})
+	assert.Equal(t, map[string]bool{"team/app-x": true, "team/app-y": false}, filtered)
+}
+
+func TestEnforceByEmailWithApiTokenScopes(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	t.Run("scopes could not be loaded", func(t *testing.T) {
+		enforcer := &EnforcerImpl{logger: logger, apiTokenScopeReader: apiTokenScopeReaderStub{err: errors.New("connection refused")}}
+		assert.False(t, enforcer.EnforceByEmail("API-TOKEN:deployer", ResourceApplications, ActionTrigger, "team/app-x"))
+		assert.Empty(t, enforcer.EnforceByEmailInBatch("API-TOKEN:deployer", ResourceApplications, ActionTrigger, []string{"team/app-x"}))
+	})
+	t.Run("action outside of scopes", func(t *testing.T) {
+		enforcer := &EnforcerImpl{logger: logger, apiTokenScopeReader: apiTokenScopeReaderStub{scopes: []ApiTokenScope{
+			{Resource: ResourceApplications, Action: ActionGet, Object: "team/*"},
+		}}}
+		assert.False(t, enforcer.EnforceByEmail("API-TOKEN:deployer", ResourceApplications, ActionTrigger, "team/app-x"))
+	})
+}
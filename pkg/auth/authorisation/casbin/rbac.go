@@ -53,6 +53,7 @@ func NewEnforcerImpl(
 	enforcer *casbin.SyncedEnforcer,
 	enforcerV2 *casbinv2.SyncedEnforcer,
 	sessionManager *middleware.SessionManager,
+	apiTokenScopeReader ApiTokenScopeReader,
 	logger *zap.SugaredLogger) (*EnforcerImpl, error) {
 	lock := make(map[string]*CacheData)
 	batchRequestLock := make(map[string]*sync.Mutex)
@@ -61,7 +62,8 @@ func NewEnforcerImpl(
 		return nil, err
 	}
 	enf := &EnforcerImpl{lockCacheData: lock, enforcerRWLock: &sync.RWMutex{}, batchRequestLock: batchRequestLock, enforcerConfig: enforcerConfig,
-		Cache: getEnforcerCache(logger, enforcerConfig), Enforcer: enforcer, EnforcerV2: enforcerV2, logger: logger, SessionManager: sessionManager}
+		Cache: getEnforcerCache(logger, enforcerConfig), Enforcer: enforcer, EnforcerV2: enforcerV2, logger: logger, SessionManager: sessionManager,
+		apiTokenScopeReader: apiTokenScopeReader}
 	setEnforcerImpl(enf)
 	return enf, nil
 }
@@ -116,6 +118,8 @@ type EnforcerImpl struct {
 	logger         *zap.SugaredLogger
 	enforcerConfig *EnforcerConfig
 	enforcerRWLock *sync.RWMutex
+	// scopes are read per enforcement so that changes made on any instance apply right away
+	apiTokenScopeReader ApiTokenScopeReader
 }
 
 // Enforce is a wrapper around casbin.Enforce to additionally enforce a default role and a custom
@@ -125,6 +129,14 @@ func (e *EnforcerImpl) Enforce(token string, resource string, action string, res
 }
 
 func (e *EnforcerImpl) EnforceByEmail(emailId string, resource string, action string, resourceItem string) bool {
+	scopes, err := e.apiTokenScopeReader.GetApiTokenScopes(emailId)
+	if err != nil {
+		e.logger.Errorw("error in getting api token scopes, denying request", "emailId", emailId, "err", err)
+		return false
+	}
+	if !IsAllowedByApiTokenScopes(scopes, resource, action, resourceItem) {
+		return false
+	}
 	return e.enforceByEmail(strings.ToLower(emailId), resource, action, strings.ToLower(resourceItem))
 }
 
@@ -184,6 +196,11 @@ func (e *EnforcerImpl) enforceByEmailInBatchSync(wg *sync.WaitGroup, mutex *sync
 }
 
 func (e *EnforcerImpl) EnforceByEmailInBatch(emailId string, resource string, action string, vals []string) map[string]bool {
+	scopes, err := e.apiTokenScopeReader.GetApiTokenScopes(emailId)
+	if err != nil {
+		e.logger.Errorw("error in getting api token scopes, denying request", "emailId", emailId, "err", err)
+		return make(map[string]bool)
+	}
 	emailId = strings.ToLower(emailId)
 	var totalTimeGap int64 = 0
 	var maxTimegap int64 = 0
@@ -251,7 +268,7 @@ func (e *EnforcerImpl) EnforceByEmailInBatch(emailId string, resource string, ac
 		"action", action, "totalElapsedTime", totalTimeGap, "maxTimegap", maxTimegap, "minTimegap",
 		minTimegap, "avgTimegap", avgTimegap, "size", len(vals), "batchSize", batchSize, "cached", e.Cache != nil && dataCached)
 
-	return result
+	return filterByApiTokenScopes(scopes, resource, action, result)
 }
 
 func (e *EnforcerImpl) getBatchRequestLock(emailId string) *sync.Mutex {
@@ -0,0 +1,563 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jitAccess
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/jitAccess/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/jitAccess/repository"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type JitAccessService interface {
+	// CreateRequest saves a pending request of the user for a role filter, nothing is granted until it is approved
+	CreateRequest(request *bean.JitAccessRequest) (*bean.JitAccessRequestDto, error)
+	GetAll(filter *bean.ListFilter) ([]*bean.JitAccessRequestDto, error)
+	GetById(id int) (*bean.JitAccessRequestDto, error)
+	GetAudit(id int) ([]*bean.JitAccessAuditDto, error)
+	// Approve grants the role filter to the user, the access expires after the requested duration from now
+	Approve(request *bean.ActionRequest) (*bean.JitAccessRequestDto, error)
+	Reject(request *bean.ActionRequest) (*bean.JitAccessRequestDto, error)
+	// Cancel withdraws a pending request, only the requester can cancel it
+	Cancel(request *bean.ActionRequest) (*bean.JitAccessRequestDto, error)
+	// Revoke takes back granted access before it expires
+	Revoke(request *bean.ActionRequest) (*bean.JitAccessRequestDto, error)
+	RevokeExpired()
+}
+
+type JitAccessServiceImpl struct {
+	logger                     *zap.SugaredLogger
+	jitAccessRequestRepository repository.JitAccessRequestRepository
+	jitAccessAuditRepository   repository.JitAccessAuditRepository
+	userService                user.UserService
+	userRepository             userRepository.UserRepository
+	userAuthRepository         userRepository.UserAuthRepository
+	enforcer                   casbin.Enforcer
+	config                     *bean.JitAccessConfig
+	// grantLock serialises grants and revokes, granted roles are computed from the roles of the user around the update
+	grantLock *sync.Mutex
+}
+
+func NewJitAccessServiceImpl(logger *zap.SugaredLogger,
+	jitAccessRequestRepository repository.JitAccessRequestRepository,
+	jitAccessAuditRepository repository.JitAccessAuditRepository,
+	userService user.UserService,
+	userRepository userRepository.UserRepository,
+	userAuthRepository userRepository.UserAuthRepository,
+	enforcer casbin.Enforcer,
+	cronLogger *cron2.CronLoggerImpl) (*JitAccessServiceImpl, error) {
+	config := &bean.JitAccessConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing jit access config", "err", err)
+		return nil, err
+	}
+	impl := &JitAccessServiceImpl{
+		logger:                     logger,
+		jitAccessRequestRepository: jitAccessRequestRepository,
+		jitAccessAuditRepository:   jitAccessAuditRepository,
+		userService:                userService,
+		userRepository:             userRepository,
+		userAuthRepository:         userAuthRepository,
+		enforcer:                   enforcer,
+		config:                     config,
+		grantLock:                  &sync.Mutex{},
+	}
+	expiryCron := cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	_, err = expiryCron.AddFunc(config.CronSchedule, impl.RevokeExpired)
+	if err != nil {
+		logger.Errorw("error in adding jit access expiry cron", "schedule", config.CronSchedule, "err", err)
+		return nil, err
+	}
+	expiryCron.Start()
+	return impl, nil
+}
+
+// allowAll is passed as manager auth to the user service, the approver is authorised for the role filter before the grant
+func allowAll(resource, token, object string) bool {
+	return true
+}
+
+func (impl *JitAccessServiceImpl) CreateRequest(request *bean.JitAccessRequest) (*bean.JitAccessRequestDto, error) {
+	err := validateAccessRequest(request, impl.config.MaxDurationMinutes)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	roleFilterKey, err := getRoleFilterKey(request.RoleFilter)
+	if err != nil {
+		return nil, err
+	}
+	openRequests, err := impl.jitAccessRequestRepository.FindOpenByUserId(request.UserId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching open jit access requests", "userId", request.UserId, "err", err)
+		return nil, err
+	}
+	for _, openRequest := range openRequests {
+		dto, err := toRequestDto(openRequest, "", "")
+		if err != nil {
+			return nil, err
+		}
+		openKey, err := getRoleFilterKey(dto.RoleFilter)
+		if err != nil {
+			return nil, err
+		}
+		if openKey == roleFilterKey {
+			errMsg := fmt.Sprintf("request %d for the same access is %s", openRequest.Id, openRequest.Status)
+			return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+		}
+	}
+	model := &repository.JitAccessRequest{
+		UserId:          request.UserId,
+		RoleFilter:      roleFilterKey,
+		DurationMinutes: request.DurationMinutes,
+		Reason:          request.Reason,
+		Status:          string(bean.RequestStatusPending),
+		AuditLog:        sql.NewDefaultAuditLog(request.UserId),
+	}
+	err = impl.jitAccessRequestRepository.Save(model)
+	if err != nil {
+		impl.logger.Errorw("error in saving jit access request", "userId", request.UserId, "err", err)
+		return nil, err
+	}
+	impl.saveAudit(model.Id, bean.AuditActionRequested, request.Reason, request.UserId)
+	return impl.toDto(model, make(map[int32]string))
+}
+
+func (impl *JitAccessServiceImpl) GetAll(filter *bean.ListFilter) ([]*bean.JitAccessRequestDto, error) {
+	models, err := impl.jitAccessRequestRepository.FindAll(string(filter.Status), filter.UserId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching jit access requests", "filter", filter, "err", err)
+		return nil, err
+	}
+	emailIds := make(map[int32]string)
+	res := make([]*bean.JitAccessRequestDto, 0, len(models))
+	for _, model := range models {
+		dto, err := impl.toDto(model, emailIds)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, dto)
+	}
+	return res, nil
+}
+
+func (impl *JitAccessServiceImpl) GetById(id int) (*bean.JitAccessRequestDto, error) {
+	model, err := impl.getRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	return impl.toDto(model, make(map[int32]string))
+}
+
+func (impl *JitAccessServiceImpl) GetAudit(id int) ([]*bean.JitAccessAuditDto, error) {
+	if _, err := impl.getRequest(id); err != nil {
+		return nil, err
+	}
+	models, err := impl.jitAccessAuditRepository.FindByRequestId(id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching jit access audit", "requestId", id, "err", err)
+		return nil, err
+	}
+	emailIds := make(map[int32]string)
+	res := make([]*bean.JitAccessAuditDto, 0, len(models))
+	for _, model := range models {
+		actionBy, err := impl.getEmailId(model.CreatedBy, emailIds)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, &bean.JitAccessAuditDto{
+			Id:        model.Id,
+			RequestId: model.JitAccessRequestId,
+			Action:    bean.AuditAction(model.Action),
+			ActionBy:  actionBy,
+			Comment:   model.Comment,
+			ActionOn:  model.CreatedOn,
+		})
+	}
+	return res, nil
+}
+
+func (impl *JitAccessServiceImpl) Approve(request *bean.ActionRequest) (*bean.JitAccessRequestDto, error) {
+	impl.grantLock.Lock()
+	defer impl.grantLock.Unlock()
+	model, err := impl.getRequestInStatus(request.Id, bean.RequestStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	if model.UserId == request.UserId {
+		errMsg := "a request can't be approved by its requester"
+		return nil, util.NewApiError(http.StatusForbidden, errMsg, errMsg)
+	}
+	// the request is claimed before the grant, so that concurrent approvals, also on other replicas, grant it once
+	now := time.Now()
+	model.Status = string(bean.RequestStatusActive)
+	model.ApprovedBy = request.UserId
+	model.ApprovedOn = now
+	model.ExpiresOn = now.Add(time.Duration(model.DurationMinutes) * time.Minute)
+	model.UpdatedOn = now
+	model.UpdatedBy = request.UserId
+	updated, err := impl.jitAccessRequestRepository.UpdateInStatus(model, bean.RequestStatusPending)
+	if err != nil {
+		impl.logger.Errorw("error in updating approved jit access request", "requestId", model.Id, "err", err)
+		return nil, err
+	}
+	if !updated {
+		errMsg := fmt.Sprintf("jit access request %d is not %s anymore", model.Id, bean.RequestStatusPending)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	grantedRoleIds, err := impl.grant(model, request.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in granting jit access", "requestId", model.Id, "err", err)
+		impl.reopen(model, request.UserId)
+		return nil, err
+	}
+	model.GrantedRoleIds = grantedRoleIds
+	updated, err = impl.jitAccessRequestRepository.UpdateInStatus(model, bean.RequestStatusActive)
+	if err != nil || !updated {
+		// the grant is not tracked without the update, it is taken back so that it does not outlive the request
+		impl.logger.Errorw("error in saving jit access grant", "requestId", model.Id, "updated", updated, "err", err)
+		if revokeErr := impl.revokeRoles(model.UserId, grantedRoleIds, model.Id); revokeErr != nil {
+			impl.logger.Errorw("error in taking back jit access grant", "requestId", model.Id, "err", revokeErr)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		errMsg := fmt.Sprintf("jit access request %d was closed while being approved", model.Id)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	impl.saveAudit(model.Id, bean.AuditActionApproved, request.Comment, request.UserId)
+	impl.syncCasbin()
+	return impl.toDto(model, make(map[int32]string))
+}
+
+// reopen moves a claimed request back to pending when its grant fails, so that it can be approved again
+func (impl *JitAccessServiceImpl) reopen(model *repository.JitAccessRequest, userId int32) {
+	model.Status = string(bean.RequestStatusPending)
+	model.ApprovedBy = 0
+	model.ApprovedOn = time.Time{}
+	model.ExpiresOn = time.Time{}
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+	if _, err := impl.jitAccessRequestRepository.UpdateInStatus(model, bean.RequestStatusActive); err != nil {
+		impl.logger.Errorw("error in reopening jit access request", "requestId", model.Id, "err", err)
+	}
+}
+
+func (impl *JitAccessServiceImpl) Reject(request *bean.ActionRequest) (*bean.JitAccessRequestDto, error) {
+	model, err := impl.getRequestInStatus(request.Id, bean.RequestStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	return impl.close(model, bean.RequestStatusRejected, bean.AuditActionRejected, request.Comment, request.UserId)
+}
+
+func (impl *JitAccessServiceImpl) Cancel(request *bean.ActionRequest) (*bean.JitAccessRequestDto, error) {
+	model, err := impl.getRequestInStatus(request.Id, bean.RequestStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	if model.UserId != request.UserId {
+		errMsg := "only the requester can cancel a request"
+		return nil, util.NewApiError(http.StatusForbidden, errMsg, errMsg)
+	}
+	return impl.close(model, bean.RequestStatusCancelled, bean.AuditActionCancelled, request.Comment, request.UserId)
+}
+
+func (impl *JitAccessServiceImpl) Revoke(request *bean.ActionRequest) (*bean.JitAccessRequestDto, error) {
+	impl.grantLock.Lock()
+	defer impl.grantLock.Unlock()
+	model, err := impl.getRequestInStatus(request.Id, bean.RequestStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.revokeRoles(model.UserId, model.GrantedRoleIds, model.Id)
+	if err != nil {
+		impl.logger.Errorw("error in revoking jit access", "requestId", model.Id, "err", err)
+		return nil, err
+	}
+	res, err := impl.close(model, bean.RequestStatusRevoked, bean.AuditActionRevoked, request.Comment, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	impl.syncCasbin()
+	return res, nil
+}
+
+// RevokeExpired revokes access of active requests past their expiry, failed revokes are retried on the next run
+func (impl *JitAccessServiceImpl) RevokeExpired() {
+	models, err := impl.jitAccessRequestRepository.FindActiveExpiredBefore(time.Now())
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching expired jit access requests", "err", err)
+		return
+	}
+	if len(models) == 0 {
+		return
+	}
+	impl.grantLock.Lock()
+	defer impl.grantLock.Unlock()
+	revoked := 0
+	for _, model := range models {
+		err = impl.revokeRoles(model.UserId, model.GrantedRoleIds, model.Id)
+		if err != nil {
+			impl.logger.Errorw("error in revoking expired jit access", "requestId", model.Id, "err", err)
+			continue
+		}
+		_, err = impl.close(model, bean.RequestStatusExpired, bean.AuditActionExpired, "", userBean.SystemUserId)
+		if err != nil {
+			continue
+		}
+		revoked++
+	}
+	if revoked > 0 {
+		impl.syncCasbin()
+	}
+}
+
+// grant adds the role filter of the request to the user and returns the roles the user did not have before
+func (impl *JitAccessServiceImpl) grant(model *repository.JitAccessRequest, approverId int32) ([]int, error) {
+	userInfo, err := impl.userService.GetById(model.UserId)
+	if err != nil && !util.IsErrNoRows(err) {
+		return nil, err
+	}
+	if util.IsErrNoRows(err) {
+		errMsg := fmt.Sprintf("user %d of the request is not active", model.UserId)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	if userInfo.SuperAdmin {
+		errMsg := "the requester is a super admin already"
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	dto, err := toRequestDto(model, "", "")
+	if err != nil {
+		return nil, err
+	}
+	roleIdsBefore, err := impl.getRoleIds(model.UserId)
+	if err != nil {
+		return nil, err
+	}
+	otherGrantedRoleIds, err := impl.getOtherGrantedRoleIds(model.UserId, model.Id)
+	if err != nil {
+		return nil, err
+	}
+	userInfo.RoleFilters = append(userInfo.RoleFilters, *dto.RoleFilter)
+	userInfo.UserId = approverId
+	_, err = impl.userService.UpdateUser(userInfo, "", nil, allowAll)
+	if err != nil {
+		return nil, err
+	}
+	roleIdsAfter, err := impl.getRoleIds(model.UserId)
+	if err != nil {
+		return nil, err
+	}
+	impl.enforcer.InvalidateCache(userInfo.EmailId)
+	return getGrantedRoleIds(roleIdsBefore, roleIdsAfter, otherGrantedRoleIds), nil
+}
+
+// revokeRoles removes the roles granted by a request, roles also granted by other active requests of the user are kept
+func (impl *JitAccessServiceImpl) revokeRoles(userId int32, grantedRoleIds []int, requestId int) error {
+	otherGrantedRoleIds, err := impl.getOtherGrantedRoleIds(userId, requestId)
+	if err != nil {
+		return err
+	}
+	roleIds := getRevocableRoleIds(grantedRoleIds, otherGrantedRoleIds)
+	if len(roleIds) == 0 {
+		return nil
+	}
+	userModel, err := impl.userRepository.GetByIdIncludeDeleted(userId)
+	if err != nil {
+		return err
+	}
+	userRoleModels, err := impl.userAuthRepository.GetUserRoleMappingByUserId(userId)
+	if err != nil && !util.IsErrNoRows(err) {
+		return err
+	}
+	revocable := make(map[int]bool, len(roleIds))
+	for _, roleId := range roleIds {
+		revocable[roleId] = true
+	}
+	mappingIds := make([]int, 0, len(roleIds))
+	for _, userRoleModel := range userRoleModels {
+		if revocable[userRoleModel.RoleId] {
+			mappingIds = append(mappingIds, userRoleModel.Id)
+		}
+	}
+	if len(mappingIds) == 0 {
+		// the roles were removed from the user meanwhile
+		return nil
+	}
+	roles, err := impl.userAuthRepository.GetRolesByIds(roleIds)
+	if err != nil {
+		return err
+	}
+	tx, err := impl.userRepository.GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = impl.userAuthRepository.DeleteUserRoleMappingByIds(mappingIds, tx)
+	if err != nil {
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	policies := make([]casbin.Policy, 0, len(roles))
+	for _, role := range roles {
+		policies = append(policies, casbin.Policy{Type: "g", Sub: casbin.Subject(userModel.EmailId), Obj: casbin.Object(role.Role)})
+	}
+	casbin.RemovePolicy(policies)
+	casbin.LoadPolicy()
+	impl.enforcer.InvalidateCache(userModel.EmailId)
+	return nil
+}
+
+func (impl *JitAccessServiceImpl) getRoleIds(userId int32) ([]int, error) {
+	userRoleModels, err := impl.userAuthRepository.GetUserRoleMappingByUserId(userId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching roles of user", "userId", userId, "err", err)
+		return nil, err
+	}
+	roleIds := make([]int, 0, len(userRoleModels))
+	for _, userRoleModel := range userRoleModels {
+		roleIds = append(roleIds, userRoleModel.RoleId)
+	}
+	return roleIds, nil
+}
+
+// getOtherGrantedRoleIds returns roles granted by active requests of the user other than the given request
+func (impl *JitAccessServiceImpl) getOtherGrantedRoleIds(userId int32, requestId int) ([]int, error) {
+	openRequests, err := impl.jitAccessRequestRepository.FindOpenByUserId(userId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching open jit access requests", "userId", userId, "err", err)
+		return nil, err
+	}
+	roleIds := make([]int, 0)
+	for _, openRequest := range openRequests {
+		if openRequest.Id != requestId && openRequest.Status == string(bean.RequestStatusActive) {
+			roleIds = append(roleIds, openRequest.GrantedRoleIds...)
+		}
+	}
+	return roleIds, nil
+}
+
+// close moves the request out of the status it was read in, it fails with a conflict when a concurrent action moved it first
+func (impl *JitAccessServiceImpl) close(model *repository.JitAccessRequest, status bean.RequestStatus, action bean.AuditAction, comment string, userId int32) (*bean.JitAccessRequestDto, error) {
+	fromStatus := bean.RequestStatus(model.Status)
+	now := time.Now()
+	model.Status = string(status)
+	model.ClosedOn = now
+	model.UpdatedOn = now
+	model.UpdatedBy = userId
+	updated, err := impl.jitAccessRequestRepository.UpdateInStatus(model, fromStatus)
+	if err != nil {
+		impl.logger.Errorw("error in updating jit access request", "requestId", model.Id, "status", status, "err", err)
+		return nil, err
+	}
+	if !updated {
+		errMsg := fmt.Sprintf("jit access request %d is not %s anymore", model.Id, fromStatus)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	impl.saveAudit(model.Id, action, comment, userId)
+	return impl.toDto(model, make(map[int32]string))
+}
+
+func (impl *JitAccessServiceImpl) saveAudit(requestId int, action bean.AuditAction, comment string, userId int32) {
+	err := impl.jitAccessAuditRepository.Save(&repository.JitAccessAudit{
+		JitAccessRequestId: requestId,
+		Action:             string(action),
+		Comment:            comment,
+		AuditLog:           sql.NewDefaultAuditLog(userId),
+	})
+	if err != nil {
+		impl.logger.Errorw("error in saving jit access audit", "requestId", requestId, "action", action, "err", err)
+	}
+}
+
+// syncCasbin re-syncs the policies of orchestrator roles, roles created for a grant get their policies through it
+func (impl *JitAccessServiceImpl) syncCasbin() {
+	if _, err := impl.userService.SyncOrchestratorToCasbin(); err != nil {
+		impl.logger.Errorw("error in syncing orchestrator roles to casbin", "err", err)
+	}
+}
+
+func (impl *JitAccessServiceImpl) getRequest(id int) (*repository.JitAccessRequest, error) {
+	model, err := impl.jitAccessRequestRepository.FindById(id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching jit access request", "id", id, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) {
+		errMsg := fmt.Sprintf("jit access request %d not found", id)
+		return nil, util.NewApiError(http.StatusNotFound, errMsg, errMsg)
+	}
+	return model, nil
+}
+
+func (impl *JitAccessServiceImpl) getRequestInStatus(id int, status bean.RequestStatus) (*repository.JitAccessRequest, error) {
+	model, err := impl.getRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	if model.Status != string(status) {
+		errMsg := fmt.Sprintf("jit access request %d is %s, not %s", id, model.Status, status)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	return model, nil
+}
+
+func (impl *JitAccessServiceImpl) getEmailId(userId int32, emailIds map[int32]string) (string, error) {
+	if emailId, ok := emailIds[userId]; ok {
+		return emailId, nil
+	}
+	emailId, err := impl.userService.GetEmailById(userId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching email of user", "userId", userId, "err", err)
+		return "", err
+	}
+	emailIds[userId] = emailId
+	return emailId, nil
+}
+
+func (impl *JitAccessServiceImpl) toDto(model *repository.JitAccessRequest, emailIds map[int32]string) (*bean.JitAccessRequestDto, error) {
+	emailId, err := impl.getEmailId(model.UserId, emailIds)
+	if err != nil {
+		return nil, err
+	}
+	approvedBy := ""
+	if model.ApprovedBy > 0 {
+		approvedBy, err = impl.getEmailId(model.ApprovedBy, emailIds)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return toRequestDto(model, emailId, approvedBy)
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jitAccess
+
+import (
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/jitAccess/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/jitAccess/repository"
+	"github.com/devtron-labs/devtron/pkg/auth/jitAccess/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestApprove(t *testing.T) {
+	t.Run("approval lost to a concurrent action grants nothing", func(tt *testing.T) {
+		requestRepository := mocks.NewJitAccessRequestRepository(tt)
+		impl := &JitAccessServiceImpl{logger: zap.NewNop().Sugar(), jitAccessRequestRepository: requestRepository, grantLock: &sync.Mutex{}}
+		requestRepository.On("FindById", 7).Return(&repository.JitAccessRequest{Id: 7, UserId: 2, DurationMinutes: 60,
+			Status: string(bean.RequestStatusPending)}, nil)
+		requestRepository.On("UpdateInStatus", mock.MatchedBy(func(model *repository.JitAccessRequest) bool {
+			return model.Status == string(bean.RequestStatusActive) && model.ApprovedBy == 3
+		}), bean.RequestStatusPending).Return(false, nil)
+		_, err := impl.Approve(&bean.ActionRequest{Id: 7, UserId: 3})
+		apiErr, ok := err.(*util.ApiError)
+		if assert.True(tt, ok) {
+			assert.Equal(tt, http.StatusConflict, apiErr.HttpStatusCode)
+		}
+	})
+}
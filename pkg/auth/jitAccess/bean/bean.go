@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"github.com/devtron-labs/devtron/api/bean"
+	"time"
+)
+
+type JitAccessConfig struct {
+	CronSchedule       string `env:"JIT_ACCESS_EXPIRY_CRON" envDefault:"* * * * *" description:"Schedule of the job revoking expired just in time access"`
+	MaxDurationMinutes int    `env:"JIT_ACCESS_MAX_DURATION_MINUTES" envDefault:"480" description:"Longest duration just in time access can be requested for"`
+}
+
+type RequestStatus string
+
+const (
+	RequestStatusPending   RequestStatus = "PENDING"
+	RequestStatusActive    RequestStatus = "ACTIVE"
+	RequestStatusRejected  RequestStatus = "REJECTED"
+	RequestStatusCancelled RequestStatus = "CANCELLED"
+	RequestStatusRevoked   RequestStatus = "REVOKED"
+	RequestStatusExpired   RequestStatus = "EXPIRED"
+)
+
+type AuditAction string
+
+const (
+	AuditActionRequested AuditAction = "REQUESTED"
+	AuditActionApproved  AuditAction = "APPROVED"
+	AuditActionRejected  AuditAction = "REJECTED"
+	AuditActionCancelled AuditAction = "CANCELLED"
+	AuditActionRevoked   AuditAction = "REVOKED"
+	AuditActionExpired   AuditAction = "EXPIRED"
+)
+
+type JitAccessRequest struct {
+	RoleFilter      *bean.RoleFilter `json:"roleFilter" validate:"required"`
+	DurationMinutes int              `json:"durationMinutes" validate:"required,min=1"`
+	Reason          string           `json:"reason" validate:"required,max=1000"`
+	UserId          int32            `json:"-"`
+}
+
+// ActionRequest approves, rejects, cancels or revokes a request
+type ActionRequest struct {
+	Id      int    `json:"-"`
+	Comment string `json:"comment" validate:"max=1000"`
+	UserId  int32  `json:"-"`
+}
+
+type ListFilter struct {
+	Status RequestStatus
+	// UserId lists requests of the user only when set
+	UserId int32
+}
+
+type JitAccessRequestDto struct {
+	Id              int              `json:"id"`
+	UserId          int32            `json:"userId"`
+	EmailId         string           `json:"emailId"`
+	RoleFilter      *bean.RoleFilter `json:"roleFilter"`
+	DurationMinutes int              `json:"durationMinutes"`
+	Reason          string           `json:"reason"`
+	Status          RequestStatus    `json:"status"`
+	RequestedOn     time.Time        `json:"requestedOn"`
+	ApprovedBy      string           `json:"approvedBy,omitempty"`
+	ApprovedOn      *time.Time       `json:"approvedOn,omitempty"`
+	ExpiresOn       *time.Time       `json:"expiresOn,omitempty"`
+	// ClosedOn is when the request was rejected, cancelled, revoked or expired
+	ClosedOn *time.Time `json:"closedOn,omitempty"`
+}
+
+type JitAccessAuditDto struct {
+	Id        int         `json:"id"`
+	RequestId int         `json:"requestId"`
+	Action    AuditAction `json:"action"`
+	ActionBy  string      `json:"actionBy"`
+	Comment   string      `json:"comment,omitempty"`
+	ActionOn  time.Time   `json:"actionOn"`
+}
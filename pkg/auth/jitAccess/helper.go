@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jitAccess
+
+import (
+	"encoding/json"
+	"fmt"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/jitAccess/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/jitAccess/repository"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"sort"
+	"time"
+)
+
+func validateAccessRequest(request *bean.JitAccessRequest, maxDurationMinutes int) error {
+	if request.DurationMinutes > maxDurationMinutes {
+		return fmt.Errorf("access can't be requested for more than %d minutes", maxDurationMinutes)
+	}
+	roleFilter := request.RoleFilter
+	if len(roleFilter.Action) == 0 {
+		return fmt.Errorf("action of the role filter is required")
+	}
+	switch roleFilter.Entity {
+	case userBean.CLUSTER_ENTITIY:
+		if len(roleFilter.Cluster) == 0 {
+			return fmt.Errorf("cluster of the role filter is required")
+		}
+	case userBean.CHART_GROUP_ENTITY:
+	default:
+		if len(roleFilter.Team) == 0 {
+			return fmt.Errorf("project of the role filter is required")
+		}
+	}
+	return nil
+}
+
+// getRoleFilterKey identifies equal role filters, an empty entity is the apps entity
+func getRoleFilterKey(roleFilter *apiBean.RoleFilter) (string, error) {
+	filter := *roleFilter
+	if len(filter.Entity) == 0 {
+		filter.Entity = userBean.ENTITY_APPS
+	}
+	key, err := json.Marshal(filter)
+	return string(key), err
+}
+
+// getGrantedRoleIds returns roles the user has after the grant which are not permanent roles of the user,
+// roles granted by other active requests are not permanent
+func getGrantedRoleIds(roleIdsBefore, roleIdsAfter, otherGrantedRoleIds []int) []int {
+	permanent := make(map[int]bool, len(roleIdsBefore))
+	for _, roleId := range roleIdsBefore {
+		permanent[roleId] = true
+	}
+	for _, roleId := range otherGrantedRoleIds {
+		delete(permanent, roleId)
+	}
+	granted := make([]int, 0)
+	for _, roleId := range roleIdsAfter {
+		if !permanent[roleId] {
+			granted = append(granted, roleId)
+		}
+	}
+	sort.Ints(granted)
+	return granted
+}
+
+// getRevocableRoleIds returns roles granted by a request which no other active request granted too
+func getRevocableRoleIds(grantedRoleIds, otherGrantedRoleIds []int) []int {
+	kept := make(map[int]bool, len(otherGrantedRoleIds))
+	for _, roleId := range otherGrantedRoleIds {
+		kept[roleId] = true
+	}
+	revocable := make([]int, 0, len(grantedRoleIds))
+	for _, roleId := range grantedRoleIds {
+		if !kept[roleId] {
+			revocable = append(revocable, roleId)
+		}
+	}
+	return revocable
+}
+
+func toRequestDto(model *repository.JitAccessRequest, emailId, approvedBy string) (*bean.JitAccessRequestDto, error) {
+	roleFilter := &apiBean.RoleFilter{}
+	if err := json.Unmarshal([]byte(model.RoleFilter), roleFilter); err != nil {
+		return nil, err
+	}
+	return &bean.JitAccessRequestDto{
+		Id:              model.Id,
+		UserId:          model.UserId,
+		EmailId:         emailId,
+		RoleFilter:      roleFilter,
+		DurationMinutes: model.DurationMinutes,
+		Reason:          model.Reason,
+		Status:          bean.RequestStatus(model.Status),
+		RequestedOn:     model.CreatedOn,
+		ApprovedBy:      approvedBy,
+		ApprovedOn:      toTimePtr(model.ApprovedOn),
+		ExpiresOn:       toTimePtr(model.ExpiresOn),
+		ClosedOn:        toTimePtr(model.ClosedOn),
+	}, nil
+}
+
+func toTimePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
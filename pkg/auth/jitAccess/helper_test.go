@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jitAccess
+
+import (
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/jitAccess/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidateAccessRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *bean.JitAccessRequest
+		wantErr bool
+	}{
+		{name: "app role", request: &bean.JitAccessRequest{DurationMinutes: 60, RoleFilter: &apiBean.RoleFilter{Team: "devtron", EntityName: "app", Environment: "prod", Action: "admin"}}},
+		{name: "cluster role", request: &bean.JitAccessRequest{DurationMinutes: 60, RoleFilter: &apiBean.RoleFilter{Entity: "cluster", Cluster: "default_cluster", Action: "view"}}},
+		{name: "longer than max", request: &bean.JitAccessRequest{DurationMinutes: 600, RoleFilter: &apiBean.RoleFilter{Team: "devtron", Action: "admin"}}, wantErr: true},
+		{name: "without action", request: &bean.JitAccessRequest{DurationMinutes: 60, RoleFilter: &apiBean.RoleFilter{Team: "devtron"}}, wantErr: true},
+		{name: "app role without project", request: &bean.JitAccessRequest{DurationMinutes: 60, RoleFilter: &apiBean.RoleFilter{EntityName: "app", Action: "admin"}}, wantErr: true},
+		{name: "cluster role without cluster", request: &bean.JitAccessRequest{DurationMinutes: 60, RoleFilter: &apiBean.RoleFilter{Entity: "cluster", Action: "view"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAccessRequest(tt.request, 480)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGetRoleFilterKey(t *testing.T) {
+	withoutEntity, err := getRoleFilterKey(&apiBean.RoleFilter{Team: "devtron", Action: "admin"})
+	assert.NoError(t, err)
+	withEntity, err := getRoleFilterKey(&apiBean.RoleFilter{Entity: "apps", Team: "devtron", Action: "admin"})
+	assert.NoError(t, err)
+	other, err := getRoleFilterKey(&apiBean.RoleFilter{Team: "devtron", Action: "view"})
+	assert.NoError(t, err)
+	assert.Equal(t, withoutEntity, withEntity)
+	assert.NotEqual(t, withEntity, other)
+}
+
+func TestGetGrantedRoleIds(t *testing.T) {
+	tests := []struct {
+		name                string
+		before, after       []int
+		otherGrantedRoleIds []int
+		want                []int
+	}{
+		{name: "new roles", before: []int{1, 2}, after: []int{1, 2, 4, 3}, want: []int{3, 4}},
+		{name: "already permanent", before: []int{1, 2}, after: []int{1, 2}, want: []int{}},
+		{name: "granted by another request", before: []int{1, 2}, after: []int{1, 2}, otherGrantedRoleIds: []int{2}, want: []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getGrantedRoleIds(tt.before, tt.after, tt.otherGrantedRoleIds))
+		})
+	}
+}
+
+func TestGetRevocableRoleIds(t *testing.T) {
+	assert.Equal(t, []int{1, 3}, getRevocableRoleIds([]int{1, 2, 3}, []int{2, 5}))
+	assert.Equal(t, []int{}, getRevocableRoleIds(nil, []int{2}))
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// JitAccessAudit is an entry of the history of a request, entries are never updated
+type JitAccessAudit struct {
+	tableName          struct{} `sql:"jit_access_audit" pg:",discard_unknown_columns"`
+	Id                 int      `sql:"id,pk"`
+	JitAccessRequestId int      `sql:"jit_access_request_id,notnull"`
+	Action             string   `sql:"action,notnull"`
+	Comment            string   `sql:"comment"`
+	sql.AuditLog
+}
+
+type JitAccessAuditRepository interface {
+	Save(model *JitAccessAudit) error
+	FindByRequestId(requestId int) ([]*JitAccessAudit, error)
+}
+
+type JitAccessAuditRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewJitAccessAuditRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *JitAccessAuditRepositoryImpl {
+	return &JitAccessAuditRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *JitAccessAuditRepositoryImpl) Save(model *JitAccessAudit) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *JitAccessAuditRepositoryImpl) FindByRequestId(requestId int) ([]*JitAccessAudit, error) {
+	var models []*JitAccessAudit
+	err := impl.dbConnection.Model(&models).
+		Where("jit_access_request_id = ?", requestId).
+		Order("id").
+		Select()
+	return models, err
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auth/jitAccess/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type JitAccessRequest struct {
+	tableName struct{} `sql:"jit_access_request" pg:",discard_unknown_columns"`
+	Id        int      `sql:"id,pk"`
+	UserId    int32    `sql:"user_id,notnull"`
+	// RoleFilter is the requested role filter, in json
+	RoleFilter      string    `sql:"role_filter,notnull"`
+	DurationMinutes int       `sql:"duration_minutes,notnull"`
+	Reason          string    `sql:"reason,notnull"`
+	Status          string    `sql:"status,notnull"`
+	ApprovedBy      int32     `sql:"approved_by"`
+	ApprovedOn      time.Time `sql:"approved_on"`
+	ExpiresOn       time.Time `sql:"expires_on"`
+	ClosedOn        time.Time `sql:"closed_on"`
+	// GrantedRoleIds are the roles the user got through this request, roles the user already had are not revoked with it
+	GrantedRoleIds []int `sql:"granted_role_ids" pg:",array"`
+	sql.AuditLog
+}
+
+type JitAccessRequestRepository interface {
+	Save(model *JitAccessRequest) error
+	// UpdateInStatus saves the request only while it is still in the given status, it returns false when
+	// a concurrent action moved the request out of it
+	UpdateInStatus(model *JitAccessRequest, status bean.RequestStatus) (bool, error)
+	FindById(id int) (*JitAccessRequest, error)
+	FindAll(status string, userId int32) ([]*JitAccessRequest, error)
+	FindOpenByUserId(userId int32) ([]*JitAccessRequest, error)
+	FindActiveExpiredBefore(before time.Time) ([]*JitAccessRequest, error)
+}
+
+type JitAccessRequestRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewJitAccessRequestRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *JitAccessRequestRepositoryImpl {
+	return &JitAccessRequestRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *JitAccessRequestRepositoryImpl) Save(model *JitAccessRequest) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *JitAccessRequestRepositoryImpl) UpdateInStatus(model *JitAccessRequest, status bean.RequestStatus) (bool, error) {
+	res, err := impl.dbConnection.Model(model).
+		WherePK().
+		Where("status = ?", status).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (impl *JitAccessRequestRepositoryImpl) FindById(id int) (*JitAccessRequest, error) {
+	model := &JitAccessRequest{}
+	err := impl.dbConnection.Model(model).Where("id = ?", id).Select()
+	return model, err
+}
+
+func (impl *JitAccessRequestRepositoryImpl) FindAll(status string, userId int32) ([]*JitAccessRequest, error) {
+	var models []*JitAccessRequest
+	query := impl.dbConnection.Model(&models)
+	if len(status) > 0 {
+		query = query.Where("status = ?", status)
+	}
+	if userId > 0 {
+		query = query.Where("user_id = ?", userId)
+	}
+	err := query.Order("id DESC").Select()
+	return models, err
+}
+
+// FindOpenByUserId returns pending and active requests of the user
+func (impl *JitAccessRequestRepositoryImpl) FindOpenByUserId(userId int32) ([]*JitAccessRequest, error) {
+	var models []*JitAccessRequest
+	err := impl.dbConnection.Model(&models).
+		Where("user_id = ?", userId).
+		Where("status IN (?)", pg.In([]bean.RequestStatus{bean.RequestStatusPending, bean.RequestStatusActive})).
+		Select()
+	return models, err
+}
+
+func (impl *JitAccessRequestRepositoryImpl) FindActiveExpiredBefore(before time.Time) ([]*JitAccessRequest, error) {
+	var models []*JitAccessRequest
+	err := impl.dbConnection.Model(&models).
+		Where("status = ?", bean.RequestStatusActive).
+		Where("expires_on <= ?", before).
+		Order("expires_on").
+		Select()
+	return models, err
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacExplainer
+
+import (
+	"fmt"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/rbacExplainer/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+// RbacExplainerService answers who can do what from the loaded casbin policies, following the auth model:
+// a subject inherits the roles of its groups, and a request is allowed when a policy allows it and none denies it
+type RbacExplainerService interface {
+	// GetPermissions lists the effective policies of the user with the role and groups granting each
+	GetPermissions(emailId string) (*bean.UserPermissions, error)
+	// Check explains the decision on a request of the user
+	Check(request *bean.CheckRequest) (*bean.CheckResult, error)
+	// GetSubjects lists the users, api tokens and groups having a policy matching the request
+	GetSubjects(resource, action, object string) (*bean.SubjectsResult, error)
+}
+
+type RbacExplainerServiceImpl struct {
+	logger         *zap.SugaredLogger
+	userRepository repository.UserRepository
+}
+
+func NewRbacExplainerServiceImpl(logger *zap.SugaredLogger, userRepository repository.UserRepository) *RbacExplainerServiceImpl {
+	return &RbacExplainerServiceImpl{
+		logger:         logger,
+		userRepository: userRepository,
+	}
+}
+
+func (impl *RbacExplainerServiceImpl) GetPermissions(emailId string) (*bean.UserPermissions, error) {
+	subject, err := impl.getSubject(emailId)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := impl.getPolicyGraph()
+	if err != nil {
+		return nil, err
+	}
+	grants := graph.getGrants(subject, nil)
+	superAdmin := false
+	for _, grant := range grants {
+		if grant.Role == apiBean.SUPERADMIN {
+			superAdmin = true
+			break
+		}
+	}
+	return &bean.UserPermissions{EmailId: subject, SuperAdmin: superAdmin, Permissions: grants}, nil
+}
+
+func (impl *RbacExplainerServiceImpl) Check(request *bean.CheckRequest) (*bean.CheckResult, error) {
+	subject, err := impl.getSubject(request.EmailId)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := impl.getPolicyGraph()
+	if err != nil {
+		return nil, err
+	}
+	resource, action, object := normalizeRequest(request.Resource, request.Action, request.Object)
+	allows, denials := splitByEffect(graph.getGrants(subject, getMatcher(resource, action, object)))
+	return &bean.CheckResult{
+		EmailId:  subject,
+		Resource: resource,
+		Action:   action,
+		Object:   object,
+		Allowed:  len(allows) > 0 && len(denials) == 0,
+		Reason:   getCheckReason(allows, denials),
+		Grants:   allows,
+		Denials:  denials,
+	}, nil
+}
+
+func (impl *RbacExplainerServiceImpl) GetSubjects(resource, action, object string) (*bean.SubjectsResult, error) {
+	graph, err := impl.getPolicyGraph()
+	if err != nil {
+		return nil, err
+	}
+	resource, action, object = normalizeRequest(resource, action, object)
+	return &bean.SubjectsResult{
+		Resource: resource,
+		Action:   action,
+		Object:   object,
+		Subjects: graph.getSubjects(getMatcher(resource, action, object)),
+	}, nil
+}
+
+// getSubject returns the casbin subject of an active user, subjects are lower cased emails
+func (impl *RbacExplainerServiceImpl) getSubject(emailId string) (string, error) {
+	user, err := impl.userRepository.FetchActiveUserByEmail(emailId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user", "emailId", emailId, "err", err)
+		return "", err
+	}
+	if user.Id == 0 {
+		errMsg := fmt.Sprintf("active user %s not found", emailId)
+		return "", util.NewApiError(http.StatusNotFound, errMsg, errMsg)
+	}
+	return strings.ToLower(user.EmailId), nil
+}
+
+func (impl *RbacExplainerServiceImpl) getPolicyGraph() (*policyGraph, error) {
+	policies, err := casbin.GetAllPolicies()
+	if err != nil {
+		impl.logger.Errorw("error in fetching casbin policies", "err", err)
+		return nil, err
+	}
+	groupingPolicies, err := casbin.GetAllGroupingPolicies()
+	if err != nil {
+		impl.logger.Errorw("error in fetching casbin grouping policies", "err", err)
+		return nil, err
+	}
+	return newPolicyGraph(policies, groupingPolicies), nil
+}
+
+// normalizeRequest lower cases the request as policies are saved lower cased
+func normalizeRequest(resource, action, object string) (string, string, string) {
+	return strings.ToLower(resource), strings.ToLower(action), strings.ToLower(object)
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+type SubjectType string
+
+const (
+	SubjectTypeUser     SubjectType = "user"
+	SubjectTypeApiToken SubjectType = "apiToken"
+	SubjectTypeGroup    SubjectType = "group"
+)
+
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+
+	RolePrefix     = "role:"
+	GroupPrefix    = "group:"
+	ApiTokenPrefix = "api-token:"
+
+	// MaxHierarchyLevel bounds role inheritance chains, as the casbin role manager does
+	MaxHierarchyLevel = 10
+)
+
+// Grant is a policy reaching a subject, Path goes from the subject through its groups to the role holding the policy
+type Grant struct {
+	Resource string   `json:"resource"`
+	Action   string   `json:"action"`
+	Object   string   `json:"object"`
+	Effect   string   `json:"effect"`
+	Role     string   `json:"role"`
+	Path     []string `json:"path"`
+}
+
+type UserPermissions struct {
+	EmailId     string   `json:"emailId"`
+	SuperAdmin  bool     `json:"superAdmin"`
+	Permissions []*Grant `json:"permissions"`
+}
+
+type CheckRequest struct {
+	EmailId  string `json:"emailId"`
+	Resource string `json:"resource" validate:"required"`
+	Action   string `json:"action" validate:"required"`
+	Object   string `json:"object" validate:"required"`
+}
+
+// CheckResult explains an enforce decision, access is allowed when some policy allows it and none denies it
+type CheckResult struct {
+	EmailId  string   `json:"emailId"`
+	Resource string   `json:"resource"`
+	Action   string   `json:"action"`
+	Object   string   `json:"object"`
+	Allowed  bool     `json:"allowed"`
+	Reason   string   `json:"reason"`
+	Grants   []*Grant `json:"grants"`
+	Denials  []*Grant `json:"denials"`
+}
+
+type SubjectAccess struct {
+	Subject string      `json:"subject"`
+	Type    SubjectType `json:"type"`
+	Allowed bool        `json:"allowed"`
+	Grants  []*Grant    `json:"grants"`
+	Denials []*Grant    `json:"denials"`
+}
+
+type SubjectsResult struct {
+	Resource string           `json:"resource"`
+	Action   string           `json:"action"`
+	Object   string           `json:"object"`
+	Subjects []*SubjectAccess `json:"subjects"`
+}
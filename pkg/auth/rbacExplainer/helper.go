@@ -0,0 +1,233 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacExplainer
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/rbacExplainer/bean"
+	"sort"
+	"strings"
+)
+
+type policy struct {
+	resource string
+	action   string
+	object   string
+	effect   string
+}
+
+// policyGraph mirrors the casbin model, g policies link subjects to groups and roles and p policies hang off roles
+type policyGraph struct {
+	parents  map[string][]string
+	children map[string][]string
+	policies map[string][]*policy
+}
+
+func newPolicyGraph(policies, groupingPolicies [][]string) *policyGraph {
+	graph := &policyGraph{
+		parents:  make(map[string][]string),
+		children: make(map[string][]string),
+		policies: make(map[string][]*policy),
+	}
+	for _, rule := range groupingPolicies {
+		if len(rule) < 2 {
+			continue
+		}
+		graph.parents[rule[0]] = append(graph.parents[rule[0]], rule[1])
+		graph.children[rule[1]] = append(graph.children[rule[1]], rule[0])
+	}
+	for _, rule := range policies {
+		if len(rule) < 4 {
+			continue
+		}
+		effect := bean.EffectAllow
+		if len(rule) > 4 && len(rule[4]) > 0 {
+			effect = rule[4]
+		}
+		graph.policies[rule[0]] = append(graph.policies[rule[0]], &policy{resource: rule[1], action: rule[2], object: rule[3], effect: effect})
+	}
+	return graph
+}
+
+// getPaths returns the paths from the subject to every node it inherits from, the subject itself included
+func (graph *policyGraph) getPaths(subject string, edges map[string][]string) map[string][][]string {
+	paths := make(map[string][][]string)
+	var walk func(node string, path []string)
+	walk = func(node string, path []string) {
+		path = append(path[:len(path):len(path)], node)
+		paths[node] = append(paths[node], path)
+		if len(path) > bean.MaxHierarchyLevel {
+			return
+		}
+		for _, next := range edges[node] {
+			if !contains(path, next) {
+				walk(next, path)
+			}
+		}
+	}
+	walk(subject, nil)
+	return paths
+}
+
+// getGrants returns every policy reaching the subject, once per path, filtered by matches when set
+func (graph *policyGraph) getGrants(subject string, matches func(p *policy) bool) []*bean.Grant {
+	grants := make([]*bean.Grant, 0)
+	for node, nodePaths := range graph.getPaths(subject, graph.parents) {
+		for _, p := range graph.policies[node] {
+			if matches != nil && !matches(p) {
+				continue
+			}
+			for _, path := range nodePaths {
+				grants = append(grants, newGrant(p, node, path))
+			}
+		}
+	}
+	sortGrants(grants)
+	return grants
+}
+
+// getSubjects returns the users, api tokens and groups reached by matching policies, with the paths to their roles
+func (graph *policyGraph) getSubjects(matches func(p *policy) bool) []*bean.SubjectAccess {
+	subjects := make(map[string]*bean.SubjectAccess)
+	for role, rolePolicies := range graph.policies {
+		for _, p := range rolePolicies {
+			if !matches(p) {
+				continue
+			}
+			for node, nodePaths := range graph.getPaths(role, graph.children) {
+				if strings.HasPrefix(node, bean.RolePrefix) {
+					continue
+				}
+				access, ok := subjects[node]
+				if !ok {
+					access = &bean.SubjectAccess{Subject: node, Type: getSubjectType(node), Grants: make([]*bean.Grant, 0), Denials: make([]*bean.Grant, 0)}
+					subjects[node] = access
+				}
+				for _, path := range nodePaths {
+					grant := newGrant(p, role, reversePath(path))
+					if p.effect == bean.EffectDeny {
+						access.Denials = append(access.Denials, grant)
+					} else {
+						access.Grants = append(access.Grants, grant)
+					}
+				}
+			}
+		}
+	}
+	res := make([]*bean.SubjectAccess, 0, len(subjects))
+	for _, access := range subjects {
+		access.Allowed = len(access.Grants) > 0 && len(access.Denials) == 0
+		sortGrants(access.Grants)
+		sortGrants(access.Denials)
+		res = append(res, access)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Type != res[j].Type {
+			return res[i].Type > res[j].Type
+		}
+		return res[i].Subject < res[j].Subject
+	})
+	return res
+}
+
+// getMatcher matches policies the way the casbin model matcher does for the request
+func getMatcher(resource, action, object string) func(p *policy) bool {
+	return func(p *policy) bool {
+		return casbin.MatchKeyByPart(resource, p.resource) && casbin.MatchKeyByPart(action, p.action) && casbin.MatchKeyByPart(object, p.object)
+	}
+}
+
+func splitByEffect(grants []*bean.Grant) ([]*bean.Grant, []*bean.Grant) {
+	allows, denials := make([]*bean.Grant, 0), make([]*bean.Grant, 0)
+	for _, grant := range grants {
+		if grant.Effect == bean.EffectDeny {
+			denials = append(denials, grant)
+		} else {
+			allows = append(allows, grant)
+		}
+	}
+	return allows, denials
+}
+
+func getCheckReason(allows, denials []*bean.Grant) string {
+	switch {
+	case len(denials) > 0:
+		return "denied by " + strings.Join(getRoles(denials), ", ")
+	case len(allows) > 0:
+		return "allowed by " + strings.Join(getRoles(allows), ", ")
+	default:
+		return "no role of the user or of its groups has a matching policy"
+	}
+}
+
+func getRoles(grants []*bean.Grant) []string {
+	roles := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		if !contains(roles, grant.Role) {
+			roles = append(roles, grant.Role)
+		}
+	}
+	return roles
+}
+
+func getSubjectType(subject string) bean.SubjectType {
+	switch {
+	case strings.HasPrefix(subject, bean.GroupPrefix):
+		return bean.SubjectTypeGroup
+	case strings.HasPrefix(subject, bean.ApiTokenPrefix):
+		return bean.SubjectTypeApiToken
+	default:
+		return bean.SubjectTypeUser
+	}
+}
+
+func newGrant(p *policy, role string, path []string) *bean.Grant {
+	return &bean.Grant{Resource: p.resource, Action: p.action, Object: p.object, Effect: p.effect, Role: role, Path: path}
+}
+
+func sortGrants(grants []*bean.Grant) {
+	sort.SliceStable(grants, func(i, j int) bool {
+		a, b := grants[i], grants[j]
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		if a.Action != b.Action {
+			return a.Action < b.Action
+		}
+		if a.Object != b.Object {
+			return a.Object < b.Object
+		}
+		return strings.Join(a.Path, "/") < strings.Join(b.Path, "/")
+	})
+}
+
+func reversePath(path []string) []string {
+	reversed := make([]string, len(path))
+	for i, node := range path {
+		reversed[len(path)-1-i] = node
+	}
+	return reversed
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacExplainer
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auth/rbacExplainer/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestGraph() *policyGraph {
+	policies := [][]string{
+		{"role:admin_devtron_app1_prod", "applications", "*", "devtron/app1", "allow"},
+		{"role:trigger_devtron_app1_prod", "applications", "trigger", "devtron/app1", "allow"},
+		{"role:trigger_devtron_app1_prod", "environment", "trigger", "prod/app1", "allow"},
+		{"role:no_prod", "environment", "trigger", "prod/*", "deny"},
+		{"role:super-admin___", "*", "*", "*"},
+	}
+	groupingPolicies := [][]string{
+		{"alice@example.com", "role:admin_devtron_app1_prod"},
+		{"bob@example.com", "group:deployers"},
+		{"group:deployers", "role:trigger_devtron_app1_prod"},
+		{"carol@example.com", "group:deployers"},
+		{"carol@example.com", "role:no_prod"},
+		{"api-token:ci", "role:trigger_devtron_app1_prod"},
+		{"admin", "role:super-admin___"},
+		// cycles are not followed
+		{"group:deployers", "group:deployers"},
+	}
+	return newPolicyGraph(policies, groupingPolicies)
+}
+
+func TestGetGrants(t *testing.T) {
+	graph := newTestGraph()
+	grants := graph.getGrants("bob@example.com", nil)
+	assert.Len(t, grants, 2)
+	assert.Equal(t, []string{"bob@example.com", "group:deployers", "role:trigger_devtron_app1_prod"}, grants[0].Path)
+	assert.Equal(t, "role:trigger_devtron_app1_prod", grants[0].Role)
+	assert.Equal(t, bean.EffectAllow, grants[0].Effect)
+	assert.Empty(t, graph.getGrants("dave@example.com", nil))
+}
+
+func TestCheck(t *testing.T) {
+	graph := newTestGraph()
+	tests := []struct {
+		name                     string
+		subject                  string
+		resource, action, object string
+		wantAllowed              bool
+		wantGrants, wantDenials  int
+	}{
+		{name: "allowed through group", subject: "bob@example.com", resource: "environment", action: "trigger", object: "prod/app1", wantAllowed: true, wantGrants: 1},
+		{name: "allowed through wildcard action", subject: "alice@example.com", resource: "applications", action: "delete", object: "devtron/app1", wantAllowed: true, wantGrants: 1},
+		{name: "denied overrides group grant", subject: "carol@example.com", resource: "environment", action: "trigger", object: "prod/app1", wantGrants: 1, wantDenials: 1},
+		{name: "no matching policy", subject: "bob@example.com", resource: "applications", action: "delete", object: "devtron/app1"},
+		{name: "super admin", subject: "admin", resource: "cluster", action: "delete", object: "default_cluster", wantAllowed: true, wantGrants: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allows, denials := splitByEffect(graph.getGrants(tt.subject, getMatcher(tt.resource, tt.action, tt.object)))
+			assert.Equal(t, tt.wantAllowed, len(allows) > 0 && len(denials) == 0)
+			assert.Len(t, allows, tt.wantGrants)
+			assert.Len(t, denials, tt.wantDenials)
+		})
+	}
+}
+
+func TestGetSubjects(t *testing.T) {
+	subjects := newTestGraph().getSubjects(getMatcher("environment", "trigger", "prod/app1"))
+	got := make(map[string]*bean.SubjectAccess)
+	for _, subject := range subjects {
+		got[subject.Subject] = subject
+	}
+	assert.Len(t, got, 5)
+	assert.True(t, got["bob@example.com"].Allowed)
+	assert.Equal(t, []string{"bob@example.com", "group:deployers", "role:trigger_devtron_app1_prod"}, got["bob@example.com"].Grants[0].Path)
+	assert.False(t, got["carol@example.com"].Allowed)
+	assert.Len(t, got["carol@example.com"].Denials, 1)
+	assert.Equal(t, bean.SubjectTypeGroup, got["group:deployers"].Type)
+	assert.Equal(t, bean.SubjectTypeApiToken, got["api-token:ci"].Type)
+	assert.True(t, got["admin"].Allowed)
+	assert.Equal(t, bean.SubjectTypeUser, subjects[0].Type)
+}
+
+func TestGetCheckReason(t *testing.T) {
+	allow := &bean.Grant{Role: "role:a"}
+	deny := &bean.Grant{Role: "role:b", Effect: bean.EffectDeny}
+	assert.Equal(t, "denied by role:b", getCheckReason([]*bean.Grant{allow}, []*bean.Grant{deny}))
+	assert.Equal(t, "allowed by role:a", getCheckReason([]*bean.Grant{allow, allow}, nil))
+	assert.Equal(t, "no role of the user or of its groups has a matching policy", getCheckReason(nil, nil))
+}
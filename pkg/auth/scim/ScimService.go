@@ -0,0 +1,827 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scim
+
+import (
+	"context"
+	"fmt"
+	"github.com/caarlos0/env"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/repository"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	userUtil "github.com/devtron-labs/devtron/pkg/auth/user/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	UsersPath  = "/orchestrator/scim/v2/Users/"
+	GroupsPath = "/orchestrator/scim/v2/Groups/"
+
+	provisionedRoleGroupDescription = "provisioned by the identity provider through scim"
+	systemUserEmail                 = "system"
+)
+
+type ScimService interface {
+	// Authenticate returns the id of the scim api token user, only the token configured for scim is accepted
+	Authenticate(ctx context.Context, token string, clientIp string) (int32, error)
+
+	GetUser(id string) (*bean.User, error)
+	ListUsers(request *bean.ListRequest) (*bean.ListResponse, error)
+	// CreateUser creates the user, or activates it when it was deactivated earlier
+	CreateUser(request *bean.User, userId int32) (*bean.User, error)
+	ReplaceUser(id string, request *bean.User, userId int32) (*bean.User, error)
+	PatchUser(id string, request *bean.PatchRequest, userId int32) (*bean.User, error)
+	// DeleteUser deactivates the user, removing its permissions
+	DeleteUser(id string, userId int32) error
+
+	GetGroup(id string, excludeMembers bool) (*bean.Group, error)
+	ListGroups(request *bean.ListRequest) (*bean.ListResponse, error)
+	// CreateGroup creates a role group without permissions, or links the role group of the same name
+	CreateGroup(request *bean.Group, userId int32) (*bean.Group, error)
+	ReplaceGroup(id string, request *bean.Group, userId int32) (*bean.Group, error)
+	PatchGroup(id string, request *bean.PatchRequest, userId int32) (*bean.Group, error)
+	// DeleteGroup removes members of the role group, the role group itself is deleted only if it was created through scim
+	DeleteGroup(id string, userId int32) error
+
+	GetServiceProviderConfig() *bean.ServiceProviderConfig
+	GetResourceTypes() []*bean.ResourceType
+}
+
+type ScimServiceImpl struct {
+	logger              *zap.SugaredLogger
+	scimConfig          *bean.ScimConfig
+	scimRepository      repository.ScimRepository
+	userService         user.UserService
+	roleGroupService    user.RoleGroupService
+	userRepository      userRepository.UserRepository
+	roleGroupRepository userRepository.RoleGroupRepository
+	enforcer            casbin.Enforcer
+}
+
+func NewScimServiceImpl(logger *zap.SugaredLogger,
+	scimRepository repository.ScimRepository,
+	userService user.UserService,
+	roleGroupService user.RoleGroupService,
+	userRepository userRepository.UserRepository,
+	roleGroupRepository userRepository.RoleGroupRepository,
+	enforcer casbin.Enforcer) (*ScimServiceImpl, error) {
+	scimConfig := &bean.ScimConfig{}
+	err := env.Parse(scimConfig)
+	if err != nil {
+		logger.Errorw("error in parsing scim config", "err", err)
+		return nil, err
+	}
+	return &ScimServiceImpl{
+		logger:              logger,
+		scimConfig:          scimConfig,
+		scimRepository:      scimRepository,
+		userService:         userService,
+		roleGroupService:    roleGroupService,
+		userRepository:      userRepository,
+		roleGroupRepository: roleGroupRepository,
+		enforcer:            enforcer,
+	}, nil
+}
+
+// allowAll is passed as manager auth to user services, the scim token manages every user and role group
+func allowAll(resource, token, object string) bool {
+	return true
+}
+
+func notFound(resourceType, id string) error {
+	return bean.NewScimError(http.StatusNotFound, "", fmt.Sprintf("%s %s not found", resourceType, id))
+}
+
+func (impl *ScimServiceImpl) Authenticate(ctx context.Context, token string, clientIp string) (int32, error) {
+	if len(token) == 0 {
+		return 0, bean.NewScimError(http.StatusUnauthorized, "", "bearer token is required")
+	}
+	userId, userType, err := impl.userService.GetUserByToken(ctx, token, clientIp)
+	if err != nil {
+		return 0, bean.NewScimError(http.StatusUnauthorized, "", "invalid token")
+	}
+	email, err := impl.userService.GetEmailFromToken(token)
+	if err != nil {
+		return 0, bean.NewScimError(http.StatusUnauthorized, "", "invalid token")
+	}
+	if userType != apiBean.USER_TYPE_API_TOKEN || email != userBean.API_TOKEN_USER_EMAIL_PREFIX+impl.scimConfig.ApiTokenName {
+		return 0, bean.NewScimError(http.StatusForbidden, "", fmt.Sprintf("scim requests must use the api token %s", impl.scimConfig.ApiTokenName))
+	}
+	return userId, nil
+}
+
+// linkedGroup is a role group linked to scim
+type linkedGroup struct {
+	scimGroup *repository.ScimGroup
+	roleGroup *userRepository.RoleGroup
+}
+
+// getLinkedGroups returns linked role groups indexed by casbin name, role groups deleted from devtron are left out
+func (impl *ScimServiceImpl) getLinkedGroups() (map[string]*linkedGroup, error) {
+	scimGroups, err := impl.scimRepository.FindAllGroups()
+	if err != nil {
+		impl.logger.Errorw("error in fetching scim groups", "err", err)
+		return nil, err
+	}
+	roleGroups, err := impl.roleGroupRepository.GetAllRoleGroup()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching role groups", "err", err)
+		return nil, err
+	}
+	roleGroupById := make(map[int32]*userRepository.RoleGroup, len(roleGroups))
+	for _, roleGroup := range roleGroups {
+		roleGroupById[roleGroup.Id] = roleGroup
+	}
+	groups := make(map[string]*linkedGroup, len(scimGroups))
+	for _, scimGroup := range scimGroups {
+		if roleGroup, ok := roleGroupById[scimGroup.RoleGroupId]; ok {
+			groups[roleGroup.CasbinName] = &linkedGroup{scimGroup: scimGroup, roleGroup: roleGroup}
+		}
+	}
+	return groups, nil
+}
+
+func (impl *ScimServiceImpl) getLinkedGroup(id string) (*linkedGroup, error) {
+	roleGroupId, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, notFound(bean.ResourceTypeGroup, id)
+	}
+	scimGroup, err := impl.scimRepository.FindGroupByRoleGroupId(int32(roleGroupId))
+	if util.IsErrNoRows(err) {
+		return nil, notFound(bean.ResourceTypeGroup, id)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching scim group", "roleGroupId", roleGroupId, "err", err)
+		return nil, err
+	}
+	roleGroup, err := impl.roleGroupRepository.GetRoleGroupById(int32(roleGroupId))
+	if util.IsErrNoRows(err) {
+		return nil, notFound(bean.ResourceTypeGroup, id)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching role group", "roleGroupId", roleGroupId, "err", err)
+		return nil, err
+	}
+	return &linkedGroup{scimGroup: scimGroup, roleGroup: roleGroup}, nil
+}
+
+// getVisibleUser returns the user unless it is an api token or system user, or was deleted outside scim
+func (impl *ScimServiceImpl) getVisibleUser(id string) (*userRepository.UserModel, *repository.ScimUser, error) {
+	userId, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, nil, notFound(bean.ResourceTypeUser, id)
+	}
+	model, err := impl.userRepository.GetByIdIncludeDeleted(int32(userId))
+	if util.IsErrNoRows(err) {
+		return nil, nil, notFound(bean.ResourceTypeUser, id)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching user", "userId", userId, "err", err)
+		return nil, nil, err
+	}
+	scimUser, err := impl.scimRepository.FindUserByUserId(model.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching scim user", "userId", userId, "err", err)
+		return nil, nil, err
+	}
+	if util.IsErrNoRows(err) {
+		scimUser = nil
+	}
+	if !isProvisionableUser(model) || (!model.Active && scimUser == nil) {
+		return nil, nil, notFound(bean.ResourceTypeUser, id)
+	}
+	return model, scimUser, nil
+}
+
+func isProvisionableUser(model *userRepository.UserModel) bool {
+	return model.UserType != apiBean.USER_TYPE_API_TOKEN && !userUtil.CheckIfAdminOrApiToken(model.EmailId) && model.EmailId != systemUserEmail
+}
+
+func (impl *ScimServiceImpl) toScimUser(model *userRepository.UserModel, scimUser *repository.ScimUser, groups map[string]*linkedGroup) *bean.User {
+	id := strconv.Itoa(int(model.Id))
+	active := model.Active
+	response := &bean.User{
+		Schemas:  []string{bean.UserSchema},
+		Id:       id,
+		UserName: model.EmailId,
+		Emails:   []*bean.Email{{Value: model.EmailId, Type: "work", Primary: true}},
+		Active:   &active,
+		Meta:     getMeta(bean.ResourceTypeUser, UsersPath+id, model.AuditLog),
+	}
+	if scimUser != nil {
+		response.ExternalId = scimUser.ExternalId
+		response.DisplayName = scimUser.DisplayName
+		if len(scimUser.GivenName) > 0 || len(scimUser.FamilyName) > 0 {
+			response.Name = &bean.Name{GivenName: scimUser.GivenName, FamilyName: scimUser.FamilyName}
+		}
+	}
+	if model.Active {
+		casbinNames, err := casbin.GetRolesForUser(model.EmailId)
+		if err != nil {
+			impl.logger.Warnw("error in fetching roles of user", "emailId", model.EmailId, "err", err)
+		}
+		for _, casbinName := range casbinNames {
+			if group, ok := groups[casbinName]; ok {
+				groupId := strconv.Itoa(int(group.roleGroup.Id))
+				response.Groups = append(response.Groups, &bean.GroupRef{Value: groupId, Display: group.roleGroup.Name, Ref: GroupsPath + groupId})
+			}
+		}
+	}
+	return response
+}
+
+func getMeta(resourceType, location string, auditLog sql.AuditLog) *bean.Meta {
+	meta := &bean.Meta{ResourceType: resourceType, Location: location}
+	if !auditLog.CreatedOn.IsZero() {
+		createdOn := auditLog.CreatedOn
+		meta.Created = &createdOn
+	}
+	if !auditLog.UpdatedOn.IsZero() {
+		updatedOn := auditLog.UpdatedOn
+		meta.LastModified = &updatedOn
+	}
+	return meta
+}
+
+func (impl *ScimServiceImpl) GetUser(id string) (*bean.User, error) {
+	model, scimUser, err := impl.getVisibleUser(id)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := impl.getLinkedGroups()
+	if err != nil {
+		return nil, err
+	}
+	return impl.toScimUser(model, scimUser, groups), nil
+}
+
+func (impl *ScimServiceImpl) ListUsers(request *bean.ListRequest) (*bean.ListResponse, error) {
+	expr, err := parseFilter(request.Filter)
+	if err != nil {
+		return nil, err
+	}
+	models, err := impl.scimRepository.FindVisibleUsers()
+	if err != nil {
+		impl.logger.Errorw("error in fetching users", "err", err)
+		return nil, err
+	}
+	scimUsers, err := impl.scimRepository.FindAllUsers()
+	if err != nil {
+		impl.logger.Errorw("error in fetching scim users", "err", err)
+		return nil, err
+	}
+	scimUserByUserId := make(map[int32]*repository.ScimUser, len(scimUsers))
+	for _, scimUser := range scimUsers {
+		scimUserByUserId[scimUser.UserId] = scimUser
+	}
+	groups, err := impl.getLinkedGroups()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*bean.User, 0, len(models))
+	for _, model := range models {
+		if !isProvisionableUser(model) {
+			continue
+		}
+		scimUser := impl.toScimUser(model, scimUserByUserId[model.Id], groups)
+		if matchesFilter(expr, getUserAttributes(scimUser)) {
+			users = append(users, scimUser)
+		}
+	}
+	start, end := getPage(len(users), request.StartIndex, request.Count)
+	return &bean.ListResponse{
+		Schemas:      []string{bean.ListResponseSchema},
+		TotalResults: len(users),
+		StartIndex:   start + 1,
+		ItemsPerPage: end - start,
+		Resources:    users[start:end],
+	}, nil
+}
+
+func validateUserName(userName string) error {
+	if len(userName) == 0 {
+		return invalidValue("userName is required")
+	}
+	if strings.Contains(userName, ",") || userUtil.CheckIfAdminOrApiToken(userName) || strings.EqualFold(userName, systemUserEmail) {
+		return invalidValue(fmt.Sprintf("userName %s can not be provisioned", userName))
+	}
+	return nil
+}
+
+func (impl *ScimServiceImpl) CreateUser(request *bean.User, userId int32) (*bean.User, error) {
+	userName := strings.TrimSpace(request.UserName)
+	if err := validateUserName(userName); err != nil {
+		return nil, err
+	}
+	existing, err := impl.userRepository.FetchActiveOrDeletedUserByEmail(userName)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching user", "emailId", userName, "err", err)
+		return nil, err
+	}
+	if err == nil && existing.Active {
+		return nil, bean.NewScimError(http.StatusConflict, bean.ScimTypeUniqueness, fmt.Sprintf("user %s already exists", userName))
+	}
+	id, err := impl.activateUser(userName, userId)
+	if err != nil {
+		return nil, err
+	}
+	model, err := impl.userRepository.GetByIdIncludeDeleted(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user", "userId", id, "err", err)
+		return nil, err
+	}
+	return impl.saveUser(model, nil, request, userId)
+}
+
+func (impl *ScimServiceImpl) ReplaceUser(id string, request *bean.User, userId int32) (*bean.User, error) {
+	model, scimUser, err := impl.getVisibleUser(id)
+	if err != nil {
+		return nil, err
+	}
+	return impl.saveUser(model, scimUser, request, userId)
+}
+
+func (impl *ScimServiceImpl) PatchUser(id string, request *bean.PatchRequest, userId int32) (*bean.User, error) {
+	model, scimUser, err := impl.getVisibleUser(id)
+	if err != nil {
+		return nil, err
+	}
+	desired := impl.toScimUser(model, scimUser, nil)
+	err = applyUserPatch(desired, request.Operations)
+	if err != nil {
+		return nil, err
+	}
+	return impl.saveUser(model, scimUser, desired, userId)
+}
+
+// saveUser saves the attributes devtron does not keep in scim user, and activates or deactivates the user.
+// The email id of a user can not be changed
+func (impl *ScimServiceImpl) saveUser(model *userRepository.UserModel, scimUser *repository.ScimUser, desired *bean.User, userId int32) (*bean.User, error) {
+	if !strings.EqualFold(strings.TrimSpace(desired.UserName), model.EmailId) {
+		return nil, bean.NewScimError(http.StatusBadRequest, bean.ScimTypeMutability, "userName of a user can not be changed")
+	}
+	isNew := scimUser == nil
+	if isNew {
+		scimUser = &repository.ScimUser{UserId: model.Id, AuditLog: sql.NewDefaultAuditLog(userId)}
+	}
+	scimUser.ExternalId = desired.ExternalId
+	scimUser.DisplayName = desired.DisplayName
+	scimUser.GivenName, scimUser.FamilyName = "", ""
+	if desired.Name != nil {
+		scimUser.GivenName, scimUser.FamilyName = desired.Name.GivenName, desired.Name.FamilyName
+	}
+	scimUser.UpdatedOn = time.Now()
+	scimUser.UpdatedBy = userId
+	var err error
+	if isNew {
+		err = impl.scimRepository.SaveUser(scimUser)
+	} else {
+		err = impl.scimRepository.UpdateUser(scimUser)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving scim user", "userId", model.Id, "err", err)
+		return nil, err
+	}
+	if desired.Active != nil && *desired.Active != model.Active {
+		if *desired.Active {
+			_, err = impl.activateUser(model.EmailId, userId)
+		} else {
+			err = impl.deactivateUser(model, userId)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return impl.GetUser(strconv.Itoa(int(model.Id)))
+}
+
+// activateUser creates the user without permissions, an inactive user is activated with no permissions
+// as deactivation removed them, the identity provider restores role group memberships
+func (impl *ScimServiceImpl) activateUser(emailId string, userId int32) (int32, error) {
+	userInfo := &apiBean.UserInfo{
+		EmailId:       emailId,
+		UserId:        userId,
+		RoleFilters:   make([]apiBean.RoleFilter, 0),
+		UserRoleGroup: make([]apiBean.UserRoleGroup, 0),
+	}
+	res, err := impl.userService.CreateUser(userInfo, "", allowAll)
+	if err != nil {
+		impl.logger.Errorw("error in creating user", "emailId", emailId, "err", err)
+		return 0, err
+	}
+	if len(res) == 0 {
+		return 0, fmt.Errorf("user %s was not created", emailId)
+	}
+	return res[0].Id, nil
+}
+
+// deactivateUser removes the permissions of the user and marks it inactive, so that tokens of the user are rejected
+func (impl *ScimServiceImpl) deactivateUser(model *userRepository.UserModel, userId int32) error {
+	_, err := impl.userService.DeleteUser(&apiBean.UserInfo{Id: model.Id, UserId: userId})
+	if err != nil {
+		impl.logger.Errorw("error in deactivating user", "userId", model.Id, "err", err)
+		return err
+	}
+	// enforcement results are cached per user, they must not outlive the deactivation
+	impl.enforcer.InvalidateCache(model.EmailId)
+	return nil
+}
+
+func (impl *ScimServiceImpl) DeleteUser(id string, userId int32) error {
+	model, _, err := impl.getVisibleUser(id)
+	if err != nil {
+		return err
+	}
+	if model.Active {
+		err = impl.deactivateUser(model, userId)
+		if err != nil {
+			return err
+		}
+	}
+	// without a scim user an inactive user is no longer visible to the identity provider
+	err = impl.scimRepository.DeleteUserByUserId(model.Id)
+	if err != nil {
+		impl.logger.Errorw("error in deleting scim user", "userId", model.Id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ScimServiceImpl) toScimGroup(group *linkedGroup, excludeMembers bool) (*bean.Group, error) {
+	id := strconv.Itoa(int(group.roleGroup.Id))
+	response := &bean.Group{
+		Schemas:     []string{bean.GroupSchema},
+		Id:          id,
+		ExternalId:  group.scimGroup.ExternalId,
+		DisplayName: group.roleGroup.Name,
+		Meta:        getMeta(bean.ResourceTypeGroup, GroupsPath+id, group.scimGroup.AuditLog),
+	}
+	if excludeMembers {
+		return response, nil
+	}
+	members, err := impl.getMembers(group.roleGroup)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		memberId := strconv.Itoa(int(member.Id))
+		response.Members = append(response.Members, &bean.Member{Value: memberId, Display: member.EmailId, Ref: UsersPath + memberId})
+	}
+	return response, nil
+}
+
+// getMembers returns active users having the role group
+func (impl *ScimServiceImpl) getMembers(roleGroup *userRepository.RoleGroup) ([]*userRepository.UserModel, error) {
+	emailIds, err := casbin.GetUserByRole(roleGroup.CasbinName)
+	if err != nil {
+		impl.logger.Errorw("error in fetching users of role group", "roleGroup", roleGroup.Name, "err", err)
+		return nil, err
+	}
+	members := make([]*userRepository.UserModel, 0, len(emailIds))
+	for _, emailId := range emailIds {
+		model, err := impl.userRepository.FetchActiveOrDeletedUserByEmail(emailId)
+		if util.IsErrNoRows(err) {
+			continue
+		} else if err != nil {
+			impl.logger.Errorw("error in fetching user", "emailId", emailId, "err", err)
+			return nil, err
+		}
+		if model.Active && isProvisionableUser(model) {
+			members = append(members, model)
+		}
+	}
+	return members, nil
+}
+
+func (impl *ScimServiceImpl) getMemberIds(roleGroup *userRepository.RoleGroup) ([]string, error) {
+	members, err := impl.getMembers(roleGroup)
+	if err != nil {
+		return nil, err
+	}
+	memberIds := make([]string, 0, len(members))
+	for _, member := range members {
+		memberIds = append(memberIds, strconv.Itoa(int(member.Id)))
+	}
+	return memberIds, nil
+}
+
+func (impl *ScimServiceImpl) GetGroup(id string, excludeMembers bool) (*bean.Group, error) {
+	group, err := impl.getLinkedGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	return impl.toScimGroup(group, excludeMembers)
+}
+
+func (impl *ScimServiceImpl) ListGroups(request *bean.ListRequest) (*bean.ListResponse, error) {
+	expr, err := parseFilter(request.Filter)
+	if err != nil {
+		return nil, err
+	}
+	linkedGroups, err := impl.getLinkedGroups()
+	if err != nil {
+		return nil, err
+	}
+	sortedGroups := make([]*linkedGroup, 0, len(linkedGroups))
+	for _, group := range linkedGroups {
+		sortedGroups = append(sortedGroups, group)
+	}
+	sort.Slice(sortedGroups, func(i, j int) bool {
+		return sortedGroups[i].roleGroup.Id < sortedGroups[j].roleGroup.Id
+	})
+	groups := make([]*bean.Group, 0, len(sortedGroups))
+	for _, group := range sortedGroups {
+		// members are needed to filter on them even when they are excluded from the response
+		scimGroup, err := impl.toScimGroup(group, request.ExcludeMembers && !strings.Contains(strings.ToLower(request.Filter), membersAttribute))
+		if err != nil {
+			return nil, err
+		}
+		if matchesFilter(expr, getGroupAttributes(scimGroup)) {
+			if request.ExcludeMembers {
+				scimGroup.Members = nil
+			}
+			groups = append(groups, scimGroup)
+		}
+	}
+	start, end := getPage(len(groups), request.StartIndex, request.Count)
+	return &bean.ListResponse{
+		Schemas:      []string{bean.ListResponseSchema},
+		TotalResults: len(groups),
+		StartIndex:   start + 1,
+		ItemsPerPage: end - start,
+		Resources:    groups[start:end],
+	}, nil
+}
+
+func (impl *ScimServiceImpl) CreateGroup(request *bean.Group, userId int32) (*bean.Group, error) {
+	name := strings.TrimSpace(request.DisplayName)
+	if len(name) == 0 || !userUtil.CheckValidationForRoleGroupCreation(name) {
+		return nil, invalidValue(fmt.Sprintf("displayName %q is not a valid role group name", request.DisplayName))
+	}
+	memberIds, err := getRequestMemberIds(request.Members)
+	if err != nil {
+		return nil, err
+	}
+	provisioned := false
+	roleGroup, err := impl.roleGroupRepository.GetRoleGroupByName(name)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching role group", "name", name, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) {
+		created, err := impl.roleGroupService.CreateRoleGroup(&apiBean.RoleGroup{
+			Name:        name,
+			Description: provisionedRoleGroupDescription,
+			RoleFilters: make([]apiBean.RoleFilter, 0),
+			UserId:      userId,
+		})
+		if err != nil {
+			impl.logger.Errorw("error in creating role group", "name", name, "err", err)
+			return nil, err
+		}
+		roleGroup, err = impl.roleGroupRepository.GetRoleGroupById(created.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching role group", "roleGroupId", created.Id, "err", err)
+			return nil, err
+		}
+		provisioned = true
+	} else {
+		_, err = impl.scimRepository.FindGroupByRoleGroupId(roleGroup.Id)
+		if err == nil {
+			return nil, bean.NewScimError(http.StatusConflict, bean.ScimTypeUniqueness, fmt.Sprintf("group %s already exists", name))
+		} else if !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching scim group", "roleGroupId", roleGroup.Id, "err", err)
+			return nil, err
+		}
+	}
+	scimGroup := &repository.ScimGroup{
+		RoleGroupId: roleGroup.Id,
+		ExternalId:  request.ExternalId,
+		Provisioned: provisioned,
+		AuditLog:    sql.NewDefaultAuditLog(userId),
+	}
+	err = impl.scimRepository.SaveGroup(scimGroup)
+	if err != nil {
+		impl.logger.Errorw("error in saving scim group", "roleGroupId", roleGroup.Id, "err", err)
+		return nil, err
+	}
+	group := &linkedGroup{scimGroup: scimGroup, roleGroup: roleGroup}
+	// members of a linked role group the identity provider does not list are removed
+	currentMemberIds, err := impl.getMemberIds(roleGroup)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.updateMembers(group, currentMemberIds, memberIds, userId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.toScimGroup(group, false)
+}
+
+func getRequestMemberIds(members []*bean.Member) ([]string, error) {
+	memberIds := make([]string, 0, len(members))
+	for _, member := range members {
+		if len(member.Value) == 0 {
+			return nil, invalidValue("member value is required")
+		}
+		memberIds = append(memberIds, member.Value)
+	}
+	return uniqueIds(memberIds), nil
+}
+
+func (impl *ScimServiceImpl) ReplaceGroup(id string, request *bean.Group, userId int32) (*bean.Group, error) {
+	group, err := impl.getLinkedGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	memberIds, err := getRequestMemberIds(request.Members)
+	if err != nil {
+		return nil, err
+	}
+	return impl.saveGroup(group, &groupPatchState{displayName: request.DisplayName, externalId: request.ExternalId, memberIds: memberIds}, userId)
+}
+
+func (impl *ScimServiceImpl) PatchGroup(id string, request *bean.PatchRequest, userId int32) (*bean.Group, error) {
+	group, err := impl.getLinkedGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	memberIds, err := impl.getMemberIds(group.roleGroup)
+	if err != nil {
+		return nil, err
+	}
+	state := &groupPatchState{displayName: group.roleGroup.Name, externalId: group.scimGroup.ExternalId, memberIds: memberIds}
+	err = applyGroupPatch(state, request.Operations)
+	if err != nil {
+		return nil, err
+	}
+	return impl.saveGroup(group, state, userId)
+}
+
+// saveGroup saves the external id and members of the group, role groups can not be renamed
+func (impl *ScimServiceImpl) saveGroup(group *linkedGroup, desired *groupPatchState, userId int32) (*bean.Group, error) {
+	if strings.TrimSpace(desired.displayName) != group.roleGroup.Name {
+		return nil, bean.NewScimError(http.StatusBadRequest, bean.ScimTypeMutability, "displayName of a group can not be changed")
+	}
+	if desired.externalId != group.scimGroup.ExternalId {
+		group.scimGroup.ExternalId = desired.externalId
+		group.scimGroup.UpdatedOn = time.Now()
+		group.scimGroup.UpdatedBy = userId
+		err := impl.scimRepository.UpdateGroup(group.scimGroup)
+		if err != nil {
+			impl.logger.Errorw("error in updating scim group", "roleGroupId", group.roleGroup.Id, "err", err)
+			return nil, err
+		}
+	}
+	currentMemberIds, err := impl.getMemberIds(group.roleGroup)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.updateMembers(group, currentMemberIds, desired.memberIds, userId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.toScimGroup(group, false)
+}
+
+// updateMembers adds the role group to users joining it and removes it from users leaving it.
+// Users are updated one by one through the user service, direct permissions of the users are kept
+func (impl *ScimServiceImpl) updateMembers(group *linkedGroup, currentMemberIds, desiredMemberIds []string, userId int32) error {
+	added, removed := diffIds(currentMemberIds, desiredMemberIds)
+	for _, memberId := range added {
+		err := impl.updateMembership(group.roleGroup, memberId, true, userId)
+		if err != nil {
+			return err
+		}
+	}
+	for _, memberId := range removed {
+		err := impl.updateMembership(group.roleGroup, memberId, false, userId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *ScimServiceImpl) updateMembership(roleGroup *userRepository.RoleGroup, memberId string, isMember bool, userId int32) error {
+	id, err := strconv.Atoi(memberId)
+	if err != nil {
+		return invalidValue(fmt.Sprintf("member %s is not a user id", memberId))
+	}
+	userInfo, err := impl.userService.GetById(int32(id))
+	if util.IsErrNoRows(err) {
+		// identity providers keep deactivated users in groups, they get the group back when activated again
+		impl.logger.Infow("skipping membership of inactive user", "userId", id, "roleGroup", roleGroup.Name)
+		return nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching user", "userId", id, "err", err)
+		return err
+	}
+	userRoleGroups := make([]apiBean.UserRoleGroup, 0, len(userInfo.UserRoleGroup)+1)
+	for _, userRoleGroup := range userInfo.UserRoleGroup {
+		if userRoleGroup.RoleGroup != nil && userRoleGroup.RoleGroup.Name != roleGroup.Name {
+			userRoleGroups = append(userRoleGroups, userRoleGroup)
+		}
+	}
+	if isMember {
+		userRoleGroups = append(userRoleGroups, apiBean.UserRoleGroup{RoleGroup: &apiBean.RoleGroup{Id: roleGroup.Id, Name: roleGroup.Name}})
+	}
+	userInfo.UserRoleGroup = userRoleGroups
+	userInfo.UserId = userId
+	_, err = impl.userService.UpdateUser(userInfo, "", nil, allowAll)
+	if err != nil {
+		impl.logger.Errorw("error in updating role groups of user", "userId", id, "roleGroup", roleGroup.Name, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ScimServiceImpl) DeleteGroup(id string, userId int32) error {
+	group, err := impl.getLinkedGroup(id)
+	if err != nil {
+		return err
+	}
+	currentMemberIds, err := impl.getMemberIds(group.roleGroup)
+	if err != nil {
+		return err
+	}
+	err = impl.updateMembers(group, currentMemberIds, nil, userId)
+	if err != nil {
+		return err
+	}
+	err = impl.scimRepository.DeleteGroup(group.scimGroup)
+	if err != nil {
+		impl.logger.Errorw("error in deleting scim group", "roleGroupId", group.roleGroup.Id, "err", err)
+		return err
+	}
+	if group.scimGroup.Provisioned {
+		_, err = impl.roleGroupService.DeleteRoleGroup(&apiBean.RoleGroup{Id: group.roleGroup.Id, UserId: userId})
+		if err != nil {
+			impl.logger.Errorw("error in deleting role group", "roleGroupId", group.roleGroup.Id, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *ScimServiceImpl) GetServiceProviderConfig() *bean.ServiceProviderConfig {
+	return &bean.ServiceProviderConfig{
+		Schemas:        []string{bean.ServiceProviderConfigSchema},
+		Patch:          &bean.Supported{Supported: true},
+		Bulk:           &bean.BulkSupport{Supported: false},
+		Filter:         &bean.FilterSupport{Supported: true, MaxResults: bean.MaxResults},
+		ChangePassword: &bean.Supported{Supported: false},
+		Sort:           &bean.Supported{Supported: false},
+		Etag:           &bean.Supported{Supported: false},
+		AuthenticationSchemes: []*bean.AuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Devtron API token",
+			Description: fmt.Sprintf("Authentication with the devtron api token named %s", impl.scimConfig.ApiTokenName),
+		}},
+	}
+}
+
+func (impl *ScimServiceImpl) GetResourceTypes() []*bean.ResourceType {
+	return []*bean.ResourceType{
+		{
+			Schemas:     []string{bean.ResourceTypeSchema},
+			Id:          bean.ResourceTypeUser,
+			Name:        bean.ResourceTypeUser,
+			Endpoint:    "/Users",
+			Description: "Devtron user",
+			Schema:      bean.UserSchema,
+			Meta:        &bean.Meta{ResourceType: "ResourceType", Location: "/orchestrator/scim/v2/ResourceTypes/" + bean.ResourceTypeUser},
+		},
+		{
+			Schemas:     []string{bean.ResourceTypeSchema},
+			Id:          bean.ResourceTypeGroup,
+			Name:        bean.ResourceTypeGroup,
+			Endpoint:    "/Groups",
+			Description: "Devtron role group",
+			Schema:      bean.GroupSchema,
+			Meta:        &bean.Meta{ResourceType: "ResourceType", Location: "/orchestrator/scim/v2/ResourceTypes/" + bean.ResourceTypeGroup},
+		},
+	}
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ScimConfig struct {
+	// ApiTokenName is the name of the devtron api token the identity provider authenticates with,
+	// no other token is accepted on scim endpoints
+	ApiTokenName string `env:"SCIM_API_TOKEN_NAME" envDefault:"scim-provisioning"`
+}
+
+const (
+	UserSchema                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	GroupSchema                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	ListResponseSchema          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	PatchOpSchema               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ErrorSchema                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	ServiceProviderConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	ResourceTypeSchema          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+
+	ResourceTypeUser  = "User"
+	ResourceTypeGroup = "Group"
+
+	ContentType = "application/scim+json"
+	// MaxResults caps the page size of list requests
+	MaxResults = 200
+)
+
+// ScimType values of error responses, as defined in RFC 7644 section 3.12
+const (
+	ScimTypeInvalidFilter = "invalidFilter"
+	ScimTypeUniqueness    = "uniqueness"
+	ScimTypeMutability    = "mutability"
+	ScimTypeInvalidSyntax = "invalidSyntax"
+	ScimTypeInvalidPath   = "invalidPath"
+	ScimTypeInvalidValue  = "invalidValue"
+)
+
+type Meta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location,omitempty"`
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// GroupRef is a role group the user is a member of
+type GroupRef struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// User is a devtron user, its userName is the email id of the user. Devtron users are never deleted,
+// a deleted scim user is a deactivated devtron user
+type User struct {
+	Schemas     []string    `json:"schemas"`
+	Id          string      `json:"id,omitempty"`
+	ExternalId  string      `json:"externalId,omitempty"`
+	UserName    string      `json:"userName"`
+	Name        *Name       `json:"name,omitempty"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Emails      []*Email    `json:"emails,omitempty"`
+	Active      *bool       `json:"active,omitempty"`
+	Groups      []*GroupRef `json:"groups,omitempty"`
+	Meta        *Meta       `json:"meta,omitempty"`
+}
+
+type Member struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// Group is a devtron role group, members get the permissions of the role group
+type Group struct {
+	Schemas     []string  `json:"schemas"`
+	Id          string    `json:"id,omitempty"`
+	ExternalId  string    `json:"externalId,omitempty"`
+	DisplayName string    `json:"displayName"`
+	Members     []*Member `json:"members,omitempty"`
+	Meta        *Meta     `json:"meta,omitempty"`
+}
+
+type ListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+type ListRequest struct {
+	Filter     string
+	StartIndex int
+	Count      int
+	// ExcludeMembers skips members of groups, identity providers exclude them when they only look up a group
+	ExcludeMembers bool
+}
+
+type PatchRequest struct {
+	Schemas    []string          `json:"schemas"`
+	Operations []*PatchOperation `json:"Operations" validate:"min=1,dive"`
+}
+
+// PatchOperation value is kept raw, identity providers send booleans as strings and a single value in place of a list
+type PatchOperation struct {
+	Op    string          `json:"op" validate:"required"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// ScimError is returned by the service for errors that map to a scim error response
+type ScimError struct {
+	Status   int
+	ScimType string
+	Detail   string
+}
+
+func (e *ScimError) Error() string {
+	return e.Detail
+}
+
+func NewScimError(status int, scimType, detail string) *ScimError {
+	return &ScimError{Status: status, ScimType: scimType, Detail: detail}
+}
+
+type Supported struct {
+	Supported bool `json:"supported"`
+}
+
+type FilterSupport struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type BulkSupport struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type AuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ServiceProviderConfig struct {
+	Schemas               []string                `json:"schemas"`
+	Patch                 *Supported              `json:"patch"`
+	Bulk                  *BulkSupport            `json:"bulk"`
+	Filter                *FilterSupport          `json:"filter"`
+	ChangePassword        *Supported              `json:"changePassword"`
+	Sort                  *Supported              `json:"sort"`
+	Etag                  *Supported              `json:"etag"`
+	AuthenticationSchemes []*AuthenticationScheme `json:"authenticationSchemes"`
+}
+
+type ResourceType struct {
+	Schemas     []string `json:"schemas"`
+	Id          string   `json:"id"`
+	Name        string   `json:"name"`
+	Endpoint    string   `json:"endpoint"`
+	Description string   `json:"description"`
+	Schema      string   `json:"schema"`
+	Meta        *Meta    `json:"meta"`
+}
@@ -0,0 +1,642 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/bean"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	patchOpAdd     = "add"
+	patchOpReplace = "replace"
+	patchOpRemove  = "remove"
+
+	membersAttribute = "members"
+)
+
+// caseExactAttributes are compared as is, other string attributes are compared ignoring case as RFC 7643 defines them
+var caseExactAttributes = map[string]bool{"id": true, "externalid": true}
+
+func invalidFilter(detail string) error {
+	return bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidFilter, detail)
+}
+
+func invalidValue(detail string) error {
+	return bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidValue, detail)
+}
+
+// filterExpr is a parsed scim filter, matched against the attributes of a resource
+// indexed by their lower cased path, e.g. "username" or "name.givenname"
+type filterExpr interface {
+	matches(attributes map[string][]string) bool
+}
+
+type logicalExpr struct {
+	and         bool
+	left, right filterExpr
+}
+
+func (e *logicalExpr) matches(attributes map[string][]string) bool {
+	if e.and {
+		return e.left.matches(attributes) && e.right.matches(attributes)
+	}
+	return e.left.matches(attributes) || e.right.matches(attributes)
+}
+
+type notExpr struct {
+	expr filterExpr
+}
+
+func (e *notExpr) matches(attributes map[string][]string) bool {
+	return !e.expr.matches(attributes)
+}
+
+type comparisonExpr struct {
+	attribute string
+	operator  string
+	value     string
+}
+
+func (e *comparisonExpr) matches(attributes map[string][]string) bool {
+	values := attributes[e.attribute]
+	if e.operator == "pr" {
+		for _, value := range values {
+			if len(value) > 0 {
+				return true
+			}
+		}
+		return false
+	}
+	if e.operator == "ne" {
+		return !(&comparisonExpr{attribute: e.attribute, operator: "eq", value: e.value}).matches(attributes)
+	}
+	expected := e.value
+	for _, value := range values {
+		if !caseExactAttributes[e.attribute] {
+			value, expected = strings.ToLower(value), strings.ToLower(e.value)
+		}
+		if compareValue(e.operator, value, expected) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareValue(operator, value, expected string) bool {
+	switch operator {
+	case "eq":
+		return value == expected
+	case "co":
+		return strings.Contains(value, expected)
+	case "sw":
+		return strings.HasPrefix(value, expected)
+	case "ew":
+		return strings.HasSuffix(value, expected)
+	case "gt":
+		return value > expected
+	case "ge":
+		return value >= expected
+	case "lt":
+		return value < expected
+	case "le":
+		return value <= expected
+	}
+	return false
+}
+
+var filterOperators = map[string]bool{"eq": true, "ne": true, "co": true, "sw": true, "ew": true, "gt": true, "ge": true, "lt": true, "le": true, "pr": true}
+
+type filterToken struct {
+	text   string
+	quoted bool
+}
+
+type filterParser struct {
+	tokens   []*filterToken
+	position int
+}
+
+// parseFilter parses a filter of RFC 7644 section 3.4.2.2, complex attribute filters like emails[type eq "work"]
+// are not supported. An empty filter matches every resource
+func parseFilter(filter string) (filterExpr, error) {
+	if len(strings.TrimSpace(filter)) == 0 {
+		return nil, nil
+	}
+	tokens, err := tokenizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	parser := &filterParser{tokens: tokens}
+	expr, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token := parser.peek(); token != nil {
+		return nil, invalidFilter(fmt.Sprintf("unexpected %q in filter", token.text))
+	}
+	return expr, nil
+}
+
+func tokenizeFilter(filter string) ([]*filterToken, error) {
+	tokens := make([]*filterToken, 0)
+	for i := 0; i < len(filter); {
+		switch c := filter[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, &filterToken{text: string(c)})
+			i++
+		case c == '"':
+			var value strings.Builder
+			i++
+			for ; i < len(filter) && filter[i] != '"'; i++ {
+				if filter[i] == '\\' && i+1 < len(filter) {
+					i++
+				}
+				value.WriteByte(filter[i])
+			}
+			if i == len(filter) {
+				return nil, invalidFilter("unterminated string in filter")
+			}
+			tokens = append(tokens, &filterToken{text: value.String(), quoted: true})
+			i++
+		default:
+			start := i
+			for ; i < len(filter) && !strings.ContainsRune(" \t()\"", rune(filter[i])); i++ {
+				if filter[i] == '[' {
+					return nil, invalidFilter("complex attribute filters are not supported")
+				}
+			}
+			tokens = append(tokens, &filterToken{text: filter[start:i]})
+		}
+	}
+	return tokens, nil
+}
+
+func (p *filterParser) peek() *filterToken {
+	if p.position < len(p.tokens) {
+		return p.tokens[p.position]
+	}
+	return nil
+}
+
+func (p *filterParser) next() *filterToken {
+	token := p.peek()
+	if token != nil {
+		p.position++
+	}
+	return token
+}
+
+func (p *filterParser) peekKeyword(keyword string) bool {
+	token := p.peek()
+	return token != nil && !token.quoted && strings.EqualFold(token.text, keyword)
+}
+
+func (p *filterParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseTerm() (filterExpr, error) {
+	if p.peekKeyword("not") {
+		p.next()
+		if !p.peekKeyword("(") {
+			return nil, invalidFilter("not must be followed by a parenthesised filter")
+		}
+		expr, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr: expr}, nil
+	}
+	if p.peekKeyword("(") {
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peekKeyword(")") {
+			return nil, invalidFilter("missing closing parenthesis in filter")
+		}
+		p.next()
+		return expr, nil
+	}
+	attribute := p.next()
+	operator := p.next()
+	if attribute == nil || attribute.quoted || operator == nil || !filterOperators[strings.ToLower(operator.text)] {
+		return nil, invalidFilter("filter must be of the form attribute operator value")
+	}
+	expr := &comparisonExpr{attribute: normalizeAttribute(attribute.text), operator: strings.ToLower(operator.text)}
+	if expr.operator == "pr" {
+		return expr, nil
+	}
+	value := p.next()
+	if value == nil || (!value.quoted && (value.text == "(" || value.text == ")")) {
+		return nil, invalidFilter(fmt.Sprintf("missing value for %s in filter", attribute.text))
+	}
+	expr.value = value.text
+	if !value.quoted && value.text == "null" {
+		expr.value = ""
+	}
+	return expr, nil
+}
+
+// normalizeAttribute lower cases the attribute path and drops the schema urn prefix of core attributes
+func normalizeAttribute(attribute string) string {
+	attribute = strings.ToLower(attribute)
+	for _, schema := range []string{bean.UserSchema, bean.GroupSchema} {
+		prefix := strings.ToLower(schema) + ":"
+		if strings.HasPrefix(attribute, prefix) {
+			return strings.TrimPrefix(attribute, prefix)
+		}
+	}
+	return attribute
+}
+
+func matchesFilter(expr filterExpr, attributes map[string][]string) bool {
+	return expr == nil || expr.matches(attributes)
+}
+
+func getUserAttributes(user *bean.User) map[string][]string {
+	attributes := map[string][]string{
+		"id":          {user.Id},
+		"externalid":  {user.ExternalId},
+		"username":    {user.UserName},
+		"displayname": {user.DisplayName},
+		"active":      {strconv.FormatBool(user.Active != nil && *user.Active)},
+	}
+	if user.Name != nil {
+		attributes["name.givenname"] = []string{user.Name.GivenName}
+		attributes["name.familyname"] = []string{user.Name.FamilyName}
+		attributes["name.formatted"] = []string{user.Name.Formatted}
+	}
+	for _, email := range user.Emails {
+		attributes["emails"] = append(attributes["emails"], email.Value)
+		attributes["emails.value"] = append(attributes["emails.value"], email.Value)
+	}
+	for _, group := range user.Groups {
+		attributes["groups"] = append(attributes["groups"], group.Value)
+		attributes["groups.value"] = append(attributes["groups.value"], group.Value)
+		attributes["groups.display"] = append(attributes["groups.display"], group.Display)
+	}
+	return attributes
+}
+
+func getGroupAttributes(group *bean.Group) map[string][]string {
+	attributes := map[string][]string{
+		"id":          {group.Id},
+		"externalid":  {group.ExternalId},
+		"displayname": {group.DisplayName},
+	}
+	for _, member := range group.Members {
+		attributes["members"] = append(attributes["members"], member.Value)
+		attributes["members.value"] = append(attributes["members.value"], member.Value)
+	}
+	return attributes
+}
+
+// getPage returns the bounds of the requested page, startIndex is 1 based
+func getPage(total, startIndex, count int) (int, int) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 {
+		count = 0
+	}
+	if count > bean.MaxResults {
+		count = bean.MaxResults
+	}
+	start := startIndex - 1
+	if start > total {
+		start = total
+	}
+	end := start + count
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// applyUserPatch applies patch operations to the user, attributes devtron does not keep are ignored
+func applyUserPatch(user *bean.User, operations []*bean.PatchOperation) error {
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		if op != patchOpAdd && op != patchOpReplace && op != patchOpRemove {
+			return invalidValue(fmt.Sprintf("unsupported patch operation %s", operation.Op))
+		}
+		if len(operation.Path) == 0 {
+			if op == patchOpRemove {
+				return bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidPath, "remove operation needs a path")
+			}
+			values := make(map[string]json.RawMessage)
+			if err := json.Unmarshal(operation.Value, &values); err != nil {
+				return invalidValue("patch operation without path must have an object value")
+			}
+			for path, value := range values {
+				if err := setUserAttribute(user, op, normalizeAttribute(path), value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := setUserAttribute(user, op, normalizeAttribute(operation.Path), operation.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setUserAttribute(user *bean.User, op, path string, value json.RawMessage) error {
+	if path == "name" {
+		if op == patchOpRemove {
+			user.Name = nil
+			return nil
+		}
+		values := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(value, &values); err != nil {
+			return invalidValue("name must be an object")
+		}
+		for subPath, subValue := range values {
+			if err := setUserAttribute(user, op, "name."+strings.ToLower(subPath), subValue); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if path == "active" {
+		if op == patchOpRemove {
+			return nil
+		}
+		active, err := parsePatchBool(value)
+		if err != nil {
+			return err
+		}
+		user.Active = &active
+		return nil
+	}
+	target := getUserStringAttribute(user, path)
+	if target == nil {
+		// attributes like title or enterprise extension ones are not kept by devtron
+		return nil
+	}
+	if op == patchOpRemove {
+		*target = ""
+		return nil
+	}
+	var stringValue string
+	if err := json.Unmarshal(value, &stringValue); err != nil {
+		return invalidValue(fmt.Sprintf("%s must be a string", path))
+	}
+	*target = stringValue
+	return nil
+}
+
+func getUserStringAttribute(user *bean.User, path string) *string {
+	switch path {
+	case "username":
+		return &user.UserName
+	case "externalid":
+		return &user.ExternalId
+	case "displayname":
+		return &user.DisplayName
+	case "name.givenname", "name.familyname", "name.formatted":
+		if user.Name == nil {
+			user.Name = &bean.Name{}
+		}
+		switch path {
+		case "name.givenname":
+			return &user.Name.GivenName
+		case "name.familyname":
+			return &user.Name.FamilyName
+		default:
+			return &user.Name.Formatted
+		}
+	}
+	return nil
+}
+
+// parsePatchBool reads a boolean value, some identity providers send booleans as "True" or "False"
+func parsePatchBool(value json.RawMessage) (bool, error) {
+	var boolValue bool
+	if err := json.Unmarshal(value, &boolValue); err == nil {
+		return boolValue, nil
+	}
+	var stringValue string
+	if err := json.Unmarshal(value, &stringValue); err == nil {
+		if parsed, err := strconv.ParseBool(strings.ToLower(stringValue)); err == nil {
+			return parsed, nil
+		}
+	}
+	return false, invalidValue("active must be a boolean")
+}
+
+// groupPatchState is the part of a group a patch can change, memberIds keep the member order
+type groupPatchState struct {
+	displayName string
+	externalId  string
+	memberIds   []string
+}
+
+// applyGroupPatch applies patch operations to the group, members are referred by user id
+func applyGroupPatch(state *groupPatchState, operations []*bean.PatchOperation) error {
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		if op != patchOpAdd && op != patchOpReplace && op != patchOpRemove {
+			return invalidValue(fmt.Sprintf("unsupported patch operation %s", operation.Op))
+		}
+		path := strings.TrimSpace(operation.Path)
+		if len(path) == 0 {
+			if op == patchOpRemove {
+				return bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidPath, "remove operation needs a path")
+			}
+			values := make(map[string]json.RawMessage)
+			if err := json.Unmarshal(operation.Value, &values); err != nil {
+				return invalidValue("patch operation without path must have an object value")
+			}
+			for attribute, value := range values {
+				if err := setGroupAttribute(state, op, normalizeAttribute(attribute), value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(path), membersAttribute+"[") {
+			if op != patchOpRemove {
+				return bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidPath, "member filters are only supported by remove operations")
+			}
+			if !strings.HasSuffix(path, "]") {
+				return bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidPath, "member filter is not closed")
+			}
+			expr, err := parseFilter(path[len(membersAttribute)+1 : len(path)-1])
+			if err != nil {
+				return err
+			}
+			remaining := make([]string, 0, len(state.memberIds))
+			for _, memberId := range state.memberIds {
+				if !matchesFilter(expr, map[string][]string{"value": {memberId}}) {
+					remaining = append(remaining, memberId)
+				}
+			}
+			state.memberIds = remaining
+			continue
+		}
+		if err := setGroupAttribute(state, op, normalizeAttribute(path), operation.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setGroupAttribute(state *groupPatchState, op, path string, value json.RawMessage) error {
+	switch path {
+	case membersAttribute:
+		var memberIds []string
+		if len(value) > 0 {
+			var err error
+			memberIds, err = parseMemberIds(value)
+			if err != nil {
+				return err
+			}
+		}
+		switch op {
+		case patchOpReplace:
+			state.memberIds = uniqueIds(memberIds)
+		case patchOpAdd:
+			state.memberIds = uniqueIds(append(state.memberIds, memberIds...))
+		case patchOpRemove:
+			// a remove without value removes every member
+			if len(value) == 0 {
+				state.memberIds = nil
+				return nil
+			}
+			removed := make(map[string]bool, len(memberIds))
+			for _, memberId := range memberIds {
+				removed[memberId] = true
+			}
+			remaining := make([]string, 0, len(state.memberIds))
+			for _, memberId := range state.memberIds {
+				if !removed[memberId] {
+					remaining = append(remaining, memberId)
+				}
+			}
+			state.memberIds = remaining
+		}
+	case "displayname", "externalid":
+		target := &state.displayName
+		if path == "externalid" {
+			target = &state.externalId
+		}
+		if op == patchOpRemove {
+			*target = ""
+			return nil
+		}
+		if err := json.Unmarshal(value, target); err != nil {
+			return invalidValue(fmt.Sprintf("%s must be a string", path))
+		}
+	default:
+		return bean.NewScimError(http.StatusBadRequest, bean.ScimTypeInvalidPath, fmt.Sprintf("unsupported group attribute %s", path))
+	}
+	return nil
+}
+
+// parseMemberIds reads a list of members, or a single member as some identity providers send
+func parseMemberIds(value json.RawMessage) ([]string, error) {
+	var members []*bean.Member
+	if err := json.Unmarshal(value, &members); err != nil {
+		member := &bean.Member{}
+		if err := json.Unmarshal(value, member); err != nil {
+			return nil, invalidValue("members must be a list of objects with a value")
+		}
+		members = []*bean.Member{member}
+	}
+	memberIds := make([]string, 0, len(members))
+	for _, member := range members {
+		if len(member.Value) == 0 {
+			return nil, invalidValue("member value is required")
+		}
+		memberIds = append(memberIds, member.Value)
+	}
+	return memberIds, nil
+}
+
+func uniqueIds(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// diffIds returns ids of desired missing in current, and ids of current missing in desired
+func diffIds(current, desired []string) ([]string, []string) {
+	currentIds := make(map[string]bool, len(current))
+	for _, id := range current {
+		currentIds[id] = true
+	}
+	desiredIds := make(map[string]bool, len(desired))
+	added := make([]string, 0)
+	for _, id := range desired {
+		desiredIds[id] = true
+		if !currentIds[id] {
+			added = append(added, id)
+		}
+	}
+	removed := make([]string, 0)
+	for _, id := range current {
+		if !desiredIds[id] {
+			removed = append(removed, id)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scim
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	active := true
+	user := &bean.User{
+		Id:          "2",
+		ExternalId:  "00u1",
+		UserName:    "Jane.Doe@example.com",
+		DisplayName: "Jane Doe",
+		Name:        &bean.Name{GivenName: "Jane", FamilyName: "Doe"},
+		Emails:      []*bean.Email{{Value: "jane.doe@example.com"}},
+		Active:      &active,
+	}
+	tests := []struct {
+		name    string
+		filter  string
+		matches bool
+		wantErr bool
+	}{
+		{name: "empty", filter: "", matches: true},
+		{name: "username eq ignores case", filter: `userName eq "jane.doe@example.com"`, matches: true},
+		{name: "schema prefixed attribute", filter: `urn:ietf:params:scim:schemas:core:2.0:User:userName eq "jane.doe@example.com"`, matches: true},
+		{name: "external id is case sensitive", filter: `externalId eq "00U1"`, matches: false},
+		{name: "sub attribute", filter: `name.familyName sw "d"`, matches: true},
+		{name: "multi valued", filter: `emails co "@example"`, matches: true},
+		{name: "boolean", filter: `active eq true`, matches: true},
+		{name: "and", filter: `userName ew "example.com" and active eq false`, matches: false},
+		{name: "or with parentheses", filter: `(userName eq "x") or (displayName eq "jane doe")`, matches: true},
+		{name: "not", filter: `not (displayName pr)`, matches: false},
+		{name: "ne", filter: `userName ne "x"`, matches: true},
+		{name: "complex attribute filter", filter: `emails[type eq "work"]`, wantErr: true},
+		{name: "missing value", filter: `userName eq`, wantErr: true},
+		{name: "unknown operator", filter: `userName like "x"`, wantErr: true},
+		{name: "unclosed parenthesis", filter: `(userName eq "x"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := parseFilter(tt.filter)
+			if tt.wantErr {
+				assert.Error(t, err)
+				scimErr, ok := err.(*bean.ScimError)
+				if assert.True(t, ok) {
+					assert.Equal(t, bean.ScimTypeInvalidFilter, scimErr.ScimType)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.matches, matchesFilter(expr, getUserAttributes(user)))
+		})
+	}
+}
+
+func TestGetPage(t *testing.T) {
+	tests := []struct {
+		name               string
+		total, start, size int
+		wantStart, wantEnd int
+	}{
+		{name: "first page", total: 10, start: 1, size: 3, wantStart: 0, wantEnd: 3},
+		{name: "last partial page", total: 10, start: 9, size: 5, wantStart: 8, wantEnd: 10},
+		{name: "start index below one", total: 10, start: 0, size: 2, wantStart: 0, wantEnd: 2},
+		{name: "start index after total", total: 10, start: 20, size: 2, wantStart: 10, wantEnd: 10},
+		{name: "zero count", total: 10, start: 1, size: 0, wantStart: 0, wantEnd: 0},
+		{name: "count capped", total: 500, start: 1, size: 1000, wantStart: 0, wantEnd: bean.MaxResults},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := getPage(tt.total, tt.start, tt.size)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
+
+func TestApplyUserPatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations []*bean.PatchOperation
+		want       *bean.User
+		wantErr    bool
+	}{
+		{name: "deactivate", operations: []*bean.PatchOperation{{Op: "replace", Path: "active", Value: json.RawMessage(`false`)}},
+			want: &bean.User{UserName: "a@example.com", Active: boolPtr(false)}},
+		{name: "string boolean", operations: []*bean.PatchOperation{{Op: "Replace", Path: "active", Value: json.RawMessage(`"False"`)}},
+			want: &bean.User{UserName: "a@example.com", Active: boolPtr(false)}},
+		{name: "without path", operations: []*bean.PatchOperation{{Op: "replace", Value: json.RawMessage(`{"active":false,"name":{"givenName":"A"},"displayName":"A B"}`)}},
+			want: &bean.User{UserName: "a@example.com", Active: boolPtr(false), DisplayName: "A B", Name: &bean.Name{GivenName: "A"}}},
+		{name: "sub attribute path", operations: []*bean.PatchOperation{{Op: "add", Path: "name.familyName", Value: json.RawMessage(`"B"`)}},
+			want: &bean.User{UserName: "a@example.com", Active: boolPtr(true), Name: &bean.Name{FamilyName: "B"}}},
+		{name: "unknown attribute ignored", operations: []*bean.PatchOperation{{Op: "replace", Path: "title", Value: json.RawMessage(`"Engineer"`)}},
+			want: &bean.User{UserName: "a@example.com", Active: boolPtr(true)}},
+		{name: "invalid boolean", operations: []*bean.PatchOperation{{Op: "replace", Path: "active", Value: json.RawMessage(`"no"`)}}, wantErr: true},
+		{name: "unsupported operation", operations: []*bean.PatchOperation{{Op: "move", Path: "active"}}, wantErr: true},
+		{name: "remove without path", operations: []*bean.PatchOperation{{Op: "remove"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &bean.User{UserName: "a@example.com", Active: boolPtr(true)}
+			err := applyUserPatch(user, tt.operations)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, user)
+		})
+	}
+}
+
+func TestApplyGroupPatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		operations  []*bean.PatchOperation
+		wantMembers []string
+		wantErr     bool
+	}{
+		{name: "add members", operations: []*bean.PatchOperation{{Op: "add", Path: "members", Value: json.RawMessage(`[{"value":"3"},{"value":"1"}]`)}},
+			wantMembers: []string{"1", "2", "3"}},
+		{name: "add single member", operations: []*bean.PatchOperation{{Op: "Add", Path: "members", Value: json.RawMessage(`{"value":"4"}`)}},
+			wantMembers: []string{"1", "2", "4"}},
+		{name: "remove member by filter", operations: []*bean.PatchOperation{{Op: "remove", Path: `members[value eq "2"]`}},
+			wantMembers: []string{"1"}},
+		{name: "remove member by value", operations: []*bean.PatchOperation{{Op: "remove", Path: "members", Value: json.RawMessage(`[{"value":"1"}]`)}},
+			wantMembers: []string{"2"}},
+		{name: "remove all members", operations: []*bean.PatchOperation{{Op: "remove", Path: "members"}},
+			wantMembers: nil},
+		{name: "replace members", operations: []*bean.PatchOperation{{Op: "replace", Path: "members", Value: json.RawMessage(`[{"value":"5"},{"value":"5"}]`)}},
+			wantMembers: []string{"5"}},
+		{name: "replace without path", operations: []*bean.PatchOperation{{Op: "replace", Value: json.RawMessage(`{"externalId":"g1"}`)}},
+			wantMembers: []string{"1", "2"}},
+		{name: "add with member filter", operations: []*bean.PatchOperation{{Op: "add", Path: `members[value eq "2"]`}}, wantErr: true},
+		{name: "member without value", operations: []*bean.PatchOperation{{Op: "add", Path: "members", Value: json.RawMessage(`[{"display":"x"}]`)}}, wantErr: true},
+		{name: "unknown attribute", operations: []*bean.PatchOperation{{Op: "replace", Path: "owner", Value: json.RawMessage(`"x"`)}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &groupPatchState{displayName: "devs", memberIds: []string{"1", "2"}}
+			err := applyGroupPatch(state, tt.operations)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMembers, state.memberIds)
+		})
+	}
+}
+
+func TestDiffIds(t *testing.T) {
+	added, removed := diffIds([]string{"1", "2", "3"}, []string{"3", "4"})
+	assert.Equal(t, []string{"4"}, added)
+	assert.Equal(t, []string{"1", "2"}, removed)
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+)
+
+// ScimUser keeps the attributes the identity provider sets on a user which devtron does not store,
+// a row exists for every user provisioned or deactivated through scim
+type ScimUser struct {
+	tableName   struct{} `sql:"scim_user" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	UserId      int32    `sql:"user_id,notnull"`
+	ExternalId  string   `sql:"external_id"`
+	DisplayName string   `sql:"display_name"`
+	GivenName   string   `sql:"given_name"`
+	FamilyName  string   `sql:"family_name"`
+	sql.AuditLog
+}
+
+// ScimGroup links a role group to the identity provider, only linked role groups are exposed through scim
+type ScimGroup struct {
+	tableName   struct{} `sql:"scim_group" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	RoleGroupId int32    `sql:"role_group_id,notnull"`
+	ExternalId  string   `sql:"external_id"`
+	// Provisioned is set when the role group was created through scim, such role groups are deleted with the scim group
+	Provisioned bool `sql:"provisioned,notnull"`
+	sql.AuditLog
+}
+
+type ScimRepository interface {
+	SaveUser(model *ScimUser) error
+	UpdateUser(model *ScimUser) error
+	FindUserByUserId(userId int32) (*ScimUser, error)
+	FindAllUsers() ([]*ScimUser, error)
+	DeleteUserByUserId(userId int32) error
+	// FindVisibleUsers returns active users, and inactive users deactivated through scim, excluding api token users
+	FindVisibleUsers() ([]*userRepository.UserModel, error)
+
+	SaveGroup(model *ScimGroup) error
+	UpdateGroup(model *ScimGroup) error
+	FindGroupByRoleGroupId(roleGroupId int32) (*ScimGroup, error)
+	FindAllGroups() ([]*ScimGroup, error)
+	DeleteGroup(model *ScimGroup) error
+}
+
+type ScimRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewScimRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ScimRepositoryImpl {
+	return &ScimRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ScimRepositoryImpl) SaveUser(model *ScimUser) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *ScimRepositoryImpl) UpdateUser(model *ScimUser) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *ScimRepositoryImpl) FindUserByUserId(userId int32) (*ScimUser, error) {
+	model := &ScimUser{}
+	err := impl.dbConnection.Model(model).Where("user_id = ?", userId).Select()
+	return model, err
+}
+
+func (impl *ScimRepositoryImpl) FindAllUsers() ([]*ScimUser, error) {
+	var models []*ScimUser
+	err := impl.dbConnection.Model(&models).Select()
+	return models, err
+}
+
+func (impl *ScimRepositoryImpl) DeleteUserByUserId(userId int32) error {
+	_, err := impl.dbConnection.Model((*ScimUser)(nil)).Where("user_id = ?", userId).Delete()
+	return err
+}
+
+func (impl *ScimRepositoryImpl) FindVisibleUsers() ([]*userRepository.UserModel, error) {
+	var models []*userRepository.UserModel
+	err := impl.dbConnection.Model(&models).
+		Where("user_type IS NULL OR user_type != ?", apiBean.USER_TYPE_API_TOKEN).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.Where("active = ?", true).
+				WhereOr("EXISTS (SELECT 1 FROM scim_user WHERE scim_user.user_id = user_model.id)"), nil
+		}).
+		Order("id").
+		Select()
+	return models, err
+}
+
+func (impl *ScimRepositoryImpl) SaveGroup(model *ScimGroup) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *ScimRepositoryImpl) UpdateGroup(model *ScimGroup) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *ScimRepositoryImpl) FindGroupByRoleGroupId(roleGroupId int32) (*ScimGroup, error) {
+	model := &ScimGroup{}
+	err := impl.dbConnection.Model(model).Where("role_group_id = ?", roleGroupId).Select()
+	return model, err
+}
+
+func (impl *ScimRepositoryImpl) FindAllGroups() ([]*ScimGroup, error) {
+	var models []*ScimGroup
+	err := impl.dbConnection.Model(&models).Order("role_group_id").Select()
+	return models, err
+}
+
+func (impl *ScimRepositoryImpl) DeleteGroup(model *ScimGroup) error {
+	return impl.dbConnection.Delete(model)
+}
@@ -19,10 +19,14 @@ package sso
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/auth/authentication"
+	"github.com/devtron-labs/devtron/pkg/auth/sso/repository"
+	repository2 "github.com/devtron-labs/devtron/pkg/auth/user/repository"
 
 	"github.com/devtron-labs/devtron/api/bean"
 	util2 "github.com/devtron-labs/devtron/util"
@@ -37,11 +41,14 @@ type SSOLoginService interface {
 	GetById(id int32) (*bean.SSOLoginDto, error)
 	GetAll() ([]*bean.SSOLoginDto, error)
 	GetByName(name string) (*bean.SSOLoginDto, error)
+	// DeactivateSSOLogin removes the connector of the config from dex, other active connectors stay as they are
+	DeactivateSSOLogin(id int32, userId int32) (*bean.SSOLoginDto, error)
 }
 
 type SSOLoginServiceImpl struct {
 	logger              *zap.SugaredLogger
-	ssoLoginRepository  SSOLoginRepository
+	ssoLoginRepository  repository.SSOLoginRepository
+	roleGroupRepository repository2.RoleGroupRepository
 	K8sUtil             *k8s.K8sServiceImpl
 	devtronSecretConfig *util2.DevtronSecretConfig
 	userAuthOidcHelper  authentication.UserAuthOidcHelper
@@ -98,11 +105,13 @@ const (
 
 func NewSSOLoginServiceImpl(
 	logger *zap.SugaredLogger,
-	ssoLoginRepository SSOLoginRepository,
-	K8sUtil *k8s.K8sServiceImpl, envVariables *util2.EnvironmentVariables, userAuthOidcHelper authentication.UserAuthOidcHelper) *SSOLoginServiceImpl {
+	ssoLoginRepository repository.SSOLoginRepository,
+	K8sUtil *k8s.K8sServiceImpl, envVariables *util2.EnvironmentVariables, userAuthOidcHelper authentication.UserAuthOidcHelper,
+	roleGroupRepository repository2.RoleGroupRepository) *SSOLoginServiceImpl {
 	serviceImpl := &SSOLoginServiceImpl{
 		logger:              logger,
 		ssoLoginRepository:  ssoLoginRepository,
+		roleGroupRepository: roleGroupRepository,
 		K8sUtil:             K8sUtil,
 		devtronSecretConfig: envVariables.DevtronSecretConfig,
 		userAuthOidcHelper:  userAuthOidcHelper,
@@ -123,27 +132,25 @@ func (impl SSOLoginServiceImpl) CreateSSOLogin(request *bean.SSOLoginDto) (*bean
 	if err != nil {
 		return nil, err
 	}
-
-	existingModel, err := impl.ssoLoginRepository.GetActive()
-	if err != nil && err != pg.ErrNoRows {
-		impl.logger.Errorw("error in creating new sso login config", "error", err)
+	connectorId, err := getConnectorId(request.Config)
+	if err != nil {
 		return nil, err
 	}
-	if existingModel != nil && existingModel.Id > 0 {
-		existingModel.Active = false
-		existingModel.UpdatedOn = time.Now()
-		existingModel.UpdatedBy = request.UserId
-		_, err = impl.ssoLoginRepository.Update(existingModel, tx)
-		if err != nil {
-			impl.logger.Errorw("error in creating new sso login config", "error", err)
-			return nil, err
-		}
+	err = impl.validateConnector(0, connectorId, request.ProvisioningPolicy)
+	if err != nil {
+		return nil, err
 	}
-	model := &SSOLoginModel{
-		Name:   request.Name,
-		Label:  request.Label,
-		Config: string(configDataByte),
-		Url:    request.Url,
+	provisioningPolicy, err := getProvisioningPolicyJson(request.ProvisioningPolicy)
+	if err != nil {
+		return nil, err
+	}
+	model := &repository.SSOLoginModel{
+		Name:               request.Name,
+		Label:              request.Label,
+		Config:             string(configDataByte),
+		Url:                request.Url,
+		ConnectorId:        connectorId,
+		ProvisioningPolicy: provisioningPolicy,
 	}
 	model.Active = true
 	model.CreatedBy = request.UserId
@@ -156,6 +163,8 @@ func (impl SSOLoginServiceImpl) CreateSSOLogin(request *bean.SSOLoginDto) (*bean
 		return nil, err
 	}
 	request.Id = model.Id
+	request.Active = true
+	request.ConnectorId = connectorId
 	_, err = impl.updateDexConfig(request)
 	if err != nil {
 		impl.logger.Errorw("error in creating new sso login config", "error", err)
@@ -192,22 +201,17 @@ func (impl SSOLoginServiceImpl) UpdateSSOLogin(request *bean.SSOLoginDto) (*bean
 		return nil, err
 	}
 
-	existingModel, err := impl.ssoLoginRepository.GetActive()
-	if err != nil && err != pg.ErrNoRows {
-		impl.logger.Errorw("error in creating new sso login config", "error", err)
+	connectorId, err := getConnectorId(request.Config)
+	if err != nil {
 		return nil, err
 	}
-	if existingModel != nil && existingModel.Id > 0 {
-		if existingModel.Id != model.Id {
-			existingModel.Active = false
-			existingModel.UpdatedOn = time.Now()
-			existingModel.UpdatedBy = request.UserId
-			_, err = impl.ssoLoginRepository.Update(existingModel, tx)
-			if err != nil {
-				impl.logger.Errorw("error in creating new sso login config", "error", err)
-				return nil, err
-			}
-		}
+	err = impl.validateConnector(model.Id, connectorId, request.ProvisioningPolicy)
+	if err != nil {
+		return nil, err
+	}
+	provisioningPolicy, err := getProvisioningPolicyJson(request.ProvisioningPolicy)
+	if err != nil {
+		return nil, err
 	}
 	configString := string(configDataByte)
 	var configData Config
@@ -232,6 +236,8 @@ func (impl SSOLoginServiceImpl) UpdateSSOLogin(request *bean.SSOLoginDto) (*bean
 	model.Label = request.Label
 	model.Url = request.Url
 	model.Config = updatedConfig
+	model.ConnectorId = connectorId
+	model.ProvisioningPolicy = provisioningPolicy
 	model.Active = true
 	model.UpdatedBy = request.UserId
 	model.UpdatedOn = time.Now()
@@ -241,6 +247,8 @@ func (impl SSOLoginServiceImpl) UpdateSSOLogin(request *bean.SSOLoginDto) (*bean
 		return nil, err
 	}
 	request.Config = newConfigString
+	request.Active = true
+	request.ConnectorId = connectorId
 	_, err = impl.updateDexConfig(request)
 	if err != nil {
 		impl.logger.Errorw("error in creating new sso login config", "error", err)
@@ -258,8 +266,15 @@ func (impl SSOLoginServiceImpl) UpdateSSOLogin(request *bean.SSOLoginDto) (*bean
 	return request, nil
 }
 
+// updateDexConfig writes the connectors of all active configs to dex, request holds the config being saved which is not committed yet
 func (impl SSOLoginServiceImpl) updateDexConfig(request *bean.SSOLoginDto) (bool, error) {
 	flag := false
+	activeModels, err := impl.ssoLoginRepository.GetAllActive()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching active sso login configs", "error", err)
+		return flag, err
+	}
+	connectors := getDexConnectors(activeModels, request)
 	k8sClient, err := impl.K8sUtil.GetClientForInCluster()
 	if err != nil {
 		impl.logger.Errorw("exception in fetching client", "error", err)
@@ -274,7 +289,7 @@ func (impl SSOLoginServiceImpl) updateDexConfig(request *bean.SSOLoginDto) (bool
 			impl.logger.Errorw("exception in fetching configmap", "error", err)
 			return flag, err
 		}
-		updatedData, err := impl.updateSSODexConfigOnDevtronSecret(request.Config)
+		updatedData, err := impl.updateSSODexConfigOnDevtronSecret(connectors)
 		if err != nil {
 			impl.logger.Errorw("exception in update configmap sso config", "error", err)
 			return flag, err
@@ -284,7 +299,9 @@ func (impl SSOLoginServiceImpl) updateDexConfig(request *bean.SSOLoginDto) (bool
 			data = make(map[string][]byte)
 		}
 		data["dex.config"] = []byte(updatedData["dex.config"])
-		data["url"] = []byte(request.Url)
+		if request.Url != "" {
+			data["url"] = []byte(request.Url)
+		}
 		secret.Data = data
 		_, err = impl.K8sUtil.UpdateSecret(impl.devtronSecretConfig.DevtronDexSecretNamespace, secret, k8sClient)
 		if err != nil {
@@ -305,10 +322,8 @@ func (impl SSOLoginServiceImpl) updateDexConfig(request *bean.SSOLoginDto) (bool
 	return true, nil
 }
 
-func (impl SSOLoginServiceImpl) updateSSODexConfigOnDevtronSecret(config json.RawMessage) (map[string]string, error) {
+func (impl SSOLoginServiceImpl) updateSSODexConfigOnDevtronSecret(connectors []json.RawMessage) (map[string]string, error) {
 	connectorConfig := map[string][]json.RawMessage{}
-	var connectors []json.RawMessage
-	connectors = append(connectors, config)
 	connectorConfig["connectors"] = connectors
 	connectorsJsonByte, err := json.Marshal(connectorConfig)
 	if err != nil {
@@ -340,15 +355,7 @@ func (impl SSOLoginServiceImpl) GetById(id int32) (*bean.SSOLoginDto, error) {
 		impl.logger.Warnw("error while Unmarshal", "error", err)
 	}
 
-	ssoLoginDto := &bean.SSOLoginDto{
-		Id:     model.Id,
-		Name:   model.Name,
-		Label:  model.Label,
-		Active: model.Active,
-		Config: config,
-		Url:    model.Url,
-	}
-	return ssoLoginDto, nil
+	return toSSOLoginDto(model, config), nil
 }
 
 func (impl SSOLoginServiceImpl) GetAll() ([]*bean.SSOLoginDto, error) {
@@ -369,14 +376,7 @@ func (impl SSOLoginServiceImpl) GetAll() ([]*bean.SSOLoginDto, error) {
 			impl.logger.Warnw("error while Unmarshal", "error", err)
 		}
 
-		ssoLoginDto := &bean.SSOLoginDto{
-			Id:     model.Id,
-			Name:   model.Name,
-			Label:  model.Label,
-			Active: model.Active,
-			Url:    model.Url,
-		}
-		ssoConfigs = append(ssoConfigs, ssoLoginDto)
+		ssoConfigs = append(ssoConfigs, toSSOLoginDto(&model, nil))
 	}
 	return ssoConfigs, nil
 }
@@ -409,13 +409,80 @@ func (impl SSOLoginServiceImpl) GetByName(name string) (*bean.SSOLoginDto, error
 		impl.logger.Warnw("error while Unmarshal", "error", err)
 	}
 
-	ssoLoginDto := &bean.SSOLoginDto{
-		Id:     model.Id,
-		Name:   model.Name,
-		Label:  model.Label,
-		Active: model.Active,
-		Config: config,
-		Url:    model.Url,
+	return toSSOLoginDto(model, config), nil
+}
+
+func (impl SSOLoginServiceImpl) DeactivateSSOLogin(id int32, userId int32) (*bean.SSOLoginDto, error) {
+	model, err := impl.ssoLoginRepository.GetById(id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching sso login config", "id", id, "error", err)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "sso config not found", fmt.Sprintf("sso login config %d not found", id))
+	}
+	if !model.Active {
+		return toSSOLoginDto(model, nil), nil
+	}
+	dbConnection := impl.ssoLoginRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		return nil, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+
+	model.Active = false
+	model.UpdatedBy = userId
+	model.UpdatedOn = time.Now()
+	_, err = impl.ssoLoginRepository.Update(model, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deactivating sso login config", "id", id, "error", err)
+		return nil, err
+	}
+	_, err = impl.updateDexConfig(&bean.SSOLoginDto{Id: model.Id})
+	if err != nil {
+		impl.logger.Errorw("error in deactivating sso login config", "id", id, "error", err)
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return toSSOLoginDto(model, nil), nil
+}
+
+// validateConnector makes sure no other active config uses the connector id and the mapped role groups exist
+func (impl SSOLoginServiceImpl) validateConnector(id int32, connectorId string, policy *bean.SsoProvisioningPolicy) error {
+	existingModel, err := impl.ssoLoginRepository.GetActiveByConnectorId(connectorId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching sso login config by connector id", "connectorId", connectorId, "error", err)
+		return err
+	}
+	if err == nil && existingModel.Id > 0 && existingModel.Id != id {
+		return util.NewApiError(http.StatusConflict, fmt.Sprintf("sso config with id %s is already active", connectorId), "duplicate active connector id")
+	}
+	err = validateProvisioningPolicy(policy)
+	if err != nil {
+		return err
+	}
+	roleGroupNames := getMappedRoleGroupNames(policy)
+	if len(roleGroupNames) == 0 {
+		return nil
+	}
+	roleGroups, err := impl.roleGroupRepository.GetRoleGroupListByNames(roleGroupNames)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching role groups", "roleGroups", roleGroupNames, "error", err)
+		return err
+	}
+	found := make(map[string]bool, len(roleGroups))
+	for _, roleGroup := range roleGroups {
+		found[roleGroup.Name] = true
+	}
+	for _, roleGroupName := range roleGroupNames {
+		if !found[roleGroupName] {
+			return util.NewApiError(http.StatusBadRequest, fmt.Sprintf("role group %s not found", roleGroupName), "mapped role group not found")
+		}
 	}
-	return ssoLoginDto, nil
+	return nil
 }
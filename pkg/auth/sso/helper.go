@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sso
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/sso/repository"
+)
+
+// getConnectorId returns the dex connector id of an sso config, it is used to tell connectors apart in the id token
+func getConnectorId(config json.RawMessage) (string, error) {
+	var configData Config
+	err := json.Unmarshal(config, &configData)
+	if err != nil {
+		return "", util.NewApiError(http.StatusBadRequest, "invalid sso config", err.Error())
+	}
+	if configData.Id == "" {
+		return "", util.NewApiError(http.StatusBadRequest, "sso config id is required", "connector id missing in sso config")
+	}
+	return configData.Id, nil
+}
+
+func validateProvisioningPolicy(policy *bean.SsoProvisioningPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	groups := make(map[string]bool, len(policy.GroupMappings))
+	for _, mapping := range policy.GroupMappings {
+		if mapping == nil || mapping.Group == "" || len(mapping.RoleGroups) == 0 {
+			return util.NewApiError(http.StatusBadRequest, "group mapping needs a group and at least one role group", "invalid group mapping")
+		}
+		if groups[mapping.Group] {
+			return util.NewApiError(http.StatusBadRequest, fmt.Sprintf("group %s is mapped more than once", mapping.Group), "duplicate group mapping")
+		}
+		groups[mapping.Group] = true
+	}
+	return nil
+}
+
+// getMappedRoleGroupNames returns the distinct role groups referred to by the policy
+func getMappedRoleGroupNames(policy *bean.SsoProvisioningPolicy) []string {
+	var roleGroupNames []string
+	if policy == nil {
+		return roleGroupNames
+	}
+	seen := make(map[string]bool)
+	for _, mapping := range policy.GroupMappings {
+		for _, roleGroup := range mapping.RoleGroups {
+			if !seen[roleGroup] {
+				seen[roleGroup] = true
+				roleGroupNames = append(roleGroupNames, roleGroup)
+			}
+		}
+	}
+	return roleGroupNames
+}
+
+func getProvisioningPolicyJson(policy *bean.SsoProvisioningPolicy) (string, error) {
+	if policy == nil {
+		return "", nil
+	}
+	policyJson, err := json.Marshal(policy)
+	if err != nil {
+		return "", err
+	}
+	return string(policyJson), nil
+}
+
+func getProvisioningPolicy(model *repository.SSOLoginModel) (*bean.SsoProvisioningPolicy, error) {
+	if model.ProvisioningPolicy == "" {
+		return nil, nil
+	}
+	policy := &bean.SsoProvisioningPolicy{}
+	err := json.Unmarshal([]byte(model.ProvisioningPolicy), policy)
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// getDexConnectors returns the connectors of all active configs with the config being saved in place of its stored version
+func getDexConnectors(activeModels []repository.SSOLoginModel, request *bean.SSOLoginDto) []json.RawMessage {
+	connectors := make([]json.RawMessage, 0, len(activeModels)+1)
+	for _, model := range activeModels {
+		if model.Id == request.Id || model.Config == "" {
+			continue
+		}
+		connectors = append(connectors, json.RawMessage(model.Config))
+	}
+	if request.Active && len(request.Config) > 0 {
+		connectors = append(connectors, request.Config)
+	}
+	return connectors
+}
+
+func toSSOLoginDto(model *repository.SSOLoginModel, config json.RawMessage) *bean.SSOLoginDto {
+	ssoLoginDto := &bean.SSOLoginDto{
+		Id:          model.Id,
+		Name:        model.Name,
+		Label:       model.Label,
+		Active:      model.Active,
+		Config:      config,
+		Url:         model.Url,
+		ConnectorId: model.ConnectorId,
+	}
+	policy, err := getProvisioningPolicy(model)
+	if err == nil {
+		ssoLoginDto.ProvisioningPolicy = policy
+	}
+	return ssoLoginDto
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sso
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/sso/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetConnectorId(t *testing.T) {
+	connectorId, err := getConnectorId(json.RawMessage(`{"id":"okta","type":"oidc","name":"Okta"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "okta", connectorId)
+
+	_, err = getConnectorId(json.RawMessage(`{"type":"github","name":"GitHub"}`))
+	assert.Error(t, err)
+	_, err = getConnectorId(json.RawMessage(`not json`))
+	assert.Error(t, err)
+}
+
+func TestValidateProvisioningPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *bean.SsoProvisioningPolicy
+		wantErr bool
+	}{
+		{name: "no policy"},
+		{name: "auto provision only", policy: &bean.SsoProvisioningPolicy{AutoProvision: true}},
+		{name: "valid mappings", policy: &bean.SsoProvisioningPolicy{GroupMappings: []*bean.SsoGroupMapping{{Group: "sre", RoleGroups: []string{"admins"}}, {Group: "dev", RoleGroups: []string{"developers"}}}}},
+		{name: "mapping without role groups", policy: &bean.SsoProvisioningPolicy{GroupMappings: []*bean.SsoGroupMapping{{Group: "sre"}}}, wantErr: true},
+		{name: "mapping without group", policy: &bean.SsoProvisioningPolicy{GroupMappings: []*bean.SsoGroupMapping{{RoleGroups: []string{"admins"}}}}, wantErr: true},
+		{name: "duplicate group", policy: &bean.SsoProvisioningPolicy{GroupMappings: []*bean.SsoGroupMapping{{Group: "sre", RoleGroups: []string{"admins"}}, {Group: "sre", RoleGroups: []string{"viewers"}}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateProvisioningPolicy(tt.policy)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestGetDexConnectors(t *testing.T) {
+	activeModels := []repository.SSOLoginModel{
+		{Id: 1, Config: `{"id":"okta"}`, Active: true},
+		{Id: 2, Config: `{"id":"github"}`, Active: true},
+	}
+	toStrings := func(connectors []json.RawMessage) []string {
+		var configs []string
+		for _, connector := range connectors {
+			configs = append(configs, string(connector))
+		}
+		return configs
+	}
+
+	connectors := getDexConnectors(activeModels, &bean.SSOLoginDto{Id: 3, Active: true, Config: json.RawMessage(`{"id":"google"}`)})
+	assert.Equal(t, []string{`{"id":"okta"}`, `{"id":"github"}`, `{"id":"google"}`}, toStrings(connectors))
+
+	connectors = getDexConnectors(activeModels, &bean.SSOLoginDto{Id: 2, Active: true, Config: json.RawMessage(`{"id":"github","name":"GitHub"}`)})
+	assert.Equal(t, []string{`{"id":"okta"}`, `{"id":"github","name":"GitHub"}`}, toStrings(connectors))
+
+	connectors = getDexConnectors(activeModels, &bean.SSOLoginDto{Id: 1})
+	assert.Equal(t, []string{`{"id":"github"}`}, toStrings(connectors))
+}
+
+func TestGetMappedRoleGroupNames(t *testing.T) {
+	policy := &bean.SsoProvisioningPolicy{GroupMappings: []*bean.SsoGroupMapping{
+		{Group: "sre", RoleGroups: []string{"admins", "viewers"}},
+		{Group: "dev", RoleGroups: []string{"developers", "viewers"}},
+	}}
+	assert.Equal(t, []string{"admins", "viewers", "developers"}, getMappedRoleGroupNames(policy))
+	assert.Empty(t, getMappedRoleGroupNames(nil))
+}
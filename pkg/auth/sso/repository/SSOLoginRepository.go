@@ -14,7 +14,7 @@
  * limitations under the License.
  */
 
-package sso
+package repository
 
 import (
 	"github.com/devtron-labs/devtron/pkg/sql"
@@ -49,6 +49,8 @@ type SSOLoginRepository interface {
 	GetById(id int32) (*SSOLoginModel, error)
 	GetAll() ([]SSOLoginModel, error)
 	GetActive() (*SSOLoginModel, error)
+	GetAllActive() ([]SSOLoginModel, error)
+	GetActiveByConnectorId(connectorId string) (*SSOLoginModel, error)
 	Delete(userModel *SSOLoginModel, tx *pg.Tx) (bool, error)
 	GetByName(name string) (*SSOLoginModel, error)
 
@@ -69,13 +71,15 @@ func NewSSOLoginRepositoryImpl(dbConnection *pg.DB,
 }
 
 type SSOLoginModel struct {
-	TableName struct{} `sql:"sso_login_config"`
-	Id        int32    `sql:"id,pk"`
-	Name      string   `sql:"name,notnull"`
-	Label     string   `sql:"label"`
-	Url       string   `sql:"url"`
-	Config    string   `sql:"config"`
-	Active    bool     `sql:"active,notnull"`
+	TableName          struct{} `sql:"sso_login_config"`
+	Id                 int32    `sql:"id,pk"`
+	Name               string   `sql:"name,notnull"`
+	Label              string   `sql:"label"`
+	Url                string   `sql:"url"`
+	Config             string   `sql:"config"`
+	Active             bool     `sql:"active,notnull"`
+	ConnectorId        string   `sql:"connector_id"`
+	ProvisioningPolicy string   `sql:"provisioning_policy"`
 	sql.AuditLog
 }
 
@@ -111,6 +115,18 @@ func (impl SSOLoginRepositoryImpl) GetActive() (*SSOLoginModel, error) {
 	return &model, err
 }
 
+func (impl SSOLoginRepositoryImpl) GetAllActive() ([]SSOLoginModel, error) {
+	var models []SSOLoginModel
+	err := impl.dbConnection.Model(&models).Where("active = ?", true).Order("id asc").Select()
+	return models, err
+}
+
+func (impl SSOLoginRepositoryImpl) GetActiveByConnectorId(connectorId string) (*SSOLoginModel, error) {
+	var model SSOLoginModel
+	err := impl.dbConnection.Model(&model).Where("connector_id = ?", connectorId).Where("active = ?", true).Limit(1).Select()
+	return &model, err
+}
+
 func (impl SSOLoginRepositoryImpl) Delete(userModel *SSOLoginModel, tx *pg.Tx) (bool, error) {
 	err := tx.Delete(userModel)
 	if err != nil {
@@ -262,7 +262,7 @@ func (impl UserAuthServiceImpl) HandleLoginWithClientIp(ctx context.Context, use
 	impl.logger.Info("login with client ip")
 	token, err := impl.HandleLogin(username, password)
 	if err == nil {
-		id, _, err := impl.userService.GetUserByToken(ctx, token)
+		id, _, err := impl.userService.GetUserByToken(ctx, token, clientIp)
 		if err != nil {
 			impl.logger.Errorw("error occurred while getting user by token", "err", err)
 		} else {
@@ -401,6 +401,7 @@ func WhitelistChecker(url string) bool {
 		"/orchestrator/auth/login",
 		"/dashboard",
 		"/orchestrator/webhook/git",
+		"/orchestrator/scim/v2",
 	}
 	for _, a := range prefixUrls {
 		if strings.Contains(url, a) {
@@ -495,7 +496,7 @@ func (impl UserAuthServiceImpl) AuthVerification(r *http.Request) (bool, string,
 	// have version for api-tokens
 	// therefore, for tokens without version we will skip the below part
 	if strings.HasPrefix(emailId, userBean.API_TOKEN_USER_EMAIL_PREFIX) && len(version) > 0 {
-		err := impl.userService.CheckIfTokenIsValid(emailId, version)
+		err := impl.userService.CheckIfTokenIsValid(emailId, version, impl.userService.GetClientIp(r))
 		if err != nil {
 			impl.logger.Errorw("token is not valid", "error", err, "token", token)
 			return false, "", err
@@ -44,7 +44,7 @@ func BenchmarkCreateDefaultPoliciesForAllTypesV2(b *testing.B) {
 	defaultRbacPolicyRepo := repository.NewRbacPolicyDataRepositoryImpl(logger, dbConnection)
 	defaultRbacRoleRepo := repository.NewRbacRoleDataRepositoryImpl(logger, dbConnection)
 	defaultRbacCacheFactory := repository.NewRbacDataCacheFactoryImpl(logger, defaultRbacPolicyRepo, defaultRbacRoleRepo)
-	userCommonService := NewUserCommonServiceImpl(userAuthRepository, logger, userRepo, nil, nil, defaultRbacCacheFactory)
+	userCommonService, _ := NewUserCommonServiceImpl(userAuthRepository, logger, userRepo, nil, nil, defaultRbacCacheFactory)
 	teams := make(map[int]string, it)
 	apps := make(map[int]string, it)
 	envs := make(map[int]string, it)
@@ -17,10 +17,14 @@
 package user
 
 import (
+	"encoding/json"
 	"fmt"
 	jwt2 "github.com/devtron-labs/authenticator/jwt"
 	"github.com/devtron-labs/devtron/api/bean"
+	repository2 "github.com/devtron-labs/devtron/pkg/auth/sso/repository"
+	"github.com/devtron-labs/devtron/pkg/auth/user/helper"
 	"github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/go-pg/pg"
 	"github.com/golang-jwt/jwt/v4"
 	"go.uber.org/zap"
 )
@@ -35,14 +39,17 @@ type UserSelfRegistrationServiceImpl struct {
 	logger                          *zap.SugaredLogger
 	selfRegistrationRolesRepository repository.SelfRegistrationRolesRepository
 	userService                     UserService
+	ssoLoginRepository              repository2.SSOLoginRepository
 }
 
 func NewUserSelfRegistrationServiceImpl(logger *zap.SugaredLogger,
-	selfRegistrationRolesRepository repository.SelfRegistrationRolesRepository, userService UserService) *UserSelfRegistrationServiceImpl {
+	selfRegistrationRolesRepository repository.SelfRegistrationRolesRepository, userService UserService,
+	ssoLoginRepository repository2.SSOLoginRepository) *UserSelfRegistrationServiceImpl {
 	return &UserSelfRegistrationServiceImpl{
 		logger:                          logger,
 		selfRegistrationRolesRepository: selfRegistrationRolesRepository,
 		userService:                     userService,
+		ssoLoginRepository:              ssoLoginRepository,
 	}
 }
 
@@ -94,10 +101,19 @@ func (impl *UserSelfRegistrationServiceImpl) CheckSelfRegistrationRoles() (Check
 }
 
 func (impl *UserSelfRegistrationServiceImpl) SelfRegister(emailId string) (*bean.UserInfo, error) {
+	return impl.selfRegister(emailId, false)
+}
+
+// selfRegister creates the user with the self registration roles, with forced the user is created even if
+// self registration is disabled, this is used by sso connectors allowed to auto provision users
+func (impl *UserSelfRegistrationServiceImpl) selfRegister(emailId string, forced bool) (*bean.UserInfo, error) {
 	roles, err := impl.CheckSelfRegistrationRoles()
-	if err != nil || roles.Enabled == false {
+	if !forced && (err != nil || roles.Enabled == false) {
 		return nil, err
 	}
+	if err != nil {
+		impl.logger.Warnw("error in fetching self registration roles, auto provisioning without roles", "emailId", emailId, "err", err)
+	}
 	impl.logger.Infow("self register start")
 	userInfo := &bean.UserInfo{
 		EmailId:    emailId,
@@ -124,11 +140,14 @@ func (impl *UserSelfRegistrationServiceImpl) CheckAndCreateUserIfConfigured(clai
 	if emailId == "" && sub == "admin" {
 		emailId = sub
 	}
+	connectorId := helper.GetSsoConnectorId(claims)
+	policy := impl.getSsoProvisioningPolicy(connectorId)
 	exists := impl.userService.UserExists(emailId)
 	var id int32
 	if !exists {
-		impl.logger.Infow("self registering user,  ", "email", emailId)
-		user, err := impl.SelfRegister(emailId)
+		autoProvision := policy != nil && policy.AutoProvision
+		impl.logger.Infow("self registering user,  ", "email", emailId, "connectorId", connectorId, "autoProvision", autoProvision)
+		user, err := impl.selfRegister(emailId, autoProvision)
 		if err != nil {
 			impl.logger.Errorw("error while register user", "error", err)
 		} else if user != nil && user.Id > 0 {
@@ -136,9 +155,40 @@ func (impl *UserSelfRegistrationServiceImpl) CheckAndCreateUserIfConfigured(clai
 			exists = true
 		}
 	}
+	if exists && policy != nil {
+		roleGroups := helper.GetSsoMappedRoleGroups(policy, claims)
+		err := impl.userService.AddRoleGroupsToUser(emailId, roleGroups)
+		if err != nil {
+			impl.logger.Errorw("error in adding user to mapped role groups", "email", emailId, "connectorId", connectorId, "roleGroups", roleGroups, "err", err)
+		}
+	}
 	if exists {
 		impl.userService.SaveLoginAudit(emailId, "localhost", id)
 	}
 	impl.logger.Infow("user status", "email", emailId, "status", exists)
 	return exists
 }
+
+// getSsoProvisioningPolicy returns the provisioning policy of the active sso connector, nil when none is configured
+func (impl *UserSelfRegistrationServiceImpl) getSsoProvisioningPolicy(connectorId string) *bean.SsoProvisioningPolicy {
+	if connectorId == "" {
+		return nil
+	}
+	model, err := impl.ssoLoginRepository.GetActiveByConnectorId(connectorId)
+	if err != nil {
+		if err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching sso login config", "connectorId", connectorId, "err", err)
+		}
+		return nil
+	}
+	if model.ProvisioningPolicy == "" {
+		return nil
+	}
+	policy := &bean.SsoProvisioningPolicy{}
+	err = json.Unmarshal([]byte(model.ProvisioningPolicy), policy)
+	if err != nil {
+		impl.logger.Errorw("error in parsing sso provisioning policy", "connectorId", connectorId, "err", err)
+		return nil
+	}
+	return policy
+}
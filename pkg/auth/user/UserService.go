@@ -29,6 +29,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/caarlos0/env"
 	"github.com/devtron-labs/authenticator/jwt"
 	"github.com/devtron-labs/authenticator/middleware"
 	"github.com/devtron-labs/devtron/api/bean"
@@ -48,6 +49,8 @@ import (
 const (
 	ConcurrentRequestLockError   = "there is an ongoing request for this user, please try after some time"
 	ConcurrentRequestUnlockError = "cannot block request that is not in process"
+	// apiTokenUsageUpdateInterval is the least time between two updates of the last use of an api token
+	apiTokenUsageUpdateInterval = time.Minute
 )
 
 type UserService interface {
@@ -77,14 +80,25 @@ type UserService interface {
 	BulkDeleteUsers(request *bean.BulkDeleteRequest) (bool, error)
 	CheckUserRoles(id int32) ([]string, error)
 	SyncOrchestratorToCasbin() (bool, error)
-	GetUserByToken(context context.Context, token string) (int32, string, error)
+	// GetUserByToken checks api tokens against the ip of the client and records their use
+	GetUserByToken(context context.Context, token string, clientIp string) (int32, string, error)
 	//IsSuperAdmin(userId int) (bool, error)
 	GetByIdIncludeDeleted(id int32) (*bean.UserInfo, error)
 	UserExists(emailId string) bool
+	// AddRoleGroupsToUser adds the user to the role groups it is not part of yet, existing memberships are kept
+	AddRoleGroupsToUser(emailId string, roleGroupNames []string) error
 	UpdateTriggerPolicyForTerminalAccess() (err error)
 	GetRoleFiltersByUserRoleGroups(userRoleGroups []bean.UserRoleGroup) ([]bean.RoleFilter, error)
 	SaveLoginAudit(emailId, clientIp string, id int32)
-	CheckIfTokenIsValid(email string, version string) error
+	CheckIfTokenIsValid(email string, version string, clientIp string) error
+	// GetClientIp returns the ip of the client of the request, forwarded addresses are read only as far as the
+	// configured trusted proxies
+	GetClientIp(r *http.Request) string
+}
+
+// ClientIpConfig tells which address of a request is the client, it is checked against the allowed cidrs of api tokens
+type ClientIpConfig struct {
+	TrustedProxyCount int `env:"TRUSTED_PROXY_COUNT" envDefault:"0" description:"Number of reverse proxies in front of devtron that append to X-Forwarded-For, the client ip is the entry added by the outermost one. 0 uses the address of the connection"`
 }
 
 type UserServiceImpl struct {
@@ -99,13 +113,20 @@ type UserServiceImpl struct {
 	sessionManager2     *middleware.SessionManager
 	userCommonService   UserCommonService
 	userAuditService    UserAuditService
+	clientIpConfig      *ClientIpConfig
 }
 
 func NewUserServiceImpl(userAuthRepository repository.UserAuthRepository,
 	logger *zap.SugaredLogger,
 	userRepository repository.UserRepository,
 	userGroupRepository repository.RoleGroupRepository,
-	sessionManager2 *middleware.SessionManager, userCommonService UserCommonService, userAuditService UserAuditService) *UserServiceImpl {
+	sessionManager2 *middleware.SessionManager, userCommonService UserCommonService, userAuditService UserAuditService) (*UserServiceImpl, error) {
+	clientIpConfig := &ClientIpConfig{}
+	err := env.Parse(clientIpConfig)
+	if err != nil {
+		logger.Errorw("error in parsing client ip config", "err", err)
+		return nil, err
+	}
 	serviceImpl := &UserServiceImpl{
 		userReqState:        make(map[int32]bool),
 		userAuthRepository:  userAuthRepository,
@@ -115,9 +136,10 @@ func NewUserServiceImpl(userAuthRepository repository.UserAuthRepository,
 		sessionManager2:     sessionManager2,
 		userCommonService:   userCommonService,
 		userAuditService:    userAuditService,
+		clientIpConfig:      clientIpConfig,
 	}
 	cStore = sessions.NewCookieStore(randKey())
-	return serviceImpl
+	return serviceImpl, nil
 }
 
 func (impl *UserServiceImpl) getUserReqLockStateById(userId int32) bool {
@@ -209,7 +231,10 @@ func (impl *UserServiceImpl) SelfRegisterUserIfNotExists(userInfo *bean.UserInfo
 			return nil, err
 		}
 
-		roles, err := impl.userAuthRepository.GetRoleByRoles(userInfo.Roles)
+		var roles []repository.RoleModel
+		if len(userInfo.Roles) > 0 {
+			roles, err = impl.userAuthRepository.GetRoleByRoles(userInfo.Roles)
+		}
 		if err != nil {
 			err = &util.ApiError{
 				Code:            constants.UserCreateDBFailed,
@@ -1103,6 +1128,37 @@ func (impl *UserServiceImpl) UserExists(emailId string) bool {
 	}
 }
 
+func (impl *UserServiceImpl) AddRoleGroupsToUser(emailId string, roleGroupNames []string) error {
+	if len(roleGroupNames) == 0 {
+		return nil
+	}
+	roleGroups, err := impl.roleGroupRepository.GetRoleGroupListByNames(roleGroupNames)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching role groups", "roleGroups", roleGroupNames, "err", err)
+		return err
+	}
+	existingRoles, err := casbin2.GetRolesForUser(emailId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching roles of user", "emailId", emailId, "err", err)
+		return err
+	}
+	existingRoleMap := make(map[string]bool, len(existingRoles))
+	for _, role := range existingRoles {
+		existingRoleMap[role] = true
+	}
+	var policies []casbin2.Policy
+	for _, roleGroup := range roleGroups {
+		if !existingRoleMap[roleGroup.CasbinName] {
+			policies = append(policies, casbin2.Policy{Type: "g", Sub: casbin2.Subject(emailId), Obj: casbin2.Object(roleGroup.CasbinName)})
+		}
+	}
+	if len(policies) > 0 {
+		impl.logger.Infow("adding user to role groups", "emailId", emailId, "policies", policies)
+		casbin2.AddPolicy(policies)
+	}
+	return nil
+}
+
 func (impl *UserServiceImpl) SaveLoginAudit(emailId, clientIp string, id int32) {
 
 	if emailId != "" && id <= 0 {
@@ -1205,7 +1261,7 @@ func (impl *UserServiceImpl) GetLoggedInUser(r *http.Request) (int32, error) {
 	} else {
 		token = r.Header.Get("token")
 	}
-	userId, userType, err := impl.GetUserByToken(r.Context(), token)
+	userId, userType, err := impl.GetUserByToken(r.Context(), token, impl.GetClientIp(r))
 	// if user is of api-token type, then update lastUsedBy and lastUsedAt
 	if err == nil && userType == bean.USER_TYPE_API_TOKEN {
 		go impl.saveUserAudit(r, userId)
@@ -1213,7 +1269,11 @@ func (impl *UserServiceImpl) GetLoggedInUser(r *http.Request) (int32, error) {
 	return userId, err
 }
 
-func (impl *UserServiceImpl) GetUserByToken(context context.Context, token string) (int32, string, error) {
+func (impl *UserServiceImpl) GetClientIp(r *http.Request) string {
+	return util2.GetTrustedClientIP(r, impl.clientIpConfig.TrustedProxyCount)
+}
+
+func (impl *UserServiceImpl) GetUserByToken(context context.Context, token string, clientIp string) (int32, string, error) {
 	_, span := otel.Tracer("userService").Start(context, "GetUserByToken")
 	email, version, err := impl.GetEmailAndVersionFromToken(token)
 	span.End()
@@ -1234,7 +1294,7 @@ func (impl *UserServiceImpl) GetUserByToken(context context.Context, token strin
 	// have version for api-tokens
 	// therefore, for tokens without version we will skip the below part
 	if userInfo.UserType == bean.USER_TYPE_API_TOKEN && len(version) > 0 {
-		err := impl.CheckIfTokenIsValid(email, version)
+		err := impl.CheckIfTokenIsValid(email, version, clientIp)
 		if err != nil {
 			impl.logger.Errorw("token is not valid", "error", err, "token", token)
 			return http.StatusUnauthorized, "", err
@@ -1243,22 +1303,47 @@ func (impl *UserServiceImpl) GetUserByToken(context context.Context, token strin
 	return userInfo.Id, userInfo.UserType, nil
 }
 
-func (impl *UserServiceImpl) CheckIfTokenIsValid(email string, version string) error {
+// CheckIfTokenIsValid checks the version, state and ip restrictions of an api token and records its use
+func (impl *UserServiceImpl) CheckIfTokenIsValid(email string, version string, clientIp string) error {
 	tokenName, err := userHelper.ExtractTokenNameFromEmail(email)
 	if err != nil {
 		impl.logger.Errorw("error in extracting token name from email", "email", email, "error", err)
 		return err
 	}
+	invalidTokenErr := &util.ApiError{
+		HttpStatusCode:  http.StatusUnauthorized,
+		Code:            constants.UserNotFoundForToken,
+		InternalMessage: "user not found for token",
+		UserMessage:     fmt.Sprintf("no user found against provided token"),
+	}
+	apiTokenAccess, err := impl.userRepository.GetApiTokenAccessByName(tokenName)
+	if err != nil {
+		impl.logger.Errorw("error in getting api token", "tokenName", tokenName, "error", err)
+		return invalidTokenErr
+	}
+	now := time.Now()
 	embeddedTokenVersion, _ := strconv.Atoi(version)
-	isProvidedTokenValid, err := impl.userRepository.CheckIfTokenExistsByTokenNameAndVersion(tokenName, embeddedTokenVersion)
-	if err != nil || !isProvidedTokenValid {
-		err := &util.ApiError{
+	if !userHelper.IsApiTokenVersionValid(embeddedTokenVersion, apiTokenAccess, now) {
+		return invalidTokenErr
+	}
+	if apiTokenAccess.DisabledOn != nil {
+		return &util.ApiError{
 			HttpStatusCode:  http.StatusUnauthorized,
 			Code:            constants.UserNotFoundForToken,
-			InternalMessage: "user not found for token",
-			UserMessage:     fmt.Sprintf("no user found against provided token"),
+			InternalMessage: "api token is disabled",
+			UserMessage:     "api token is disabled as it was not used for long, rotate it to use it again",
+		}
+	}
+	if !userHelper.IsClientIpAllowed(clientIp, apiTokenAccess.AllowedCidrs) {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusUnauthorized,
+			Code:            constants.UserNotFoundForToken,
+			InternalMessage: "api token used from an ip which is not allowed",
+			UserMessage:     fmt.Sprintf("api token is not allowed from ip %s", clientIp),
 		}
-		return err
+	}
+	if len(clientIp) > 0 {
+		go impl.saveApiTokenUsage(tokenName, clientIp, now)
 	}
 	return nil
 }
@@ -1651,6 +1736,13 @@ func (impl *UserServiceImpl) UpdateTriggerPolicyForTerminalAccess() (err error)
 	return nil
 }
 
+func (impl *UserServiceImpl) saveApiTokenUsage(tokenName string, clientIp string, usedOn time.Time) {
+	err := impl.userRepository.UpdateApiTokenLastUsed(tokenName, clientIp, usedOn, apiTokenUsageUpdateInterval)
+	if err != nil {
+		impl.logger.Errorw("error in saving api token usage", "tokenName", tokenName, "error", err)
+	}
+}
+
 func (impl *UserServiceImpl) saveUserAudit(r *http.Request, userId int32) {
 	clientIp := util2.GetClientIP(r)
 	userAudit := &UserAudit{
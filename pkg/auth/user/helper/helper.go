@@ -17,14 +17,19 @@
 package helper
 
 import (
+	"encoding/base64"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	bean2 "github.com/devtron-labs/devtron/api/bean"
 	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/auth/user/bean"
 	"github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/exp/slices"
+	"net"
 	"strings"
+	"time"
 )
 
 func IsSystemOrAdminUser(userId int32) bool {
@@ -112,3 +117,117 @@ func CheckIfSuperAdminFromRoles(roles []*repository.RoleModel) bool {
 	}
 	return false
 }
+
+// IsApiTokenVersionValid accepts the current version of a token and, while a rotation overlap lasts, the previous one
+func IsApiTokenVersionValid(version int, access *repository.ApiTokenAccess, now time.Time) bool {
+	if version == access.Version {
+		return true
+	}
+	return access.PreviousVersion > 0 && version == access.PreviousVersion &&
+		access.PreviousVersionValidUntil != nil && now.Before(*access.PreviousVersionValidUntil)
+}
+
+// IsClientIpAllowed checks the client ip against the allowed cidrs, any ip is allowed when no cidr is configured.
+// clientIp is a single address as returned by util.GetTrustedClientIP, optionally with a port
+func IsClientIpAllowed(clientIp string, allowedCidrs []string) bool {
+	if len(allowedCidrs) == 0 {
+		return true
+	}
+	ip := ParseClientIp(clientIp)
+	if ip == nil {
+		return false
+	}
+	for _, allowedCidr := range allowedCidrs {
+		_, ipNet, err := net.ParseCIDR(allowedCidr)
+		if err == nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func ParseClientIp(clientIp string) net.IP {
+	clientIp = strings.TrimSpace(clientIp)
+	if host, _, err := net.SplitHostPort(clientIp); err == nil {
+		clientIp = host
+	}
+	return net.ParseIP(strings.Trim(clientIp, "[]"))
+}
+
+const defaultSsoGroupsClaim = "groups"
+
+// GetSsoConnectorId returns the dex connector a user signed in through. The federated claims are present only
+// when the federated:id scope is requested, otherwise the connector id is read from the sub claim which dex
+// issues as a base64 encoded protobuf message holding the user id (field 1) and the connector id (field 2)
+func GetSsoConnectorId(claims jwt.MapClaims) string {
+	if federatedClaims, ok := claims["federated_claims"].(map[string]interface{}); ok {
+		if connectorId, ok := federatedClaims["connector_id"].(string); ok && connectorId != "" {
+			return connectorId
+		}
+	}
+	sub, ok := claims["sub"].(string)
+	if !ok || sub == "" {
+		return ""
+	}
+	subject, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(sub, "="))
+	if err != nil {
+		return ""
+	}
+	for len(subject) > 0 {
+		tag, n := binary.Uvarint(subject)
+		if n <= 0 || tag&7 != 2 {
+			return ""
+		}
+		subject = subject[n:]
+		length, n := binary.Uvarint(subject)
+		if n <= 0 || uint64(len(subject)-n) < length {
+			return ""
+		}
+		value := subject[n : n+int(length)]
+		subject = subject[n+int(length):]
+		if tag>>3 == 2 {
+			return string(value)
+		}
+	}
+	return ""
+}
+
+// GetSsoMappedRoleGroups returns the role groups mapped to the groups the user holds in the policy's groups claim
+func GetSsoMappedRoleGroups(policy *bean2.SsoProvisioningPolicy, claims jwt.MapClaims) []string {
+	var roleGroups []string
+	if policy == nil || len(policy.GroupMappings) == 0 {
+		return roleGroups
+	}
+	groupsClaim := policy.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = defaultSsoGroupsClaim
+	}
+	userGroups := make(map[string]bool)
+	switch groups := claims[groupsClaim].(type) {
+	case string:
+		userGroups[groups] = true
+	case []string:
+		for _, group := range groups {
+			userGroups[group] = true
+		}
+	case []interface{}:
+		for _, group := range groups {
+			if groupName, ok := group.(string); ok {
+				userGroups[groupName] = true
+			}
+		}
+	}
+	seen := make(map[string]bool)
+	for _, mapping := range policy.GroupMappings {
+		if !userGroups[mapping.Group] {
+			continue
+		}
+		for _, roleGroup := range mapping.RoleGroups {
+			if !seen[roleGroup] {
+				seen[roleGroup] = true
+				roleGroups = append(roleGroups, roleGroup)
+			}
+		}
+	}
+	return roleGroups
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"encoding/base64"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestIsApiTokenVersionValid(t *testing.T) {
+	now := time.Now()
+	validUntil := now.Add(time.Hour)
+	expiredOn := now.Add(-time.Hour)
+	tests := []struct {
+		name    string
+		version int
+		access  *repository.ApiTokenAccess
+		want    bool
+	}{
+		{name: "current version", version: 3, access: &repository.ApiTokenAccess{Version: 3}, want: true},
+		{name: "old version", version: 2, access: &repository.ApiTokenAccess{Version: 3}, want: false},
+		{name: "previous version in overlap", version: 2, access: &repository.ApiTokenAccess{Version: 3, PreviousVersion: 2, PreviousVersionValidUntil: &validUntil}, want: true},
+		{name: "previous version after overlap", version: 2, access: &repository.ApiTokenAccess{Version: 3, PreviousVersion: 2, PreviousVersionValidUntil: &expiredOn}, want: false},
+		{name: "older than previous version", version: 1, access: &repository.ApiTokenAccess{Version: 3, PreviousVersion: 2, PreviousVersionValidUntil: &validUntil}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsApiTokenVersionValid(tt.version, tt.access, now))
+		})
+	}
+}
+
+func TestIsClientIpAllowed(t *testing.T) {
+	cidrs := []string{"10.0.0.0/8", "2001:db8::/32"}
+	tests := []struct {
+		name     string
+		clientIp string
+		cidrs    []string
+		want     bool
+	}{
+		{name: "no restriction", clientIp: "", want: true},
+		{name: "remote address with port", clientIp: "10.1.2.3:51234", cidrs: cidrs, want: true},
+		{name: "list of forwarded addresses", clientIp: "10.1.2.3, 172.16.0.1", cidrs: cidrs, want: false},
+		{name: "ipv6 with port", clientIp: "[2001:db8::1]:443", cidrs: cidrs, want: true},
+		{name: "outside ranges", clientIp: "192.168.1.1", cidrs: cidrs, want: false},
+		{name: "unknown ip", clientIp: "", cidrs: cidrs, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsClientIpAllowed(tt.clientIp, tt.cidrs))
+		})
+	}
+}
+
+func TestGetSsoConnectorId(t *testing.T) {
+	dexSubject := func(userId, connectorId string) string {
+		message := append([]byte{0x0a, byte(len(userId))}, userId...)
+		message = append(message, 0x12, byte(len(connectorId)))
+		message = append(message, connectorId...)
+		return base64.RawURLEncoding.EncodeToString(message)
+	}
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   string
+	}{
+		{name: "federated claims", claims: jwt.MapClaims{"federated_claims": map[string]interface{}{"connector_id": "okta"}, "sub": dexSubject("1234", "github")}, want: "okta"},
+		{name: "dex subject", claims: jwt.MapClaims{"sub": dexSubject("1234", "github")}, want: "github"},
+		{name: "padded dex subject", claims: jwt.MapClaims{"sub": dexSubject("12345", "github") + "=="}, want: "github"},
+		{name: "plain subject", claims: jwt.MapClaims{"sub": "admin"}, want: ""},
+		{name: "no subject", claims: jwt.MapClaims{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetSsoConnectorId(tt.claims))
+		})
+	}
+}
+
+func TestGetSsoMappedRoleGroups(t *testing.T) {
+	policy := &bean.SsoProvisioningPolicy{
+		GroupMappings: []*bean.SsoGroupMapping{
+			{Group: "engineering", RoleGroups: []string{"developers", "viewers"}},
+			{Group: "sre", RoleGroups: []string{"admins", "viewers"}},
+		},
+	}
+	tests := []struct {
+		name   string
+		policy *bean.SsoProvisioningPolicy
+		claims jwt.MapClaims
+		want   []string
+	}{
+		{name: "no policy", claims: jwt.MapClaims{"groups": []interface{}{"sre"}}},
+		{name: "groups from token", policy: policy, claims: jwt.MapClaims{"groups": []interface{}{"engineering", "sre", "sales"}}, want: []string{"developers", "viewers", "admins"}},
+		{name: "single group", policy: policy, claims: jwt.MapClaims{"groups": "sre"}, want: []string{"admins", "viewers"}},
+		{name: "unmapped groups", policy: policy, claims: jwt.MapClaims{"groups": []interface{}{"sales"}}},
+		{name: "custom claim", policy: &bean.SsoProvisioningPolicy{GroupsClaim: "teams", GroupMappings: policy.GroupMappings}, claims: jwt.MapClaims{"groups": []interface{}{"sre"}, "teams": []interface{}{"engineering"}}, want: []string{"developers", "viewers"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetSsoMappedRoleGroups(tt.policy, tt.claims))
+		})
+	}
+}
@@ -171,6 +171,20 @@ func (mr *MockUserServiceMockRecorder) GetEmailFromToken(token interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailFromToken", reflect.TypeOf((*MockUserService)(nil).GetEmailFromToken), token)
 }
 
+// GetClientIp mocks base method.
+func (m *MockUserService) GetClientIp(r *http.Request) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientIp", r)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetClientIp indicates an expected call of GetClientIp.
+func (mr *MockUserServiceMockRecorder) GetClientIp(r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientIp", reflect.TypeOf((*MockUserService)(nil).GetClientIp), r)
+}
+
 // GetLoggedInUser mocks base method.
 func (m *MockUserService) GetLoggedInUser(r *http.Request) (int32, error) {
 	m.ctrl.T.Helper()
@@ -202,9 +216,9 @@ func (mr *MockUserServiceMockRecorder) GetRoleFiltersByGroupNames(groupNames int
 }
 
 // GetUserByToken mocks base method.
-func (m *MockUserService) GetUserByToken(context context.Context, token string) (int32, string, error) {
+func (m *MockUserService) GetUserByToken(context context.Context, token, clientIp string) (int32, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByToken", context, token)
+	ret := m.ctrl.Call(m, "GetUserByToken", context, token, clientIp)
 	ret0, _ := ret[0].(int32)
 	ret1, _ := ret[1].(string)
 	ret2, _ := ret[2].(error)
@@ -212,9 +226,9 @@ func (m *MockUserService) GetUserByToken(context context.Context, token string)
 }
 
 // GetUserByToken indicates an expected call of GetUserByToken.
-func (mr *MockUserServiceMockRecorder) GetUserByToken(context, token interface{}) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) GetUserByToken(context, token, clientIp interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByToken", reflect.TypeOf((*MockUserService)(nil).GetUserByToken), context, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByToken", reflect.TypeOf((*MockUserService)(nil).GetUserByToken), context, token, clientIp)
 }
 
 // IsSuperAdmin mocks base method.
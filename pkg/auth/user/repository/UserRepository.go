@@ -22,12 +22,14 @@ package repository
 import (
 	"fmt"
 	"github.com/devtron-labs/devtron/api/bean"
+	casbin2 "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
 	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
 	"github.com/devtron-labs/devtron/pkg/auth/user/repository/helper"
 	"github.com/devtron-labs/devtron/pkg/auth/user/util"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
+	"strings"
 	"time"
 )
 
@@ -49,7 +51,23 @@ type UserRepository interface {
 	FetchActiveOrDeletedUserByEmail(email string) (*UserModel, error)
 	UpdateRoleIdForUserRolesMappings(roleId int, newRoleId int) (*UserRoleModel, error)
 	GetCountExecutingQuery(query string, queryParams []interface{}) (int, error)
-	CheckIfTokenExistsByTokenNameAndVersion(tokenName string, tokenVersion int) (bool, error)
+	GetApiTokenAccessByName(tokenName string) (*ApiTokenAccess, error)
+	// GetApiTokenScopes reads the scopes from the token row of an api token user,
+	// other users and tokens created before scopes have none
+	GetApiTokenScopes(emailId string) ([]casbin2.ApiTokenScope, error)
+	UpdateApiTokenLastUsed(tokenName string, clientIp string, usedOn time.Time, minInterval time.Duration) error
+}
+
+// ApiTokenAccess is the part of an api token checked while authenticating its requests
+type ApiTokenAccess struct {
+	TableName                 struct{}                `sql:"api_token" pg:",discard_unknown_columns"`
+	Name                      string                  `sql:"name"`
+	Version                   int                     `sql:"version"`
+	PreviousVersion           int                     `sql:"previous_version"`
+	PreviousVersionValidUntil *time.Time              `sql:"previous_version_valid_until"`
+	AllowedCidrs              []string                `sql:"allowed_cidrs" pg:",array"`
+	DisabledOn                *time.Time              `sql:"disabled_on"`
+	Scopes                    []casbin2.ApiTokenScope `sql:"scopes"`
 }
 
 type UserRepositoryImpl struct {
@@ -268,12 +286,34 @@ func (impl UserRepositoryImpl) GetCountExecutingQuery(query string, queryParams
 
 // below method does operation on api_token table,
 // we are writing this method here instead of ApiTokenRepository to avoid cyclic import
-func (impl UserRepositoryImpl) CheckIfTokenExistsByTokenNameAndVersion(tokenName string, tokenVersion int) (bool, error) {
-	query := impl.dbConnection.Model().
-		Table(userBean.ApiTokenTableName).
+func (impl UserRepositoryImpl) GetApiTokenAccessByName(tokenName string) (*ApiTokenAccess, error) {
+	apiTokenAccess := &ApiTokenAccess{}
+	err := impl.dbConnection.Model(apiTokenAccess).
 		Where("name = ?", tokenName).
-		Where("version = ?", tokenVersion)
+		Select()
+	return apiTokenAccess, err
+}
 
-	exists, err := query.Exists()
-	return exists, err
+func (impl UserRepositoryImpl) GetApiTokenScopes(emailId string) ([]casbin2.ApiTokenScope, error) {
+	if !strings.HasPrefix(emailId, userBean.API_TOKEN_USER_EMAIL_PREFIX) {
+		return nil, nil
+	}
+	apiTokenAccess, err := impl.GetApiTokenAccessByName(strings.TrimPrefix(emailId, userBean.API_TOKEN_USER_EMAIL_PREFIX))
+	if err == pg.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return apiTokenAccess.Scopes, nil
+}
+
+// UpdateApiTokenLastUsed skips the update if the token was marked used within minInterval, to avoid a write on every request
+func (impl UserRepositoryImpl) UpdateApiTokenLastUsed(tokenName string, clientIp string, usedOn time.Time, minInterval time.Duration) error {
+	_, err := impl.dbConnection.Model(&ApiTokenAccess{}).
+		Set("last_used_at = ?", usedOn).
+		Set("last_used_by_ip = ?", clientIp).
+		Where("name = ?", tokenName).
+		Where("(last_used_at IS NULL OR last_used_at < ?)", usedOn.Add(-minInterval)).
+		Update()
+	return err
 }
@@ -38,6 +38,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef"
 	chartRefBean "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/read"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/pkg/variables"
 	variablesRepository "github.com/devtron-labs/devtron/pkg/variables/repository"
@@ -91,6 +92,7 @@ type ChartServiceImpl struct {
 	gitOpsConfigReadService          config.GitOpsConfigReadService
 	deploymentConfigService          common.DeploymentConfigService
 	envConfigOverrideReadService     read.EnvConfigOverrideService
+	projectGuardrailService          projectGuardrail.ProjectGuardrailService
 }
 
 func NewChartServiceImpl(chartRepository chartRepoRepository.ChartRepository,
@@ -108,7 +110,8 @@ func NewChartServiceImpl(chartRepository chartRepoRepository.ChartRepository,
 	chartRefService chartRef.ChartRefService,
 	gitOpsConfigReadService config.GitOpsConfigReadService,
 	deploymentConfigService common.DeploymentConfigService,
-	envConfigOverrideReadService read.EnvConfigOverrideService) *ChartServiceImpl {
+	envConfigOverrideReadService read.EnvConfigOverrideService,
+	projectGuardrailService projectGuardrail.ProjectGuardrailService) *ChartServiceImpl {
 	return &ChartServiceImpl{
 		chartRepository:                  chartRepository,
 		logger:                           logger,
@@ -126,6 +129,7 @@ func NewChartServiceImpl(chartRepository chartRepoRepository.ChartRepository,
 		gitOpsConfigReadService:          gitOpsConfigReadService,
 		deploymentConfigService:          deploymentConfigService,
 		envConfigOverrideReadService:     envConfigOverrideReadService,
+		projectGuardrailService:          projectGuardrailService,
 	}
 }
 
@@ -139,6 +143,11 @@ func (impl *ChartServiceImpl) Create(templateRequest TemplateRequest, ctx contex
 		impl.logger.Errorw("error in getting missing chart for chartRefId", "err", err, "chartRefId")
 		return nil, err
 	}
+	err = impl.projectGuardrailService.ValidateChartRefUpdate(templateRequest.AppId, templateRequest.ChartRefId)
+	if err != nil {
+		impl.logger.Errorw("chart ref not allowed by project guardrail", "appId", templateRequest.AppId, "chartRefId", templateRequest.ChartRefId, "err", err)
+		return nil, err
+	}
 	chartMeta, err := impl.getChartMetaData(templateRequest)
 	if err != nil {
 		return nil, err
@@ -654,6 +663,13 @@ func (impl *ChartServiceImpl) UpdateAppOverride(ctx context.Context, templateReq
 	if err != nil {
 		return nil, err
 	}
+	if currentLatestChart.Id != templateRequest.Id {
+		err = impl.projectGuardrailService.ValidateChartRefUpdate(templateRequest.AppId, template.ChartRefId)
+		if err != nil {
+			impl.logger.Errorw("chart ref not allowed by project guardrail", "appId", templateRequest.AppId, "chartRefId", template.ChartRefId, "err", err)
+			return nil, err
+		}
+	}
 	if currentLatestChart.Id > 0 && currentLatestChart.Id == templateRequest.Id {
 
 	} else if currentLatestChart.Id != templateRequest.Id {
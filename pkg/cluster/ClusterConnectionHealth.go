@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"sync"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/middleware"
+	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
+	"github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/go-pg/pg"
+	"k8s.io/client-go/kubernetes"
+)
+
+// connectionCheck holds what is measured while checking the connection of a cluster besides the error
+type connectionCheck struct {
+	latency       time.Duration
+	serverVersion string
+}
+
+func (impl *ClusterServiceImpl) getConnectionCheck(k8sClientSet *kubernetes.Clientset, clusterId int, checkStartedOn time.Time, respMap *sync.Map) *connectionCheck {
+	check := &connectionCheck{latency: time.Since(checkStartedOn)}
+	if value, ok := respMap.Load(clusterId); ok && value != nil {
+		return check
+	}
+	serverVersion, err := k8sClientSet.Discovery().ServerVersion()
+	if err != nil {
+		impl.logger.Warnw("error in fetching server version of cluster", "clusterId", clusterId, "err", err)
+		return check
+	}
+	check.serverVersion = serverVersion.String()
+	return check
+}
+
+// saveConnectionHealth keeps the result of a connectivity check of every cluster, respMap holds the connection errors
+func (impl *ClusterServiceImpl) saveConnectionHealth(clusters []*bean.ClusterBean, respMap *sync.Map, checks *sync.Map) {
+	checkedOn := time.Now()
+	models := make([]*repository.ClusterConnectionHealth, 0, len(clusters))
+	for _, cluster := range clusters {
+		if cluster.IsVirtualCluster {
+			continue
+		}
+		model := &repository.ClusterConnectionHealth{
+			ClusterId: cluster.Id,
+			CheckedOn: checkedOn,
+		}
+		value, found := respMap.Load(cluster.Id)
+		if !found {
+			model.ErrorMessage = "connection not checked"
+		} else if connectionError, ok := value.(error); ok && connectionError != nil {
+			model.ErrorMessage = connectionError.Error()
+			if len(model.ErrorMessage) > 2000 {
+				model.ErrorMessage = "unable to connect to cluster"
+			}
+		}
+		model.Healthy = len(model.ErrorMessage) == 0
+		var latency time.Duration
+		if value, ok := checks.Load(cluster.Id); ok {
+			if check, ok := value.(*connectionCheck); ok {
+				latency = check.latency
+				model.ServerVersion = check.serverVersion
+			}
+		}
+		model.LatencyMs = latency.Milliseconds()
+		models = append(models, model)
+		middleware.RecordClusterConnectionHealthMetrics(cluster.ClusterName, model.Healthy, latency.Seconds())
+	}
+	err := impl.clusterConnectionHealthRepository.SaveAll(models)
+	if err != nil {
+		impl.logger.Errorw("error in saving cluster connection health", "err", err)
+	}
+}
+
+func (impl *ClusterServiceImpl) deleteExpiredConnectionHealth() {
+	if impl.clusterConfig == nil || impl.clusterConfig.ClusterHealthRetentionDays <= 0 {
+		return
+	}
+	before := time.Now().AddDate(0, 0, -impl.clusterConfig.ClusterHealthRetentionDays)
+	deleted, err := impl.clusterConnectionHealthRepository.DeleteCheckedBefore(before)
+	if err != nil {
+		impl.logger.Errorw("error in deleting expired cluster connection health", "before", before, "err", err)
+		return
+	}
+	impl.logger.Debugw("deleted expired cluster connection health", "before", before, "deleted", deleted)
+}
+
+func (impl *ClusterServiceImpl) GetConnectionHealth(clusterIds []int, from time.Time, withHistory bool) ([]*bean.ClusterConnectionHealthSummary, error) {
+	var clusters []repository.Cluster
+	var err error
+	if len(clusterIds) == 0 {
+		clusters, err = impl.clusterRepository.FindAllActiveExceptVirtual()
+	} else {
+		clusters, err = impl.clusterRepository.FindByIds(clusterIds)
+	}
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching clusters", "clusterIds", clusterIds, "err", err)
+		return nil, err
+	}
+	var models []*repository.ClusterConnectionHealth
+	if len(clusters) == 1 {
+		models, err = impl.clusterConnectionHealthRepository.FindByClusterIdCheckedAfter(clusters[0].Id, from)
+	} else {
+		models, err = impl.clusterConnectionHealthRepository.FindAllCheckedAfter(from)
+	}
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching cluster connection health", "clusterIds", clusterIds, "from", from, "err", err)
+		return nil, err
+	}
+	modelsByClusterId := make(map[int][]*repository.ClusterConnectionHealth)
+	for _, model := range models {
+		modelsByClusterId[model.ClusterId] = append(modelsByClusterId[model.ClusterId], model)
+	}
+	var flapThreshold int
+	if impl.clusterConfig != nil {
+		flapThreshold = impl.clusterConfig.ClusterHealthFlapThreshold
+	}
+	summaries := make([]*bean.ClusterConnectionHealthSummary, 0, len(clusters))
+	for _, cluster := range clusters {
+		if cluster.IsVirtualCluster {
+			continue
+		}
+		summaries = append(summaries, adapter.GetClusterConnectionHealthSummary(cluster.Id, cluster.ClusterName, modelsByClusterId[cluster.Id], flapThreshold, withHistory))
+	}
+	return summaries, nil
+}
@@ -84,6 +84,8 @@ type ClusterService interface {
 	FindByIdWithoutConfig(id int) (*bean.ClusterBean, error)
 	FindByIds(id []int) ([]bean.ClusterBean, error)
 	Update(ctx context.Context, bean *bean.ClusterBean, userId int32) (*bean.ClusterBean, error)
+	// CheckIfConfigIsValid connects to the cluster with the config of the bean
+	CheckIfConfigIsValid(cluster *bean.ClusterBean) error
 	Delete(bean *bean.ClusterBean, userId int32) error
 
 	FindAllForAutoComplete() ([]bean.ClusterBean, error)
@@ -98,6 +100,8 @@ type ClusterService interface {
 	ConvertClusterBeanObjectToCluster(bean *bean.ClusterBean) *v1alpha1.Cluster
 
 	GetClusterConfigByClusterId(clusterId int) (*k8s.ClusterConfig, error)
+	// GetConnectionHealth sums up the connectivity checks since from of the given clusters, of all active clusters if none is given
+	GetConnectionHealth(clusterIds []int, from time.Time, withHistory bool) ([]*bean.ClusterConnectionHealthSummary, error)
 }
 
 type ClusterServiceImpl struct {
@@ -109,6 +113,9 @@ type ClusterServiceImpl struct {
 	userRepository      repository3.UserRepository
 	roleGroupRepository repository3.RoleGroupRepository
 	clusterReadService  read.ClusterReadService
+
+	clusterConnectionHealthRepository repository.ClusterConnectionHealthRepository
+	clusterConfig                     *globalUtil.GlobalClusterConfig
 }
 
 func NewClusterServiceImpl(repository repository.ClusterRepository, logger *zap.SugaredLogger,
@@ -117,16 +124,19 @@ func NewClusterServiceImpl(repository repository.ClusterRepository, logger *zap.
 	roleGroupRepository repository3.RoleGroupRepository,
 	envVariables *globalUtil.EnvironmentVariables,
 	cronLogger *cronUtil.CronLoggerImpl,
-	clusterReadService read.ClusterReadService) (*ClusterServiceImpl, error) {
+	clusterReadService read.ClusterReadService,
+	clusterConnectionHealthRepository repository.ClusterConnectionHealthRepository) (*ClusterServiceImpl, error) {
 	clusterService := &ClusterServiceImpl{
-		clusterRepository:   repository,
-		logger:              logger,
-		K8sUtil:             K8sUtil,
-		K8sInformerFactory:  K8sInformerFactory,
-		userAuthRepository:  userAuthRepository,
-		userRepository:      userRepository,
-		roleGroupRepository: roleGroupRepository,
-		clusterReadService:  clusterReadService,
+		clusterRepository:                 repository,
+		logger:                            logger,
+		K8sUtil:                           K8sUtil,
+		K8sInformerFactory:                K8sInformerFactory,
+		userAuthRepository:                userAuthRepository,
+		userRepository:                    userRepository,
+		roleGroupRepository:               roleGroupRepository,
+		clusterReadService:                clusterReadService,
+		clusterConnectionHealthRepository: clusterConnectionHealthRepository,
+		clusterConfig:                     envVariables.GlobalClusterConfig,
 	}
 	// initialise cron
 	newCron := cron.New(cron.WithChain(cron.Recover(cronLogger)))
@@ -186,6 +196,7 @@ func (impl *ClusterServiceImpl) getAndUpdateClusterConnectionStatus() {
 		return
 	}
 	impl.ConnectClustersInBatch(clusters, true)
+	impl.deleteExpiredConnectionHealth()
 }
 
 func (impl *ClusterServiceImpl) Save(parent context.Context, bean *bean.ClusterBean, userId int32) (*bean.ClusterBean, error) {
@@ -777,6 +788,8 @@ func (impl *ClusterServiceImpl) updateConnectionStatusForVirtualCluster(respMap
 func (impl *ClusterServiceImpl) ConnectClustersInBatch(clusters []*bean.ClusterBean, clusterExistInDb bool) {
 	var wg sync.WaitGroup
 	respMap := &sync.Map{}
+	// checks holds the latency and server version of the clusters, it is only kept for clusters saved in db
+	checks := &sync.Map{}
 	for idx, cluster := range clusters {
 		if cluster.IsVirtualCluster {
 			impl.updateConnectionStatusForVirtualCluster(respMap, cluster.Id, cluster.ClusterName)
@@ -785,6 +798,7 @@ func (impl *ClusterServiceImpl) ConnectClustersInBatch(clusters []*bean.ClusterB
 		wg.Add(1)
 		go func(idx int, cluster *bean.ClusterBean) {
 			defer wg.Done()
+			checkStartedOn := time.Now()
 			clusterConfig := cluster.GetClusterConfig()
 			_, _, k8sClientSet, err := impl.K8sUtil.GetK8sConfigAndClients(clusterConfig)
 			if err != nil {
@@ -797,11 +811,17 @@ func (impl *ClusterServiceImpl) ConnectClustersInBatch(clusters []*bean.ClusterB
 				id = idx
 			}
 			impl.GetAndUpdateConnectionStatusForOneCluster(k8sClientSet, id, respMap)
+			if clusterExistInDb {
+				checks.Store(id, impl.getConnectionCheck(k8sClientSet, id, checkStartedOn, respMap))
+			}
 		}(idx, cluster)
 	}
 
 	wg.Wait()
 	impl.HandleErrorInClusterConnections(clusters, respMap, clusterExistInDb)
+	if clusterExistInDb {
+		impl.saveConnectionHealth(clusters, respMap, checks)
+	}
 }
 
 func (impl *ClusterServiceImpl) HandleErrorInClusterConnections(clusters []*bean.ClusterBean, respMap *sync.Map, clusterExistInDb bool) {
@@ -44,3 +44,47 @@ func GetClusterBean(model repository.Cluster) bean.ClusterBean {
 	}
 	return clusterBean
 }
+
+// GetClusterConnectionHealthSummary sums up the checks of a cluster which are expected in ascending order of check time
+func GetClusterConnectionHealthSummary(clusterId int, clusterName string, models []*repository.ClusterConnectionHealth, flapThreshold int, withHistory bool) *bean.ClusterConnectionHealthSummary {
+	summary := &bean.ClusterConnectionHealthSummary{
+		ClusterId:   clusterId,
+		ClusterName: clusterName,
+		Checks:      len(models),
+	}
+	if len(models) == 0 {
+		return summary
+	}
+	var healthyChecks int
+	var totalLatencyMs int64
+	for idx, model := range models {
+		if model.Healthy {
+			healthyChecks++
+			totalLatencyMs += model.LatencyMs
+			summary.ServerVersion = model.ServerVersion
+		} else {
+			summary.LastError = model.ErrorMessage
+			checkedOn := model.CheckedOn
+			summary.LastErrorOn = &checkedOn
+		}
+		if idx > 0 && models[idx-1].Healthy != model.Healthy {
+			summary.StateChanges++
+		}
+		if withHistory {
+			summary.History = append(summary.History, &bean.ClusterConnectionHealth{
+				Healthy:       model.Healthy,
+				LatencyMs:     model.LatencyMs,
+				ServerVersion: model.ServerVersion,
+				ErrorMessage:  model.ErrorMessage,
+				CheckedOn:     model.CheckedOn,
+			})
+		}
+	}
+	summary.Healthy = models[len(models)-1].Healthy
+	summary.HealthyPercentage = float64(healthyChecks*100) / float64(len(models))
+	if healthyChecks > 0 {
+		summary.AverageLatencyMs = totalLatencyMs / int64(healthyChecks)
+	}
+	summary.Flapping = flapThreshold > 0 && summary.StateChanges >= flapThreshold
+	return summary
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetClusterConnectionHealthSummary(t *testing.T) {
+	now := time.Now()
+	models := []*repository.ClusterConnectionHealth{
+		{Healthy: true, LatencyMs: 100, ServerVersion: "v1.28.1", CheckedOn: now.Add(-4 * time.Minute)},
+		{Healthy: false, ErrorMessage: "timeout", CheckedOn: now.Add(-3 * time.Minute)},
+		{Healthy: true, LatencyMs: 300, ServerVersion: "v1.28.2", CheckedOn: now.Add(-2 * time.Minute)},
+		{Healthy: true, LatencyMs: 200, ServerVersion: "v1.28.2", CheckedOn: now.Add(-time.Minute)},
+	}
+
+	summary := GetClusterConnectionHealthSummary(1, "prod", models, 2, false)
+	assert.Equal(t, 4, summary.Checks)
+	assert.Equal(t, float64(75), summary.HealthyPercentage)
+	assert.Equal(t, int64(200), summary.AverageLatencyMs)
+	assert.Equal(t, 2, summary.StateChanges)
+	assert.True(t, summary.Flapping)
+	assert.True(t, summary.Healthy)
+	assert.Equal(t, "v1.28.2", summary.ServerVersion)
+	assert.Equal(t, "timeout", summary.LastError)
+	assert.Equal(t, models[1].CheckedOn, *summary.LastErrorOn)
+	assert.Empty(t, summary.History)
+
+	summary = GetClusterConnectionHealthSummary(1, "prod", models, 3, true)
+	assert.False(t, summary.Flapping)
+	assert.Len(t, summary.History, 4)
+
+	summary = GetClusterConnectionHealthSummary(1, "prod", nil, 3, true)
+	assert.Equal(t, 0, summary.Checks)
+	assert.False(t, summary.Healthy)
+}
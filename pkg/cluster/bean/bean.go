@@ -3,6 +3,7 @@ package bean
 import (
 	"github.com/devtron-labs/common-lib/utils/k8s"
 	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
+	"time"
 )
 
 const (
@@ -81,3 +82,28 @@ type DefaultClusterComponent struct {
 	EnvName        string `json:"envName"`
 	Status         string `json:"status"`
 }
+
+type ClusterConnectionHealth struct {
+	Healthy       bool      `json:"healthy"`
+	LatencyMs     int64     `json:"latencyMs"`
+	ServerVersion string    `json:"serverVersion,omitempty"`
+	ErrorMessage  string    `json:"errorMessage,omitempty"`
+	CheckedOn     time.Time `json:"checkedOn"`
+}
+
+// ClusterConnectionHealthSummary sums up the connectivity checks of a cluster in a time window,
+// a cluster is flapping when its health changed at least the configured number of times in the window
+type ClusterConnectionHealthSummary struct {
+	ClusterId         int                        `json:"clusterId"`
+	ClusterName       string                     `json:"clusterName"`
+	Checks            int                        `json:"checks"`
+	HealthyPercentage float64                    `json:"healthyPercentage"`
+	AverageLatencyMs  int64                      `json:"averageLatencyMs"`
+	StateChanges      int                        `json:"stateChanges"`
+	Flapping          bool                       `json:"flapping"`
+	Healthy           bool                       `json:"healthy"`
+	ServerVersion     string                     `json:"serverVersion,omitempty"`
+	LastError         string                     `json:"lastError,omitempty"`
+	LastErrorOn       *time.Time                 `json:"lastErrorOn,omitempty"`
+	History           []*ClusterConnectionHealth `json:"history,omitempty"`
+}
@@ -70,6 +70,11 @@ type EnvironmentService interface {
 	GetDetailsById(envId int) (*repository.Environment, error)
 }
 
+// NamespaceProvisioner sets up the namespace of a created environment, it is only available to the full build
+type NamespaceProvisioner interface {
+	ProvisionNamespace(environment *bean2.EnvironmentBean, userId int32)
+}
+
 type EnvironmentServiceImpl struct {
 	environmentRepository repository.EnvironmentRepository
 	logger                *zap.SugaredLogger
@@ -80,6 +85,7 @@ type EnvironmentServiceImpl struct {
 	userAuthService      user.UserAuthService
 	attributesRepository repository2.AttributesRepository
 	clusterReadService   read.ClusterReadService
+	namespaceProvisioner NamespaceProvisioner
 }
 
 func NewEnvironmentServiceImpl(environmentRepository repository.EnvironmentRepository,
@@ -101,6 +107,18 @@ func NewEnvironmentServiceImpl(environmentRepository repository.EnvironmentRepos
 	}
 }
 
+// NewEnvironmentServiceImplWithNamespaceProvisioner calls the provisioner with every environment created along with its namespace
+func NewEnvironmentServiceImplWithNamespaceProvisioner(environmentRepository repository.EnvironmentRepository,
+	clusterService cluster.ClusterService, logger *zap.SugaredLogger,
+	K8sUtil *util2.K8sServiceImpl, k8sInformerFactory informer.K8sInformerFactory,
+	userAuthService user.UserAuthService, attributesRepository repository2.AttributesRepository,
+	clusterReadService read.ClusterReadService, namespaceProvisioner NamespaceProvisioner) *EnvironmentServiceImpl {
+	impl := NewEnvironmentServiceImpl(environmentRepository, clusterService, logger, K8sUtil, k8sInformerFactory,
+		userAuthService, attributesRepository, clusterReadService)
+	impl.namespaceProvisioner = namespaceProvisioner
+	return impl
+}
+
 func (impl EnvironmentServiceImpl) Create(mappings *bean2.EnvironmentBean, userId int32) (*bean2.EnvironmentBean, error) {
 	existingEnvs, err := impl.environmentRepository.FindByClusterId(mappings.ClusterId)
 	if err != nil && !util.IsErrNoRows(err) {
@@ -164,6 +182,10 @@ func (impl EnvironmentServiceImpl) Create(mappings *bean2.EnvironmentBean, userI
 	}
 	mappings.Id = model.Id
 	mappings.ClusterServerUrl = clusterBean.ServerUrl
+	if len(model.Namespace) > 0 && impl.namespaceProvisioner != nil {
+		mappings.ClusterName = clusterBean.ClusterName
+		impl.namespaceProvisioner.ProvisionNamespace(mappings, userId)
+	}
 	return mappings, nil
 }
 
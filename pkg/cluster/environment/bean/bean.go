@@ -31,8 +31,11 @@ type EnvironmentBean struct {
 	AppCount               int      `json:"appCount"`
 	IsVirtualEnvironment   bool     `json:"isVirtualEnvironment"`
 	AllowedDeploymentTypes []string `json:"allowedDeploymentTypes"`
-	ClusterServerUrl       string   `json:"-"`
-	ErrorInConnecting      string   `json:"-"`
+	// NamespaceTemplateId is the template applied to the namespace of an environment being created,
+	// the default template is applied when it is not set
+	NamespaceTemplateId int    `json:"namespaceTemplateId,omitempty"`
+	ClusterServerUrl    string `json:"-"`
+	ErrorInConnecting   string `json:"-"`
 }
 
 type EnvDto struct {
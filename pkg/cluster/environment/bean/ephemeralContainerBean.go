@@ -1,6 +1,9 @@
 package bean
 
-import "github.com/devtron-labs/devtron/pkg/cluster/repository"
+import (
+	"github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"time"
+)
 
 type EphemeralContainerRequest struct {
 	BasicData                   *EphemeralContainerBasicData    `json:"basicData"`
@@ -9,7 +12,11 @@ type EphemeralContainerRequest struct {
 	ClusterId                   int                             `json:"clusterId" validate:"gt=0"`
 	PodName                     string                          `json:"podName"   validate:"required"`
 	ExternalArgoApplicationName string                          `json:"externalArgoApplicationName,omitempty"`
-	UserId                      int32                           `json:"-"`
+	// DebugProfileId is the admin defined profile the container is created with, the image of BasicData
+	// must be one of its images and defaults to its first one
+	DebugProfileId int        `json:"debugProfileId,omitempty"`
+	ExpiresOn      *time.Time `json:"-"`
+	UserId         int32      `json:"-"`
 }
 
 type EphemeralContainerAdvancedData struct {
@@ -31,5 +38,7 @@ func (request EphemeralContainerRequest) GetContainerBean() repository.Ephemeral
 		TargetContainer:     request.BasicData.TargetContainerName,
 		Config:              request.AdvancedData.Manifest,
 		IsExternallyCreated: false,
+		DebugProfileId:      request.DebugProfileId,
+		ExpiresOn:           request.ExpiresOn,
 	}
 }
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"time"
+
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// ClusterConnectionHealth is one connectivity check of a cluster, unlike cluster.error_in_connecting which holds
+// the latest error only these are kept for the retention period to make flapping clusters visible
+type ClusterConnectionHealth struct {
+	tableName     struct{}  `sql:"cluster_connection_health" pg:",discard_unknown_columns"`
+	Id            int       `sql:"id,pk"`
+	ClusterId     int       `sql:"cluster_id,notnull"`
+	Healthy       bool      `sql:"healthy,notnull"`
+	LatencyMs     int64     `sql:"latency_ms,notnull"`
+	ServerVersion string    `sql:"server_version"`
+	ErrorMessage  string    `sql:"error_message"`
+	CheckedOn     time.Time `sql:"checked_on,notnull"`
+}
+
+type ClusterConnectionHealthRepository interface {
+	SaveAll(models []*ClusterConnectionHealth) error
+	FindByClusterIdCheckedAfter(clusterId int, from time.Time) ([]*ClusterConnectionHealth, error)
+	FindAllCheckedAfter(from time.Time) ([]*ClusterConnectionHealth, error)
+	DeleteCheckedBefore(before time.Time) (int, error)
+}
+
+type ClusterConnectionHealthRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewClusterConnectionHealthRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ClusterConnectionHealthRepositoryImpl {
+	return &ClusterConnectionHealthRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ClusterConnectionHealthRepositoryImpl) SaveAll(models []*ClusterConnectionHealth) error {
+	if len(models) == 0 {
+		return nil
+	}
+	_, err := impl.dbConnection.Model(&models).Insert()
+	return err
+}
+
+func (impl *ClusterConnectionHealthRepositoryImpl) FindByClusterIdCheckedAfter(clusterId int, from time.Time) ([]*ClusterConnectionHealth, error) {
+	var models []*ClusterConnectionHealth
+	err := impl.dbConnection.Model(&models).
+		Where("cluster_id = ?", clusterId).
+		Where("checked_on >= ?", from).
+		Order("checked_on asc").
+		Select()
+	return models, err
+}
+
+func (impl *ClusterConnectionHealthRepositoryImpl) FindAllCheckedAfter(from time.Time) ([]*ClusterConnectionHealth, error) {
+	var models []*ClusterConnectionHealth
+	err := impl.dbConnection.Model(&models).
+		Where("checked_on >= ?", from).
+		Order("cluster_id asc").
+		Order("checked_on asc").
+		Select()
+	return models, err
+}
+
+func (impl *ClusterConnectionHealthRepositoryImpl) DeleteCheckedBefore(before time.Time) (int, error) {
+	res, err := impl.dbConnection.Model(&ClusterConnectionHealth{}).
+		Where("checked_on < ?", before).
+		Delete()
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
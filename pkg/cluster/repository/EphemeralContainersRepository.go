@@ -38,6 +38,17 @@ type EphemeralContainerBean struct {
 	TargetContainer     string   `sql:"target_container"`
 	Config              string   `sql:"config"`
 	IsExternallyCreated bool     `sql:"is_externally_created"`
+	DebugProfileId      int      `sql:"debug_profile_id"`
+	// ExpiresOn is when the process of a container created with a debug profile having a ttl exits
+	ExpiresOn *time.Time `sql:"expires_on"`
+	// CleanedUpOn is when the pod of the container was found deleted or recreated after its session ended
+	CleanedUpOn *time.Time `sql:"cleaned_up_on"`
+}
+
+// EndedEphemeralContainer is a container whose session was terminated or expired while its pod may still carry it
+type EndedEphemeralContainer struct {
+	EphemeralContainerBean
+	TerminatedOn *time.Time `sql:"terminated_on"`
 }
 
 type EphemeralContainerAction struct {
@@ -54,6 +65,9 @@ type EphemeralContainersRepository interface {
 	SaveEphemeralContainerData(tx *pg.Tx, model *EphemeralContainerBean) error
 	SaveEphemeralContainerActionAudit(tx *pg.Tx, model *EphemeralContainerAction) error
 	FindContainerByName(clusterID int, namespace, podName, name string) (*EphemeralContainerBean, error)
+	// FindEndedSessionContainers returns the containers created by devtron which were terminated or expired before now and are not cleaned up yet
+	FindEndedSessionContainers(now time.Time) ([]*EndedEphemeralContainer, error)
+	MarkCleanedUp(ids []int, cleanedUpOn time.Time) error
 }
 
 func NewEphemeralContainersRepositoryImpl(db *pg.DB, transactionUtilImpl *sql.TransactionUtilImpl) *EphemeralContainersRepositoryImpl {
@@ -92,3 +106,26 @@ func (impl EphemeralContainersRepositoryImpl) FindContainerByName(clusterID int,
 	}
 	return container, nil
 }
+
+func (impl EphemeralContainersRepositoryImpl) FindEndedSessionContainers(now time.Time) ([]*EndedEphemeralContainer, error) {
+	var containers []*EndedEphemeralContainer
+	query := `SELECT ec.*, terminated.terminated_on FROM ephemeral_container ec
+		LEFT JOIN (SELECT ephemeral_container_id, max(performed_at) AS terminated_on FROM ephemeral_container_actions
+			WHERE action_type = ? GROUP BY ephemeral_container_id) terminated ON terminated.ephemeral_container_id = ec.id
+		WHERE ec.is_externally_created = false AND ec.cleaned_up_on IS NULL
+		AND (terminated.terminated_on IS NOT NULL OR ec.expires_on < ?)
+		ORDER BY ec.cluster_id, ec.id;`
+	_, err := impl.dbConnection.Query(&containers, query, ActionTerminate, now)
+	return containers, err
+}
+
+func (impl EphemeralContainersRepositoryImpl) MarkCleanedUp(ids []int, cleanedUpOn time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := impl.dbConnection.Model((*EphemeralContainerBean)(nil)).
+		Set("cleaned_up_on = ?", cleanedUpOn).
+		Where("id IN (?)", pg.In(ids)).
+		Update()
+	return err
+}
@@ -7,6 +7,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	repository "github.com/devtron-labs/devtron/pkg/cluster/repository"
+
+	time "time"
 )
 
 // EphemeralContainersRepository is an autogenerated mock type for the EphemeralContainersRepository type
@@ -51,6 +53,43 @@ func (_m *EphemeralContainersRepository) FindContainerByName(clusterID int, name
 	return r0, r1
 }
 
+// FindEndedSessionContainers provides a mock function with given fields: now
+func (_m *EphemeralContainersRepository) FindEndedSessionContainers(now time.Time) ([]*repository.EndedEphemeralContainer, error) {
+	ret := _m.Called(now)
+
+	var r0 []*repository.EndedEphemeralContainer
+	if rf, ok := ret.Get(0).(func(time.Time) []*repository.EndedEphemeralContainer); ok {
+		r0 = rf(now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*repository.EndedEphemeralContainer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkCleanedUp provides a mock function with given fields: ids, cleanedUpOn
+func (_m *EphemeralContainersRepository) MarkCleanedUp(ids []int, cleanedUpOn time.Time) error {
+	ret := _m.Called(ids, cleanedUpOn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]int, time.Time) error); ok {
+		r0 = rf(ids, cleanedUpOn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RollbackTx provides a mock function with given fields: tx
 func (_m *EphemeralContainersRepository) RollbackTx(tx *pg.Tx) error {
 	ret := _m.Called(tx)
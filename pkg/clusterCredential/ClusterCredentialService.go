@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterCredential
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/env"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/internal/util"
+	bean2 "github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/clusterCredential/bean"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	eventUtil "github.com/devtron-labs/devtron/util/event"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type ClusterCredentialService interface {
+	// GetExpiry returns the credential expiry of the active clusters, of all of them when clusterIds is empty
+	GetExpiry(clusterIds []int) ([]*bean.ClusterCredentialExpiry, error)
+	// RotateCredentials connects to the cluster with the new credentials before saving them, the argo cd cluster
+	// secret and the informers of the cluster are refreshed with the saved credentials
+	RotateCredentials(ctx context.Context, request *bean.RotateCredentialsRequest) (*bean.ClusterCredentialExpiry, error)
+	// WarnExpiringCredentials notifies about the clusters with credentials expired or expiring within the warning period
+	WarnExpiringCredentials()
+}
+
+type ClusterCredentialServiceImpl struct {
+	logger         *zap.SugaredLogger
+	clusterService cluster.ClusterService
+	eventClient    client.EventClient
+	serviceConfig  *bean.ClusterCredentialServiceConfig
+}
+
+func NewClusterCredentialServiceImpl(logger *zap.SugaredLogger,
+	clusterService cluster.ClusterService,
+	eventClient client.EventClient,
+	cronLogger *cron2.CronLoggerImpl) (*ClusterCredentialServiceImpl, error) {
+	serviceConfig := &bean.ClusterCredentialServiceConfig{}
+	err := env.Parse(serviceConfig)
+	if err != nil {
+		logger.Errorw("error in parsing cluster credential config", "err", err)
+		return nil, err
+	}
+	impl := &ClusterCredentialServiceImpl{
+		logger:         logger,
+		clusterService: clusterService,
+		eventClient:    eventClient,
+		serviceConfig:  serviceConfig,
+	}
+	expiryCron := cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	_, err = expiryCron.AddFunc(serviceConfig.ExpiryCheckCron, impl.WarnExpiringCredentials)
+	if err != nil {
+		logger.Errorw("error in adding cluster credential expiry cron", "schedule", serviceConfig.ExpiryCheckCron, "err", err)
+		return nil, err
+	}
+	expiryCron.Start()
+	return impl, nil
+}
+
+func (impl *ClusterCredentialServiceImpl) GetExpiry(clusterIds []int) ([]*bean.ClusterCredentialExpiry, error) {
+	clusters, err := impl.clusterService.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in fetching active clusters", "err", err)
+		return nil, err
+	}
+	now := time.Now()
+	expiries := make([]*bean.ClusterCredentialExpiry, 0, len(clusters))
+	for _, activeCluster := range clusters {
+		if activeCluster.IsVirtualCluster || (len(clusterIds) > 0 && !slices.Contains(clusterIds, activeCluster.Id)) {
+			continue
+		}
+		expiries = append(expiries, impl.getClusterExpiry(&activeCluster, now))
+	}
+	return expiries, nil
+}
+
+func (impl *ClusterCredentialServiceImpl) RotateCredentials(ctx context.Context, request *bean.RotateCredentialsRequest) (*bean.ClusterCredentialExpiry, error) {
+	existingCluster, err := impl.clusterService.FindById(request.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster", "clusterId", request.ClusterId, "err", err)
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "cluster not found", err.Error())
+		}
+		return nil, err
+	}
+	if existingCluster.IsVirtualCluster {
+		return nil, util.NewApiError(http.StatusBadRequest, "virtual clusters have no credentials", "virtual cluster")
+	}
+	if existingCluster.ClusterName == clusterBean.DEFAULT_CLUSTER {
+		return nil, util.NewApiError(http.StatusBadRequest, "credentials of default_cluster are managed by the system", "default cluster")
+	}
+	rotatedConfig, changed := getRotatedConfig(existingCluster.Config, request)
+	if !changed {
+		return nil, util.NewApiError(http.StatusBadRequest, "new credentials are the same as the current ones", "credentials unchanged")
+	}
+	for _, credential := range getCredentialExpiries(rotatedConfig, existingCluster.InsecureSkipTLSVerify, time.Now(), impl.serviceConfig.ExpiryWarningDays) {
+		if credential.Status == bean.ExpiryStatusExpired {
+			message := fmt.Sprintf("new %s has expired", credential.Type)
+			return nil, util.NewApiError(http.StatusBadRequest, message, message)
+		}
+	}
+
+	// the current credentials stay in use until the new ones are known to work
+	existingCluster.Config = rotatedConfig
+	err = impl.clusterService.CheckIfConfigIsValid(existingCluster)
+	if err != nil {
+		impl.logger.Errorw("error in connecting to cluster with new credentials", "clusterId", request.ClusterId, "err", err)
+		return nil, util.NewApiError(http.StatusBadRequest, "unable to connect to the cluster with the new credentials", err.Error())
+	}
+	updatedCluster, err := impl.clusterService.Update(ctx, existingCluster, request.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in saving rotated cluster credentials", "clusterId", request.ClusterId, "err", err)
+		return nil, err
+	}
+	impl.logger.Infow("cluster credentials rotated", "clusterId", request.ClusterId, "userId", request.UserId)
+	return impl.getClusterExpiry(updatedCluster, time.Now()), nil
+}
+
+func (impl *ClusterCredentialServiceImpl) WarnExpiringCredentials() {
+	expiries, err := impl.GetExpiry(nil)
+	if err != nil {
+		return
+	}
+	for _, expiry := range expiries {
+		if expiry.Status == bean.ExpiryStatusExpiringSoon || expiry.Status == bean.ExpiryStatusExpired {
+			impl.notify(expiry)
+		}
+	}
+}
+
+func (impl *ClusterCredentialServiceImpl) getClusterExpiry(cluster *clusterBean.ClusterBean, now time.Time) *bean.ClusterCredentialExpiry {
+	credentials := getCredentialExpiries(cluster.Config, cluster.InsecureSkipTLSVerify, now, impl.serviceConfig.ExpiryWarningDays)
+	return getClusterCredentialExpiry(cluster.Id, cluster.ClusterName, credentials)
+}
+
+// notify sends the expiring credentials of a cluster to the notification settings of the cluster
+func (impl *ClusterCredentialServiceImpl) notify(expiry *bean.ClusterCredentialExpiry) {
+	var reasons []string
+	for _, credential := range expiry.Credentials {
+		if credential.Status == bean.ExpiryStatusExpired {
+			reasons = append(reasons, fmt.Sprintf("%s expired on %s", credential.Type, credential.ExpiresOn.Format(time.RFC3339)))
+		} else if credential.Status == bean.ExpiryStatusExpiringSoon {
+			reasons = append(reasons, fmt.Sprintf("%s expires in %d days on %s", credential.Type, credential.DaysLeft, credential.ExpiresOn.Format(time.RFC3339)))
+		}
+	}
+	impl.logger.Warnw("cluster credentials expiring", "clusterId", expiry.ClusterId, "clusterName", expiry.ClusterName, "reasons", reasons)
+	event := client.Event{
+		EventTypeId: int(eventUtil.Fail),
+		EventName:   bean.ExpiryEventName,
+		EventTime:   time.Now().Format(bean2.LayoutRFC3339),
+		ClusterId:   expiry.ClusterId,
+		Payload: &client.Payload{
+			Source:        expiry.ClusterName,
+			Stage:         string(expiry.Status),
+			FailureReason: strings.Join(reasons, ", "),
+		},
+	}
+	_, err := impl.eventClient.WriteNotificationEvent(event)
+	if err != nil {
+		impl.logger.Errorw("error in sending cluster credential expiry notification", "clusterId", expiry.ClusterId, "err", err)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type CredentialType string
+
+const (
+	CredentialTypeBearerToken          CredentialType = "BearerToken"
+	CredentialTypeClientCertificate    CredentialType = "ClientCertificate"
+	CredentialTypeCertificateAuthority CredentialType = "CertificateAuthority"
+)
+
+type ExpiryStatus string
+
+const (
+	// ExpiryStatusValid is set for credentials expiring after the warning period
+	ExpiryStatusValid        ExpiryStatus = "Valid"
+	ExpiryStatusExpiringSoon ExpiryStatus = "ExpiringSoon"
+	ExpiryStatusExpired      ExpiryStatus = "Expired"
+	// ExpiryStatusNoExpiry is set for credentials without an expiry, like legacy service account tokens
+	ExpiryStatusNoExpiry ExpiryStatus = "NoExpiry"
+	// ExpiryStatusUnknown is set for credentials which could not be parsed
+	ExpiryStatusUnknown ExpiryStatus = "Unknown"
+)
+
+const ExpiryEventName = "Cluster Credential Expiry"
+
+type ClusterCredentialServiceConfig struct {
+	ExpiryCheckCron   string `env:"CLUSTER_CREDENTIAL_EXPIRY_CHECK_CRON" envDefault:"0 9 * * *" description:"Schedule of the job warning about cluster credentials expiring soon"`
+	ExpiryWarningDays int    `env:"CLUSTER_CREDENTIAL_EXPIRY_WARNING_DAYS" envDefault:"14" description:"Credentials expiring within these many days are warned about on every run of the expiry job"`
+}
+
+type ClusterCredentialExpiry struct {
+	ClusterId   int                 `json:"clusterId"`
+	ClusterName string              `json:"clusterName"`
+	Credentials []*CredentialExpiry `json:"credentials"`
+	// ExpiresOn is the earliest expiry of the credentials of the cluster
+	ExpiresOn *time.Time   `json:"expiresOn,omitempty"`
+	Status    ExpiryStatus `json:"status"`
+}
+
+type CredentialExpiry struct {
+	Type      CredentialType `json:"type"`
+	ExpiresOn *time.Time     `json:"expiresOn,omitempty"`
+	DaysLeft  int            `json:"daysLeft"`
+	Status    ExpiryStatus   `json:"status"`
+	// Subject is the service account of a token or the common name of a certificate
+	Subject string `json:"subject,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// RotateCredentialsRequest swaps the credentials of a cluster, credentials missing in the request keep their current value
+type RotateCredentialsRequest struct {
+	ClusterId   int    `json:"-"`
+	BearerToken string `json:"bearerToken"`
+	TlsKey      string `json:"tlsKey"`
+	CertData    string `json:"certData"`
+	// CertAuthData is needed only when the certificate authority of the api server changed
+	CertAuthData string `json:"certAuthData"`
+	UserId       int32  `json:"-"`
+}
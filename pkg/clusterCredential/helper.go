@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterCredential
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
+	"github.com/devtron-labs/devtron/pkg/clusterCredential/bean"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// expiryStatusSeverity orders the statuses of the credentials of a cluster, the most severe one is the status of the cluster
+var expiryStatusSeverity = map[bean.ExpiryStatus]int{
+	bean.ExpiryStatusNoExpiry:     0,
+	bean.ExpiryStatusValid:        1,
+	bean.ExpiryStatusUnknown:      2,
+	bean.ExpiryStatusExpiringSoon: 3,
+	bean.ExpiryStatusExpired:      4,
+}
+
+func getCredentialExpiries(config map[string]string, insecureSkipTLSVerify bool, now time.Time, warningDays int) []*bean.CredentialExpiry {
+	var credentials []*bean.CredentialExpiry
+	if token := config[commonBean.BearerToken]; len(token) > 0 {
+		credentials = append(credentials, getTokenExpiry(token))
+	}
+	// certificates are not used to connect when tls verification is skipped
+	if !insecureSkipTLSVerify {
+		if certData := config[commonBean.CertData]; len(certData) > 0 {
+			credentials = append(credentials, getCertificateExpiry(bean.CredentialTypeClientCertificate, certData))
+		}
+		if caData := config[commonBean.CertificateAuthorityData]; len(caData) > 0 {
+			credentials = append(credentials, getCertificateExpiry(bean.CredentialTypeCertificateAuthority, caData))
+		}
+	}
+	for _, credential := range credentials {
+		setExpiryStatus(credential, now, warningDays)
+	}
+	return credentials
+}
+
+// getTokenExpiry reads the exp claim of a service account token, the signature can't be verified here and is not needed
+func getTokenExpiry(token string) *bean.CredentialExpiry {
+	expiry := &bean.CredentialExpiry{Type: bean.CredentialTypeBearerToken}
+	claims := jwt.MapClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(token, claims)
+	if err != nil {
+		expiry.Error = "token is not a jwt, its expiry is unknown"
+		return expiry
+	}
+	if subject, ok := claims["sub"].(string); ok {
+		expiry.Subject = subject
+	}
+	if exp, ok := claims["exp"].(float64); ok {
+		expiresOn := time.Unix(int64(exp), 0)
+		expiry.ExpiresOn = &expiresOn
+	}
+	return expiry
+}
+
+func getCertificateExpiry(credentialType bean.CredentialType, data string) *bean.CredentialExpiry {
+	expiry := &bean.CredentialExpiry{Type: credentialType}
+	certificate, err := parseCertificate(data)
+	if err != nil {
+		expiry.Error = err.Error()
+		return expiry
+	}
+	expiresOn := certificate.NotAfter
+	expiry.ExpiresOn = &expiresOn
+	expiry.Subject = certificate.Subject.CommonName
+	return expiry
+}
+
+// parseCertificate parses the first certificate of pem data, base64 encoded pem data is accepted as well
+func parseCertificate(data string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err == nil {
+			block, _ = pem.Decode(decoded)
+		}
+	}
+	if block == nil {
+		return nil, errors.New("certificate is not pem encoded")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func setExpiryStatus(expiry *bean.CredentialExpiry, now time.Time, warningDays int) {
+	switch {
+	case len(expiry.Error) > 0:
+		expiry.Status = bean.ExpiryStatusUnknown
+	case expiry.ExpiresOn == nil:
+		expiry.Status = bean.ExpiryStatusNoExpiry
+	default:
+		expiry.DaysLeft = int(math.Floor(expiry.ExpiresOn.Sub(now).Hours() / 24))
+		if !expiry.ExpiresOn.After(now) {
+			expiry.Status = bean.ExpiryStatusExpired
+		} else if expiry.DaysLeft < warningDays {
+			expiry.Status = bean.ExpiryStatusExpiringSoon
+		} else {
+			expiry.Status = bean.ExpiryStatusValid
+		}
+	}
+}
+
+func getClusterCredentialExpiry(clusterId int, clusterName string, credentials []*bean.CredentialExpiry) *bean.ClusterCredentialExpiry {
+	clusterExpiry := &bean.ClusterCredentialExpiry{
+		ClusterId:   clusterId,
+		ClusterName: clusterName,
+		Credentials: credentials,
+		Status:      bean.ExpiryStatusNoExpiry,
+	}
+	for _, credential := range credentials {
+		if expiryStatusSeverity[credential.Status] > expiryStatusSeverity[clusterExpiry.Status] {
+			clusterExpiry.Status = credential.Status
+		}
+		if credential.ExpiresOn != nil && (clusterExpiry.ExpiresOn == nil || credential.ExpiresOn.Before(*clusterExpiry.ExpiresOn)) {
+			clusterExpiry.ExpiresOn = credential.ExpiresOn
+		}
+	}
+	return clusterExpiry
+}
+
+// getRotatedConfig returns the config with the credentials of the request swapped in and whether any credential changed
+func getRotatedConfig(config map[string]string, request *bean.RotateCredentialsRequest) (map[string]string, bool) {
+	rotatedConfig := make(map[string]string, len(config))
+	for key, value := range config {
+		rotatedConfig[key] = value
+	}
+	changed := false
+	credentials := map[string]string{
+		commonBean.BearerToken:              request.BearerToken,
+		commonBean.TlsKey:                   request.TlsKey,
+		commonBean.CertData:                 request.CertData,
+		commonBean.CertificateAuthorityData: request.CertAuthData,
+	}
+	for key, value := range credentials {
+		if len(value) > 0 && rotatedConfig[key] != value {
+			rotatedConfig[key] = value
+			changed = true
+		}
+	}
+	return rotatedConfig, changed
+}
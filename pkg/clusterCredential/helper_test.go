@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterCredential
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
+	"github.com/devtron-labs/devtron/pkg/clusterCredential/bean"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestCertificate(t *testing.T, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "admin"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func getTestToken(t *testing.T, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	assert.NoError(t, err)
+	return token
+}
+
+func TestGetCredentialExpiries(t *testing.T) {
+	now := time.Now()
+	certificate := getTestCertificate(t, now.Add(10*24*time.Hour+time.Hour))
+	config := map[string]string{
+		commonBean.BearerToken:              getTestToken(t, jwt.MapClaims{"sub": "system:serviceaccount:kube-system:devtron", "exp": now.Add(-time.Hour).Unix()}),
+		commonBean.CertData:                 certificate,
+		commonBean.CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(getTestCertificate(t, now.Add(100*24*time.Hour)))),
+	}
+
+	credentials := getCredentialExpiries(config, false, now, 14)
+	assert.Len(t, credentials, 3)
+	assert.Equal(t, bean.CredentialTypeBearerToken, credentials[0].Type)
+	assert.Equal(t, bean.ExpiryStatusExpired, credentials[0].Status)
+	assert.Equal(t, "system:serviceaccount:kube-system:devtron", credentials[0].Subject)
+	assert.Equal(t, bean.ExpiryStatusExpiringSoon, credentials[1].Status)
+	assert.Equal(t, 10, credentials[1].DaysLeft)
+	assert.Equal(t, "admin", credentials[1].Subject)
+	assert.Equal(t, bean.ExpiryStatusValid, credentials[2].Status)
+
+	// certificates are not used when tls verification is skipped
+	assert.Len(t, getCredentialExpiries(config, true, now, 14), 1)
+}
+
+func TestGetTokenExpiry(t *testing.T) {
+	now := time.Now()
+	expiry := getTokenExpiry(getTestToken(t, jwt.MapClaims{"sub": "legacy"}))
+	setExpiryStatus(expiry, now, 14)
+	assert.Equal(t, bean.ExpiryStatusNoExpiry, expiry.Status)
+
+	expiry = getTokenExpiry("opaque-token")
+	setExpiryStatus(expiry, now, 14)
+	assert.Equal(t, bean.ExpiryStatusUnknown, expiry.Status)
+	assert.NotEmpty(t, expiry.Error)
+}
+
+func TestGetClusterCredentialExpiry(t *testing.T) {
+	earlier := time.Now().Add(time.Hour)
+	later := earlier.Add(time.Hour)
+	credentials := []*bean.CredentialExpiry{
+		{Status: bean.ExpiryStatusValid, ExpiresOn: &later},
+		{Status: bean.ExpiryStatusExpiringSoon, ExpiresOn: &earlier},
+		{Status: bean.ExpiryStatusNoExpiry},
+	}
+	expiry := getClusterCredentialExpiry(1, "prod", credentials)
+	assert.Equal(t, bean.ExpiryStatusExpiringSoon, expiry.Status)
+	assert.Equal(t, earlier, *expiry.ExpiresOn)
+
+	expiry = getClusterCredentialExpiry(1, "prod", nil)
+	assert.Equal(t, bean.ExpiryStatusNoExpiry, expiry.Status)
+	assert.Nil(t, expiry.ExpiresOn)
+}
+
+func TestGetRotatedConfig(t *testing.T) {
+	config := map[string]string{commonBean.BearerToken: "old", commonBean.CertificateAuthorityData: "ca"}
+
+	rotatedConfig, changed := getRotatedConfig(config, &bean.RotateCredentialsRequest{BearerToken: "new"})
+	assert.True(t, changed)
+	assert.Equal(t, "new", rotatedConfig[commonBean.BearerToken])
+	assert.Equal(t, "ca", rotatedConfig[commonBean.CertificateAuthorityData])
+	assert.Equal(t, "old", config[commonBean.BearerToken])
+
+	_, changed = getRotatedConfig(config, &bean.RotateCredentialsRequest{BearerToken: "old"})
+	assert.False(t, changed)
+}
@@ -0,0 +1,277 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterOnboarding
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment"
+	envBean "github.com/devtron-labs/devtron/pkg/cluster/environment/bean"
+	"github.com/devtron-labs/devtron/pkg/clusterOnboarding/bean"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail"
+	teamRepository "github.com/devtron-labs/devtron/pkg/team/repository"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const clusterAlreadyExists = "cluster-already-exists"
+
+type ClusterOnboardingService interface {
+	// BulkOnboard creates a cluster for every selected context of the kubeconfig, environments for the namespaces
+	// matching the environment rules and allows the projects to deploy to the new clusters. A failing context does
+	// not stop the others, the result of every context is returned
+	BulkOnboard(ctx context.Context, request *bean.BulkOnboardRequest) (*bean.BulkOnboardResponse, error)
+}
+
+type ClusterOnboardingServiceImpl struct {
+	logger                  *zap.SugaredLogger
+	clusterService          cluster.ClusterService
+	environmentService      environment.EnvironmentService
+	projectGuardrailService projectGuardrail.ProjectGuardrailService
+	teamRepository          teamRepository.TeamRepository
+	K8sUtil                 *k8s.K8sServiceImpl
+}
+
+func NewClusterOnboardingServiceImpl(logger *zap.SugaredLogger,
+	clusterService cluster.ClusterService,
+	environmentService environment.EnvironmentService,
+	projectGuardrailService projectGuardrail.ProjectGuardrailService,
+	teamRepository teamRepository.TeamRepository,
+	K8sUtil *k8s.K8sServiceImpl) *ClusterOnboardingServiceImpl {
+	return &ClusterOnboardingServiceImpl{
+		logger:                  logger,
+		clusterService:          clusterService,
+		environmentService:      environmentService,
+		projectGuardrailService: projectGuardrailService,
+		teamRepository:          teamRepository,
+		K8sUtil:                 K8sUtil,
+	}
+}
+
+func (impl *ClusterOnboardingServiceImpl) BulkOnboard(ctx context.Context, request *bean.BulkOnboardRequest) (*bean.BulkOnboardResponse, error) {
+	err := validateEnvironmentRules(request.EnvironmentRules)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.validateProjects(request.ProjectIds)
+	if err != nil {
+		return nil, err
+	}
+	kubeConfig, kubeConfigJson, err := parseKubeconfig(request.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	err = validateContextSelections(request.Contexts, kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	validatedClusters, err := impl.clusterService.ValidateKubeconfig(kubeConfigJson)
+	if err != nil {
+		impl.logger.Errorw("error in validating kubeconfig", "err", err)
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+
+	response := &bean.BulkOnboardResponse{}
+	for _, selection := range request.Contexts {
+		result := impl.onboardContext(ctx, selection, kubeConfig, validatedClusters, request)
+		response.Clusters = append(response.Clusters, result)
+	}
+	return response, nil
+}
+
+func (impl *ClusterOnboardingServiceImpl) validateProjects(projectIds []int) error {
+	if len(projectIds) == 0 {
+		return nil
+	}
+	ids := make([]*int, 0, len(projectIds))
+	for i := range projectIds {
+		ids = append(ids, &projectIds[i])
+	}
+	teams, err := impl.teamRepository.FindByIds(ids)
+	if err != nil {
+		impl.logger.Errorw("error in fetching projects", "projectIds", projectIds, "err", err)
+		return err
+	}
+	found := make(map[int]bool, len(teams))
+	for _, team := range teams {
+		found[team.Id] = true
+	}
+	for _, projectId := range projectIds {
+		if !found[projectId] {
+			message := fmt.Sprintf("project %d not found", projectId)
+			return util.NewApiError(http.StatusNotFound, message, message)
+		}
+	}
+	return nil
+}
+
+func (impl *ClusterOnboardingServiceImpl) onboardContext(ctx context.Context, selection *bean.ContextSelection, kubeConfig *api.Config,
+	validatedClusters map[string]*clusterBean.ValidateClusterBean, request *bean.BulkOnboardRequest) *bean.ClusterOnboardResult {
+	kubeContext := kubeConfig.Contexts[selection.Context]
+	result := &bean.ClusterOnboardResult{
+		Context:     selection.Context,
+		ClusterName: getClusterName(selection, kubeContext),
+		Status:      bean.OnboardStatusFailed,
+	}
+	if kubeContext == nil {
+		result.Error = "context not found in kubeconfig"
+		return result
+	}
+	validatedCluster := validatedClusters[kubeContext.Cluster]
+	if validatedCluster == nil || validatedCluster.ClusterBean == nil {
+		result.Error = "cluster of the context not found in kubeconfig"
+		return result
+	}
+	userInfo := validatedCluster.UserInfos[kubeContext.AuthInfo]
+	if userInfo == nil {
+		result.Error = "user of the context not found in kubeconfig"
+		return result
+	}
+	if len(userInfo.ErrorInConnecting) > 0 && userInfo.ErrorInConnecting != clusterAlreadyExists {
+		result.Error = userInfo.ErrorInConnecting
+		return result
+	}
+
+	existingCluster, err := impl.clusterService.FindOne(result.ClusterName)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cluster", "clusterName", result.ClusterName, "err", err)
+		result.Error = err.Error()
+		return result
+	}
+	if existingCluster != nil && existingCluster.Id > 0 {
+		result.ClusterId = existingCluster.Id
+		result.Status = bean.OnboardStatusSkipped
+		result.Error = "cluster already exists"
+		return result
+	}
+
+	newCluster := &clusterBean.ClusterBean{
+		ClusterName:           result.ClusterName,
+		ServerUrl:             strings.TrimSuffix(validatedCluster.ServerUrl, "/"),
+		InsecureSkipTLSVerify: validatedCluster.InsecureSkipTLSVerify,
+		Config:                userInfo.Config,
+		IsProd:                selection.IsProd,
+		Active:                true,
+	}
+	savedCluster, err := impl.clusterService.Save(ctx, newCluster, request.UserId)
+	if err != nil || savedCluster == nil || savedCluster.Id == 0 {
+		impl.logger.Errorw("error in onboarding cluster", "clusterName", result.ClusterName, "err", err)
+		result.Error = "cluster could not be saved"
+		if err != nil {
+			result.Error = err.Error()
+		}
+		return result
+	}
+	result.ClusterId = savedCluster.Id
+	result.Status = bean.OnboardStatusCreated
+	if len(request.EnvironmentRules) > 0 {
+		result.Environments, err = impl.createEnvironments(savedCluster, request.EnvironmentRules, request.UserId)
+		if err != nil {
+			result.Error = fmt.Sprintf("environments not created, %s", err.Error())
+		}
+	}
+	if len(request.ProjectIds) > 0 {
+		err = impl.assignProjects(request.ProjectIds, result, request.UserId)
+		if err != nil {
+			result.ProjectError = err.Error()
+		}
+	}
+	if isPartiallyCreated(result) {
+		result.Status = bean.OnboardStatusPartiallyCreated
+	}
+	return result
+}
+
+// assignProjects allows the projects to deploy to the new cluster and assigns the environments created on it to them
+func (impl *ClusterOnboardingServiceImpl) assignProjects(projectIds []int, result *bean.ClusterOnboardResult, userId int32) error {
+	err := impl.projectGuardrailService.AllowClusters(projectIds, []int{result.ClusterId}, userId)
+	if err != nil {
+		impl.logger.Errorw("error in allowing onboarded cluster in projects", "projectIds", projectIds, "clusterId", result.ClusterId, "err", err)
+		return err
+	}
+	envIds := getCreatedEnvironmentIds(result.Environments)
+	if len(envIds) == 0 {
+		return nil
+	}
+	err = impl.projectGuardrailService.AssignEnvironments(projectIds, envIds, userId)
+	if err != nil {
+		impl.logger.Errorw("error in assigning onboarded environments to projects", "projectIds", projectIds, "envIds", envIds, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ClusterOnboardingServiceImpl) createEnvironments(cluster *clusterBean.ClusterBean, rules []*bean.EnvironmentRule, userId int32) ([]*bean.EnvironmentOnboardResult, error) {
+	namespaces, err := impl.listNamespaces(cluster)
+	if err != nil {
+		impl.logger.Errorw("error in listing namespaces of onboarded cluster", "clusterName", cluster.ClusterName, "err", err)
+		return nil, err
+	}
+	var results []*bean.EnvironmentOnboardResult
+	for _, namespace := range namespaces {
+		rule := getMatchingEnvironmentRule(rules, namespace)
+		if rule == nil {
+			continue
+		}
+		result := &bean.EnvironmentOnboardResult{
+			Namespace:       namespace,
+			EnvironmentName: getEnvironmentName(rule.EnvironmentName, cluster.ClusterName, namespace),
+			Status:          bean.OnboardStatusFailed,
+		}
+		env, err := impl.environmentService.Create(&envBean.EnvironmentBean{
+			Environment: result.EnvironmentName,
+			ClusterId:   cluster.Id,
+			Namespace:   namespace,
+			Active:      true,
+		}, userId)
+		if err != nil {
+			impl.logger.Errorw("error in creating environment of onboarded cluster", "clusterName", cluster.ClusterName, "namespace", namespace, "err", err)
+			result.Error = err.Error()
+		} else {
+			result.EnvironmentId = env.Id
+			result.Status = bean.OnboardStatusCreated
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (impl *ClusterOnboardingServiceImpl) listNamespaces(cluster *clusterBean.ClusterBean) ([]string, error) {
+	_, _, clientSet, err := impl.K8sUtil.GetK8sConfigAndClients(cluster.GetClusterConfig())
+	if err != nil {
+		return nil, err
+	}
+	namespaceList, err := clientSet.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	namespaces := make([]string, 0, len(namespaceList.Items))
+	for _, namespace := range namespaceList.Items {
+		namespaces = append(namespaces, namespace.Name)
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
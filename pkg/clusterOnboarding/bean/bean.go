@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+type OnboardStatus string
+
+const (
+	OnboardStatusCreated OnboardStatus = "Created"
+	// OnboardStatusPartiallyCreated is set when the cluster was created but some of its environments or its project assignment failed
+	OnboardStatusPartiallyCreated OnboardStatus = "PartiallyCreated"
+	OnboardStatusSkipped          OnboardStatus = "Skipped"
+	OnboardStatusFailed           OnboardStatus = "Failed"
+)
+
+const (
+	ClusterNamePlaceholder   = "{cluster}"
+	NamespacePlaceholder     = "{namespace}"
+	DefaultEnvironmentName   = ClusterNamePlaceholder + "-" + NamespacePlaceholder
+	MaxEnvironmentNameLength = 50
+)
+
+// BulkOnboardRequest onboards the selected contexts of a kubeconfig as clusters in one go
+type BulkOnboardRequest struct {
+	// Kubeconfig is the kubeconfig in yaml or json
+	Kubeconfig string              `json:"kubeconfig" validate:"required"`
+	Contexts   []*ContextSelection `json:"contexts" validate:"required,min=1,dive"`
+	// EnvironmentRules create an environment for every namespace of a new cluster matching a rule, the first matching rule wins
+	EnvironmentRules []*EnvironmentRule `json:"environmentRules" validate:"dive"`
+	// ProjectIds are the projects allowed to deploy to the new clusters, the created environments are assigned to them
+	ProjectIds []int `json:"projectIds"`
+	UserId     int32 `json:"-"`
+}
+
+type ContextSelection struct {
+	Context string `json:"context" validate:"required"`
+	// ClusterName defaults to the name of the cluster of the context in the kubeconfig
+	ClusterName string `json:"clusterName"`
+	IsProd      bool   `json:"isProd"`
+}
+
+type EnvironmentRule struct {
+	// NamespacePattern is a glob matched against the namespace names, e.g. team-*
+	NamespacePattern string `json:"namespacePattern" validate:"required"`
+	// EnvironmentName is the name template of the environments, defaults to {cluster}-{namespace}
+	EnvironmentName string `json:"environmentName"`
+}
+
+type BulkOnboardResponse struct {
+	Clusters []*ClusterOnboardResult `json:"clusters"`
+}
+
+type ClusterOnboardResult struct {
+	Context      string                      `json:"context"`
+	ClusterName  string                      `json:"clusterName"`
+	ClusterId    int                         `json:"clusterId,omitempty"`
+	Status       OnboardStatus               `json:"status"`
+	Error        string                      `json:"error,omitempty"`
+	Environments []*EnvironmentOnboardResult `json:"environments,omitempty"`
+	// ProjectError is set when the cluster or its environments could not be assigned to the projects
+	ProjectError string `json:"projectError,omitempty"`
+}
+
+type EnvironmentOnboardResult struct {
+	Namespace       string        `json:"namespace"`
+	EnvironmentName string        `json:"environmentName"`
+	EnvironmentId   int           `json:"environmentId,omitempty"`
+	Status          OnboardStatus `json:"status"`
+	Error           string        `json:"error,omitempty"`
+}
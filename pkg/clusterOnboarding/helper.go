@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterOnboarding
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/clusterOnboarding/bean"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/yaml"
+)
+
+var invalidEnvironmentNameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// parseKubeconfig returns the parsed kubeconfig along with its json form which is what kubeconfig validation expects
+func parseKubeconfig(kubeconfig string) (*api.Config, string, error) {
+	kubeConfig, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return nil, "", util.NewApiError(http.StatusBadRequest, "invalid kubeconfig", err.Error())
+	}
+	kubeConfigJson, err := yaml.YAMLToJSON([]byte(kubeconfig))
+	if err != nil {
+		return nil, "", util.NewApiError(http.StatusBadRequest, "invalid kubeconfig", err.Error())
+	}
+	return kubeConfig, string(kubeConfigJson), nil
+}
+
+func validateContextSelections(selections []*bean.ContextSelection, kubeConfig *api.Config) error {
+	clusterNames := make(map[string]bool, len(selections))
+	for _, selection := range selections {
+		clusterName := getClusterName(selection, kubeConfig.Contexts[selection.Context])
+		if clusterName == "" {
+			continue
+		}
+		if clusterNames[clusterName] {
+			message := fmt.Sprintf("cluster %s is selected more than once", clusterName)
+			return util.NewApiError(http.StatusBadRequest, message, message)
+		}
+		clusterNames[clusterName] = true
+	}
+	return nil
+}
+
+func validateEnvironmentRules(rules []*bean.EnvironmentRule) error {
+	for _, rule := range rules {
+		if _, err := path.Match(rule.NamespacePattern, ""); err != nil {
+			message := fmt.Sprintf("invalid namespace pattern %s", rule.NamespacePattern)
+			return util.NewApiError(http.StatusBadRequest, message, err.Error())
+		}
+		if len(rule.EnvironmentName) > 0 && !strings.Contains(rule.EnvironmentName, bean.NamespacePlaceholder) {
+			message := fmt.Sprintf("environment name %s should contain %s", rule.EnvironmentName, bean.NamespacePlaceholder)
+			return util.NewApiError(http.StatusBadRequest, message, message)
+		}
+	}
+	return nil
+}
+
+func getClusterName(selection *bean.ContextSelection, kubeContext *api.Context) string {
+	if len(selection.ClusterName) > 0 {
+		return selection.ClusterName
+	}
+	if kubeContext != nil {
+		return kubeContext.Cluster
+	}
+	return ""
+}
+
+func getMatchingEnvironmentRule(rules []*bean.EnvironmentRule, namespace string) *bean.EnvironmentRule {
+	for _, rule := range rules {
+		if matched, _ := path.Match(rule.NamespacePattern, namespace); matched {
+			return rule
+		}
+	}
+	return nil
+}
+
+// getEnvironmentName fills the name template and turns the result into a valid environment name
+func getEnvironmentName(template string, clusterName string, namespace string) string {
+	if len(template) == 0 {
+		template = bean.DefaultEnvironmentName
+	}
+	name := strings.NewReplacer(bean.ClusterNamePlaceholder, clusterName, bean.NamespacePlaceholder, namespace).Replace(template)
+	name = invalidEnvironmentNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.Trim(name, "-")
+	if len(name) > bean.MaxEnvironmentNameLength {
+		name = strings.TrimRight(name[:bean.MaxEnvironmentNameLength], "-")
+	}
+	return name
+}
+
+func getCreatedEnvironmentIds(environments []*bean.EnvironmentOnboardResult) []int {
+	var envIds []int
+	for _, environment := range environments {
+		if environment.Status == bean.OnboardStatusCreated {
+			envIds = append(envIds, environment.EnvironmentId)
+		}
+	}
+	return envIds
+}
+
+// isPartiallyCreated reports whether a created cluster has failed environments or could not be assigned to the projects
+func isPartiallyCreated(result *bean.ClusterOnboardResult) bool {
+	if result.Status != bean.OnboardStatusCreated {
+		return false
+	}
+	if len(result.Error) > 0 || len(result.ProjectError) > 0 {
+		return true
+	}
+	for _, environment := range result.Environments {
+		if environment.Status != bean.OnboardStatusCreated {
+			return true
+		}
+	}
+	return false
+}
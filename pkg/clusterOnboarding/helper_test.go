@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterOnboarding
+
+import (
+	"testing"
+
+	"github.com/devtron-labs/devtron/pkg/clusterOnboarding/bean"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvironmentName(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		clusterName string
+		namespace   string
+		want        string
+	}{
+		{name: "default template", clusterName: "prod", namespace: "payments", want: "prod-payments"},
+		{name: "custom template", template: "{namespace}-east", clusterName: "prod", namespace: "payments", want: "payments-east"},
+		{name: "invalid characters", clusterName: "Prod_EU", namespace: "team.a", want: "prod-eu-team-a"},
+		{name: "too long", template: "{namespace}", namespace: "a123456789b123456789c123456789d123456789e12345678-xyz", want: "a123456789b123456789c123456789d123456789e12345678"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getEnvironmentName(tt.template, tt.clusterName, tt.namespace))
+		})
+	}
+}
+
+func TestGetMatchingEnvironmentRule(t *testing.T) {
+	rules := []*bean.EnvironmentRule{{NamespacePattern: "team-*", EnvironmentName: "{namespace}"}, {NamespacePattern: "*"}}
+	assert.Equal(t, rules[0], getMatchingEnvironmentRule(rules, "team-a"))
+	assert.Equal(t, rules[1], getMatchingEnvironmentRule(rules, "default"))
+	assert.Nil(t, getMatchingEnvironmentRule(rules[:1], "default"))
+}
+
+func TestIsPartiallyCreated(t *testing.T) {
+	environments := []*bean.EnvironmentOnboardResult{
+		{Namespace: "team-a", EnvironmentId: 1, Status: bean.OnboardStatusCreated},
+		{Namespace: "team-b", Status: bean.OnboardStatusFailed, Error: "environment already exists"},
+	}
+	assert.Equal(t, []int{1}, getCreatedEnvironmentIds(environments))
+
+	assert.False(t, isPartiallyCreated(&bean.ClusterOnboardResult{Status: bean.OnboardStatusCreated, Environments: environments[:1]}))
+	assert.True(t, isPartiallyCreated(&bean.ClusterOnboardResult{Status: bean.OnboardStatusCreated, Environments: environments}))
+	assert.True(t, isPartiallyCreated(&bean.ClusterOnboardResult{Status: bean.OnboardStatusCreated, ProjectError: "project not found"}))
+	assert.True(t, isPartiallyCreated(&bean.ClusterOnboardResult{Status: bean.OnboardStatusCreated, Error: "environments not created, forbidden"}))
+	assert.False(t, isPartiallyCreated(&bean.ClusterOnboardResult{Status: bean.OnboardStatusSkipped, Error: "cluster already exists"}))
+}
+
+func TestValidateEnvironmentRules(t *testing.T) {
+	assert.NoError(t, validateEnvironmentRules([]*bean.EnvironmentRule{{NamespacePattern: "team-*"}}))
+	assert.Error(t, validateEnvironmentRules([]*bean.EnvironmentRule{{NamespacePattern: "team-["}}))
+	assert.Error(t, validateEnvironmentRules([]*bean.EnvironmentRule{{NamespacePattern: "*", EnvironmentName: "static"}}))
+}
+
+func TestParseKubeconfig(t *testing.T) {
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- name: prod
+  cluster:
+    server: https://prod.example.com
+contexts:
+- name: prod-admin
+  context:
+    cluster: prod
+    user: admin
+users:
+- name: admin
+  user:
+    token: abc
+`
+	kubeConfig, kubeConfigJson, err := parseKubeconfig(kubeconfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", kubeConfig.Contexts["prod-admin"].Cluster)
+	assert.Contains(t, kubeConfigJson, `"kind":"Config"`)
+	_, _, err = parseKubeconfig("clusters: [")
+	assert.Error(t, err)
+}
@@ -0,0 +1,368 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package debugProfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/util"
+	envBean "github.com/devtron-labs/devtron/pkg/cluster/environment/bean"
+	envRepository "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/debugProfile/bean"
+	"github.com/devtron-labs/devtron/pkg/debugProfile/repository"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const clusterReconcileTimeout = time.Minute
+
+type DebugProfileService interface {
+	GetAll() ([]*bean.DebugProfileDto, error)
+	// GetApplicable returns the profiles which can be used on the pods of a namespace
+	GetApplicable(clusterId int, namespace string) ([]*bean.DebugProfileDto, error)
+	Create(request *bean.DebugProfileDto) (*bean.DebugProfileDto, error)
+	Update(request *bean.DebugProfileDto) (*bean.DebugProfileDto, error)
+	Delete(id int, userId int32) error
+	// ResolveProfile returns the profile an ephemeral container is created with, nil when it is created without one,
+	// and sets the expiry of the container when the profile has a ttl
+	ResolveProfile(request *envBean.EphemeralContainerRequest) (*bean.DebugProfileDto, error)
+	// ReconcileDebugContainers checks the pods of the debug containers whose sessions ended, pods which were
+	// deleted or recreated since are cleaned up and the others are reported as lingering
+	ReconcileDebugContainers()
+	GetLingeringContainers() *bean.LingeringDebugContainerReport
+}
+
+type DebugProfileServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	debugProfileRepository        repository.DebugProfileRepository
+	environmentRepository         envRepository.EnvironmentRepository
+	ephemeralContainersRepository clusterRepository.EphemeralContainersRepository
+	k8sCommonService              k8s.K8sCommonService
+	config                        *bean.DebugProfileConfig
+	reconcileLock                 sync.Mutex
+	reportLock                    sync.RWMutex
+	report                        *bean.LingeringDebugContainerReport
+}
+
+func NewDebugProfileServiceImpl(logger *zap.SugaredLogger,
+	debugProfileRepository repository.DebugProfileRepository,
+	environmentRepository envRepository.EnvironmentRepository,
+	ephemeralContainersRepository clusterRepository.EphemeralContainersRepository,
+	k8sCommonService k8s.K8sCommonService,
+	cronLogger *cron2.CronLoggerImpl) (*DebugProfileServiceImpl, error) {
+	config := &bean.DebugProfileConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing debug profile config", "err", err)
+		return nil, err
+	}
+	impl := &DebugProfileServiceImpl{
+		logger:                        logger,
+		debugProfileRepository:        debugProfileRepository,
+		environmentRepository:         environmentRepository,
+		ephemeralContainersRepository: ephemeralContainersRepository,
+		k8sCommonService:              k8sCommonService,
+		config:                        config,
+		report:                        &bean.LingeringDebugContainerReport{Containers: make([]*bean.LingeringDebugContainer, 0)},
+	}
+	reconcileCron := cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	_, err = reconcileCron.AddFunc(config.ReconcileCron, impl.ReconcileDebugContainers)
+	if err != nil {
+		logger.Errorw("error in adding debug container reconcile cron", "schedule", config.ReconcileCron, "err", err)
+		return nil, err
+	}
+	reconcileCron.Start()
+	return impl, nil
+}
+
+func (impl *DebugProfileServiceImpl) GetAll() ([]*bean.DebugProfileDto, error) {
+	models, err := impl.debugProfileRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in fetching debug profiles", "err", err)
+		return nil, err
+	}
+	profiles := make([]*bean.DebugProfileDto, 0, len(models))
+	for _, model := range models {
+		profile, err := toDto(model)
+		if err != nil {
+			impl.logger.Errorw("error in reading debug profile", "id", model.Id, "err", err)
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+func (impl *DebugProfileServiceImpl) GetApplicable(clusterId int, namespace string) ([]*bean.DebugProfileDto, error) {
+	environmentId, err := impl.getEnvironmentId(clusterId, namespace)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := impl.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	applicable := make([]*bean.DebugProfileDto, 0, len(profiles))
+	for _, profile := range profiles {
+		if isAllowedInEnvironment(profile, environmentId) {
+			applicable = append(applicable, profile)
+		}
+	}
+	return applicable, nil
+}
+
+func (impl *DebugProfileServiceImpl) Create(request *bean.DebugProfileDto) (*bean.DebugProfileDto, error) {
+	err := impl.validate(request)
+	if err != nil {
+		return nil, err
+	}
+	model, err := toModel(request, &repository.DebugProfile{})
+	if err != nil {
+		return nil, err
+	}
+	model.AuditLog = sql.NewDefaultAuditLog(request.UserId)
+	err = impl.debugProfileRepository.Save(model)
+	if err != nil {
+		impl.logger.Errorw("error in saving debug profile", "request", request, "err", err)
+		return nil, err
+	}
+	return toDto(model)
+}
+
+func (impl *DebugProfileServiceImpl) Update(request *bean.DebugProfileDto) (*bean.DebugProfileDto, error) {
+	existing, err := impl.getProfile(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.validate(request)
+	if err != nil {
+		return nil, err
+	}
+	model, err := toModel(request, existing)
+	if err != nil {
+		return nil, err
+	}
+	model.UpdateAuditLog(request.UserId)
+	err = impl.debugProfileRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in updating debug profile", "request", request, "err", err)
+		return nil, err
+	}
+	return toDto(model)
+}
+
+func (impl *DebugProfileServiceImpl) Delete(id int, userId int32) error {
+	profile, err := impl.getProfile(id)
+	if err != nil {
+		return err
+	}
+	profile.Active = false
+	profile.UpdateAuditLog(userId)
+	err = impl.debugProfileRepository.Update(profile)
+	if err != nil {
+		impl.logger.Errorw("error in deleting debug profile", "id", id, "err", err)
+	}
+	return err
+}
+
+func (impl *DebugProfileServiceImpl) ResolveProfile(request *envBean.EphemeralContainerRequest) (*bean.DebugProfileDto, error) {
+	if request.DebugProfileId == 0 {
+		if impl.config.ProfileEnforced {
+			message := "ephemeral containers can only be created with a debug profile"
+			return nil, util.NewApiError(http.StatusBadRequest, message, message)
+		}
+		return nil, nil
+	}
+	if request.AdvancedData != nil {
+		message := "a manifest can't be used with a debug profile"
+		return nil, util.NewApiError(http.StatusBadRequest, message, message)
+	}
+	model, err := impl.getProfile(request.DebugProfileId)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := toDto(model)
+	if err != nil {
+		impl.logger.Errorw("error in reading debug profile", "id", model.Id, "err", err)
+		return nil, err
+	}
+	environmentId, err := impl.getEnvironmentId(request.ClusterId, request.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if !isAllowedInEnvironment(profile, environmentId) {
+		message := fmt.Sprintf("debug profile %s is not allowed in namespace %s", profile.Name, request.Namespace)
+		return nil, util.NewApiError(http.StatusForbidden, message, message)
+	}
+	if profile.TtlSeconds > 0 {
+		expiresOn := time.Now().Add(time.Duration(profile.TtlSeconds) * time.Second)
+		request.ExpiresOn = &expiresOn
+	}
+	return profile, nil
+}
+
+func (impl *DebugProfileServiceImpl) ReconcileDebugContainers() {
+	if !impl.reconcileLock.TryLock() {
+		impl.logger.Infow("debug container reconcile already running, skipping")
+		return
+	}
+	defer impl.reconcileLock.Unlock()
+	now := time.Now()
+	containers, err := impl.ephemeralContainersRepository.FindEndedSessionContainers(now)
+	if err != nil {
+		impl.logger.Errorw("error in fetching debug containers of ended sessions", "err", err)
+		return
+	}
+	byCluster := make(map[int][]*clusterRepository.EndedEphemeralContainer)
+	for _, container := range containers {
+		byCluster[container.ClusterId] = append(byCluster[container.ClusterId], container)
+	}
+	report := &bean.LingeringDebugContainerReport{
+		ReconciledOn:  now,
+		Containers:    make([]*bean.LingeringDebugContainer, 0),
+		ClusterErrors: make(map[int]string),
+	}
+	var cleanedUp []int
+	for clusterId, clusterContainers := range byCluster {
+		lingering, clusterCleanedUp, err := impl.reconcileCluster(clusterId, clusterContainers)
+		if err != nil {
+			impl.logger.Errorw("error in reconciling debug containers of cluster", "clusterId", clusterId, "err", err)
+			report.ClusterErrors[clusterId] = err.Error()
+			continue
+		}
+		report.Containers = append(report.Containers, lingering...)
+		cleanedUp = append(cleanedUp, clusterCleanedUp...)
+	}
+	err = impl.ephemeralContainersRepository.MarkCleanedUp(cleanedUp, now)
+	if err != nil {
+		impl.logger.Errorw("error in marking debug containers cleaned up", "ids", cleanedUp, "err", err)
+	}
+	if len(report.Containers) > 0 {
+		impl.logger.Warnw("pods still carry debug containers of ended sessions", "count", len(report.Containers))
+	}
+	impl.reportLock.Lock()
+	impl.report = report
+	impl.reportLock.Unlock()
+}
+
+func (impl *DebugProfileServiceImpl) reconcileCluster(clusterId int, containers []*clusterRepository.EndedEphemeralContainer) ([]*bean.LingeringDebugContainer, []int, error) {
+	_, v1Client, err := impl.k8sCommonService.GetCoreClientByClusterId(clusterId)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), clusterReconcileTimeout)
+	defer cancel()
+	var lingering []*bean.LingeringDebugContainer
+	var cleanedUp []int
+	for _, container := range containers {
+		pod, err := v1Client.Pods(container.Namespace).Get(ctx, container.PodName, metav1.GetOptions{})
+		if k8sErrors.IsNotFound(err) {
+			cleanedUp = append(cleanedUp, container.Id)
+			continue
+		} else if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, nil, err
+			}
+			impl.logger.Errorw("error in fetching pod of debug container", "clusterId", clusterId, "namespace", container.Namespace, "pod", container.PodName, "err", err)
+			continue
+		}
+		present, running := getDebugContainerState(pod, container.Name)
+		if !present {
+			cleanedUp = append(cleanedUp, container.Id)
+			continue
+		}
+		lingering = append(lingering, &bean.LingeringDebugContainer{
+			EphemeralContainerId: container.Id,
+			ClusterId:            container.ClusterId,
+			Namespace:            container.Namespace,
+			PodName:              container.PodName,
+			ContainerName:        container.Name,
+			TargetContainerName:  container.TargetContainer,
+			DebugProfileId:       container.DebugProfileId,
+			SessionEndedOn:       getSessionEndedOn(container),
+			Running:              running,
+		})
+	}
+	return lingering, cleanedUp, nil
+}
+
+func (impl *DebugProfileServiceImpl) GetLingeringContainers() *bean.LingeringDebugContainerReport {
+	impl.reportLock.RLock()
+	defer impl.reportLock.RUnlock()
+	return impl.report
+}
+
+func (impl *DebugProfileServiceImpl) getEnvironmentId(clusterId int, namespace string) (int, error) {
+	environment, err := impl.environmentRepository.FindOneByNamespaceAndClusterId(namespace, clusterId)
+	if util.IsErrNoRows(err) {
+		return 0, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching environment of namespace", "clusterId", clusterId, "namespace", namespace, "err", err)
+		return 0, err
+	}
+	return environment.Id, nil
+}
+
+func (impl *DebugProfileServiceImpl) validate(request *bean.DebugProfileDto) error {
+	err := validateProfile(request)
+	if err != nil {
+		return err
+	}
+	if len(request.EnvironmentIds) == 0 {
+		return nil
+	}
+	ids := make([]*int, 0, len(request.EnvironmentIds))
+	for i := range request.EnvironmentIds {
+		ids = append(ids, &request.EnvironmentIds[i])
+	}
+	environments, err := impl.environmentRepository.FindByIds(ids)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environments of debug profile", "envIds", request.EnvironmentIds, "err", err)
+		return err
+	}
+	if len(environments) != sets.NewInt(request.EnvironmentIds...).Len() {
+		message := fmt.Sprintf("environments %v of the profile don't all exist", request.EnvironmentIds)
+		return util.NewApiError(http.StatusBadRequest, message, message)
+	}
+	return nil
+}
+
+func (impl *DebugProfileServiceImpl) getProfile(id int) (*repository.DebugProfile, error) {
+	profile, err := impl.debugProfileRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching debug profile", "id", id, "err", err)
+		if util.IsErrNoRows(err) {
+			message := fmt.Sprintf("debug profile %d does not exist", id)
+			return nil, util.NewApiError(http.StatusNotFound, message, message)
+		}
+		return nil, err
+	}
+	return profile, nil
+}
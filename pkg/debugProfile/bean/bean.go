@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+type DebugProfileConfig struct {
+	// ProfileEnforced rejects ephemeral containers created without a debug profile
+	ProfileEnforced bool   `env:"DEBUG_PROFILE_ENFORCED" envDefault:"false" description:"Ephemeral debug containers can only be created with a debug profile"`
+	ReconcileCron   string `env:"DEBUG_CONTAINER_RECONCILE_CRON" envDefault:"*/15 * * * *" description:"Schedule of the check for pods still carrying debug containers of ended sessions"`
+}
+
+// DebugProfileDto is an admin defined template of ephemeral debug containers. Images are the images users can
+// pick from, StartupCommands are run by the container before it waits for sessions and TtlSeconds, when set,
+// stops the container process that long after it is created. EnvironmentIds restricts the profile to the pods of
+// those environments, the profile can be used on any pod when it is empty
+type DebugProfileDto struct {
+	Id              int                     `json:"id"`
+	Name            string                  `json:"name" validate:"required,max=250"`
+	Description     string                  `json:"description"`
+	Images          []string                `json:"images" validate:"min=1,dive,required"`
+	StartupCommands []string                `json:"startupCommands"`
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+	EnvironmentIds  []int                   `json:"environmentIds"`
+	TtlSeconds      int                     `json:"ttlSeconds" validate:"gte=0"`
+	UserId          int32                   `json:"-"`
+}
+
+// LingeringDebugContainer is a debug container whose session ended while its pod still carries it, ephemeral
+// containers can't be removed from a pod so the pod has to be recreated to get rid of it
+type LingeringDebugContainer struct {
+	EphemeralContainerId int       `json:"ephemeralContainerId"`
+	ClusterId            int       `json:"clusterId"`
+	Namespace            string    `json:"namespace"`
+	PodName              string    `json:"podName"`
+	ContainerName        string    `json:"containerName"`
+	TargetContainerName  string    `json:"targetContainerName"`
+	DebugProfileId       int       `json:"debugProfileId,omitempty"`
+	SessionEndedOn       time.Time `json:"sessionEndedOn"`
+	// Running is set when the process of the container is still running after its session ended
+	Running bool `json:"running"`
+}
+
+type LingeringDebugContainerReport struct {
+	ReconciledOn time.Time                  `json:"reconciledOn"`
+	Containers   []*LingeringDebugContainer `json:"containers"`
+	// ClusterErrors are the clusters whose pods couldn't be checked, by cluster id
+	ClusterErrors map[int]string `json:"clusterErrors,omitempty"`
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package debugProfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devtron-labs/common-lib/utils/k8sObjectsUtil"
+	"github.com/devtron-labs/devtron/internal/util"
+	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/debugProfile/bean"
+	"github.com/devtron-labs/devtron/pkg/debugProfile/repository"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/strings/slices"
+)
+
+// keepAliveLoop keeps the container of a profile without a ttl running until its session terminates it
+const keepAliveLoop = "while true; do sleep 600; done;"
+
+func toDto(model *repository.DebugProfile) (*bean.DebugProfileDto, error) {
+	dto := &bean.DebugProfileDto{
+		Id:              model.Id,
+		Name:            model.Name,
+		Description:     model.Description,
+		Images:          model.Images,
+		StartupCommands: model.StartupCommands,
+		EnvironmentIds:  model.EnvironmentIds,
+		TtlSeconds:      model.TtlSeconds,
+	}
+	if len(model.SecurityContext) > 0 {
+		dto.SecurityContext = &corev1.SecurityContext{}
+		err := json.Unmarshal([]byte(model.SecurityContext), dto.SecurityContext)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dto, nil
+}
+
+func toModel(dto *bean.DebugProfileDto, model *repository.DebugProfile) (*repository.DebugProfile, error) {
+	model.Name = dto.Name
+	model.Description = dto.Description
+	model.Images = dto.Images
+	model.StartupCommands = dto.StartupCommands
+	model.EnvironmentIds = dto.EnvironmentIds
+	model.TtlSeconds = dto.TtlSeconds
+	model.SecurityContext = ""
+	if dto.SecurityContext != nil {
+		securityContext, err := json.Marshal(dto.SecurityContext)
+		if err != nil {
+			return nil, err
+		}
+		model.SecurityContext = string(securityContext)
+	}
+	model.Active = true
+	return model, nil
+}
+
+func validateProfile(dto *bean.DebugProfileDto) error {
+	for _, image := range dto.Images {
+		if strings.ContainsAny(image, " \t\n") {
+			message := fmt.Sprintf("invalid image %q", image)
+			return util.NewApiError(http.StatusBadRequest, message, message)
+		}
+	}
+	for _, command := range dto.StartupCommands {
+		if strings.Contains(command, "\n") {
+			message := "startup commands must be single lines"
+			return util.NewApiError(http.StatusBadRequest, message, message)
+		}
+	}
+	return nil
+}
+
+// isAllowedInEnvironment tells if the profile can be used on the pods of an environment, environmentId is 0 for
+// namespaces which are not an environment
+func isAllowedInEnvironment(profile *bean.DebugProfileDto, environmentId int) bool {
+	if len(profile.EnvironmentIds) == 0 {
+		return true
+	}
+	for _, id := range profile.EnvironmentIds {
+		if id == environmentId {
+			return true
+		}
+	}
+	return false
+}
+
+// selectImage returns the image a container of the profile is created with, the first image of the profile when
+// none is asked for
+func selectImage(profile *bean.DebugProfileDto, image string) (string, error) {
+	if len(image) == 0 {
+		return profile.Images[0], nil
+	}
+	if !slices.Contains(profile.Images, image) {
+		message := fmt.Sprintf("image %s is not allowed by debug profile %s", image, profile.Name)
+		return "", util.NewApiError(http.StatusBadRequest, message, message)
+	}
+	return image, nil
+}
+
+// ApplyProfile sets the image, the security context and the command of a debug container created with the profile.
+// The command writes the startup commands followed by a sleep of the ttl, or the keep alive loop, in the script
+// file sessions are terminated by, so the container process exits by itself once the ttl is over
+func ApplyProfile(container *corev1.EphemeralContainer, profile *bean.DebugProfileDto, image string) error {
+	image, err := selectImage(profile, image)
+	if err != nil {
+		return err
+	}
+	container.Image = image
+	container.SecurityContext = profile.SecurityContext
+	lines := append([]string{}, profile.StartupCommands...)
+	if profile.TtlSeconds > 0 {
+		lines = append(lines, fmt.Sprintf("sleep %d", profile.TtlSeconds))
+	} else {
+		lines = append(lines, keepAliveLoop)
+	}
+	quoted := make([]string, 0, len(lines))
+	for _, line := range lines {
+		quoted = append(quoted, shellQuote(line))
+	}
+	scriptFile := fmt.Sprintf(k8sObjectsUtil.EphemeralContainerStartingShellScriptFileName, container.Name)
+	scriptCreateCommand := fmt.Sprintf("printf '%%s\\n' %s > %s", strings.Join(quoted, " "), scriptFile)
+	container.Command = []string{"sh", "-c", scriptCreateCommand + " && sh " + scriptFile}
+	return nil
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'"'"'`) + "'"
+}
+
+// getSessionEndedOn is the earliest of the termination and the expiry of the session of a container
+func getSessionEndedOn(container *clusterRepository.EndedEphemeralContainer) time.Time {
+	if container.TerminatedOn == nil {
+		return *container.ExpiresOn
+	}
+	if container.ExpiresOn != nil && container.ExpiresOn.Before(*container.TerminatedOn) {
+		return *container.ExpiresOn
+	}
+	return *container.TerminatedOn
+}
+
+// getDebugContainerState tells if the pod still carries the ephemeral container and if its process is running,
+// a recreated pod with the same name doesn't carry it
+func getDebugContainerState(pod *corev1.Pod, containerName string) (present bool, running bool) {
+	for _, container := range pod.Spec.EphemeralContainers {
+		if container.Name == containerName {
+			present = true
+			break
+		}
+	}
+	if !present {
+		return false, false
+	}
+	for _, status := range pod.Status.EphemeralContainerStatuses {
+		if status.Name == containerName {
+			return true, status.State.Running != nil
+		}
+	}
+	return true, false
+}
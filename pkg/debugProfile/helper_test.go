@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package debugProfile
+
+import (
+	"testing"
+	"time"
+
+	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/debugProfile/bean"
+	"github.com/devtron-labs/devtron/pkg/debugProfile/repository"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func newProfile() *bean.DebugProfileDto {
+	runAsNonRoot := true
+	return &bean.DebugProfileDto{
+		Name:            "network",
+		Images:          []string{"nicolaka/netshoot:v0.13", "busybox:1.36"},
+		StartupCommands: []string{"echo 'debug session started'"},
+		SecurityContext: &corev1.SecurityContext{RunAsNonRoot: &runAsNonRoot},
+		EnvironmentIds:  []int{3, 5},
+		TtlSeconds:      1800,
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	t.Run("profile with ttl", func(t *testing.T) {
+		profile := newProfile()
+		container := &corev1.EphemeralContainer{}
+		container.Name = "debugger-abcde"
+		err := ApplyProfile(container, profile, "")
+		assert.Nil(t, err)
+		assert.Equal(t, "nicolaka/netshoot:v0.13", container.Image)
+		assert.Equal(t, profile.SecurityContext, container.SecurityContext)
+		expected := `printf '%s\n' 'echo '"'"'debug session started'"'"'' 'sleep 1800' > ./tmp/debugger-abcde-devtron.sh && sh ./tmp/debugger-abcde-devtron.sh`
+		assert.Equal(t, []string{"sh", "-c", expected}, container.Command)
+	})
+
+	t.Run("profile without ttl keeps the container alive", func(t *testing.T) {
+		profile := newProfile()
+		profile.TtlSeconds = 0
+		profile.StartupCommands = nil
+		container := &corev1.EphemeralContainer{}
+		container.Name = "debugger-abcde"
+		err := ApplyProfile(container, profile, "busybox:1.36")
+		assert.Nil(t, err)
+		assert.Equal(t, "busybox:1.36", container.Image)
+		assert.Equal(t, `printf '%s\n' 'while true; do sleep 600; done;' > ./tmp/debugger-abcde-devtron.sh && sh ./tmp/debugger-abcde-devtron.sh`, container.Command[2])
+	})
+
+	t.Run("image not approved by the profile", func(t *testing.T) {
+		err := ApplyProfile(&corev1.EphemeralContainer{}, newProfile(), "ubuntu:latest")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestIsAllowedInEnvironment(t *testing.T) {
+	profile := newProfile()
+	assert.True(t, isAllowedInEnvironment(profile, 5))
+	assert.False(t, isAllowedInEnvironment(profile, 4))
+	assert.False(t, isAllowedInEnvironment(profile, 0))
+	profile.EnvironmentIds = nil
+	assert.True(t, isAllowedInEnvironment(profile, 0))
+}
+
+func TestProfileModelRoundTrip(t *testing.T) {
+	profile := newProfile()
+	model, err := toModel(profile, &repository.DebugProfile{})
+	assert.Nil(t, err)
+	assert.True(t, model.Active)
+	assert.Equal(t, `{"runAsNonRoot":true}`, model.SecurityContext)
+	dto, err := toDto(model)
+	assert.Nil(t, err)
+	assert.Equal(t, profile, dto)
+}
+
+func TestValidateProfile(t *testing.T) {
+	assert.Nil(t, validateProfile(newProfile()))
+	profile := newProfile()
+	profile.Images = []string{"busybox 1.36"}
+	assert.NotNil(t, validateProfile(profile))
+	profile = newProfile()
+	profile.StartupCommands = []string{"apk add curl\nrm -rf /"}
+	assert.NotNil(t, validateProfile(profile))
+}
+
+func TestGetSessionEndedOn(t *testing.T) {
+	terminatedOn := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	expiresOn := terminatedOn.Add(time.Hour)
+	container := &clusterRepository.EndedEphemeralContainer{TerminatedOn: &terminatedOn}
+	container.ExpiresOn = &expiresOn
+	assert.Equal(t, terminatedOn, getSessionEndedOn(container))
+	container.TerminatedOn = nil
+	assert.Equal(t, expiresOn, getSessionEndedOn(container))
+}
+
+func TestGetDebugContainerState(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{
+			{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "running-debugger"}},
+			{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "exited-debugger"}},
+		}},
+		Status: corev1.PodStatus{EphemeralContainerStatuses: []corev1.ContainerStatus{
+			{Name: "running-debugger", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			{Name: "exited-debugger", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+		}},
+	}
+	present, running := getDebugContainerState(pod, "running-debugger")
+	assert.True(t, present)
+	assert.True(t, running)
+	present, running = getDebugContainerState(pod, "exited-debugger")
+	assert.True(t, present)
+	assert.False(t, running)
+	present, _ = getDebugContainerState(pod, "debugger-of-replaced-pod")
+	assert.False(t, present)
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type DebugProfile struct {
+	tableName       struct{} `sql:"debug_profile" pg:",discard_unknown_columns"`
+	Id              int      `sql:"id,pk"`
+	Name            string   `sql:"name,notnull"`
+	Description     string   `sql:"description"`
+	Images          []string `sql:"images" pg:",array"`
+	StartupCommands []string `sql:"startup_commands" pg:",array"`
+	// SecurityContext is the json of the security context of the containers
+	SecurityContext string `sql:"security_context"`
+	EnvironmentIds  []int  `sql:"environment_ids" pg:",array"`
+	TtlSeconds      int    `sql:"ttl_seconds,notnull"`
+	Active          bool   `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type DebugProfileRepository interface {
+	Save(model *DebugProfile) error
+	Update(model *DebugProfile) error
+	FindById(id int) (*DebugProfile, error)
+	FindAllActive() ([]*DebugProfile, error)
+}
+
+type DebugProfileRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewDebugProfileRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *DebugProfileRepositoryImpl {
+	return &DebugProfileRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *DebugProfileRepositoryImpl) Save(model *DebugProfile) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *DebugProfileRepositoryImpl) Update(model *DebugProfile) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *DebugProfileRepositoryImpl) FindById(id int) (*DebugProfile, error) {
+	model := &DebugProfile{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *DebugProfileRepositoryImpl) FindAllActive() ([]*DebugProfile, error) {
+	var models []*DebugProfile
+	err := impl.dbConnection.Model(&models).
+		Where("active = ?", true).
+		Order("name").
+		Select()
+	return models, err
+}
@@ -0,0 +1,386 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dryRun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	k8sUtil "github.com/devtron-labs/common-lib/utils/k8s"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/api/helm-app/gRPC"
+	helmRead "github.com/devtron-labs/devtron/api/helm-app/service/read"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/app"
+	"github.com/devtron-labs/devtron/pkg/deployment/common"
+	commonBean "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/dryRun/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate"
+	deploymentTemplateBean "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/read"
+	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/adapter"
+	triggerBean "github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
+	"github.com/devtron-labs/devtron/pkg/generateManifest"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/pointer"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// DryRunService renders the manifests a cd trigger would apply and diffs them against the deployed release,
+// without saving anything in db or applying anything on the target cluster
+type DryRunService interface {
+	DryRun(ctx context.Context, request *bean.DryRunRequest) (*bean.DryRunResponse, error)
+}
+
+type DryRunServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	pipelineRepository           pipelineConfig.PipelineRepository
+	cdWorkflowRepository         pipelineConfig.CdWorkflowRepository
+	pipelineOverrideRepository   chartConfig.PipelineOverrideRepository
+	envConfigOverrideReadService read.EnvConfigOverrideService
+	deploymentConfigService      common.DeploymentConfigService
+	manifestCreationService      manifest.ManifestCreationService
+	deploymentTemplateService    deploymentTemplate.DeploymentTemplateService
+	chartTemplateService         util.ChartTemplateService
+	appService                   app.AppService
+	helmAppClient                gRPC.HelmAppClient
+	helmAppReadService           helmRead.HelmAppReadService
+	k8sCommonService             k8s.K8sCommonService
+	K8sUtil                      *k8sUtil.K8sServiceImpl
+}
+
+func NewDryRunServiceImpl(logger *zap.SugaredLogger,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	pipelineOverrideRepository chartConfig.PipelineOverrideRepository,
+	envConfigOverrideReadService read.EnvConfigOverrideService,
+	deploymentConfigService common.DeploymentConfigService,
+	manifestCreationService manifest.ManifestCreationService,
+	deploymentTemplateService deploymentTemplate.DeploymentTemplateService,
+	chartTemplateService util.ChartTemplateService,
+	appService app.AppService,
+	helmAppClient gRPC.HelmAppClient,
+	helmAppReadService helmRead.HelmAppReadService,
+	k8sCommonService k8s.K8sCommonService,
+	K8sUtil *k8sUtil.K8sServiceImpl) *DryRunServiceImpl {
+	return &DryRunServiceImpl{
+		logger:                       logger,
+		pipelineRepository:           pipelineRepository,
+		cdWorkflowRepository:         cdWorkflowRepository,
+		pipelineOverrideRepository:   pipelineOverrideRepository,
+		envConfigOverrideReadService: envConfigOverrideReadService,
+		deploymentConfigService:      deploymentConfigService,
+		manifestCreationService:      manifestCreationService,
+		deploymentTemplateService:    deploymentTemplateService,
+		chartTemplateService:         chartTemplateService,
+		appService:                   appService,
+		helmAppClient:                helmAppClient,
+		helmAppReadService:           helmAppReadService,
+		k8sCommonService:             k8sCommonService,
+		K8sUtil:                      K8sUtil,
+	}
+}
+
+func (impl *DryRunServiceImpl) DryRun(ctx context.Context, request *bean.DryRunRequest) (*bean.DryRunResponse, error) {
+	pipeline, err := impl.pipelineRepository.FindById(request.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline", "pipelineId", request.PipelineId, "err", err)
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "pipeline not found", err.Error())
+		}
+		return nil, err
+	}
+	if pipeline.AppId != request.AppId {
+		return nil, util.NewApiError(http.StatusBadRequest, "pipeline does not belong to the app", "pipeline does not belong to the app")
+	}
+	envDeploymentConfig, err := impl.deploymentConfigService.GetConfigForDevtronApps(pipeline.AppId, pipeline.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment deployment config", "appId", pipeline.AppId, "envId", pipeline.EnvironmentId, "err", err)
+		return nil, err
+	}
+	overrideRequest := impl.getOverrideRequest(request, pipeline, envDeploymentConfig)
+	valuesOverrideResponse, err := impl.manifestCreationService.GetValuesOverrideForTrigger(overrideRequest, time.Now(), ctx)
+	if err != nil {
+		impl.logger.Errorw("error in rendering values for dry run", "pipelineId", request.PipelineId, "ciArtifactId", request.CiArtifactId, "err", err)
+		return nil, err
+	}
+	isVirtualEnv := pipeline.Environment.IsVirtualEnvironment
+	var restConfig *rest.Config
+	var k8sVersion string
+	if isVirtualEnv {
+		k8sServerVersion, err := impl.K8sUtil.GetKubeVersion()
+		if err != nil {
+			impl.logger.Errorw("error in getting k8s server version", "err", err)
+			return nil, err
+		}
+		k8sVersion = k8sServerVersion.String()
+	} else {
+		restConfig, k8sVersion, err = impl.getTargetClusterDetails(ctx, pipeline.Environment.ClusterId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	desiredManifest, err := impl.renderDesiredManifest(ctx, overrideRequest, valuesOverrideResponse, k8sVersion)
+	if err != nil {
+		return nil, err
+	}
+	deployedWfrId, deployedManifest, err := impl.renderDeployedManifest(ctx, overrideRequest, k8sVersion)
+	if err != nil {
+		return nil, err
+	}
+	desiredObjects, err := parseManifest(desiredManifest)
+	if err != nil {
+		impl.logger.Errorw("error in parsing rendered manifest", "pipelineId", request.PipelineId, "err", err)
+		return nil, util.NewApiError(http.StatusUnprocessableEntity, "rendered manifest is not valid yaml", err.Error())
+	}
+	deployedObjects, err := parseManifest(deployedManifest)
+	if err != nil {
+		impl.logger.Errorw("error in parsing deployed manifest", "pipelineId", request.PipelineId, "err", err)
+		return nil, err
+	}
+	diffs, err := diffResources(deployedObjects, desiredObjects)
+	if err != nil {
+		impl.logger.Errorw("error in computing manifest diff", "pipelineId", request.PipelineId, "err", err)
+		return nil, err
+	}
+
+	serverDryRunPerformed := !request.SkipServerDryRun && !isVirtualEnv
+	if serverDryRunPerformed {
+		impl.serverSideDryRun(ctx, restConfig, overrideRequest.Namespace, desiredObjects, diffs)
+	}
+	response := &bean.DryRunResponse{
+		AppId:                 pipeline.AppId,
+		PipelineId:            pipeline.Id,
+		EnvironmentId:         pipeline.EnvironmentId,
+		CiArtifactId:          request.CiArtifactId,
+		DeploymentWithConfig:  overrideRequest.DeploymentWithConfig,
+		DeployedWfrId:         deployedWfrId,
+		ServerDryRunPerformed: serverDryRunPerformed,
+		Summary:               getDryRunSummary(diffs),
+		Resources:             diffs,
+	}
+	if valuesOverrideResponse.Artifact != nil {
+		response.Image = valuesOverrideResponse.Artifact.Image
+	}
+	return response, nil
+}
+
+func (impl *DryRunServiceImpl) getOverrideRequest(request *bean.DryRunRequest, pipeline *pipelineConfig.Pipeline, envDeploymentConfig *commonBean.DeploymentConfig) *apiBean.ValuesOverrideRequest {
+	overrideRequest := &apiBean.ValuesOverrideRequest{
+		CiArtifactId:                          request.CiArtifactId,
+		AdditionalOverride:                    request.AdditionalOverride,
+		DeploymentWithConfig:                  request.DeploymentWithConfig,
+		WfrIdForDeploymentWithSpecificTrigger: request.WfrIdForDeploymentWithSpecificTrigger,
+		CdWorkflowType:                        apiBean.CD_WORKFLOW_TYPE_DEPLOY,
+		UserId:                                request.UserId,
+		IsDryRun:                              true,
+	}
+	if len(overrideRequest.DeploymentWithConfig) == 0 {
+		overrideRequest.DeploymentWithConfig = apiBean.DEPLOYMENT_CONFIG_TYPE_LAST_SAVED
+	}
+	adapter.SetPipelineFieldsInOverrideRequest(overrideRequest, pipeline, envDeploymentConfig)
+	return overrideRequest
+}
+
+// getTargetClusterDetails returns the rest config and the server version of the cluster the pipeline deploys to
+func (impl *DryRunServiceImpl) getTargetClusterDetails(ctx context.Context, clusterId int) (*rest.Config, string, error) {
+	restConfig, err, clusterBean := impl.k8sCommonService.GetRestConfigByClusterId(ctx, clusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting rest config by cluster id", "clusterId", clusterId, "err", err)
+		return nil, "", err
+	}
+	discoveryClient, err := impl.K8sUtil.GetK8sDiscoveryClient(clusterBean.GetClusterConfig())
+	if err != nil {
+		impl.logger.Errorw("error in getting discovery client", "clusterId", clusterId, "err", err)
+		return nil, "", err
+	}
+	k8sServerVersion, err := discoveryClient.ServerVersion()
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s server version", "clusterId", clusterId, "err", err)
+		return nil, "", err
+	}
+	return restConfig, k8sServerVersion.String(), nil
+}
+
+func (impl *DryRunServiceImpl) renderDesiredManifest(ctx context.Context, overrideRequest *apiBean.ValuesOverrideRequest,
+	valuesOverrideResponse *app.ValuesOverrideResponse, k8sVersion string) (string, error) {
+	envOverride := valuesOverrideResponse.EnvOverride
+	builtChartPath, err := impl.deploymentTemplateService.BuildChartAndGetPath(overrideRequest.AppName, envOverride, ctx)
+	if err != nil {
+		impl.logger.Errorw("error in building chart for dry run", "appName", overrideRequest.AppName, "err", err)
+		return "", err
+	}
+	chartBytes, err := impl.chartTemplateService.LoadChartInBytes(builtChartPath, true)
+	if err != nil {
+		impl.logger.Errorw("error in loading chart bytes", "builtChartPath", builtChartPath, "err", err)
+		return "", err
+	}
+	return impl.templateChart(ctx, overrideRequest, envOverride, chartBytes, valuesOverrideResponse.MergedValues, k8sVersion)
+}
+
+// renderDeployedManifest renders the manifest of the last deployment on the pipeline which was not failed,
+// returns empty manifest if the pipeline was never deployed
+func (impl *DryRunServiceImpl) renderDeployedManifest(ctx context.Context, overrideRequest *apiBean.ValuesOverrideRequest, k8sVersion string) (int, string, error) {
+	wfr, err := impl.cdWorkflowRepository.FindLastUnFailedProcessedRunner(overrideRequest.AppId, overrideRequest.EnvId)
+	if util.IsErrNoRows(err) {
+		return 0, "", nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching last deployed runner", "appId", overrideRequest.AppId, "envId", overrideRequest.EnvId, "err", err)
+		return 0, "", err
+	}
+	pipelineOverride, err := impl.pipelineOverrideRepository.FindLatestByCdWorkflowId(wfr.CdWorkflowId)
+	if util.IsErrNoRows(err) {
+		return 0, "", nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching deployed pipeline override", "cdWorkflowId", wfr.CdWorkflowId, "err", err)
+		return 0, "", err
+	}
+	envOverride, err := impl.envConfigOverrideReadService.GetByIdIncludingInactive(pipelineOverride.EnvConfigOverrideId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployed env config override", "id", pipelineOverride.EnvConfigOverrideId, "err", err)
+		return 0, "", err
+	}
+	chartBytes, err := impl.appService.GetDeployedManifestByPipelineIdAndCDWorkflowId(overrideRequest.AppId, overrideRequest.EnvId, wfr.CdWorkflowId, ctx)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployed chart", "appId", overrideRequest.AppId, "envId", overrideRequest.EnvId, "cdWorkflowId", wfr.CdWorkflowId, "err", err)
+		return 0, "", err
+	}
+	deployedManifest, err := impl.templateChart(ctx, overrideRequest, envOverride, chartBytes, pipelineOverride.PipelineMergedValues, k8sVersion)
+	if err != nil {
+		return 0, "", err
+	}
+	return wfr.Id, deployedManifest, nil
+}
+
+func (impl *DryRunServiceImpl) templateChart(ctx context.Context, overrideRequest *apiBean.ValuesOverrideRequest, envOverride *deploymentTemplateBean.EnvConfigOverride,
+	chartBytes []byte, valuesYaml string, k8sVersion string) (string, error) {
+	//handle specific case for all cronjob charts from cronjob-chart_1-2-0 to cronjob-chart_1-5-0, see DeploymentTemplateService.GenerateManifest
+	if regexp.MustCompile(triggerBean.CronJobChartRegexExpression).MatchString(envOverride.Chart.ReferenceTemplate) {
+		k8sVersion = k8s.StripPrereleaseFromK8sVersion(k8sVersion)
+	}
+	installReleaseRequest := &gRPC.InstallReleaseRequest{
+		AppName:         overrideRequest.AppName,
+		ChartName:       envOverride.Chart.ChartName,
+		ChartVersion:    envOverride.Chart.ChartVersion,
+		ValuesYaml:      valuesYaml,
+		K8SVersion:      k8sVersion,
+		ChartRepository: generateManifest.ChartRepository,
+		ReleaseIdentifier: &gRPC.ReleaseIdentifier{
+			ReleaseName:      overrideRequest.ReleaseName,
+			ReleaseNamespace: overrideRequest.Namespace,
+		},
+		ChartContent: &gRPC.ChartContent{
+			Content: chartBytes,
+		},
+	}
+	config, err := impl.helmAppReadService.GetClusterConf(overrideRequest.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster detail", "clusterId", overrideRequest.ClusterId, "err", err)
+		return "", err
+	}
+	installReleaseRequest.ReleaseIdentifier.ClusterConfig = config
+	templateChartResponse, err := impl.helmAppClient.TemplateChart(ctx, installReleaseRequest)
+	if err != nil {
+		impl.logger.Errorw("error in templating chart", "appName", overrideRequest.AppName, "err", err)
+		clientErrCode, errMsg := util.GetClientDetailedError(err)
+		if clientErrCode.IsFailedPreconditionCode() || clientErrCode.IsInvalidArgumentCode() {
+			return "", &util.ApiError{HttpStatusCode: http.StatusUnprocessableEntity, Code: strconv.Itoa(http.StatusUnprocessableEntity), InternalMessage: errMsg, UserMessage: errMsg}
+		}
+		return "", err
+	}
+	return templateChartResponse.GeneratedManifest, nil
+}
+
+// serverSideDryRun validates every desired object against the target cluster with a server side apply in dry run mode,
+// results are set on the corresponding resource diffs
+func (impl *DryRunServiceImpl) serverSideDryRun(ctx context.Context, restConfig *rest.Config, namespace string,
+	desiredObjects []*unstructured.Unstructured, diffs []*bean.ResourceDiff) {
+	diffByKey := make(map[resourceKey]*bean.ResourceDiff, len(diffs))
+	for _, diff := range diffs {
+		diffByKey[resourceKey{group: diff.Group, kind: diff.Kind, namespace: diff.Namespace, name: diff.Name}] = diff
+	}
+	type resourceIfWithScope struct {
+		resourceIf dynamic.NamespaceableResourceInterface
+		namespaced bool
+	}
+	resourceIfByGvk := make(map[schema.GroupVersionKind]*resourceIfWithScope)
+	for _, obj := range desiredObjects {
+		diff, ok := diffByKey[getResourceKey(obj)]
+		if !ok {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		resourceIf, ok := resourceIfByGvk[gvk]
+		if !ok {
+			dynamicIf, namespaced, err := impl.K8sUtil.GetResourceIf(restConfig, gvk)
+			if err != nil {
+				impl.logger.Errorw("error in getting dynamic interface for resource", "gvk", gvk, "err", err)
+				diff.ServerDryRun = &bean.ServerDryRunResult{Status: bean.ServerDryRunFailed, Message: err.Error()}
+				continue
+			}
+			resourceIf = &resourceIfWithScope{resourceIf: dynamicIf, namespaced: namespaced}
+			resourceIfByGvk[gvk] = resourceIf
+		}
+		diff.ServerDryRun = impl.applyInDryRunMode(ctx, resourceIf.resourceIf, resourceIf.namespaced, namespace, obj)
+	}
+	for _, diff := range diffs {
+		if diff.ServerDryRun == nil && diff.ChangeType != bean.ResourceRemoved {
+			diff.ServerDryRun = &bean.ServerDryRunResult{Status: bean.ServerDryRunSkipped}
+		}
+	}
+}
+
+func (impl *DryRunServiceImpl) applyInDryRunMode(ctx context.Context, resourceIf dynamic.NamespaceableResourceInterface, namespaced bool,
+	defaultNamespace string, obj *unstructured.Unstructured) *bean.ServerDryRunResult {
+	manifestJson, err := json.Marshal(obj.Object)
+	if err != nil {
+		return &bean.ServerDryRunResult{Status: bean.ServerDryRunFailed, Message: err.Error()}
+	}
+	patchOptions := metav1.PatchOptions{
+		DryRun:       []string{metav1.DryRunAll},
+		FieldManager: bean.DryRunFieldManager,
+		Force:        pointer.Bool(true),
+	}
+	if namespaced {
+		namespace := obj.GetNamespace()
+		if len(namespace) == 0 {
+			namespace = defaultNamespace
+		}
+		_, err = resourceIf.Namespace(namespace).Patch(ctx, obj.GetName(), types.ApplyPatchType, manifestJson, patchOptions)
+	} else {
+		_, err = resourceIf.Patch(ctx, obj.GetName(), types.ApplyPatchType, manifestJson, patchOptions)
+	}
+	if err != nil {
+		impl.logger.Debugw("server side dry run failed", "kind", obj.GetKind(), "name", obj.GetName(), "err", err)
+		return &bean.ServerDryRunResult{Status: bean.ServerDryRunFailed, Message: fmt.Sprintf("%s/%s: %s", obj.GetKind(), obj.GetName(), err.Error())}
+	}
+	return &bean.ServerDryRunResult{Status: bean.ServerDryRunSucceeded}
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"encoding/json"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+)
+
+type ResourceChangeType string
+
+const (
+	ResourceAdded     ResourceChangeType = "ADDED"
+	ResourceRemoved   ResourceChangeType = "REMOVED"
+	ResourceModified  ResourceChangeType = "MODIFIED"
+	ResourceUnchanged ResourceChangeType = "UNCHANGED"
+)
+
+type ServerDryRunStatus string
+
+const (
+	ServerDryRunSucceeded ServerDryRunStatus = "Succeeded"
+	ServerDryRunFailed    ServerDryRunStatus = "Failed"
+	ServerDryRunSkipped   ServerDryRunStatus = "Skipped"
+)
+
+// MaskedSecretValue replaces secret data in dry run responses
+const MaskedSecretValue = "********"
+
+const DryRunFieldManager = "devtron-dry-run"
+
+type DryRunRequest struct {
+	PipelineId                            int                                 `json:"pipelineId" validate:"required"`
+	AppId                                 int                                 `json:"appId" validate:"required"`
+	CiArtifactId                          int                                 `json:"ciArtifactId" validate:"required"`
+	DeploymentWithConfig                  apiBean.DeploymentConfigurationType `json:"deploymentWithConfig"`
+	WfrIdForDeploymentWithSpecificTrigger int                                 `json:"wfrIdForDeploymentWithSpecificTrigger"`
+	AdditionalOverride                    json.RawMessage                     `json:"additionalOverride,omitempty"`
+	// SkipServerDryRun only renders and diffs the manifests, without validating them against the target cluster
+	SkipServerDryRun bool  `json:"skipServerDryRun"`
+	UserId           int32 `json:"-"`
+}
+
+type DryRunResponse struct {
+	AppId                int                                 `json:"appId"`
+	PipelineId           int                                 `json:"pipelineId"`
+	EnvironmentId        int                                 `json:"environmentId"`
+	CiArtifactId         int                                 `json:"ciArtifactId"`
+	Image                string                              `json:"image"`
+	DeploymentWithConfig apiBean.DeploymentConfigurationType `json:"deploymentWithConfig"`
+	// DeployedWfrId is the cd workflow runner whose manifest the diff is computed against, 0 if nothing is deployed yet
+	DeployedWfrId         int             `json:"deployedWfrId"`
+	ServerDryRunPerformed bool            `json:"serverDryRunPerformed"`
+	Summary               *DryRunSummary  `json:"summary"`
+	Resources             []*ResourceDiff `json:"resources"`
+}
+
+type DryRunSummary struct {
+	Added              int `json:"added"`
+	Removed            int `json:"removed"`
+	Modified           int `json:"modified"`
+	Unchanged          int `json:"unchanged"`
+	ServerDryRunFailed int `json:"serverDryRunFailed"`
+}
+
+type ResourceDiff struct {
+	Group            string              `json:"group"`
+	Version          string              `json:"version"`
+	Kind             string              `json:"kind"`
+	Namespace        string              `json:"namespace"`
+	Name             string              `json:"name"`
+	ChangeType       ResourceChangeType  `json:"changeType"`
+	FieldChanges     []*FieldChange      `json:"fieldChanges,omitempty"`
+	DesiredManifest  string              `json:"desiredManifest,omitempty"`
+	DeployedManifest string              `json:"deployedManifest,omitempty"`
+	ServerDryRun     *ServerDryRunResult `json:"serverDryRun,omitempty"`
+}
+
+type FieldChange struct {
+	Path     string      `json:"path"`
+	OldValue interface{} `json:"oldValue"`
+	NewValue interface{} `json:"newValue"`
+}
+
+type ServerDryRunResult struct {
+	Status  ServerDryRunStatus `json:"status"`
+	Message string             `json:"message,omitempty"`
+}
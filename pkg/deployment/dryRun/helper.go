@@ -0,0 +1,239 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dryRun
+
+import (
+	"fmt"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/devtron-labs/devtron/pkg/deployment/dryRun/bean"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"reflect"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strings"
+)
+
+const secretKind = "Secret"
+
+type resourceKey struct {
+	group     string
+	kind      string
+	namespace string
+	name      string
+}
+
+func getResourceKey(obj *unstructured.Unstructured) resourceKey {
+	gvk := obj.GroupVersionKind()
+	return resourceKey{group: gvk.Group, kind: gvk.Kind, namespace: obj.GetNamespace(), name: obj.GetName()}
+}
+
+// parseManifest splits a rendered multi document manifest into objects, skipping empty documents
+func parseManifest(manifest string) ([]*unstructured.Unstructured, error) {
+	if len(strings.TrimSpace(manifest)) == 0 {
+		return nil, nil
+	}
+	objects, err := kube.SplitYAML([]byte(manifest))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*unstructured.Unstructured, 0, len(objects))
+	for _, obj := range objects {
+		if obj == nil || len(obj.GetKind()) == 0 {
+			continue
+		}
+		result = append(result, obj)
+	}
+	return result, nil
+}
+
+// diffResources compares the deployed objects with the desired ones and returns one diff per resource,
+// sorted by kind and name. Secret data is masked in the returned field changes and manifests.
+func diffResources(deployed, desired []*unstructured.Unstructured) ([]*bean.ResourceDiff, error) {
+	deployedByKey := make(map[resourceKey]*unstructured.Unstructured, len(deployed))
+	for _, obj := range deployed {
+		deployedByKey[getResourceKey(obj)] = obj
+	}
+	diffs := make([]*bean.ResourceDiff, 0, len(desired)+len(deployed))
+	seen := make(map[resourceKey]bool, len(desired))
+	for _, obj := range desired {
+		key := getResourceKey(obj)
+		seen[key] = true
+		diff := newResourceDiff(obj)
+		desiredManifest, err := toMaskedYaml(obj)
+		if err != nil {
+			return nil, err
+		}
+		diff.DesiredManifest = desiredManifest
+		deployedObj, ok := deployedByKey[key]
+		if !ok {
+			diff.ChangeType = bean.ResourceAdded
+			diffs = append(diffs, diff)
+			continue
+		}
+		deployedManifest, err := toMaskedYaml(deployedObj)
+		if err != nil {
+			return nil, err
+		}
+		diff.DeployedManifest = deployedManifest
+		diff.FieldChanges = diffFields("", deployedObj.Object, obj.Object)
+		if len(diff.FieldChanges) > 0 {
+			diff.ChangeType = bean.ResourceModified
+		} else {
+			diff.ChangeType = bean.ResourceUnchanged
+		}
+		if isSecret(obj) {
+			maskSecretFieldChanges(diff.FieldChanges)
+		}
+		diffs = append(diffs, diff)
+	}
+	for _, obj := range deployed {
+		if seen[getResourceKey(obj)] {
+			continue
+		}
+		diff := newResourceDiff(obj)
+		deployedManifest, err := toMaskedYaml(obj)
+		if err != nil {
+			return nil, err
+		}
+		diff.DeployedManifest = deployedManifest
+		diff.ChangeType = bean.ResourceRemoved
+		diffs = append(diffs, diff)
+	}
+	sort.SliceStable(diffs, func(i, j int) bool {
+		if diffs[i].Kind != diffs[j].Kind {
+			return diffs[i].Kind < diffs[j].Kind
+		}
+		if diffs[i].Namespace != diffs[j].Namespace {
+			return diffs[i].Namespace < diffs[j].Namespace
+		}
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs, nil
+}
+
+func newResourceDiff(obj *unstructured.Unstructured) *bean.ResourceDiff {
+	gvk := obj.GroupVersionKind()
+	return &bean.ResourceDiff{
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Kind:      gvk.Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+}
+
+// diffFields walks both values and records leaf level changes with their dotted path,
+// lists are compared as a whole as positional diffs of lists are rarely meaningful to reviewers
+func diffFields(path string, oldValue, newValue interface{}) []*bean.FieldChange {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if !oldIsMap || !newIsMap {
+		if reflect.DeepEqual(oldValue, newValue) {
+			return nil
+		}
+		return []*bean.FieldChange{{Path: path, OldValue: oldValue, NewValue: newValue}}
+	}
+	keys := make([]string, 0, len(oldMap)+len(newMap))
+	for key := range oldMap {
+		keys = append(keys, key)
+	}
+	for key := range newMap {
+		if _, ok := oldMap[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	changes := make([]*bean.FieldChange, 0)
+	for _, key := range keys {
+		childPath := key
+		if len(path) > 0 {
+			childPath = fmt.Sprintf("%s.%s", path, key)
+		}
+		changes = append(changes, diffFields(childPath, oldMap[key], newMap[key])...)
+	}
+	return changes
+}
+
+func isSecret(obj *unstructured.Unstructured) bool {
+	return obj.GetKind() == secretKind && obj.GroupVersionKind().Group == ""
+}
+
+func isSecretDataPath(path string) bool {
+	for _, prefix := range []string{"data", "stringData"} {
+		if path == prefix || strings.HasPrefix(path, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func maskSecretFieldChanges(changes []*bean.FieldChange) {
+	for _, change := range changes {
+		if !isSecretDataPath(change.Path) {
+			continue
+		}
+		if change.OldValue != nil {
+			change.OldValue = bean.MaskedSecretValue
+		}
+		if change.NewValue != nil {
+			change.NewValue = bean.MaskedSecretValue
+		}
+	}
+}
+
+// toMaskedYaml returns the yaml of the object, masking secret data values
+func toMaskedYaml(obj *unstructured.Unstructured) (string, error) {
+	toMarshal := obj.Object
+	if isSecret(obj) {
+		masked := obj.DeepCopy()
+		for _, field := range []string{"data", "stringData"} {
+			data, ok := masked.Object[field].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for key := range data {
+				data[key] = bean.MaskedSecretValue
+			}
+		}
+		toMarshal = masked.Object
+	}
+	manifest, err := yaml.Marshal(toMarshal)
+	if err != nil {
+		return "", err
+	}
+	return string(manifest), nil
+}
+
+func getDryRunSummary(diffs []*bean.ResourceDiff) *bean.DryRunSummary {
+	summary := &bean.DryRunSummary{}
+	for _, diff := range diffs {
+		switch diff.ChangeType {
+		case bean.ResourceAdded:
+			summary.Added++
+		case bean.ResourceRemoved:
+			summary.Removed++
+		case bean.ResourceModified:
+			summary.Modified++
+		case bean.ResourceUnchanged:
+			summary.Unchanged++
+		}
+		if diff.ServerDryRun != nil && diff.ServerDryRun.Status == bean.ServerDryRunFailed {
+			summary.ServerDryRunFailed++
+		}
+	}
+	return summary
+}
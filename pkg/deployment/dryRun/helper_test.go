@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dryRun
+
+import (
+	"github.com/devtron-labs/devtron/pkg/deployment/dryRun/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const deployedManifest = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: app-svc
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - image: app:v1
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app-secret
+data:
+  password: b2xk
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-cm
+data:
+  key: value
+`
+
+const desiredManifest = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: app-svc
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - image: app:v2
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app-secret
+data:
+  password: bmV3
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: app-hpa
+`
+
+func TestDiffResources(t *testing.T) {
+	deployed, err := parseManifest(deployedManifest)
+	assert.Nil(t, err)
+	desired, err := parseManifest(desiredManifest)
+	assert.Nil(t, err)
+	diffs, err := diffResources(deployed, desired)
+	assert.Nil(t, err)
+	diffByName := make(map[string]*bean.ResourceDiff)
+	for _, diff := range diffs {
+		diffByName[diff.Name] = diff
+	}
+	assert.Len(t, diffs, 5)
+	assert.Equal(t, bean.ResourceUnchanged, diffByName["app-svc"].ChangeType)
+	assert.Equal(t, bean.ResourceAdded, diffByName["app-hpa"].ChangeType)
+	assert.Equal(t, bean.ResourceRemoved, diffByName["app-cm"].ChangeType)
+
+	deployment := diffByName["app"]
+	assert.Equal(t, bean.ResourceModified, deployment.ChangeType)
+	assert.Len(t, deployment.FieldChanges, 2)
+	assert.Equal(t, "spec.replicas", deployment.FieldChanges[0].Path)
+	assert.Equal(t, "spec.template.spec.containers", deployment.FieldChanges[1].Path)
+
+	secret := diffByName["app-secret"]
+	assert.Equal(t, bean.ResourceModified, secret.ChangeType)
+	assert.Equal(t, bean.MaskedSecretValue, secret.FieldChanges[0].OldValue)
+	assert.Equal(t, bean.MaskedSecretValue, secret.FieldChanges[0].NewValue)
+	assert.NotContains(t, secret.DesiredManifest, "bmV3")
+	assert.NotContains(t, secret.DeployedManifest, "b2xk")
+
+	summary := getDryRunSummary(diffs)
+	assert.Equal(t, &bean.DryRunSummary{Added: 1, Removed: 1, Modified: 2, Unchanged: 1}, summary)
+}
+
+func TestDiffFields(t *testing.T) {
+	oldValue := map[string]interface{}{"a": map[string]interface{}{"b": 1, "c": "x"}, "d": true}
+	newValue := map[string]interface{}{"a": map[string]interface{}{"b": 2, "c": "x"}, "e": "new"}
+	changes := diffFields("", oldValue, newValue)
+	assert.Equal(t, []*bean.FieldChange{
+		{Path: "a.b", OldValue: 1, NewValue: 2},
+		{Path: "d", OldValue: true, NewValue: nil},
+		{Path: "e", OldValue: nil, NewValue: "new"},
+	}, changes)
+}
@@ -32,6 +32,7 @@ import (
 	dirCopy "github.com/otiai10/copy"
 	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
+	"io/fs"
 	"net/url"
 	"os"
 	"path"
@@ -49,6 +50,8 @@ type GitOperationService interface {
 	CommitValues(ctx context.Context, chartGitAttr *ChartConfig) (commitHash string, commitTime time.Time, err error)
 	PushChartToGitRepo(ctx context.Context, gitOpsRepoName, referenceTemplate, version, tempReferenceTemplateDir, repoUrl string, userId int32) (err error)
 	PushChartToGitOpsRepoForHelmApp(ctx context.Context, PushChartToGitRequest *bean.PushChartToGitRequestDTO, requirementsConfig *ChartConfig, valuesConfig *ChartConfig) (*commonBean.ChartGitAttribute, string, error)
+	// DeleteFileFromGitRepo removes all files with the given name from the repo; nothing is committed if no such file exists
+	DeleteFileFromGitRepo(ctx context.Context, repoUrl, fileName, commitMsg string, userId int32) error
 
 	CreateRepository(ctx context.Context, dto *apiBean.GitOpsConfigDto, userId int32) (string, bool, error)
 	GetRepoUrlByRepoName(repoName string) (string, error)
@@ -272,6 +275,77 @@ func (impl *GitOperationServiceImpl) CommitValues(ctx context.Context, chartGitA
 	return commitHash, commitTime, nil
 }
 
+func (impl *GitOperationServiceImpl) DeleteFileFromGitRepo(ctx context.Context, repoUrl, fileName, commitMsg string, userId int32) error {
+	newCtx, span := otel.Tracer("orchestrator").Start(ctx, "GitOperationServiceImpl.DeleteFileFromGitRepo")
+	defer span.End()
+	gitOpsRepoName := impl.gitOpsConfigReadService.GetGitOpsRepoNameFromUrl(repoUrl)
+	chartDir := fmt.Sprintf("%s-%s", gitOpsRepoName, impl.chartTemplateService.GetDir())
+	clonedDir, err := impl.getClonedDir(newCtx, chartDir, repoUrl)
+	defer impl.chartTemplateService.CleanDir(clonedDir)
+	if err != nil {
+		impl.logger.Errorw("error in cloning repo", "url", repoUrl, "err", err)
+		return err
+	}
+	userEmailId, userName := impl.gitOpsConfigReadService.GetUserEmailIdAndNameForGitOpsCommit(userId)
+	callback := func() error {
+		err := impl.GitPull(clonedDir, repoUrl)
+		if err != nil {
+			impl.logger.Errorw("error in pulling git repo", "url", repoUrl, "err", err)
+			return err
+		}
+		removedFiles, err := removeFilesByName(clonedDir, fileName)
+		if err != nil {
+			impl.logger.Errorw("error in removing files from cloned repo", "url", repoUrl, "fileName", fileName, "err", err)
+			return err
+		}
+		if removedFiles == 0 {
+			impl.logger.Debugw("file not found in git repo, skipping commit", "url", repoUrl, "fileName", fileName)
+			return nil
+		}
+		commit, err := impl.gitFactory.GitOpsHelper.CommitAndPushAllChanges(newCtx, clonedDir, commitMsg, userName, userEmailId)
+		if err != nil {
+			impl.logger.Errorw("error in pushing git", "err", err)
+			return retryFunc.NewRetryableError(err)
+		}
+		impl.logger.Debugw("file deleted from git repo", "url", repoUrl, "fileName", fileName, "commit", commit)
+		return nil
+	}
+	err = retryFunc.Retry(callback, impl.isRetryableGitCommitError,
+		impl.globalEnvVariables.ArgoGitCommitRetryCountOnConflict,
+		time.Duration(impl.globalEnvVariables.ArgoGitCommitRetryDelayOnConflict)*time.Second,
+		impl.logger)
+	if err != nil {
+		impl.logger.Errorw("error in deleting file from git repo", "url", repoUrl, "fileName", fileName, "err", err)
+		return err
+	}
+	return nil
+}
+
+func removeFilesByName(rootDir, fileName string) (int, error) {
+	var filePaths []string
+	err := filepath.WalkDir(rootDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !entry.IsDir() && entry.Name() == fileName {
+			filePaths = append(filePaths, filePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, filePath := range filePaths {
+		if err = os.Remove(filePath); err != nil {
+			return 0, err
+		}
+	}
+	return len(filePaths), nil
+}
+
 func (impl *GitOperationServiceImpl) isRetryableGitCommitError(err error) bool {
 	if retryErr := (&retryFunc.RetryableError{}); errors.As(err, &retryErr) {
 		return true
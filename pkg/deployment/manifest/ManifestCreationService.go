@@ -231,7 +231,14 @@ func (impl *ManifestCreationServiceImpl) GetValuesOverrideForTrigger(overrideReq
 	valuesOverrideResponse.EnvOverride = envOverride
 
 	// Conditional Block based on PipelineOverrideCreated --> start
-	if !isPipelineOverrideCreated {
+	if !isPipelineOverrideCreated && overrideRequest.IsDryRun {
+		pipelineOverride, err = impl.getPipelineOverrideForDryRun(newCtx, overrideRequest, envOverride.Id, triggeredAt)
+		if err != nil {
+			return valuesOverrideResponse, err
+		}
+		pipelineOverride.Pipeline = pipeline
+		pipelineOverride.CiArtifact = artifact
+	} else if !isPipelineOverrideCreated {
 		pipelineOverride, err = impl.savePipelineOverride(newCtx, overrideRequest, envOverride.Id, triggeredAt)
 		if err != nil {
 			return valuesOverrideResponse, err
@@ -290,15 +297,17 @@ func (impl *ManifestCreationServiceImpl) GetValuesOverrideForTrigger(overrideReq
 			}
 		}
 		// handle image pull secret if access given
-		mergedValues, err = impl.dockerRegistryIpsConfigService.HandleImagePullSecretOnApplicationDeployment(newCtx, envOverride.Environment, artifact, pipeline.CiPipelineId, mergedValues)
+		mergedValues, err = impl.dockerRegistryIpsConfigService.HandleImagePullSecretOnApplicationDeployment(newCtx, envOverride.Environment, artifact, pipeline.CiPipelineId, mergedValues, overrideRequest.IsDryRun)
 		if err != nil {
 			return valuesOverrideResponse, err
 		}
 
 		valuesOverrideResponse.MergedValues = string(mergedValues)
-		err = impl.pipelineOverrideRepository.UpdatePipelineMergedValues(newCtx, nil, pipelineOverride.Id, string(mergedValues), overrideRequest.UserId)
-		if err != nil {
-			return valuesOverrideResponse, err
+		if !overrideRequest.IsDryRun {
+			err = impl.pipelineOverrideRepository.UpdatePipelineMergedValues(newCtx, nil, pipelineOverride.Id, string(mergedValues), overrideRequest.UserId)
+			if err != nil {
+				return valuesOverrideResponse, err
+			}
 		}
 		pipelineOverride.PipelineMergedValues = string(mergedValues)
 		valuesOverrideResponse.PipelineOverride = pipelineOverride
@@ -467,12 +476,15 @@ func (impl *ManifestCreationServiceImpl) getEnvOverrideForLastSavedConfigTrigger
 				IsBasicViewLocked: chart.IsBasicViewLocked,
 				CurrentViewEditor: chart.CurrentViewEditor,
 			}
-			_, span = otel.Tracer("orchestrator").Start(ctx, "environmentConfigRepository.Save")
-			err = impl.environmentConfigRepository.Save(envOverrideDBObj)
-			span.End()
-			if err != nil {
-				impl.logger.Errorw("error in creating envConfig", "data", envOverride, "error", err)
-				return nil, err
+			// for dry run, env override is only needed in memory for rendering the values
+			if !overrideRequest.IsDryRun {
+				_, span = otel.Tracer("orchestrator").Start(ctx, "environmentConfigRepository.Save")
+				err = impl.environmentConfigRepository.Save(envOverrideDBObj)
+				span.End()
+				if err != nil {
+					impl.logger.Errorw("error in creating envConfig", "data", envOverride, "error", err)
+					return nil, err
+				}
 			}
 			envOverride = adapter.EnvOverrideDBToDTO(envOverrideDBObj)
 		}
@@ -791,6 +803,26 @@ func (impl *ManifestCreationServiceImpl) savePipelineOverride(ctx context.Contex
 	return po, nil
 }
 
+// getPipelineOverrideForDryRun builds the pipeline override that would be saved on trigger, without persisting it
+func (impl *ManifestCreationServiceImpl) getPipelineOverrideForDryRun(ctx context.Context, overrideRequest *bean.ValuesOverrideRequest, envOverrideId int, triggeredAt time.Time) (*chartConfig.PipelineOverride, error) {
+	_, span := otel.Tracer("orchestrator").Start(ctx, "ManifestCreationServiceImpl.getPipelineOverrideForDryRun")
+	defer span.End()
+	currentReleaseNo, err := impl.pipelineOverrideRepository.GetCurrentPipelineReleaseCounter(overrideRequest.PipelineId)
+	if err != nil {
+		return nil, err
+	}
+	po := &chartConfig.PipelineOverride{
+		EnvConfigOverrideId:    envOverrideId,
+		Status:                 models.CHARTSTATUS_NEW,
+		PipelineId:             overrideRequest.PipelineId,
+		CiArtifactId:           overrideRequest.CiArtifactId,
+		PipelineReleaseCounter: currentReleaseNo + 1,
+		AuditLog:               sql.AuditLog{CreatedBy: overrideRequest.UserId, CreatedOn: triggeredAt, UpdatedOn: triggeredAt, UpdatedBy: overrideRequest.UserId},
+		DeploymentType:         overrideRequest.DeploymentType,
+	}
+	return po, nil
+}
+
 func (impl *ManifestCreationServiceImpl) checkAndFixDuplicateReleaseNo(override *chartConfig.PipelineOverride) error {
 
 	uniqueVerified := false
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devtronApps
+
+import (
+	"context"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval"
+	approvalBean "github.com/devtron-labs/devtron/pkg/deploymentApproval/bean"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// ApprovedDeploymentTriggerService deploys approved requests of protected environments, approvals are checked on trigger
+// so a deployment held for approval proceeds without being triggered again
+type ApprovedDeploymentTriggerService interface {
+	// TriggerApproved deploys an approved request on behalf of its requester, the request is marked failed if the trigger fails
+	TriggerApproved(requestId int) error
+	TriggerAllApproved()
+}
+
+type ApprovedDeploymentTriggerServiceImpl struct {
+	logger                    *zap.SugaredLogger
+	triggerService            TriggerService
+	deploymentApprovalService deploymentApproval.DeploymentApprovalService
+}
+
+func NewApprovedDeploymentTriggerServiceImpl(logger *zap.SugaredLogger,
+	triggerService TriggerService,
+	deploymentApprovalService deploymentApproval.DeploymentApprovalService,
+	cronLogger *cron2.CronLoggerImpl) (*ApprovedDeploymentTriggerServiceImpl, error) {
+	config := &approvalBean.DeploymentApprovalConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing deployment approval config", "err", err)
+		return nil, err
+	}
+	impl := &ApprovedDeploymentTriggerServiceImpl{
+		logger:                    logger,
+		triggerService:            triggerService,
+		deploymentApprovalService: deploymentApprovalService,
+	}
+	// approved requests are triggered right after the approval, the cron picks up those missed by a restart
+	triggerCron := cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	_, err = triggerCron.AddFunc(config.CronSchedule, impl.TriggerAllApproved)
+	if err != nil {
+		logger.Errorw("error in adding approved deployment trigger cron", "schedule", config.CronSchedule, "err", err)
+		return nil, err
+	}
+	triggerCron.Start()
+	return impl, nil
+}
+
+func (impl *ApprovedDeploymentTriggerServiceImpl) TriggerApproved(requestId int) error {
+	overrideRequest, err := impl.deploymentApprovalService.GetOverrideRequest(requestId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching trigger request of approved deployment", "requestId", requestId, "err", err)
+		return err
+	}
+	triggerContext := bean.TriggerContext{
+		Context:                     context.Background(),
+		DeploymentApprovalRequestId: requestId,
+	}
+	_, _, _, err = impl.triggerService.ManualCdTrigger(triggerContext, overrideRequest)
+	if err != nil {
+		impl.logger.Errorw("error in triggering approved deployment", "requestId", requestId, "pipelineId", overrideRequest.PipelineId, "err", err)
+		impl.deploymentApprovalService.MarkFailed(requestId, err.Error())
+		return err
+	}
+	return nil
+}
+
+func (impl *ApprovedDeploymentTriggerServiceImpl) TriggerAllApproved() {
+	requestIds, err := impl.deploymentApprovalService.GetApprovedRequestIds()
+	if err != nil {
+		return
+	}
+	for _, requestId := range requestIds {
+		_ = impl.TriggerApproved(requestId)
+	}
+}
@@ -65,6 +65,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/helper"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/userDeploymentRequest/service"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval"
 	clientErrors "github.com/devtron-labs/devtron/pkg/errors"
 	"github.com/devtron-labs/devtron/pkg/eventProcessor/out"
 	"github.com/devtron-labs/devtron/pkg/imageDigestPolicy"
@@ -172,6 +173,7 @@ type TriggerServiceImpl struct {
 	gitOperationService                 git.GitOperationService
 	attributeService                    attributes.AttributesService
 	clusterRepository                   repository5.ClusterRepository
+	deploymentApprovalService           deploymentApproval.DeploymentApprovalService
 }
 
 func NewTriggerServiceImpl(logger *zap.SugaredLogger,
@@ -230,6 +232,7 @@ func NewTriggerServiceImpl(logger *zap.SugaredLogger,
 	gitOperationService git.GitOperationService,
 	attributeService attributes.AttributesService,
 	clusterRepository repository5.ClusterRepository,
+	deploymentApprovalService deploymentApproval.DeploymentApprovalService,
 ) (*TriggerServiceImpl, error) {
 	impl := &TriggerServiceImpl{
 		logger:                              logger,
@@ -293,7 +296,8 @@ func NewTriggerServiceImpl(logger *zap.SugaredLogger,
 		gitOperationService:         gitOperationService,
 		attributeService:            attributeService,
 
-		clusterRepository: clusterRepository,
+		clusterRepository:         clusterRepository,
+		deploymentApprovalService: deploymentApprovalService,
 	}
 	config, err := types.GetCdConfig()
 	if err != nil {
@@ -484,6 +488,21 @@ func (impl *TriggerServiceImpl) ManualCdTrigger(triggerContext bean.TriggerConte
 		if overrideRequest.DeploymentType == models.DEPLOYMENTTYPE_UNKNOWN {
 			overrideRequest.DeploymentType = models.DEPLOYMENTTYPE_DEPLOY
 		}
+		if isNotHibernateRequest(overrideRequest.DeploymentType) {
+			feasibilityErr := impl.CheckFeasibility(&bean.TriggerRequirementRequestDto{
+				TriggerRequest: bean.TriggerRequest{
+					Pipeline:       cdPipeline,
+					Artifact:       artifact,
+					TriggeredBy:    overrideRequest.UserId,
+					TriggerContext: triggerContext,
+				},
+				OverrideRequest: overrideRequest,
+			})
+			if feasibilityErr != nil {
+				impl.logger.Errorw("deployment is not feasible, ManualCdTrigger", "pipelineId", cdPipeline.Id, "ciArtifactId", artifact.Id, "err", feasibilityErr)
+				return 0, "", nil, feasibilityErr
+			}
+		}
 
 		cdWf, err := impl.cdWorkflowRepository.FindByWorkflowIdAndRunnerType(ctx, overrideRequest.CdWorkflowId, bean3.CD_WORKFLOW_TYPE_PRE)
 		if err != nil && !util.IsErrNoRows(err) {
@@ -624,6 +643,24 @@ func (impl *TriggerServiceImpl) TriggerAutomaticDeployment(request bean.TriggerR
 	cdWf := request.CdWf
 	ctx := context.Background()
 
+	// auto triggers of a protected environment wait for approval, the approved request is deployed as a manual trigger
+	feasibilityErr := impl.CheckFeasibility(&bean.TriggerRequirementRequestDto{
+		TriggerRequest: request,
+		OverrideRequest: &bean3.ValuesOverrideRequest{
+			PipelineId:           pipeline.Id,
+			AppId:                pipeline.AppId,
+			CiArtifactId:         artifact.Id,
+			CdWorkflowType:       bean3.CD_WORKFLOW_TYPE_DEPLOY,
+			DeploymentType:       models.DEPLOYMENTTYPE_DEPLOY,
+			DeploymentWithConfig: bean3.DEPLOYMENT_CONFIG_TYPE_LAST_SAVED,
+			UserId:               triggeredBy,
+		},
+	})
+	if feasibilityErr != nil {
+		impl.logger.Errorw("deployment is not feasible, TriggerAutomaticDeployment", "pipelineId", pipeline.Id, "ciArtifactId", artifact.Id, "err", feasibilityErr)
+		return feasibilityErr
+	}
+
 	if cdWf == nil || (cdWf != nil && cdWf.CiArtifactId != artifact.Id) {
 		// cdWf != nil && cdWf.CiArtifactId != artifact.Id for auto trigger case when deployment is triggered with image generated by plugin
 		cdWf = &pipelineConfig.CdWorkflow{
@@ -63,6 +63,10 @@ type TriggerContext struct {
 
 	// manual or automatic
 	TriggerType TriggerType
+
+	// DeploymentApprovalRequestId is set when an approved deployment is triggered, the approval check consumes that request only
+	// +optional
+	DeploymentApprovalRequestId int
 }
 
 type TriggerType int
@@ -82,7 +86,8 @@ const (
 )
 
 type TriggerRequirementRequestDto struct {
-	TriggerRequest TriggerRequest
+	TriggerRequest  TriggerRequest
+	OverrideRequest *bean.ValuesOverrideRequest
 }
 
 type VulnerabilityCheckRequest struct {
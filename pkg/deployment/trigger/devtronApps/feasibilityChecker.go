@@ -18,13 +18,22 @@ package devtronApps
 
 import (
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
+	approvalBean "github.com/devtron-labs/devtron/pkg/deploymentApproval/bean"
 )
 
 type FeasibilityManager interface {
 	CheckFeasibility(triggerRequirementRequest *bean.TriggerRequirementRequestDto) error
 }
 
+// CheckFeasibility checks the deployment against the protection rule of its environment, deployments to a protected
+// environment need an approved request for the artifact and configuration. Security vulnerabilities are checked on trigger validation.
 func (impl *TriggerServiceImpl) CheckFeasibility(triggerRequirementRequest *bean.TriggerRequirementRequestDto) error {
-	// have not implemented right now, will be implemented in future for security vulnerability
-	return nil
+	triggerRequest := triggerRequirementRequest.TriggerRequest
+	return impl.deploymentApprovalService.CheckApproval(&approvalBean.ApprovalCheckRequest{
+		Pipeline:          triggerRequest.Pipeline,
+		CiArtifactId:      triggerRequest.Artifact.Id,
+		OverrideRequest:   triggerRequirementRequest.OverrideRequest,
+		TriggeredBy:       triggerRequest.TriggeredBy,
+		ApprovalRequestId: triggerRequest.TriggerContext.DeploymentApprovalRequestId,
+	})
 }
@@ -25,4 +25,6 @@ var DevtronAppsDeployTriggerWireSet = wire.NewSet(
 	userDeploymentRequest.WireSet,
 	NewTriggerServiceImpl,
 	wire.Bind(new(TriggerService), new(*TriggerServiceImpl)),
+	NewApprovedDeploymentTriggerServiceImpl,
+	wire.Bind(new(ApprovedDeploymentTriggerService), new(*ApprovedDeploymentTriggerServiceImpl)),
 )
@@ -18,6 +18,7 @@ package deployment
 
 import (
 	"github.com/devtron-labs/devtron/pkg/deployment/deployedApp"
+	"github.com/devtron-labs/devtron/pkg/deployment/dryRun"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest"
 	"github.com/devtron-labs/devtron/pkg/deployment/providerConfig"
@@ -33,4 +34,5 @@ var DeploymentWireSet = wire.NewSet(
 	trigger.DeploymentTriggerWireSet,
 	deployedApp.DeployedAppWireSet,
 	providerConfig.DeploymentProviderConfigWireSet,
+	dryRun.DryRunWireSet,
 )
@@ -0,0 +1,789 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentApproval
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	client "github.com/devtron-labs/devtron/client/events"
+	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	envRepository "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval/bean"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+type DeploymentApprovalService interface {
+	GetAllRules() ([]*bean.ProtectionRuleDto, error)
+	GetRule(envId int) (*bean.ProtectionRuleDto, error)
+	// SaveRule creates or replaces the protection rule of an environment, open requests keep the rule they were created with
+	SaveRule(rule *bean.ProtectionRuleDto) (*bean.ProtectionRuleDto, error)
+	DeleteRule(envId int, userId int32) error
+
+	// CheckApproval returns nil if the deployment can go ahead. A deployment to a protected environment consumes an
+	// approved request for its artifact and configuration, else an approval request is created and the approvers are notified
+	CheckApproval(request *bean.ApprovalCheckRequest) error
+
+	GetAll(filter *bean.ListFilter) ([]*bean.ApprovalRequestDto, error)
+	GetById(id int) (*bean.ApprovalRequestDto, error)
+	// Approve adds the approval of the user, the request is approved once it has the required approvals
+	Approve(request *bean.ActionRequest) (*bean.ApprovalRequestDto, error)
+	Reject(request *bean.ActionRequest) (*bean.ApprovalRequestDto, error)
+	// Cancel withdraws a request which is not deployed yet, only the requester can cancel it
+	Cancel(request *bean.ActionRequest) (*bean.ApprovalRequestDto, error)
+
+	// GetApprovedRequestIds returns the approved requests waiting for their deployment
+	GetApprovedRequestIds() ([]int, error)
+	// GetOverrideRequest returns the trigger request an approved request is deployed with, on behalf of its requester
+	GetOverrideRequest(id int) (*apiBean.ValuesOverrideRequest, error)
+	// MarkFailed closes an approved request whose deployment could not be triggered
+	MarkFailed(id int, reason string)
+	ExpireRequests()
+}
+
+type DeploymentApprovalServiceImpl struct {
+	logger                      *zap.SugaredLogger
+	ruleRepository              repository.EnvironmentProtectionRuleRepository
+	requestRepository           repository.DeploymentApprovalRequestRepository
+	actionRepository            repository.DeploymentApprovalActionRepository
+	environmentRepository       envRepository.EnvironmentRepository
+	roleGroupRepository         userRepository.RoleGroupRepository
+	userService                 user.UserService
+	chartRepository             chartRepoRepository.ChartRepository
+	envConfigOverrideRepository chartConfig.EnvConfigOverrideRepository
+	configMapRepository         chartConfig.ConfigMapRepository
+	pipelineConfigRepository    chartConfig.PipelineConfigRepository
+	ciArtifactRepository        repository2.CiArtifactRepository
+	eventFactory                client.EventFactory
+	eventClient                 client.EventClient
+	config                      *bean.DeploymentApprovalConfig
+}
+
+func NewDeploymentApprovalServiceImpl(logger *zap.SugaredLogger,
+	ruleRepository repository.EnvironmentProtectionRuleRepository,
+	requestRepository repository.DeploymentApprovalRequestRepository,
+	actionRepository repository.DeploymentApprovalActionRepository,
+	environmentRepository envRepository.EnvironmentRepository,
+	roleGroupRepository userRepository.RoleGroupRepository,
+	userService user.UserService,
+	chartRepository chartRepoRepository.ChartRepository,
+	envConfigOverrideRepository chartConfig.EnvConfigOverrideRepository,
+	configMapRepository chartConfig.ConfigMapRepository,
+	pipelineConfigRepository chartConfig.PipelineConfigRepository,
+	ciArtifactRepository repository2.CiArtifactRepository,
+	eventFactory client.EventFactory,
+	eventClient client.EventClient,
+	cronLogger *cron2.CronLoggerImpl) (*DeploymentApprovalServiceImpl, error) {
+	config := &bean.DeploymentApprovalConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing deployment approval config", "err", err)
+		return nil, err
+	}
+	impl := &DeploymentApprovalServiceImpl{
+		logger:                      logger,
+		ruleRepository:              ruleRepository,
+		requestRepository:           requestRepository,
+		actionRepository:            actionRepository,
+		environmentRepository:       environmentRepository,
+		roleGroupRepository:         roleGroupRepository,
+		userService:                 userService,
+		chartRepository:             chartRepository,
+		envConfigOverrideRepository: envConfigOverrideRepository,
+		configMapRepository:         configMapRepository,
+		pipelineConfigRepository:    pipelineConfigRepository,
+		ciArtifactRepository:        ciArtifactRepository,
+		eventFactory:                eventFactory,
+		eventClient:                 eventClient,
+		config:                      config,
+	}
+	expiryCron := cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	_, err = expiryCron.AddFunc(config.CronSchedule, impl.ExpireRequests)
+	if err != nil {
+		logger.Errorw("error in adding deployment approval expiry cron", "schedule", config.CronSchedule, "err", err)
+		return nil, err
+	}
+	expiryCron.Start()
+	return impl, nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) GetAllRules() ([]*bean.ProtectionRuleDto, error) {
+	models, err := impl.ruleRepository.FindAllActive()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching environment protection rules", "err", err)
+		return nil, err
+	}
+	res := make([]*bean.ProtectionRuleDto, 0, len(models))
+	for _, model := range models {
+		env, err := impl.environmentRepository.FindById(model.EnvironmentId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching environment", "envId", model.EnvironmentId, "err", err)
+			return nil, err
+		}
+		envName := ""
+		if env != nil {
+			envName = env.Name
+		}
+		res = append(res, toRuleDto(model, envName))
+	}
+	return res, nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) GetRule(envId int) (*bean.ProtectionRuleDto, error) {
+	env, err := impl.getEnvironment(envId)
+	if err != nil {
+		return nil, err
+	}
+	model, err := impl.getRule(envId)
+	if err != nil {
+		return nil, err
+	}
+	return toRuleDto(model, env.Name), nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) SaveRule(rule *bean.ProtectionRuleDto) (*bean.ProtectionRuleDto, error) {
+	err := validateRule(rule)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	env, err := impl.getEnvironment(rule.EnvironmentId)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := impl.roleGroupRepository.GetRoleGroupListByNames(rule.ApproverRoleGroups)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching role groups", "names", rule.ApproverRoleGroups, "err", err)
+		return nil, err
+	}
+	missing := slices.Clone(rule.ApproverRoleGroups)
+	for _, group := range groups {
+		missing = slices.DeleteFunc(missing, func(name string) bool { return name == group.Name })
+	}
+	if len(missing) > 0 {
+		errMsg := fmt.Sprintf("role groups %s not found", strings.Join(missing, ", "))
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	model, err := impl.ruleRepository.FindByEnvironmentId(rule.EnvironmentId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching environment protection rule", "envId", rule.EnvironmentId, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) {
+		model = &repository.EnvironmentProtectionRule{AuditLog: sql.NewDefaultAuditLog(rule.UserId)}
+		toRuleModel(rule, model)
+		err = impl.ruleRepository.Save(model)
+	} else {
+		toRuleModel(rule, model)
+		model.UpdateAuditLog(rule.UserId)
+		err = impl.ruleRepository.Update(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving environment protection rule", "envId", rule.EnvironmentId, "err", err)
+		return nil, err
+	}
+	return toRuleDto(model, env.Name), nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) DeleteRule(envId int, userId int32) error {
+	model, err := impl.getRule(envId)
+	if err != nil {
+		return err
+	}
+	model.Active = false
+	model.UpdateAuditLog(userId)
+	err = impl.ruleRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in deleting environment protection rule", "envId", envId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) CheckApproval(request *bean.ApprovalCheckRequest) error {
+	pipeline := request.Pipeline
+	rule, err := impl.ruleRepository.FindByEnvironmentId(pipeline.EnvironmentId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching environment protection rule", "envId", pipeline.EnvironmentId, "err", err)
+		return err
+	}
+	if util.IsErrNoRows(err) {
+		return nil
+	}
+	snapshotHash, err := impl.getConfigSnapshotHash(pipeline, request.OverrideRequest)
+	if err != nil {
+		impl.logger.Errorw("error in computing config snapshot of deployment", "pipelineId", pipeline.Id, "err", err)
+		return err
+	}
+	if request.ApprovalRequestId > 0 {
+		return impl.consumeApprovedRequest(rule, request, snapshotHash)
+	}
+	model, err := impl.requestRepository.FindOpen(pipeline.Id, request.CiArtifactId, snapshotHash)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching open approval request", "pipelineId", pipeline.Id, "ciArtifactId", request.CiArtifactId, "err", err)
+		return err
+	}
+	if err == nil {
+		if model.Status == string(bean.RequestStatusApproved) {
+			return impl.consume(model, request.TriggeredBy)
+		}
+		actions, err := impl.actionRepository.FindByRequestId(model.Id)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching approval request actions", "requestId", model.Id, "err", err)
+			return err
+		}
+		return newApprovalRequiredError(model, len(getApproverIds(actions)))
+	}
+	model, err = impl.createRequest(rule, request, snapshotHash)
+	if err != nil {
+		return err
+	}
+	return newApprovalRequiredError(model, 0)
+}
+
+// consumeApprovedRequest deploys the given approved request, a new request is created if the configuration changed after the approval
+func (impl *DeploymentApprovalServiceImpl) consumeApprovedRequest(rule *repository.EnvironmentProtectionRule, request *bean.ApprovalCheckRequest, snapshotHash string) error {
+	model, err := impl.getRequestInStatus(request.ApprovalRequestId, bean.RequestStatusApproved)
+	if err != nil {
+		return err
+	}
+	if model.PipelineId != request.Pipeline.Id || model.CiArtifactId != request.CiArtifactId {
+		errMsg := fmt.Sprintf("approval request %d is not for this pipeline and artifact", model.Id)
+		return util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	if model.ConfigSnapshotHash != snapshotHash {
+		reason := "configuration changed after the approval"
+		impl.markFailed(model.Id, reason)
+		newModel, err := impl.createRequest(rule, request, snapshotHash)
+		if err != nil {
+			return err
+		}
+		return newApprovalRequiredError(newModel, 0)
+	}
+	return impl.consume(model, request.TriggeredBy)
+}
+
+// consume marks an approved request deployed, a request is deployed once even if triggered concurrently
+func (impl *DeploymentApprovalServiceImpl) consume(model *repository.DeploymentApprovalRequest, userId int32) error {
+	if model.ExpiresOn.Before(time.Now()) {
+		errMsg := fmt.Sprintf("approval request %d has expired", model.Id)
+		return util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	updated, err := impl.requestRepository.UpdateStatus(model.Id, bean.RequestStatusApproved, bean.RequestStatusDeployed, "", userId)
+	if err != nil {
+		impl.logger.Errorw("error in marking approval request deployed", "requestId", model.Id, "err", err)
+		return err
+	}
+	if !updated {
+		errMsg := fmt.Sprintf("approval request %d is not approved anymore", model.Id)
+		return util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	impl.saveAction(model.Id, bean.ActionDeployed, "", userId)
+	return nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) createRequest(rule *repository.EnvironmentProtectionRule, request *bean.ApprovalCheckRequest, snapshotHash string) (*repository.DeploymentApprovalRequest, error) {
+	overrideRequest, err := getOverrideRequestJson(request.OverrideRequest)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	model := &repository.DeploymentApprovalRequest{
+		PipelineId:         request.Pipeline.Id,
+		AppId:              request.Pipeline.AppId,
+		EnvId:              request.Pipeline.EnvironmentId,
+		CiArtifactId:       request.CiArtifactId,
+		ConfigSnapshotHash: snapshotHash,
+		OverrideRequest:    overrideRequest,
+		Status:             string(bean.RequestStatusPending),
+		RequiredApprovals:  rule.RequiredApprovals,
+		ApproverRoleGroups: rule.ApproverRoleGroups,
+		RequestedBy:        request.TriggeredBy,
+		ExpiresOn:          getExpiresOn(now, rule.RequestTtlMinutes, impl.config.DefaultRequestTtlMinutes),
+		AuditLog:           sql.NewDefaultAuditLog(request.TriggeredBy),
+	}
+	err = impl.requestRepository.Save(model)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment approval request", "pipelineId", model.PipelineId, "err", err)
+		return nil, err
+	}
+	impl.saveAction(model.Id, bean.ActionRequested, "", request.TriggeredBy)
+	go impl.notifyApprovers(model)
+	return model, nil
+}
+
+// getConfigSnapshotHash hashes the configuration the deployment would use, saved configuration is read from the
+// deployment template, config maps, secrets and strategy of the app and environment
+func (impl *DeploymentApprovalServiceImpl) getConfigSnapshotHash(pipeline *pipelineConfig.Pipeline, overrideRequest *apiBean.ValuesOverrideRequest) (string, error) {
+	snapshot := &configSnapshot{
+		DeploymentWithConfig: overrideRequest.DeploymentWithConfig,
+		SpecificTriggerWfrId: overrideRequest.WfrIdForDeploymentWithSpecificTrigger,
+		AdditionalOverride:   string(overrideRequest.AdditionalOverride),
+		Strategy:             overrideRequest.DeploymentTemplate,
+	}
+	if !isSavedConfigDeployment(overrideRequest.DeploymentWithConfig) {
+		return hashConfigSnapshot(snapshot)
+	}
+	chart, err := impl.chartRepository.FindLatestChartForAppByAppId(pipeline.AppId)
+	if err != nil && !util.IsErrNoRows(err) {
+		return "", err
+	}
+	if chart != nil {
+		snapshot.ChartRefId = chart.ChartRefId
+		snapshot.DeploymentTemplate = chart.GlobalOverride
+	}
+	envOverride, err := impl.envConfigOverrideRepository.ActiveEnvConfigOverride(pipeline.AppId, pipeline.EnvironmentId)
+	if err != nil && !util.IsErrNoRows(err) {
+		return "", err
+	}
+	if envOverride != nil && envOverride.IsOverride {
+		snapshot.EnvDeploymentTemplate = envOverride.EnvOverrideValues
+		if envOverride.Chart != nil {
+			snapshot.ChartRefId = envOverride.Chart.ChartRefId
+		}
+	}
+	appConfig, err := impl.configMapRepository.GetByAppIdAppLevel(pipeline.AppId)
+	if err != nil && !util.IsErrNoRows(err) {
+		return "", err
+	}
+	if appConfig != nil {
+		snapshot.ConfigMaps = appConfig.ConfigMapData
+		snapshot.Secrets = appConfig.SecretData
+	}
+	envConfig, err := impl.configMapRepository.GetByAppIdAndEnvIdEnvLevel(pipeline.AppId, pipeline.EnvironmentId)
+	if err != nil && !util.IsErrNoRows(err) {
+		return "", err
+	}
+	if envConfig != nil {
+		snapshot.EnvConfigMaps = envConfig.ConfigMapData
+		snapshot.EnvSecrets = envConfig.SecretData
+	}
+	strategy, err := impl.pipelineConfigRepository.GetDefaultStrategyByPipelineId(pipeline.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		return "", err
+	}
+	if strategy != nil {
+		snapshot.StrategyConfig = strategy.Config
+	}
+	return hashConfigSnapshot(snapshot)
+}
+
+func (impl *DeploymentApprovalServiceImpl) GetAll(filter *bean.ListFilter) ([]*bean.ApprovalRequestDto, error) {
+	models, err := impl.requestRepository.FindAll(filter)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment approval requests", "filter", filter, "err", err)
+		return nil, err
+	}
+	emailIds := make(map[int32]string)
+	res := make([]*bean.ApprovalRequestDto, 0, len(models))
+	for _, model := range models {
+		requestedBy, err := impl.getEmailId(model.RequestedBy, emailIds)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, toRequestDto(model, requestedBy, nil))
+	}
+	return res, nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) GetById(id int) (*bean.ApprovalRequestDto, error) {
+	model, err := impl.getRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	return impl.toDto(model)
+}
+
+func (impl *DeploymentApprovalServiceImpl) Approve(request *bean.ActionRequest) (*bean.ApprovalRequestDto, error) {
+	tx, err := impl.requestRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "requestId", request.Id, "err", err)
+		return nil, err
+	}
+	defer impl.requestRepository.RollbackTx(tx)
+	// the request stays locked until the approval is committed, so that concurrent approvals are counted
+	// one after the other and the request is approved exactly when the last required approval is saved
+	model, err := impl.requestRepository.FindByIdForUpdate(request.Id, tx)
+	if err != nil {
+		return nil, impl.handleRequestFetchError(request.Id, err)
+	}
+	err = checkRequestStatus(model, bean.RequestStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	if model.RequestedBy == request.UserId {
+		errMsg := "a deployment can't be approved by its requester"
+		return nil, util.NewApiError(http.StatusForbidden, errMsg, errMsg)
+	}
+	err = impl.checkApprover(model, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := impl.actionRepository.FindByRequestIdWithTxn(model.Id, tx)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching approval request actions", "requestId", model.Id, "err", err)
+		return nil, err
+	}
+	if slices.Contains(getApproverIds(actions), request.UserId) {
+		errMsg := fmt.Sprintf("approval request %d is already approved by the user", model.Id)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	action := &repository.DeploymentApprovalAction{
+		DeploymentApprovalRequestId: model.Id,
+		Action:                      string(bean.ActionApproved),
+		Comment:                     request.Comment,
+		AuditLog:                    sql.NewDefaultAuditLog(request.UserId),
+	}
+	err = impl.actionRepository.SaveWithTxn(action, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving approval", "requestId", model.Id, "err", err)
+		return nil, err
+	}
+	if len(getApproverIds(append(actions, action))) >= model.RequiredApprovals {
+		model.Status = string(bean.RequestStatusApproved)
+		model.UpdateAuditLog(request.UserId)
+		err = impl.requestRepository.UpdateWithTxn(model, tx)
+		if err != nil {
+			impl.logger.Errorw("error in updating approved deployment approval request", "requestId", model.Id, "err", err)
+			return nil, err
+		}
+	}
+	err = impl.requestRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing approval", "requestId", model.Id, "err", err)
+		return nil, err
+	}
+	return impl.toDto(model)
+}
+
+func (impl *DeploymentApprovalServiceImpl) Reject(request *bean.ActionRequest) (*bean.ApprovalRequestDto, error) {
+	model, err := impl.getRequestInStatus(request.Id, bean.RequestStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	if model.RequestedBy == request.UserId {
+		errMsg := "a deployment can't be rejected by its requester, cancel it instead"
+		return nil, util.NewApiError(http.StatusForbidden, errMsg, errMsg)
+	}
+	err = impl.checkApprover(model, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.close(model, bean.RequestStatusRejected, bean.ActionRejected, request.Comment, request.UserId)
+}
+
+func (impl *DeploymentApprovalServiceImpl) Cancel(request *bean.ActionRequest) (*bean.ApprovalRequestDto, error) {
+	model, err := impl.getRequest(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if model.Status != string(bean.RequestStatusPending) && model.Status != string(bean.RequestStatusApproved) {
+		errMsg := fmt.Sprintf("approval request %d is %s and can't be cancelled", model.Id, model.Status)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	if model.RequestedBy != request.UserId {
+		errMsg := "only the requester can cancel a request"
+		return nil, util.NewApiError(http.StatusForbidden, errMsg, errMsg)
+	}
+	return impl.close(model, bean.RequestStatusCancelled, bean.ActionCancelled, request.Comment, request.UserId)
+}
+
+func (impl *DeploymentApprovalServiceImpl) GetApprovedRequestIds() ([]int, error) {
+	models, err := impl.requestRepository.FindByStatus(bean.RequestStatusApproved)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching approved deployment approval requests", "err", err)
+		return nil, err
+	}
+	now := time.Now()
+	ids := make([]int, 0, len(models))
+	for _, model := range models {
+		if model.ExpiresOn.After(now) {
+			ids = append(ids, model.Id)
+		}
+	}
+	return ids, nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) GetOverrideRequest(id int) (*apiBean.ValuesOverrideRequest, error) {
+	model, err := impl.getRequestInStatus(id, bean.RequestStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+	overrideRequest := &apiBean.ValuesOverrideRequest{}
+	err = json.Unmarshal([]byte(model.OverrideRequest), overrideRequest)
+	if err != nil {
+		impl.logger.Errorw("error in decoding override request of approval request", "requestId", id, "err", err)
+		return nil, err
+	}
+	overrideRequest.UserId = model.RequestedBy
+	return overrideRequest, nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) MarkFailed(id int, reason string) {
+	impl.markFailed(id, reason)
+}
+
+// ExpireRequests closes pending and approved requests past their expiry, they have to be requested again
+func (impl *DeploymentApprovalServiceImpl) ExpireRequests() {
+	models, err := impl.requestRepository.FindOpenExpiredBefore(time.Now())
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching expired deployment approval requests", "err", err)
+		return
+	}
+	for _, model := range models {
+		updated, err := impl.requestRepository.UpdateStatus(model.Id, bean.RequestStatus(model.Status), bean.RequestStatusExpired, "", userBean.SystemUserId)
+		if err != nil {
+			impl.logger.Errorw("error in expiring deployment approval request", "requestId", model.Id, "err", err)
+			continue
+		}
+		if updated {
+			impl.saveAction(model.Id, bean.ActionExpired, "", userBean.SystemUserId)
+		}
+	}
+}
+
+func (impl *DeploymentApprovalServiceImpl) markFailed(id int, reason string) {
+	updated, err := impl.requestRepository.UpdateStatus(id, bean.RequestStatusApproved, bean.RequestStatusFailed, reason, userBean.SystemUserId)
+	if err != nil {
+		impl.logger.Errorw("error in marking deployment approval request failed", "requestId", id, "err", err)
+		return
+	}
+	if updated {
+		impl.saveAction(id, bean.ActionFailed, reason, userBean.SystemUserId)
+	}
+}
+
+// checkApprover checks the user belongs to one of the approver role groups of the request
+func (impl *DeploymentApprovalServiceImpl) checkApprover(model *repository.DeploymentApprovalRequest, userId int32) error {
+	userRoles, err := impl.userService.CheckUserRoles(userId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching roles of user", "userId", userId, "err", err)
+		return err
+	}
+	casbinNames, err := impl.getApproverGroupCasbinNames(model)
+	if err != nil {
+		return err
+	}
+	if !isApprover(userRoles, casbinNames) {
+		errMsg := fmt.Sprintf("only members of %s can approve or reject this request", strings.Join(model.ApproverRoleGroups, ", "))
+		return util.NewApiError(http.StatusForbidden, errMsg, errMsg)
+	}
+	return nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) getApproverGroupCasbinNames(model *repository.DeploymentApprovalRequest) ([]string, error) {
+	groups, err := impl.roleGroupRepository.GetRoleGroupListByNames(model.ApproverRoleGroups)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching approver role groups", "names", model.ApproverRoleGroups, "err", err)
+		return nil, err
+	}
+	casbinNames := make([]string, 0, len(groups))
+	for _, group := range groups {
+		casbinNames = append(casbinNames, group.CasbinName)
+	}
+	return casbinNames, nil
+}
+
+// notifyApprovers sends the approval notification to the members of the approver role groups, except the requester
+func (impl *DeploymentApprovalServiceImpl) notifyApprovers(model *repository.DeploymentApprovalRequest) {
+	requestedBy, err := impl.userService.GetEmailById(model.RequestedBy)
+	if err != nil {
+		impl.logger.Errorw("error in fetching email of requester", "requestId", model.Id, "err", err)
+		return
+	}
+	casbinNames, err := impl.getApproverGroupCasbinNames(model)
+	if err != nil {
+		return
+	}
+	recipients := make([]string, 0)
+	for _, casbinName := range casbinNames {
+		members, err := casbin.GetUserByRole(casbinName)
+		if err != nil {
+			impl.logger.Errorw("error in fetching members of role group", "casbinName", casbinName, "err", err)
+			continue
+		}
+		for _, member := range members {
+			if member != requestedBy && !slices.Contains(recipients, member) {
+				recipients = append(recipients, member)
+			}
+		}
+	}
+	if len(recipients) == 0 {
+		impl.logger.Warnw("no approver to notify for deployment approval request", "requestId", model.Id, "roleGroups", model.ApproverRoleGroups)
+		return
+	}
+	artifact, err := impl.ciArtifactRepository.Get(model.CiArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching artifact of approval request", "requestId", model.Id, "err", err)
+		return
+	}
+	event, err := impl.eventFactory.Build(util2.Approval, &model.PipelineId, model.AppId, &model.EnvId, util2.CD)
+	if err != nil {
+		impl.logger.Errorw("error in building approval event", "requestId", model.Id, "err", err)
+		return
+	}
+	event.CiArtifactId = model.CiArtifactId
+	event.UserId = int(model.RequestedBy)
+	event.Payload = &client.Payload{
+		TriggeredBy:        requestedBy,
+		DockerImageUrl:     artifact.Image,
+		ProvidedRecipients: recipients,
+		ImageApprovalLink:  fmt.Sprintf("/dashboard/app/%d/trigger", model.AppId),
+	}
+	_, err = impl.eventClient.WriteNotificationEvent(event)
+	if err != nil {
+		impl.logger.Errorw("error in sending approval notification", "requestId", model.Id, "err", err)
+	}
+}
+
+func (impl *DeploymentApprovalServiceImpl) close(model *repository.DeploymentApprovalRequest, status bean.RequestStatus, action bean.Action, comment string, userId int32) (*bean.ApprovalRequestDto, error) {
+	updated, err := impl.requestRepository.UpdateStatus(model.Id, bean.RequestStatus(model.Status), status, "", userId)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment approval request", "requestId", model.Id, "status", status, "err", err)
+		return nil, err
+	}
+	if !updated {
+		errMsg := fmt.Sprintf("approval request %d was updated meanwhile, retry", model.Id)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	impl.saveAction(model.Id, action, comment, userId)
+	return impl.GetById(model.Id)
+}
+
+func (impl *DeploymentApprovalServiceImpl) saveAction(requestId int, action bean.Action, comment string, userId int32) {
+	err := impl.actionRepository.Save(&repository.DeploymentApprovalAction{
+		DeploymentApprovalRequestId: requestId,
+		Action:                      string(action),
+		Comment:                     comment,
+		AuditLog:                    sql.NewDefaultAuditLog(userId),
+	})
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment approval action", "requestId", requestId, "action", action, "err", err)
+	}
+}
+
+func (impl *DeploymentApprovalServiceImpl) getEnvironment(envId int) (*envRepository.Environment, error) {
+	env, err := impl.environmentRepository.FindById(envId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching environment", "envId", envId, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) {
+		errMsg := fmt.Sprintf("environment %d not found", envId)
+		return nil, util.NewApiError(http.StatusNotFound, errMsg, errMsg)
+	}
+	return env, nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) getRule(envId int) (*repository.EnvironmentProtectionRule, error) {
+	model, err := impl.ruleRepository.FindByEnvironmentId(envId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching environment protection rule", "envId", envId, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) {
+		errMsg := fmt.Sprintf("environment %d has no protection rule", envId)
+		return nil, util.NewApiError(http.StatusNotFound, errMsg, errMsg)
+	}
+	return model, nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) getRequest(id int) (*repository.DeploymentApprovalRequest, error) {
+	model, err := impl.requestRepository.FindById(id)
+	if err != nil {
+		return nil, impl.handleRequestFetchError(id, err)
+	}
+	return model, nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) handleRequestFetchError(id int, err error) error {
+	if util.IsErrNoRows(err) {
+		errMsg := fmt.Sprintf("approval request %d not found", id)
+		return util.NewApiError(http.StatusNotFound, errMsg, errMsg)
+	}
+	impl.logger.Errorw("error in fetching deployment approval request", "id", id, "err", err)
+	return err
+}
+
+func (impl *DeploymentApprovalServiceImpl) getRequestInStatus(id int, status bean.RequestStatus) (*repository.DeploymentApprovalRequest, error) {
+	model, err := impl.getRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	err = checkRequestStatus(model, status)
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) getEmailId(userId int32, emailIds map[int32]string) (string, error) {
+	if emailId, ok := emailIds[userId]; ok {
+		return emailId, nil
+	}
+	emailId, err := impl.userService.GetEmailById(userId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching email of user", "userId", userId, "err", err)
+		return "", err
+	}
+	emailIds[userId] = emailId
+	return emailId, nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) toDto(model *repository.DeploymentApprovalRequest) (*bean.ApprovalRequestDto, error) {
+	emailIds := make(map[int32]string)
+	requestedBy, err := impl.getEmailId(model.RequestedBy, emailIds)
+	if err != nil {
+		return nil, err
+	}
+	actionModels, err := impl.actionRepository.FindByRequestId(model.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching approval request actions", "requestId", model.Id, "err", err)
+		return nil, err
+	}
+	actions := make([]*bean.ActionDto, 0, len(actionModels))
+	for _, actionModel := range actionModels {
+		actionBy, err := impl.getEmailId(actionModel.CreatedBy, emailIds)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, &bean.ActionDto{
+			Action:   bean.Action(actionModel.Action),
+			ActionBy: actionBy,
+			Comment:  actionModel.Comment,
+			ActionOn: actionModel.CreatedOn,
+		})
+	}
+	return toRequestDto(model, requestedBy, actions), nil
+}
@@ -0,0 +1,251 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentApproval
+
+import (
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/constants"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	userRepositoryMocks "github.com/devtron-labs/devtron/pkg/auth/user/repository/RepositoryMocks"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval/bean"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval/repository"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval/repository/mocks"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// userServiceStub returns the casbin roles of the approvers, other methods of the user service are not used by approvals
+type userServiceStub struct {
+	user.UserService
+	roles map[int32][]string
+}
+
+func (impl userServiceStub) CheckUserRoles(id int32) ([]string, error) {
+	return impl.roles[id], nil
+}
+
+func (impl userServiceStub) GetEmailById(userId int32) (string, error) {
+	return "user@devtron.ai", nil
+}
+
+func initDeploymentApprovalService(t *testing.T) (*DeploymentApprovalServiceImpl, *mocks.DeploymentApprovalRequestRepository, *mocks.DeploymentApprovalActionRepository) {
+	ruleRepository := mocks.NewEnvironmentProtectionRuleRepository(t)
+	ruleRepository.On("FindByEnvironmentId", 7).Return(&repository.EnvironmentProtectionRule{EnvironmentId: 7, RequiredApprovals: 2,
+		ApproverRoleGroups: []string{"approvers"}}, nil).Maybe()
+	ruleRepository.On("FindByEnvironmentId", mock.Anything).Return(nil, pg.ErrNoRows).Maybe()
+	requestRepository := mocks.NewDeploymentApprovalRequestRepository(t)
+	actionRepository := mocks.NewDeploymentApprovalActionRepository(t)
+	roleGroupRepository := userRepositoryMocks.NewRoleGroupRepository(t)
+	roleGroupRepository.On("GetRoleGroupListByNames", []string{"approvers"}).
+		Return([]*userRepository.RoleGroup{{Name: "approvers", CasbinName: "group:approvers"}}, nil).Maybe()
+	service := &DeploymentApprovalServiceImpl{
+		logger:              zap.NewNop().Sugar(),
+		ruleRepository:      ruleRepository,
+		requestRepository:   requestRepository,
+		actionRepository:    actionRepository,
+		roleGroupRepository: roleGroupRepository,
+		userService: userServiceStub{roles: map[int32][]string{
+			3: {"group:approvers"},
+			4: {"group:approvers"},
+			5: {"group:viewers"},
+		}},
+	}
+	return service, requestRepository, actionRepository
+}
+
+func expectApprovalTransaction(requestRepository *mocks.DeploymentApprovalRequestRepository) {
+	tx := &pg.Tx{}
+	requestRepository.On("StartTx").Return(tx, nil)
+	requestRepository.On("RollbackTx", tx).Return(nil)
+}
+
+func pendingRequest() *repository.DeploymentApprovalRequest {
+	return &repository.DeploymentApprovalRequest{Id: 1, Status: string(bean.RequestStatusPending), RequiredApprovals: 2,
+		ApproverRoleGroups: []string{"approvers"}, RequestedBy: 2}
+}
+
+func approvalBy(userId int32) *repository.DeploymentApprovalAction {
+	return &repository.DeploymentApprovalAction{DeploymentApprovalRequestId: 1, Action: string(bean.ActionApproved), AuditLog: sql.NewDefaultAuditLog(userId)}
+}
+
+func assertApiErrorStatus(t *testing.T, err error, status int) {
+	apiErr, ok := err.(*util.ApiError)
+	if assert.True(t, ok, "expected an api error, got %v", err) {
+		assert.Equal(t, status, apiErr.HttpStatusCode)
+	}
+}
+
+func TestApprove(t *testing.T) {
+	t.Run("approval below the threshold keeps the request pending", func(tt *testing.T) {
+		service, requestRepository, actionRepository := initDeploymentApprovalService(tt)
+		expectApprovalTransaction(requestRepository)
+		requestRepository.On("FindByIdForUpdate", 1, mock.Anything).Return(pendingRequest(), nil)
+		actionRepository.On("FindByRequestIdWithTxn", 1, mock.Anything).Return(nil, pg.ErrNoRows)
+		actionRepository.On("SaveWithTxn", mock.Anything, mock.Anything).Return(nil)
+		requestRepository.On("CommitTx", mock.Anything).Return(nil)
+		actionRepository.On("FindByRequestId", 1).Return([]*repository.DeploymentApprovalAction{approvalBy(3)}, nil)
+
+		dto, err := service.Approve(&bean.ActionRequest{Id: 1, UserId: 3})
+		assert.Nil(tt, err)
+		assert.Equal(tt, bean.RequestStatusPending, dto.Status)
+		requestRepository.AssertNotCalled(tt, "UpdateWithTxn", mock.Anything, mock.Anything)
+	})
+
+	t.Run("last required approval approves the request in the same transaction", func(tt *testing.T) {
+		service, requestRepository, actionRepository := initDeploymentApprovalService(tt)
+		expectApprovalTransaction(requestRepository)
+		requestRepository.On("FindByIdForUpdate", 1, mock.Anything).Return(pendingRequest(), nil)
+		actionRepository.On("FindByRequestIdWithTxn", 1, mock.Anything).Return([]*repository.DeploymentApprovalAction{approvalBy(3)}, nil)
+		actionRepository.On("SaveWithTxn", mock.MatchedBy(func(action *repository.DeploymentApprovalAction) bool {
+			return action.CreatedBy == 4 && action.Action == string(bean.ActionApproved)
+		}), mock.Anything).Return(nil)
+		requestRepository.On("UpdateWithTxn", mock.MatchedBy(func(model *repository.DeploymentApprovalRequest) bool {
+			return model.Status == string(bean.RequestStatusApproved)
+		}), mock.Anything).Return(nil)
+		requestRepository.On("CommitTx", mock.Anything).Return(nil)
+		actionRepository.On("FindByRequestId", 1).Return([]*repository.DeploymentApprovalAction{approvalBy(3), approvalBy(4)}, nil)
+
+		dto, err := service.Approve(&bean.ActionRequest{Id: 1, UserId: 4})
+		assert.Nil(tt, err)
+		assert.Equal(tt, bean.RequestStatusApproved, dto.Status)
+	})
+
+	t.Run("request approved meanwhile is not approved again", func(tt *testing.T) {
+		service, requestRepository, actionRepository := initDeploymentApprovalService(tt)
+		expectApprovalTransaction(requestRepository)
+		approved := pendingRequest()
+		approved.Status = string(bean.RequestStatusApproved)
+		requestRepository.On("FindByIdForUpdate", 1, mock.Anything).Return(approved, nil)
+
+		_, err := service.Approve(&bean.ActionRequest{Id: 1, UserId: 4})
+		assertApiErrorStatus(tt, err, http.StatusConflict)
+		actionRepository.AssertNotCalled(tt, "SaveWithTxn", mock.Anything, mock.Anything)
+	})
+
+	t.Run("second approval of a user", func(tt *testing.T) {
+		service, requestRepository, actionRepository := initDeploymentApprovalService(tt)
+		expectApprovalTransaction(requestRepository)
+		requestRepository.On("FindByIdForUpdate", 1, mock.Anything).Return(pendingRequest(), nil)
+		actionRepository.On("FindByRequestIdWithTxn", 1, mock.Anything).Return([]*repository.DeploymentApprovalAction{approvalBy(3)}, nil)
+
+		_, err := service.Approve(&bean.ActionRequest{Id: 1, UserId: 3})
+		assertApiErrorStatus(tt, err, http.StatusConflict)
+		actionRepository.AssertNotCalled(tt, "SaveWithTxn", mock.Anything, mock.Anything)
+	})
+
+	t.Run("self approval", func(tt *testing.T) {
+		service, requestRepository, _ := initDeploymentApprovalService(tt)
+		expectApprovalTransaction(requestRepository)
+		requestRepository.On("FindByIdForUpdate", 1, mock.Anything).Return(pendingRequest(), nil)
+
+		_, err := service.Approve(&bean.ActionRequest{Id: 1, UserId: 2})
+		assertApiErrorStatus(tt, err, http.StatusForbidden)
+	})
+
+	t.Run("user outside the approver role groups", func(tt *testing.T) {
+		service, requestRepository, _ := initDeploymentApprovalService(tt)
+		expectApprovalTransaction(requestRepository)
+		requestRepository.On("FindByIdForUpdate", 1, mock.Anything).Return(pendingRequest(), nil)
+
+		_, err := service.Approve(&bean.ActionRequest{Id: 1, UserId: 5})
+		assertApiErrorStatus(tt, err, http.StatusForbidden)
+	})
+
+	t.Run("missing request", func(tt *testing.T) {
+		service, requestRepository, _ := initDeploymentApprovalService(tt)
+		expectApprovalTransaction(requestRepository)
+		requestRepository.On("FindByIdForUpdate", 1, mock.Anything).Return(nil, pg.ErrNoRows)
+
+		_, err := service.Approve(&bean.ActionRequest{Id: 1, UserId: 3})
+		assertApiErrorStatus(tt, err, http.StatusNotFound)
+	})
+}
+
+func TestCheckApproval(t *testing.T) {
+	newCheckRequest := func(envId int, approvalRequestId int) *bean.ApprovalCheckRequest {
+		return &bean.ApprovalCheckRequest{
+			Pipeline:          &pipelineConfig.Pipeline{Id: 9, AppId: 8, EnvironmentId: envId},
+			CiArtifactId:      11,
+			OverrideRequest:   &apiBean.ValuesOverrideRequest{DeploymentWithConfig: apiBean.DEPLOYMENT_CONFIG_TYPE_SPECIFIC_TRIGGER, WfrIdForDeploymentWithSpecificTrigger: 12},
+			TriggeredBy:       2,
+			ApprovalRequestId: approvalRequestId,
+		}
+	}
+	approvedRequest := func(t *testing.T, request *bean.ApprovalCheckRequest) *repository.DeploymentApprovalRequest {
+		snapshotHash, err := hashConfigSnapshot(&configSnapshot{DeploymentWithConfig: apiBean.DEPLOYMENT_CONFIG_TYPE_SPECIFIC_TRIGGER, SpecificTriggerWfrId: 12})
+		assert.Nil(t, err)
+		return &repository.DeploymentApprovalRequest{Id: 1, PipelineId: 9, CiArtifactId: 11, ConfigSnapshotHash: snapshotHash,
+			Status: string(bean.RequestStatusApproved), RequiredApprovals: 2, ExpiresOn: time.Now().Add(time.Hour)}
+	}
+
+	t.Run("environment without protection rule", func(tt *testing.T) {
+		service, _, _ := initDeploymentApprovalService(tt)
+		assert.Nil(tt, service.CheckApproval(newCheckRequest(6, 0)))
+	})
+
+	t.Run("pending request blocks the deployment", func(tt *testing.T) {
+		service, requestRepository, actionRepository := initDeploymentApprovalService(tt)
+		pending := pendingRequest()
+		requestRepository.On("FindOpen", 9, 11, mock.Anything).Return(pending, nil)
+		actionRepository.On("FindByRequestId", 1).Return([]*repository.DeploymentApprovalAction{approvalBy(3)}, nil)
+
+		err := service.CheckApproval(newCheckRequest(7, 0))
+		assertApiErrorStatus(tt, err, http.StatusForbidden)
+		assert.Equal(tt, constants.ApprovalRequired, err.(*util.ApiError).Code)
+	})
+
+	t.Run("approved request is consumed once", func(tt *testing.T) {
+		service, requestRepository, actionRepository := initDeploymentApprovalService(tt)
+		checkRequest := newCheckRequest(7, 1)
+		requestRepository.On("FindById", 1).Return(approvedRequest(tt, checkRequest), nil)
+		requestRepository.On("UpdateStatus", 1, bean.RequestStatusApproved, bean.RequestStatusDeployed, "", int32(2)).Return(true, nil).Once()
+		actionRepository.On("Save", mock.MatchedBy(func(action *repository.DeploymentApprovalAction) bool {
+			return action.Action == string(bean.ActionDeployed)
+		})).Return(nil)
+		assert.Nil(tt, service.CheckApproval(checkRequest))
+
+		requestRepository.On("UpdateStatus", 1, bean.RequestStatusApproved, bean.RequestStatusDeployed, "", int32(2)).Return(false, nil).Once()
+		assertApiErrorStatus(tt, service.CheckApproval(checkRequest), http.StatusConflict)
+	})
+
+	t.Run("approved request of another artifact", func(tt *testing.T) {
+		service, requestRepository, _ := initDeploymentApprovalService(tt)
+		checkRequest := newCheckRequest(7, 1)
+		model := approvedRequest(tt, checkRequest)
+		model.CiArtifactId = 10
+		requestRepository.On("FindById", 1).Return(model, nil)
+		assertApiErrorStatus(tt, service.CheckApproval(checkRequest), http.StatusConflict)
+	})
+
+	t.Run("expired approval", func(tt *testing.T) {
+		service, requestRepository, _ := initDeploymentApprovalService(tt)
+		checkRequest := newCheckRequest(7, 1)
+		model := approvedRequest(tt, checkRequest)
+		model.ExpiresOn = time.Now().Add(-time.Minute)
+		requestRepository.On("FindById", 1).Return(model, nil)
+		assertApiErrorStatus(tt, service.CheckApproval(checkRequest), http.StatusConflict)
+	})
+}
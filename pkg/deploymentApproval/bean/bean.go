@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"time"
+)
+
+type DeploymentApprovalConfig struct {
+	CronSchedule             string `env:"DEPLOYMENT_APPROVAL_CRON" envDefault:"* * * * *" description:"Schedule of the job expiring approval requests and triggering approved deployments"`
+	DefaultRequestTtlMinutes int    `env:"DEPLOYMENT_APPROVAL_DEFAULT_TTL_MINUTES" envDefault:"1440" description:"Validity of an approval request when the protection rule sets none"`
+}
+
+type RequestStatus string
+
+const (
+	RequestStatusPending   RequestStatus = "PENDING"
+	RequestStatusApproved  RequestStatus = "APPROVED"
+	RequestStatusDeployed  RequestStatus = "DEPLOYED"
+	RequestStatusRejected  RequestStatus = "REJECTED"
+	RequestStatusCancelled RequestStatus = "CANCELLED"
+	RequestStatusExpired   RequestStatus = "EXPIRED"
+	RequestStatusFailed    RequestStatus = "FAILED"
+)
+
+type Action string
+
+const (
+	ActionRequested Action = "REQUESTED"
+	ActionApproved  Action = "APPROVED"
+	ActionRejected  Action = "REJECTED"
+	ActionCancelled Action = "CANCELLED"
+	ActionExpired   Action = "EXPIRED"
+	ActionDeployed  Action = "DEPLOYED"
+	ActionFailed    Action = "FAILED"
+)
+
+// ProtectionRuleDto makes deployments to an environment wait for approvals from members of the role groups
+type ProtectionRuleDto struct {
+	EnvironmentId      int      `json:"environmentId"`
+	EnvironmentName    string   `json:"environmentName,omitempty"`
+	RequiredApprovals  int      `json:"requiredApprovals" validate:"min=1"`
+	ApproverRoleGroups []string `json:"approverRoleGroups" validate:"min=1"`
+	// RequestTtlMinutes is how long a request stays valid, the default of the config is used when zero
+	RequestTtlMinutes int   `json:"requestTtlMinutes" validate:"gte=0"`
+	UserId            int32 `json:"-"`
+}
+
+// ApprovalCheckRequest is the deployment checked against the protection rule of its environment
+type ApprovalCheckRequest struct {
+	Pipeline        *pipelineConfig.Pipeline
+	CiArtifactId    int
+	OverrideRequest *bean.ValuesOverrideRequest
+	TriggeredBy     int32
+	// ApprovalRequestId is set when an approved request is deployed, only that request is consumed then
+	ApprovalRequestId int
+}
+
+// ActionRequest approves, rejects or cancels a request
+type ActionRequest struct {
+	Id      int    `json:"-"`
+	Comment string `json:"comment" validate:"max=1000"`
+	UserId  int32  `json:"-"`
+}
+
+type ListFilter struct {
+	Status        RequestStatus
+	PipelineId    int
+	EnvironmentId int
+}
+
+type ApprovalRequestDto struct {
+	Id                 int           `json:"id"`
+	PipelineId         int           `json:"pipelineId"`
+	AppId              int           `json:"appId"`
+	EnvironmentId      int           `json:"environmentId"`
+	CiArtifactId       int           `json:"ciArtifactId"`
+	ConfigSnapshotHash string        `json:"configSnapshotHash"`
+	Status             RequestStatus `json:"status"`
+	RequiredApprovals  int           `json:"requiredApprovals"`
+	ApproverRoleGroups []string      `json:"approverRoleGroups"`
+	RequestedBy        string        `json:"requestedBy"`
+	RequestedOn        time.Time     `json:"requestedOn"`
+	ExpiresOn          time.Time     `json:"expiresOn"`
+	ClosedOn           *time.Time    `json:"closedOn,omitempty"`
+	FailureReason      string        `json:"failureReason,omitempty"`
+	Actions            []*ActionDto  `json:"actions"`
+}
+
+type ActionDto struct {
+	Action   Action    `json:"action"`
+	ActionBy string    `json:"actionBy"`
+	Comment  string    `json:"comment,omitempty"`
+	ActionOn time.Time `json:"actionOn"`
+}
@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentApproval
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/constants"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval/bean"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval/repository"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// configSnapshot is the configuration a deployment is done with, an approval is bound to its hash
+type configSnapshot struct {
+	DeploymentWithConfig  apiBean.DeploymentConfigurationType `json:"deploymentWithConfig"`
+	SpecificTriggerWfrId  int                                 `json:"specificTriggerWfrId"`
+	AdditionalOverride    string                              `json:"additionalOverride"`
+	Strategy              string                              `json:"strategy"`
+	ChartRefId            int                                 `json:"chartRefId"`
+	DeploymentTemplate    string                              `json:"deploymentTemplate"`
+	EnvDeploymentTemplate string                              `json:"envDeploymentTemplate"`
+	ConfigMaps            string                              `json:"configMaps"`
+	Secrets               string                              `json:"secrets"`
+	EnvConfigMaps         string                              `json:"envConfigMaps"`
+	EnvSecrets            string                              `json:"envSecrets"`
+	StrategyConfig        string                              `json:"strategyConfig"`
+}
+
+func hashConfigSnapshot(snapshot *configSnapshot) (string, error) {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// isSavedConfigDeployment is true when the deployment uses the saved configuration, a specific trigger is deployed with its own history
+func isSavedConfigDeployment(deploymentWithConfig apiBean.DeploymentConfigurationType) bool {
+	return deploymentWithConfig != apiBean.DEPLOYMENT_CONFIG_TYPE_SPECIFIC_TRIGGER
+}
+
+// getOverrideRequestJson drops the ids of the current run from the request, a replay creates its own workflow
+func getOverrideRequestJson(overrideRequest *apiBean.ValuesOverrideRequest) (string, error) {
+	request := *overrideRequest
+	request.WfrId = 0
+	request.CdWorkflowId = 0
+	request.PipelineOverrideId = 0
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// getApproverIds returns the distinct users who approved the request
+func getApproverIds(actions []*repository.DeploymentApprovalAction) []int32 {
+	approverIds := make([]int32, 0)
+	for _, action := range actions {
+		if action.Action == string(bean.ActionApproved) && !slices.Contains(approverIds, action.CreatedBy) {
+			approverIds = append(approverIds, action.CreatedBy)
+		}
+	}
+	return approverIds
+}
+
+// isApprover checks the user belongs to one of the approver role groups, groups are matched on their casbin names
+func isApprover(userRoles []string, approverGroupCasbinNames []string) bool {
+	for _, casbinName := range approverGroupCasbinNames {
+		if slices.Contains(userRoles, casbinName) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkRequestStatus(model *repository.DeploymentApprovalRequest, status bean.RequestStatus) error {
+	if model.Status != string(status) {
+		errMsg := fmt.Sprintf("approval request %d is %s, not %s", model.Id, model.Status, status)
+		return util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	return nil
+}
+
+func getExpiresOn(requestedOn time.Time, ttlMinutes, defaultTtlMinutes int) time.Time {
+	if ttlMinutes <= 0 {
+		ttlMinutes = defaultTtlMinutes
+	}
+	return requestedOn.Add(time.Duration(ttlMinutes) * time.Minute)
+}
+
+func validateRule(rule *bean.ProtectionRuleDto) error {
+	seen := make(map[string]bool, len(rule.ApproverRoleGroups))
+	for _, group := range rule.ApproverRoleGroups {
+		if len(group) == 0 || seen[group] {
+			return fmt.Errorf("approver role groups must be distinct and non empty")
+		}
+		seen[group] = true
+	}
+	return nil
+}
+
+func newApprovalRequiredError(model *repository.DeploymentApprovalRequest, approvals int) *util.ApiError {
+	errMsg := fmt.Sprintf("deployment needs %d approval(s), approval request %d has %d and is %s",
+		model.RequiredApprovals, model.Id, approvals, model.Status)
+	return util.NewApiError(http.StatusForbidden, errMsg, errMsg).WithCode(constants.ApprovalRequired)
+}
+
+func toRuleModel(rule *bean.ProtectionRuleDto, model *repository.EnvironmentProtectionRule) {
+	model.EnvironmentId = rule.EnvironmentId
+	model.RequiredApprovals = rule.RequiredApprovals
+	model.ApproverRoleGroups = rule.ApproverRoleGroups
+	model.RequestTtlMinutes = rule.RequestTtlMinutes
+	model.Active = true
+}
+
+func toRuleDto(model *repository.EnvironmentProtectionRule, envName string) *bean.ProtectionRuleDto {
+	return &bean.ProtectionRuleDto{
+		EnvironmentId:      model.EnvironmentId,
+		EnvironmentName:    envName,
+		RequiredApprovals:  model.RequiredApprovals,
+		ApproverRoleGroups: model.ApproverRoleGroups,
+		RequestTtlMinutes:  model.RequestTtlMinutes,
+	}
+}
+
+func toRequestDto(model *repository.DeploymentApprovalRequest, requestedBy string, actions []*bean.ActionDto) *bean.ApprovalRequestDto {
+	dto := &bean.ApprovalRequestDto{
+		Id:                 model.Id,
+		PipelineId:         model.PipelineId,
+		AppId:              model.AppId,
+		EnvironmentId:      model.EnvId,
+		CiArtifactId:       model.CiArtifactId,
+		ConfigSnapshotHash: model.ConfigSnapshotHash,
+		Status:             bean.RequestStatus(model.Status),
+		RequiredApprovals:  model.RequiredApprovals,
+		ApproverRoleGroups: model.ApproverRoleGroups,
+		RequestedBy:        requestedBy,
+		RequestedOn:        model.CreatedOn,
+		ExpiresOn:          model.ExpiresOn,
+		FailureReason:      model.FailureReason,
+		Actions:            actions,
+	}
+	if !model.ClosedOn.IsZero() {
+		closedOn := model.ClosedOn
+		dto.ClosedOn = &closedOn
+	}
+	return dto
+}
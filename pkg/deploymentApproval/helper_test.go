@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentApproval
+
+import (
+	"encoding/json"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/constants"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval/bean"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHashConfigSnapshot(t *testing.T) {
+	snapshot := &configSnapshot{ChartRefId: 10, DeploymentTemplate: `{"replicaCount":1}`, ConfigMaps: `{"maps":[]}`}
+	hash, err := hashConfigSnapshot(snapshot)
+	assert.Nil(t, err)
+	assert.Len(t, hash, 64)
+
+	same, err := hashConfigSnapshot(&configSnapshot{ChartRefId: 10, DeploymentTemplate: `{"replicaCount":1}`, ConfigMaps: `{"maps":[]}`})
+	assert.Nil(t, err)
+	assert.Equal(t, hash, same)
+
+	snapshot.EnvSecrets = `{"secrets":[]}`
+	changed, err := hashConfigSnapshot(snapshot)
+	assert.Nil(t, err)
+	assert.NotEqual(t, hash, changed)
+}
+
+func TestIsSavedConfigDeployment(t *testing.T) {
+	assert.True(t, isSavedConfigDeployment(""))
+	assert.True(t, isSavedConfigDeployment(apiBean.DEPLOYMENT_CONFIG_TYPE_LAST_SAVED))
+	assert.True(t, isSavedConfigDeployment(apiBean.DEPLOYMENT_CONFIG_TYPE_LATEST_TRIGGER))
+	assert.False(t, isSavedConfigDeployment(apiBean.DEPLOYMENT_CONFIG_TYPE_SPECIFIC_TRIGGER))
+}
+
+func TestGetOverrideRequestJson(t *testing.T) {
+	overrideRequest := &apiBean.ValuesOverrideRequest{PipelineId: 1, AppId: 2, CiArtifactId: 3, WfrId: 4, CdWorkflowId: 5, PipelineOverrideId: 6, UserId: 7}
+	data, err := getOverrideRequestJson(overrideRequest)
+	assert.Nil(t, err)
+	decoded := &apiBean.ValuesOverrideRequest{}
+	assert.Nil(t, json.Unmarshal([]byte(data), decoded))
+	assert.Equal(t, 3, decoded.CiArtifactId)
+	assert.Zero(t, decoded.WfrId)
+	assert.Zero(t, decoded.CdWorkflowId)
+	assert.Zero(t, decoded.PipelineOverrideId)
+	// the request being stored is not changed
+	assert.Equal(t, 4, overrideRequest.WfrId)
+}
+
+func TestGetApproverIds(t *testing.T) {
+	actions := []*repository.DeploymentApprovalAction{
+		{Action: string(bean.ActionRequested), AuditLog: sql.AuditLog{CreatedBy: 2}},
+		{Action: string(bean.ActionApproved), AuditLog: sql.AuditLog{CreatedBy: 3}},
+		{Action: string(bean.ActionApproved), AuditLog: sql.AuditLog{CreatedBy: 3}},
+		{Action: string(bean.ActionApproved), AuditLog: sql.AuditLog{CreatedBy: 4}},
+	}
+	assert.Equal(t, []int32{3, 4}, getApproverIds(actions))
+	assert.Empty(t, getApproverIds(nil))
+}
+
+func TestIsApprover(t *testing.T) {
+	assert.True(t, isApprover([]string{"group:qa", "group:sre"}, []string{"group:sre"}))
+	assert.False(t, isApprover([]string{"group:qa"}, []string{"group:sre"}))
+	assert.False(t, isApprover(nil, []string{"group:sre"}))
+	assert.False(t, isApprover([]string{"group:qa"}, nil))
+}
+
+func TestGetExpiresOn(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, now.Add(30*time.Minute), getExpiresOn(now, 30, 1440))
+	assert.Equal(t, now.Add(1440*time.Minute), getExpiresOn(now, 0, 1440))
+}
+
+func TestValidateRule(t *testing.T) {
+	assert.Nil(t, validateRule(&bean.ProtectionRuleDto{RequiredApprovals: 1, ApproverRoleGroups: []string{"sre", "qa"}}))
+	assert.NotNil(t, validateRule(&bean.ProtectionRuleDto{RequiredApprovals: 1, ApproverRoleGroups: []string{"sre", "sre"}}))
+	assert.NotNil(t, validateRule(&bean.ProtectionRuleDto{RequiredApprovals: 1, ApproverRoleGroups: []string{""}}))
+}
+
+func TestNewApprovalRequiredError(t *testing.T) {
+	model := &repository.DeploymentApprovalRequest{Id: 7, RequiredApprovals: 2, Status: string(bean.RequestStatusPending)}
+	err := newApprovalRequiredError(model, 1)
+	assert.Equal(t, http.StatusForbidden, err.HttpStatusCode)
+	assert.Equal(t, constants.ApprovalRequired, err.Code)
+	assert.Contains(t, err.UserMessage, "approval request 7 has 1")
+}
+
+func TestToRequestDto(t *testing.T) {
+	model := &repository.DeploymentApprovalRequest{Id: 1, EnvId: 2, Status: string(bean.RequestStatusPending)}
+	dto := toRequestDto(model, "user@example.com", nil)
+	assert.Equal(t, 2, dto.EnvironmentId)
+	assert.Nil(t, dto.ClosedOn)
+
+	model.ClosedOn = time.Now()
+	dto = toRequestDto(model, "user@example.com", nil)
+	assert.NotNil(t, dto.ClosedOn)
+}
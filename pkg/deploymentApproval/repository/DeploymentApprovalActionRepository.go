@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// DeploymentApprovalAction is an entry of the history of a request, entries are never updated
+type DeploymentApprovalAction struct {
+	tableName                   struct{} `sql:"deployment_approval_action" pg:",discard_unknown_columns"`
+	Id                          int      `sql:"id,pk"`
+	DeploymentApprovalRequestId int      `sql:"deployment_approval_request_id,notnull"`
+	Action                      string   `sql:"action,notnull"`
+	Comment                     string   `sql:"comment"`
+	sql.AuditLog
+}
+
+type DeploymentApprovalActionRepository interface {
+	Save(model *DeploymentApprovalAction) error
+	SaveWithTxn(model *DeploymentApprovalAction, tx *pg.Tx) error
+	FindByRequestId(requestId int) ([]*DeploymentApprovalAction, error)
+	FindByRequestIdWithTxn(requestId int, tx *pg.Tx) ([]*DeploymentApprovalAction, error)
+}
+
+type DeploymentApprovalActionRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewDeploymentApprovalActionRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *DeploymentApprovalActionRepositoryImpl {
+	return &DeploymentApprovalActionRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *DeploymentApprovalActionRepositoryImpl) Save(model *DeploymentApprovalAction) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *DeploymentApprovalActionRepositoryImpl) SaveWithTxn(model *DeploymentApprovalAction, tx *pg.Tx) error {
+	return tx.Insert(model)
+}
+
+func (impl *DeploymentApprovalActionRepositoryImpl) FindByRequestId(requestId int) ([]*DeploymentApprovalAction, error) {
+	var models []*DeploymentApprovalAction
+	err := impl.dbConnection.Model(&models).
+		Where("deployment_approval_request_id = ?", requestId).
+		Order("id").
+		Select()
+	return models, err
+}
+
+func (impl *DeploymentApprovalActionRepositoryImpl) FindByRequestIdWithTxn(requestId int, tx *pg.Tx) ([]*DeploymentApprovalAction, error) {
+	var models []*DeploymentApprovalAction
+	err := tx.Model(&models).
+		Where("deployment_approval_request_id = ?", requestId).
+		Order("id").
+		Select()
+	return models, err
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type DeploymentApprovalRequest struct {
+	tableName    struct{} `sql:"deployment_approval_request" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	PipelineId   int      `sql:"pipeline_id,notnull"`
+	AppId        int      `sql:"app_id,notnull"`
+	EnvId        int      `sql:"environment_id,notnull"`
+	CiArtifactId int      `sql:"ci_artifact_id,notnull"`
+	// ConfigSnapshotHash identifies the configuration deployed with the artifact, an approval is not valid for other configurations
+	ConfigSnapshotHash string `sql:"config_snapshot_hash,notnull"`
+	// OverrideRequest is the trigger request in json, it is replayed once the request is approved
+	OverrideRequest string `sql:"override_request,notnull"`
+	Status          string `sql:"status,notnull"`
+	// RequiredApprovals and ApproverRoleGroups are copied from the rule, rule changes don't affect open requests
+	RequiredApprovals  int       `sql:"required_approvals,notnull"`
+	ApproverRoleGroups []string  `sql:"approver_role_groups" pg:",array"`
+	RequestedBy        int32     `sql:"requested_by,notnull"`
+	ExpiresOn          time.Time `sql:"expires_on,notnull"`
+	ClosedOn           time.Time `sql:"closed_on"`
+	FailureReason      string    `sql:"failure_reason"`
+	sql.AuditLog
+}
+
+type DeploymentApprovalRequestRepository interface {
+	sql.TransactionWrapper
+	Save(model *DeploymentApprovalRequest) error
+	Update(model *DeploymentApprovalRequest) error
+	UpdateWithTxn(model *DeploymentApprovalRequest, tx *pg.Tx) error
+	// UpdateStatus closes the request with the status only if it is still in the from status, false is returned otherwise
+	UpdateStatus(id int, from, to bean.RequestStatus, failureReason string, userId int32) (bool, error)
+	FindById(id int) (*DeploymentApprovalRequest, error)
+	// FindByIdForUpdate locks the request until the transaction ends, approvals of the request are counted one after the other
+	FindByIdForUpdate(id int, tx *pg.Tx) (*DeploymentApprovalRequest, error)
+	FindAll(filter *bean.ListFilter) ([]*DeploymentApprovalRequest, error)
+	// FindOpen returns the pending or approved, unexpired request for the artifact and configuration
+	FindOpen(pipelineId, ciArtifactId int, configSnapshotHash string) (*DeploymentApprovalRequest, error)
+	FindByStatus(status bean.RequestStatus) ([]*DeploymentApprovalRequest, error)
+	FindOpenExpiredBefore(before time.Time) ([]*DeploymentApprovalRequest, error)
+}
+
+type DeploymentApprovalRequestRepositoryImpl struct {
+	*sql.TransactionUtilImpl
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewDeploymentApprovalRequestRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger,
+	transactionUtilImpl *sql.TransactionUtilImpl) *DeploymentApprovalRequestRepositoryImpl {
+	return &DeploymentApprovalRequestRepositoryImpl{
+		TransactionUtilImpl: transactionUtilImpl,
+		dbConnection:        dbConnection,
+		logger:              logger,
+	}
+}
+
+func (impl *DeploymentApprovalRequestRepositoryImpl) Save(model *DeploymentApprovalRequest) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *DeploymentApprovalRequestRepositoryImpl) Update(model *DeploymentApprovalRequest) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *DeploymentApprovalRequestRepositoryImpl) UpdateWithTxn(model *DeploymentApprovalRequest, tx *pg.Tx) error {
+	return tx.Update(model)
+}
+
+func (impl *DeploymentApprovalRequestRepositoryImpl) UpdateStatus(id int, from, to bean.RequestStatus, failureReason string, userId int32) (bool, error) {
+	now := time.Now()
+	res, err := impl.dbConnection.Model(&DeploymentApprovalRequest{}).
+		Set("status = ?", to).
+		Set("failure_reason = ?", failureReason).
+		Set("closed_on = ?", now).
+		Set("updated_on = ?", now).
+		Set("updated_by = ?", userId).
+		Where("id = ?", id).
+		Where("status = ?", from).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (impl *DeploymentApprovalRequestRepositoryImpl) FindById(id int) (*DeploymentApprovalRequest, error) {
+	model := &DeploymentApprovalRequest{}
+	err := impl.dbConnection.Model(model).Where("id = ?", id).Select()
+	return model, err
+}
+
+func (impl *DeploymentApprovalRequestRepositoryImpl) FindByIdForUpdate(id int, tx *pg.Tx) (*DeploymentApprovalRequest, error) {
+	model := &DeploymentApprovalRequest{}
+	err := tx.Model(model).Where("id = ?", id).For("UPDATE").Select()
+	return model, err
+}
+
+func (impl *DeploymentApprovalRequestRepositoryImpl) FindAll(filter *bean.ListFilter) ([]*DeploymentApprovalRequest, error) {
+	var models []*DeploymentApprovalRequest
+	query := impl.dbConnection.Model(&models)
+	if len(filter.Status) > 0 {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.PipelineId > 0 {
+		query = query.Where("pipeline_id = ?", filter.PipelineId)
+	}
+	if filter.EnvironmentId > 0 {
+		query = query.Where("environment_id = ?", filter.EnvironmentId)
+	}
+	err := query.Order("id DESC").Select()
+	return models, err
+}
+
+func (impl *DeploymentApprovalRequestRepositoryImpl) FindOpen(pipelineId, ciArtifactId int, configSnapshotHash string) (*DeploymentApprovalRequest, error) {
+	model := &DeploymentApprovalRequest{}
+	err := impl.dbConnection.Model(model).
+		Where("pipeline_id = ?", pipelineId).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Where("config_snapshot_hash = ?", configSnapshotHash).
+		Where("status IN (?)", pg.In([]bean.RequestStatus{bean.RequestStatusPending, bean.RequestStatusApproved})).
+		Where("expires_on > ?", time.Now()).
+		Order("id DESC").
+		Limit(1).
+		Select()
+	return model, err
+}
+
+func (impl *DeploymentApprovalRequestRepositoryImpl) FindByStatus(status bean.RequestStatus) ([]*DeploymentApprovalRequest, error) {
+	var models []*DeploymentApprovalRequest
+	err := impl.dbConnection.Model(&models).
+		Where("status = ?", status).
+		Order("id").
+		Select()
+	return models, err
+}
+
+func (impl *DeploymentApprovalRequestRepositoryImpl) FindOpenExpiredBefore(before time.Time) ([]*DeploymentApprovalRequest, error) {
+	var models []*DeploymentApprovalRequest
+	err := impl.dbConnection.Model(&models).
+		Where("status IN (?)", pg.In([]bean.RequestStatus{bean.RequestStatusPending, bean.RequestStatusApproved})).
+		Where("expires_on <= ?", before).
+		Order("expires_on").
+		Select()
+	return models, err
+}
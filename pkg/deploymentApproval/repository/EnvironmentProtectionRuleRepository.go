@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type EnvironmentProtectionRule struct {
+	tableName          struct{} `sql:"environment_protection_rule" pg:",discard_unknown_columns"`
+	Id                 int      `sql:"id,pk"`
+	EnvironmentId      int      `sql:"environment_id,notnull"`
+	RequiredApprovals  int      `sql:"required_approvals,notnull"`
+	ApproverRoleGroups []string `sql:"approver_role_groups" pg:",array"`
+	RequestTtlMinutes  int      `sql:"request_ttl_minutes,notnull"`
+	Active             bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type EnvironmentProtectionRuleRepository interface {
+	Save(model *EnvironmentProtectionRule) error
+	Update(model *EnvironmentProtectionRule) error
+	FindByEnvironmentId(envId int) (*EnvironmentProtectionRule, error)
+	FindAllActive() ([]*EnvironmentProtectionRule, error)
+}
+
+type EnvironmentProtectionRuleRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewEnvironmentProtectionRuleRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *EnvironmentProtectionRuleRepositoryImpl {
+	return &EnvironmentProtectionRuleRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *EnvironmentProtectionRuleRepositoryImpl) Save(model *EnvironmentProtectionRule) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *EnvironmentProtectionRuleRepositoryImpl) Update(model *EnvironmentProtectionRule) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *EnvironmentProtectionRuleRepositoryImpl) FindByEnvironmentId(envId int) (*EnvironmentProtectionRule, error) {
+	model := &EnvironmentProtectionRule{}
+	err := impl.dbConnection.Model(model).
+		Where("environment_id = ?", envId).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *EnvironmentProtectionRuleRepositoryImpl) FindAllActive() ([]*EnvironmentProtectionRule, error) {
+	var models []*EnvironmentProtectionRule
+	err := impl.dbConnection.Model(&models).
+		Where("active = ?", true).
+		Order("environment_id").
+		Select()
+	return models, err
+}
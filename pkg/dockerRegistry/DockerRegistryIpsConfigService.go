@@ -38,7 +38,11 @@ import (
 
 type DockerRegistryIpsConfigService interface {
 	IsImagePullSecretAccessProvided(dockerRegistryId string, clusterId int, isVirtualEnv bool) (bool, error)
-	HandleImagePullSecretOnApplicationDeployment(ctx context.Context, environment *repository2.Environment, artifact *repository3.CiArtifact, ciPipelineId int, valuesFileContent []byte) ([]byte, error)
+	HandleImagePullSecretOnApplicationDeployment(ctx context.Context, environment *repository2.Environment, artifact *repository3.CiArtifact, ciPipelineId int, valuesFileContent []byte, isDryRun bool) ([]byte, error)
+	// SyncImagePullSecret creates or updates the image pull secret of a registry in the namespace of an environment,
+	// it returns the name of the secret, empty when the registry gives no access to the cluster of the environment.
+	// For dry run only the name is returned
+	SyncImagePullSecret(dockerRegistryId string, environment *repository2.Environment, isDryRun bool) (string, error)
 }
 
 type DockerRegistryIpsConfigServiceImpl struct {
@@ -80,7 +84,7 @@ func (impl DockerRegistryIpsConfigServiceImpl) IsImagePullSecretAccessProvided(d
 	return isAccessProvided, nil
 }
 
-func (impl DockerRegistryIpsConfigServiceImpl) HandleImagePullSecretOnApplicationDeployment(ctx context.Context, environment *repository2.Environment, artifact *repository3.CiArtifact, ciPipelineId int, valuesFileContent []byte) ([]byte, error) {
+func (impl DockerRegistryIpsConfigServiceImpl) HandleImagePullSecretOnApplicationDeployment(ctx context.Context, environment *repository2.Environment, artifact *repository3.CiArtifact, ciPipelineId int, valuesFileContent []byte, isDryRun bool) ([]byte, error) {
 	_, span := otel.Tracer("orchestrator").Start(ctx, "DockerRegistryIpsConfigServiceImpl.HandleImagePullSecretOnApplicationDeployment")
 	defer span.End()
 	clusterId := environment.ClusterId
@@ -126,7 +130,8 @@ func (impl DockerRegistryIpsConfigServiceImpl) HandleImagePullSecretOnApplicatio
 	ipsName := BuildIpsName(*dockerRegistryId, ipsCredentialType, ipsConfig.CredentialValue)
 
 	// Create or update secret of credential type is not of NAME type
-	if ipsCredentialType != IPS_CREDENTIAL_TYPE_NAME {
+	// for dry run, secret is not synced in cluster and only ips name is set in values
+	if ipsCredentialType != IPS_CREDENTIAL_TYPE_NAME && !isDryRun {
 		err = impl.createOrUpdateDockerRegistryImagePullSecret(clusterId, environment.Namespace, ipsName, dockerRegistryBean)
 		if err != nil {
 			return nil, err
@@ -144,6 +149,29 @@ func (impl DockerRegistryIpsConfigServiceImpl) HandleImagePullSecretOnApplicatio
 	return updatedValuesFileContent, nil
 }
 
+func (impl DockerRegistryIpsConfigServiceImpl) SyncImagePullSecret(dockerRegistryId string, environment *repository2.Environment, isDryRun bool) (string, error) {
+	dockerRegistryBean, err := impl.dockerArtifactStoreRepository.FindOne(dockerRegistryId)
+	if err != nil {
+		impl.logger.Errorw("error in getting docker registry", "dockerRegistryId", dockerRegistryId, "error", err)
+		return "", err
+	}
+	ipsConfig := dockerRegistryBean.IpsConfig
+	if ipsConfig == nil || !CheckIfImagePullSecretAccessProvided(ipsConfig.AppliedClusterIdsCsv, ipsConfig.IgnoredClusterIdsCsv, environment.ClusterId, environment.IsVirtualEnvironment) {
+		impl.logger.Infow("ips access not given", "dockerRegistryId", dockerRegistryId, "clusterId", environment.ClusterId)
+		return "", nil
+	}
+	ipsCredentialType := string(ipsConfig.CredentialType)
+	ipsName := BuildIpsName(dockerRegistryId, ipsCredentialType, ipsConfig.CredentialValue)
+	// secrets of NAME type are managed outside devtron, only their name is used
+	if ipsCredentialType != IPS_CREDENTIAL_TYPE_NAME && !isDryRun {
+		err = impl.createOrUpdateDockerRegistryImagePullSecret(environment.ClusterId, environment.Namespace, ipsName, dockerRegistryBean)
+		if err != nil {
+			return "", err
+		}
+	}
+	return ipsName, nil
+}
+
 func (impl DockerRegistryIpsConfigServiceImpl) createOrUpdateDockerRegistryImagePullSecret(clusterId int, namespace string, ipsName string, dockerRegistryBean *repository.DockerArtifactStore) error {
 	impl.logger.Infow("creating/updating ips", "ipsName", ipsName, "clusterId", clusterId)
 
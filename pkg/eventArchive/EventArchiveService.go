@@ -0,0 +1,310 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventArchive
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/caarlos0/env"
+	k8sUtil "github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/app/status"
+	"github.com/devtron-labs/devtron/pkg/eventArchive/bean"
+	"github.com/devtron-labs/devtron/pkg/eventArchive/repository"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/k8s/informer"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	retentionCron = "30 * * * *"
+	// maxOwnerDepth is how many controllers up an object is looked up for the labels of its app,
+	// a pod is three levels below a deployment of a cron job
+	maxOwnerDepth      = 3
+	ownerLookupTimeout = 10 * time.Second
+)
+
+type EventArchiveService interface {
+	// GetTimeline returns the events of an app on an environment seen in [from, to) along with the statuses of its
+	// deployments on it
+	GetTimeline(appId, envId int, from, to time.Time) (*bean.EventTimeline, error)
+	DeleteExpiredEvents()
+}
+
+type collectedEvent struct {
+	clusterName string
+	event       *v1.Event
+}
+
+type EventArchiveServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	eventArchiveRepository        repository.K8sEventArchiveRepository
+	pipelineRepository            pipelineConfig.PipelineRepository
+	pipelineStatusTimelineService status.PipelineStatusTimelineService
+	k8sCommonService              k8s.K8sCommonService
+	k8sUtil                       *k8sUtil.K8sServiceImpl
+	config                        *bean.EventArchiveConfig
+	events                        chan *collectedEvent
+	droppedEvents                 atomic.Int64
+	// environments are keyed by cluster name and namespace, they are read by the informers of every cluster
+	environments     map[string]*environment
+	environmentsLock sync.RWMutex
+	// ownerAppIds caches the app found from the owners of an object by its uid, it is only used by the collector
+	// and is reset along with the environments
+	ownerAppIds map[types.UID]int
+}
+
+func NewEventArchiveServiceImpl(logger *zap.SugaredLogger,
+	eventArchiveRepository repository.K8sEventArchiveRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	pipelineStatusTimelineService status.PipelineStatusTimelineService,
+	k8sCommonService k8s.K8sCommonService,
+	k8sUtil *k8sUtil.K8sServiceImpl,
+	k8sInformerFactory informer.K8sInformerFactory,
+	cronLogger *cron2.CronLoggerImpl) (*EventArchiveServiceImpl, error) {
+	config := &bean.EventArchiveConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing event archive config", "err", err)
+		return nil, err
+	}
+	impl := &EventArchiveServiceImpl{
+		logger:                        logger,
+		eventArchiveRepository:        eventArchiveRepository,
+		pipelineRepository:            pipelineRepository,
+		pipelineStatusTimelineService: pipelineStatusTimelineService,
+		k8sCommonService:              k8sCommonService,
+		k8sUtil:                       k8sUtil,
+		config:                        config,
+		environments:                  make(map[string]*environment),
+		ownerAppIds:                   make(map[types.UID]int),
+	}
+	retentionCronJob := cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	_, err = retentionCronJob.AddFunc(retentionCron, impl.DeleteExpiredEvents)
+	if err != nil {
+		logger.Errorw("error in adding event archive retention cron", "err", err)
+		return nil, err
+	}
+	retentionCronJob.Start()
+	if !config.Enabled {
+		return impl, nil
+	}
+	impl.events = make(chan *collectedEvent, max(config.BufferSize, 1))
+	impl.refreshEnvironments()
+	go impl.collect()
+	k8sInformerFactory.WatchEvents(impl.getEventHandler)
+	return impl, nil
+}
+
+func (impl *EventArchiveServiceImpl) getEventHandler(clusterName string) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			impl.enqueue(clusterName, obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			impl.enqueue(clusterName, newObj)
+		},
+	}
+}
+
+// enqueue hands an event of a namespace of an environment over to the collector, the informer is never blocked
+// and the event is dropped when the collector is behind
+func (impl *EventArchiveServiceImpl) enqueue(clusterName string, obj interface{}) {
+	event, ok := obj.(*v1.Event)
+	if !ok || impl.getEnvironment(clusterName, event.Namespace) == nil {
+		return
+	}
+	select {
+	case impl.events <- &collectedEvent{clusterName: clusterName, event: event}:
+	default:
+		impl.droppedEvents.Add(1)
+	}
+}
+
+func (impl *EventArchiveServiceImpl) getEnvironment(clusterName, namespace string) *environment {
+	impl.environmentsLock.RLock()
+	defer impl.environmentsLock.RUnlock()
+	return impl.environments[getEnvironmentKey(clusterName, namespace)]
+}
+
+func (impl *EventArchiveServiceImpl) refreshEnvironments() {
+	rows, err := impl.eventArchiveRepository.FindEnvironmentReleases()
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment releases for event archive", "err", err)
+		return
+	}
+	environments := buildEnvironments(rows)
+	impl.environmentsLock.Lock()
+	impl.environments = environments
+	impl.environmentsLock.Unlock()
+	impl.ownerAppIds = make(map[types.UID]int)
+}
+
+// collect saves the events in batches, an event updated many times before a flush is saved once
+func (impl *EventArchiveServiceImpl) collect() {
+	flushTicker := time.NewTicker(time.Duration(max(impl.config.FlushIntervalSeconds, 1)) * time.Second)
+	defer flushTicker.Stop()
+	refreshTicker := time.NewTicker(time.Duration(max(impl.config.CacheRefreshMinutes, 1)) * time.Minute)
+	defer refreshTicker.Stop()
+	pending := make(map[string]*collectedEvent)
+	for {
+		select {
+		case collected := <-impl.events:
+			pending[collected.clusterName+"/"+string(collected.event.UID)] = collected
+			if len(pending) >= impl.config.BatchSize {
+				impl.flush(pending)
+				pending = make(map[string]*collectedEvent)
+			}
+		case <-flushTicker.C:
+			impl.flush(pending)
+			pending = make(map[string]*collectedEvent)
+		case <-refreshTicker.C:
+			impl.refreshEnvironments()
+		}
+	}
+}
+
+func (impl *EventArchiveServiceImpl) flush(pending map[string]*collectedEvent) {
+	if dropped := impl.droppedEvents.Swap(0); dropped > 0 {
+		impl.logger.Warnw("dropped kubernetes events as the event archive buffer was full", "count", dropped)
+	}
+	if len(pending) == 0 {
+		return
+	}
+	models := make([]*repository.K8sEventArchive, 0, len(pending))
+	for _, collected := range pending {
+		env := impl.getEnvironment(collected.clusterName, collected.event.Namespace)
+		if env == nil {
+			continue
+		}
+		model := toEventModel(collected.event, env)
+		model.AppId = impl.getAppId(env, &collected.event.InvolvedObject)
+		models = append(models, model)
+	}
+	err := impl.eventArchiveRepository.UpsertAll(models)
+	if err != nil {
+		impl.logger.Errorw("error in saving kubernetes events", "count", len(models), "err", err)
+	}
+}
+
+// getAppId correlates the object of an event to a devtron app on the environment, by its name first and
+// else by the labels of it and of its controllers
+func (impl *EventArchiveServiceImpl) getAppId(env *environment, involvedObject *v1.ObjectReference) int {
+	if len(env.releases) == 0 {
+		return 0
+	}
+	if appId := getAppIdByName(involvedObject.Name, env.releases); appId > 0 {
+		return appId
+	}
+	// objects of other namespaces or cluster scoped ones can't be of an app of the environment
+	if involvedObject.Namespace != env.namespace || len(involvedObject.UID) == 0 {
+		return 0
+	}
+	if appId, ok := impl.ownerAppIds[involvedObject.UID]; ok {
+		return appId
+	}
+	appId := impl.getAppIdByOwners(env, involvedObject)
+	impl.ownerAppIds[involvedObject.UID] = appId
+	return appId
+}
+
+func (impl *EventArchiveServiceImpl) getAppIdByOwners(env *environment, involvedObject *v1.ObjectReference) int {
+	ctx, cancel := context.WithTimeout(context.Background(), ownerLookupTimeout)
+	defer cancel()
+	restConfig, err, _ := impl.k8sCommonService.GetRestConfigByClusterId(ctx, env.clusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting rest config by cluster id", "clusterId", env.clusterId, "err", err)
+		return 0
+	}
+	apiVersion, kind, name := involvedObject.APIVersion, involvedObject.Kind, involvedObject.Name
+	for depth := 0; depth <= maxOwnerDepth; depth++ {
+		gvk := schema.FromAPIVersionAndKind(apiVersion, kind)
+		manifest, err := impl.k8sUtil.GetResource(ctx, env.namespace, name, gvk, restConfig)
+		if err != nil {
+			// objects of past events may be deleted already
+			impl.logger.Debugw("error in getting involved object of event", "gvk", gvk, "name", name, "err", err)
+			return 0
+		}
+		if appId := getAppIdByLabels(manifest.Manifest.GetLabels(), env); appId > 0 {
+			return appId
+		}
+		owner := metav1.GetControllerOf(&manifest.Manifest)
+		if owner == nil {
+			return 0
+		}
+		if appId := getAppIdByName(owner.Name, env.releases); appId > 0 {
+			return appId
+		}
+		apiVersion, kind, name = owner.APIVersion, owner.Kind, owner.Name
+	}
+	return 0
+}
+
+func (impl *EventArchiveServiceImpl) DeleteExpiredEvents() {
+	before := time.Now().AddDate(0, 0, -impl.config.RetentionDays)
+	deleted, err := impl.eventArchiveRepository.DeleteLastSeenBefore(before)
+	if err != nil {
+		impl.logger.Errorw("error in deleting expired kubernetes events", "before", before, "err", err)
+		return
+	}
+	impl.logger.Debugw("deleted expired kubernetes events", "count", deleted)
+}
+
+func (impl *EventArchiveServiceImpl) GetTimeline(appId, envId int, from, to time.Time) (*bean.EventTimeline, error) {
+	events, err := impl.eventArchiveRepository.FindByAppIdAndEnvId(appId, envId, from, to, impl.config.TimelineLimit+1)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching archived events", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	eventsTruncated := len(events) > impl.config.TimelineLimit
+	if eventsTruncated {
+		events = events[:impl.config.TimelineLimit]
+	}
+	pipelines, err := impl.pipelineRepository.FindActiveByAppIdAndEnvironmentId(appId, envId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching pipelines by app and env", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	var deployments []*status.PipelineStatusTimelineDto
+	for _, pipeline := range pipelines {
+		timelines, err := impl.pipelineStatusTimelineService.FetchTimelinesForPipeline(pipeline.Id, from, to)
+		if err != nil {
+			impl.logger.Errorw("error in fetching deployment timelines", "pipelineId", pipeline.Id, "err", err)
+			return nil, err
+		}
+		deployments = append(deployments, timelines...)
+	}
+	return &bean.EventTimeline{
+		AppId:           appId,
+		EnvId:           envId,
+		From:            from,
+		To:              to,
+		Entries:         buildTimeline(events, deployments),
+		EventsTruncated: eventsTruncated,
+	}, nil
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/app/status"
+)
+
+type EventArchiveConfig struct {
+	Enabled              bool `env:"EVENT_ARCHIVE_ENABLED" envDefault:"true" description:"Collects the kubernetes events of the namespaces of the environments of every cluster, the events of all namespaces of every cluster are cached in memory while enabled"`
+	RetentionDays        int  `env:"EVENT_ARCHIVE_RETENTION_DAYS" envDefault:"14" description:"Archived events last seen before these many days are deleted"`
+	FlushIntervalSeconds int  `env:"EVENT_ARCHIVE_FLUSH_INTERVAL_SECONDS" envDefault:"10" description:"Interval at which the collected events are saved"`
+	BatchSize            int  `env:"EVENT_ARCHIVE_BATCH_SIZE" envDefault:"500" description:"Collected events are saved once these many are pending, even before the flush interval"`
+	BufferSize           int  `env:"EVENT_ARCHIVE_BUFFER_SIZE" envDefault:"10000" description:"Events received while these many are waiting to be saved are dropped"`
+	CacheRefreshMinutes  int  `env:"EVENT_ARCHIVE_CACHE_REFRESH_MINUTES" envDefault:"5" description:"Interval at which the environments and apps the events are correlated to are reloaded"`
+	TimelineLimit        int  `env:"EVENT_ARCHIVE_TIMELINE_LIMIT" envDefault:"1000" description:"Most events returned in a timeline, the latest ones are kept"`
+}
+
+const (
+	DevtronAppIdLabel = "appId"
+	DevtronEnvIdLabel = "envId"
+	ReleaseLabel      = "release"
+)
+
+// DefaultTimelineWindow is the window of a timeline requested without a start
+const DefaultTimelineWindow = 24 * time.Hour
+
+type TimelineEntryType string
+
+const (
+	TimelineEntryTypeEvent      TimelineEntryType = "event"
+	TimelineEntryTypeDeployment TimelineEntryType = "deployment"
+)
+
+type ArchivedEventDto struct {
+	InvolvedKind string    `json:"involvedKind"`
+	InvolvedName string    `json:"involvedName"`
+	Reason       string    `json:"reason"`
+	Message      string    `json:"message"`
+	Type         string    `json:"type"`
+	Count        int32     `json:"count"`
+	FirstSeen    time.Time `json:"firstSeen"`
+	LastSeen     time.Time `json:"lastSeen"`
+	Source       string    `json:"source"`
+}
+
+// TimelineEntry is either an event, placed at the time it was last seen, or a status of a deployment
+type TimelineEntry struct {
+	Type       TimelineEntryType                 `json:"type"`
+	Time       time.Time                         `json:"time"`
+	Event      *ArchivedEventDto                 `json:"event,omitempty"`
+	Deployment *status.PipelineStatusTimelineDto `json:"deployment,omitempty"`
+}
+
+type EventTimeline struct {
+	AppId   int              `json:"appId"`
+	EnvId   int              `json:"envId"`
+	From    time.Time        `json:"from"`
+	To      time.Time        `json:"to"`
+	Entries []*TimelineEntry `json:"entries"`
+	// EventsTruncated is set when the window has more events than the timeline limit, the oldest are left out
+	EventsTruncated bool `json:"eventsTruncated"`
+}
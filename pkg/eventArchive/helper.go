@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventArchive
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/app/status"
+	"github.com/devtron-labs/devtron/pkg/eventArchive/bean"
+	"github.com/devtron-labs/devtron/pkg/eventArchive/repository"
+	v1 "k8s.io/api/core/v1"
+)
+
+type release struct {
+	appId int
+	name  string
+}
+
+// environment is a namespace of a cluster mapped to a devtron environment with the releases deployed on it
+type environment struct {
+	id        int
+	clusterId int
+	namespace string
+	releases  []*release
+}
+
+func getEnvironmentKey(clusterName, namespace string) string {
+	return clusterName + "/" + namespace
+}
+
+// buildEnvironments groups the releases by the cluster and namespace of their environment, the first environment
+// of a namespace mapped to many is kept
+func buildEnvironments(rows []*repository.EnvironmentRelease) map[string]*environment {
+	environments := make(map[string]*environment)
+	for _, row := range rows {
+		key := getEnvironmentKey(row.ClusterName, row.Namespace)
+		env, ok := environments[key]
+		if !ok {
+			env = &environment{id: row.EnvironmentId, clusterId: row.ClusterId, namespace: row.Namespace}
+			environments[key] = env
+		}
+		if env.id != row.EnvironmentId || row.AppId == 0 || len(row.DeploymentAppName) == 0 {
+			continue
+		}
+		env.releases = append(env.releases, &release{appId: row.AppId, name: row.DeploymentAppName})
+	}
+	return environments
+}
+
+// getAppIdByName matches an object to the release it was deployed by, the objects of devtron charts are named
+// after the release or prefixed by it, the longest matching release wins
+func getAppIdByName(name string, releases []*release) int {
+	appId, matchedLength := 0, 0
+	for _, r := range releases {
+		if (name == r.name || strings.HasPrefix(name, r.name+"-")) && len(r.name) > matchedLength {
+			appId, matchedLength = r.appId, len(r.name)
+		}
+	}
+	return appId
+}
+
+// getAppIdByLabels reads the app of an object from the labels set by devtron charts, the app has to be deployed
+// on the environment
+func getAppIdByLabels(labels map[string]string, env *environment) int {
+	if appId, err := strconv.Atoi(labels[bean.DevtronAppIdLabel]); err == nil {
+		if envId, err := strconv.Atoi(labels[bean.DevtronEnvIdLabel]); err == nil && envId != env.id {
+			return 0
+		}
+		for _, r := range env.releases {
+			if r.appId == appId {
+				return appId
+			}
+		}
+	}
+	if releaseName, ok := labels[bean.ReleaseLabel]; ok {
+		for _, r := range env.releases {
+			if r.name == releaseName {
+				return r.appId
+			}
+		}
+	}
+	return 0
+}
+
+// getEventTimes returns when an event was first and last seen and how many times, events of the events.k8s.io
+// api only set the event time and a series once repeated
+func getEventTimes(event *v1.Event) (time.Time, time.Time, int32) {
+	firstSeen := event.FirstTimestamp.Time
+	if firstSeen.IsZero() {
+		firstSeen = event.EventTime.Time
+	}
+	if firstSeen.IsZero() {
+		firstSeen = event.CreationTimestamp.Time
+	}
+	lastSeen, count := event.LastTimestamp.Time, event.Count
+	if event.Series != nil {
+		if lastSeen.IsZero() {
+			lastSeen = event.Series.LastObservedTime.Time
+		}
+		if count == 0 {
+			count = event.Series.Count
+		}
+	}
+	if lastSeen.IsZero() {
+		lastSeen = firstSeen
+	}
+	if count == 0 {
+		count = 1
+	}
+	return firstSeen, lastSeen, count
+}
+
+func toEventModel(event *v1.Event, env *environment) *repository.K8sEventArchive {
+	firstSeen, lastSeen, count := getEventTimes(event)
+	source := event.Source.Component
+	if len(source) == 0 {
+		source = event.ReportingController
+	}
+	return &repository.K8sEventArchive{
+		ClusterId:     env.clusterId,
+		EnvironmentId: env.id,
+		Namespace:     event.Namespace,
+		EventUid:      string(event.UID),
+		InvolvedKind:  event.InvolvedObject.Kind,
+		InvolvedName:  event.InvolvedObject.Name,
+		Reason:        event.Reason,
+		Message:       event.Message,
+		Type:          event.Type,
+		Count:         count,
+		FirstSeen:     firstSeen,
+		LastSeen:      lastSeen,
+		Source:        source,
+	}
+}
+
+// buildTimeline merges the events and the deployment statuses in the order of time, an event is placed at the time
+// it was last seen
+func buildTimeline(events []*repository.K8sEventArchive, deployments []*status.PipelineStatusTimelineDto) []*bean.TimelineEntry {
+	entries := make([]*bean.TimelineEntry, 0, len(events)+len(deployments))
+	for _, event := range events {
+		entries = append(entries, &bean.TimelineEntry{
+			Type: bean.TimelineEntryTypeEvent,
+			Time: event.LastSeen,
+			Event: &bean.ArchivedEventDto{
+				InvolvedKind: event.InvolvedKind,
+				InvolvedName: event.InvolvedName,
+				Reason:       event.Reason,
+				Message:      event.Message,
+				Type:         event.Type,
+				Count:        event.Count,
+				FirstSeen:    event.FirstSeen,
+				LastSeen:     event.LastSeen,
+				Source:       event.Source,
+			},
+		})
+	}
+	for _, deployment := range deployments {
+		entries = append(entries, &bean.TimelineEntry{
+			Type:       bean.TimelineEntryTypeDeployment,
+			Time:       deployment.StatusTime,
+			Deployment: deployment,
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	return entries
+}
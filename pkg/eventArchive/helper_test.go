@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventArchive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/app/status"
+	"github.com/devtron-labs/devtron/pkg/eventArchive/bean"
+	"github.com/devtron-labs/devtron/pkg/eventArchive/repository"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getTestEnvironment() *environment {
+	return &environment{
+		id:        5,
+		clusterId: 1,
+		namespace: "prod",
+		releases: []*release{
+			{appId: 10, name: "payments-prod"},
+			{appId: 11, name: "payments-prod-worker"},
+		},
+	}
+}
+
+func TestBuildEnvironments(t *testing.T) {
+	rows := []*repository.EnvironmentRelease{
+		{EnvironmentId: 5, ClusterId: 1, ClusterName: "default_cluster", Namespace: "prod", AppId: 10, DeploymentAppName: "payments-prod"},
+		{EnvironmentId: 5, ClusterId: 1, ClusterName: "default_cluster", Namespace: "prod", AppId: 11, DeploymentAppName: "payments-prod-worker"},
+		{EnvironmentId: 6, ClusterId: 1, ClusterName: "default_cluster", Namespace: "prod", AppId: 12, DeploymentAppName: "orders-prod"},
+		{EnvironmentId: 7, ClusterId: 2, ClusterName: "staging", Namespace: "qa"},
+	}
+	environments := buildEnvironments(rows)
+	assert.Len(t, environments, 2)
+	prod := environments[getEnvironmentKey("default_cluster", "prod")]
+	assert.Equal(t, 5, prod.id)
+	assert.Len(t, prod.releases, 2, "releases of a second environment of the namespace are left out")
+	qa := environments[getEnvironmentKey("staging", "qa")]
+	assert.Equal(t, 7, qa.id)
+	assert.Empty(t, qa.releases)
+}
+
+func TestGetAppIdByName(t *testing.T) {
+	releases := getTestEnvironment().releases
+	tests := []struct {
+		name     string
+		object   string
+		expected int
+	}{
+		{name: "deployment named after release", object: "payments-prod", expected: 10},
+		{name: "pod of release", object: "payments-prod-7d9f8b-x2k4q", expected: 10},
+		{name: "longest release wins", object: "payments-prod-worker-5c6d7-abcde", expected: 11},
+		{name: "name sharing a prefix only", object: "payments-production", expected: 0},
+		{name: "other object", object: "redis-0", expected: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, getAppIdByName(tt.object, releases))
+		})
+	}
+}
+
+func TestGetAppIdByLabels(t *testing.T) {
+	env := getTestEnvironment()
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		expected int
+	}{
+		{name: "app and env labels", labels: map[string]string{"appId": "11", "envId": "5"}, expected: 11},
+		{name: "app label without env", labels: map[string]string{"appId": "10"}, expected: 10},
+		{name: "app of another env", labels: map[string]string{"appId": "10", "envId": "6"}, expected: 0},
+		{name: "app not deployed on env", labels: map[string]string{"appId": "99"}, expected: 0},
+		{name: "release label", labels: map[string]string{"release": "payments-prod-worker"}, expected: 11},
+		{name: "no labels", labels: nil, expected: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, getAppIdByLabels(tt.labels, env))
+		})
+	}
+}
+
+func TestGetEventTimes(t *testing.T) {
+	first := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	last := first.Add(10 * time.Minute)
+
+	coreEvent := &v1.Event{FirstTimestamp: metav1.NewTime(first), LastTimestamp: metav1.NewTime(last), Count: 4}
+	firstSeen, lastSeen, count := getEventTimes(coreEvent)
+	assert.Equal(t, first, firstSeen)
+	assert.Equal(t, last, lastSeen)
+	assert.Equal(t, int32(4), count)
+
+	seriesEvent := &v1.Event{
+		EventTime: metav1.NewMicroTime(first),
+		Series:    &v1.EventSeries{Count: 3, LastObservedTime: metav1.NewMicroTime(last)},
+	}
+	firstSeen, lastSeen, count = getEventTimes(seriesEvent)
+	assert.Equal(t, first, firstSeen)
+	assert.Equal(t, last, lastSeen)
+	assert.Equal(t, int32(3), count)
+
+	newEvent := &v1.Event{EventTime: metav1.NewMicroTime(first)}
+	firstSeen, lastSeen, count = getEventTimes(newEvent)
+	assert.Equal(t, first, firstSeen)
+	assert.Equal(t, first, lastSeen)
+	assert.Equal(t, int32(1), count)
+}
+
+func TestBuildTimeline(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	events := []*repository.K8sEventArchive{
+		{Reason: "BackOff", FirstSeen: start.Add(2 * time.Minute), LastSeen: start.Add(9 * time.Minute), Count: 5},
+		{Reason: "Pulled", FirstSeen: start.Add(3 * time.Minute), LastSeen: start.Add(3 * time.Minute), Count: 1},
+	}
+	deployments := []*status.PipelineStatusTimelineDto{
+		{Status: "DEPLOYMENT_INITIATED", StatusTime: start},
+		{Status: "HEALTHY", StatusTime: start.Add(5 * time.Minute)},
+	}
+	entries := buildTimeline(events, deployments)
+	assert.Len(t, entries, 4)
+	assert.Equal(t, bean.TimelineEntryTypeDeployment, entries[0].Type)
+	assert.Equal(t, "Pulled", entries[1].Event.Reason)
+	assert.Equal(t, bean.TimelineEntryTypeDeployment, entries[2].Type)
+	assert.Equal(t, "BackOff", entries[3].Event.Reason)
+	assert.Equal(t, int32(5), entries[3].Event.Count)
+	assert.Equal(t, start.Add(9*time.Minute), entries[3].Time)
+}
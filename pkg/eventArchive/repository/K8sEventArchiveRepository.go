@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"time"
+
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// K8sEventArchive is a kubernetes event of a namespace of an environment, it is kept after the api server drops it.
+// An event repeating updates its row, app id is 0 for events of objects which are not of a devtron app
+type K8sEventArchive struct {
+	tableName     struct{}  `sql:"k8s_event_archive" pg:",discard_unknown_columns"`
+	Id            int       `sql:"id,pk"`
+	ClusterId     int       `sql:"cluster_id,notnull"`
+	EnvironmentId int       `sql:"environment_id,notnull"`
+	AppId         int       `sql:"app_id,notnull"`
+	Namespace     string    `sql:"namespace,notnull"`
+	EventUid      string    `sql:"event_uid,notnull"`
+	InvolvedKind  string    `sql:"involved_kind"`
+	InvolvedName  string    `sql:"involved_name"`
+	Reason        string    `sql:"reason"`
+	Message       string    `sql:"message"`
+	Type          string    `sql:"type"`
+	Count         int32     `sql:"count,notnull"`
+	FirstSeen     time.Time `sql:"first_seen,notnull"`
+	LastSeen      time.Time `sql:"last_seen,notnull"`
+	Source        string    `sql:"source"`
+}
+
+// EnvironmentRelease is an active environment along with a release of a devtron app deployed on it,
+// app id is 0 for an environment without a cd pipeline
+type EnvironmentRelease struct {
+	EnvironmentId     int    `sql:"environment_id"`
+	ClusterId         int    `sql:"cluster_id"`
+	ClusterName       string `sql:"cluster_name"`
+	Namespace         string `sql:"namespace"`
+	AppId             int    `sql:"app_id"`
+	DeploymentAppName string `sql:"deployment_app_name"`
+}
+
+type K8sEventArchiveRepository interface {
+	// UpsertAll saves the events, the count, last seen time and message of the ones already saved are updated
+	UpsertAll(models []*K8sEventArchive) error
+	// FindByAppIdAndEnvId returns the latest events of an app on an environment seen in [from, to)
+	FindByAppIdAndEnvId(appId, envId int, from, to time.Time, limit int) ([]*K8sEventArchive, error)
+	DeleteLastSeenBefore(before time.Time) (int, error)
+	FindEnvironmentReleases() ([]*EnvironmentRelease, error)
+}
+
+type K8sEventArchiveRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewK8sEventArchiveRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *K8sEventArchiveRepositoryImpl {
+	return &K8sEventArchiveRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *K8sEventArchiveRepositoryImpl) UpsertAll(models []*K8sEventArchive) error {
+	if len(models) == 0 {
+		return nil
+	}
+	_, err := impl.dbConnection.Model(&models).
+		OnConflict("(cluster_id, event_uid) DO UPDATE").
+		Set("app_id = EXCLUDED.app_id").
+		Set("message = EXCLUDED.message").
+		Set("count = EXCLUDED.count").
+		Set("last_seen = EXCLUDED.last_seen").
+		Insert()
+	return err
+}
+
+func (impl *K8sEventArchiveRepositoryImpl) FindByAppIdAndEnvId(appId, envId int, from, to time.Time, limit int) ([]*K8sEventArchive, error) {
+	var models []*K8sEventArchive
+	err := impl.dbConnection.Model(&models).
+		Where("app_id = ?", appId).
+		Where("environment_id = ?", envId).
+		Where("last_seen >= ?", from).
+		Where("first_seen < ?", to).
+		Order("last_seen DESC").
+		Limit(limit).
+		Select()
+	return models, err
+}
+
+func (impl *K8sEventArchiveRepositoryImpl) DeleteLastSeenBefore(before time.Time) (int, error) {
+	result, err := impl.dbConnection.Model(&K8sEventArchive{}).Where("last_seen < ?", before).Delete()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+func (impl *K8sEventArchiveRepositoryImpl) FindEnvironmentReleases() ([]*EnvironmentRelease, error) {
+	var releases []*EnvironmentRelease
+	query := `SELECT e.id AS environment_id, e.cluster_id, c.cluster_name, e.namespace, p.app_id, p.deployment_app_name
+		FROM environment e
+		INNER JOIN cluster c ON c.id = e.cluster_id AND c.active = true
+		LEFT JOIN pipeline p ON p.environment_id = e.id AND p.deleted = false
+		WHERE e.active = true AND e.is_virtual_environment = false`
+	_, err := impl.dbConnection.Query(&releases, query)
+	return releases, err
+}
@@ -46,6 +46,7 @@ import (
 	eventProcessorBean "github.com/devtron-labs/devtron/pkg/eventProcessor/out/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	"github.com/devtron-labs/devtron/pkg/pipeline/executors"
+	"github.com/devtron-labs/devtron/pkg/previewEnvironment"
 	"github.com/devtron-labs/devtron/pkg/workflow/cd"
 	"github.com/devtron-labs/devtron/pkg/workflow/cd/adapter"
 	cdWorkflowBean "github.com/devtron-labs/devtron/pkg/workflow/cd/bean"
@@ -86,6 +87,7 @@ type WorkflowEventProcessorImpl struct {
 	cdWorkflowCommonService      cd.CdWorkflowCommonService
 	cdPipelineConfigService      pipeline.CdPipelineConfigService
 	userDeploymentRequestService service.UserDeploymentRequestService
+	previewEnvironmentService    previewEnvironment.PreviewEnvironmentService
 
 	devtronAppReleaseContextMap     map[int]bean.DevtronAppReleaseContextType
 	devtronAppReleaseContextMapLock *sync.Mutex
@@ -118,7 +120,8 @@ func NewWorkflowEventProcessorImpl(logger *zap.SugaredLogger,
 	pipelineRepository pipelineConfig.PipelineRepository,
 	ciArtifactRepository repository.CiArtifactRepository,
 	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
-	deploymentConfigService common.DeploymentConfigService) (*WorkflowEventProcessorImpl, error) {
+	deploymentConfigService common.DeploymentConfigService,
+	previewEnvironmentService previewEnvironment.PreviewEnvironmentService) (*WorkflowEventProcessorImpl, error) {
 	impl := &WorkflowEventProcessorImpl{
 		logger:                          logger,
 		pubSubClient:                    pubSubClient,
@@ -145,6 +148,7 @@ func NewWorkflowEventProcessorImpl(logger *zap.SugaredLogger,
 		ciArtifactRepository:            ciArtifactRepository,
 		cdWorkflowRepository:            cdWorkflowRepository,
 		deploymentConfigService:         deploymentConfigService,
+		previewEnvironmentService:       previewEnvironmentService,
 	}
 	appServiceConfig, err := app.GetAppServiceConfig()
 	if err != nil {
@@ -617,6 +621,8 @@ func (impl *WorkflowEventProcessorImpl) validateAndHandleCiSuccessEvent(triggerC
 			ciPipelineId, "request", request, "error", err)
 		return 0, err
 	}
+	// builds of pull requests are deployed in their preview environment, if configured for the ci pipeline
+	impl.previewEnvironmentService.HandleCiArtifact(ciPipelineId, buildArtifactId)
 	return buildArtifactId, nil
 }
 
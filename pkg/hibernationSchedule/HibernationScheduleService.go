@@ -0,0 +1,552 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hibernationSchedule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/internal/sql/repository/resourceGroup"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	bean2 "github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/bulkAction"
+	envRepository "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	devtronResourceBean "github.com/devtron-labs/devtron/pkg/devtronResource/bean"
+	"github.com/devtron-labs/devtron/pkg/devtronResource/read"
+	"github.com/devtron-labs/devtron/pkg/hibernationSchedule/bean"
+	"github.com/devtron-labs/devtron/pkg/hibernationSchedule/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	eventUtil "github.com/devtron-labs/devtron/util/event"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type HibernationScheduleService interface {
+	// CreateOrUpdate saves the schedule, it takes effect from its next sleep or wake event
+	CreateOrUpdate(request *bean.HibernationScheduleRequest) (*bean.HibernationScheduleDto, error)
+	// GetAll returns active schedules, of the environment when envId is set
+	GetAll(envId int) ([]*bean.HibernationScheduleDto, error)
+	GetById(id int) (*bean.HibernationScheduleDto, error)
+	Delete(id int, userId int32) error
+	// KeepAwake skips the sleep events of the schedule until the requested time,
+	// the apps of a hibernated schedule are un-hibernated right away
+	KeepAwake(request *bean.KeepAwakeRequest) (*bean.HibernationScheduleDto, error)
+	GetExecutions(id int, offset, limit int) ([]*bean.HibernationScheduleExecutionDto, error)
+	// GetEnvironmentIdentifiers returns the rbac objects of the environments hibernated through the environment or the resource group
+	GetEnvironmentIdentifiers(environmentId, resourceGroupId int) ([]string, error)
+	ExecuteDueSchedules()
+}
+
+type HibernationScheduleServiceImpl struct {
+	logger                                 *zap.SugaredLogger
+	hibernationScheduleRepository          repository.HibernationScheduleRepository
+	hibernationScheduleExecutionRepository repository.HibernationScheduleExecutionRepository
+	environmentRepository                  envRepository.EnvironmentRepository
+	resourceGroupRepository                resourceGroup.ResourceGroupRepository
+	resourceGroupMappingRepository         resourceGroup.ResourceGroupMappingRepository
+	devtronResourceSearchableKeyService    read.DevtronResourceSearchableKeyService
+	bulkUpdateService                      bulkAction.BulkUpdateService
+	eventClient                            client.EventClient
+	// executionLock keeps executions from overlapping when an execution outlasts the cron interval
+	executionLock *sync.Mutex
+}
+
+func NewHibernationScheduleServiceImpl(logger *zap.SugaredLogger,
+	hibernationScheduleRepository repository.HibernationScheduleRepository,
+	hibernationScheduleExecutionRepository repository.HibernationScheduleExecutionRepository,
+	environmentRepository envRepository.EnvironmentRepository,
+	resourceGroupRepository resourceGroup.ResourceGroupRepository,
+	resourceGroupMappingRepository resourceGroup.ResourceGroupMappingRepository,
+	devtronResourceSearchableKeyService read.DevtronResourceSearchableKeyService,
+	bulkUpdateService bulkAction.BulkUpdateService,
+	eventClient client.EventClient,
+	cronLogger *cron2.CronLoggerImpl) (*HibernationScheduleServiceImpl, error) {
+	serviceConfig := &bean.HibernationScheduleServiceConfig{}
+	err := env.Parse(serviceConfig)
+	if err != nil {
+		logger.Errorw("error in parsing hibernation schedule config", "err", err)
+		return nil, err
+	}
+	impl := &HibernationScheduleServiceImpl{
+		logger:                                 logger,
+		hibernationScheduleRepository:          hibernationScheduleRepository,
+		hibernationScheduleExecutionRepository: hibernationScheduleExecutionRepository,
+		environmentRepository:                  environmentRepository,
+		resourceGroupRepository:                resourceGroupRepository,
+		resourceGroupMappingRepository:         resourceGroupMappingRepository,
+		devtronResourceSearchableKeyService:    devtronResourceSearchableKeyService,
+		bulkUpdateService:                      bulkUpdateService,
+		eventClient:                            eventClient,
+		executionLock:                          &sync.Mutex{},
+	}
+	executionCron := cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	_, err = executionCron.AddFunc(serviceConfig.CronSchedule, impl.ExecuteDueSchedules)
+	if err != nil {
+		logger.Errorw("error in adding hibernation schedule cron", "schedule", serviceConfig.CronSchedule, "err", err)
+		return nil, err
+	}
+	executionCron.Start()
+	return impl, nil
+}
+
+func (impl *HibernationScheduleServiceImpl) CreateOrUpdate(request *bean.HibernationScheduleRequest) (*bean.HibernationScheduleDto, error) {
+	err := validateScheduleRequest(request)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	if request.EnvironmentId > 0 {
+		_, err = impl.environmentRepository.FindById(request.EnvironmentId)
+	} else {
+		_, err = impl.resourceGroupRepository.FindById(request.ResourceGroupId)
+	}
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching schedule target", "environmentId", request.EnvironmentId, "resourceGroupId", request.ResourceGroupId, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) {
+		errMsg := fmt.Sprintf("environment %d or resource group %d not found", request.EnvironmentId, request.ResourceGroupId)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	model := &repository.HibernationSchedule{State: string(bean.HibernationStateAwake)}
+	if request.Id > 0 {
+		model, err = impl.getSchedule(request.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	model.Name = request.Name
+	model.EnvironmentId = request.EnvironmentId
+	model.ResourceGroupId = request.ResourceGroupId
+	model.Timezone = request.Timezone
+	model.SleepTime = request.SleepTime
+	model.WakeTime = request.WakeTime
+	model.WeekDays = request.WeekDays
+	model.ExcludedAppIds = request.ExcludedAppIds
+	// events already past under the previous timings are not acted upon
+	model.LastEventOn = time.Now()
+	model.Active = true
+	if model.Id > 0 {
+		model.UpdateAuditLog(request.UserId)
+		err = impl.hibernationScheduleRepository.Update(model)
+	} else {
+		model.AuditLog = sql.NewDefaultAuditLog(request.UserId)
+		err = impl.hibernationScheduleRepository.Save(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving hibernation schedule", "model", model, "err", err)
+		return nil, err
+	}
+	return impl.toScheduleDto(model)
+}
+
+func (impl *HibernationScheduleServiceImpl) GetAll(envId int) ([]*bean.HibernationScheduleDto, error) {
+	var models []*repository.HibernationSchedule
+	var err error
+	if envId > 0 {
+		models, err = impl.hibernationScheduleRepository.FindActiveByEnvironmentId(envId)
+	} else {
+		models, err = impl.hibernationScheduleRepository.FindAllActive()
+	}
+	if err != nil {
+		impl.logger.Errorw("error in fetching hibernation schedules", "envId", envId, "err", err)
+		return nil, err
+	}
+	dtos := make([]*bean.HibernationScheduleDto, 0, len(models))
+	for _, model := range models {
+		dto, err := impl.toScheduleDto(model)
+		if err != nil {
+			return nil, err
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos, nil
+}
+
+func (impl *HibernationScheduleServiceImpl) GetById(id int) (*bean.HibernationScheduleDto, error) {
+	model, err := impl.getSchedule(id)
+	if err != nil {
+		return nil, err
+	}
+	return impl.toScheduleDto(model)
+}
+
+func (impl *HibernationScheduleServiceImpl) Delete(id int, userId int32) error {
+	model, err := impl.getSchedule(id)
+	if err != nil {
+		return err
+	}
+	model.Active = false
+	model.UpdateAuditLog(userId)
+	err = impl.hibernationScheduleRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in deleting hibernation schedule", "id", id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *HibernationScheduleServiceImpl) KeepAwake(request *bean.KeepAwakeRequest) (*bean.HibernationScheduleDto, error) {
+	if !request.KeepAwakeUntil.After(time.Now()) {
+		errMsg := "keepAwakeUntil must be in the future"
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	impl.executionLock.Lock()
+	defer impl.executionLock.Unlock()
+	model, err := impl.getSchedule(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	model.KeepAwakeUntil = &request.KeepAwakeUntil
+	if model.State == string(bean.HibernationStateHibernated) {
+		targets, err := impl.getTargets(model)
+		if err != nil {
+			impl.logger.Errorw("error in fetching targets of hibernation schedule", "id", model.Id, "err", err)
+			return nil, err
+		}
+		impl.execute(model, targets, bean.HibernationActionUnHibernate, bean.ExecutionTriggerKeepAwake, request.UserId)
+	}
+	err = impl.hibernationScheduleRepository.UpdateExecutionState(model)
+	if err != nil {
+		impl.logger.Errorw("error in updating keep awake of hibernation schedule", "id", model.Id, "err", err)
+		return nil, err
+	}
+	return impl.toScheduleDto(model)
+}
+
+func (impl *HibernationScheduleServiceImpl) GetExecutions(id int, offset, limit int) ([]*bean.HibernationScheduleExecutionDto, error) {
+	if limit <= 0 {
+		limit = bean.DefaultExecutionLimit
+	} else if limit > bean.MaxExecutionListLimit {
+		limit = bean.MaxExecutionListLimit
+	}
+	models, err := impl.hibernationScheduleExecutionRepository.FindByScheduleId(id, offset, limit)
+	if err != nil {
+		impl.logger.Errorw("error in fetching hibernation schedule executions", "id", id, "err", err)
+		return nil, err
+	}
+	dtos := make([]*bean.HibernationScheduleExecutionDto, 0, len(models))
+	for _, model := range models {
+		dto := &bean.HibernationScheduleExecutionDto{
+			Id:                    model.Id,
+			HibernationScheduleId: model.HibernationScheduleId,
+			EnvironmentId:         model.EnvironmentId,
+			Action:                bean.HibernationAction(model.Action),
+			Trigger:               bean.ExecutionTrigger(model.Trigger),
+			Status:                bean.ExecutionStatus(model.Status),
+			Message:               model.Message,
+			ExecutedOn:            model.CreatedOn,
+		}
+		if len(model.AppResponse) > 0 {
+			err = json.Unmarshal([]byte(model.AppResponse), &dto.AppResponse)
+			if err != nil {
+				impl.logger.Errorw("error in unmarshalling app response of execution", "executionId", model.Id, "err", err)
+			}
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos, nil
+}
+
+func (impl *HibernationScheduleServiceImpl) GetEnvironmentIdentifiers(environmentId, resourceGroupId int) ([]string, error) {
+	envIds := []int{environmentId}
+	if resourceGroupId > 0 {
+		isEnvGroup, parentResourceId, mappedResourceIds, err := impl.getResourceGroup(resourceGroupId)
+		if err != nil {
+			return nil, err
+		}
+		if isEnvGroup {
+			envIds = []int{parentResourceId}
+		} else {
+			envIds = mappedResourceIds
+		}
+	}
+	identifiers := make([]string, 0, len(envIds))
+	for _, envId := range envIds {
+		environment, err := impl.environmentRepository.FindById(envId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment", "envId", envId, "err", err)
+			return nil, err
+		}
+		identifiers = append(identifiers, environment.EnvironmentIdentifier)
+	}
+	return identifiers, nil
+}
+
+func (impl *HibernationScheduleServiceImpl) ExecuteDueSchedules() {
+	if !impl.executionLock.TryLock() {
+		impl.logger.Infow("previous hibernation schedule execution in progress, skipping")
+		return
+	}
+	defer impl.executionLock.Unlock()
+	schedules, err := impl.hibernationScheduleRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in fetching hibernation schedules", "err", err)
+		return
+	}
+	now := time.Now()
+	for _, schedule := range schedules {
+		impl.executeIfDue(schedule, now)
+	}
+}
+
+// executeIfDue acts on the latest event of the schedule if it wasn't acted upon yet. Sleep events are skipped
+// while the schedule is kept awake, once the keep awake override expires the apps are hibernated
+// if the schedule would have been sleeping by then. The targets are resolved before the event is marked
+// as acted upon so that the event is retried by the next run when they can't be resolved
+func (impl *HibernationScheduleServiceImpl) executeIfDue(schedule *repository.HibernationSchedule, now time.Time) {
+	latestEvent, err := getLatestEvent(schedule, now)
+	if err != nil {
+		impl.logger.Errorw("error in evaluating hibernation schedule", "id", schedule.Id, "err", err)
+		return
+	}
+	keepAwakeExpired := schedule.KeepAwakeUntil != nil && !now.Before(*schedule.KeepAwakeUntil)
+	isNewEvent := latestEvent != nil && latestEvent.Time.After(schedule.LastEventOn)
+	if !keepAwakeExpired && !isNewEvent {
+		return
+	}
+	targets, err := impl.getTargets(schedule)
+	if err != nil {
+		impl.logger.Errorw("error in fetching targets of hibernation schedule", "id", schedule.Id, "err", err)
+		return
+	}
+	if keepAwakeExpired {
+		schedule.KeepAwakeUntil = nil
+		schedule.LastEventOn = now
+		if latestEvent != nil && latestEvent.Action == bean.HibernationActionHibernate && schedule.State == string(bean.HibernationStateAwake) {
+			impl.execute(schedule, targets, latestEvent.Action, bean.ExecutionTriggerScheduled, userBean.SYSTEM_USER_ID)
+		}
+	} else {
+		schedule.LastEventOn = latestEvent.Time
+		if latestEvent.Action == bean.HibernationActionHibernate && schedule.KeepAwakeUntil != nil {
+			message := fmt.Sprintf("kept awake until %s", schedule.KeepAwakeUntil.Format(time.RFC3339))
+			impl.saveSkippedExecutions(schedule, targets, latestEvent.Action, message)
+		} else {
+			impl.execute(schedule, targets, latestEvent.Action, bean.ExecutionTriggerScheduled, userBean.SYSTEM_USER_ID)
+		}
+	}
+	err = impl.hibernationScheduleRepository.UpdateExecutionState(schedule)
+	if err != nil {
+		impl.logger.Errorw("error in updating execution state of hibernation schedule", "id", schedule.Id, "err", err)
+	}
+}
+
+// execute hibernates or un-hibernates the apps of the schedule in each of its environments, recording and notifying
+// the outcome per environment. Rbac isn't enforced here as it is enforced on the environments when the schedule is saved
+func (impl *HibernationScheduleServiceImpl) execute(schedule *repository.HibernationSchedule, targets []*bean.HibernationTarget,
+	action bean.HibernationAction, trigger bean.ExecutionTrigger, userId int32) {
+	skipAuth := func(token string, appObject string, envObject string) bool { return true }
+	ctx := context.Background()
+	for _, target := range targets {
+		request := &bulkAction.BulkApplicationForEnvironmentPayload{
+			EnvId:         target.EnvironmentId,
+			AppIdIncludes: target.AppIdIncludes,
+			AppIdExcludes: target.AppIdExcludes,
+			UserId:        userId,
+		}
+		var response *bulkAction.BulkApplicationHibernateUnhibernateForEnvironmentResponse
+		var err error
+		if action == bean.HibernationActionHibernate {
+			response, err = impl.bulkUpdateService.BulkHibernate(request, ctx, nil, "", skipAuth)
+		} else {
+			response, err = impl.bulkUpdateService.BulkUnHibernate(request, ctx, nil, "", skipAuth)
+		}
+		execution := &repository.HibernationScheduleExecution{
+			HibernationScheduleId: schedule.Id,
+			EnvironmentId:         target.EnvironmentId,
+			Action:                string(action),
+			Trigger:               string(trigger),
+			AuditLog:              sql.NewDefaultAuditLog(userId),
+		}
+		if err != nil {
+			impl.logger.Errorw("error in executing hibernation schedule", "id", schedule.Id, "envId", target.EnvironmentId, "action", action, "err", err)
+			execution.Status = string(bean.ExecutionStatusFailed)
+			execution.Message = err.Error()
+		} else {
+			execution.Status = string(getExecutionStatus(response.Response))
+			appResponse, err := json.Marshal(response.Response)
+			if err != nil {
+				impl.logger.Errorw("error in marshalling app response of execution", "id", schedule.Id, "err", err)
+			}
+			execution.AppResponse = string(appResponse)
+		}
+		impl.saveExecution(execution)
+		impl.notify(schedule, execution)
+	}
+	if action == bean.HibernationActionHibernate {
+		schedule.State = string(bean.HibernationStateHibernated)
+	} else {
+		schedule.State = string(bean.HibernationStateAwake)
+	}
+}
+
+func (impl *HibernationScheduleServiceImpl) saveSkippedExecutions(schedule *repository.HibernationSchedule, targets []*bean.HibernationTarget,
+	action bean.HibernationAction, message string) {
+	for _, target := range targets {
+		impl.saveExecution(&repository.HibernationScheduleExecution{
+			HibernationScheduleId: schedule.Id,
+			EnvironmentId:         target.EnvironmentId,
+			Action:                string(action),
+			Trigger:               string(bean.ExecutionTriggerScheduled),
+			Status:                string(bean.ExecutionStatusSkipped),
+			Message:               message,
+			AuditLog:              sql.NewDefaultAuditLog(userBean.SYSTEM_USER_ID),
+		})
+	}
+}
+
+func (impl *HibernationScheduleServiceImpl) saveExecution(execution *repository.HibernationScheduleExecution) {
+	err := impl.hibernationScheduleExecutionRepository.Save(execution)
+	if err != nil {
+		impl.logger.Errorw("error in saving hibernation schedule execution", "execution", execution, "err", err)
+	}
+}
+
+// notify sends the outcome of an execution to the notification settings of its environment
+func (impl *HibernationScheduleServiceImpl) notify(schedule *repository.HibernationSchedule, execution *repository.HibernationScheduleExecution) {
+	environment, err := impl.environmentRepository.FindById(execution.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment for hibernation notification", "envId", execution.EnvironmentId, "err", err)
+		return
+	}
+	eventType := eventUtil.Success
+	if execution.Status == string(bean.ExecutionStatusFailed) || execution.Status == string(bean.ExecutionStatusPartial) {
+		eventType = eventUtil.Fail
+	}
+	event := client.Event{
+		EventTypeId: int(eventType),
+		EventName:   bean.HibernationEventName,
+		EventTime:   time.Now().Format(bean2.LayoutRFC3339),
+		EnvId:       environment.Id,
+		ClusterId:   environment.ClusterId,
+		IsProdEnv:   environment.Default,
+		UserId:      int(execution.CreatedBy),
+		Payload: &client.Payload{
+			EnvName:       environment.Name,
+			Source:        schedule.Name,
+			Stage:         execution.Action,
+			TriggeredBy:   execution.Trigger,
+			FailureReason: execution.Message,
+		},
+	}
+	_, err = impl.eventClient.WriteNotificationEvent(event)
+	if err != nil {
+		impl.logger.Errorw("error in sending hibernation notification", "scheduleId", schedule.Id, "envId", environment.Id, "err", err)
+	}
+}
+
+func (impl *HibernationScheduleServiceImpl) getTargets(schedule *repository.HibernationSchedule) ([]*bean.HibernationTarget, error) {
+	if schedule.EnvironmentId > 0 {
+		return []*bean.HibernationTarget{{EnvironmentId: schedule.EnvironmentId, AppIdExcludes: schedule.ExcludedAppIds}}, nil
+	}
+	isEnvGroup, parentResourceId, mappedResourceIds, err := impl.getResourceGroup(schedule.ResourceGroupId)
+	if err != nil {
+		return nil, err
+	}
+	return getResourceGroupTargets(isEnvGroup, parentResourceId, mappedResourceIds, schedule.ExcludedAppIds), nil
+}
+
+// getResourceGroup returns whether the resource group is a group of apps in an environment,
+// along with its parent resource and the resources mapped to it
+func (impl *HibernationScheduleServiceImpl) getResourceGroup(resourceGroupId int) (bool, int, []int, error) {
+	group, err := impl.resourceGroupRepository.FindById(resourceGroupId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching resource group", "resourceGroupId", resourceGroupId, "err", err)
+		return false, 0, nil, err
+	}
+	mappings, err := impl.resourceGroupMappingRepository.FindByResourceGroupId(resourceGroupId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching resource group mappings", "resourceGroupId", resourceGroupId, "err", err)
+		return false, 0, nil, err
+	}
+	mappedResourceIds := make([]int, 0, len(mappings))
+	for _, mapping := range mappings {
+		mappedResourceIds = append(mappedResourceIds, mapping.ResourceId)
+	}
+	resourceKeyToId := impl.devtronResourceSearchableKeyService.GetAllSearchableKeyNameIdMap()
+	isEnvGroup := group.ResourceKey == resourceKeyToId[devtronResourceBean.DEVTRON_RESOURCE_SEARCHABLE_KEY_ENV_ID]
+	return isEnvGroup, group.ResourceId, mappedResourceIds, nil
+}
+
+func (impl *HibernationScheduleServiceImpl) getSchedule(id int) (*repository.HibernationSchedule, error) {
+	model, err := impl.hibernationScheduleRepository.FindById(id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching hibernation schedule", "id", id, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) {
+		errMsg := fmt.Sprintf("hibernation schedule %d not found", id)
+		return nil, util.NewApiError(http.StatusNotFound, errMsg, errMsg)
+	}
+	return model, nil
+}
+
+func (impl *HibernationScheduleServiceImpl) toScheduleDto(model *repository.HibernationSchedule) (*bean.HibernationScheduleDto, error) {
+	dto := &bean.HibernationScheduleDto{
+		Id:              model.Id,
+		Name:            model.Name,
+		EnvironmentId:   model.EnvironmentId,
+		ResourceGroupId: model.ResourceGroupId,
+		Timezone:        model.Timezone,
+		SleepTime:       model.SleepTime,
+		WakeTime:        model.WakeTime,
+		WeekDays:        model.WeekDays,
+		ExcludedAppIds:  model.ExcludedAppIds,
+		KeepAwakeUntil:  model.KeepAwakeUntil,
+		State:           bean.HibernationState(model.State),
+		CreatedBy:       model.CreatedBy,
+		UpdatedOn:       model.UpdatedOn,
+	}
+	if model.EnvironmentId > 0 {
+		environment, err := impl.environmentRepository.FindById(model.EnvironmentId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment", "envId", model.EnvironmentId, "err", err)
+			return nil, err
+		}
+		dto.EnvironmentName = environment.Name
+	} else {
+		group, err := impl.resourceGroupRepository.FindById(model.ResourceGroupId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching resource group", "resourceGroupId", model.ResourceGroupId, "err", err)
+			return nil, err
+		}
+		if group != nil {
+			dto.ResourceGroupName = group.Name
+		}
+	}
+	environmentIdentifiers, err := impl.GetEnvironmentIdentifiers(model.EnvironmentId, model.ResourceGroupId)
+	if err != nil && !util.IsErrNoRows(err) {
+		return nil, err
+	}
+	dto.EnvironmentIdentifiers = environmentIdentifiers
+	nextEvent, err := getNextEvent(model, time.Now())
+	if err != nil {
+		impl.logger.Errorw("error in evaluating next event of hibernation schedule", "id", model.Id, "err", err)
+		return nil, err
+	}
+	if nextEvent != nil {
+		dto.NextEventAction = string(nextEvent.Action)
+		dto.NextEventOn = &nextEvent.Time
+	}
+	return dto, nil
+}
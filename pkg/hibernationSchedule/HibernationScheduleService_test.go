@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hibernationSchedule
+
+import (
+	"errors"
+	resourceGroupMocks "github.com/devtron-labs/devtron/internal/sql/repository/resourceGroup/mocks"
+	"github.com/devtron-labs/devtron/pkg/hibernationSchedule/bean"
+	"github.com/devtron-labs/devtron/pkg/hibernationSchedule/repository"
+	"github.com/devtron-labs/devtron/pkg/hibernationSchedule/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExecuteIfDue(t *testing.T) {
+	now := time.Date(2026, 10, 14, 21, 0, 0, 0, time.UTC)
+	sleepingSchedule := func() *repository.HibernationSchedule {
+		return &repository.HibernationSchedule{Id: 1, Timezone: "UTC", SleepTime: "20:00", WakeTime: "08:00",
+			State: string(bean.HibernationStateAwake), Active: true}
+	}
+	t.Run("event kept pending when the targets can't be resolved", func(tt *testing.T) {
+		scheduleRepository := mocks.NewHibernationScheduleRepository(tt)
+		resourceGroupRepository := resourceGroupMocks.NewResourceGroupRepository(tt)
+		resourceGroupRepository.On("FindById", 5).Return(nil, errors.New("connection refused"))
+		impl := &HibernationScheduleServiceImpl{logger: zap.NewNop().Sugar(), hibernationScheduleRepository: scheduleRepository,
+			resourceGroupRepository: resourceGroupRepository, executionLock: &sync.Mutex{}}
+		schedule := sleepingSchedule()
+		schedule.ResourceGroupId = 5
+		impl.executeIfDue(schedule, now)
+		assert.True(tt, schedule.LastEventOn.IsZero())
+		scheduleRepository.AssertNotCalled(tt, "UpdateExecutionState", mock.Anything)
+	})
+	t.Run("sleep event skipped while kept awake", func(tt *testing.T) {
+		scheduleRepository := mocks.NewHibernationScheduleRepository(tt)
+		executionRepository := mocks.NewHibernationScheduleExecutionRepository(tt)
+		impl := &HibernationScheduleServiceImpl{logger: zap.NewNop().Sugar(), hibernationScheduleRepository: scheduleRepository,
+			hibernationScheduleExecutionRepository: executionRepository, executionLock: &sync.Mutex{}}
+		schedule := sleepingSchedule()
+		schedule.EnvironmentId = 3
+		keepAwakeUntil := now.Add(time.Hour)
+		schedule.KeepAwakeUntil = &keepAwakeUntil
+		executionRepository.On("Save", mock.MatchedBy(func(execution *repository.HibernationScheduleExecution) bool {
+			return execution.EnvironmentId == 3 && execution.Status == string(bean.ExecutionStatusSkipped)
+		})).Return(nil)
+		scheduleRepository.On("UpdateExecutionState", schedule).Return(nil)
+		impl.executeIfDue(schedule, now)
+		assert.Equal(tt, time.Date(2026, 10, 14, 20, 0, 0, 0, time.UTC), schedule.LastEventOn)
+		assert.Equal(tt, string(bean.HibernationStateAwake), schedule.State)
+	})
+}
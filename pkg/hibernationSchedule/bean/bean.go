@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type HibernationAction string
+
+const (
+	HibernationActionHibernate   HibernationAction = "HIBERNATE"
+	HibernationActionUnHibernate HibernationAction = "UNHIBERNATE"
+)
+
+type HibernationState string
+
+const (
+	HibernationStateAwake      HibernationState = "AWAKE"
+	HibernationStateHibernated HibernationState = "HIBERNATED"
+)
+
+type ExecutionTrigger string
+
+const (
+	ExecutionTriggerScheduled ExecutionTrigger = "SCHEDULED"
+	ExecutionTriggerKeepAwake ExecutionTrigger = "KEEP_AWAKE"
+)
+
+type ExecutionStatus string
+
+const (
+	ExecutionStatusSucceeded ExecutionStatus = "Succeeded"
+	ExecutionStatusPartial   ExecutionStatus = "PartiallySucceeded"
+	ExecutionStatusFailed    ExecutionStatus = "Failed"
+	ExecutionStatusSkipped   ExecutionStatus = "Skipped"
+)
+
+const (
+	ClockTimeLayout       = "15:04"
+	HibernationEventName  = "Hibernation Schedule"
+	DefaultTimezone       = "UTC"
+	DefaultExecutionLimit = 20
+	MaxExecutionListLimit = 100
+)
+
+type HibernationScheduleServiceConfig struct {
+	CronSchedule string `env:"HIBERNATION_SCHEDULE_CRON" envDefault:"* * * * *" description:"Schedule of the job evaluating hibernation schedules, sleep and wake times are honoured at this granularity"`
+}
+
+type HibernationScheduleRequest struct {
+	Id              int    `json:"id"`
+	Name            string `json:"name" validate:"required,max=250"`
+	EnvironmentId   int    `json:"environmentId"`
+	ResourceGroupId int    `json:"resourceGroupId"`
+	Timezone        string `json:"timezone"`
+	// SleepTime and WakeTime are in HH:MM (24h) format
+	SleepTime string `json:"sleepTime" validate:"required"`
+	WakeTime  string `json:"wakeTime" validate:"required"`
+	// WeekDays on which sleep and wake events occur, 0 being sunday, all days when empty
+	WeekDays       []int `json:"weekDays" validate:"dive,min=0,max=6"`
+	ExcludedAppIds []int `json:"excludedAppIds"`
+	UserId         int32 `json:"-"`
+}
+
+type KeepAwakeRequest struct {
+	Id             int       `json:"-"`
+	KeepAwakeUntil time.Time `json:"keepAwakeUntil" validate:"required"`
+	UserId         int32     `json:"-"`
+}
+
+type HibernationScheduleDto struct {
+	Id                int              `json:"id"`
+	Name              string           `json:"name"`
+	EnvironmentId     int              `json:"environmentId,omitempty"`
+	EnvironmentName   string           `json:"environmentName,omitempty"`
+	ResourceGroupId   int              `json:"resourceGroupId,omitempty"`
+	ResourceGroupName string           `json:"resourceGroupName,omitempty"`
+	Timezone          string           `json:"timezone"`
+	SleepTime         string           `json:"sleepTime"`
+	WakeTime          string           `json:"wakeTime"`
+	WeekDays          []int            `json:"weekDays"`
+	ExcludedAppIds    []int            `json:"excludedAppIds"`
+	KeepAwakeUntil    *time.Time       `json:"keepAwakeUntil,omitempty"`
+	State             HibernationState `json:"state"`
+	NextEventAction   string           `json:"nextEventAction,omitempty"`
+	NextEventOn       *time.Time       `json:"nextEventOn,omitempty"`
+	CreatedBy         int32            `json:"createdBy"`
+	UpdatedOn         time.Time        `json:"updatedOn"`
+	// EnvironmentIdentifiers are the rbac objects of the environments hibernated by the schedule
+	EnvironmentIdentifiers []string `json:"-"`
+}
+
+type HibernationScheduleExecutionDto struct {
+	Id                    int               `json:"id"`
+	HibernationScheduleId int               `json:"hibernationScheduleId"`
+	EnvironmentId         int               `json:"environmentId"`
+	Action                HibernationAction `json:"action"`
+	Trigger               ExecutionTrigger  `json:"trigger"`
+	Status                ExecutionStatus   `json:"status"`
+	AppResponse           []map[string]any  `json:"appResponse"`
+	Message               string            `json:"message,omitempty"`
+	ExecutedOn            time.Time         `json:"executedOn"`
+}
+
+// ScheduleEvent is an occurrence of the sleep or wake time of a schedule
+type ScheduleEvent struct {
+	Action HibernationAction
+	Time   time.Time
+}
+
+// HibernationTarget is a set of apps hibernated together in an environment,
+// AppIdIncludes is empty when all apps of the environment other than AppIdExcludes are targeted
+type HibernationTarget struct {
+	EnvironmentId int
+	AppIdIncludes []int
+	AppIdExcludes []int
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hibernationSchedule
+
+import (
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/bulkAction"
+	"github.com/devtron-labs/devtron/pkg/hibernationSchedule/bean"
+	"github.com/devtron-labs/devtron/pkg/hibernationSchedule/repository"
+	"slices"
+	"time"
+)
+
+// eventLookupDays is the number of days scanned around a time for sleep and wake events,
+// a week and a day so that an event is found whatever the configured week days are
+const eventLookupDays = 8
+
+// validateScheduleRequest validates the request, defaulting its timezone to UTC
+func validateScheduleRequest(request *bean.HibernationScheduleRequest) error {
+	if (request.EnvironmentId > 0) == (request.ResourceGroupId > 0) {
+		return errors.New("exactly one of environmentId and resourceGroupId is required")
+	}
+	if len(request.Timezone) == 0 {
+		request.Timezone = bean.DefaultTimezone
+	}
+	_, err := time.LoadLocation(request.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q", request.Timezone)
+	}
+	sleepTime, err := time.Parse(bean.ClockTimeLayout, request.SleepTime)
+	if err != nil {
+		return fmt.Errorf("invalid sleepTime %q, expected HH:MM", request.SleepTime)
+	}
+	wakeTime, err := time.Parse(bean.ClockTimeLayout, request.WakeTime)
+	if err != nil {
+		return fmt.Errorf("invalid wakeTime %q, expected HH:MM", request.WakeTime)
+	}
+	if sleepTime.Equal(wakeTime) {
+		return errors.New("sleepTime and wakeTime must differ")
+	}
+	for _, weekDay := range request.WeekDays {
+		if weekDay < int(time.Sunday) || weekDay > int(time.Saturday) {
+			return fmt.Errorf("invalid week day %d, expected 0 (sunday) to 6 (saturday)", weekDay)
+		}
+	}
+	return nil
+}
+
+// getLatestEvent returns the latest sleep or wake event of the schedule at or before now
+func getLatestEvent(schedule *repository.HibernationSchedule, now time.Time) (*bean.ScheduleEvent, error) {
+	events, err := getEventsAround(schedule, now)
+	if err != nil {
+		return nil, err
+	}
+	var latest *bean.ScheduleEvent
+	for _, event := range events {
+		if event.Time.After(now) {
+			continue
+		}
+		if latest == nil || event.Time.After(latest.Time) {
+			latest = event
+		}
+	}
+	return latest, nil
+}
+
+// getNextEvent returns the first sleep or wake event of the schedule after now
+func getNextEvent(schedule *repository.HibernationSchedule, now time.Time) (*bean.ScheduleEvent, error) {
+	events, err := getEventsAround(schedule, now)
+	if err != nil {
+		return nil, err
+	}
+	var next *bean.ScheduleEvent
+	for _, event := range events {
+		if !event.Time.After(now) {
+			continue
+		}
+		if next == nil || event.Time.Before(next.Time) {
+			next = event
+		}
+	}
+	return next, nil
+}
+
+// getEventsAround returns the sleep and wake events of the schedule falling within eventLookupDays of now.
+// Events are evaluated on the wall clock of the schedule timezone, so they follow daylight saving changes
+func getEventsAround(schedule *repository.HibernationSchedule, now time.Time) ([]*bean.ScheduleEvent, error) {
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	sleepTime, err := time.Parse(bean.ClockTimeLayout, schedule.SleepTime)
+	if err != nil {
+		return nil, err
+	}
+	wakeTime, err := time.Parse(bean.ClockTimeLayout, schedule.WakeTime)
+	if err != nil {
+		return nil, err
+	}
+	localNow := now.In(location)
+	var events []*bean.ScheduleEvent
+	for offset := -eventLookupDays; offset <= eventLookupDays; offset++ {
+		day := time.Date(localNow.Year(), localNow.Month(), localNow.Day()+offset, 0, 0, 0, 0, location)
+		if len(schedule.WeekDays) > 0 && !slices.Contains(schedule.WeekDays, int(day.Weekday())) {
+			continue
+		}
+		events = append(events,
+			&bean.ScheduleEvent{
+				Action: bean.HibernationActionHibernate,
+				Time:   time.Date(day.Year(), day.Month(), day.Day(), sleepTime.Hour(), sleepTime.Minute(), 0, 0, location),
+			},
+			&bean.ScheduleEvent{
+				Action: bean.HibernationActionUnHibernate,
+				Time:   time.Date(day.Year(), day.Month(), day.Day(), wakeTime.Hour(), wakeTime.Minute(), 0, 0, location),
+			})
+	}
+	return events, nil
+}
+
+// getResourceGroupTargets returns the apps of a resource group to be hibernated per environment.
+// An env group is a group of apps in the environment parentResourceId, an app group a group of
+// environments of the app parentResourceId. Excluded apps are dropped from the targets
+func getResourceGroupTargets(isEnvGroup bool, parentResourceId int, mappedResourceIds []int, excludedAppIds []int) []*bean.HibernationTarget {
+	var targets []*bean.HibernationTarget
+	if isEnvGroup {
+		var appIds []int
+		for _, appId := range mappedResourceIds {
+			if !slices.Contains(excludedAppIds, appId) {
+				appIds = append(appIds, appId)
+			}
+		}
+		// empty app includes target all apps of the environment, so a group with every app excluded has no target
+		if len(appIds) > 0 {
+			targets = append(targets, &bean.HibernationTarget{EnvironmentId: parentResourceId, AppIdIncludes: appIds})
+		}
+		return targets
+	}
+	if slices.Contains(excludedAppIds, parentResourceId) {
+		return targets
+	}
+	for _, envId := range mappedResourceIds {
+		targets = append(targets, &bean.HibernationTarget{EnvironmentId: envId, AppIdIncludes: []int{parentResourceId}})
+	}
+	return targets
+}
+
+// getExecutionStatus derives the status of a bulk hibernate or un-hibernate operation from its per app response,
+// apps skipped as already in the desired state are counted as succeeded
+func getExecutionStatus(appResponses []map[string]any) bean.ExecutionStatus {
+	succeeded := 0
+	for _, appResponse := range appResponses {
+		if success, ok := appResponse["success"].(bool); ok && success {
+			succeeded++
+		} else if _, ok := appResponse[bulkAction.Skipped]; ok {
+			succeeded++
+		}
+	}
+	switch {
+	case succeeded == len(appResponses):
+		return bean.ExecutionStatusSucceeded
+	case succeeded == 0:
+		return bean.ExecutionStatusFailed
+	default:
+		return bean.ExecutionStatusPartial
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hibernationSchedule
+
+import (
+	"github.com/devtron-labs/devtron/pkg/hibernationSchedule/bean"
+	"github.com/devtron-labs/devtron/pkg/hibernationSchedule/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestValidateScheduleRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *bean.HibernationScheduleRequest
+		wantErr bool
+	}{
+		{name: "valid", request: &bean.HibernationScheduleRequest{EnvironmentId: 1, SleepTime: "20:00", WakeTime: "08:00", WeekDays: []int{1, 5}}},
+		{name: "no target", request: &bean.HibernationScheduleRequest{SleepTime: "20:00", WakeTime: "08:00"}, wantErr: true},
+		{name: "both targets", request: &bean.HibernationScheduleRequest{EnvironmentId: 1, ResourceGroupId: 2, SleepTime: "20:00", WakeTime: "08:00"}, wantErr: true},
+		{name: "invalid timezone", request: &bean.HibernationScheduleRequest{EnvironmentId: 1, Timezone: "Mars/Olympus", SleepTime: "20:00", WakeTime: "08:00"}, wantErr: true},
+		{name: "invalid time", request: &bean.HibernationScheduleRequest{EnvironmentId: 1, SleepTime: "8pm", WakeTime: "08:00"}, wantErr: true},
+		{name: "same times", request: &bean.HibernationScheduleRequest{EnvironmentId: 1, SleepTime: "08:00", WakeTime: "08:00"}, wantErr: true},
+		{name: "invalid week day", request: &bean.HibernationScheduleRequest{EnvironmentId: 1, SleepTime: "20:00", WakeTime: "08:00", WeekDays: []int{7}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateScheduleRequest(tt.request)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, bean.DefaultTimezone, tt.request.Timezone)
+		})
+	}
+}
+
+func TestGetLatestAndNextEvent(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	assert.NoError(t, err)
+	// sleep 20:00, wake 08:00 on weekdays
+	schedule := &repository.HibernationSchedule{Timezone: "Asia/Kolkata", SleepTime: "20:00", WakeTime: "08:00", WeekDays: []int{1, 2, 3, 4, 5}}
+	tests := []struct {
+		name       string
+		now        time.Time
+		wantLatest *bean.ScheduleEvent
+		wantNext   *bean.ScheduleEvent
+	}{
+		{
+			name:       "wednesday night",
+			now:        time.Date(2024, 5, 15, 23, 0, 0, 0, kolkata),
+			wantLatest: &bean.ScheduleEvent{Action: bean.HibernationActionHibernate, Time: time.Date(2024, 5, 15, 20, 0, 0, 0, kolkata)},
+			wantNext:   &bean.ScheduleEvent{Action: bean.HibernationActionUnHibernate, Time: time.Date(2024, 5, 16, 8, 0, 0, 0, kolkata)},
+		},
+		{
+			name:       "weekend sleeps through from friday",
+			now:        time.Date(2024, 5, 19, 12, 0, 0, 0, kolkata),
+			wantLatest: &bean.ScheduleEvent{Action: bean.HibernationActionHibernate, Time: time.Date(2024, 5, 17, 20, 0, 0, 0, kolkata)},
+			wantNext:   &bean.ScheduleEvent{Action: bean.HibernationActionUnHibernate, Time: time.Date(2024, 5, 20, 8, 0, 0, 0, kolkata)},
+		},
+		{
+			name:       "exactly at wake time, evaluated in utc",
+			now:        time.Date(2024, 5, 20, 8, 0, 0, 0, kolkata).UTC(),
+			wantLatest: &bean.ScheduleEvent{Action: bean.HibernationActionUnHibernate, Time: time.Date(2024, 5, 20, 8, 0, 0, 0, kolkata)},
+			wantNext:   &bean.ScheduleEvent{Action: bean.HibernationActionHibernate, Time: time.Date(2024, 5, 20, 20, 0, 0, 0, kolkata)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latest, err := getLatestEvent(schedule, tt.now)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantLatest.Action, latest.Action)
+			assert.True(t, tt.wantLatest.Time.Equal(latest.Time), "latest event at %s", latest.Time)
+			next, err := getNextEvent(schedule, tt.now)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantNext.Action, next.Action)
+			assert.True(t, tt.wantNext.Time.Equal(next.Time), "next event at %s", next.Time)
+		})
+	}
+}
+
+func TestGetResourceGroupTargets(t *testing.T) {
+	tests := []struct {
+		name              string
+		isEnvGroup        bool
+		parentResourceId  int
+		mappedResourceIds []int
+		excludedAppIds    []int
+		want              []*bean.HibernationTarget
+	}{
+		{
+			name:              "env group",
+			isEnvGroup:        true,
+			parentResourceId:  1,
+			mappedResourceIds: []int{10, 11, 12},
+			excludedAppIds:    []int{11},
+			want:              []*bean.HibernationTarget{{EnvironmentId: 1, AppIdIncludes: []int{10, 12}}},
+		},
+		{
+			name:              "env group with every app excluded",
+			isEnvGroup:        true,
+			parentResourceId:  1,
+			mappedResourceIds: []int{10},
+			excludedAppIds:    []int{10},
+			want:              nil,
+		},
+		{
+			name:              "app group",
+			parentResourceId:  10,
+			mappedResourceIds: []int{1, 2},
+			want: []*bean.HibernationTarget{
+				{EnvironmentId: 1, AppIdIncludes: []int{10}},
+				{EnvironmentId: 2, AppIdIncludes: []int{10}},
+			},
+		},
+		{
+			name:              "excluded app group",
+			parentResourceId:  10,
+			mappedResourceIds: []int{1, 2},
+			excludedAppIds:    []int{10},
+			want:              nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getResourceGroupTargets(tt.isEnvGroup, tt.parentResourceId, tt.mappedResourceIds, tt.excludedAppIds))
+		})
+	}
+}
+
+func TestGetExecutionStatus(t *testing.T) {
+	succeeded := map[string]any{"id": "1", "success": true}
+	skipped := map[string]any{"id": "2", "success": false, "skipped": "Application is already hibernated"}
+	failed := map[string]any{"id": "3", "success": false, "error": "no deployment history found"}
+	assert.Equal(t, bean.ExecutionStatusSucceeded, getExecutionStatus(nil))
+	assert.Equal(t, bean.ExecutionStatusSucceeded, getExecutionStatus([]map[string]any{succeeded, skipped}))
+	assert.Equal(t, bean.ExecutionStatusPartial, getExecutionStatus([]map[string]any{succeeded, failed}))
+	assert.Equal(t, bean.ExecutionStatusFailed, getExecutionStatus([]map[string]any{failed}))
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type HibernationScheduleExecution struct {
+	tableName             struct{} `sql:"hibernation_schedule_execution" pg:",discard_unknown_columns"`
+	Id                    int      `sql:"id,pk"`
+	HibernationScheduleId int      `sql:"hibernation_schedule_id,notnull"`
+	EnvironmentId         int      `sql:"environment_id,notnull"`
+	Action                string   `sql:"action,notnull"`
+	Trigger               string   `sql:"trigger,notnull"`
+	Status                string   `sql:"status,notnull"`
+	// AppResponse is the per app response of the bulk hibernate or un-hibernate operation, in json
+	AppResponse string `sql:"app_response"`
+	Message     string `sql:"message"`
+	sql.AuditLog
+}
+
+type HibernationScheduleExecutionRepository interface {
+	Save(model *HibernationScheduleExecution) error
+	FindByScheduleId(scheduleId int, offset, limit int) ([]*HibernationScheduleExecution, error)
+}
+
+type HibernationScheduleExecutionRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewHibernationScheduleExecutionRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *HibernationScheduleExecutionRepositoryImpl {
+	return &HibernationScheduleExecutionRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *HibernationScheduleExecutionRepositoryImpl) Save(model *HibernationScheduleExecution) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *HibernationScheduleExecutionRepositoryImpl) FindByScheduleId(scheduleId int, offset, limit int) ([]*HibernationScheduleExecution, error) {
+	var models []*HibernationScheduleExecution
+	err := impl.dbConnection.Model(&models).
+		Where("hibernation_schedule_id = ?", scheduleId).
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Select()
+	return models, err
+}
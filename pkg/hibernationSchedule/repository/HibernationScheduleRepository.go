@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type HibernationSchedule struct {
+	tableName       struct{}   `sql:"hibernation_schedule" pg:",discard_unknown_columns"`
+	Id              int        `sql:"id,pk"`
+	Name            string     `sql:"name,notnull"`
+	EnvironmentId   int        `sql:"environment_id"`
+	ResourceGroupId int        `sql:"resource_group_id"`
+	Timezone        string     `sql:"timezone,notnull"`
+	SleepTime       string     `sql:"sleep_time,notnull"`
+	WakeTime        string     `sql:"wake_time,notnull"`
+	WeekDays        []int      `sql:"week_days" pg:",array"`
+	ExcludedAppIds  []int      `sql:"excluded_app_ids" pg:",array"`
+	KeepAwakeUntil  *time.Time `sql:"keep_awake_until"`
+	State           string     `sql:"state,notnull"`
+	// LastEventOn is the time of the latest sleep or wake event acted upon,
+	// events at or before it are never executed again
+	LastEventOn time.Time `sql:"last_event_on,notnull"`
+	Active      bool      `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type HibernationScheduleRepository interface {
+	Save(model *HibernationSchedule) error
+	Update(model *HibernationSchedule) error
+	// UpdateExecutionState updates the fields maintained by schedule executions, without touching the audit log of the schedule
+	UpdateExecutionState(model *HibernationSchedule) error
+	FindById(id int) (*HibernationSchedule, error)
+	FindAllActive() ([]*HibernationSchedule, error)
+	FindActiveByEnvironmentId(envId int) ([]*HibernationSchedule, error)
+}
+
+type HibernationScheduleRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewHibernationScheduleRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *HibernationScheduleRepositoryImpl {
+	return &HibernationScheduleRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *HibernationScheduleRepositoryImpl) Save(model *HibernationSchedule) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *HibernationScheduleRepositoryImpl) Update(model *HibernationSchedule) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *HibernationScheduleRepositoryImpl) UpdateExecutionState(model *HibernationSchedule) error {
+	_, err := impl.dbConnection.Model(model).
+		Column("keep_awake_until", "state", "last_event_on").
+		WherePK().
+		Update()
+	return err
+}
+
+func (impl *HibernationScheduleRepositoryImpl) FindById(id int) (*HibernationSchedule, error) {
+	model := &HibernationSchedule{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *HibernationScheduleRepositoryImpl) FindAllActive() ([]*HibernationSchedule, error) {
+	var models []*HibernationSchedule
+	err := impl.dbConnection.Model(&models).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return models, err
+}
+
+func (impl *HibernationScheduleRepositoryImpl) FindActiveByEnvironmentId(envId int) ([]*HibernationSchedule, error) {
+	var models []*HibernationSchedule
+	err := impl.dbConnection.Model(&models).
+		Where("environment_id = ?", envId).
+		Where("active = ?", true).
+		Select()
+	return models, err
+}
@@ -29,6 +29,8 @@ import (
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
 	bean5 "github.com/devtron-labs/devtron/pkg/cluster/environment/bean"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
+	"github.com/devtron-labs/devtron/pkg/debugProfile"
+	debugProfileBean "github.com/devtron-labs/devtron/pkg/debugProfile/bean"
 	clientErrors "github.com/devtron-labs/devtron/pkg/errors"
 	"github.com/devtron-labs/devtron/pkg/fluxApplication"
 	bean2 "github.com/devtron-labs/devtron/pkg/fluxApplication/bean"
@@ -112,6 +114,7 @@ type K8sApplicationServiceImpl struct {
 	//argoApplicationService       argoApplication.ArgoApplicationService
 	fluxApplicationService fluxApplication.FluxApplicationService
 	clusterReadService     read.ClusterReadService
+	debugProfileService    debugProfile.DebugProfileService
 }
 
 func NewK8sApplicationServiceImpl(Logger *zap.SugaredLogger, clusterService cluster.ClusterService, pump connector.Pump, helmAppService client.HelmAppService, K8sUtil *k8s2.K8sServiceImpl, aCDAuthConfig *util3.ACDAuthConfig, K8sResourceHistoryService kubernetesResourceAuditLogs.K8sResourceHistoryService,
@@ -119,7 +122,8 @@ func NewK8sApplicationServiceImpl(Logger *zap.SugaredLogger, clusterService clus
 	ephemeralContainerService cluster.EphemeralContainerService,
 	ephemeralContainerRepository repository.EphemeralContainersRepository,
 	fluxApplicationService fluxApplication.FluxApplicationService,
-	clusterReadService read.ClusterReadService) (*K8sApplicationServiceImpl, error) {
+	clusterReadService read.ClusterReadService,
+	debugProfileService debugProfile.DebugProfileService) (*K8sApplicationServiceImpl, error) {
 	ephemeralContainerConfig := &EphemeralContainerConfig{}
 	err := env.Parse(ephemeralContainerConfig)
 	if err != nil {
@@ -142,6 +146,7 @@ func NewK8sApplicationServiceImpl(Logger *zap.SugaredLogger, clusterService clus
 		//argoApplicationService:       argoApplicationService,
 		fluxApplicationService: fluxApplicationService,
 		clusterReadService:     clusterReadService,
+		debugProfileService:    debugProfileService,
 	}, nil
 }
 
@@ -874,9 +879,13 @@ func (impl *K8sApplicationServiceImpl) applyResourceFromManifest(ctx context.Con
 	return isUpdateResource, nil
 }
 func (impl *K8sApplicationServiceImpl) CreatePodEphemeralContainers(req *bean5.EphemeralContainerRequest) error {
+	profile, err := impl.debugProfileService.ResolveProfile(req)
+	if err != nil {
+		impl.logger.Errorw("error in resolving debug profile of ephemeral container", "debugProfileId", req.DebugProfileId, "err", err)
+		return err
+	}
 	var clientSet *kubernetes.Clientset
 	var v1Client *v1.CoreV1Client
-	var err error
 	if len(req.ExternalArgoApplicationName) > 0 {
 		clientSet, v1Client, err = impl.k8sCommonService.GetCoreClientByClusterIdForExternalArgoApps(req)
 		if err != nil {
@@ -909,7 +918,7 @@ func (impl *K8sApplicationServiceImpl) CreatePodEphemeralContainers(req *bean5.E
 		impl.logger.Errorw("error occurred in unMarshaling pod object", "podObject", pod, "err", err)
 		return fmt.Errorf("error creating JSON for pod: %v", err)
 	}
-	debugPod, debugContainer, err := impl.generateDebugContainer(pod, *req)
+	debugPod, debugContainer, err := impl.generateDebugContainer(pod, *req, profile)
 	if err != nil {
 		impl.logger.Errorw("error in generateDebugContainer", "request", req, "err", err)
 		return err
@@ -982,7 +991,7 @@ func (impl *K8sApplicationServiceImpl) CreatePodEphemeralContainers(req *bean5.E
 	return err
 }
 
-func (impl *K8sApplicationServiceImpl) generateDebugContainer(pod *corev1.Pod, req bean5.EphemeralContainerRequest) (*corev1.Pod, *corev1.EphemeralContainer, error) {
+func (impl *K8sApplicationServiceImpl) generateDebugContainer(pod *corev1.Pod, req bean5.EphemeralContainerRequest, profile *debugProfileBean.DebugProfileDto) (*corev1.Pod, *corev1.EphemeralContainer, error) {
 	copied := pod.DeepCopy()
 	ephemeralContainer := &corev1.EphemeralContainer{}
 	if req.AdvancedData != nil {
@@ -1012,9 +1021,16 @@ func (impl *K8sApplicationServiceImpl) generateDebugContainer(pod *corev1.Pod, r
 		}
 	}
 	ephemeralContainer.Name = ephemeralContainer.Name + "-" + util2.Generate(5)
-	scriptCreateCommand := fmt.Sprintf("echo 'while true; do sleep 600; done;' > "+k8sObjectUtils.EphemeralContainerStartingShellScriptFileName, ephemeralContainer.Name)
-	scriptRunCommand := fmt.Sprintf("sh "+k8sObjectUtils.EphemeralContainerStartingShellScriptFileName, ephemeralContainer.Name)
-	ephemeralContainer.Command = []string{"sh", "-c", scriptCreateCommand + " && " + scriptRunCommand}
+	if profile != nil {
+		err := debugProfile.ApplyProfile(ephemeralContainer, profile, req.BasicData.Image)
+		if err != nil {
+			return copied, ephemeralContainer, err
+		}
+	} else {
+		scriptCreateCommand := fmt.Sprintf("echo 'while true; do sleep 600; done;' > "+k8sObjectUtils.EphemeralContainerStartingShellScriptFileName, ephemeralContainer.Name)
+		scriptRunCommand := fmt.Sprintf("sh "+k8sObjectUtils.EphemeralContainerStartingShellScriptFileName, ephemeralContainer.Name)
+		ephemeralContainer.Command = []string{"sh", "-c", scriptCreateCommand + " && " + scriptRunCommand}
+	}
 	copied.Spec.EphemeralContainers = append(copied.Spec.EphemeralContainers, *ephemeralContainer)
 	ephemeralContainer = &copied.Spec.EphemeralContainers[len(copied.Spec.EphemeralContainers)-1]
 	return copied, ephemeralContainer, nil
@@ -187,6 +187,128 @@ type NodeDetails struct {
 	Taints    []*LabelAnnotationTaintObject `json:"taints"`
 }
 
+type PodDrainAction string
+
+const (
+	PodDrainActionEvict  PodDrainAction = "Evict"
+	PodDrainActionDelete PodDrainAction = "Delete"
+	PodDrainActionSkip   PodDrainAction = "Skip"
+	// PodDrainActionError is for pods the drain options don't allow deleting, they fail the drain
+	PodDrainActionError PodDrainAction = "Error"
+)
+
+type DisruptionBudgetStatus string
+
+const (
+	DisruptionBudgetStatusOk DisruptionBudgetStatus = "Ok"
+	// DisruptionBudgetStatusThrottled budgets allow some of the evictions, the others wait for replacements to be healthy
+	DisruptionBudgetStatusThrottled DisruptionBudgetStatus = "Throttled"
+	DisruptionBudgetStatusBlocking  DisruptionBudgetStatus = "Blocking"
+	// DisruptionBudgetStatusBypassed budgets are ignored as pods are deleted instead of evicted
+	DisruptionBudgetStatusBypassed DisruptionBudgetStatus = "Bypassed"
+)
+
+type BatchDrainStatusType string
+
+const (
+	BatchDrainStatusPending     BatchDrainStatusType = "Pending"
+	BatchDrainStatusRunning     BatchDrainStatusType = "Running"
+	BatchDrainStatusHealthCheck BatchDrainStatusType = "HealthCheck"
+	BatchDrainStatusSucceeded   BatchDrainStatusType = "Succeeded"
+	BatchDrainStatusFailed      BatchDrainStatusType = "Failed"
+)
+
+type NodeDrainPlanRequest struct {
+	ClusterId int      `json:"clusterId"`
+	NodeNames []string `json:"nodeNames"`
+	// NodeGroup selects all the nodes of a node group, it is used when NodeNames is empty
+	NodeGroup       string           `json:"nodeGroup"`
+	NodeDrainHelper *NodeDrainHelper `json:"nodeDrainOptions"`
+}
+
+type BatchDrainRequest struct {
+	NodeDrainPlanRequest
+	// HealthCheckTimeoutSeconds is how long the workloads of the pods evicted from a node have to be ready again
+	// before the next node is drained
+	HealthCheckTimeoutSeconds int `json:"healthCheckTimeoutSeconds"`
+	// IgnoreCapacity drains even when the remaining nodes can't fit the evicted pods, for clusters with an autoscaler
+	IgnoreCapacity bool `json:"ignoreCapacity"`
+}
+
+type NodeDrainPlan struct {
+	ClusterId         int                       `json:"clusterId"`
+	Nodes             []*NodeDrainPlanDetail    `json:"nodes"`
+	DisruptionBudgets []*DisruptionBudgetImpact `json:"disruptionBudgets"`
+	Capacity          *DrainCapacity            `json:"capacity"`
+	// Drainable is false when pods can't be deleted with the drain options, disruption budgets block evictions
+	// or the evicted pods don't fit on the remaining nodes
+	Drainable bool     `json:"drainable"`
+	Warnings  []string `json:"warnings"`
+}
+
+type NodeDrainPlanDetail struct {
+	Name          string          `json:"name"`
+	NodeGroup     string          `json:"nodeGroup"`
+	Unschedulable bool            `json:"unschedulable"`
+	Pods          []*PodDrainPlan `json:"pods"`
+}
+
+type PodDrainPlan struct {
+	Name                 string         `json:"name"`
+	Namespace            string         `json:"namespace"`
+	OwnerKind            string         `json:"ownerKind,omitempty"`
+	OwnerName            string         `json:"ownerName,omitempty"`
+	Action               PodDrainAction `json:"action"`
+	Message              string         `json:"message,omitempty"`
+	LocalStorage         bool           `json:"localStorage"`
+	DisruptionBudgets    []string       `json:"disruptionBudgets,omitempty"`
+	CpuRequestMillicores int64          `json:"cpuRequestMillicores"`
+	MemoryRequestBytes   int64          `json:"memoryRequestBytes"`
+	// TargetNode is the node the simulation reschedules the pod on, empty for pods not recreated by a controller
+	// and for pods which don't fit anywhere
+	TargetNode string `json:"targetNode,omitempty"`
+}
+
+type DisruptionBudgetImpact struct {
+	Name               string                 `json:"name"`
+	Namespace          string                 `json:"namespace"`
+	DisruptionsAllowed int32                  `json:"disruptionsAllowed"`
+	CurrentHealthy     int32                  `json:"currentHealthy"`
+	DesiredHealthy     int32                  `json:"desiredHealthy"`
+	PodsToEvict        int                    `json:"podsToEvict"`
+	Status             DisruptionBudgetStatus `json:"status"`
+}
+
+type DrainCapacity struct {
+	RemainingNodes         int   `json:"remainingNodes"`
+	RequiredCpuMillicores  int64 `json:"requiredCpuMillicores"`
+	RequiredMemoryBytes    int64 `json:"requiredMemoryBytes"`
+	AvailableCpuMillicores int64 `json:"availableCpuMillicores"`
+	AvailableMemoryBytes   int64 `json:"availableMemoryBytes"`
+	// Sufficient is set when the simulation fits all the rescheduled pods on the remaining nodes
+	Sufficient   bool     `json:"sufficient"`
+	UnplacedPods []string `json:"unplacedPods"`
+}
+
+type BatchDrainStatus struct {
+	Id         string                  `json:"id"`
+	ClusterId  int                     `json:"clusterId"`
+	Status     BatchDrainStatusType    `json:"status"`
+	Message    string                  `json:"message,omitempty"`
+	Nodes      []*BatchDrainNodeStatus `json:"nodes"`
+	StartedOn  time.Time               `json:"startedOn"`
+	FinishedOn *time.Time              `json:"finishedOn,omitempty"`
+	UserId     int32                   `json:"userId"`
+}
+
+type BatchDrainNodeStatus struct {
+	Name       string               `json:"name"`
+	Status     BatchDrainStatusType `json:"status"`
+	Message    string               `json:"message,omitempty"`
+	StartedOn  *time.Time           `json:"startedOn,omitempty"`
+	FinishedOn *time.Time           `json:"finishedOn,omitempty"`
+}
+
 // PodDelete informs filtering logic whether a pod should be deleted or not
 type PodDelete struct {
 	Pod    corev1.Pod
@@ -209,6 +331,11 @@ func (l *PodDeleteList) Pods() []corev1.Pod {
 	return pods
 }
 
+// Items returns all the pods with their delete status
+func (l *PodDeleteList) Items() []PodDelete {
+	return l.items
+}
+
 func (l *PodDeleteList) Errors() []error {
 	failedPods := make(map[string][]string)
 	for _, i := range l.items {
@@ -0,0 +1,419 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capacity
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
+)
+
+const daemonSetKind = "DaemonSet"
+
+// nodeFreeCapacity is what is left of the allocatable resources of a node after the requests of its pods
+type nodeFreeCapacity struct {
+	node          *corev1.Node
+	cpuMillicores int64
+	memoryBytes   int64
+	pods          int64
+}
+
+// podToReschedule is a pod recreated by its controller after the drain
+type podToReschedule struct {
+	pod  *corev1.Pod
+	plan *bean.PodDrainPlan
+}
+
+// buildDrainPlan simulates draining the nodes, podDeletes are the pods of each drained node run through the drain
+// filters and pods are all the pods of the cluster
+func (impl *K8sCapacityServiceImpl) buildDrainPlan(drainNodes []*corev1.Node, podDeletes map[string][]bean.PodDelete, nodes []corev1.Node,
+	pods []corev1.Pod, budgets []policyv1.PodDisruptionBudget, disableEviction bool) *bean.NodeDrainPlan {
+	plan := &bean.NodeDrainPlan{
+		Nodes:             make([]*bean.NodeDrainPlanDetail, 0, len(drainNodes)),
+		DisruptionBudgets: make([]*bean.DisruptionBudgetImpact, 0),
+		Warnings:          make([]string, 0),
+	}
+	drained := make(map[string]bool, len(drainNodes))
+	var toReschedule []*podToReschedule
+	localStoragePods, unmanagedPods := 0, 0
+	for _, node := range drainNodes {
+		drained[node.Name] = true
+		nodePlan := &bean.NodeDrainPlanDetail{
+			Name:          node.Name,
+			NodeGroup:     impl.getNodeGroup(node),
+			Unschedulable: node.Spec.Unschedulable,
+			Pods:          make([]*bean.PodDrainPlan, 0, len(podDeletes[node.Name])),
+		}
+		for i := range podDeletes[node.Name] {
+			pod := &podDeletes[node.Name][i].Pod
+			podPlan := getPodDrainPlan(pod, podDeletes[node.Name][i].Status, disableEviction)
+			nodePlan.Pods = append(nodePlan.Pods, podPlan)
+			if podPlan.Action != bean.PodDrainActionEvict && podPlan.Action != bean.PodDrainActionDelete {
+				continue
+			}
+			if podPlan.LocalStorage {
+				localStoragePods++
+			}
+			if isPodFinished(pod) {
+				continue
+			}
+			if len(podPlan.OwnerKind) == 0 {
+				unmanagedPods++
+			} else if podPlan.OwnerKind != daemonSetKind {
+				toReschedule = append(toReschedule, &podToReschedule{pod: pod, plan: podPlan})
+			}
+		}
+		plan.Nodes = append(plan.Nodes, nodePlan)
+	}
+	plan.DisruptionBudgets = getDisruptionBudgetImpacts(plan.Nodes, budgets, podDeletes, disableEviction)
+	plan.Capacity = simulateRescheduling(toReschedule, getNodeFreeCapacities(nodes, pods, drained))
+	if localStoragePods > 0 {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("data in the emptyDir volumes of %d pods is lost", localStoragePods))
+	}
+	if unmanagedPods > 0 {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("%d pods without a controller are deleted and not recreated", unmanagedPods))
+	}
+	for _, pod := range toReschedule {
+		if affinity := pod.pod.Spec.Affinity; (affinity != nil && affinity.PodAntiAffinity != nil) || len(pod.pod.Spec.TopologySpreadConstraints) > 0 {
+			plan.Warnings = append(plan.Warnings, "pod anti affinity and topology spread constraints aren't simulated, pods using them may not fit where placed")
+			break
+		}
+	}
+	blockers := getDrainBlockers(plan, false)
+	plan.Warnings = append(plan.Warnings, blockers...)
+	plan.Drainable = len(blockers) == 0
+	return plan
+}
+
+func getPodDrainPlan(pod *corev1.Pod, status bean.PodDeleteStatus, disableEviction bool) *bean.PodDrainPlan {
+	requests, _ := resourcehelper.PodRequestsAndLimits(pod)
+	podPlan := &bean.PodDrainPlan{
+		Name:                 pod.Name,
+		Namespace:            pod.Namespace,
+		Message:              status.Message,
+		LocalStorage:         hasLocalStorage(pod),
+		CpuRequestMillicores: requests.Cpu().MilliValue(),
+		MemoryRequestBytes:   requests.Memory().Value(),
+	}
+	if owner := v1.GetControllerOf(pod); owner != nil {
+		podPlan.OwnerKind, podPlan.OwnerName = owner.Kind, owner.Name
+	}
+	switch {
+	case status.Reason == bean.PodDeleteStatusTypeError:
+		podPlan.Action = bean.PodDrainActionError
+	case !status.Delete:
+		podPlan.Action = bean.PodDrainActionSkip
+	case disableEviction:
+		podPlan.Action = bean.PodDrainActionDelete
+	default:
+		podPlan.Action = bean.PodDrainActionEvict
+	}
+	return podPlan
+}
+
+// getDisruptionBudgetImpacts reports the budgets covering the pods to be evicted, the names of the budgets are set on the pods
+func getDisruptionBudgetImpacts(nodePlans []*bean.NodeDrainPlanDetail, budgets []policyv1.PodDisruptionBudget,
+	podDeletes map[string][]bean.PodDelete, disableEviction bool) []*bean.DisruptionBudgetImpact {
+	impacts := make([]*bean.DisruptionBudgetImpact, 0)
+	impactByBudget := make(map[string]*bean.DisruptionBudgetImpact)
+	for _, nodePlan := range nodePlans {
+		for i, podPlan := range nodePlan.Pods {
+			if podPlan.Action != bean.PodDrainActionEvict && podPlan.Action != bean.PodDrainActionDelete {
+				continue
+			}
+			pod := &podDeletes[nodePlan.Name][i].Pod
+			for j := range budgets {
+				budget := &budgets[j]
+				if !isPodCoveredByBudget(pod, budget) {
+					continue
+				}
+				key := budget.Namespace + "/" + budget.Name
+				impact, ok := impactByBudget[key]
+				if !ok {
+					impact = &bean.DisruptionBudgetImpact{
+						Name:               budget.Name,
+						Namespace:          budget.Namespace,
+						DisruptionsAllowed: budget.Status.DisruptionsAllowed,
+						CurrentHealthy:     budget.Status.CurrentHealthy,
+						DesiredHealthy:     budget.Status.DesiredHealthy,
+					}
+					impactByBudget[key] = impact
+					impacts = append(impacts, impact)
+				}
+				impact.PodsToEvict++
+				podPlan.DisruptionBudgets = append(podPlan.DisruptionBudgets, budget.Name)
+			}
+		}
+	}
+	for _, impact := range impacts {
+		switch {
+		case disableEviction:
+			impact.Status = bean.DisruptionBudgetStatusBypassed
+		case impact.DisruptionsAllowed == 0:
+			impact.Status = bean.DisruptionBudgetStatusBlocking
+		case int32(impact.PodsToEvict) > impact.DisruptionsAllowed:
+			impact.Status = bean.DisruptionBudgetStatusThrottled
+		default:
+			impact.Status = bean.DisruptionBudgetStatusOk
+		}
+	}
+	return impacts
+}
+
+// isPodCoveredByBudget matches the selector of a budget in the namespace of the pod, a budget without a selector
+// covers no pods
+func isPodCoveredByBudget(pod *corev1.Pod, budget *policyv1.PodDisruptionBudget) bool {
+	if pod.Namespace != budget.Namespace || budget.Spec.Selector == nil {
+		return false
+	}
+	selector, err := v1.LabelSelectorAsSelector(budget.Spec.Selector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(pod.Labels))
+}
+
+// getNodeFreeCapacities returns the capacity left on the ready and schedulable nodes which aren't drained
+func getNodeFreeCapacities(nodes []corev1.Node, pods []corev1.Pod, drained map[string]bool) []*nodeFreeCapacity {
+	capacityByNode := make(map[string]*nodeFreeCapacity)
+	capacities := make([]*nodeFreeCapacity, 0, len(nodes))
+	for i := range nodes {
+		node := &nodes[i]
+		if drained[node.Name] || node.Spec.Unschedulable || !isNodeReady(node) {
+			continue
+		}
+		capacity := &nodeFreeCapacity{
+			node:          node,
+			cpuMillicores: node.Status.Allocatable.Cpu().MilliValue(),
+			memoryBytes:   node.Status.Allocatable.Memory().Value(),
+			pods:          node.Status.Allocatable.Pods().Value(),
+		}
+		capacityByNode[node.Name] = capacity
+		capacities = append(capacities, capacity)
+	}
+	for i := range pods {
+		capacity, ok := capacityByNode[pods[i].Spec.NodeName]
+		if !ok || isPodFinished(&pods[i]) {
+			continue
+		}
+		requests, _ := resourcehelper.PodRequestsAndLimits(&pods[i])
+		capacity.cpuMillicores -= requests.Cpu().MilliValue()
+		capacity.memoryBytes -= requests.Memory().Value()
+		capacity.pods--
+	}
+	sort.Slice(capacities, func(i, j int) bool {
+		return capacities[i].node.Name < capacities[j].node.Name
+	})
+	return capacities
+}
+
+// simulateRescheduling places the pods on the first node they fit on, largest pods first. Taints, node selectors
+// and required node affinity are honoured, inter pod affinity and topology spread aren't simulated
+func simulateRescheduling(pods []*podToReschedule, capacities []*nodeFreeCapacity) *bean.DrainCapacity {
+	drainCapacity := &bean.DrainCapacity{
+		RemainingNodes: len(capacities),
+		UnplacedPods:   make([]string, 0),
+	}
+	for _, capacity := range capacities {
+		drainCapacity.AvailableCpuMillicores += max(capacity.cpuMillicores, 0)
+		drainCapacity.AvailableMemoryBytes += max(capacity.memoryBytes, 0)
+	}
+	sort.SliceStable(pods, func(i, j int) bool {
+		if pods[i].plan.CpuRequestMillicores != pods[j].plan.CpuRequestMillicores {
+			return pods[i].plan.CpuRequestMillicores > pods[j].plan.CpuRequestMillicores
+		}
+		return pods[i].plan.MemoryRequestBytes > pods[j].plan.MemoryRequestBytes
+	})
+	for _, pod := range pods {
+		drainCapacity.RequiredCpuMillicores += pod.plan.CpuRequestMillicores
+		drainCapacity.RequiredMemoryBytes += pod.plan.MemoryRequestBytes
+		for _, capacity := range capacities {
+			if capacity.pods < 1 || capacity.cpuMillicores < pod.plan.CpuRequestMillicores ||
+				capacity.memoryBytes < pod.plan.MemoryRequestBytes || !canScheduleOnNode(pod.pod, capacity.node) {
+				continue
+			}
+			capacity.cpuMillicores -= pod.plan.CpuRequestMillicores
+			capacity.memoryBytes -= pod.plan.MemoryRequestBytes
+			capacity.pods--
+			pod.plan.TargetNode = capacity.node.Name
+			break
+		}
+		if len(pod.plan.TargetNode) == 0 {
+			drainCapacity.UnplacedPods = append(drainCapacity.UnplacedPods, pod.pod.Namespace+"/"+pod.pod.Name)
+		}
+	}
+	drainCapacity.Sufficient = len(drainCapacity.UnplacedPods) == 0
+	return drainCapacity
+}
+
+// getDrainBlockers returns why the nodes can't be drained, pods which don't fit on the remaining nodes are ignored
+// with ignoreCapacity
+func getDrainBlockers(plan *bean.NodeDrainPlan, ignoreCapacity bool) []string {
+	var blockers []string
+	errorPods := 0
+	for _, nodePlan := range plan.Nodes {
+		for _, podPlan := range nodePlan.Pods {
+			if podPlan.Action == bean.PodDrainActionError {
+				errorPods++
+			}
+		}
+	}
+	if errorPods > 0 {
+		blockers = append(blockers, fmt.Sprintf("%d pods can't be deleted with the drain options", errorPods))
+	}
+	for _, budget := range plan.DisruptionBudgets {
+		if budget.Status == bean.DisruptionBudgetStatusBlocking {
+			blockers = append(blockers, fmt.Sprintf("disruption budget %s/%s allows no disruptions", budget.Namespace, budget.Name))
+		}
+	}
+	if !ignoreCapacity && plan.Capacity != nil && !plan.Capacity.Sufficient {
+		blockers = append(blockers, fmt.Sprintf("%d pods don't fit on the remaining nodes", len(plan.Capacity.UnplacedPods)))
+	}
+	return blockers
+}
+
+func canScheduleOnNode(pod *corev1.Pod, node *corev1.Node) bool {
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		if !toleratesTaint(pod.Spec.Tolerations, taint) {
+			return false
+		}
+	}
+	if !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	// the terms are ORed
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if matchesNodeSelectorTerm(term, node) {
+			return true
+		}
+	}
+	return false
+}
+
+func toleratesTaint(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesNodeSelectorTerm ANDs the requirements of a term, a term without requirements matches no nodes
+func matchesNodeSelectorTerm(term corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, expression := range term.MatchExpressions {
+		if !matchesNodeSelectorRequirement(expression, labels.Set(node.Labels)) {
+			return false
+		}
+	}
+	for _, field := range term.MatchFields {
+		// metadata.name is the only field supported by the scheduler
+		if field.Key != "metadata.name" || !matchesNodeSelectorRequirement(field, labels.Set{field.Key: node.Name}) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesNodeSelectorRequirement(requirement corev1.NodeSelectorRequirement, set labels.Set) bool {
+	operators := map[corev1.NodeSelectorOperator]selection.Operator{
+		corev1.NodeSelectorOpIn:           selection.In,
+		corev1.NodeSelectorOpNotIn:        selection.NotIn,
+		corev1.NodeSelectorOpExists:       selection.Exists,
+		corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+		corev1.NodeSelectorOpGt:           selection.GreaterThan,
+		corev1.NodeSelectorOpLt:           selection.LessThan,
+	}
+	operator, ok := operators[requirement.Operator]
+	if !ok {
+		return false
+	}
+	labelRequirement, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+	if err != nil {
+		return false
+	}
+	return labelRequirement.Matches(set)
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func isPodFinished(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+func hasLocalStorage(pod *corev1.Pod) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// getEvictedWorkloads returns the replica sets and stateful sets of the pods evicted from a node, the other
+// controllers have no ready replica count to wait on
+func getEvictedWorkloads(nodePlan *bean.NodeDrainPlanDetail) []*bean.PodDrainPlan {
+	var workloads []*bean.PodDrainPlan
+	added := make(map[string]bool)
+	for _, podPlan := range nodePlan.Pods {
+		if podPlan.Action != bean.PodDrainActionEvict && podPlan.Action != bean.PodDrainActionDelete {
+			continue
+		}
+		if podPlan.OwnerKind != "ReplicaSet" && podPlan.OwnerKind != "StatefulSet" {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s/%s", podPlan.Namespace, podPlan.OwnerKind, podPlan.OwnerName)
+		if !added[key] {
+			added[key] = true
+			workloads = append(workloads, podPlan)
+		}
+	}
+	return workloads
+}
+
+func isWorkloadReady(replicas *int32, readyReplicas int32) bool {
+	desired := int32(1)
+	if replicas != nil {
+		desired = *replicas
+	}
+	return readyReplicas >= desired
+}
@@ -0,0 +1,253 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capacity
+
+import (
+	"testing"
+
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getTestNode(name, nodeGroup string, cpu, memory string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: v1.ObjectMeta{Name: name, Labels: map[string]string{bean.AWSEKSNodeGroupLabel: nodeGroup, "zone": "a"}},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func getTestPod(name, nodeName, ownerKind, ownerName, cpu, memory string) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": ownerName}},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{
+				Name: "main",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if len(ownerKind) > 0 {
+		controller := true
+		pod.OwnerReferences = []v1.OwnerReference{{Kind: ownerKind, Name: ownerName, Controller: &controller}}
+	}
+	return pod
+}
+
+func getTestBudget(name string, app string, disruptionsAllowed int32) policyv1.PodDisruptionBudget {
+	return policyv1.PodDisruptionBudget{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &v1.LabelSelector{MatchLabels: map[string]string{"app": app}}},
+		Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: disruptionsAllowed},
+	}
+}
+
+func TestGetNodesToDrain(t *testing.T) {
+	impl := &K8sCapacityServiceImpl{}
+	nodes := []corev1.Node{
+		getTestNode("node-b", "general", "2", "4Gi"),
+		getTestNode("node-a", "general", "2", "4Gi"),
+		getTestNode("node-c", "gpu", "2", "4Gi"),
+	}
+
+	drainNodes, err := impl.getNodesToDrain(nodes, &bean.NodeDrainPlanRequest{NodeNames: []string{"node-c", "node-b", "node-c"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"node-c", "node-b"}, []string{drainNodes[0].Name, drainNodes[1].Name})
+
+	drainNodes, err = impl.getNodesToDrain(nodes, &bean.NodeDrainPlanRequest{NodeGroup: "general"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"node-a", "node-b"}, []string{drainNodes[0].Name, drainNodes[1].Name})
+
+	_, err = impl.getNodesToDrain(nodes, &bean.NodeDrainPlanRequest{NodeNames: []string{"node-d"}})
+	assert.NotNil(t, err)
+	_, err = impl.getNodesToDrain(nodes, &bean.NodeDrainPlanRequest{NodeGroup: "spot"})
+	assert.NotNil(t, err)
+	_, err = impl.getNodesToDrain(nodes, &bean.NodeDrainPlanRequest{})
+	assert.NotNil(t, err)
+}
+
+func TestBuildDrainPlan(t *testing.T) {
+	impl := &K8sCapacityServiceImpl{}
+	nodes := []corev1.Node{
+		getTestNode("node-a", "general", "2", "4Gi"),
+		getTestNode("node-b", "general", "4", "8Gi"),
+	}
+	web := getTestPod("web-1", "node-a", "ReplicaSet", "web", "500m", "512Mi")
+	web.Spec.Volumes = []corev1.Volume{{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+	db := getTestPod("db-0", "node-a", "StatefulSet", "db", "1", "1Gi")
+	logs := getTestPod("logs-x", "node-a", "DaemonSet", "logs", "100m", "128Mi")
+	debug := getTestPod("debug", "node-a", "", "", "100m", "128Mi")
+	existing := getTestPod("api-1", "node-b", "ReplicaSet", "api", "1", "2Gi")
+	podDeletes := map[string][]bean.PodDelete{
+		"node-a": {
+			{Pod: web, Status: bean.MakePodDeleteStatusWithWarning(true, "deleting Pods with local storage")},
+			{Pod: db, Status: bean.MakePodDeleteStatusOkay()},
+			{Pod: logs, Status: bean.MakePodDeleteStatusWithWarning(false, "ignoring DaemonSet-managed Pods")},
+			{Pod: debug, Status: bean.MakePodDeleteStatusWithWarning(true, "deleting Pods that declare no controller")},
+		},
+	}
+	budgets := []policyv1.PodDisruptionBudget{getTestBudget("web", "web", 1), getTestBudget("db", "db", 0), getTestBudget("api", "api", 0)}
+
+	plan := impl.buildDrainPlan([]*corev1.Node{&nodes[0]}, podDeletes, nodes, []corev1.Pod{web, db, logs, debug, existing}, budgets, false)
+	assert.Len(t, plan.Nodes, 1)
+	assert.Equal(t, "general", plan.Nodes[0].NodeGroup)
+	pods := plan.Nodes[0].Pods
+	assert.Equal(t, bean.PodDrainActionEvict, pods[0].Action)
+	assert.True(t, pods[0].LocalStorage)
+	assert.Equal(t, []string{"web"}, pods[0].DisruptionBudgets)
+	assert.Equal(t, "node-b", pods[0].TargetNode)
+	assert.Equal(t, "node-b", pods[1].TargetNode)
+	assert.Equal(t, bean.PodDrainActionSkip, pods[2].Action)
+	assert.Empty(t, pods[2].TargetNode)
+	assert.Equal(t, bean.PodDrainActionEvict, pods[3].Action)
+	assert.Empty(t, pods[3].TargetNode)
+
+	// the budget of api covers no drained pods
+	assert.Len(t, plan.DisruptionBudgets, 2)
+	assert.Equal(t, bean.DisruptionBudgetStatusOk, plan.DisruptionBudgets[0].Status)
+	assert.Equal(t, bean.DisruptionBudgetStatusBlocking, plan.DisruptionBudgets[1].Status)
+
+	assert.Equal(t, 1, plan.Capacity.RemainingNodes)
+	assert.True(t, plan.Capacity.Sufficient)
+	assert.Equal(t, int64(1500), plan.Capacity.RequiredCpuMillicores)
+	assert.Equal(t, int64(3000), plan.Capacity.AvailableCpuMillicores)
+	assert.False(t, plan.Drainable)
+	assert.Contains(t, plan.Warnings, "disruption budget default/db allows no disruptions")
+	assert.Contains(t, plan.Warnings, "data in the emptyDir volumes of 1 pods is lost")
+	assert.Contains(t, plan.Warnings, "1 pods without a controller are deleted and not recreated")
+
+	// deleting instead of evicting bypasses the budgets
+	plan = impl.buildDrainPlan([]*corev1.Node{&nodes[0]}, podDeletes, nodes, []corev1.Pod{web, db, logs, debug, existing}, budgets, true)
+	assert.Equal(t, bean.PodDrainActionDelete, plan.Nodes[0].Pods[0].Action)
+	assert.Equal(t, bean.DisruptionBudgetStatusBypassed, plan.DisruptionBudgets[1].Status)
+	assert.True(t, plan.Drainable)
+
+	// draining both nodes leaves nowhere to reschedule
+	podDeletes["node-b"] = []bean.PodDelete{{Pod: existing, Status: bean.MakePodDeleteStatusOkay()}}
+	plan = impl.buildDrainPlan([]*corev1.Node{&nodes[0], &nodes[1]}, podDeletes, nodes, []corev1.Pod{web, db, logs, debug, existing}, nil, false)
+	assert.False(t, plan.Capacity.Sufficient)
+	assert.Len(t, plan.Capacity.UnplacedPods, 3)
+	assert.Equal(t, []string{"3 pods don't fit on the remaining nodes"}, getDrainBlockers(plan, false))
+	assert.Empty(t, getDrainBlockers(plan, true))
+}
+
+func TestBuildDrainPlanWithErrors(t *testing.T) {
+	impl := &K8sCapacityServiceImpl{}
+	nodes := []corev1.Node{getTestNode("node-a", "general", "2", "4Gi")}
+	debug := getTestPod("debug", "node-a", "", "", "100m", "128Mi")
+	podDeletes := map[string][]bean.PodDelete{
+		"node-a": {{Pod: debug, Status: bean.MakePodDeleteStatusWithError("Pods declare no controller (use --force to override)")}},
+	}
+	plan := impl.buildDrainPlan([]*corev1.Node{&nodes[0]}, podDeletes, nodes, []corev1.Pod{debug}, nil, false)
+	assert.Equal(t, bean.PodDrainActionError, plan.Nodes[0].Pods[0].Action)
+	assert.Equal(t, "Pods declare no controller (use --force to override)", plan.Nodes[0].Pods[0].Message)
+	assert.False(t, plan.Drainable)
+	assert.Contains(t, plan.Warnings, "1 pods can't be deleted with the drain options")
+}
+
+func TestGetNodeFreeCapacities(t *testing.T) {
+	nodes := []corev1.Node{
+		getTestNode("node-a", "general", "2", "4Gi"),
+		getTestNode("node-b", "general", "2", "4Gi"),
+		getTestNode("node-c", "general", "2", "4Gi"),
+		getTestNode("node-d", "general", "2", "4Gi"),
+	}
+	nodes[1].Spec.Unschedulable = true
+	nodes[2].Status.Conditions[0].Status = corev1.ConditionFalse
+	finished := getTestPod("job-1", "node-a", "Job", "job", "1", "1Gi")
+	finished.Status.Phase = corev1.PodSucceeded
+	pods := []corev1.Pod{getTestPod("web-1", "node-a", "ReplicaSet", "web", "500m", "1Gi"), finished}
+
+	capacities := getNodeFreeCapacities(nodes, pods, map[string]bool{"node-d": true})
+	assert.Len(t, capacities, 1)
+	assert.Equal(t, "node-a", capacities[0].node.Name)
+	assert.Equal(t, int64(1500), capacities[0].cpuMillicores)
+	assert.Equal(t, int64(3*bean.Gibibyte), capacities[0].memoryBytes)
+	assert.Equal(t, int64(109), capacities[0].pods)
+}
+
+func TestCanScheduleOnNode(t *testing.T) {
+	node := getTestNode("node-a", "gpu", "2", "4Gi")
+	node.Spec.Taints = []corev1.Taint{
+		{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule},
+	}
+	pod := getTestPod("web-1", "node-b", "ReplicaSet", "web", "100m", "128Mi")
+	assert.False(t, canScheduleOnNode(&pod, &node))
+
+	pod.Spec.Tolerations = []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}}
+	assert.True(t, canScheduleOnNode(&pod, &node))
+
+	pod.Spec.NodeSelector = map[string]string{"zone": "b"}
+	assert.False(t, canScheduleOnNode(&pod, &node))
+	pod.Spec.NodeSelector = map[string]string{"zone": "a"}
+	assert.True(t, canScheduleOnNode(&pod, &node))
+
+	getAffinity := func(terms ...corev1.NodeSelectorTerm) *corev1.Affinity {
+		return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+		}}
+	}
+	pod.Spec.Affinity = getAffinity(corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+		{Key: bean.AWSEKSNodeGroupLabel, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"gpu"}},
+	}})
+	assert.False(t, canScheduleOnNode(&pod, &node))
+
+	// the terms are ORed
+	pod.Spec.Affinity = getAffinity(
+		corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"b"}}}},
+		corev1.NodeSelectorTerm{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-a"}}}},
+	)
+	assert.True(t, canScheduleOnNode(&pod, &node))
+
+	pod.Spec.Affinity = getAffinity(corev1.NodeSelectorTerm{})
+	assert.False(t, canScheduleOnNode(&pod, &node))
+}
+
+func TestGetEvictedWorkloads(t *testing.T) {
+	nodePlan := &bean.NodeDrainPlanDetail{Pods: []*bean.PodDrainPlan{
+		{Name: "web-1", Namespace: "default", OwnerKind: "ReplicaSet", OwnerName: "web", Action: bean.PodDrainActionEvict},
+		{Name: "web-2", Namespace: "default", OwnerKind: "ReplicaSet", OwnerName: "web", Action: bean.PodDrainActionEvict},
+		{Name: "db-0", Namespace: "default", OwnerKind: "StatefulSet", OwnerName: "db", Action: bean.PodDrainActionDelete},
+		{Name: "logs-x", Namespace: "default", OwnerKind: "DaemonSet", OwnerName: "logs", Action: bean.PodDrainActionSkip},
+		{Name: "job-1", Namespace: "default", OwnerKind: "Job", OwnerName: "job", Action: bean.PodDrainActionEvict},
+	}}
+	workloads := getEvictedWorkloads(nodePlan)
+	assert.Len(t, workloads, 2)
+	assert.Equal(t, "web", workloads[0].OwnerName)
+	assert.Equal(t, "db", workloads[1].OwnerName)
+
+	replicas := int32(3)
+	assert.True(t, isWorkloadReady(&replicas, 3))
+	assert.False(t, isWorkloadReady(&replicas, 2))
+	assert.True(t, isWorkloadReady(nil, 1))
+}
@@ -28,6 +28,7 @@ import (
 	application2 "github.com/devtron-labs/devtron/pkg/k8s/application"
 	bean3 "github.com/devtron-labs/devtron/pkg/k8s/bean"
 	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -39,14 +40,25 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	resourcehelper "k8s.io/kubectl/pkg/util/resource"
 	metrics "k8s.io/metrics/pkg/client/clientset/versioned"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
+const (
+	evictionRetryInterval             = 5 * time.Second
+	evictionRetryTimeout              = 5 * time.Minute
+	defaultDrainHealthCheckTimeoutSec = 300
+	drainHealthCheckInterval          = 5 * time.Second
+	batchDrainRetention               = 24 * time.Hour
+)
+
 type K8sCapacityService interface {
 	GetClusterCapacityDetailList(ctx context.Context, clusters []*bean2.ClusterBean) ([]*bean.ClusterCapacityDetail, error)
 	GetClusterCapacityDetail(ctx context.Context, cluster *bean2.ClusterBean, callForList bool) (*bean.ClusterCapacityDetail, error)
@@ -58,6 +70,13 @@ type K8sCapacityService interface {
 	DrainNode(ctx context.Context, request *bean.NodeUpdateRequestDto) (string, error)
 	EditNodeTaints(ctx context.Context, request *bean.NodeUpdateRequestDto) (string, error)
 	GetNode(ctx context.Context, clusterId int, nodeName string) (*corev1.Node, error)
+	// PlanNodeDrain simulates draining the nodes without changing them
+	PlanNodeDrain(ctx context.Context, request *bean.NodeDrainPlanRequest) (*bean.NodeDrainPlan, error)
+	// StartBatchDrain drains the nodes one at a time in the background, the next node is drained once the workloads
+	// of the pods evicted from the previous one are ready. Batches are best effort and kept in memory only, they are
+	// visible to this instance alone and a restart stops them
+	StartBatchDrain(ctx context.Context, request *bean.BatchDrainRequest, userId int32) (*bean.BatchDrainStatus, error)
+	GetBatchDrain(id string) (*bean.BatchDrainStatus, error)
 }
 
 type K8sCapacityServiceImpl struct {
@@ -65,6 +84,9 @@ type K8sCapacityServiceImpl struct {
 	k8sApplicationService application2.K8sApplicationService
 	K8sUtil               *k8s2.K8sServiceImpl
 	k8sCommonService      k8s.K8sCommonService
+	// batchDrains are the batch drains started by this instance, they are not shared with other replicas
+	batchDrains    map[string]*bean.BatchDrainStatus
+	batchDrainLock sync.RWMutex
 }
 
 func NewK8sCapacityServiceImpl(Logger *zap.SugaredLogger,
@@ -76,6 +98,7 @@ func NewK8sCapacityServiceImpl(Logger *zap.SugaredLogger,
 		k8sApplicationService: k8sApplicationService,
 		K8sUtil:               K8sUtil,
 		k8sCommonService:      k8sCommonService,
+		batchDrains:           make(map[string]*bean.BatchDrainStatus),
 	}
 }
 
@@ -710,6 +733,256 @@ func (impl *K8sCapacityServiceImpl) GetNode(ctx context.Context, clusterId int,
 	return impl.K8sUtil.GetNodeByName(context.Background(), k8sClientSet, nodeName)
 }
 
+func (impl *K8sCapacityServiceImpl) PlanNodeDrain(ctx context.Context, request *bean.NodeDrainPlanRequest) (*bean.NodeDrainPlan, error) {
+	//getting kubernetes clientSet by rest config
+	_, _, k8sClientSet, err := impl.k8sCommonService.GetK8sConfigAndClientsByClusterId(ctx, request.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.planNodeDrain(ctx, k8sClientSet, request)
+}
+
+func (impl *K8sCapacityServiceImpl) planNodeDrain(ctx context.Context, k8sClientSet *kubernetes.Clientset, request *bean.NodeDrainPlanRequest) (*bean.NodeDrainPlan, error) {
+	if request.NodeDrainHelper == nil {
+		request.NodeDrainHelper = &bean.NodeDrainHelper{GracePeriodSeconds: -1}
+	}
+	nodeList, err := impl.K8sUtil.GetNodesList(ctx, k8sClientSet)
+	if err != nil {
+		impl.logger.Errorw("error in getting node list", "err", err, "clusterId", request.ClusterId)
+		return nil, err
+	}
+	drainNodes, err := impl.getNodesToDrain(nodeList.Items, request)
+	if err != nil {
+		return nil, err
+	}
+	podList, err := k8sClientSet.CoreV1().Pods(corev1.NamespaceAll).List(ctx, v1.ListOptions{})
+	if err != nil {
+		impl.logger.Errorw("error in getting pod list", "err", err, "clusterId", request.ClusterId)
+		return nil, err
+	}
+	budgetList, err := k8sClientSet.PolicyV1().PodDisruptionBudgets(corev1.NamespaceAll).List(ctx, v1.ListOptions{})
+	if err != nil {
+		impl.logger.Errorw("error in getting pod disruption budget list", "err", err, "clusterId", request.ClusterId)
+		return nil, err
+	}
+	drainHelper := *request.NodeDrainHelper
+	drainHelper.K8sClientSet = k8sClientSet
+	filters := drainHelper.MakeFilters()
+	podsByNode := make(map[string][]corev1.Pod, len(drainNodes))
+	for _, node := range drainNodes {
+		podsByNode[node.Name] = make([]corev1.Pod, 0)
+	}
+	for _, pod := range podList.Items {
+		if pods, ok := podsByNode[pod.Spec.NodeName]; ok {
+			podsByNode[pod.Spec.NodeName] = append(pods, pod)
+		}
+	}
+	podDeletes := make(map[string][]bean.PodDelete, len(drainNodes))
+	for _, node := range drainNodes {
+		podDeletes[node.Name] = bean.FilterPods(&corev1.PodList{Items: podsByNode[node.Name]}, filters).Items()
+	}
+	plan := impl.buildDrainPlan(drainNodes, podDeletes, nodeList.Items, podList.Items, budgetList.Items, drainHelper.DisableEviction)
+	plan.ClusterId = request.ClusterId
+	return plan, nil
+}
+
+// getNodesToDrain returns the requested nodes in the order requested, or the nodes of the node group by name
+func (impl *K8sCapacityServiceImpl) getNodesToDrain(nodes []corev1.Node, request *bean.NodeDrainPlanRequest) ([]*corev1.Node, error) {
+	var drainNodes []*corev1.Node
+	if len(request.NodeNames) > 0 {
+		nodeByName := make(map[string]*corev1.Node, len(nodes))
+		for i := range nodes {
+			nodeByName[nodes[i].Name] = &nodes[i]
+		}
+		added := make(map[string]bool, len(request.NodeNames))
+		for _, name := range request.NodeNames {
+			node, ok := nodeByName[name]
+			if !ok {
+				return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, UserMessage: fmt.Sprintf("node %s not found", name)}
+			}
+			if !added[name] {
+				added[name] = true
+				drainNodes = append(drainNodes, node)
+			}
+		}
+		return drainNodes, nil
+	}
+	if len(request.NodeGroup) == 0 {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "nodeNames or nodeGroup is required"}
+	}
+	for i := range nodes {
+		if impl.getNodeGroup(&nodes[i]) == request.NodeGroup {
+			drainNodes = append(drainNodes, &nodes[i])
+		}
+	}
+	if len(drainNodes) == 0 {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, UserMessage: fmt.Sprintf("no nodes found in node group %s", request.NodeGroup)}
+	}
+	sort.Slice(drainNodes, func(i, j int) bool {
+		return drainNodes[i].Name < drainNodes[j].Name
+	})
+	return drainNodes, nil
+}
+
+func (impl *K8sCapacityServiceImpl) StartBatchDrain(ctx context.Context, request *bean.BatchDrainRequest, userId int32) (*bean.BatchDrainStatus, error) {
+	impl.logger.Infow("received batch node drain request", "request", request)
+	if request.HealthCheckTimeoutSeconds <= 0 {
+		request.HealthCheckTimeoutSeconds = defaultDrainHealthCheckTimeoutSec
+	}
+	_, _, k8sClientSet, err := impl.k8sCommonService.GetK8sConfigAndClientsByClusterId(ctx, request.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := impl.planNodeDrain(ctx, k8sClientSet, &request.NodeDrainPlanRequest)
+	if err != nil {
+		return nil, err
+	}
+	if blockers := getDrainBlockers(plan, request.IgnoreCapacity); len(blockers) > 0 {
+		message := fmt.Sprintf("nodes can't be drained, %s", strings.Join(blockers, ", "))
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: message, InternalMessage: message}
+	}
+	now := time.Now()
+	batch := &bean.BatchDrainStatus{
+		Id:        uuid.New().String(),
+		ClusterId: request.ClusterId,
+		Status:    bean.BatchDrainStatusRunning,
+		Nodes:     make([]*bean.BatchDrainNodeStatus, 0, len(plan.Nodes)),
+		StartedOn: now,
+		UserId:    userId,
+	}
+	for _, nodePlan := range plan.Nodes {
+		batch.Nodes = append(batch.Nodes, &bean.BatchDrainNodeStatus{Name: nodePlan.Name, Status: bean.BatchDrainStatusPending})
+	}
+	impl.batchDrainLock.Lock()
+	for id, existing := range impl.batchDrains {
+		if existing.FinishedOn != nil && now.Sub(*existing.FinishedOn) > batchDrainRetention {
+			delete(impl.batchDrains, id)
+		} else if existing.ClusterId == request.ClusterId && existing.Status == bean.BatchDrainStatusRunning {
+			impl.batchDrainLock.Unlock()
+			return nil, &util.ApiError{HttpStatusCode: http.StatusConflict, UserMessage: fmt.Sprintf("batch drain %s is running on the cluster", id)}
+		}
+	}
+	impl.batchDrains[batch.Id] = batch
+	impl.batchDrainLock.Unlock()
+	go impl.runBatchDrain(batch.Id, request, plan)
+	return impl.GetBatchDrain(batch.Id)
+}
+
+func (impl *K8sCapacityServiceImpl) runBatchDrain(id string, request *bean.BatchDrainRequest, plan *bean.NodeDrainPlan) {
+	ctx := context.Background()
+	healthCheckTimeout := time.Duration(request.HealthCheckTimeoutSeconds) * time.Second
+	for i, nodePlan := range plan.Nodes {
+		impl.updateBatchDrainNode(id, i, bean.BatchDrainStatusRunning, "")
+		drainHelper := *request.NodeDrainHelper
+		_, err := impl.DrainNode(ctx, &bean.NodeUpdateRequestDto{ClusterId: request.ClusterId, Name: nodePlan.Name, NodeDrainHelper: &drainHelper})
+		if err != nil {
+			impl.logger.Errorw("error in draining node of batch", "err", err, "batchDrainId", id, "nodeName", nodePlan.Name)
+			impl.updateBatchDrainNode(id, i, bean.BatchDrainStatusFailed, err.Error())
+			return
+		}
+		impl.updateBatchDrainNode(id, i, bean.BatchDrainStatusHealthCheck, "")
+		err = impl.waitForWorkloadsReady(ctx, request.ClusterId, nodePlan, healthCheckTimeout)
+		if err != nil {
+			impl.logger.Errorw("workloads of drained node not ready", "err", err, "batchDrainId", id, "nodeName", nodePlan.Name)
+			impl.updateBatchDrainNode(id, i, bean.BatchDrainStatusFailed, err.Error())
+			return
+		}
+		impl.updateBatchDrainNode(id, i, bean.BatchDrainStatusSucceeded, "")
+	}
+}
+
+// updateBatchDrainNode sets the status of a node of a batch, the batch fails with the first node failing and
+// succeeds with the last node succeeding
+func (impl *K8sCapacityServiceImpl) updateBatchDrainNode(id string, index int, status bean.BatchDrainStatusType, message string) {
+	impl.batchDrainLock.Lock()
+	defer impl.batchDrainLock.Unlock()
+	batch, ok := impl.batchDrains[id]
+	if !ok {
+		return
+	}
+	now := time.Now()
+	node := batch.Nodes[index]
+	node.Status, node.Message = status, message
+	switch status {
+	case bean.BatchDrainStatusRunning:
+		node.StartedOn = &now
+	case bean.BatchDrainStatusFailed:
+		node.FinishedOn = &now
+		batch.Status = bean.BatchDrainStatusFailed
+		batch.Message = fmt.Sprintf("draining node %s failed", node.Name)
+		batch.FinishedOn = &now
+	case bean.BatchDrainStatusSucceeded:
+		node.FinishedOn = &now
+		if index == len(batch.Nodes)-1 {
+			batch.Status = bean.BatchDrainStatusSucceeded
+			batch.FinishedOn = &now
+		}
+	}
+}
+
+// waitForWorkloadsReady waits for the replica sets and stateful sets of the pods evicted from a node to have all
+// their replicas ready
+func (impl *K8sCapacityServiceImpl) waitForWorkloadsReady(ctx context.Context, clusterId int, nodePlan *bean.NodeDrainPlanDetail, timeout time.Duration) error {
+	_, _, k8sClientSet, err := impl.k8sCommonService.GetK8sConfigAndClientsByClusterId(ctx, clusterId)
+	if err != nil {
+		return err
+	}
+	workloads := getEvictedWorkloads(nodePlan)
+	var notReady []string
+	err = wait.PollUntilContextTimeout(ctx, drainHealthCheckInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		notReady = notReady[:0]
+		for _, workload := range workloads {
+			var replicas *int32
+			var readyReplicas int32
+			switch workload.OwnerKind {
+			case "ReplicaSet":
+				replicaSet, err := k8sClientSet.AppsV1().ReplicaSets(workload.Namespace).Get(ctx, workload.OwnerName, v1.GetOptions{})
+				if apierrors.IsNotFound(err) {
+					continue
+				} else if err != nil {
+					impl.logger.Warnw("error in getting replica set", "err", err, "namespace", workload.Namespace, "name", workload.OwnerName)
+					return false, nil
+				}
+				replicas, readyReplicas = replicaSet.Spec.Replicas, replicaSet.Status.ReadyReplicas
+			case "StatefulSet":
+				statefulSet, err := k8sClientSet.AppsV1().StatefulSets(workload.Namespace).Get(ctx, workload.OwnerName, v1.GetOptions{})
+				if apierrors.IsNotFound(err) {
+					continue
+				} else if err != nil {
+					impl.logger.Warnw("error in getting stateful set", "err", err, "namespace", workload.Namespace, "name", workload.OwnerName)
+					return false, nil
+				}
+				replicas, readyReplicas = statefulSet.Spec.Replicas, statefulSet.Status.ReadyReplicas
+			}
+			if !isWorkloadReady(replicas, readyReplicas) {
+				notReady = append(notReady, fmt.Sprintf("%s/%s", workload.Namespace, workload.OwnerName))
+			}
+		}
+		return len(notReady) == 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("workloads not ready after %s: %s", timeout, strings.Join(notReady, ", "))
+	}
+	return nil
+}
+
+func (impl *K8sCapacityServiceImpl) GetBatchDrain(id string) (*bean.BatchDrainStatus, error) {
+	impl.batchDrainLock.RLock()
+	defer impl.batchDrainLock.RUnlock()
+	batch, ok := impl.batchDrains[id]
+	if !ok {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, UserMessage: "batch drain not found"}
+	}
+	// copied as the running drain keeps updating it
+	batchCopy := *batch
+	batchCopy.Nodes = make([]*bean.BatchDrainNodeStatus, 0, len(batch.Nodes))
+	for _, node := range batch.Nodes {
+		nodeCopy := *node
+		batchCopy.Nodes = append(batchCopy.Nodes, &nodeCopy)
+	}
+	return &batchCopy, nil
+}
+
 func validateTaintEditRequest(reqTaints []corev1.Taint) error {
 	if len(reqTaints) == 0 {
 		return nil
@@ -809,18 +1082,19 @@ func (impl *K8sCapacityServiceImpl) evictPods(pods []corev1.Pod, k8sClientSet *k
 		go func(pod corev1.Pod, returnCh chan error) {
 			// Create a temporary pod, so we don't mutate the pod in the loop.
 			activePod := pod
-			err := k8s2.EvictPod(activePod, k8sClientSet, evictionGroupVersion, deleteOptions)
-			if err == nil {
-				returnCh <- nil
-				return
-			} else if apierrors.IsNotFound(err) {
-				returnCh <- nil
-				return
-			} else if apierrors.IsTooManyRequests(err) {
-				time.Sleep(5 * time.Second)
-			} else {
-				returnCh <- fmt.Errorf("error when evicting pods/%q -n %q: %v", activePod.Name, activePod.Namespace, err)
-				return
+			deadline := time.Now().Add(evictionRetryTimeout)
+			for {
+				err := k8s2.EvictPod(activePod, k8sClientSet, evictionGroupVersion, deleteOptions)
+				if err == nil || apierrors.IsNotFound(err) {
+					returnCh <- nil
+					return
+				} else if apierrors.IsTooManyRequests(err) && time.Now().Before(deadline) {
+					// the eviction is refused by a disruption budget until replacements of evicted pods are healthy
+					time.Sleep(evictionRetryInterval)
+				} else {
+					returnCh <- fmt.Errorf("error when evicting pods/%q -n %q: %v", activePod.Name, activePod.Namespace, err)
+					return
+				}
 			}
 		}(pod, returnCh)
 	}
@@ -37,6 +37,11 @@ type K8sInformerFactoryImpl struct {
 	globalMapClusterNamespace sync.Map // {"cluster1":{"ns1":true","ns2":true"}}
 	informerStopper           map[string]chan struct{}
 	k8sUtil                   *k8s.K8sServiceImpl
+	// informerStopper, informerFactories and eventHandlerBuilders are guarded by eventLock, events are watched
+	// on the informer factory of a cluster so that they stop with its namespace informer
+	informerFactories    map[string]kubeinformers.SharedInformerFactory
+	eventHandlerBuilders []func(clusterName string) cache.ResourceEventHandler
+	eventLock            sync.Mutex
 }
 
 type K8sInformerFactory interface {
@@ -44,6 +49,11 @@ type K8sInformerFactory interface {
 	BuildInformer(clusterInfo []*bean.ClusterInfo)
 	CleanNamespaceInformer(clusterName string)
 	DeleteClusterFromCache(clusterName string)
+	// WatchEvents starts an informer of the kubernetes events of every cluster, including the clusters added
+	// later, and sends them to the handler built for the cluster. The informer watches every namespace and keeps
+	// all the events the api server holds in memory, about the size of the event store of each cluster, as the
+	// namespaces of interest change at runtime and can't be expressed as a field selector
+	WatchEvents(handlerFor func(clusterName string) cache.ResourceEventHandler)
 }
 
 func NewK8sInformerFactoryImpl(logger *zap.SugaredLogger, globalMapClusterNamespace sync.Map, k8sUtil *k8s.K8sServiceImpl) *K8sInformerFactoryImpl {
@@ -53,6 +63,7 @@ func NewK8sInformerFactoryImpl(logger *zap.SugaredLogger, globalMapClusterNamesp
 		k8sUtil:                   k8sUtil,
 	}
 	informerFactory.informerStopper = make(map[string]chan struct{})
+	informerFactory.informerFactories = make(map[string]kubeinformers.SharedInformerFactory)
 	return informerFactory
 }
 
@@ -115,17 +126,26 @@ func (impl *K8sInformerFactoryImpl) buildInformerAndNamespaceList(clusterName st
 			}
 		},
 	})
-	informerFactory.Start(stopper)
+	impl.eventLock.Lock()
+	for _, handlerFor := range impl.eventHandlerBuilders {
+		informerFactory.Core().V1().Events().Informer().AddEventHandler(handlerFor(clusterName))
+	}
+	impl.informerFactories[clusterName] = informerFactory
 	impl.informerStopper[clusterName] = stopper
+	impl.eventLock.Unlock()
+	informerFactory.Start(stopper)
 	return impl.globalMapClusterNamespace
 }
 
 func (impl *K8sInformerFactoryImpl) CleanNamespaceInformer(clusterName string) {
+	impl.eventLock.Lock()
+	defer impl.eventLock.Unlock()
 	stopper := impl.informerStopper[clusterName]
 	if stopper != nil {
 		close(stopper)
 		delete(impl.informerStopper, clusterName)
 	}
+	delete(impl.informerFactories, clusterName)
 	return
 }
 
@@ -134,3 +154,17 @@ func (impl *K8sInformerFactoryImpl) DeleteClusterFromCache(clusterName string) {
 	impl.globalMapClusterNamespace.Delete(clusterName)
 	return
 }
+
+func (impl *K8sInformerFactoryImpl) WatchEvents(handlerFor func(clusterName string) cache.ResourceEventHandler) {
+	impl.eventLock.Lock()
+	defer impl.eventLock.Unlock()
+	impl.eventHandlerBuilders = append(impl.eventHandlerBuilders, handlerFor)
+	for clusterName, informerFactory := range impl.informerFactories {
+		informerFactory.Core().V1().Events().Informer().AddEventHandler(handlerFor(clusterName))
+		stopper := impl.informerStopper[clusterName]
+		if stopper != nil {
+			// starts the events informer, the informers already running are left as they are
+			informerFactory.Start(stopper)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podLogs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster/read"
+	"github.com/devtron-labs/devtron/pkg/k8s/podLogs/bean"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type PodLogsService interface {
+	ValidateAggregatedLogsRequestQuery(r *http.Request) (*bean.AggregatedLogsRequest, error)
+	// GetAggregatedLogs merges the log streams of the containers of the matching pods, lines are streamed with their
+	// timestamp first like the logs of a single pod. Only the pods passing authorise are streamed.
+	GetAggregatedLogs(ctx context.Context, request *bean.AggregatedLogsRequest, authorise func(pod *v1.Pod) bool) (io.ReadCloser, error)
+}
+
+type PodLogsServiceImpl struct {
+	logger             *zap.SugaredLogger
+	clusterReadService read.ClusterReadService
+	k8sUtil            *k8s.K8sServiceImpl
+	config             *bean.PodLogsConfig
+}
+
+func NewPodLogsServiceImpl(logger *zap.SugaredLogger, clusterReadService read.ClusterReadService,
+	k8sUtil *k8s.K8sServiceImpl) (*PodLogsServiceImpl, error) {
+	config := &bean.PodLogsConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing pod logs config", "err", err)
+		return nil, err
+	}
+	return &PodLogsServiceImpl{
+		logger:             logger,
+		clusterReadService: clusterReadService,
+		k8sUtil:            k8sUtil,
+		config:             config,
+	}, nil
+}
+
+func (impl *PodLogsServiceImpl) ValidateAggregatedLogsRequestQuery(r *http.Request) (*bean.AggregatedLogsRequest, error) {
+	v := r.URL.Query()
+	request := &bean.AggregatedLogsRequest{
+		Namespace:     v.Get("namespace"),
+		LabelSelector: v.Get("labelSelector"),
+		Include:       v.Get("include"),
+		Exclude:       v.Get("exclude"),
+	}
+	var err error
+	if request.ClusterId, err = strconv.Atoi(v.Get("clusterId")); err != nil || request.ClusterId <= 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "invalid param: clusterId", "invalid param: clusterId")
+	}
+	if len(request.Namespace) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "missing required param namespace", "missing required param namespace")
+	}
+	if name := v.Get("name"); len(name) > 0 {
+		request.Workload = &bean.WorkloadIdentifier{
+			GroupVersionKind: schema.GroupVersionKind{Group: v.Get("group"), Version: v.Get("version"), Kind: v.Get("kind")},
+			Name:             name,
+		}
+		if len(request.Workload.GroupVersionKind.Version) == 0 || len(request.Workload.GroupVersionKind.Kind) == 0 {
+			return nil, util.NewApiError(http.StatusBadRequest, "version and kind of the workload are required", "missing version or kind of workload")
+		}
+	}
+	if (request.Workload != nil) == (len(request.LabelSelector) > 0) {
+		return nil, util.NewApiError(http.StatusBadRequest, "logs are streamed by either a label selector or a workload", "exactly one of labelSelector and name is required")
+	}
+	if len(request.LabelSelector) > 0 {
+		if _, err = labels.Parse(request.LabelSelector); err != nil {
+			return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("invalid label selector: %s", err.Error()), err.Error())
+		}
+	}
+	if containers := v.Get("containers"); len(containers) > 0 {
+		request.Containers = strings.Split(containers, ",")
+	}
+	if param := v.Get("sinceSeconds"); len(param) > 0 {
+		if request.SinceSeconds, err = strconv.Atoi(param); err != nil || request.SinceSeconds <= 0 {
+			return nil, util.NewApiError(http.StatusBadRequest, "invalid value provided for sinceSeconds", "invalid value provided for sinceSeconds")
+		}
+	}
+	if param := v.Get("sinceTime"); len(param) > 0 {
+		sinceTime, err := strconv.ParseInt(param, 10, 64)
+		if err != nil || sinceTime <= 0 {
+			return nil, util.NewApiError(http.StatusBadRequest, "invalid value provided for sinceTime", "invalid value provided for sinceTime")
+		}
+		since := metav1.Unix(sinceTime, 0)
+		request.SinceTime = &since
+	}
+	if param := v.Get("tailLines"); len(param) > 0 {
+		if request.TailLines, err = strconv.Atoi(param); err != nil || request.TailLines <= 0 {
+			return nil, util.NewApiError(http.StatusBadRequest, "invalid value provided for tailLines", "invalid value provided for tailLines")
+		}
+	}
+	request.Follow, _ = strconv.ParseBool(v.Get("follow"))
+	return request, nil
+}
+
+func (impl *PodLogsServiceImpl) GetAggregatedLogs(ctx context.Context, request *bean.AggregatedLogsRequest, authorise func(pod *v1.Pod) bool) (io.ReadCloser, error) {
+	filter, err := newLineFilter(request.Include, request.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	clusterBean, err := impl.clusterReadService.FindById(request.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster", "clusterId", request.ClusterId, "err", err)
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "cluster not found", err.Error())
+		}
+		return nil, err
+	}
+	restConfig, err := impl.k8sUtil.GetRestConfigByCluster(clusterBean.GetClusterConfig())
+	if err != nil {
+		impl.logger.Errorw("error in getting rest config by cluster", "clusterId", request.ClusterId, "err", err)
+		return nil, err
+	}
+	_, clientSet, err := impl.k8sUtil.GetK8sConfigAndClientsByRestConfig(restConfig)
+	if err != nil {
+		impl.logger.Errorw("error in getting client set", "clusterId", request.ClusterId, "err", err)
+		return nil, err
+	}
+	selector := request.LabelSelector
+	if request.Workload != nil {
+		resourceIf, _, err := impl.k8sUtil.GetResourceIf(restConfig, request.Workload.GroupVersionKind)
+		if err != nil {
+			impl.logger.Errorw("error in getting resource interface", "gvk", request.Workload.GroupVersionKind, "err", err)
+			return nil, err
+		}
+		workload, err := resourceIf.Namespace(request.Namespace).Get(ctx, request.Workload.Name, metav1.GetOptions{})
+		if err != nil {
+			impl.logger.Errorw("error in fetching workload", "namespace", request.Namespace, "workload", request.Workload, "err", err)
+			return nil, err
+		}
+		workloadSelector, err := getWorkloadSelector(workload)
+		if err != nil {
+			return nil, err
+		}
+		selector = workloadSelector.String()
+	}
+	pods, err := clientSet.CoreV1().Pods(request.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		impl.logger.Errorw("error in fetching pods", "namespace", request.Namespace, "selector", selector, "err", err)
+		return nil, err
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	reader, writer := io.Pipe()
+	a := &aggregator{
+		logger:     impl.logger,
+		k8sUtil:    impl.k8sUtil,
+		config:     impl.config,
+		restConfig: restConfig,
+		clientSet:  clientSet,
+		request:    request,
+		selector:   selector,
+		filter:     filter,
+		authorise:  authorise,
+		ctx:        streamCtx,
+		writer:     writer,
+		streams:    make(map[string]*containerStream),
+		authorised: make(map[string]bool),
+	}
+	authorisedPods := 0
+	for i := range pods.Items {
+		if a.startPod(&pods.Items[i], true) {
+			authorisedPods++
+		}
+	}
+	if len(pods.Items) > 0 && authorisedPods == 0 {
+		cancel()
+		a.wait()
+		return nil, util.NewApiError(http.StatusForbidden, "unauthorized", "none of the matching pods are authorised")
+	}
+	if request.Follow {
+		a.watch(pods.ResourceVersion)
+	}
+	go a.wait()
+	return &aggregatedStream{reader: reader, cancel: cancel}, nil
+}
+
+// aggregatedStream stops every container stream once it is closed
+type aggregatedStream struct {
+	reader *io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (stream *aggregatedStream) Read(p []byte) (int, error) {
+	return stream.reader.Read(p)
+}
+
+func (stream *aggregatedStream) Close() error {
+	stream.cancel()
+	return stream.reader.Close()
+}
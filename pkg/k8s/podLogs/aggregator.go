@@ -0,0 +1,240 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podLogs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/pkg/k8s/podLogs/bean"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+type containerStream struct {
+	containerId string
+	active      bool
+}
+
+// aggregator streams the containers of the matching pods into one writer, a container is streamed once per
+// container instance so that a restarted container is picked up again while following
+type aggregator struct {
+	logger     *zap.SugaredLogger
+	k8sUtil    *k8s.K8sServiceImpl
+	config     *bean.PodLogsConfig
+	restConfig *rest.Config
+	clientSet  *kubernetes.Clientset
+	request    *bean.AggregatedLogsRequest
+	selector   string
+	filter     *lineFilter
+	authorise  func(pod *v1.Pod) bool
+	ctx        context.Context
+	writer     *io.PipeWriter
+	writeLock  sync.Mutex
+	lock       sync.Mutex
+	streams    map[string]*containerStream
+	authorised map[string]bool
+	active     int
+	wg         sync.WaitGroup
+}
+
+// startPod streams the started containers of a pod, initial pods are streamed from the requested time while pods
+// which appear later are streamed from their start
+func (a *aggregator) startPod(pod *v1.Pod, initial bool) bool {
+	if !a.isAuthorised(pod) {
+		return false
+	}
+	for containerName, containerId := range getStartedContainers(pod, a.request.Containers) {
+		a.startContainer(pod.Name, containerName, containerId, initial)
+	}
+	return true
+}
+
+func (a *aggregator) isAuthorised(pod *v1.Pod) bool {
+	uid := string(pod.UID)
+	a.lock.Lock()
+	authorised, found := a.authorised[uid]
+	a.lock.Unlock()
+	if found {
+		return authorised
+	}
+	authorised = a.authorise(pod)
+	a.lock.Lock()
+	a.authorised[uid] = authorised
+	a.lock.Unlock()
+	return authorised
+}
+
+func (a *aggregator) startContainer(podName, containerName, containerId string, initial bool) {
+	key := getStreamKey(podName, containerName)
+	a.lock.Lock()
+	if s, found := a.streams[key]; found && (s.active || s.containerId == containerId) {
+		a.lock.Unlock()
+		return
+	}
+	if a.active >= a.config.MaxStreams {
+		// recorded as streamed so that the limit is reported once per container instance
+		a.streams[key] = &containerStream{containerId: containerId}
+		a.lock.Unlock()
+		// written aside as the initial pods are started before the stream is read
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			_ = a.writeNotice(podName, containerName, fmt.Sprintf("not streamed, at most %d containers are streamed at once", a.config.MaxStreams))
+		}()
+		return
+	}
+	_, restarted := a.streams[key]
+	a.streams[key] = &containerStream{containerId: containerId, active: true}
+	a.active++
+	a.lock.Unlock()
+
+	sinceTime, sinceSeconds, tailLines := (*metav1.Time)(nil), 0, 0
+	if initial && !restarted {
+		sinceTime, sinceSeconds, tailLines = a.request.SinceTime, a.request.SinceSeconds, a.request.TailLines
+	}
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.streamContainer(podName, containerName, sinceTime, sinceSeconds, tailLines)
+		a.lock.Lock()
+		a.streams[key].active = false
+		a.active--
+		a.lock.Unlock()
+	}()
+}
+
+func (a *aggregator) streamContainer(podName, containerName string, sinceTime *metav1.Time, sinceSeconds int, tailLines int) {
+	var after time.Time
+	if sinceTime != nil {
+		// since time is sent in seconds, the lines already streamed in the same second are skipped here
+		after = sinceTime.Time
+		sinceSeconds = 0
+	} else {
+		startTime := metav1.Unix(0, 0)
+		sinceTime = &startTime
+	}
+	stream, err := a.k8sUtil.GetPodLogs(a.ctx, rest.CopyConfig(a.restConfig), podName, a.request.Namespace, sinceTime, tailLines, sinceSeconds, a.request.Follow, containerName, false)
+	if err != nil {
+		if a.ctx.Err() == nil {
+			a.logger.Errorw("error in streaming container logs", "namespace", a.request.Namespace, "pod", podName, "container", containerName, "err", err)
+			_ = a.writeNotice(podName, containerName, fmt.Sprintf("error in streaming logs: %s", err.Error()))
+		}
+		return
+	}
+	defer stream.Close()
+	reader := bufio.NewReader(stream)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			timestamp, message, ok := parseLine(line)
+			if ok && !timestamp.Before(after) && a.filter.matches(message) {
+				if writeErr := a.write(formatLine(timestamp, podName, containerName, message)); writeErr != nil {
+					return
+				}
+			}
+		}
+		if err != nil {
+			if err != io.EOF && a.ctx.Err() == nil {
+				a.logger.Debugw("container log stream ended", "pod", podName, "container", containerName, "err", err)
+			}
+			return
+		}
+	}
+}
+
+// watch picks up the containers of the matching pods as they start, the pods are listed again whenever the watch ends
+func (a *aggregator) watch(resourceVersion string) {
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		for {
+			podWatch, err := a.clientSet.CoreV1().Pods(a.request.Namespace).Watch(a.ctx, metav1.ListOptions{LabelSelector: a.selector, ResourceVersion: resourceVersion})
+			if err == nil {
+				a.handleEvents(podWatch)
+			} else if a.ctx.Err() == nil {
+				a.logger.Errorw("error in watching pods", "namespace", a.request.Namespace, "selector", a.selector, "err", err)
+			}
+			if err != nil || a.ctx.Err() != nil {
+				select {
+				case <-a.ctx.Done():
+					return
+				case <-time.After(time.Duration(a.config.WatchRetryIntervalSeconds) * time.Second):
+				}
+			}
+			pods, err := a.clientSet.CoreV1().Pods(a.request.Namespace).List(a.ctx, metav1.ListOptions{LabelSelector: a.selector})
+			if err != nil {
+				if a.ctx.Err() != nil {
+					return
+				}
+				a.logger.Errorw("error in listing pods", "namespace", a.request.Namespace, "selector", a.selector, "err", err)
+				resourceVersion = ""
+				continue
+			}
+			for i := range pods.Items {
+				a.startPod(&pods.Items[i], false)
+			}
+			resourceVersion = pods.ResourceVersion
+		}
+	}()
+}
+
+func (a *aggregator) handleEvents(podWatch watch.Interface) {
+	defer podWatch.Stop()
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case event, ok := <-podWatch.ResultChan():
+			if !ok || event.Type == watch.Error {
+				return
+			}
+			if event.Type != watch.Added && event.Type != watch.Modified {
+				continue
+			}
+			if pod, ok := event.Object.(*v1.Pod); ok {
+				a.startPod(pod, false)
+			}
+		}
+	}
+}
+
+func (a *aggregator) write(line string) error {
+	a.writeLock.Lock()
+	defer a.writeLock.Unlock()
+	_, err := a.writer.Write([]byte(line))
+	return err
+}
+
+func (a *aggregator) writeNotice(podName, containerName, message string) error {
+	return a.write(formatLine(time.Now(), podName, containerName, message))
+}
+
+// wait closes the stream once every container stream has ended, which happens on close when following
+func (a *aggregator) wait() {
+	a.wg.Wait()
+	_ = a.writer.Close()
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type PodLogsConfig struct {
+	MaxStreams                int `env:"AGGREGATED_LOGS_MAX_STREAMS" envDefault:"50" description:"Most containers streamed at once by an aggregated log stream"`
+	WatchRetryIntervalSeconds int `env:"AGGREGATED_LOGS_WATCH_RETRY_INTERVAL_SECONDS" envDefault:"5" description:"Wait before the pods of a followed aggregated log stream are watched again after the watch fails"`
+}
+
+// WorkloadIdentifier is a workload whose pods are streamed, pods are matched by the selector in its spec
+type WorkloadIdentifier struct {
+	GroupVersionKind schema.GroupVersionKind `json:"groupVersionKind"`
+	Name             string                  `json:"name"`
+}
+
+// AggregatedLogsRequest streams the logs of all the containers of the pods matching a label selector or a workload,
+// with the lines of every container prefixed by pod/container
+type AggregatedLogsRequest struct {
+	ClusterId     int
+	Namespace     string
+	LabelSelector string
+	Workload      *WorkloadIdentifier
+	// Containers limits the containers streamed by name, all the containers of a pod are streamed when empty
+	Containers []string
+	// Include and Exclude are regular expressions, a line is streamed when it matches Include and doesn't match Exclude
+	Include      string
+	Exclude      string
+	SinceSeconds int
+	SinceTime    *metav1.Time
+	TailLines    int
+	// Follow keeps streaming, containers of pods which appear or restart are picked up as they start
+	Follow bool
+}
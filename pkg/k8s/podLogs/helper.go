@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podLogs
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/util"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type lineFilter struct {
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+func newLineFilter(include, exclude string) (*lineFilter, error) {
+	filter := &lineFilter{}
+	var err error
+	if len(include) > 0 {
+		if filter.include, err = regexp.Compile(include); err != nil {
+			return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("invalid include pattern: %s", err.Error()), err.Error())
+		}
+	}
+	if len(exclude) > 0 {
+		if filter.exclude, err = regexp.Compile(exclude); err != nil {
+			return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("invalid exclude pattern: %s", err.Error()), err.Error())
+		}
+	}
+	return filter, nil
+}
+
+func (filter *lineFilter) matches(message string) bool {
+	if filter.include != nil && !filter.include.MatchString(message) {
+		return false
+	}
+	return filter.exclude == nil || !filter.exclude.MatchString(message)
+}
+
+// parseLine splits a log line streamed with timestamps into its timestamp and message
+func parseLine(line string) (time.Time, string, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	timestamp, message, _ := strings.Cut(line, " ")
+	parsedTime, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	return parsedTime, message, true
+}
+
+// formatLine keeps the timestamp first, the log stream is read like the stream of a single pod
+func formatLine(timestamp time.Time, podName, containerName, message string) string {
+	return fmt.Sprintf("%s [%s/%s] %s\n", timestamp.UTC().Format(time.RFC3339Nano), podName, containerName, message)
+}
+
+func getStreamKey(podName, containerName string) string {
+	return fmt.Sprintf("%s/%s", podName, containerName)
+}
+
+// getWorkloadSelector reads the pod selector of a workload, a label selector for deployments, stateful sets, daemon
+// sets, replica sets, jobs and rollouts or a plain label set for replication controllers
+func getWorkloadSelector(workload *unstructured.Unstructured) (labels.Selector, error) {
+	selectorMap, found, err := unstructured.NestedMap(workload.Object, "spec", "selector")
+	if err != nil || !found || len(selectorMap) == 0 {
+		message := fmt.Sprintf("%s %s has no pod selector", workload.GetKind(), workload.GetName())
+		return nil, util.NewApiError(http.StatusBadRequest, message, message)
+	}
+	_, hasMatchLabels := selectorMap["matchLabels"]
+	_, hasMatchExpressions := selectorMap["matchExpressions"]
+	if !hasMatchLabels && !hasMatchExpressions {
+		set := labels.Set{}
+		for key, value := range selectorMap {
+			set[key] = fmt.Sprint(value)
+		}
+		return labels.SelectorFromSet(set), nil
+	}
+	labelSelector := &metav1.LabelSelector{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(selectorMap, labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	return metav1.LabelSelectorAsSelector(labelSelector)
+}
+
+// getStartedContainers returns the containers of a pod which have logs, by the id of the running or last run instance
+func getStartedContainers(pod *v1.Pod, containerNames []string) map[string]string {
+	containerIds := make(map[string]string)
+	for _, status := range pod.Status.ContainerStatuses {
+		if len(containerNames) > 0 && !slices.Contains(containerNames, status.Name) {
+			continue
+		}
+		if status.State.Running != nil {
+			containerIds[status.Name] = status.ContainerID
+		} else if status.State.Terminated != nil {
+			containerIds[status.Name] = status.State.Terminated.ContainerID
+		}
+	}
+	return containerIds
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podLogs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestLineFilter(t *testing.T) {
+	filter, err := newLineFilter("(?i)error|warn", "healthz")
+	assert.NoError(t, err)
+	assert.True(t, filter.matches("ERROR connecting to db"))
+	assert.True(t, filter.matches("warn: slow request"))
+	assert.False(t, filter.matches("info: started"))
+	assert.False(t, filter.matches("error GET /healthz"))
+
+	filter, err = newLineFilter("", "")
+	assert.NoError(t, err)
+	assert.True(t, filter.matches("anything"))
+
+	_, err = newLineFilter("(", "")
+	assert.Error(t, err)
+	_, err = newLineFilter("", "[")
+	assert.Error(t, err)
+}
+
+func TestParseAndFormatLine(t *testing.T) {
+	timestamp, message, ok := parseLine("2024-05-01T10:00:00.123456789Z GET /api 200\n")
+	assert.True(t, ok)
+	assert.Equal(t, "GET /api 200", message)
+	assert.Equal(t, "2024-05-01T10:00:00.123456789Z [web-1/app] GET /api 200\n", formatLine(timestamp, "web-1", "app", message))
+
+	_, message, ok = parseLine("2024-05-01T10:00:00Z\n")
+	assert.True(t, ok)
+	assert.Equal(t, "", message)
+
+	_, _, ok = parseLine("not a timestamp\n")
+	assert.False(t, ok)
+
+	assert.Equal(t, "1970-01-01T00:00:01Z [web-1/app] done\n", formatLine(time.Unix(1, 0), "web-1", "app", "done"))
+}
+
+func TestGetWorkloadSelector(t *testing.T) {
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "Deployment",
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"app": "web"},
+				"matchExpressions": []interface{}{
+					map[string]interface{}{"key": "tier", "operator": "In", "values": []interface{}{"frontend"}},
+				},
+			},
+		},
+	}}
+	selector, err := getWorkloadSelector(deployment)
+	assert.NoError(t, err)
+	assert.Equal(t, "app=web,tier in (frontend)", selector.String())
+
+	replicationController := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "ReplicationController",
+		"spec": map[string]interface{}{"selector": map[string]interface{}{"app": "web"}},
+	}}
+	selector, err = getWorkloadSelector(replicationController)
+	assert.NoError(t, err)
+	assert.Equal(t, "app=web", selector.String())
+
+	cronJob := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "CronJob",
+		"spec": map[string]interface{}{"schedule": "* * * * *"},
+	}}
+	_, err = getWorkloadSelector(cronJob)
+	assert.Error(t, err)
+}
+
+func TestGetStartedContainers(t *testing.T) {
+	pod := &v1.Pod{Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{
+		{Name: "app", ContainerID: "containerd://a2", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+		{Name: "sidecar", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ContainerID: "containerd://s1"}}},
+		{Name: "pending", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{}}},
+	}}}
+	assert.Equal(t, map[string]string{"app": "containerd://a2", "sidecar": "containerd://s1"}, getStartedContainers(pod, nil))
+	assert.Equal(t, map[string]string{"app": "containerd://a2"}, getStartedContainers(pod, []string{"app", "pending"}))
+}
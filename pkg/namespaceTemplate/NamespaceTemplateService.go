@@ -0,0 +1,593 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespaceTemplate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	k8sUtil "github.com/devtron-labs/common-lib/utils/k8s"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/util"
+	envBean "github.com/devtron-labs/devtron/pkg/cluster/environment/bean"
+	envRepository "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/dockerRegistry"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/namespaceTemplate/bean"
+	"github.com/devtron-labs/devtron/pkg/namespaceTemplate/repository"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/variables"
+	"github.com/devtron-labs/devtron/pkg/variables/parsers"
+	"go.uber.org/zap"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/pointer"
+)
+
+const applyTimeout = 2 * time.Minute
+
+type NamespaceTemplateService interface {
+	GetAll() ([]*bean.NamespaceTemplateDto, error)
+	GetById(id int) (*bean.NamespaceTemplateDto, error)
+	Create(request *bean.NamespaceTemplateDto) (*bean.NamespaceTemplateDto, error)
+	// Update saves the template, the namespaces it was applied to are only changed once it is applied again
+	Update(request *bean.NamespaceTemplateDto) (*bean.NamespaceTemplateDto, error)
+	// Delete leaves the objects applied from the template in their namespaces
+	Delete(id int, userId int32) error
+	GetEnvironments(templateId int) ([]*bean.TemplateEnvironmentDto, error)
+	// Preview diffs the objects of a template rendered for an environment against the live ones of its namespace,
+	// along with the objects of the template applied before which would be deleted
+	Preview(templateId, envId int) (*bean.NamespaceTemplatePreview, error)
+	// Apply applies a template to environments, to every environment it was applied to before when none are given
+	Apply(request *bean.ApplyTemplateRequest) ([]*bean.TemplateEnvironmentDto, error)
+	// ProvisionNamespace applies the requested template, else the default one, to the namespace of a created environment
+	ProvisionNamespace(env *envBean.EnvironmentBean, userId int32)
+}
+
+type NamespaceTemplateServiceImpl struct {
+	logger                         *zap.SugaredLogger
+	templateRepository             repository.NamespaceTemplateRepository
+	templateEnvironmentRepository  repository.NamespaceTemplateEnvironmentRepository
+	environmentRepository          envRepository.EnvironmentRepository
+	dockerArtifactStoreRepository  dockerRegistryRepository.DockerArtifactStoreRepository
+	dockerRegistryIpsConfigService dockerRegistry.DockerRegistryIpsConfigService
+	scopedVariableManager          variables.ScopedVariableManager
+	k8sCommonService               k8s.K8sCommonService
+	k8sUtil                        *k8sUtil.K8sServiceImpl
+}
+
+func NewNamespaceTemplateServiceImpl(logger *zap.SugaredLogger,
+	templateRepository repository.NamespaceTemplateRepository,
+	templateEnvironmentRepository repository.NamespaceTemplateEnvironmentRepository,
+	environmentRepository envRepository.EnvironmentRepository,
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository,
+	dockerRegistryIpsConfigService dockerRegistry.DockerRegistryIpsConfigService,
+	scopedVariableManager variables.ScopedVariableManager,
+	k8sCommonService k8s.K8sCommonService,
+	k8sUtil *k8sUtil.K8sServiceImpl) *NamespaceTemplateServiceImpl {
+	return &NamespaceTemplateServiceImpl{
+		logger:                         logger,
+		templateRepository:             templateRepository,
+		templateEnvironmentRepository:  templateEnvironmentRepository,
+		environmentRepository:          environmentRepository,
+		dockerArtifactStoreRepository:  dockerArtifactStoreRepository,
+		dockerRegistryIpsConfigService: dockerRegistryIpsConfigService,
+		scopedVariableManager:          scopedVariableManager,
+		k8sCommonService:               k8sCommonService,
+		k8sUtil:                        k8sUtil,
+	}
+}
+
+func toDto(model *repository.NamespaceTemplate) *bean.NamespaceTemplateDto {
+	return &bean.NamespaceTemplateDto{
+		Id:          model.Id,
+		Name:        model.Name,
+		Description: model.Description,
+		Manifest:    model.Manifest,
+		RegistryIds: model.RegistryIds,
+		IsDefault:   model.IsDefault,
+	}
+}
+
+func (impl *NamespaceTemplateServiceImpl) GetAll() ([]*bean.NamespaceTemplateDto, error) {
+	models, err := impl.templateRepository.FindAllActive()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching namespace templates", "err", err)
+		return nil, err
+	}
+	dtos := make([]*bean.NamespaceTemplateDto, 0, len(models))
+	for _, model := range models {
+		dtos = append(dtos, toDto(model))
+	}
+	return dtos, nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) getTemplate(id int) (*repository.NamespaceTemplate, error) {
+	model, err := impl.templateRepository.FindById(id)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "namespace template not found", err.Error())
+		}
+		impl.logger.Errorw("error in fetching namespace template", "id", id, "err", err)
+		return nil, err
+	}
+	return model, nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) getEnvironment(id int) (*envRepository.Environment, error) {
+	env, err := impl.environmentRepository.FindById(id)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "environment not found", err.Error())
+		}
+		impl.logger.Errorw("error in fetching environment", "id", id, "err", err)
+		return nil, err
+	}
+	return env, nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) GetById(id int) (*bean.NamespaceTemplateDto, error) {
+	model, err := impl.getTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+	return toDto(model), nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) validate(request *bean.NamespaceTemplateDto) error {
+	if err := validateTemplate(request.Manifest); err != nil {
+		return err
+	}
+	existing, err := impl.templateRepository.FindByName(request.Name)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching namespace template by name", "name", request.Name, "err", err)
+		return err
+	}
+	if existing != nil && existing.Id > 0 && existing.Id != request.Id {
+		return util.NewApiError(http.StatusConflict, "namespace template with the same name exists", "duplicate namespace template name")
+	}
+	for _, registryId := range request.RegistryIds {
+		if _, err = impl.dockerArtifactStoreRepository.FindOne(registryId); err != nil {
+			if util.IsErrNoRows(err) {
+				msg := fmt.Sprintf("container registry %s not found", registryId)
+				return util.NewApiError(http.StatusBadRequest, msg, msg)
+			}
+			impl.logger.Errorw("error in fetching container registry", "registryId", registryId, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) Create(request *bean.NamespaceTemplateDto) (*bean.NamespaceTemplateDto, error) {
+	if err := impl.validate(request); err != nil {
+		return nil, err
+	}
+	model := &repository.NamespaceTemplate{
+		Name:        request.Name,
+		Description: request.Description,
+		Manifest:    request.Manifest,
+		RegistryIds: request.RegistryIds,
+		IsDefault:   request.IsDefault,
+		Active:      true,
+		AuditLog:    sql.NewDefaultAuditLog(request.UserId),
+	}
+	err := impl.templateRepository.Save(model)
+	if err != nil {
+		impl.logger.Errorw("error in saving namespace template", "name", request.Name, "err", err)
+		return nil, err
+	}
+	if err = impl.unsetOtherDefault(model); err != nil {
+		return nil, err
+	}
+	return toDto(model), nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) Update(request *bean.NamespaceTemplateDto) (*bean.NamespaceTemplateDto, error) {
+	model, err := impl.getTemplate(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err = impl.validate(request); err != nil {
+		return nil, err
+	}
+	model.Name = request.Name
+	model.Description = request.Description
+	model.Manifest = request.Manifest
+	model.RegistryIds = request.RegistryIds
+	model.IsDefault = request.IsDefault
+	model.UpdateAuditLog(request.UserId)
+	err = impl.templateRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in updating namespace template", "id", request.Id, "err", err)
+		return nil, err
+	}
+	if err = impl.unsetOtherDefault(model); err != nil {
+		return nil, err
+	}
+	return toDto(model), nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) unsetOtherDefault(model *repository.NamespaceTemplate) error {
+	if !model.IsDefault {
+		return nil
+	}
+	err := impl.templateRepository.UnsetDefault(model.Id, model.UpdatedBy)
+	if err != nil {
+		impl.logger.Errorw("error in unsetting default namespace template", "id", model.Id, "err", err)
+	}
+	return err
+}
+
+func (impl *NamespaceTemplateServiceImpl) Delete(id int, userId int32) error {
+	model, err := impl.getTemplate(id)
+	if err != nil {
+		return err
+	}
+	model.Active = false
+	model.IsDefault = false
+	model.UpdateAuditLog(userId)
+	err = impl.templateRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in deleting namespace template", "id", id, "err", err)
+	}
+	return err
+}
+
+func (impl *NamespaceTemplateServiceImpl) GetEnvironments(templateId int) ([]*bean.TemplateEnvironmentDto, error) {
+	if _, err := impl.getTemplate(templateId); err != nil {
+		return nil, err
+	}
+	mappings, err := impl.templateEnvironmentRepository.FindByTemplateId(templateId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching environments of namespace template", "templateId", templateId, "err", err)
+		return nil, err
+	}
+	envIds := make([]*int, 0, len(mappings))
+	for _, mapping := range mappings {
+		envIds = append(envIds, &mapping.EnvironmentId)
+	}
+	envById := make(map[int]*envRepository.Environment, len(mappings))
+	if len(envIds) > 0 {
+		envs, err := impl.environmentRepository.FindByIds(envIds)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching environments", "ids", envIds, "err", err)
+			return nil, err
+		}
+		for _, env := range envs {
+			envById[env.Id] = env
+		}
+	}
+	dtos := make([]*bean.TemplateEnvironmentDto, 0, len(mappings))
+	for _, mapping := range mappings {
+		env, ok := envById[mapping.EnvironmentId]
+		if !ok {
+			// deleted environments
+			continue
+		}
+		dtos = append(dtos, &bean.TemplateEnvironmentDto{
+			EnvironmentId:   env.Id,
+			EnvironmentName: env.Name,
+			Namespace:       env.Namespace,
+			Status:          bean.ApplyStatus(mapping.Status),
+			Message:         mapping.Message,
+			AppliedOn:       mapping.AppliedOn,
+		})
+	}
+	return dtos, nil
+}
+
+// render resolves the scoped variables of a template for an environment and syncs the image pull secrets of its
+// registries into the namespace, secrets are only named for dry run
+func (impl *NamespaceTemplateServiceImpl) render(template *repository.NamespaceTemplate, env *envRepository.Environment, isDryRun bool) ([]*unstructured.Unstructured, []string, error) {
+	imagePullSecrets := make([]string, 0, len(template.RegistryIds))
+	for _, registryId := range template.RegistryIds {
+		ipsName, err := impl.dockerRegistryIpsConfigService.SyncImagePullSecret(registryId, env, isDryRun)
+		if err != nil {
+			impl.logger.Errorw("error in syncing image pull secret", "registryId", registryId, "envId", env.Id, "err", err)
+			return nil, nil, err
+		}
+		if len(ipsName) > 0 {
+			imagePullSecrets = append(imagePullSecrets, ipsName)
+		}
+	}
+	scope := resourceQualifiers.Scope{
+		EnvId:     env.Id,
+		ClusterId: env.ClusterId,
+		SystemMetadata: &resourceQualifiers.SystemMetadata{
+			EnvironmentName: env.Name,
+			Namespace:       env.Namespace,
+		},
+	}
+	if env.Cluster != nil {
+		scope.SystemMetadata.ClusterName = env.Cluster.ClusterName
+	}
+	resolvedManifest, _, err := impl.scopedVariableManager.ExtractVariablesAndResolveTemplate(scope, template.Manifest, parsers.StringVariableTemplate, true, false)
+	if err != nil {
+		impl.logger.Errorw("error in resolving variables of namespace template", "templateId", template.Id, "envId", env.Id, "err", err)
+		return nil, nil, err
+	}
+	objects, err := renderObjects(resolvedManifest, env.Namespace, template.Id, imagePullSecrets)
+	if err != nil {
+		return nil, nil, err
+	}
+	return objects, imagePullSecrets, nil
+}
+
+// applyObject server side applies an object, taking over the fields set by others, and returns it as stored
+func (impl *NamespaceTemplateServiceImpl) applyObject(ctx context.Context, restConfig *rest.Config, obj *unstructured.Unstructured, isDryRun bool) (*unstructured.Unstructured, error) {
+	resourceIf, _, err := impl.k8sUtil.GetResourceIf(restConfig, obj.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+	manifestJson, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	patchOptions := metav1.PatchOptions{
+		FieldManager: bean.FieldManager,
+		Force:        pointer.Bool(true),
+	}
+	if isDryRun {
+		patchOptions.DryRun = []string{metav1.DryRunAll}
+	}
+	return resourceIf.Namespace(obj.GetNamespace()).Patch(ctx, obj.GetName(), types.ApplyPatchType, manifestJson, patchOptions)
+}
+
+// getLiveObject returns nil for an object which does not exist
+func (impl *NamespaceTemplateServiceImpl) getLiveObject(ctx context.Context, restConfig *rest.Config, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	resourceIf, _, err := impl.k8sUtil.GetResourceIf(restConfig, obj.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+	live, err := resourceIf.Namespace(obj.GetNamespace()).Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return live, err
+}
+
+func (impl *NamespaceTemplateServiceImpl) deleteObject(ctx context.Context, restConfig *rest.Config, obj *unstructured.Unstructured) error {
+	resourceIf, _, err := impl.k8sUtil.GetResourceIf(restConfig, obj.GroupVersionKind())
+	if err != nil {
+		return err
+	}
+	err = resourceIf.Namespace(obj.GetNamespace()).Delete(ctx, obj.GetName(), metav1.DeleteOptions{})
+	if k8sErrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// getAppliedObjects returns the objects last applied to the namespace of an environment from any template
+func (impl *NamespaceTemplateServiceImpl) getAppliedObjects(envId int) (*repository.NamespaceTemplateEnvironment, []*unstructured.Unstructured, error) {
+	mapping, err := impl.templateEnvironmentRepository.FindByEnvironmentId(envId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, nil, nil
+		}
+		impl.logger.Errorw("error in fetching namespace template of environment", "envId", envId, "err", err)
+		return nil, nil, err
+	}
+	applied, err := parseManifest(mapping.AppliedManifest)
+	if err != nil {
+		impl.logger.Errorw("error in parsing applied manifest", "envId", envId, "err", err)
+		return nil, nil, err
+	}
+	return mapping, applied, nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) Preview(templateId, envId int) (*bean.NamespaceTemplatePreview, error) {
+	template, err := impl.getTemplate(templateId)
+	if err != nil {
+		return nil, err
+	}
+	env, err := impl.getEnvironment(envId)
+	if err != nil {
+		return nil, err
+	}
+	objects, imagePullSecrets, err := impl.render(template, env, true)
+	if err != nil {
+		return nil, err
+	}
+	_, applied, err := impl.getAppliedObjects(envId)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), applyTimeout)
+	defer cancel()
+	restConfig, err, _ := impl.k8sCommonService.GetRestConfigByClusterId(ctx, env.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting rest config by cluster id", "clusterId", env.ClusterId, "err", err)
+		return nil, err
+	}
+	diffs := make([]*bean.ResourceDiff, 0, len(objects))
+	for _, obj := range objects {
+		live, err := impl.getLiveObject(ctx, restConfig, obj)
+		if err != nil {
+			diff := newResourceDiff(obj)
+			diff.Error = err.Error()
+			diffs = append(diffs, diff)
+			continue
+		}
+		desired, err := impl.applyObject(ctx, restConfig, obj, true)
+		if err != nil {
+			diff := newResourceDiff(obj)
+			diff.Error = err.Error()
+			diffs = append(diffs, diff)
+			continue
+		}
+		diff, err := diffObjects(live, desired)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, diff)
+	}
+	for _, obj := range getRemovedObjects(applied, objects) {
+		obj.SetNamespace(env.Namespace)
+		live, err := impl.getLiveObject(ctx, restConfig, obj)
+		if err != nil || live == nil {
+			continue
+		}
+		diff := newResourceDiff(live)
+		diff.ChangeType = bean.ResourceRemoved
+		if liveManifest, err := toManifest([]*unstructured.Unstructured{normalizeObject(live)}); err == nil {
+			diff.LiveManifest = liveManifest
+		}
+		diffs = append(diffs, diff)
+	}
+	sortResourceDiffs(diffs)
+	return &bean.NamespaceTemplatePreview{
+		TemplateId:       templateId,
+		EnvironmentId:    envId,
+		Namespace:        env.Namespace,
+		ImagePullSecrets: imagePullSecrets,
+		Summary:          getPreviewSummary(diffs),
+		Resources:        diffs,
+	}, nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) Apply(request *bean.ApplyTemplateRequest) ([]*bean.TemplateEnvironmentDto, error) {
+	template, err := impl.getTemplate(request.TemplateId)
+	if err != nil {
+		return nil, err
+	}
+	envIds := request.EnvironmentIds
+	if len(envIds) == 0 {
+		mappings, err := impl.templateEnvironmentRepository.FindByTemplateId(template.Id)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching environments of namespace template", "templateId", template.Id, "err", err)
+			return nil, err
+		}
+		for _, mapping := range mappings {
+			envIds = append(envIds, mapping.EnvironmentId)
+		}
+	}
+	if len(envIds) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "namespace template is not applied to any environment", "no environments to apply namespace template to")
+	}
+	results := make([]*bean.TemplateEnvironmentDto, 0, len(envIds))
+	for _, envId := range envIds {
+		env, err := impl.getEnvironment(envId)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, impl.applyToEnvironment(template, env, request.UserId))
+	}
+	return results, nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) ProvisionNamespace(env *envBean.EnvironmentBean, userId int32) {
+	var template *repository.NamespaceTemplate
+	var err error
+	if env.NamespaceTemplateId > 0 {
+		template, err = impl.templateRepository.FindById(env.NamespaceTemplateId)
+	} else {
+		template, err = impl.templateRepository.FindDefault()
+		if util.IsErrNoRows(err) {
+			return
+		}
+	}
+	if err != nil {
+		impl.logger.Errorw("error in fetching namespace template for environment", "envId", env.Id, "templateId", env.NamespaceTemplateId, "err", err)
+		return
+	}
+	model, err := impl.environmentRepository.FindById(env.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment", "envId", env.Id, "err", err)
+		return
+	}
+	result := impl.applyToEnvironment(template, model, userId)
+	if result.Status == bean.ApplyStatusFailed {
+		impl.logger.Errorw("error in provisioning namespace of environment", "envId", env.Id, "templateId", template.Id, "err", result.Message)
+	}
+}
+
+// applyToEnvironment applies a template to the namespace of an environment, deleting the objects of the template
+// applied before which are not in it anymore, and records the outcome
+func (impl *NamespaceTemplateServiceImpl) applyToEnvironment(template *repository.NamespaceTemplate, env *envRepository.Environment, userId int32) *bean.TemplateEnvironmentDto {
+	result := &bean.TemplateEnvironmentDto{
+		EnvironmentId:   env.Id,
+		EnvironmentName: env.Name,
+		Namespace:       env.Namespace,
+		Status:          bean.ApplyStatusSucceeded,
+		AppliedOn:       time.Now(),
+	}
+	mapping, applied, err := impl.getAppliedObjects(env.Id)
+	if err != nil {
+		result.Status, result.Message = bean.ApplyStatusFailed, err.Error()
+		return result
+	}
+	if mapping == nil {
+		mapping = &repository.NamespaceTemplateEnvironment{EnvironmentId: env.Id, AuditLog: sql.NewDefaultAuditLog(userId)}
+	}
+	appliedManifest, err := impl.apply(template, env, applied)
+	if err != nil {
+		result.Status, result.Message = bean.ApplyStatusFailed, err.Error()
+	} else {
+		mapping.AppliedManifest = appliedManifest
+	}
+	mapping.TemplateId = template.Id
+	mapping.Status = string(result.Status)
+	mapping.Message = result.Message
+	mapping.AppliedOn = result.AppliedOn
+	mapping.UpdateAuditLog(userId)
+	if mapping.Id > 0 {
+		err = impl.templateEnvironmentRepository.Update(mapping)
+	} else {
+		err = impl.templateEnvironmentRepository.Save(mapping)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving namespace template of environment", "envId", env.Id, "templateId", template.Id, "err", err)
+	}
+	return result
+}
+
+func (impl *NamespaceTemplateServiceImpl) apply(template *repository.NamespaceTemplate, env *envRepository.Environment, applied []*unstructured.Unstructured) (string, error) {
+	objects, _, err := impl.render(template, env, false)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), applyTimeout)
+	defer cancel()
+	restConfig, err, _ := impl.k8sCommonService.GetRestConfigByClusterId(ctx, env.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting rest config by cluster id", "clusterId", env.ClusterId, "err", err)
+		return "", err
+	}
+	for _, obj := range objects {
+		if _, err = impl.applyObject(ctx, restConfig, obj, false); err != nil {
+			impl.logger.Errorw("error in applying object of namespace template", "kind", obj.GetKind(), "name", obj.GetName(), "envId", env.Id, "err", err)
+			return "", fmt.Errorf("%s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+	}
+	for _, obj := range getRemovedObjects(applied, objects) {
+		obj.SetNamespace(env.Namespace)
+		if err = impl.deleteObject(ctx, restConfig, obj); err != nil {
+			impl.logger.Errorw("error in deleting object removed from namespace template", "kind", obj.GetKind(), "name", obj.GetName(), "envId", env.Id, "err", err)
+			return "", fmt.Errorf("%s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+	}
+	return toManifest(objects)
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+// AllowedKinds are the kinds of the baseline objects a template can set up in a namespace
+var AllowedKinds = map[string]string{
+	"ResourceQuota":  "",
+	"LimitRange":     "",
+	"ServiceAccount": "",
+	"NetworkPolicy":  "networking.k8s.io",
+}
+
+const (
+	FieldManager = "devtron-namespace-template"
+	// TemplateIdLabel is set on every object applied from a template
+	TemplateIdLabel = "devtron.ai/namespace-template-id"
+)
+
+type ApplyStatus string
+
+const (
+	ApplyStatusSucceeded ApplyStatus = "Succeeded"
+	ApplyStatusFailed    ApplyStatus = "Failed"
+)
+
+type ResourceChangeType string
+
+const (
+	ResourceAdded     ResourceChangeType = "ADDED"
+	ResourceRemoved   ResourceChangeType = "REMOVED"
+	ResourceModified  ResourceChangeType = "MODIFIED"
+	ResourceUnchanged ResourceChangeType = "UNCHANGED"
+)
+
+type NamespaceTemplateDto struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name" validate:"required,max=250"`
+	Description string `json:"description"`
+	// Manifest is the multi document yaml of the objects, scoped variables are referred to as @{{name}} and are
+	// resolved for the environment the template is applied to
+	Manifest string `json:"manifest" validate:"required"`
+	// RegistryIds are the container registries whose image pull secrets are synced into the namespace and added to
+	// the service accounts of the template
+	RegistryIds []string `json:"registryIds"`
+	// IsDefault templates are applied to environments created without a template, there is at most one
+	IsDefault bool  `json:"isDefault"`
+	UserId    int32 `json:"-"`
+}
+
+type TemplateEnvironmentDto struct {
+	EnvironmentId   int         `json:"environmentId"`
+	EnvironmentName string      `json:"environmentName"`
+	Namespace       string      `json:"namespace"`
+	Status          ApplyStatus `json:"status"`
+	Message         string      `json:"message,omitempty"`
+	AppliedOn       time.Time   `json:"appliedOn"`
+}
+
+type ApplyTemplateRequest struct {
+	TemplateId int `json:"templateId" validate:"required"`
+	// EnvironmentIds to apply the template to, every environment it was applied to before when empty
+	EnvironmentIds []int `json:"environmentIds"`
+	UserId         int32 `json:"-"`
+}
+
+type NamespaceTemplatePreview struct {
+	TemplateId       int             `json:"templateId"`
+	EnvironmentId    int             `json:"environmentId"`
+	Namespace        string          `json:"namespace"`
+	ImagePullSecrets []string        `json:"imagePullSecrets"`
+	Summary          *PreviewSummary `json:"summary"`
+	Resources        []*ResourceDiff `json:"resources"`
+}
+
+type PreviewSummary struct {
+	Added     int `json:"added"`
+	Removed   int `json:"removed"`
+	Modified  int `json:"modified"`
+	Unchanged int `json:"unchanged"`
+	Failed    int `json:"failed"`
+}
+
+type ResourceDiff struct {
+	Group        string             `json:"group"`
+	Version      string             `json:"version"`
+	Kind         string             `json:"kind"`
+	Name         string             `json:"name"`
+	ChangeType   ResourceChangeType `json:"changeType"`
+	FieldChanges []*FieldChange     `json:"fieldChanges,omitempty"`
+	// DesiredManifest is the object as the api server would store it once applied
+	DesiredManifest string `json:"desiredManifest,omitempty"`
+	LiveManifest    string `json:"liveManifest,omitempty"`
+	// Error is set when the object would be rejected by the api server
+	Error string `json:"error,omitempty"`
+}
+
+type FieldChange struct {
+	Path     string      `json:"path"`
+	OldValue interface{} `json:"oldValue"`
+	NewValue interface{} `json:"newValue"`
+}
@@ -0,0 +1,294 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespaceTemplate
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/namespaceTemplate/bean"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// variableExpression matches the scoped variables left in a template once resolved
+var variableExpression = regexp.MustCompile(`@{{([^}]+)}}`)
+
+// variablePlaceholder stands in for the variables of a template while its objects are validated on save
+const variablePlaceholder = "variable"
+
+func getObjectKey(obj *unstructured.Unstructured) string {
+	return obj.GroupVersionKind().Group + "/" + obj.GetKind() + "/" + obj.GetName()
+}
+
+// parseManifest splits a multi document manifest into objects, skipping empty documents
+func parseManifest(manifest string) ([]*unstructured.Unstructured, error) {
+	if len(strings.TrimSpace(manifest)) == 0 {
+		return nil, nil
+	}
+	objects, err := kube.SplitYAML([]byte(manifest))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*unstructured.Unstructured, 0, len(objects))
+	for _, obj := range objects {
+		if obj == nil || len(obj.GetKind()) == 0 {
+			continue
+		}
+		result = append(result, obj)
+	}
+	return result, nil
+}
+
+// validateObjects checks that the objects are of the allowed kinds, named, unique and not meant for another namespace,
+// namespace is empty while validating a template on save
+func validateObjects(objects []*unstructured.Unstructured, namespace string) error {
+	if len(objects) == 0 {
+		return util.NewApiError(http.StatusBadRequest, "template has no objects", "template has no objects")
+	}
+	keys := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		group, ok := bean.AllowedKinds[obj.GetKind()]
+		if !ok || obj.GroupVersionKind().Group != group {
+			msg := fmt.Sprintf("%s of %s is not allowed in a namespace template", obj.GetKind(), obj.GetAPIVersion())
+			return util.NewApiError(http.StatusBadRequest, msg, msg)
+		}
+		if len(obj.GetName()) == 0 {
+			msg := fmt.Sprintf("%s without a name", obj.GetKind())
+			return util.NewApiError(http.StatusBadRequest, msg, msg)
+		}
+		if len(obj.GetNamespace()) > 0 && len(namespace) > 0 && obj.GetNamespace() != namespace {
+			msg := fmt.Sprintf("%s %s is meant for namespace %s", obj.GetKind(), obj.GetName(), obj.GetNamespace())
+			return util.NewApiError(http.StatusBadRequest, msg, msg)
+		}
+		key := getObjectKey(obj)
+		if keys[key] {
+			msg := fmt.Sprintf("%s %s is defined more than once", obj.GetKind(), obj.GetName())
+			return util.NewApiError(http.StatusBadRequest, msg, msg)
+		}
+		keys[key] = true
+	}
+	return nil
+}
+
+// validateTemplate parses a template with its variables replaced by a placeholder and validates its objects
+func validateTemplate(manifest string) error {
+	objects, err := parseManifest(variableExpression.ReplaceAllString(manifest, variablePlaceholder))
+	if err != nil {
+		return util.NewApiError(http.StatusBadRequest, "invalid template manifest, "+err.Error(), err.Error())
+	}
+	return validateObjects(objects, "")
+}
+
+// renderObjects parses a template whose variables are resolved into the objects applied to a namespace, they are
+// labelled with the template and its service accounts get the image pull secrets synced into the namespace
+func renderObjects(resolvedManifest string, namespace string, templateId int, imagePullSecrets []string) ([]*unstructured.Unstructured, error) {
+	if matches := variableExpression.FindAllStringSubmatch(resolvedManifest, -1); len(matches) > 0 {
+		names := make([]string, 0, len(matches))
+		for _, match := range matches {
+			names = append(names, match[1])
+		}
+		msg := fmt.Sprintf("unresolved variables in template, %s", strings.Join(names, ", "))
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	objects, err := parseManifest(resolvedManifest)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, "invalid template manifest, "+err.Error(), err.Error())
+	}
+	if err = validateObjects(objects, namespace); err != nil {
+		return nil, err
+	}
+	for _, obj := range objects {
+		obj.SetNamespace(namespace)
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[bean.TemplateIdLabel] = strconv.Itoa(templateId)
+		obj.SetLabels(labels)
+		if obj.GetKind() == "ServiceAccount" {
+			addImagePullSecrets(obj, imagePullSecrets)
+		}
+	}
+	return objects, nil
+}
+
+func addImagePullSecrets(serviceAccount *unstructured.Unstructured, names []string) {
+	secrets, _, _ := unstructured.NestedSlice(serviceAccount.Object, "imagePullSecrets")
+	existing := make(map[string]bool, len(secrets))
+	for _, secret := range secrets {
+		if secretMap, ok := secret.(map[string]interface{}); ok {
+			existing[fmt.Sprint(secretMap["name"])] = true
+		}
+	}
+	for _, name := range names {
+		if !existing[name] {
+			secrets = append(secrets, map[string]interface{}{"name": name})
+			existing[name] = true
+		}
+	}
+	if len(secrets) > 0 {
+		serviceAccount.Object["imagePullSecrets"] = secrets
+	}
+}
+
+// toManifest joins the objects into a multi document manifest
+func toManifest(objects []*unstructured.Unstructured) (string, error) {
+	documents := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		document, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return "", err
+		}
+		documents = append(documents, string(document))
+	}
+	return strings.Join(documents, "---\n"), nil
+}
+
+// getRemovedObjects returns the objects applied before which are no longer in the template
+func getRemovedObjects(applied, desired []*unstructured.Unstructured) []*unstructured.Unstructured {
+	desiredKeys := make(map[string]bool, len(desired))
+	for _, obj := range desired {
+		desiredKeys[getObjectKey(obj)] = true
+	}
+	removed := make([]*unstructured.Unstructured, 0)
+	for _, obj := range applied {
+		if !desiredKeys[getObjectKey(obj)] {
+			removed = append(removed, obj)
+		}
+	}
+	return removed
+}
+
+// normalizeObject drops the fields set by the api server which change with every write, leaving the ones a template
+// sets or gets defaulted
+func normalizeObject(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	normalized := obj.DeepCopy()
+	for _, field := range []string{"managedFields", "resourceVersion", "generation", "creationTimestamp", "uid"} {
+		unstructured.RemoveNestedField(normalized.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(normalized.Object, "status")
+	return normalized
+}
+
+func newResourceDiff(obj *unstructured.Unstructured) *bean.ResourceDiff {
+	gvk := obj.GroupVersionKind()
+	return &bean.ResourceDiff{
+		Group:   gvk.Group,
+		Version: gvk.Version,
+		Kind:    gvk.Kind,
+		Name:    obj.GetName(),
+	}
+}
+
+// diffObjects compares the live object with the one the api server would store once the template is applied,
+// live is nil for an object which is not created yet
+func diffObjects(live, desired *unstructured.Unstructured) (*bean.ResourceDiff, error) {
+	diff := newResourceDiff(desired)
+	desired = normalizeObject(desired)
+	desiredManifest, err := yaml.Marshal(desired.Object)
+	if err != nil {
+		return nil, err
+	}
+	diff.DesiredManifest = string(desiredManifest)
+	if live == nil {
+		diff.ChangeType = bean.ResourceAdded
+		return diff, nil
+	}
+	live = normalizeObject(live)
+	liveManifest, err := yaml.Marshal(live.Object)
+	if err != nil {
+		return nil, err
+	}
+	diff.LiveManifest = string(liveManifest)
+	diff.FieldChanges = diffFields("", live.Object, desired.Object)
+	if len(diff.FieldChanges) > 0 {
+		diff.ChangeType = bean.ResourceModified
+	} else {
+		diff.ChangeType = bean.ResourceUnchanged
+	}
+	return diff, nil
+}
+
+// diffFields walks both values and records leaf level changes with their dotted path,
+// lists are compared as a whole as positional diffs of lists are rarely meaningful to reviewers
+func diffFields(path string, oldValue, newValue interface{}) []*bean.FieldChange {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if !oldIsMap || !newIsMap {
+		if reflect.DeepEqual(oldValue, newValue) {
+			return nil
+		}
+		return []*bean.FieldChange{{Path: path, OldValue: oldValue, NewValue: newValue}}
+	}
+	keys := make([]string, 0, len(oldMap)+len(newMap))
+	for key := range oldMap {
+		keys = append(keys, key)
+	}
+	for key := range newMap {
+		if _, ok := oldMap[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	changes := make([]*bean.FieldChange, 0)
+	for _, key := range keys {
+		childPath := key
+		if len(path) > 0 {
+			childPath = fmt.Sprintf("%s.%s", path, key)
+		}
+		changes = append(changes, diffFields(childPath, oldMap[key], newMap[key])...)
+	}
+	return changes
+}
+
+func sortResourceDiffs(diffs []*bean.ResourceDiff) {
+	sort.SliceStable(diffs, func(i, j int) bool {
+		if diffs[i].Kind != diffs[j].Kind {
+			return diffs[i].Kind < diffs[j].Kind
+		}
+		return diffs[i].Name < diffs[j].Name
+	})
+}
+
+func getPreviewSummary(diffs []*bean.ResourceDiff) *bean.PreviewSummary {
+	summary := &bean.PreviewSummary{}
+	for _, diff := range diffs {
+		if len(diff.Error) > 0 {
+			summary.Failed++
+			continue
+		}
+		switch diff.ChangeType {
+		case bean.ResourceAdded:
+			summary.Added++
+		case bean.ResourceRemoved:
+			summary.Removed++
+		case bean.ResourceModified:
+			summary.Modified++
+		case bean.ResourceUnchanged:
+			summary.Unchanged++
+		}
+	}
+	return summary
+}
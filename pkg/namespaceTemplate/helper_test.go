@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespaceTemplate
+
+import (
+	"testing"
+
+	"github.com/devtron-labs/devtron/pkg/namespaceTemplate/bean"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const testManifest = `apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: quota
+spec:
+  hard:
+    requests.cpu: "@{{cpu-quota}}"
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: deployer
+imagePullSecrets:
+  - name: existing
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: deny-all
+spec:
+  podSelector: {}
+`
+
+func TestValidateTemplate(t *testing.T) {
+	assert.NoError(t, validateTemplate(testManifest))
+	assert.Error(t, validateTemplate(""), "a template without objects")
+	assert.Error(t, validateTemplate("apiVersion: v1\nkind: Secret\nmetadata:\n  name: creds\n"), "kind not allowed")
+	assert.Error(t, validateTemplate("apiVersion: extensions/v1beta1\nkind: NetworkPolicy\nmetadata:\n  name: deny\n"), "group not allowed")
+	assert.Error(t, validateTemplate("apiVersion: v1\nkind: LimitRange\nmetadata:\n  labels: {}\n"), "object without a name")
+	assert.Error(t, validateTemplate("apiVersion: v1\nkind: LimitRange\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: LimitRange\nmetadata:\n  name: a\n"), "duplicate object")
+}
+
+func TestRenderObjects(t *testing.T) {
+	_, err := renderObjects(testManifest, "prod", 3, nil)
+	assert.Error(t, err, "unresolved variables")
+
+	resolved := variableExpression.ReplaceAllString(testManifest, "4")
+	objects, err := renderObjects(resolved, "prod", 3, []string{"existing", "devtron-ips-2"})
+	assert.NoError(t, err)
+	assert.Len(t, objects, 3)
+	for _, obj := range objects {
+		assert.Equal(t, "prod", obj.GetNamespace())
+		assert.Equal(t, "3", obj.GetLabels()[bean.TemplateIdLabel])
+	}
+	secrets, _, _ := unstructured.NestedSlice(objects[1].Object, "imagePullSecrets")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "existing"},
+		map[string]interface{}{"name": "devtron-ips-2"},
+	}, secrets)
+	_, found, _ := unstructured.NestedSlice(objects[2].Object, "imagePullSecrets")
+	assert.False(t, found, "image pull secrets are only added to service accounts")
+
+	_, err = renderObjects("apiVersion: v1\nkind: LimitRange\nmetadata:\n  name: a\n  namespace: qa\n", "prod", 3, nil)
+	assert.Error(t, err, "object meant for another namespace")
+}
+
+func TestGetRemovedObjects(t *testing.T) {
+	resolved := variableExpression.ReplaceAllString(testManifest, "4")
+	applied, err := parseManifest(resolved)
+	assert.NoError(t, err)
+	desired, err := parseManifest(resolved + "---\napiVersion: v1\nkind: LimitRange\nmetadata:\n  name: limits\n")
+	assert.NoError(t, err)
+	assert.Empty(t, getRemovedObjects(applied, desired))
+
+	removed := getRemovedObjects(applied, desired[1:])
+	assert.Len(t, removed, 1)
+	assert.Equal(t, "quota", removed[0].GetName())
+}
+
+func newTestQuota(cpu string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ResourceQuota",
+		"metadata": map[string]interface{}{
+			"name":            "quota",
+			"namespace":       "prod",
+			"resourceVersion": "12",
+			"uid":             "abc",
+		},
+		"spec":   map[string]interface{}{"hard": map[string]interface{}{"requests.cpu": cpu}},
+		"status": map[string]interface{}{"used": map[string]interface{}{"requests.cpu": "1"}},
+	}}
+}
+
+func TestDiffObjects(t *testing.T) {
+	diff, err := diffObjects(nil, newTestQuota("4"))
+	assert.NoError(t, err)
+	assert.Equal(t, bean.ResourceAdded, diff.ChangeType)
+	assert.Empty(t, diff.LiveManifest)
+	assert.NotContains(t, diff.DesiredManifest, "resourceVersion")
+
+	live := newTestQuota("4")
+	desired := newTestQuota("8")
+	desired.SetResourceVersion("13")
+	diff, err = diffObjects(live, desired)
+	assert.NoError(t, err)
+	assert.Equal(t, bean.ResourceModified, diff.ChangeType)
+	assert.Equal(t, []*bean.FieldChange{{Path: "spec.hard.requests.cpu", OldValue: "4", NewValue: "8"}}, diff.FieldChanges)
+
+	diff, err = diffObjects(live, newTestQuota("4"))
+	assert.NoError(t, err)
+	assert.Equal(t, bean.ResourceUnchanged, diff.ChangeType)
+}
+
+func TestGetPreviewSummary(t *testing.T) {
+	diffs := []*bean.ResourceDiff{
+		{Kind: "ServiceAccount", Name: "deployer", ChangeType: bean.ResourceAdded},
+		{Kind: "LimitRange", Name: "limits", ChangeType: bean.ResourceRemoved},
+		{Kind: "ResourceQuota", Name: "quota", ChangeType: bean.ResourceModified},
+		{Kind: "NetworkPolicy", Name: "deny-all", ChangeType: bean.ResourceUnchanged},
+		{Kind: "NetworkPolicy", Name: "allow-dns", Error: "forbidden"},
+	}
+	assert.Equal(t, &bean.PreviewSummary{Added: 1, Removed: 1, Modified: 1, Unchanged: 1, Failed: 1}, getPreviewSummary(diffs))
+	sortResourceDiffs(diffs)
+	assert.Equal(t, "LimitRange", diffs[0].Kind)
+	assert.Equal(t, "allow-dns", diffs[1].Name)
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// NamespaceTemplateEnvironment is the template last applied to the namespace of an environment, applied manifest
+// holds the objects applied so that the ones dropped from the template are deleted when it is applied again
+type NamespaceTemplateEnvironment struct {
+	tableName       struct{}  `sql:"namespace_template_environment" pg:",discard_unknown_columns"`
+	Id              int       `sql:"id,pk"`
+	TemplateId      int       `sql:"template_id,notnull"`
+	EnvironmentId   int       `sql:"environment_id,notnull"`
+	AppliedManifest string    `sql:"applied_manifest"`
+	Status          string    `sql:"status,notnull"`
+	Message         string    `sql:"message"`
+	AppliedOn       time.Time `sql:"applied_on,notnull"`
+	sql.AuditLog
+}
+
+type NamespaceTemplateEnvironmentRepository interface {
+	Save(model *NamespaceTemplateEnvironment) error
+	Update(model *NamespaceTemplateEnvironment) error
+	FindByEnvironmentId(environmentId int) (*NamespaceTemplateEnvironment, error)
+	FindByTemplateId(templateId int) ([]*NamespaceTemplateEnvironment, error)
+}
+
+type NamespaceTemplateEnvironmentRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewNamespaceTemplateEnvironmentRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *NamespaceTemplateEnvironmentRepositoryImpl {
+	return &NamespaceTemplateEnvironmentRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *NamespaceTemplateEnvironmentRepositoryImpl) Save(model *NamespaceTemplateEnvironment) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *NamespaceTemplateEnvironmentRepositoryImpl) Update(model *NamespaceTemplateEnvironment) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *NamespaceTemplateEnvironmentRepositoryImpl) FindByEnvironmentId(environmentId int) (*NamespaceTemplateEnvironment, error) {
+	model := &NamespaceTemplateEnvironment{}
+	err := impl.dbConnection.Model(model).
+		Where("environment_id = ?", environmentId).
+		Select()
+	return model, err
+}
+
+func (impl *NamespaceTemplateEnvironmentRepositoryImpl) FindByTemplateId(templateId int) ([]*NamespaceTemplateEnvironment, error) {
+	var models []*NamespaceTemplateEnvironment
+	err := impl.dbConnection.Model(&models).
+		Where("template_id = ?", templateId).
+		Order("environment_id").
+		Select()
+	return models, err
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type NamespaceTemplate struct {
+	tableName   struct{} `sql:"namespace_template" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	Name        string   `sql:"name,notnull"`
+	Description string   `sql:"description"`
+	Manifest    string   `sql:"manifest,notnull"`
+	RegistryIds []string `sql:"registry_ids" pg:",array"`
+	IsDefault   bool     `sql:"is_default,notnull"`
+	Active      bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type NamespaceTemplateRepository interface {
+	Save(model *NamespaceTemplate) error
+	Update(model *NamespaceTemplate) error
+	FindById(id int) (*NamespaceTemplate, error)
+	FindByName(name string) (*NamespaceTemplate, error)
+	FindAllActive() ([]*NamespaceTemplate, error)
+	FindDefault() (*NamespaceTemplate, error)
+	// UnsetDefault unmarks the default template other than the given one
+	UnsetDefault(exceptId int, userId int32) error
+}
+
+type NamespaceTemplateRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewNamespaceTemplateRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *NamespaceTemplateRepositoryImpl {
+	return &NamespaceTemplateRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *NamespaceTemplateRepositoryImpl) Save(model *NamespaceTemplate) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *NamespaceTemplateRepositoryImpl) Update(model *NamespaceTemplate) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *NamespaceTemplateRepositoryImpl) FindById(id int) (*NamespaceTemplate, error) {
+	model := &NamespaceTemplate{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *NamespaceTemplateRepositoryImpl) FindByName(name string) (*NamespaceTemplate, error) {
+	model := &NamespaceTemplate{}
+	err := impl.dbConnection.Model(model).
+		Where("name = ?", name).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *NamespaceTemplateRepositoryImpl) FindAllActive() ([]*NamespaceTemplate, error) {
+	var models []*NamespaceTemplate
+	err := impl.dbConnection.Model(&models).
+		Where("active = ?", true).
+		Order("name").
+		Select()
+	return models, err
+}
+
+func (impl *NamespaceTemplateRepositoryImpl) FindDefault() (*NamespaceTemplate, error) {
+	model := &NamespaceTemplate{}
+	err := impl.dbConnection.Model(model).
+		Where("is_default = ?", true).
+		Where("active = ?", true).
+		Limit(1).
+		Select()
+	return model, err
+}
+
+func (impl *NamespaceTemplateRepositoryImpl) UnsetDefault(exceptId int, userId int32) error {
+	_, err := impl.dbConnection.Model(&NamespaceTemplate{}).
+		Set("is_default = ?", false).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("is_default = ?", true).
+		Where("id <> ?", exceptId).
+		Update()
+	return err
+}
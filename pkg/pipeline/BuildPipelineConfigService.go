@@ -41,6 +41,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
 	"github.com/devtron-labs/devtron/pkg/pipeline/types"
 	repository2 "github.com/devtron-labs/devtron/pkg/plugin/repository"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail"
 	resourceGroup2 "github.com/devtron-labs/devtron/pkg/resourceGroup"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/util/rbac"
@@ -144,6 +145,7 @@ type CiPipelineConfigServiceImpl struct {
 	buildPipelineSwitchService    BuildPipelineSwitchService
 	pipelineStageRepository       repository.PipelineStageRepository
 	globalPluginRepository        repository2.GlobalPluginRepository
+	projectGuardrailService       projectGuardrail.ProjectGuardrailService
 }
 
 func NewCiPipelineConfigServiceImpl(logger *zap.SugaredLogger,
@@ -171,7 +173,8 @@ func NewCiPipelineConfigServiceImpl(logger *zap.SugaredLogger,
 	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
 	buildPipelineSwitchService BuildPipelineSwitchService,
 	pipelineStageRepository repository.PipelineStageRepository,
-	globalPluginRepository repository2.GlobalPluginRepository) *CiPipelineConfigServiceImpl {
+	globalPluginRepository repository2.GlobalPluginRepository,
+	projectGuardrailService projectGuardrail.ProjectGuardrailService) *CiPipelineConfigServiceImpl {
 	securityConfig := &SecurityConfig{}
 	err := env.Parse(securityConfig)
 	if err != nil {
@@ -205,6 +208,7 @@ func NewCiPipelineConfigServiceImpl(logger *zap.SugaredLogger,
 		buildPipelineSwitchService:    buildPipelineSwitchService,
 		pipelineStageRepository:       pipelineStageRepository,
 		globalPluginRepository:        globalPluginRepository,
+		projectGuardrailService:       projectGuardrailService,
 	}
 }
 
@@ -1128,6 +1132,11 @@ func (impl *CiPipelineConfigServiceImpl) UpdateCiTemplate(updateRequest *bean.Ci
 		impl.logger.Errorw("stale version requested", "appId", updateRequest.Id, "old", originalCiConf.Version, "new", updateRequest.Version)
 		return nil, fmt.Errorf("stale version of resource requested kindly refresh. requested: %s, found %s", updateRequest.Version, originalCiConf.Version)
 	}
+	err = impl.projectGuardrailService.ValidateDockerRegistryUpdate(updateRequest.AppId, updateRequest.DockerRegistry)
+	if err != nil {
+		impl.logger.Errorw("container registry not allowed by project guardrail", "appId", updateRequest.AppId, "registry", updateRequest.DockerRegistry, "err", err)
+		return nil, err
+	}
 	dockerArtifaceStore, err := impl.dockerArtifactStoreRepository.FindOne(updateRequest.DockerRegistry)
 	if err != nil {
 		impl.logger.Errorw("error in fetching DockerRegistry  for update", "appId", updateRequest.Id, "err", err, "registry", updateRequest.DockerRegistry)
@@ -37,6 +37,7 @@ import (
 	constants3 "github.com/devtron-labs/devtron/pkg/pipeline/constants"
 	util4 "github.com/devtron-labs/devtron/pkg/pipeline/util"
 	"github.com/devtron-labs/devtron/pkg/plugin"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail"
 	"golang.org/x/exp/slices"
 	"net/http"
 	"path"
@@ -147,6 +148,7 @@ type CiCdPipelineOrchestratorImpl struct {
 	gitOpsConfigReadService       config.GitOpsConfigReadService
 	deploymentConfigService       common.DeploymentConfigService
 	workflowCacheConfig           types.WorkflowCacheConfig
+	projectGuardrailService       projectGuardrail.ProjectGuardrailService
 }
 
 func NewCiCdPipelineOrchestrator(
@@ -176,7 +178,8 @@ func NewCiCdPipelineOrchestrator(
 	genericNoteService genericNotes.GenericNoteService,
 	chartService chart.ChartService, transactionManager sql.TransactionWrapper,
 	gitOpsConfigReadService config.GitOpsConfigReadService,
-	deploymentConfigService common.DeploymentConfigService) *CiCdPipelineOrchestratorImpl {
+	deploymentConfigService common.DeploymentConfigService,
+	projectGuardrailService projectGuardrail.ProjectGuardrailService) *CiCdPipelineOrchestratorImpl {
 	_, workflowCacheConfig, err := types.GetCiConfigWithWorkflowCacheConfig()
 	if err != nil {
 		logger.Errorw("Error in getting workflow cache config, continuing with default values", "err", err)
@@ -212,6 +215,7 @@ func NewCiCdPipelineOrchestrator(
 		gitOpsConfigReadService:       gitOpsConfigReadService,
 		deploymentConfigService:       deploymentConfigService,
 		workflowCacheConfig:           workflowCacheConfig,
+		projectGuardrailService:       projectGuardrailService,
 	}
 }
 
@@ -1238,7 +1242,9 @@ func (impl CiCdPipelineOrchestratorImpl) CheckStringMatchRegex(regex string, val
 
 func (impl CiCdPipelineOrchestratorImpl) CreateApp(createRequest *bean.CreateAppDTO) (*bean.CreateAppDTO, error) {
 	// validate the labels key-value if propagate is true
+	labelKeys := make([]string, 0, len(createRequest.AppLabels))
 	for _, label := range createRequest.AppLabels {
+		labelKeys = append(labelKeys, label.Key)
 		if !label.Propagate {
 			continue
 		}
@@ -1249,6 +1255,11 @@ func (impl CiCdPipelineOrchestratorImpl) CreateApp(createRequest *bean.CreateApp
 			return nil, err
 		}
 	}
+	err := impl.projectGuardrailService.ValidateAppCreate(createRequest.TeamId, labelKeys)
+	if err != nil {
+		impl.logger.Errorw("error in validating project guardrail on app create", "teamId", createRequest.TeamId, "appName", createRequest.AppName, "err", err)
+		return nil, err
+	}
 
 	dbConnection := impl.appRepository.GetConnection()
 	tx, err := dbConnection.Begin()
@@ -58,6 +58,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/pipeline/history"
 	repository4 "github.com/devtron-labs/devtron/pkg/pipeline/history/repository"
 	repository5 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail"
 	resourceGroup2 "github.com/devtron-labs/devtron/pkg/resourceGroup"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/pkg/variables"
@@ -160,6 +161,7 @@ type CdPipelineConfigServiceImpl struct {
 	pipelineConfigEventPublishService out.PipelineConfigEventPublishService
 	deploymentTypeOverrideService     config2.DeploymentTypeOverrideService
 	deploymentConfigService           common.DeploymentConfigService
+	projectGuardrailService           projectGuardrail.ProjectGuardrailService
 }
 
 func NewCdPipelineConfigServiceImpl(logger *zap.SugaredLogger, pipelineRepository pipelineConfig.PipelineRepository,
@@ -184,7 +186,8 @@ func NewCdPipelineConfigServiceImpl(logger *zap.SugaredLogger, pipelineRepositor
 	imageDigestPolicyService imageDigestPolicy.ImageDigestPolicyService,
 	pipelineConfigEventPublishService out.PipelineConfigEventPublishService,
 	deploymentTypeOverrideService config2.DeploymentTypeOverrideService,
-	deploymentConfigService common.DeploymentConfigService) *CdPipelineConfigServiceImpl {
+	deploymentConfigService common.DeploymentConfigService,
+	projectGuardrailService projectGuardrail.ProjectGuardrailService) *CdPipelineConfigServiceImpl {
 	return &CdPipelineConfigServiceImpl{
 		logger:                            logger,
 		pipelineRepository:                pipelineRepository,
@@ -221,6 +224,7 @@ func NewCdPipelineConfigServiceImpl(logger *zap.SugaredLogger, pipelineRepositor
 		pipelineConfigEventPublishService: pipelineConfigEventPublishService,
 		deploymentTypeOverrideService:     deploymentTypeOverrideService,
 		deploymentConfigService:           deploymentConfigService,
+		projectGuardrailService:           projectGuardrailService,
 	}
 }
 
@@ -422,6 +426,16 @@ func (impl *CdPipelineConfigServiceImpl) CreateCdPipelines(pipelineCreateRequest
 		return nil, err
 	}
 
+	guardrailEnvIds := make([]int, 0, len(envIds))
+	for _, envId := range envIds {
+		guardrailEnvIds = append(guardrailEnvIds, *envId)
+	}
+	err = impl.projectGuardrailService.ValidateCdPipelineCreate(app.Id, guardrailEnvIds)
+	if err != nil {
+		impl.logger.Errorw("error in validating project guardrail on cd pipeline create", "appId", app.Id, "envIds", guardrailEnvIds, "err", err)
+		return nil, err
+	}
+
 	AppDeploymentConfig, err := impl.deploymentConfigService.GetAndMigrateConfigIfAbsentForDevtronApps(app.Id, 0)
 	if err != nil {
 		impl.logger.Errorw("error in fetching deployment config by appId", "appId", app.Id, "err", err)
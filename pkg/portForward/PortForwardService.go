@@ -0,0 +1,303 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package portForward
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	auditLogBean "github.com/devtron-labs/devtron/pkg/auditLog/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/read"
+	"github.com/devtron-labs/devtron/pkg/portForward/bean"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type PortForwardService interface {
+	// CreateSession opens a port-forward session, connections are tunneled to it until it expires, stays idle or is closed
+	CreateSession(ctx context.Context, request *bean.SessionRequest) (*bean.SessionDto, error)
+	GetSessions(userId int32) []*bean.SessionDto
+	// ValidateSession checks that a session is open and belongs to the user before a tunnel is opened to it
+	ValidateSession(sessionId string, userId int32) error
+	// Tunnel forwards a client connection to the session, it returns once the connection is closed
+	Tunnel(sessionId string, client io.ReadWriteCloser) error
+	CloseSession(sessionId string, userId int32) error
+}
+
+type PortForwardServiceImpl struct {
+	logger             *zap.SugaredLogger
+	clusterReadService read.ClusterReadService
+	k8sUtil            *k8s.K8sServiceImpl
+	auditLogService    auditLog.AuditLogService
+	config             *bean.PortForwardConfig
+	lock               sync.RWMutex
+	sessions           map[string]*session
+}
+
+func NewPortForwardServiceImpl(logger *zap.SugaredLogger, clusterReadService read.ClusterReadService,
+	k8sUtil *k8s.K8sServiceImpl, auditLogService auditLog.AuditLogService) (*PortForwardServiceImpl, error) {
+	config := &bean.PortForwardConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing port-forward config", "err", err)
+		return nil, err
+	}
+	impl := &PortForwardServiceImpl{
+		logger:             logger,
+		clusterReadService: clusterReadService,
+		k8sUtil:            k8sUtil,
+		auditLogService:    auditLogService,
+		config:             config,
+		sessions:           make(map[string]*session),
+	}
+	go impl.closeExpiredSessions()
+	return impl, nil
+}
+
+func (impl *PortForwardServiceImpl) CreateSession(ctx context.Context, request *bean.SessionRequest) (*bean.SessionDto, error) {
+	err := validateSessionRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	if count := impl.countSessions(request.UserId); count >= impl.config.MaxSessionsPerUser {
+		message := fmt.Sprintf("at most %d port-forward sessions can be open at once, close one to open another", impl.config.MaxSessionsPerUser)
+		return nil, util.NewApiError(http.StatusTooManyRequests, message, message)
+	}
+	clusterBean, err := impl.clusterReadService.FindById(request.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster", "clusterId", request.ClusterId, "err", err)
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "cluster not found", err.Error())
+		}
+		return nil, err
+	}
+	clusterConfig := clusterBean.GetClusterConfig()
+	restConfig, err := impl.k8sUtil.GetRestConfigByCluster(clusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error in getting rest config by cluster", "clusterId", request.ClusterId, "err", err)
+		return nil, err
+	}
+	_, clientSet, err := impl.k8sUtil.GetK8sConfigAndClientsByRestConfig(restConfig)
+	if err != nil {
+		impl.logger.Errorw("error in getting client set", "clusterId", request.ClusterId, "err", err)
+		return nil, err
+	}
+	podName, targetPort := request.PodName, request.Port
+	if len(request.ServiceName) > 0 {
+		service, err := clientSet.CoreV1().Services(request.Namespace).Get(ctx, request.ServiceName, metav1.GetOptions{})
+		if err != nil {
+			impl.logger.Errorw("error in fetching service", "namespace", request.Namespace, "service", request.ServiceName, "err", err)
+			return nil, err
+		}
+		pods, err := clientSet.CoreV1().Pods(request.Namespace).List(ctx, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String()})
+		if err != nil {
+			impl.logger.Errorw("error in fetching pods of service", "namespace", request.Namespace, "service", request.ServiceName, "err", err)
+			return nil, err
+		}
+		podName, targetPort, err = getServiceTarget(service, request.Port, pods.Items)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		pod, err := clientSet.CoreV1().Pods(request.Namespace).Get(ctx, request.PodName, metav1.GetOptions{})
+		if err != nil {
+			impl.logger.Errorw("error in fetching pod", "namespace", request.Namespace, "pod", request.PodName, "err", err)
+			return nil, err
+		}
+		err = validatePod(pod)
+		if err != nil {
+			return nil, err
+		}
+	}
+	portForwardUrl := clientSet.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(request.Namespace).Name(podName).SubResource("portforward").URL()
+	// spdy needs the tls configuration in the rest config instead of the custom transport, like terminal sessions
+	clusterConfig.PopulateTlsConfigurationsInto(restConfig)
+	restConfig.Transport = nil
+
+	now := time.Now()
+	sessionCtx, cancel := context.WithCancel(context.Background())
+	s := &session{
+		id:             uuid.New().String(),
+		userId:         request.UserId,
+		request:        request,
+		podName:        podName,
+		targetPort:     targetPort,
+		restConfig:     restConfig,
+		portForwardUrl: portForwardUrl,
+		createdOn:      now,
+		expiresOn:      now.Add(time.Duration(impl.config.SessionTtlMinutes) * time.Minute),
+		ctx:            sessionCtx,
+		cancel:         cancel,
+	}
+	s.touch()
+	impl.lock.Lock()
+	impl.sessions[s.id] = s
+	impl.lock.Unlock()
+	impl.audit(s, auditLogBean.ActionCreate, "")
+	return s.toDto(impl.getIdleTimeout()), nil
+}
+
+func (impl *PortForwardServiceImpl) GetSessions(userId int32) []*bean.SessionDto {
+	impl.lock.RLock()
+	defer impl.lock.RUnlock()
+	sessions := make([]*bean.SessionDto, 0)
+	for _, s := range impl.sessions {
+		if s.userId == userId {
+			sessions = append(sessions, s.toDto(impl.getIdleTimeout()))
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedOn.Before(sessions[j].CreatedOn)
+	})
+	return sessions
+}
+
+func (impl *PortForwardServiceImpl) ValidateSession(sessionId string, userId int32) error {
+	_, err := impl.getSession(sessionId, userId)
+	return err
+}
+
+func (impl *PortForwardServiceImpl) Tunnel(sessionId string, client io.ReadWriteCloser) error {
+	impl.lock.RLock()
+	s, ok := impl.sessions[sessionId]
+	impl.lock.RUnlock()
+	if !ok {
+		_ = client.Close()
+		return util.NewApiError(http.StatusNotFound, "port-forward session not found, it may have expired", "session not found")
+	}
+	err := s.tunnel(impl.logger, client)
+	if err != nil {
+		impl.logger.Errorw("error in port-forward tunnel", "sessionId", sessionId, "pod", s.podName, "port", s.targetPort, "err", err)
+	}
+	return err
+}
+
+func (impl *PortForwardServiceImpl) CloseSession(sessionId string, userId int32) error {
+	s, err := impl.getSession(sessionId, userId)
+	if err != nil {
+		return err
+	}
+	impl.closeSession(s, bean.CloseReasonClosed)
+	return nil
+}
+
+func (impl *PortForwardServiceImpl) getSession(sessionId string, userId int32) (*session, error) {
+	impl.lock.RLock()
+	s, ok := impl.sessions[sessionId]
+	impl.lock.RUnlock()
+	// sessions of other users are not found rather than forbidden, their ids are not leaked
+	if !ok || s.userId != userId {
+		return nil, util.NewApiError(http.StatusNotFound, "port-forward session not found, it may have expired", "session not found")
+	}
+	return s, nil
+}
+
+func (impl *PortForwardServiceImpl) countSessions(userId int32) int {
+	impl.lock.RLock()
+	defer impl.lock.RUnlock()
+	count := 0
+	for _, s := range impl.sessions {
+		if s.userId == userId {
+			count++
+		}
+	}
+	return count
+}
+
+func (impl *PortForwardServiceImpl) getIdleTimeout() time.Duration {
+	return time.Duration(impl.config.IdleTimeoutMinutes) * time.Minute
+}
+
+func (impl *PortForwardServiceImpl) closeExpiredSessions() {
+	ticker := time.NewTicker(time.Duration(impl.config.ExpiryCheckIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		now := time.Now()
+		idleTimeout := impl.getIdleTimeout()
+		impl.lock.RLock()
+		expired := make(map[*session]bean.CloseReason)
+		for _, s := range impl.sessions {
+			if now.After(s.expiresOn) {
+				expired[s] = bean.CloseReasonExpired
+			} else if s.isIdle(idleTimeout) {
+				expired[s] = bean.CloseReasonIdle
+			}
+		}
+		impl.lock.RUnlock()
+		for s, reason := range expired {
+			impl.closeSession(s, reason)
+		}
+	}
+}
+
+// closeSession ends the tunnels of a session, it is audited once even when closed by the user and by expiry at once
+func (impl *PortForwardServiceImpl) closeSession(s *session, reason bean.CloseReason) {
+	impl.lock.Lock()
+	_, ok := impl.sessions[s.id]
+	delete(impl.sessions, s.id)
+	impl.lock.Unlock()
+	if !ok {
+		return
+	}
+	s.cancel()
+	impl.logger.Infow("port-forward session closed", "sessionId", s.id, "userId", s.userId, "reason", reason)
+	impl.audit(s, auditLogBean.ActionDelete, reason)
+}
+
+func (impl *PortForwardServiceImpl) audit(s *session, action auditLogBean.Action, reason bean.CloseReason) {
+	resourceIds := map[string]string{
+		"sessionId":  s.id,
+		"clusterId":  strconv.Itoa(s.request.ClusterId),
+		"namespace":  s.request.Namespace,
+		"pod":        s.podName,
+		"targetPort": strconv.Itoa(s.targetPort),
+	}
+	if len(s.request.ServiceName) > 0 {
+		resourceIds["service"] = s.request.ServiceName
+		resourceIds["port"] = strconv.Itoa(s.request.Port)
+	}
+	if action == auditLogBean.ActionDelete {
+		resourceIds["reason"] = string(reason)
+		resourceIds["bytesIn"] = strconv.FormatInt(s.bytesIn.Load(), 10)
+		resourceIds["bytesOut"] = strconv.FormatInt(s.bytesOut.Load(), 10)
+	}
+	impl.auditLogService.Record(&auditLogBean.AuditEvent{
+		UserId:       s.userId,
+		Method:       bean.AuditMethodPortForward,
+		Path:         bean.AuditPathPortForward,
+		Resource:     bean.AuditResourcePortForward,
+		ResourceIds:  resourceIds,
+		Action:       action,
+		Outcome:      auditLogBean.OutcomeSuccess,
+		StatusCode:   http.StatusOK,
+		DurationInMs: time.Since(s.createdOn).Milliseconds(),
+		CreatedOn:    time.Now(),
+	})
+}
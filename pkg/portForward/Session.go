@@ -0,0 +1,191 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package portForward
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/portForward/bean"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// session is an open port-forward, every tunnel of it is a connection to the target port of the pod
+type session struct {
+	id             string
+	userId         int32
+	request        *bean.SessionRequest
+	podName        string
+	targetPort     int
+	restConfig     *rest.Config
+	portForwardUrl *url.URL
+	createdOn      time.Time
+	expiresOn      time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	// lastActivity is the unix nano time of the last traffic, or of the last tunnel opened or closed
+	lastActivity      atomic.Int64
+	activeConnections atomic.Int32
+	bytesIn           atomic.Int64
+	bytesOut          atomic.Int64
+	requestId         atomic.Int64
+}
+
+func (s *session) touch() {
+	s.lastActivity.Store(time.Now().UnixNano())
+}
+
+func (s *session) isIdle(idleTimeout time.Duration) bool {
+	return s.activeConnections.Load() == 0 && time.Since(time.Unix(0, s.lastActivity.Load())) > idleTimeout
+}
+
+func (s *session) toDto(idleTimeout time.Duration) *bean.SessionDto {
+	return &bean.SessionDto{
+		SessionId:          s.id,
+		ClusterId:          s.request.ClusterId,
+		Namespace:          s.request.Namespace,
+		PodName:            s.podName,
+		ServiceName:        s.request.ServiceName,
+		Port:               s.request.Port,
+		TargetPort:         s.targetPort,
+		CreatedOn:          s.createdOn,
+		ExpiresOn:          s.expiresOn,
+		IdleTimeoutSeconds: int(idleTimeout.Seconds()),
+		ActiveConnections:  s.activeConnections.Load(),
+		BytesIn:            s.bytesIn.Load(),
+		BytesOut:           s.bytesOut.Load(),
+	}
+}
+
+// tunnel forwards a client connection to the target port of the pod until either side closes it or the session ends
+func (s *session) tunnel(logger *zap.SugaredLogger, client io.ReadWriteCloser) error {
+	s.activeConnections.Add(1)
+	s.touch()
+	defer func() {
+		s.activeConnections.Add(-1)
+		s.touch()
+	}()
+	transport, upgrader, err := spdy.RoundTripperFor(s.restConfig)
+	if err != nil {
+		return err
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, s.portForwardUrl)
+	connection, _, err := dialer.Dial(portforward.PortForwardProtocolV1Name)
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	// the streams of a connection to a pod port are told apart by the request id, like kubectl port-forward does
+	headers := http.Header{}
+	headers.Set(v1.StreamType, v1.StreamTypeError)
+	headers.Set(v1.PortHeader, strconv.Itoa(s.targetPort))
+	headers.Set(v1.PortForwardRequestIDHeader, strconv.FormatInt(s.requestId.Add(1), 10))
+	errorStream, err := connection.CreateStream(headers)
+	if err != nil {
+		return err
+	}
+	// the error stream is only read from
+	_ = errorStream.Close()
+	headers.Set(v1.StreamType, v1.StreamTypeData)
+	dataStream, err := connection.CreateStream(headers)
+	if err != nil {
+		return err
+	}
+	return s.copyStreams(logger, client, connection, dataStream, errorStream)
+}
+
+func (s *session) copyStreams(logger *zap.SugaredLogger, client io.ReadWriteCloser, connection httpstream.Connection,
+	dataStream httpstream.Stream, errorStream httpstream.Stream) error {
+	remoteErr := make(chan error, 1)
+	go func() {
+		message, err := io.ReadAll(errorStream)
+		if err == nil && len(message) > 0 {
+			err = fmt.Errorf("error forwarding port %d of pod %s: %s", s.targetPort, s.podName, string(message))
+		}
+		remoteErr <- err
+	}()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		// closing the write side tells the pod the client is done, the response can still be read
+		defer dataStream.Close()
+		_, err := io.Copy(dataStream, &countingReader{reader: client, count: &s.bytesIn, session: s})
+		if err != nil {
+			logger.Debugw("port-forward client stream ended", "sessionId", s.id, "err", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		_, err := io.Copy(client, &countingReader{reader: dataStream, count: &s.bytesOut, session: s})
+		if err != nil {
+			logger.Debugw("port-forward pod stream ended", "sessionId", s.id, "err", err)
+		}
+		// the client is closed once the pod is done so that the copy from it ends as well
+		_ = client.Close()
+	}()
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-s.ctx.Done():
+		_ = client.Close()
+		_ = connection.Close()
+		<-done
+	case <-connection.CloseChan():
+		_ = client.Close()
+		<-done
+	}
+	select {
+	case err := <-remoteErr:
+		return err
+	default:
+		return nil
+	}
+}
+
+type countingReader struct {
+	reader  io.Reader
+	count   *atomic.Int64
+	session *session
+}
+
+func (reader *countingReader) Read(p []byte) (int, error) {
+	n, err := reader.reader.Read(p)
+	if n > 0 {
+		reader.count.Add(int64(n))
+		reader.session.touch()
+	}
+	return n, err
+}
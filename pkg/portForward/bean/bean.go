@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type PortForwardConfig struct {
+	SessionTtlMinutes          int `env:"PORT_FORWARD_SESSION_TTL_MINUTES" envDefault:"60" description:"Port-forward sessions are closed this long after they are opened"`
+	IdleTimeoutMinutes         int `env:"PORT_FORWARD_IDLE_TIMEOUT_MINUTES" envDefault:"10" description:"Port-forward sessions without open connections are closed after this long without traffic"`
+	MaxSessionsPerUser         int `env:"PORT_FORWARD_MAX_SESSIONS_PER_USER" envDefault:"5" description:"Most port-forward sessions a user can have open at once"`
+	ExpiryCheckIntervalSeconds int `env:"PORT_FORWARD_EXPIRY_CHECK_INTERVAL_SECONDS" envDefault:"30" description:"How often port-forward sessions are checked for expiry and idleness"`
+}
+
+type CloseReason string
+
+const (
+	CloseReasonClosed  CloseReason = "closed"
+	CloseReasonExpired CloseReason = "expired"
+	CloseReasonIdle    CloseReason = "idle"
+)
+
+const (
+	AuditMethodPortForward   = "PORT_FORWARD"
+	AuditResourcePortForward = "port-forward/session"
+	AuditPathPortForward     = "/orchestrator/k8s/port-forward/session"
+)
+
+// SessionRequest opens a port-forward to a port of a pod, or to a port of a service which is forwarded to the
+// target port of one of its ready pods
+type SessionRequest struct {
+	ClusterId   int    `json:"clusterId" validate:"required,gt=0"`
+	Namespace   string `json:"namespace" validate:"required"`
+	PodName     string `json:"podName,omitempty"`
+	ServiceName string `json:"serviceName,omitempty"`
+	Port        int    `json:"port" validate:"required,gt=0,lte=65535"`
+	UserId      int32  `json:"-"`
+}
+
+type SessionDto struct {
+	SessionId   string `json:"sessionId"`
+	ClusterId   int    `json:"clusterId"`
+	Namespace   string `json:"namespace"`
+	PodName     string `json:"podName"`
+	ServiceName string `json:"serviceName,omitempty"`
+	Port        int    `json:"port"`
+	// TargetPort is the port of the pod connections are forwarded to
+	TargetPort         int       `json:"targetPort"`
+	CreatedOn          time.Time `json:"createdOn"`
+	ExpiresOn          time.Time `json:"expiresOn"`
+	IdleTimeoutSeconds int       `json:"idleTimeoutSeconds"`
+	ActiveConnections  int32     `json:"activeConnections"`
+	BytesIn            int64     `json:"bytesIn"`
+	BytesOut           int64     `json:"bytesOut"`
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package portForward
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/portForward/bean"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func validateSessionRequest(request *bean.SessionRequest) error {
+	if (len(request.PodName) > 0) == (len(request.ServiceName) > 0) {
+		return util.NewApiError(http.StatusBadRequest, "a port-forward is opened to either a pod or a service", "exactly one of podName and serviceName is required")
+	}
+	return nil
+}
+
+func validatePod(pod *v1.Pod) error {
+	if pod.Status.Phase != v1.PodRunning {
+		message := fmt.Sprintf("pod %s is %s, only running pods can be port-forwarded to", pod.Name, pod.Status.Phase)
+		return util.NewApiError(http.StatusBadRequest, message, message)
+	}
+	return nil
+}
+
+// getServiceTarget returns the ready pod and its port a service port is forwarded to, like kubectl port-forward
+// does for services. Pods are sorted by name so that sessions of the same service land on the same pod
+func getServiceTarget(service *v1.Service, port int, pods []v1.Pod) (string, int, error) {
+	var servicePort *v1.ServicePort
+	for i := range service.Spec.Ports {
+		if int(service.Spec.Ports[i].Port) == port {
+			servicePort = &service.Spec.Ports[i]
+			break
+		}
+	}
+	if servicePort == nil {
+		message := fmt.Sprintf("service %s has no port %d", service.Name, port)
+		return "", 0, util.NewApiError(http.StatusBadRequest, message, message)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+	for i := range pods {
+		if !isPodReady(&pods[i]) {
+			continue
+		}
+		targetPort, ok := getTargetPort(&pods[i], servicePort)
+		if ok {
+			return pods[i].Name, targetPort, nil
+		}
+	}
+	message := fmt.Sprintf("service %s has no ready pod serving port %d", service.Name, port)
+	return "", 0, util.NewApiError(http.StatusBadRequest, message, message)
+}
+
+func getTargetPort(pod *v1.Pod, servicePort *v1.ServicePort) (int, bool) {
+	targetPort := servicePort.TargetPort
+	if targetPort.Type == intstr.Int {
+		if targetPort.IntValue() == 0 {
+			// an unset target port is the port of the service
+			return int(servicePort.Port), true
+		}
+		return targetPort.IntValue(), true
+	}
+	for _, container := range pod.Spec.Containers {
+		for _, containerPort := range container.Ports {
+			if containerPort.Name == targetPort.StrVal && containerPort.Protocol == servicePort.Protocol {
+				return int(containerPort.ContainerPort), true
+			}
+		}
+	}
+	return 0, false
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	if pod.Status.Phase != v1.PodRunning || pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package portForward
+
+import (
+	"testing"
+
+	"github.com/devtron-labs/devtron/pkg/portForward/bean"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func testPod(name string, ready bool, ports ...v1.ContainerPort) v1.Pod {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "app", Ports: ports}}},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestValidateSessionRequest(t *testing.T) {
+	assert.NoError(t, validateSessionRequest(&bean.SessionRequest{PodName: "web"}))
+	assert.NoError(t, validateSessionRequest(&bean.SessionRequest{ServiceName: "web"}))
+	assert.Error(t, validateSessionRequest(&bean.SessionRequest{}))
+	assert.Error(t, validateSessionRequest(&bean.SessionRequest{PodName: "web", ServiceName: "web"}))
+}
+
+func TestIsPodReady(t *testing.T) {
+	ready := testPod("web", true)
+	assert.True(t, isPodReady(&ready))
+
+	notReady := testPod("web", false)
+	assert.False(t, isPodReady(&notReady))
+
+	pending := testPod("web", true)
+	pending.Status.Phase = v1.PodPending
+	assert.False(t, isPodReady(&pending))
+
+	terminating := testPod("web", true)
+	terminating.DeletionTimestamp = &metav1.Time{}
+	assert.False(t, isPodReady(&terminating))
+}
+
+func TestGetTargetPort(t *testing.T) {
+	pod := testPod("web", true, v1.ContainerPort{Name: "http", ContainerPort: 8080, Protocol: v1.ProtocolTCP})
+	tests := []struct {
+		name       string
+		port       v1.ServicePort
+		targetPort int
+		found      bool
+	}{
+		{name: "numeric", port: v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(9090)}, targetPort: 9090, found: true},
+		{name: "unset", port: v1.ServicePort{Port: 80}, targetPort: 80, found: true},
+		{name: "named", port: v1.ServicePort{Port: 80, Protocol: v1.ProtocolTCP, TargetPort: intstr.FromString("http")}, targetPort: 8080, found: true},
+		{name: "unknown name", port: v1.ServicePort{Port: 80, Protocol: v1.ProtocolTCP, TargetPort: intstr.FromString("grpc")}, found: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetPort, found := getTargetPort(&pod, &tt.port)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.targetPort, targetPort)
+		})
+	}
+}
+
+func TestGetServiceTarget(t *testing.T) {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: v1.ServiceSpec{Ports: []v1.ServicePort{
+			{Port: 80, Protocol: v1.ProtocolTCP, TargetPort: intstr.FromString("http")},
+		}},
+	}
+	httpPort := v1.ContainerPort{Name: "http", ContainerPort: 8080, Protocol: v1.ProtocolTCP}
+	pods := []v1.Pod{
+		testPod("web-c", true, httpPort),
+		testPod("web-a", false, httpPort),
+		testPod("web-b", true, httpPort),
+	}
+
+	podName, targetPort, err := getServiceTarget(service, 80, pods)
+	assert.NoError(t, err)
+	assert.Equal(t, "web-b", podName)
+	assert.Equal(t, 8080, targetPort)
+
+	_, _, err = getServiceTarget(service, 443, pods)
+	assert.Error(t, err)
+
+	_, _, err = getServiceTarget(service, 80, []v1.Pod{testPod("web-a", false, httpPort)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,657 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package previewEnvironment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/async"
+	k8s2 "github.com/devtron-labs/common-lib/utils/k8s"
+	bean3 "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	appRepository "github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appClone"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	bean2 "github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment"
+	envBean "github.com/devtron-labs/devtron/pkg/cluster/environment/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/common"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git"
+	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps"
+	triggerBean "github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/previewEnvironment/bean"
+	previewRepository "github.com/devtron-labs/devtron/pkg/previewEnvironment/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	"time"
+)
+
+type PreviewEnvironmentService interface {
+	CreateOrUpdateConfig(request *bean.PreviewEnvironmentConfigRequest) (*bean.PreviewEnvironmentConfigDto, error)
+	GetConfigs(appId int) ([]*bean.PreviewEnvironmentConfigDto, error)
+	// DeleteConfig deactivates the config and tears down all of its preview environments
+	DeleteConfig(appId, configId int, userId int32) error
+	GetPreviewEnvironments(appId int) ([]*bean.PreviewEnvironmentDto, error)
+	DeletePreviewEnvironment(appId, id int, userId int32) error
+	// HandleCiArtifact deploys an artifact built from a pull request in its preview environment,
+	// the environment and its cd pipeline are provisioned on the first build of the pull request
+	HandleCiArtifact(ciPipelineId, ciArtifactId int)
+	// HandleGitWebhookEvent tears down preview environments of the pull request closed or merged in the event
+	HandleGitWebhookEvent(payload string)
+	CleanUpExpiredPreviewEnvironments()
+}
+
+type PreviewEnvironmentServiceImpl struct {
+	logger                             *zap.SugaredLogger
+	serviceConfig                      *bean.PreviewEnvironmentServiceConfig
+	previewEnvironmentConfigRepository previewRepository.PreviewEnvironmentConfigRepository
+	previewEnvironmentRepository       previewRepository.PreviewEnvironmentRepository
+	appRepository                      appRepository.AppRepository
+	ciArtifactRepository               repository.CiArtifactRepository
+	ciPipelineMaterialRepository       pipelineConfig.CiPipelineMaterialRepository
+	pipelineRepository                 pipelineConfig.PipelineRepository
+	environmentService                 environment.EnvironmentService
+	appCloneService                    appClone.AppCloneService
+	cdPipelineConfigService            pipeline.CdPipelineConfigService
+	cdTriggerService                   devtronApps.TriggerService
+	deploymentConfigService            common.DeploymentConfigService
+	gitOperationService                git.GitOperationService
+	k8sUtil                            *k8s2.K8sServiceImpl
+	asyncRunnable                      *async.Runnable
+}
+
+// errPreviewEnvironmentProvisioning is returned when another build of the pull request is provisioning its preview
+var errPreviewEnvironmentProvisioning = errors.New("preview environment is being provisioned by another build")
+
+func NewPreviewEnvironmentServiceImpl(logger *zap.SugaredLogger,
+	previewEnvironmentConfigRepository previewRepository.PreviewEnvironmentConfigRepository,
+	previewEnvironmentRepository previewRepository.PreviewEnvironmentRepository,
+	appRepository appRepository.AppRepository,
+	ciArtifactRepository repository.CiArtifactRepository,
+	ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	environmentService environment.EnvironmentService,
+	appCloneService appClone.AppCloneService,
+	cdPipelineConfigService pipeline.CdPipelineConfigService,
+	cdTriggerService devtronApps.TriggerService,
+	deploymentConfigService common.DeploymentConfigService,
+	gitOperationService git.GitOperationService,
+	k8sUtil *k8s2.K8sServiceImpl,
+	asyncRunnable *async.Runnable,
+	cronLogger *cron2.CronLoggerImpl) (*PreviewEnvironmentServiceImpl, error) {
+	serviceConfig := &bean.PreviewEnvironmentServiceConfig{}
+	err := env.Parse(serviceConfig)
+	if err != nil {
+		logger.Errorw("error in parsing preview environment config", "err", err)
+		return nil, err
+	}
+	impl := &PreviewEnvironmentServiceImpl{
+		logger:                             logger,
+		serviceConfig:                      serviceConfig,
+		previewEnvironmentConfigRepository: previewEnvironmentConfigRepository,
+		previewEnvironmentRepository:       previewEnvironmentRepository,
+		appRepository:                      appRepository,
+		ciArtifactRepository:               ciArtifactRepository,
+		ciPipelineMaterialRepository:       ciPipelineMaterialRepository,
+		pipelineRepository:                 pipelineRepository,
+		environmentService:                 environmentService,
+		appCloneService:                    appCloneService,
+		cdPipelineConfigService:            cdPipelineConfigService,
+		cdTriggerService:                   cdTriggerService,
+		deploymentConfigService:            deploymentConfigService,
+		gitOperationService:                gitOperationService,
+		k8sUtil:                            k8sUtil,
+		asyncRunnable:                      asyncRunnable,
+	}
+	cleanUpCron := cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	_, err = cleanUpCron.AddFunc(serviceConfig.CleanUpCronSchedule, impl.CleanUpExpiredPreviewEnvironments)
+	if err != nil {
+		logger.Errorw("error in adding preview environment clean up cron", "schedule", serviceConfig.CleanUpCronSchedule, "err", err)
+		return nil, err
+	}
+	cleanUpCron.Start()
+	return impl, nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) CreateOrUpdateConfig(request *bean.PreviewEnvironmentConfigRequest) (*bean.PreviewEnvironmentConfigDto, error) {
+	templatePipeline, err := impl.pipelineRepository.FindById(request.TemplateCdPipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching template cd pipeline", "templateCdPipelineId", request.TemplateCdPipelineId, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) || templatePipeline.AppId != request.AppId {
+		errMsg := fmt.Sprintf("cd pipeline %d not found in app %d", request.TemplateCdPipelineId, request.AppId)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	if templatePipeline.CiPipelineId == 0 {
+		errMsg := "template cd pipeline must be attached to a ci pipeline building pull requests"
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	ttlHours := request.TtlHours
+	if ttlHours == 0 {
+		ttlHours = impl.serviceConfig.DefaultTtlHours
+	}
+	existingConfig, err := impl.previewEnvironmentConfigRepository.FindActiveByCiPipelineId(templatePipeline.CiPipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching preview environment config", "ciPipelineId", templatePipeline.CiPipelineId, "err", err)
+		return nil, err
+	}
+	if existingConfig.Id > 0 && existingConfig.Id != request.Id {
+		errMsg := fmt.Sprintf("preview environments are already configured for ci pipeline %d", templatePipeline.CiPipelineId)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	model := &previewRepository.PreviewEnvironmentConfig{}
+	if request.Id > 0 {
+		model, err = impl.getConfig(request.AppId, request.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	model.AppId = request.AppId
+	model.CiPipelineId = templatePipeline.CiPipelineId
+	model.TemplateCdPipelineId = templatePipeline.Id
+	model.TtlHours = ttlHours
+	model.Active = true
+	if model.Id > 0 {
+		model.UpdateAuditLog(request.UserId)
+		err = impl.previewEnvironmentConfigRepository.Update(model)
+	} else {
+		model.AuditLog = sql.NewDefaultAuditLog(request.UserId)
+		err = impl.previewEnvironmentConfigRepository.Save(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving preview environment config", "model", model, "err", err)
+		return nil, err
+	}
+	return toConfigDto(model, templatePipeline), nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) GetConfigs(appId int) ([]*bean.PreviewEnvironmentConfigDto, error) {
+	models, err := impl.previewEnvironmentConfigRepository.FindActiveByAppId(appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching preview environment configs", "appId", appId, "err", err)
+		return nil, err
+	}
+	dtos := make([]*bean.PreviewEnvironmentConfigDto, 0, len(models))
+	for _, model := range models {
+		templatePipeline, err := impl.pipelineRepository.FindById(model.TemplateCdPipelineId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching template cd pipeline", "templateCdPipelineId", model.TemplateCdPipelineId, "err", err)
+			return nil, err
+		}
+		dtos = append(dtos, toConfigDto(model, templatePipeline))
+	}
+	return dtos, nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) DeleteConfig(appId, configId int, userId int32) error {
+	model, err := impl.getConfig(appId, configId)
+	if err != nil {
+		return err
+	}
+	model.Active = false
+	model.UpdateAuditLog(userId)
+	err = impl.previewEnvironmentConfigRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in deleting preview environment config", "id", configId, "err", err)
+		return err
+	}
+	previews, err := impl.previewEnvironmentRepository.FindActiveByConfigId(configId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching preview environments of config", "configId", configId, "err", err)
+		return err
+	}
+	impl.tearDownAsync(previews, userId)
+	return nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) GetPreviewEnvironments(appId int) ([]*bean.PreviewEnvironmentDto, error) {
+	previews, err := impl.previewEnvironmentRepository.FindActiveByAppId(appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching preview environments", "appId", appId, "err", err)
+		return nil, err
+	}
+	configIds := make([]int, 0, len(previews))
+	envIds := make([]*int, 0, len(previews))
+	for _, preview := range previews {
+		configIds = append(configIds, preview.PreviewEnvironmentConfigId)
+		if preview.EnvironmentId > 0 {
+			envIds = append(envIds, &preview.EnvironmentId)
+		}
+	}
+	configs, err := impl.previewEnvironmentConfigRepository.FindActiveByIds(configIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching preview environment configs", "ids", configIds, "err", err)
+		return nil, err
+	}
+	ttlByConfigId := make(map[int]int, len(configs))
+	for _, config := range configs {
+		ttlByConfigId[config.Id] = config.TtlHours
+	}
+	envById := make(map[int]*envBean.EnvironmentBean)
+	if len(envIds) > 0 {
+		envs, err := impl.environmentService.FindByIds(envIds)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environments", "err", err)
+			return nil, err
+		}
+		for _, env := range envs {
+			envById[env.Id] = env
+		}
+	}
+	dtos := make([]*bean.PreviewEnvironmentDto, 0, len(previews))
+	for _, preview := range previews {
+		dto := toPreviewEnvironmentDto(preview, ttlByConfigId[preview.PreviewEnvironmentConfigId])
+		if env, ok := envById[preview.EnvironmentId]; ok {
+			dto.EnvironmentName = env.Environment
+			dto.Namespace = env.Namespace
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos, nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) DeletePreviewEnvironment(appId, id int, userId int32) error {
+	preview, err := impl.previewEnvironmentRepository.FindById(id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching preview environment", "id", id, "err", err)
+		return err
+	}
+	if util.IsErrNoRows(err) || preview.AppId != appId || preview.Status == bean.PreviewEnvironmentStatusDeleted {
+		errMsg := fmt.Sprintf("preview environment %d not found", id)
+		return util.NewApiError(http.StatusNotFound, errMsg, errMsg)
+	}
+	impl.tearDownAsync([]*previewRepository.PreviewEnvironment{preview}, userId)
+	return nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) HandleCiArtifact(ciPipelineId, ciArtifactId int) {
+	config, err := impl.previewEnvironmentConfigRepository.FindActiveByCiPipelineId(ciPipelineId)
+	if err != nil {
+		if !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching preview environment config", "ciPipelineId", ciPipelineId, "err", err)
+		}
+		return
+	}
+	artifact, err := impl.ciArtifactRepository.Get(ciArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci artifact", "ciArtifactId", ciArtifactId, "err", err)
+		return
+	}
+	pullRequest, err := getPullRequestInfoFromMaterialInfo(artifact.MaterialInfo)
+	if err != nil {
+		impl.logger.Errorw("error in parsing material info of artifact", "ciArtifactId", ciArtifactId, "err", err)
+		return
+	}
+	if pullRequest == nil {
+		// not a pull request build
+		return
+	}
+	impl.asyncRunnable.Execute(func() {
+		impl.deployPullRequestBuild(config, pullRequest, ciArtifactId)
+	})
+}
+
+func (impl *PreviewEnvironmentServiceImpl) deployPullRequestBuild(config *previewRepository.PreviewEnvironmentConfig, pullRequest *bean.PullRequestInfo, ciArtifactId int) {
+	gitMaterialId, err := impl.getGitMaterialIdOfRepo(config.CiPipelineId, pullRequest.RepoUrl)
+	if err != nil {
+		impl.logger.Errorw("error in finding git material of pull request", "ciPipelineId", config.CiPipelineId, "repoUrl", pullRequest.RepoUrl, "err", err)
+		return
+	}
+	preview, err := impl.getOrProvisionPreviewEnvironment(config, gitMaterialId, pullRequest)
+	if errors.Is(err, errPreviewEnvironmentProvisioning) {
+		impl.logger.Infow("skipping deployment of pull request build", "configId", config.Id, "pullRequestId", pullRequest.Id, "ciArtifactId", ciArtifactId, "reason", err.Error())
+		return
+	} else if err != nil {
+		impl.logger.Errorw("error in provisioning preview environment", "configId", config.Id, "pullRequestId", pullRequest.Id, "err", err)
+		return
+	}
+	if preview.Status == bean.PreviewEnvironmentStatusTearingDown {
+		impl.logger.Infow("preview environment is being torn down, skipping deployment", "id", preview.Id)
+		return
+	}
+	overrideRequest := &bean3.ValuesOverrideRequest{
+		PipelineId:           preview.CdPipelineId,
+		AppId:                preview.AppId,
+		CiArtifactId:         ciArtifactId,
+		CdWorkflowType:       bean3.CD_WORKFLOW_TYPE_DEPLOY,
+		DeploymentWithConfig: bean3.DEPLOYMENT_CONFIG_TYPE_LAST_SAVED,
+		UserId:               userBean.SYSTEM_USER_ID,
+	}
+	triggerContext := triggerBean.TriggerContext{
+		Context: context.Background(),
+	}
+	_, _, _, err = impl.cdTriggerService.ManualCdTrigger(triggerContext, overrideRequest)
+	preview.CiArtifactId = ciArtifactId
+	preview.LastActivityOn = time.Now()
+	if err != nil {
+		impl.logger.Errorw("error in deploying pull request build in preview environment", "id", preview.Id, "ciArtifactId", ciArtifactId, "err", err)
+		preview.Status = bean.PreviewEnvironmentStatusFailed
+		preview.Message = err.Error()
+	} else {
+		preview.Status = bean.PreviewEnvironmentStatusTriggered
+		preview.Message = ""
+	}
+	impl.updatePreviewEnvironment(preview)
+}
+
+// getGitMaterialIdOfRepo returns the git material of the ci pipeline pointing to the repo of a pull request
+func (impl *PreviewEnvironmentServiceImpl) getGitMaterialIdOfRepo(ciPipelineId int, repoUrl string) (int, error) {
+	ciPipelineMaterials, err := impl.ciPipelineMaterialRepository.GetByPipelineId(ciPipelineId)
+	if err != nil {
+		return 0, err
+	}
+	repoKey := getRepoKey(repoUrl)
+	for _, ciPipelineMaterial := range ciPipelineMaterials {
+		if ciPipelineMaterial.GitMaterial != nil && getRepoKey(ciPipelineMaterial.GitMaterial.Url) == repoKey {
+			return ciPipelineMaterial.GitMaterialId, nil
+		}
+	}
+	return 0, fmt.Errorf("no git material of ci pipeline %d points to repo %s", ciPipelineId, repoUrl)
+}
+
+// getOrProvisionPreviewEnvironment returns the live preview of the pull request, provisioning it if needed.
+// Concurrent builds are resolved in the db, only the build inserting the preview or claiming a failed one provisions it
+func (impl *PreviewEnvironmentServiceImpl) getOrProvisionPreviewEnvironment(config *previewRepository.PreviewEnvironmentConfig, gitMaterialId int, pullRequest *bean.PullRequestInfo) (*previewRepository.PreviewEnvironment, error) {
+	preview, err := impl.previewEnvironmentRepository.FindActiveByPullRequestKey(config.AppId, gitMaterialId, pullRequest.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		return nil, err
+	}
+	if preview.Id > 0 && preview.CdPipelineId > 0 {
+		return preview, nil
+	}
+	if preview.Id > 0 {
+		claimed, err := impl.previewEnvironmentRepository.MarkProvisioning(preview.Id, userBean.SYSTEM_USER_ID)
+		if err != nil {
+			return nil, err
+		}
+		if !claimed {
+			return nil, errPreviewEnvironmentProvisioning
+		}
+		preview.Status = bean.PreviewEnvironmentStatusProvisioning
+	} else {
+		preview = &previewRepository.PreviewEnvironment{
+			PreviewEnvironmentConfigId: config.Id,
+			AppId:                      config.AppId,
+			GitMaterialId:              gitMaterialId,
+			PullRequestId:              pullRequest.Id,
+			SourceBranch:               pullRequest.SourceBranch,
+			PullRequestUrl:             pullRequest.Url,
+			Status:                     bean.PreviewEnvironmentStatusProvisioning,
+			LastActivityOn:             time.Now(),
+			AuditLog:                   sql.NewDefaultAuditLog(userBean.SYSTEM_USER_ID),
+		}
+		err = impl.previewEnvironmentRepository.Save(preview)
+		if isPullRequestPreviewConflict(err) {
+			return nil, errPreviewEnvironmentProvisioning
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	err = impl.provisionPreviewEnvironment(config, preview)
+	if err != nil {
+		preview.Status = bean.PreviewEnvironmentStatusFailed
+		preview.Message = err.Error()
+		impl.updatePreviewEnvironment(preview)
+		return nil, err
+	}
+	impl.updatePreviewEnvironment(preview)
+	return preview, nil
+}
+
+// provisionPreviewEnvironment creates the environment in the cluster of the template environment
+// and clones the template cd pipeline along with its overrides into it
+func (impl *PreviewEnvironmentServiceImpl) provisionPreviewEnvironment(config *previewRepository.PreviewEnvironmentConfig, preview *previewRepository.PreviewEnvironment) error {
+	templatePipeline, err := impl.pipelineRepository.FindById(config.TemplateCdPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching template cd pipeline", "templateCdPipelineId", config.TemplateCdPipelineId, "err", err)
+		return err
+	}
+	if preview.EnvironmentId == 0 {
+		name := getPreviewEnvironmentName(templatePipeline.App.AppName, preview.GitMaterialId, preview.PullRequestId)
+		envRequest := &envBean.EnvironmentBean{
+			Environment: name,
+			ClusterId:   templatePipeline.Environment.ClusterId,
+			Namespace:   name,
+			Active:      true,
+			Description: bean.PreviewEnvironmentDescription,
+		}
+		env, err := impl.environmentService.Create(envRequest, userBean.SYSTEM_USER_ID)
+		if err != nil {
+			impl.logger.Errorw("error in creating preview environment", "name", name, "err", err)
+			return err
+		}
+		preview.EnvironmentId = env.Id
+	}
+	env, err := impl.environmentService.FindById(preview.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching preview environment", "environmentId", preview.EnvironmentId, "err", err)
+		return err
+	}
+	cloneRequest := &appClone.CdPipelineEnvCloneRequest{
+		RefCdPipelineId:     templatePipeline.Id,
+		TargetEnvironmentId: env.Id,
+		TargetNamespace:     env.Namespace,
+		PipelineName:        env.Environment,
+		UserId:              userBean.SYSTEM_USER_ID,
+	}
+	cdPipelines, err := impl.appCloneService.CloneCdPipelineToEnvironment(cloneRequest, context.Background())
+	if err != nil {
+		impl.logger.Errorw("error in cloning template cd pipeline", "cloneRequest", cloneRequest, "err", err)
+		return err
+	}
+	if len(cdPipelines.Pipelines) == 0 {
+		return fmt.Errorf("cd pipeline not created for preview environment %s", env.Environment)
+	}
+	preview.CdPipelineId = cdPipelines.Pipelines[0].Id
+	return nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) HandleGitWebhookEvent(payload string) {
+	pullRequest, closed := getClosedPullRequestFromPayload(payload)
+	if !closed || len(pullRequest.Id) == 0 {
+		return
+	}
+	previews, err := impl.previewEnvironmentRepository.FindActiveByPullRequest(pullRequest.Id, pullRequest.SourceBranch)
+	if err != nil {
+		impl.logger.Errorw("error in fetching preview environments of pull request", "pullRequestId", pullRequest.Id, "err", err)
+		return
+	}
+	impl.tearDownAsync(getPreviewEnvironmentsOfRepo(previews, pullRequest.RepoUrl), userBean.SYSTEM_USER_ID)
+}
+
+func (impl *PreviewEnvironmentServiceImpl) CleanUpExpiredPreviewEnvironments() {
+	now := time.Now()
+	previews, err := impl.previewEnvironmentRepository.FindExpired(now, now.Add(-time.Duration(impl.serviceConfig.TearDownRetryMins)*time.Minute))
+	if err != nil {
+		impl.logger.Errorw("error in fetching expired preview environments", "err", err)
+		return
+	}
+	if len(previews) > 0 {
+		impl.logger.Infow("tearing down expired preview environments", "count", len(previews))
+	}
+	for _, preview := range previews {
+		impl.tearDown(preview, userBean.SYSTEM_USER_ID)
+	}
+}
+
+func (impl *PreviewEnvironmentServiceImpl) tearDownAsync(previews []*previewRepository.PreviewEnvironment, userId int32) {
+	if len(previews) == 0 {
+		return
+	}
+	impl.asyncRunnable.Execute(func() {
+		for _, preview := range previews {
+			impl.tearDown(preview, userId)
+		}
+	})
+}
+
+// tearDown deletes the cd pipeline along with its argo cd app or helm release, the values of the environment
+// committed in the gitops repo, the namespace and the environment. On failure the preview is left in failed state
+// and is retried by the clean up job once expired, a tear down interrupted midway is retried after the retry period
+func (impl *PreviewEnvironmentServiceImpl) tearDown(preview *previewRepository.PreviewEnvironment, userId int32) {
+	preview.Status = bean.PreviewEnvironmentStatusTearingDown
+	preview.UpdateAuditLog(userId)
+	impl.updatePreviewEnvironment(preview)
+	err := impl.deletePreviewResources(preview, userId)
+	if err != nil {
+		impl.logger.Errorw("error in tearing down preview environment", "id", preview.Id, "err", err)
+		preview.Status = bean.PreviewEnvironmentStatusFailed
+		preview.Message = err.Error()
+	} else {
+		preview.Status = bean.PreviewEnvironmentStatusDeleted
+		preview.Message = ""
+	}
+	preview.UpdateAuditLog(userId)
+	impl.updatePreviewEnvironment(preview)
+}
+
+func (impl *PreviewEnvironmentServiceImpl) deletePreviewResources(preview *previewRepository.PreviewEnvironment, userId int32) error {
+	ctx := context.Background()
+	if preview.CdPipelineId > 0 {
+		cdPipeline, err := impl.pipelineRepository.FindById(preview.CdPipelineId)
+		if err != nil && !util.IsErrNoRows(err) {
+			return err
+		}
+		if cdPipeline.Id > 0 {
+			// deployment config is read before deleting the pipeline as it is marked inactive on delete
+			deploymentConfig, err := impl.deploymentConfigService.GetConfigForDevtronApps(cdPipeline.AppId, cdPipeline.EnvironmentId)
+			if err != nil {
+				impl.logger.Errorw("error in fetching deployment config", "appId", cdPipeline.AppId, "envId", cdPipeline.EnvironmentId, "err", err)
+				return err
+			}
+			_, err = impl.cdPipelineConfigService.DeleteCdPipeline(cdPipeline, ctx, bean2.CASCADE_DELETE, true, userId)
+			if err != nil {
+				impl.logger.Errorw("error in deleting preview cd pipeline", "pipelineId", cdPipeline.Id, "err", err)
+				return err
+			}
+			if util.IsAcdApp(deploymentConfig.DeploymentAppType) && len(deploymentConfig.RepoURL) > 0 {
+				commitMsg := fmt.Sprintf("removing values of preview environment %d", cdPipeline.EnvironmentId)
+				err = impl.gitOperationService.DeleteFileFromGitRepo(ctx, deploymentConfig.RepoURL, getEnvValuesFileName(cdPipeline.EnvironmentId), commitMsg, userId)
+				if err != nil {
+					impl.logger.Errorw("error in deleting preview values from gitops repo", "repoUrl", deploymentConfig.RepoURL, "err", err)
+					return err
+				}
+			}
+		}
+		preview.CdPipelineId = 0
+	}
+	if preview.EnvironmentId > 0 {
+		env, err := impl.environmentService.FindById(preview.EnvironmentId)
+		if err != nil && !util.IsErrNoRows(err) {
+			return err
+		}
+		if env != nil && env.Id > 0 {
+			err = impl.deleteNamespace(env)
+			if err != nil {
+				return err
+			}
+			err = impl.environmentService.Delete(&envBean.EnvironmentBean{Id: env.Id, Environment: env.Environment}, userId)
+			if err != nil {
+				impl.logger.Errorw("error in deleting preview environment", "envId", env.Id, "err", err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) deleteNamespace(env *envBean.EnvironmentBean) error {
+	if len(env.Namespace) == 0 {
+		return nil
+	}
+	clusterBean, err := impl.environmentService.FindClusterByEnvId(env.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster of preview environment", "envId", env.Id, "err", err)
+		return err
+	}
+	client, err := impl.k8sUtil.GetCoreV1Client(clusterBean.GetClusterConfig())
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s client", "clusterId", clusterBean.Id, "err", err)
+		return err
+	}
+	err = client.Namespaces().Delete(context.Background(), env.Namespace, metav1.DeleteOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		impl.logger.Errorw("error in deleting preview namespace", "namespace", env.Namespace, "clusterId", clusterBean.Id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) getConfig(appId, configId int) (*previewRepository.PreviewEnvironmentConfig, error) {
+	model, err := impl.previewEnvironmentConfigRepository.FindById(configId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching preview environment config", "id", configId, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) || model.AppId != appId {
+		errMsg := fmt.Sprintf("preview environment config %d not found", configId)
+		return nil, util.NewApiError(http.StatusNotFound, errMsg, errMsg)
+	}
+	return model, nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) updatePreviewEnvironment(preview *previewRepository.PreviewEnvironment) {
+	err := impl.previewEnvironmentRepository.Update(preview)
+	if err != nil {
+		impl.logger.Errorw("error in updating preview environment", "id", preview.Id, "status", preview.Status, "err", err)
+	}
+}
+
+func toConfigDto(model *previewRepository.PreviewEnvironmentConfig, templatePipeline *pipelineConfig.Pipeline) *bean.PreviewEnvironmentConfigDto {
+	dto := &bean.PreviewEnvironmentConfigDto{
+		Id:                   model.Id,
+		AppId:                model.AppId,
+		CiPipelineId:         model.CiPipelineId,
+		TemplateCdPipelineId: model.TemplateCdPipelineId,
+		TtlHours:             model.TtlHours,
+	}
+	if templatePipeline != nil {
+		dto.TemplateEnvironmentId = templatePipeline.EnvironmentId
+		dto.TemplateEnvironmentName = templatePipeline.Environment.Name
+	}
+	return dto
+}
+
+func toPreviewEnvironmentDto(model *previewRepository.PreviewEnvironment, ttlHours int) *bean.PreviewEnvironmentDto {
+	return &bean.PreviewEnvironmentDto{
+		Id:                         model.Id,
+		PreviewEnvironmentConfigId: model.PreviewEnvironmentConfigId,
+		AppId:                      model.AppId,
+		GitMaterialId:              model.GitMaterialId,
+		PullRequestId:              model.PullRequestId,
+		SourceBranch:               model.SourceBranch,
+		PullRequestUrl:             model.PullRequestUrl,
+		EnvironmentId:              model.EnvironmentId,
+		CdPipelineId:               model.CdPipelineId,
+		CiArtifactId:               model.CiArtifactId,
+		Status:                     model.Status,
+		Message:                    model.Message,
+		LastActivityOn:             model.LastActivityOn,
+		ExpiresOn:                  getExpiresOn(model.LastActivityOn, ttlHours),
+	}
+}
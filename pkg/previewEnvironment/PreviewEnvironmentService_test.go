@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package previewEnvironment
+
+import (
+	"errors"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	pipelineMocks "github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/mocks"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/previewEnvironment/bean"
+	previewRepository "github.com/devtron-labs/devtron/pkg/previewEnvironment/repository"
+	"github.com/devtron-labs/devtron/pkg/previewEnvironment/repository/mocks"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+// uniqueViolation mimics the error returned by postgres on a unique index violation
+type uniqueViolation struct {
+	index string
+}
+
+func (e uniqueViolation) Error() string {
+	return "duplicate key value violates unique constraint " + e.index
+}
+
+func (e uniqueViolation) Field(field byte) string {
+	if field == 'n' {
+		return e.index
+	}
+	return ""
+}
+
+func (e uniqueViolation) IntegrityViolation() bool {
+	return true
+}
+
+func TestGetOrProvisionPreviewEnvironment(t *testing.T) {
+	config := &previewRepository.PreviewEnvironmentConfig{Id: 1, AppId: 2, TemplateCdPipelineId: 3}
+	pullRequest := &bean.PullRequestInfo{Id: "11", SourceBranch: "feature/a", RepoUrl: "https://github.com/org/repo"}
+
+	t.Run("provisioned preview is reused", func(tt *testing.T) {
+		service, previewRepo, _ := initPreviewEnvironmentService(tt)
+		existing := &previewRepository.PreviewEnvironment{Id: 5, AppId: 2, GitMaterialId: 4, CdPipelineId: 6, Status: bean.PreviewEnvironmentStatusTriggered}
+		previewRepo.On("FindActiveByPullRequestKey", 2, 4, "11").Return(existing, nil)
+
+		preview, err := service.getOrProvisionPreviewEnvironment(config, 4, pullRequest)
+		assert.Nil(tt, err)
+		assert.Equal(tt, existing, preview)
+	})
+
+	t.Run("concurrent build of the pull request skips provisioning", func(tt *testing.T) {
+		service, previewRepo, _ := initPreviewEnvironmentService(tt)
+		previewRepo.On("FindActiveByPullRequestKey", 2, 4, "11").Return(&previewRepository.PreviewEnvironment{}, pg.ErrNoRows)
+		previewRepo.On("Save", mock.MatchedBy(func(preview *previewRepository.PreviewEnvironment) bool {
+			return preview.AppId == 2 && preview.GitMaterialId == 4 && preview.PullRequestId == "11"
+		})).Return(uniqueViolation{index: previewEnvironmentPullRequestUniqueIndex})
+
+		preview, err := service.getOrProvisionPreviewEnvironment(config, 4, pullRequest)
+		assert.Nil(tt, preview)
+		assert.ErrorIs(tt, err, errPreviewEnvironmentProvisioning)
+	})
+
+	t.Run("other unique violations are returned as is", func(tt *testing.T) {
+		service, previewRepo, _ := initPreviewEnvironmentService(tt)
+		saveErr := uniqueViolation{index: "some_other_index"}
+		previewRepo.On("FindActiveByPullRequestKey", 2, 4, "11").Return(&previewRepository.PreviewEnvironment{}, pg.ErrNoRows)
+		previewRepo.On("Save", mock.AnythingOfType("*repository.PreviewEnvironment")).Return(saveErr)
+
+		_, err := service.getOrProvisionPreviewEnvironment(config, 4, pullRequest)
+		assert.Equal(tt, saveErr, err)
+	})
+
+	t.Run("failed preview claimed by another build is not provisioned again", func(tt *testing.T) {
+		service, previewRepo, _ := initPreviewEnvironmentService(tt)
+		existing := &previewRepository.PreviewEnvironment{Id: 5, AppId: 2, GitMaterialId: 4, Status: bean.PreviewEnvironmentStatusFailed}
+		previewRepo.On("FindActiveByPullRequestKey", 2, 4, "11").Return(existing, nil)
+		previewRepo.On("MarkProvisioning", 5, mock.AnythingOfType("int32")).Return(false, nil)
+
+		preview, err := service.getOrProvisionPreviewEnvironment(config, 4, pullRequest)
+		assert.Nil(tt, preview)
+		assert.ErrorIs(tt, err, errPreviewEnvironmentProvisioning)
+	})
+
+	t.Run("failure in provisioning marks the preview failed", func(tt *testing.T) {
+		service, previewRepo, pipelineRepo := initPreviewEnvironmentService(tt)
+		existing := &previewRepository.PreviewEnvironment{Id: 5, AppId: 2, GitMaterialId: 4, Status: bean.PreviewEnvironmentStatusFailed}
+		provisionErr := errors.New("template pipeline not found")
+		previewRepo.On("FindActiveByPullRequestKey", 2, 4, "11").Return(existing, nil)
+		previewRepo.On("MarkProvisioning", 5, mock.AnythingOfType("int32")).Return(true, nil)
+		pipelineRepo.On("FindById", 3).Return(nil, provisionErr)
+		previewRepo.On("Update", existing).Return(nil)
+
+		preview, err := service.getOrProvisionPreviewEnvironment(config, 4, pullRequest)
+		assert.Nil(tt, preview)
+		assert.Equal(tt, provisionErr, err)
+		assert.Equal(tt, bean.PreviewEnvironmentStatusFailed, existing.Status)
+		assert.Equal(tt, provisionErr.Error(), existing.Message)
+	})
+}
+
+func TestTearDown(t *testing.T) {
+	t.Run("preview whose cd pipeline is already deleted is marked deleted", func(tt *testing.T) {
+		service, previewRepo, pipelineRepo := initPreviewEnvironmentService(tt)
+		preview := &previewRepository.PreviewEnvironment{Id: 5, CdPipelineId: 6, Status: bean.PreviewEnvironmentStatusTriggered}
+		var statuses []string
+		previewRepo.On("Update", preview).Run(func(args mock.Arguments) {
+			statuses = append(statuses, args.Get(0).(*previewRepository.PreviewEnvironment).Status)
+		}).Return(nil)
+		pipelineRepo.On("FindById", 6).Return(&pipelineConfig.Pipeline{}, pg.ErrNoRows)
+
+		service.tearDown(preview, 1)
+		assert.Equal(tt, []string{bean.PreviewEnvironmentStatusTearingDown, bean.PreviewEnvironmentStatusDeleted}, statuses)
+		assert.Equal(tt, 0, preview.CdPipelineId)
+		assert.Empty(tt, preview.Message)
+	})
+
+	t.Run("failure in deleting resources leaves the preview failed", func(tt *testing.T) {
+		service, previewRepo, pipelineRepo := initPreviewEnvironmentService(tt)
+		preview := &previewRepository.PreviewEnvironment{Id: 5, CdPipelineId: 6, Status: bean.PreviewEnvironmentStatusTriggered}
+		fetchErr := errors.New("connection refused")
+		previewRepo.On("Update", preview).Return(nil)
+		pipelineRepo.On("FindById", 6).Return(nil, fetchErr)
+
+		service.tearDown(preview, 1)
+		assert.Equal(tt, bean.PreviewEnvironmentStatusFailed, preview.Status)
+		assert.Equal(tt, fetchErr.Error(), preview.Message)
+		assert.Equal(tt, 6, preview.CdPipelineId)
+	})
+}
+
+func initPreviewEnvironmentService(t *testing.T) (*PreviewEnvironmentServiceImpl, *mocks.PreviewEnvironmentRepository, *pipelineMocks.PipelineRepository) {
+	logger, err := util.NewSugardLogger()
+	if err != nil {
+		assert.Fail(t, "error in creating logger", "err", err)
+	}
+	previewRepo := mocks.NewPreviewEnvironmentRepository(t)
+	pipelineRepo := pipelineMocks.NewPipelineRepository(t)
+	service := &PreviewEnvironmentServiceImpl{
+		logger:                       logger,
+		serviceConfig:                &bean.PreviewEnvironmentServiceConfig{},
+		previewEnvironmentRepository: previewRepo,
+		pipelineRepository:           pipelineRepo,
+	}
+	return service, previewRepo, pipelineRepo
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+const (
+	PreviewEnvironmentStatusProvisioning = "Provisioning"
+	PreviewEnvironmentStatusTriggered    = "DeploymentTriggered"
+	PreviewEnvironmentStatusFailed       = "Failed"
+	PreviewEnvironmentStatusTearingDown  = "TearingDown"
+	PreviewEnvironmentStatusDeleted      = "Deleted"
+)
+
+const (
+	PreviewEnvironmentNameMaxLength = 50
+	PreviewEnvironmentDescription   = "preview environment for pull request"
+)
+
+type PreviewEnvironmentServiceConfig struct {
+	CleanUpCronSchedule string `env:"PREVIEW_ENV_CLEANUP_CRON_SCHEDULE" envDefault:"*/30 * * * *" description:"Schedule of the job deleting preview environments of pull requests inactive beyond their ttl"`
+	DefaultTtlHours     int    `env:"PREVIEW_ENV_DEFAULT_TTL_HOURS" envDefault:"72" description:"Ttl of preview environments when not set on the preview environment config"`
+	TearDownRetryMins   int    `env:"PREVIEW_ENV_TEARDOWN_RETRY_MINS" envDefault:"60" description:"Minutes after which a preview environment still tearing down is torn down again by the clean up job"`
+}
+
+type PreviewEnvironmentConfigRequest struct {
+	Id                   int   `json:"id"`
+	AppId                int   `json:"appId" validate:"required"`
+	TemplateCdPipelineId int   `json:"templateCdPipelineId" validate:"required"`
+	TtlHours             int   `json:"ttlHours" validate:"min=0"`
+	UserId               int32 `json:"-"`
+}
+
+type PreviewEnvironmentConfigDto struct {
+	Id                      int    `json:"id"`
+	AppId                   int    `json:"appId"`
+	CiPipelineId            int    `json:"ciPipelineId"`
+	TemplateCdPipelineId    int    `json:"templateCdPipelineId"`
+	TemplateEnvironmentId   int    `json:"templateEnvironmentId"`
+	TemplateEnvironmentName string `json:"templateEnvironmentName"`
+	TtlHours                int    `json:"ttlHours"`
+}
+
+type PreviewEnvironmentDto struct {
+	Id                         int       `json:"id"`
+	PreviewEnvironmentConfigId int       `json:"previewEnvironmentConfigId"`
+	AppId                      int       `json:"appId"`
+	GitMaterialId              int       `json:"gitMaterialId"`
+	PullRequestId              string    `json:"pullRequestId"`
+	SourceBranch               string    `json:"sourceBranch"`
+	PullRequestUrl             string    `json:"pullRequestUrl,omitempty"`
+	EnvironmentId              int       `json:"environmentId,omitempty"`
+	EnvironmentName            string    `json:"environmentName,omitempty"`
+	Namespace                  string    `json:"namespace,omitempty"`
+	CdPipelineId               int       `json:"cdPipelineId,omitempty"`
+	CiArtifactId               int       `json:"ciArtifactId,omitempty"`
+	Status                     string    `json:"status"`
+	Message                    string    `json:"message,omitempty"`
+	LastActivityOn             time.Time `json:"lastActivityOn"`
+	ExpiresOn                  time.Time `json:"expiresOn"`
+}
+
+// PullRequestInfo identifies a pull request across the webhook data of a build and the raw events of the git host,
+// pull request ids are unique only within the repo
+type PullRequestInfo struct {
+	Id           string
+	SourceBranch string
+	Url          string
+	RepoUrl      string
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package previewEnvironment
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	bean2 "github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/previewEnvironment/bean"
+	previewRepository "github.com/devtron-labs/devtron/pkg/previewEnvironment/repository"
+	"github.com/go-pg/pg"
+	"github.com/tidwall/gjson"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var invalidNameCharsRegex = regexp.MustCompile(`[^a-z0-9-]+`)
+
+const previewEnvironmentPullRequestUniqueIndex = "preview_environment_pull_request_unique"
+
+// getPullRequestInfoFromMaterialInfo returns the pull request an artifact was built from,
+// nil is returned for builds of branches and tags
+func getPullRequestInfoFromMaterialInfo(materialInfoJson string) (*bean.PullRequestInfo, error) {
+	var ciMaterialInfos []repository.CiMaterialInfo
+	if len(materialInfoJson) == 0 {
+		return nil, nil
+	}
+	err := json.Unmarshal([]byte(materialInfoJson), &ciMaterialInfos)
+	if err != nil {
+		return nil, err
+	}
+	for _, ciMaterialInfo := range ciMaterialInfos {
+		for _, modification := range ciMaterialInfo.Modifications {
+			webhookData := modification.WebhookData
+			if webhookData.EventActionType != bean2.WEBHOOK_EVENT_NON_MERGED_ACTION_TYPE {
+				continue
+			}
+			pullRequestId := webhookData.Data[bean2.WEBHOOK_SELECTOR_UNIQUE_ID_NAME]
+			if len(pullRequestId) == 0 {
+				continue
+			}
+			return &bean.PullRequestInfo{
+				Id:           pullRequestId,
+				SourceBranch: webhookData.Data[bean2.WEBHOOK_SELECTOR_SOURCE_BRANCH_NAME_NAME],
+				Url:          webhookData.Data[bean2.WEBHOOK_SELECTOR_GIT_URL_NAME],
+				RepoUrl:      ciMaterialInfo.Material.GitConfiguration.URL,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// getClosedPullRequestFromPayload detects closed or merged pull requests in github, gitlab and bitbucket cloud events.
+// pull request ids are read from the same fields git-sensor uses for the unique id selector
+func getClosedPullRequestFromPayload(payload string) (*bean.PullRequestInfo, bool) {
+	if !gjson.Valid(payload) {
+		return nil, false
+	}
+	parsed := gjson.Parse(payload)
+	// github
+	if pullRequest := parsed.Get("pull_request"); pullRequest.Exists() && parsed.Get("action").String() == "closed" {
+		return &bean.PullRequestInfo{
+			Id:           pullRequest.Get("id").String(),
+			SourceBranch: pullRequest.Get("head.ref").String(),
+			Url:          pullRequest.Get("html_url").String(),
+			RepoUrl:      parsed.Get("repository.html_url").String(),
+		}, true
+	}
+	// gitlab
+	if parsed.Get("object_kind").String() == "merge_request" {
+		attributes := parsed.Get("object_attributes")
+		action := attributes.Get("action").String()
+		if action == "close" || action == "merge" {
+			return &bean.PullRequestInfo{
+				Id:           attributes.Get("id").String(),
+				SourceBranch: attributes.Get("source_branch").String(),
+				Url:          attributes.Get("url").String(),
+				RepoUrl:      parsed.Get("project.web_url").String(),
+			}, true
+		}
+		return nil, false
+	}
+	// bitbucket cloud
+	if pullRequest := parsed.Get("pullrequest"); pullRequest.Exists() {
+		state := pullRequest.Get("state").String()
+		if state == "MERGED" || state == "DECLINED" || state == "SUPERSEDED" {
+			return &bean.PullRequestInfo{
+				Id:           pullRequest.Get("id").String(),
+				SourceBranch: pullRequest.Get("source.branch.name").String(),
+				Url:          pullRequest.Get("links.html.href").String(),
+				RepoUrl:      parsed.Get("repository.links.html.href").String(),
+			}, true
+		}
+	}
+	return nil, false
+}
+
+// getRepoKey reduces https and ssh urls of a repo to host and path, so that the url of a git material
+// can be matched with the repo url sent by the git host
+func getRepoKey(repoUrl string) string {
+	key := strings.ToLower(strings.TrimSpace(repoUrl))
+	if i := strings.Index(key, "://"); i >= 0 {
+		key = key[i+len("://"):]
+	}
+	host, path, found := strings.Cut(key, "/")
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	// scp like ssh urls separate the path from the host with a colon
+	host, scpPath, isScpLike := strings.Cut(host, ":")
+	if isScpLike && !isPort(scpPath) {
+		path, found = scpPath+"/"+path, true
+	}
+	if !found {
+		return host
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	return host + "/" + path
+}
+
+func isPort(value string) bool {
+	if len(value) == 0 {
+		return true
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// getPreviewEnvironmentsOfRepo filters out previews of pull requests raised in other repos with the same id,
+// nothing is returned if the repo is unknown
+func getPreviewEnvironmentsOfRepo(previews []*previewRepository.PreviewEnvironment, repoUrl string) []*previewRepository.PreviewEnvironment {
+	filtered := make([]*previewRepository.PreviewEnvironment, 0, len(previews))
+	if len(repoUrl) == 0 {
+		return filtered
+	}
+	repoKey := getRepoKey(repoUrl)
+	for _, preview := range previews {
+		if preview.GitMaterial != nil && getRepoKey(preview.GitMaterial.Url) == repoKey {
+			filtered = append(filtered, preview)
+		}
+	}
+	return filtered
+}
+
+// isPullRequestPreviewConflict tells if saving a preview failed as the pull request already has one
+func isPullRequestPreviewConflict(err error) bool {
+	pgErr, ok := err.(pg.Error)
+	return ok && pgErr.IntegrityViolation() && pgErr.Field('n') == previewEnvironmentPullRequestUniqueIndex
+}
+
+// getPreviewEnvironmentName builds a name usable both as environment name and namespace, the git material keeps
+// the names of pull requests with the same id in different repos of an app apart. The app name is truncated so
+// that the suffix is always kept
+func getPreviewEnvironmentName(appName string, gitMaterialId int, pullRequestId string) string {
+	suffix := fmt.Sprintf("-%d-pr-%s", gitMaterialId, sanitizeName(pullRequestId))
+	prefix := sanitizeName(appName)
+	if maxPrefixLength := bean.PreviewEnvironmentNameMaxLength - len(suffix); len(prefix) > maxPrefixLength {
+		prefix = strings.TrimRight(prefix[:max(maxPrefixLength, 0)], "-")
+	}
+	name := prefix + suffix
+	if len(name) > bean.PreviewEnvironmentNameMaxLength {
+		name = strings.Trim(name[len(name)-bean.PreviewEnvironmentNameMaxLength:], "-")
+	}
+	return name
+}
+
+func sanitizeName(name string) string {
+	return strings.Trim(invalidNameCharsRegex.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+func getExpiresOn(lastActivityOn time.Time, ttlHours int) time.Time {
+	return lastActivityOn.Add(time.Duration(ttlHours) * time.Hour)
+}
+
+// getEnvValuesFileName is the name of the file holding merged values of an environment in the gitops repo of an app
+func getEnvValuesFileName(environmentId int) string {
+	return fmt.Sprintf("_%d-values.yaml", environmentId)
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package previewEnvironment
+
+import (
+	"github.com/devtron-labs/devtron/pkg/build/git/gitMaterial/repository"
+	"github.com/devtron-labs/devtron/pkg/previewEnvironment/bean"
+	previewRepository "github.com/devtron-labs/devtron/pkg/previewEnvironment/repository"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestGetPullRequestInfoFromMaterialInfo(t *testing.T) {
+	tests := []struct {
+		name         string
+		materialInfo string
+		want         *bean.PullRequestInfo
+	}{
+		{name: "empty", materialInfo: "", want: nil},
+		{
+			name:         "branch build",
+			materialInfo: `[{"material":{},"changed":true,"modifications":[{"revision":"abc","branch":"main"}]}]`,
+			want:         nil,
+		},
+		{
+			name:         "merged event",
+			materialInfo: `[{"modifications":[{"webhookData":{"Id":1,"EventActionType":"merged","Data":{"unique id":"11"}}}]}]`,
+			want:         nil,
+		},
+		{
+			name:         "pull request build",
+			materialInfo: `[{"material":{"git-configuration":{"url":"git@github.com:org/repo.git"}},"modifications":[{"webhookData":{"Id":1,"EventActionType":"non-merged","Data":{"unique id":"11","source branch name":"feature/a","git url":"https://github.com/org/repo/pull/4"}}}]}]`,
+			want:         &bean.PullRequestInfo{Id: "11", SourceBranch: "feature/a", Url: "https://github.com/org/repo/pull/4", RepoUrl: "git@github.com:org/repo.git"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getPullRequestInfoFromMaterialInfo(tt.materialInfo)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetClosedPullRequestFromPayload(t *testing.T) {
+	tests := []struct {
+		name       string
+		payload    string
+		wantClosed bool
+		want       *bean.PullRequestInfo
+	}{
+		{name: "invalid json", payload: "{", wantClosed: false},
+		{name: "github opened", payload: `{"action":"opened","pull_request":{"id":11,"head":{"ref":"a"}}}`, wantClosed: false},
+		{
+			name:       "github closed",
+			payload:    `{"action":"closed","pull_request":{"id":11,"html_url":"u","head":{"ref":"a"}},"repository":{"html_url":"r"}}`,
+			wantClosed: true,
+			want:       &bean.PullRequestInfo{Id: "11", SourceBranch: "a", Url: "u", RepoUrl: "r"},
+		},
+		{name: "gitlab update", payload: `{"object_kind":"merge_request","object_attributes":{"id":7,"action":"update"}}`, wantClosed: false},
+		{
+			name:       "gitlab merged",
+			payload:    `{"object_kind":"merge_request","object_attributes":{"id":7,"action":"merge","source_branch":"b","url":"u"},"project":{"web_url":"r"}}`,
+			wantClosed: true,
+			want:       &bean.PullRequestInfo{Id: "7", SourceBranch: "b", Url: "u", RepoUrl: "r"},
+		},
+		{
+			name:       "bitbucket declined",
+			payload:    `{"pullrequest":{"id":3,"state":"DECLINED","source":{"branch":{"name":"c"}},"links":{"html":{"href":"u"}}},"repository":{"links":{"html":{"href":"r"}}}}`,
+			wantClosed: true,
+			want:       &bean.PullRequestInfo{Id: "3", SourceBranch: "c", Url: "u", RepoUrl: "r"},
+		},
+		{name: "push event", payload: `{"ref":"refs/heads/main"}`, wantClosed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, closed := getClosedPullRequestFromPayload(tt.payload)
+			assert.Equal(t, tt.wantClosed, closed)
+			if tt.wantClosed {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetRepoKey(t *testing.T) {
+	tests := []struct {
+		repoUrl string
+		want    string
+	}{
+		{repoUrl: "https://github.com/Org/Repo", want: "github.com/org/repo"},
+		{repoUrl: "https://github.com/org/repo.git", want: "github.com/org/repo"},
+		{repoUrl: "https://github.com/org/repo/", want: "github.com/org/repo"},
+		{repoUrl: "git@github.com:org/repo.git", want: "github.com/org/repo"},
+		{repoUrl: "ssh://git@gitlab.example.com:2222/group/sub/repo.git", want: "gitlab.example.com/group/sub/repo"},
+		{repoUrl: "https://user@bitbucket.org/org/repo.git", want: "bitbucket.org/org/repo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repoUrl, func(t *testing.T) {
+			assert.Equal(t, tt.want, getRepoKey(tt.repoUrl))
+		})
+	}
+}
+
+func TestGetPreviewEnvironmentsOfRepo(t *testing.T) {
+	sameRepo := &previewRepository.PreviewEnvironment{Id: 1, GitMaterial: &repository.GitMaterial{Url: "git@github.com:org/repo.git"}}
+	otherRepo := &previewRepository.PreviewEnvironment{Id: 2, GitMaterial: &repository.GitMaterial{Url: "https://github.com/org/other.git"}}
+	previews := []*previewRepository.PreviewEnvironment{sameRepo, otherRepo}
+
+	assert.Equal(t, []*previewRepository.PreviewEnvironment{sameRepo}, getPreviewEnvironmentsOfRepo(previews, "https://github.com/org/repo"))
+	assert.Empty(t, getPreviewEnvironmentsOfRepo(previews, "https://github.com/another-org/repo"))
+	assert.Empty(t, getPreviewEnvironmentsOfRepo(previews, ""))
+}
+
+func TestGetPreviewEnvironmentName(t *testing.T) {
+	assert.Equal(t, "my-app-3-pr-1234", getPreviewEnvironmentName("my-app", 3, "1234"))
+	assert.Equal(t, "my-app-3-pr-feature-x", getPreviewEnvironmentName("My_App", 3, "Feature/X"))
+	assert.NotEqual(t, getPreviewEnvironmentName("my-app", 3, "1234"), getPreviewEnvironmentName("my-app", 4, "1234"))
+	name := getPreviewEnvironmentName(strings.Repeat("a", 60), 3, "987654321")
+	assert.Len(t, name, bean.PreviewEnvironmentNameMaxLength)
+	assert.True(t, strings.HasSuffix(name, "-3-pr-987654321"))
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type PreviewEnvironmentConfig struct {
+	tableName            struct{} `sql:"preview_environment_config" pg:",discard_unknown_columns"`
+	Id                   int      `sql:"id,pk"`
+	AppId                int      `sql:"app_id,notnull"`
+	CiPipelineId         int      `sql:"ci_pipeline_id,notnull"`
+	TemplateCdPipelineId int      `sql:"template_cd_pipeline_id,notnull"`
+	TtlHours             int      `sql:"ttl_hours,notnull"`
+	Active               bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type PreviewEnvironmentConfigRepository interface {
+	Save(model *PreviewEnvironmentConfig) error
+	Update(model *PreviewEnvironmentConfig) error
+	FindById(id int) (*PreviewEnvironmentConfig, error)
+	FindActiveByAppId(appId int) ([]*PreviewEnvironmentConfig, error)
+	FindActiveByCiPipelineId(ciPipelineId int) (*PreviewEnvironmentConfig, error)
+	FindActiveByIds(ids []int) ([]*PreviewEnvironmentConfig, error)
+}
+
+type PreviewEnvironmentConfigRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewPreviewEnvironmentConfigRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *PreviewEnvironmentConfigRepositoryImpl {
+	return &PreviewEnvironmentConfigRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *PreviewEnvironmentConfigRepositoryImpl) Save(model *PreviewEnvironmentConfig) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *PreviewEnvironmentConfigRepositoryImpl) Update(model *PreviewEnvironmentConfig) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *PreviewEnvironmentConfigRepositoryImpl) FindById(id int) (*PreviewEnvironmentConfig, error) {
+	model := &PreviewEnvironmentConfig{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *PreviewEnvironmentConfigRepositoryImpl) FindActiveByAppId(appId int) ([]*PreviewEnvironmentConfig, error) {
+	var models []*PreviewEnvironmentConfig
+	err := impl.dbConnection.Model(&models).
+		Where("app_id = ?", appId).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return models, err
+}
+
+func (impl *PreviewEnvironmentConfigRepositoryImpl) FindActiveByCiPipelineId(ciPipelineId int) (*PreviewEnvironmentConfig, error) {
+	model := &PreviewEnvironmentConfig{}
+	err := impl.dbConnection.Model(model).
+		Where("ci_pipeline_id = ?", ciPipelineId).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *PreviewEnvironmentConfigRepositoryImpl) FindActiveByIds(ids []int) ([]*PreviewEnvironmentConfig, error) {
+	var models []*PreviewEnvironmentConfig
+	if len(ids) == 0 {
+		return models, nil
+	}
+	err := impl.dbConnection.Model(&models).
+		Where("id IN (?)", pg.In(ids)).
+		Where("active = ?", true).
+		Select()
+	return models, err
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	gitMaterialRepository "github.com/devtron-labs/devtron/pkg/build/git/gitMaterial/repository"
+	"github.com/devtron-labs/devtron/pkg/previewEnvironment/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+	"time"
+)
+
+type PreviewEnvironment struct {
+	tableName                  struct{}  `sql:"preview_environment" pg:",discard_unknown_columns"`
+	Id                         int       `sql:"id,pk"`
+	PreviewEnvironmentConfigId int       `sql:"preview_environment_config_id,notnull"`
+	AppId                      int       `sql:"app_id,notnull"`
+	GitMaterialId              int       `sql:"git_material_id,notnull"`
+	PullRequestId              string    `sql:"pull_request_id,notnull"`
+	SourceBranch               string    `sql:"source_branch"`
+	PullRequestUrl             string    `sql:"pull_request_url"`
+	EnvironmentId              int       `sql:"environment_id"`
+	CdPipelineId               int       `sql:"cd_pipeline_id"`
+	CiArtifactId               int       `sql:"ci_artifact_id"`
+	Status                     string    `sql:"status,notnull"`
+	Message                    string    `sql:"message"`
+	LastActivityOn             time.Time `sql:"last_activity_on,notnull"`
+	GitMaterial                *gitMaterialRepository.GitMaterial
+	sql.AuditLog
+}
+
+type PreviewEnvironmentRepository interface {
+	Save(model *PreviewEnvironment) error
+	Update(model *PreviewEnvironment) error
+	// MarkProvisioning moves a failed preview back to provisioning, false is returned if it is no longer failed
+	MarkProvisioning(id int, userId int32) (bool, error)
+	FindById(id int) (*PreviewEnvironment, error)
+	FindActiveByPullRequestKey(appId, gitMaterialId int, pullRequestId string) (*PreviewEnvironment, error)
+	// FindActiveByPullRequest returns previews of the pull request in any repo along with their git material
+	FindActiveByPullRequest(pullRequestId, sourceBranch string) ([]*PreviewEnvironment, error)
+	FindActiveByAppId(appId int) ([]*PreviewEnvironment, error)
+	FindActiveByConfigId(configId int) ([]*PreviewEnvironment, error)
+	// FindExpired returns previews without any activity within the ttl of their config, along with the previews
+	// still tearing down since before tearingDownBefore whose tear down was interrupted
+	FindExpired(now time.Time, tearingDownBefore time.Time) ([]*PreviewEnvironment, error)
+}
+
+type PreviewEnvironmentRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewPreviewEnvironmentRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *PreviewEnvironmentRepositoryImpl {
+	return &PreviewEnvironmentRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) Save(model *PreviewEnvironment) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) Update(model *PreviewEnvironment) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) MarkProvisioning(id int, userId int32) (bool, error) {
+	res, err := impl.dbConnection.Model(&PreviewEnvironment{}).
+		Set("status = ?", bean.PreviewEnvironmentStatusProvisioning).
+		Set("message = ?", "").
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("id = ?", id).
+		Where("status = ?", bean.PreviewEnvironmentStatusFailed).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() == 1, nil
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) FindById(id int) (*PreviewEnvironment, error) {
+	model := &PreviewEnvironment{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Select()
+	return model, err
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) FindActiveByPullRequestKey(appId, gitMaterialId int, pullRequestId string) (*PreviewEnvironment, error) {
+	model := &PreviewEnvironment{}
+	err := impl.dbConnection.Model(model).
+		Where("app_id = ?", appId).
+		Where("git_material_id = ?", gitMaterialId).
+		Where("pull_request_id = ?", pullRequestId).
+		Where("status != ?", bean.PreviewEnvironmentStatusDeleted).
+		Select()
+	return model, err
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) FindActiveByPullRequest(pullRequestId, sourceBranch string) ([]*PreviewEnvironment, error) {
+	var models []*PreviewEnvironment
+	err := impl.dbConnection.Model(&models).
+		Column("preview_environment.*", "GitMaterial").
+		Where("preview_environment.pull_request_id = ?", pullRequestId).
+		Where("preview_environment.source_branch = ?", sourceBranch).
+		Where("preview_environment.status != ?", bean.PreviewEnvironmentStatusDeleted).
+		Select()
+	return models, err
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) FindActiveByAppId(appId int) ([]*PreviewEnvironment, error) {
+	var models []*PreviewEnvironment
+	err := impl.dbConnection.Model(&models).
+		Where("app_id = ?", appId).
+		Where("status != ?", bean.PreviewEnvironmentStatusDeleted).
+		Order("id DESC").
+		Select()
+	return models, err
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) FindActiveByConfigId(configId int) ([]*PreviewEnvironment, error) {
+	var models []*PreviewEnvironment
+	err := impl.dbConnection.Model(&models).
+		Where("preview_environment_config_id = ?", configId).
+		Where("status != ?", bean.PreviewEnvironmentStatusDeleted).
+		Select()
+	return models, err
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) FindExpired(now time.Time, tearingDownBefore time.Time) ([]*PreviewEnvironment, error) {
+	var models []*PreviewEnvironment
+	err := impl.dbConnection.Model(&models).
+		Join("INNER JOIN preview_environment_config pec ON pec.id = preview_environment.preview_environment_config_id").
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+				q = q.Where("preview_environment.status NOT IN (?)", pg.In([]string{bean.PreviewEnvironmentStatusDeleted, bean.PreviewEnvironmentStatusTearingDown})).
+					Where("preview_environment.last_activity_on + pec.ttl_hours * INTERVAL '1 hour' < ?", now)
+				return q, nil
+			})
+			q = q.WhereOrGroup(func(q *orm.Query) (*orm.Query, error) {
+				q = q.Where("preview_environment.status = ?", bean.PreviewEnvironmentStatusTearingDown).
+					Where("preview_environment.updated_on < ?", tearingDownBefore)
+				return q, nil
+			})
+			return q, nil
+		}).
+		Select()
+	return models, err
+}
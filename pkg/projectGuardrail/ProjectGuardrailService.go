@@ -0,0 +1,461 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package projectGuardrail
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	envRepository "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail/bean"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	teamRepository "github.com/devtron-labs/devtron/pkg/team/repository"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type ProjectGuardrailService interface {
+	// GetAllUsage returns the usage of every active project, with its guardrail if one is configured
+	GetAllUsage() ([]*bean.ProjectGuardrailUsage, error)
+	GetUsage(teamId int) (*bean.ProjectGuardrailUsage, error)
+	SaveGuardrail(request *bean.ProjectGuardrailDto) (*bean.ProjectGuardrailDto, error)
+	DeleteGuardrail(teamId int, userId int32) error
+
+	// ValidateAppCreate checks the app limit and the mandatory labels of the project a new app is created in
+	ValidateAppCreate(teamId int, labelKeys []string) error
+	// ValidateCdPipelineCreate checks the environments of new cd pipelines along with the chart ref and
+	// container registry of the app which will be deployed on them
+	ValidateCdPipelineCreate(appId int, envIds []int) error
+	// ValidateAppClone checks everything copied from the template app before the clone starts, so that
+	// a violation does not leave a partially cloned app behind
+	ValidateAppClone(templateAppId int, teamId int) error
+	// ValidateAppImport checks everything an app snapshot import creates or changes before the import starts,
+	// appId is 0 when the import creates the app. envIds are the environments of the new cd pipelines
+	ValidateAppImport(appId int, teamId int, labelKeys []string, envIds []int, chartRefIds []int, dockerRegistryIds []string) error
+	// ValidateChartRefUpdate checks the chart ref an existing app switches its deployment template to
+	ValidateChartRefUpdate(appId int, chartRefId int) error
+	// ValidateDockerRegistryUpdate checks the container registry an existing app switches its ci template to
+	ValidateDockerRegistryUpdate(appId int, dockerRegistryId string) error
+	// AllowClusters adds the clusters to the allow list of the projects, projects without a guardrail or without
+	// a cluster and environment allow list can deploy anywhere already and are left unchanged
+	AllowClusters(teamIds []int, clusterIds []int, userId int32) error
+	// AssignEnvironments adds the environments to the environment allow list of the projects, projects without a
+	// guardrail or without a cluster and environment allow list can deploy anywhere already and are left unchanged
+	AssignEnvironments(teamIds []int, envIds []int, userId int32) error
+}
+
+type ProjectGuardrailServiceImpl struct {
+	logger                     *zap.SugaredLogger
+	projectGuardrailRepository repository.ProjectGuardrailRepository
+	teamRepository             teamRepository.TeamRepository
+	appRepository              app.AppRepository
+	environmentRepository      envRepository.EnvironmentRepository
+	pipelineRepository         pipelineConfig.PipelineRepository
+	chartRepository            chartRepoRepository.ChartRepository
+	ciTemplateRepository       pipelineConfig.CiTemplateRepository
+}
+
+func NewProjectGuardrailServiceImpl(logger *zap.SugaredLogger,
+	projectGuardrailRepository repository.ProjectGuardrailRepository,
+	teamRepository teamRepository.TeamRepository,
+	appRepository app.AppRepository,
+	environmentRepository envRepository.EnvironmentRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	chartRepository chartRepoRepository.ChartRepository,
+	ciTemplateRepository pipelineConfig.CiTemplateRepository) *ProjectGuardrailServiceImpl {
+	return &ProjectGuardrailServiceImpl{
+		logger:                     logger,
+		projectGuardrailRepository: projectGuardrailRepository,
+		teamRepository:             teamRepository,
+		appRepository:              appRepository,
+		environmentRepository:      environmentRepository,
+		pipelineRepository:         pipelineRepository,
+		chartRepository:            chartRepository,
+		ciTemplateRepository:       ciTemplateRepository,
+	}
+}
+
+func (impl *ProjectGuardrailServiceImpl) GetAllUsage() ([]*bean.ProjectGuardrailUsage, error) {
+	teams, err := impl.teamRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in fetching teams", "err", err)
+		return nil, err
+	}
+	guardrails, err := impl.projectGuardrailRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in fetching project guardrails", "err", err)
+		return nil, err
+	}
+	guardrailByTeamId := make(map[int]*repository.ProjectGuardrail, len(guardrails))
+	for _, guardrail := range guardrails {
+		guardrailByTeamId[guardrail.TeamId] = guardrail
+	}
+	usages := make([]*bean.ProjectGuardrailUsage, 0, len(teams))
+	for _, team := range teams {
+		usage, err := impl.getUsage(team.Id, team.Name, guardrailByTeamId[team.Id])
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
+
+func (impl *ProjectGuardrailServiceImpl) GetUsage(teamId int) (*bean.ProjectGuardrailUsage, error) {
+	team, err := impl.teamRepository.FindOne(teamId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching team", "teamId", teamId, "err", err)
+		return nil, err
+	}
+	guardrail, err := impl.getGuardrail(teamId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.getUsage(team.Id, team.Name, guardrail)
+}
+
+func (impl *ProjectGuardrailServiceImpl) getUsage(teamId int, teamName string, guardrail *repository.ProjectGuardrail) (*bean.ProjectGuardrailUsage, error) {
+	appCount, err := impl.projectGuardrailRepository.CountAppsByTeamId(teamId)
+	if err != nil {
+		impl.logger.Errorw("error in counting apps of team", "teamId", teamId, "err", err)
+		return nil, err
+	}
+	envIds, err := impl.projectGuardrailRepository.FindEnvironmentIdsByTeamId(teamId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environments of team", "teamId", teamId, "err", err)
+		return nil, err
+	}
+	usage := &bean.ProjectGuardrailUsage{
+		TeamId:           teamId,
+		TeamName:         teamName,
+		AppCount:         appCount,
+		EnvironmentCount: len(envIds),
+		EnvironmentIds:   envIds,
+	}
+	if guardrail != nil {
+		usage.Guardrail = toDto(guardrail)
+	}
+	return usage, nil
+}
+
+func (impl *ProjectGuardrailServiceImpl) SaveGuardrail(request *bean.ProjectGuardrailDto) (*bean.ProjectGuardrailDto, error) {
+	_, err := impl.teamRepository.FindOne(request.TeamId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching team", "teamId", request.TeamId, "err", err)
+		if util.IsErrNoRows(err) {
+			message := fmt.Sprintf("project %d does not exist", request.TeamId)
+			return nil, util.NewApiError(http.StatusNotFound, message, message)
+		}
+		return nil, err
+	}
+	existing, err := impl.getGuardrail(request.TeamId)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		model := toModel(request, &repository.ProjectGuardrail{})
+		model.AuditLog = sql.NewDefaultAuditLog(request.UserId)
+		err = impl.projectGuardrailRepository.Save(model)
+	} else {
+		model := toModel(request, existing)
+		model.UpdateAuditLog(request.UserId)
+		err = impl.projectGuardrailRepository.Update(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving project guardrail", "request", request, "err", err)
+		return nil, err
+	}
+	return request, nil
+}
+
+func (impl *ProjectGuardrailServiceImpl) DeleteGuardrail(teamId int, userId int32) error {
+	guardrail, err := impl.getGuardrail(teamId)
+	if err != nil || guardrail == nil {
+		return err
+	}
+	guardrail.Active = false
+	guardrail.UpdateAuditLog(userId)
+	err = impl.projectGuardrailRepository.Update(guardrail)
+	if err != nil {
+		impl.logger.Errorw("error in deleting project guardrail", "teamId", teamId, "err", err)
+	}
+	return err
+}
+
+func (impl *ProjectGuardrailServiceImpl) ValidateAppCreate(teamId int, labelKeys []string) error {
+	guardrail, err := impl.getGuardrail(teamId)
+	if err != nil || guardrail == nil {
+		return err
+	}
+	teamName, err := impl.getTeamName(teamId)
+	if err != nil {
+		return err
+	}
+	return impl.validateNewApp(guardrail, teamName, labelKeys)
+}
+
+// validateNewApp checks the mandatory labels and the app limit of the project an app is added to
+func (impl *ProjectGuardrailServiceImpl) validateNewApp(guardrail *repository.ProjectGuardrail, teamName string, labelKeys []string) error {
+	teamId := guardrail.TeamId
+	if missing := getMissingLabels(guardrail.MandatoryAppLabels, labelKeys); len(missing) > 0 {
+		return mandatoryLabelsMissingError(teamName, missing)
+	}
+	if guardrail.MaxApps > 0 {
+		appCount, err := impl.projectGuardrailRepository.CountAppsByTeamId(teamId)
+		if err != nil {
+			impl.logger.Errorw("error in counting apps of team", "teamId", teamId, "err", err)
+			return err
+		}
+		if appCount >= guardrail.MaxApps {
+			return maxAppsExceededError(teamName, guardrail.MaxApps)
+		}
+	}
+	return nil
+}
+
+func (impl *ProjectGuardrailServiceImpl) ValidateCdPipelineCreate(appId int, envIds []int) error {
+	guardrail, teamName, err := impl.getAppGuardrail(appId)
+	if err != nil || guardrail == nil {
+		return err
+	}
+	err = impl.validateEnvironments(guardrail, teamName, envIds)
+	if err != nil {
+		return err
+	}
+	return impl.validateAppConfig(guardrail, teamName, appId)
+}
+
+func (impl *ProjectGuardrailServiceImpl) ValidateAppClone(templateAppId int, teamId int) error {
+	guardrail, err := impl.getGuardrail(teamId)
+	if err != nil || guardrail == nil {
+		return err
+	}
+	teamName, err := impl.getTeamName(teamId)
+	if err != nil {
+		return err
+	}
+	err = impl.validateAppConfig(guardrail, teamName, templateAppId)
+	if err != nil {
+		return err
+	}
+	pipelines, err := impl.pipelineRepository.FindActiveByAppId(templateAppId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cd pipelines of template app", "appId", templateAppId, "err", err)
+		return err
+	}
+	envIds := make([]int, 0, len(pipelines))
+	for _, pipeline := range pipelines {
+		envIds = append(envIds, pipeline.EnvironmentId)
+	}
+	return impl.validateEnvironments(guardrail, teamName, envIds)
+}
+
+func (impl *ProjectGuardrailServiceImpl) ValidateAppImport(appId int, teamId int, labelKeys []string, envIds []int, chartRefIds []int, dockerRegistryIds []string) error {
+	guardrail, err := impl.getGuardrail(teamId)
+	if err != nil || guardrail == nil {
+		return err
+	}
+	teamName, err := impl.getTeamName(teamId)
+	if err != nil {
+		return err
+	}
+	isNewInTeam := appId == 0
+	if appId > 0 {
+		app, err := impl.appRepository.FindById(appId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching app", "appId", appId, "err", err)
+			return err
+		}
+		isNewInTeam = app.TeamId != teamId
+	}
+	if isNewInTeam {
+		err = impl.validateNewApp(guardrail, teamName, labelKeys)
+		if err != nil {
+			return err
+		}
+	}
+	for _, chartRefId := range chartRefIds {
+		if !isChartRefAllowed(guardrail, chartRefId) {
+			return chartRefNotAllowedError(teamName, chartRefId)
+		}
+	}
+	for _, dockerRegistryId := range dockerRegistryIds {
+		if !isDockerRegistryAllowed(guardrail, dockerRegistryId) {
+			return dockerRegistryNotAllowedError(teamName, dockerRegistryId)
+		}
+	}
+	return impl.validateEnvironments(guardrail, teamName, envIds)
+}
+
+func (impl *ProjectGuardrailServiceImpl) ValidateChartRefUpdate(appId int, chartRefId int) error {
+	guardrail, teamName, err := impl.getAppGuardrail(appId)
+	if err != nil || guardrail == nil {
+		return err
+	}
+	if !isChartRefAllowed(guardrail, chartRefId) {
+		return chartRefNotAllowedError(teamName, chartRefId)
+	}
+	return nil
+}
+
+func (impl *ProjectGuardrailServiceImpl) ValidateDockerRegistryUpdate(appId int, dockerRegistryId string) error {
+	guardrail, teamName, err := impl.getAppGuardrail(appId)
+	if err != nil || guardrail == nil {
+		return err
+	}
+	if !isDockerRegistryAllowed(guardrail, dockerRegistryId) {
+		return dockerRegistryNotAllowedError(teamName, dockerRegistryId)
+	}
+	return nil
+}
+
+// getAppGuardrail returns the guardrail of the project of an app and the project name, the guardrail is nil if the
+// project has none configured
+func (impl *ProjectGuardrailServiceImpl) getAppGuardrail(appId int) (*repository.ProjectGuardrail, string, error) {
+	app, err := impl.appRepository.FindById(appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching app", "appId", appId, "err", err)
+		return nil, "", err
+	}
+	guardrail, err := impl.getGuardrail(app.TeamId)
+	if err != nil || guardrail == nil {
+		return nil, "", err
+	}
+	teamName, err := impl.getTeamName(app.TeamId)
+	if err != nil {
+		return nil, "", err
+	}
+	return guardrail, teamName, nil
+}
+
+func (impl *ProjectGuardrailServiceImpl) validateEnvironments(guardrail *repository.ProjectGuardrail, teamName string, envIds []int) error {
+	if len(envIds) == 0 {
+		return nil
+	}
+	envIdPtrs := make([]*int, 0, len(envIds))
+	for i := range envIds {
+		envIdPtrs = append(envIdPtrs, &envIds[i])
+	}
+	envs, err := impl.environmentRepository.FindByIds(envIdPtrs)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environments", "envIds", envIds, "err", err)
+		return err
+	}
+	for _, env := range envs {
+		if !isEnvironmentAllowed(guardrail, env.Id, env.ClusterId) {
+			return environmentNotAllowedError(teamName, env.Name)
+		}
+	}
+	if guardrail.MaxEnvironments > 0 {
+		usedEnvIds, err := impl.projectGuardrailRepository.FindEnvironmentIdsByTeamId(guardrail.TeamId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environments of team", "teamId", guardrail.TeamId, "err", err)
+			return err
+		}
+		if len(usedEnvIds)+len(getNewEnvironmentIds(usedEnvIds, envIds)) > guardrail.MaxEnvironments {
+			return maxEnvironmentsExceededError(teamName, guardrail.MaxEnvironments)
+		}
+	}
+	return nil
+}
+
+// validateAppConfig checks the deployment chart and the container registry configured on an app
+func (impl *ProjectGuardrailServiceImpl) validateAppConfig(guardrail *repository.ProjectGuardrail, teamName string, appId int) error {
+	if len(guardrail.AllowedChartRefIds) > 0 {
+		chartRefId, err := impl.chartRepository.FindChartRefIdForLatestChartForAppByAppId(appId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching chart ref of app", "appId", appId, "err", err)
+			return err
+		}
+		if chartRefId > 0 && !isChartRefAllowed(guardrail, chartRefId) {
+			return chartRefNotAllowedError(teamName, chartRefId)
+		}
+	}
+	if len(guardrail.AllowedDockerRegistryIds) > 0 {
+		ciTemplate, err := impl.ciTemplateRepository.FindByAppId(appId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching ci template of app", "appId", appId, "err", err)
+			return err
+		}
+		if ciTemplate != nil && ciTemplate.DockerRegistryId != nil && !isDockerRegistryAllowed(guardrail, *ciTemplate.DockerRegistryId) {
+			return dockerRegistryNotAllowedError(teamName, *ciTemplate.DockerRegistryId)
+		}
+	}
+	return nil
+}
+
+func (impl *ProjectGuardrailServiceImpl) AllowClusters(teamIds []int, clusterIds []int, userId int32) error {
+	for _, teamId := range teamIds {
+		guardrail, err := impl.getGuardrail(teamId)
+		if err != nil {
+			return err
+		}
+		if guardrail == nil || !addAllowedClusters(guardrail, clusterIds) {
+			continue
+		}
+		guardrail.UpdateAuditLog(userId)
+		err = impl.projectGuardrailRepository.Update(guardrail)
+		if err != nil {
+			impl.logger.Errorw("error in allowing clusters in project guardrail", "teamId", teamId, "clusterIds", clusterIds, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *ProjectGuardrailServiceImpl) AssignEnvironments(teamIds []int, envIds []int, userId int32) error {
+	for _, teamId := range teamIds {
+		guardrail, err := impl.getGuardrail(teamId)
+		if err != nil {
+			return err
+		}
+		if guardrail == nil || !addAllowedEnvironments(guardrail, envIds) {
+			continue
+		}
+		guardrail.UpdateAuditLog(userId)
+		err = impl.projectGuardrailRepository.Update(guardrail)
+		if err != nil {
+			impl.logger.Errorw("error in assigning environments in project guardrail", "teamId", teamId, "envIds", envIds, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// getGuardrail returns nil if the project has no guardrail configured
+func (impl *ProjectGuardrailServiceImpl) getGuardrail(teamId int) (*repository.ProjectGuardrail, error) {
+	guardrail, err := impl.projectGuardrailRepository.FindByTeamId(teamId)
+	if util.IsErrNoRows(err) {
+		return nil, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching project guardrail", "teamId", teamId, "err", err)
+		return nil, err
+	}
+	return guardrail, nil
+}
+
+func (impl *ProjectGuardrailServiceImpl) getTeamName(teamId int) (string, error) {
+	team, err := impl.teamRepository.FindOne(teamId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching team", "teamId", teamId, "err", err)
+		return "", err
+	}
+	return team.Name, nil
+}
@@ -0,0 +1,257 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package projectGuardrail
+
+import (
+	"github.com/devtron-labs/devtron/internal/constants"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	appMocks "github.com/devtron-labs/devtron/internal/sql/repository/app/mocks"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	pipelineMocks "github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/mocks"
+	"github.com/devtron-labs/devtron/internal/util"
+	chartRepoMocks "github.com/devtron-labs/devtron/pkg/chartRepo/repository/mocks"
+	envRepository "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	envMocks "github.com/devtron-labs/devtron/pkg/cluster/repository/mocks"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail/repository"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail/repository/mocks"
+	teamRepository "github.com/devtron-labs/devtron/pkg/team/repository"
+	teamMocks "github.com/devtron-labs/devtron/pkg/team/repository/mocks"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"testing"
+)
+
+type projectGuardrailServiceMocks struct {
+	guardrailRepository   *mocks.ProjectGuardrailRepository
+	teamRepository        *teamMocks.TeamRepository
+	appRepository         *appMocks.AppRepository
+	environmentRepository *envMocks.EnvironmentRepository
+	pipelineRepository    *pipelineMocks.PipelineRepository
+	chartRepository       *chartRepoMocks.ChartRepository
+	ciTemplateRepository  *pipelineMocks.CiTemplateRepository
+}
+
+func initProjectGuardrailService(t *testing.T) (*ProjectGuardrailServiceImpl, *projectGuardrailServiceMocks) {
+	m := &projectGuardrailServiceMocks{
+		guardrailRepository:   mocks.NewProjectGuardrailRepository(t),
+		teamRepository:        teamMocks.NewTeamRepository(t),
+		appRepository:         appMocks.NewAppRepository(t),
+		environmentRepository: envMocks.NewEnvironmentRepository(t),
+		pipelineRepository:    pipelineMocks.NewPipelineRepository(t),
+		chartRepository:       chartRepoMocks.NewChartRepository(t),
+		ciTemplateRepository:  pipelineMocks.NewCiTemplateRepository(t),
+	}
+	service := NewProjectGuardrailServiceImpl(zap.NewNop().Sugar(), m.guardrailRepository, m.teamRepository, m.appRepository,
+		m.environmentRepository, m.pipelineRepository, m.chartRepository, m.ciTemplateRepository)
+	return service, m
+}
+
+func assertGuardrailErrorCode(t *testing.T, err error, code string) {
+	apiErr, ok := err.(*util.ApiError)
+	if assert.True(t, ok, "expected an api error, got %v", err) {
+		assert.Equal(t, code, apiErr.Code)
+	}
+}
+
+func TestValidateAppCreate(t *testing.T) {
+	team := teamRepository.Team{Id: 1, Name: "payments"}
+
+	t.Run("project without guardrail", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(nil, pg.ErrNoRows)
+		assert.Nil(tt, service.ValidateAppCreate(1, nil))
+	})
+
+	t.Run("mandatory label missing", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{TeamId: 1, MandatoryAppLabels: []string{"owner", "tier"}}, nil)
+		m.teamRepository.On("FindOne", 1).Return(team, nil)
+		err := service.ValidateAppCreate(1, []string{"owner"})
+		assertGuardrailErrorCode(tt, err, constants.ProjectGuardrailMandatoryLabelsMissing)
+	})
+
+	t.Run("app limit reached", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{TeamId: 1, MaxApps: 3}, nil)
+		m.teamRepository.On("FindOne", 1).Return(team, nil)
+		m.guardrailRepository.On("CountAppsByTeamId", 1).Return(3, nil)
+		err := service.ValidateAppCreate(1, nil)
+		assertGuardrailErrorCode(tt, err, constants.ProjectGuardrailMaxAppsExceeded)
+	})
+
+	t.Run("app within limit", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{TeamId: 1, MaxApps: 3}, nil)
+		m.teamRepository.On("FindOne", 1).Return(team, nil)
+		m.guardrailRepository.On("CountAppsByTeamId", 1).Return(2, nil)
+		assert.Nil(tt, service.ValidateAppCreate(1, nil))
+	})
+}
+
+func TestValidateCdPipelineCreate(t *testing.T) {
+	team := teamRepository.Team{Id: 1, Name: "payments"}
+	registryId := "docker-hub"
+	envs := []*envRepository.Environment{{Id: 5, Name: "prod", ClusterId: 2}}
+
+	t.Run("environment of a cluster outside the allow list", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.appRepository.On("FindById", 10).Return(&app.App{Id: 10, TeamId: 1}, nil)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{TeamId: 1, AllowedClusterIds: []int{1}}, nil)
+		m.teamRepository.On("FindOne", 1).Return(team, nil)
+		m.environmentRepository.On("FindByIds", mock.Anything).Return(envs, nil)
+		err := service.ValidateCdPipelineCreate(10, []int{5})
+		assertGuardrailErrorCode(tt, err, constants.ProjectGuardrailEnvironmentNotAllowed)
+	})
+
+	t.Run("environment limit counts only new environments", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.appRepository.On("FindById", 10).Return(&app.App{Id: 10, TeamId: 1}, nil)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{TeamId: 1, MaxEnvironments: 2}, nil)
+		m.teamRepository.On("FindOne", 1).Return(team, nil)
+		m.environmentRepository.On("FindByIds", mock.Anything).Return(envs, nil)
+		m.guardrailRepository.On("FindEnvironmentIdsByTeamId", 1).Return([]int{3, 4}, nil)
+		err := service.ValidateCdPipelineCreate(10, []int{5})
+		assertGuardrailErrorCode(tt, err, constants.ProjectGuardrailMaxEnvironmentsExceeded)
+
+		service, m = initProjectGuardrailService(tt)
+		m.appRepository.On("FindById", 10).Return(&app.App{Id: 10, TeamId: 1}, nil)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{TeamId: 1, MaxEnvironments: 2}, nil)
+		m.teamRepository.On("FindOne", 1).Return(team, nil)
+		m.environmentRepository.On("FindByIds", mock.Anything).Return(envs, nil)
+		m.guardrailRepository.On("FindEnvironmentIdsByTeamId", 1).Return([]int{4, 5}, nil)
+		assert.Nil(tt, service.ValidateCdPipelineCreate(10, []int{5}))
+	})
+
+	t.Run("chart of the app outside the allow list", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.appRepository.On("FindById", 10).Return(&app.App{Id: 10, TeamId: 1}, nil)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{TeamId: 1, AllowedChartRefIds: []int{20}}, nil)
+		m.teamRepository.On("FindOne", 1).Return(team, nil)
+		m.environmentRepository.On("FindByIds", mock.Anything).Return(envs, nil)
+		m.chartRepository.On("FindChartRefIdForLatestChartForAppByAppId", 10).Return(21, nil)
+		err := service.ValidateCdPipelineCreate(10, []int{5})
+		assertGuardrailErrorCode(tt, err, constants.ProjectGuardrailChartRefNotAllowed)
+	})
+
+	t.Run("registry of the app outside the allow list", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.appRepository.On("FindById", 10).Return(&app.App{Id: 10, TeamId: 1}, nil)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{TeamId: 1, AllowedDockerRegistryIds: []string{"ecr"}}, nil)
+		m.teamRepository.On("FindOne", 1).Return(team, nil)
+		m.environmentRepository.On("FindByIds", mock.Anything).Return(envs, nil)
+		m.ciTemplateRepository.On("FindByAppId", 10).Return(&pipelineConfig.CiTemplate{AppId: 10, DockerRegistryId: &registryId}, nil)
+		err := service.ValidateCdPipelineCreate(10, []int{5})
+		assertGuardrailErrorCode(tt, err, constants.ProjectGuardrailDockerRegistryNotAllowed)
+	})
+}
+
+func TestValidateAppClone(t *testing.T) {
+	t.Run("environments of the template app outside the allow list", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{TeamId: 1, AllowedEnvironmentIds: []int{6}}, nil)
+		m.teamRepository.On("FindOne", 1).Return(teamRepository.Team{Id: 1, Name: "payments"}, nil)
+		m.pipelineRepository.On("FindActiveByAppId", 10).Return([]*pipelineConfig.Pipeline{{AppId: 10, EnvironmentId: 5}}, nil)
+		m.environmentRepository.On("FindByIds", mock.Anything).Return([]*envRepository.Environment{{Id: 5, Name: "prod", ClusterId: 2}}, nil)
+		err := service.ValidateAppClone(10, 1)
+		assertGuardrailErrorCode(tt, err, constants.ProjectGuardrailEnvironmentNotAllowed)
+	})
+}
+
+func TestValidateAppImport(t *testing.T) {
+	team := teamRepository.Team{Id: 1, Name: "payments"}
+
+	t.Run("mandatory labels are checked when the app moves to the project", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{TeamId: 1, MandatoryAppLabels: []string{"owner"}}, nil)
+		m.teamRepository.On("FindOne", 1).Return(team, nil)
+		m.appRepository.On("FindById", 10).Return(&app.App{Id: 10, TeamId: 2}, nil)
+		err := service.ValidateAppImport(10, 1, nil, nil, nil, nil)
+		assertGuardrailErrorCode(tt, err, constants.ProjectGuardrailMandatoryLabelsMissing)
+	})
+
+	t.Run("chart outside the allow list", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{TeamId: 1, MandatoryAppLabels: []string{"owner"}, AllowedChartRefIds: []int{20}}, nil)
+		m.teamRepository.On("FindOne", 1).Return(team, nil)
+		m.appRepository.On("FindById", 10).Return(&app.App{Id: 10, TeamId: 1}, nil)
+		err := service.ValidateAppImport(10, 1, nil, nil, []int{20, 21}, nil)
+		assertGuardrailErrorCode(tt, err, constants.ProjectGuardrailChartRefNotAllowed)
+	})
+
+	t.Run("registry outside the allow list", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{TeamId: 1, AllowedDockerRegistryIds: []string{"ecr"}}, nil)
+		m.teamRepository.On("FindOne", 1).Return(team, nil)
+		err := service.ValidateAppImport(0, 1, nil, nil, nil, []string{"docker-hub"})
+		assertGuardrailErrorCode(tt, err, constants.ProjectGuardrailDockerRegistryNotAllowed)
+	})
+}
+
+func TestValidateChartRefUpdate(t *testing.T) {
+	t.Run("project without guardrail", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.appRepository.On("FindById", 10).Return(&app.App{Id: 10, TeamId: 1}, nil)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(nil, pg.ErrNoRows)
+		assert.Nil(tt, service.ValidateChartRefUpdate(10, 21))
+	})
+
+	t.Run("chart outside the allow list", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.appRepository.On("FindById", 10).Return(&app.App{Id: 10, TeamId: 1}, nil)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{TeamId: 1, AllowedChartRefIds: []int{20}}, nil)
+		m.teamRepository.On("FindOne", 1).Return(teamRepository.Team{Id: 1, Name: "payments"}, nil)
+		assert.Nil(tt, service.ValidateChartRefUpdate(10, 20))
+		err := service.ValidateChartRefUpdate(10, 21)
+		assertGuardrailErrorCode(tt, err, constants.ProjectGuardrailChartRefNotAllowed)
+	})
+}
+
+func TestValidateDockerRegistryUpdate(t *testing.T) {
+	t.Run("registry outside the allow list", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.appRepository.On("FindById", 10).Return(&app.App{Id: 10, TeamId: 1}, nil)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{TeamId: 1, AllowedDockerRegistryIds: []string{"ecr"}}, nil)
+		m.teamRepository.On("FindOne", 1).Return(teamRepository.Team{Id: 1, Name: "payments"}, nil)
+		assert.Nil(tt, service.ValidateDockerRegistryUpdate(10, "ecr"))
+		err := service.ValidateDockerRegistryUpdate(10, "docker-hub")
+		assertGuardrailErrorCode(tt, err, constants.ProjectGuardrailDockerRegistryNotAllowed)
+	})
+}
+
+func TestAssignEnvironments(t *testing.T) {
+	t.Run("projects without an allow list are left unchanged", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(nil, pg.ErrNoRows)
+		m.guardrailRepository.On("FindByTeamId", 2).Return(&repository.ProjectGuardrail{Id: 4, TeamId: 2, MaxApps: 5}, nil)
+		assert.NoError(tt, service.AssignEnvironments([]int{1, 2}, []int{7, 8}, 2))
+	})
+	t.Run("environments added to the allow list", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{Id: 4, TeamId: 1, AllowedClusterIds: []int{2}}, nil)
+		m.guardrailRepository.On("Update", mock.MatchedBy(func(guardrail *repository.ProjectGuardrail) bool {
+			return assert.Equal(tt, []int{7}, guardrail.AllowedEnvironmentIds) && assert.Equal(tt, []int{2}, guardrail.AllowedClusterIds)
+		})).Return(nil)
+		assert.NoError(tt, service.AssignEnvironments([]int{1}, []int{7}, 2))
+	})
+	t.Run("environments already allowed", func(tt *testing.T) {
+		service, m := initProjectGuardrailService(tt)
+		m.guardrailRepository.On("FindByTeamId", 1).Return(&repository.ProjectGuardrail{Id: 4, TeamId: 1, AllowedEnvironmentIds: []int{7}}, nil)
+		assert.NoError(tt, service.AssignEnvironments([]int{1}, []int{7}, 2))
+	})
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+// ProjectGuardrailDto holds the limits of a project, a zero limit or an empty list means no restriction
+type ProjectGuardrailDto struct {
+	TeamId                   int      `json:"teamId"`
+	MaxApps                  int      `json:"maxApps" validate:"gte=0"`
+	MaxEnvironments          int      `json:"maxEnvironments" validate:"gte=0"`
+	AllowedClusterIds        []int    `json:"allowedClusterIds"`
+	AllowedEnvironmentIds    []int    `json:"allowedEnvironmentIds"`
+	AllowedChartRefIds       []int    `json:"allowedChartRefIds"`
+	AllowedDockerRegistryIds []string `json:"allowedDockerRegistryIds"`
+	MandatoryAppLabels       []string `json:"mandatoryAppLabels"`
+	UserId                   int32    `json:"-"`
+}
+
+// ProjectGuardrailUsage is the current usage of a project against its guardrail, Guardrail is nil if none is configured
+type ProjectGuardrailUsage struct {
+	TeamId           int                  `json:"teamId"`
+	TeamName         string               `json:"teamName"`
+	AppCount         int                  `json:"appCount"`
+	EnvironmentCount int                  `json:"environmentCount"`
+	EnvironmentIds   []int                `json:"environmentIds"`
+	Guardrail        *ProjectGuardrailDto `json:"guardrail,omitempty"`
+}
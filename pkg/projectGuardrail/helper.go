@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package projectGuardrail
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/constants"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail/bean"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail/repository"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// isEnvironmentAllowed allows an environment listed by id or belonging to a listed cluster,
+// every environment is allowed when neither list is set
+func isEnvironmentAllowed(guardrail *repository.ProjectGuardrail, envId, clusterId int) bool {
+	if len(guardrail.AllowedEnvironmentIds) == 0 && len(guardrail.AllowedClusterIds) == 0 {
+		return true
+	}
+	return slices.Contains(guardrail.AllowedEnvironmentIds, envId) || slices.Contains(guardrail.AllowedClusterIds, clusterId)
+}
+
+// addAllowedClusters adds the clusters missing from a restricting allow list and reports whether the guardrail changed
+func addAllowedClusters(guardrail *repository.ProjectGuardrail, clusterIds []int) bool {
+	if len(guardrail.AllowedEnvironmentIds) == 0 && len(guardrail.AllowedClusterIds) == 0 {
+		return false
+	}
+	changed := false
+	for _, clusterId := range clusterIds {
+		if !slices.Contains(guardrail.AllowedClusterIds, clusterId) {
+			guardrail.AllowedClusterIds = append(guardrail.AllowedClusterIds, clusterId)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// addAllowedEnvironments adds the environments missing from a restricting allow list and reports whether the guardrail changed
+func addAllowedEnvironments(guardrail *repository.ProjectGuardrail, envIds []int) bool {
+	if len(guardrail.AllowedEnvironmentIds) == 0 && len(guardrail.AllowedClusterIds) == 0 {
+		return false
+	}
+	changed := false
+	for _, envId := range envIds {
+		if !slices.Contains(guardrail.AllowedEnvironmentIds, envId) {
+			guardrail.AllowedEnvironmentIds = append(guardrail.AllowedEnvironmentIds, envId)
+			changed = true
+		}
+	}
+	return changed
+}
+
+func isChartRefAllowed(guardrail *repository.ProjectGuardrail, chartRefId int) bool {
+	return len(guardrail.AllowedChartRefIds) == 0 || slices.Contains(guardrail.AllowedChartRefIds, chartRefId)
+}
+
+func isDockerRegistryAllowed(guardrail *repository.ProjectGuardrail, dockerRegistryId string) bool {
+	return len(guardrail.AllowedDockerRegistryIds) == 0 || slices.Contains(guardrail.AllowedDockerRegistryIds, dockerRegistryId)
+}
+
+func getMissingLabels(mandatoryLabels []string, labelKeys []string) []string {
+	missing := make([]string, 0)
+	for _, label := range mandatoryLabels {
+		if !slices.Contains(labelKeys, label) {
+			missing = append(missing, label)
+		}
+	}
+	return missing
+}
+
+// getNewEnvironmentIds returns the distinct requested environments which are not already used by the project
+func getNewEnvironmentIds(usedEnvIds []int, requestedEnvIds []int) []int {
+	newEnvIds := make([]int, 0)
+	for _, envId := range requestedEnvIds {
+		if !slices.Contains(usedEnvIds, envId) && !slices.Contains(newEnvIds, envId) {
+			newEnvIds = append(newEnvIds, envId)
+		}
+	}
+	return newEnvIds
+}
+
+func newGuardrailError(code string, message string) *util.ApiError {
+	return util.NewApiError(http.StatusUnprocessableEntity, message, message).WithCode(code)
+}
+
+func maxAppsExceededError(teamName string, maxApps int) *util.ApiError {
+	return newGuardrailError(constants.ProjectGuardrailMaxAppsExceeded,
+		fmt.Sprintf("project %s has reached its limit of %d apps", teamName, maxApps))
+}
+
+func maxEnvironmentsExceededError(teamName string, maxEnvironments int) *util.ApiError {
+	return newGuardrailError(constants.ProjectGuardrailMaxEnvironmentsExceeded,
+		fmt.Sprintf("project %s can deploy to at most %d environments", teamName, maxEnvironments))
+}
+
+func environmentNotAllowedError(teamName string, envName string) *util.ApiError {
+	return newGuardrailError(constants.ProjectGuardrailEnvironmentNotAllowed,
+		fmt.Sprintf("environment %s is not allowed for project %s", envName, teamName))
+}
+
+func chartRefNotAllowedError(teamName string, chartRefId int) *util.ApiError {
+	return newGuardrailError(constants.ProjectGuardrailChartRefNotAllowed,
+		fmt.Sprintf("chart ref %d is not allowed for project %s", chartRefId, teamName))
+}
+
+func dockerRegistryNotAllowedError(teamName string, dockerRegistryId string) *util.ApiError {
+	return newGuardrailError(constants.ProjectGuardrailDockerRegistryNotAllowed,
+		fmt.Sprintf("container registry %s is not allowed for project %s", dockerRegistryId, teamName))
+}
+
+func mandatoryLabelsMissingError(teamName string, missing []string) *util.ApiError {
+	return newGuardrailError(constants.ProjectGuardrailMandatoryLabelsMissing,
+		fmt.Sprintf("apps of project %s must have the labels %s", teamName, strings.Join(missing, ", ")))
+}
+
+func toModel(dto *bean.ProjectGuardrailDto, model *repository.ProjectGuardrail) *repository.ProjectGuardrail {
+	model.TeamId = dto.TeamId
+	model.MaxApps = dto.MaxApps
+	model.MaxEnvironments = dto.MaxEnvironments
+	model.AllowedClusterIds = dto.AllowedClusterIds
+	model.AllowedEnvironmentIds = dto.AllowedEnvironmentIds
+	model.AllowedChartRefIds = dto.AllowedChartRefIds
+	model.AllowedDockerRegistryIds = dto.AllowedDockerRegistryIds
+	model.MandatoryAppLabels = dto.MandatoryAppLabels
+	model.Active = true
+	return model
+}
+
+func toDto(model *repository.ProjectGuardrail) *bean.ProjectGuardrailDto {
+	return &bean.ProjectGuardrailDto{
+		TeamId:                   model.TeamId,
+		MaxApps:                  model.MaxApps,
+		MaxEnvironments:          model.MaxEnvironments,
+		AllowedClusterIds:        model.AllowedClusterIds,
+		AllowedEnvironmentIds:    model.AllowedEnvironmentIds,
+		AllowedChartRefIds:       model.AllowedChartRefIds,
+		AllowedDockerRegistryIds: model.AllowedDockerRegistryIds,
+		MandatoryAppLabels:       model.MandatoryAppLabels,
+	}
+}
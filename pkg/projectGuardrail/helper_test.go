@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package projectGuardrail
+
+import (
+	"github.com/devtron-labs/devtron/internal/constants"
+	"github.com/devtron-labs/devtron/pkg/projectGuardrail/repository"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestIsEnvironmentAllowed(t *testing.T) {
+	tests := []struct {
+		name      string
+		guardrail *repository.ProjectGuardrail
+		envId     int
+		clusterId int
+		want      bool
+	}{
+		{name: "no restriction", guardrail: &repository.ProjectGuardrail{}, envId: 1, clusterId: 1, want: true},
+		{name: "listed environment", guardrail: &repository.ProjectGuardrail{AllowedEnvironmentIds: []int{1}}, envId: 1, clusterId: 1, want: true},
+		{name: "environment of listed cluster", guardrail: &repository.ProjectGuardrail{AllowedEnvironmentIds: []int{2}, AllowedClusterIds: []int{1}}, envId: 1, clusterId: 1, want: true},
+		{name: "unlisted environment", guardrail: &repository.ProjectGuardrail{AllowedEnvironmentIds: []int{2}}, envId: 1, clusterId: 1, want: false},
+		{name: "environment of unlisted cluster", guardrail: &repository.ProjectGuardrail{AllowedClusterIds: []int{2}}, envId: 1, clusterId: 1, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isEnvironmentAllowed(tt.guardrail, tt.envId, tt.clusterId))
+		})
+	}
+}
+
+func TestIsChartRefAndDockerRegistryAllowed(t *testing.T) {
+	guardrail := &repository.ProjectGuardrail{}
+	assert.True(t, isChartRefAllowed(guardrail, 10))
+	assert.True(t, isDockerRegistryAllowed(guardrail, "docker-hub"))
+
+	guardrail.AllowedChartRefIds = []int{10}
+	guardrail.AllowedDockerRegistryIds = []string{"ecr"}
+	assert.True(t, isChartRefAllowed(guardrail, 10))
+	assert.False(t, isChartRefAllowed(guardrail, 11))
+	assert.True(t, isDockerRegistryAllowed(guardrail, "ecr"))
+	assert.False(t, isDockerRegistryAllowed(guardrail, "docker-hub"))
+}
+
+func TestGetMissingLabels(t *testing.T) {
+	assert.Empty(t, getMissingLabels(nil, []string{"owner"}))
+	assert.Empty(t, getMissingLabels([]string{"owner"}, []string{"owner", "tier"}))
+	assert.Equal(t, []string{"cost-center"}, getMissingLabels([]string{"owner", "cost-center"}, []string{"owner"}))
+}
+
+func TestGetNewEnvironmentIds(t *testing.T) {
+	assert.Empty(t, getNewEnvironmentIds([]int{1, 2}, []int{2, 1}))
+	assert.Equal(t, []int{3}, getNewEnvironmentIds([]int{1, 2}, []int{1, 3, 3}))
+	assert.Equal(t, []int{1, 2}, getNewEnvironmentIds(nil, []int{1, 2}))
+}
+
+func TestAddAllowedClusters(t *testing.T) {
+	unrestricted := &repository.ProjectGuardrail{}
+	assert.False(t, addAllowedClusters(unrestricted, []int{3}))
+	assert.Empty(t, unrestricted.AllowedClusterIds)
+
+	byCluster := &repository.ProjectGuardrail{AllowedClusterIds: []int{1, 2}}
+	assert.True(t, addAllowedClusters(byCluster, []int{2, 3}))
+	assert.Equal(t, []int{1, 2, 3}, byCluster.AllowedClusterIds)
+	assert.False(t, addAllowedClusters(byCluster, []int{3}))
+
+	byEnvironment := &repository.ProjectGuardrail{AllowedEnvironmentIds: []int{7}}
+	assert.True(t, addAllowedClusters(byEnvironment, []int{4}))
+	assert.Equal(t, []int{4}, byEnvironment.AllowedClusterIds)
+}
+
+func TestAddAllowedEnvironments(t *testing.T) {
+	guardrail := &repository.ProjectGuardrail{AllowedEnvironmentIds: []int{1}}
+	assert.True(t, addAllowedEnvironments(guardrail, []int{1, 2}))
+	assert.Equal(t, []int{1, 2}, guardrail.AllowedEnvironmentIds)
+	assert.False(t, addAllowedEnvironments(guardrail, []int{2}))
+
+	unrestricted := &repository.ProjectGuardrail{}
+	assert.False(t, addAllowedEnvironments(unrestricted, []int{1}))
+	assert.Empty(t, unrestricted.AllowedEnvironmentIds)
+}
+
+func TestGuardrailErrors(t *testing.T) {
+	err := maxAppsExceededError("payments", 5)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.HttpStatusCode)
+	assert.Equal(t, constants.ProjectGuardrailMaxAppsExceeded, err.Code)
+	assert.Equal(t, "project payments has reached its limit of 5 apps", err.UserMessage)
+
+	err = mandatoryLabelsMissingError("payments", []string{"owner", "tier"})
+	assert.Equal(t, constants.ProjectGuardrailMandatoryLabelsMissing, err.Code)
+	assert.Equal(t, "apps of project payments must have the labels owner, tier", err.UserMessage)
+}
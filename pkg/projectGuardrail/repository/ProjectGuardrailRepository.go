@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/helper"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type ProjectGuardrail struct {
+	tableName                struct{} `sql:"project_guardrail" pg:",discard_unknown_columns"`
+	Id                       int      `sql:"id,pk"`
+	TeamId                   int      `sql:"team_id,notnull"`
+	MaxApps                  int      `sql:"max_apps,notnull"`
+	MaxEnvironments          int      `sql:"max_environments,notnull"`
+	AllowedClusterIds        []int    `sql:"allowed_cluster_ids" pg:",array"`
+	AllowedEnvironmentIds    []int    `sql:"allowed_environment_ids" pg:",array"`
+	AllowedChartRefIds       []int    `sql:"allowed_chart_ref_ids" pg:",array"`
+	AllowedDockerRegistryIds []string `sql:"allowed_docker_registry_ids" pg:",array"`
+	MandatoryAppLabels       []string `sql:"mandatory_app_labels" pg:",array"`
+	Active                   bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type ProjectGuardrailRepository interface {
+	Save(model *ProjectGuardrail) error
+	Update(model *ProjectGuardrail) error
+	FindByTeamId(teamId int) (*ProjectGuardrail, error)
+	FindAllActive() ([]*ProjectGuardrail, error)
+	// CountAppsByTeamId counts the active devtron apps and jobs of a project, chart store apps are not counted
+	CountAppsByTeamId(teamId int) (int, error)
+	// FindEnvironmentIdsByTeamId returns the distinct environments the apps of a project have cd pipelines on
+	FindEnvironmentIdsByTeamId(teamId int) ([]int, error)
+}
+
+type ProjectGuardrailRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewProjectGuardrailRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ProjectGuardrailRepositoryImpl {
+	return &ProjectGuardrailRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ProjectGuardrailRepositoryImpl) Save(model *ProjectGuardrail) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *ProjectGuardrailRepositoryImpl) Update(model *ProjectGuardrail) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *ProjectGuardrailRepositoryImpl) FindByTeamId(teamId int) (*ProjectGuardrail, error) {
+	model := &ProjectGuardrail{}
+	err := impl.dbConnection.Model(model).
+		Where("team_id = ?", teamId).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *ProjectGuardrailRepositoryImpl) FindAllActive() ([]*ProjectGuardrail, error) {
+	var models []*ProjectGuardrail
+	err := impl.dbConnection.Model(&models).
+		Where("active = ?", true).
+		Select()
+	return models, err
+}
+
+func (impl *ProjectGuardrailRepositoryImpl) CountAppsByTeamId(teamId int) (int, error) {
+	var count int
+	query := "SELECT count(id) FROM app WHERE team_id = ? AND active = ? AND app_type != ?;"
+	_, err := impl.dbConnection.Query(&count, query, teamId, true, helper.ChartStoreApp)
+	return count, err
+}
+
+func (impl *ProjectGuardrailRepositoryImpl) FindEnvironmentIdsByTeamId(teamId int) ([]int, error) {
+	var envIds []int
+	query := "SELECT DISTINCT p.environment_id FROM pipeline p" +
+		" INNER JOIN app a ON a.id = p.app_id" +
+		" WHERE a.team_id = ? AND a.active = ? AND p.deleted = ?;"
+	_, err := impl.dbConnection.Query(&envIds, query, teamId, true, false)
+	return envIds, err
+}
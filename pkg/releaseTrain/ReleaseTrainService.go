@@ -0,0 +1,703 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package releaseTrain
+
+import (
+	"context"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/async"
+	bean3 "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	appRepository "github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps"
+	triggerBean "github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
+	releaseBean "github.com/devtron-labs/devtron/pkg/releaseTrain/bean"
+	releaseRepository "github.com/devtron-labs/devtron/pkg/releaseTrain/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type ReleaseTrainService interface {
+	CreateReleaseTrain(request *releaseBean.ReleaseTrainRequest) (*releaseBean.ReleaseTrainDto, error)
+	GetReleaseTrain(id int) (*releaseBean.ReleaseTrainDto, error)
+	ListReleaseTrains() ([]*releaseBean.ReleaseTrainDto, error)
+	DeleteReleaseTrain(id int, userId int32) error
+	DiffReleaseTrains(baseId, targetId int) (*releaseBean.ReleaseTrainDiff, error)
+	// PromoteReleaseTrain deploys every app of the release train to the given environment as a single unit
+	PromoteReleaseTrain(request *releaseBean.ReleaseTrainPromoteRequest) (*releaseBean.ReleaseTrainDeploymentDto, error)
+	// RollbackEnvironment restores the release train deployed in the environment before the current one
+	RollbackEnvironment(request *releaseBean.ReleaseTrainRollbackRequest) (*releaseBean.ReleaseTrainDeploymentDto, error)
+	GetReleaseTrainDeployment(deploymentId int) (*releaseBean.ReleaseTrainDeploymentDto, error)
+	GetEnvironmentReleaseHistory(environmentId int) ([]*releaseBean.ReleaseTrainDeploymentDto, error)
+	// SyncDeploymentStatuses syncs the apps of deployments in progress with their cd workflow runners and rolls up
+	// the status of the deployments, it is run by the status sync cron
+	SyncDeploymentStatuses()
+}
+
+type ReleaseTrainServiceImpl struct {
+	logger                           *zap.SugaredLogger
+	releaseTrainRepository           releaseRepository.ReleaseTrainRepository
+	releaseTrainDeploymentRepository releaseRepository.ReleaseTrainDeploymentRepository
+	appRepository                    appRepository.AppRepository
+	ciArtifactRepository             repository.CiArtifactRepository
+	pipelineRepository               pipelineConfig.PipelineRepository
+	cdWorkflowRepository             pipelineConfig.CdWorkflowRepository
+	environmentRepository            repository2.EnvironmentRepository
+	cdTriggerService                 devtronApps.TriggerService
+	asyncRunnable                    *async.Runnable
+	config                           *releaseBean.ReleaseTrainConfig
+}
+
+func NewReleaseTrainServiceImpl(logger *zap.SugaredLogger,
+	releaseTrainRepository releaseRepository.ReleaseTrainRepository,
+	releaseTrainDeploymentRepository releaseRepository.ReleaseTrainDeploymentRepository,
+	appRepository appRepository.AppRepository,
+	ciArtifactRepository repository.CiArtifactRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	environmentRepository repository2.EnvironmentRepository,
+	cdTriggerService devtronApps.TriggerService,
+	asyncRunnable *async.Runnable,
+	cronLogger *cron2.CronLoggerImpl) (*ReleaseTrainServiceImpl, error) {
+	config := &releaseBean.ReleaseTrainConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing release train config", "err", err)
+		return nil, err
+	}
+	impl := &ReleaseTrainServiceImpl{
+		logger:                           logger,
+		releaseTrainRepository:           releaseTrainRepository,
+		releaseTrainDeploymentRepository: releaseTrainDeploymentRepository,
+		appRepository:                    appRepository,
+		ciArtifactRepository:             ciArtifactRepository,
+		pipelineRepository:               pipelineRepository,
+		cdWorkflowRepository:             cdWorkflowRepository,
+		environmentRepository:            environmentRepository,
+		cdTriggerService:                 cdTriggerService,
+		asyncRunnable:                    asyncRunnable,
+		config:                           config,
+	}
+	statusSyncCron := cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	_, err = statusSyncCron.AddFunc(config.StatusSyncCron, impl.SyncDeploymentStatuses)
+	if err != nil {
+		logger.Errorw("error in adding release train status sync cron", "schedule", config.StatusSyncCron, "err", err)
+		return nil, err
+	}
+	statusSyncCron.Start()
+	return impl, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) CreateReleaseTrain(request *releaseBean.ReleaseTrainRequest) (*releaseBean.ReleaseTrainDto, error) {
+	exists, err := impl.releaseTrainRepository.ExistsByNameAndVersion(request.Name, request.Version)
+	if err != nil {
+		impl.logger.Errorw("error in checking release train existence", "name", request.Name, "version", request.Version, "err", err)
+		return nil, err
+	}
+	if exists {
+		errMsg := fmt.Sprintf("release train %s with version %s already exists", request.Name, request.Version)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	appModels := make([]*releaseRepository.ReleaseTrainApp, 0, len(request.Apps))
+	seenAppIds := make(map[int]bool, len(request.Apps))
+	for _, appRequest := range request.Apps {
+		if seenAppIds[appRequest.AppId] {
+			errMsg := fmt.Sprintf("app %d is added more than once in the release train", appRequest.AppId)
+			return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+		seenAppIds[appRequest.AppId] = true
+		appModel, err := impl.buildReleaseTrainApp(appRequest, request.SourceEnvironmentId)
+		if err != nil {
+			return nil, err
+		}
+		appModel.AuditLog = sql.NewDefaultAuditLog(request.UserId)
+		appModels = append(appModels, appModel)
+	}
+
+	tx, err := impl.releaseTrainRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.releaseTrainRepository.RollbackTx(tx)
+	model := &releaseRepository.ReleaseTrain{
+		Name:        request.Name,
+		Version:     request.Version,
+		Description: request.Description,
+		Active:      true,
+		AuditLog:    sql.NewDefaultAuditLog(request.UserId),
+	}
+	err = impl.releaseTrainRepository.Save(model, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving release train", "request", request, "err", err)
+		return nil, err
+	}
+	for _, appModel := range appModels {
+		appModel.ReleaseTrainId = model.Id
+	}
+	err = impl.releaseTrainRepository.SaveApps(appModels, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving release train apps", "releaseTrainId", model.Id, "err", err)
+		return nil, err
+	}
+	err = impl.releaseTrainRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "releaseTrainId", model.Id, "err", err)
+		return nil, err
+	}
+	return impl.GetReleaseTrain(model.Id)
+}
+
+// buildReleaseTrainApp pins the artifact for an app. When no artifact is given, the artifact and
+// the config snapshot of the latest successful deployment in the source environment are used.
+func (impl *ReleaseTrainServiceImpl) buildReleaseTrainApp(appRequest *releaseBean.ReleaseTrainAppRequest, sourceEnvironmentId int) (*releaseRepository.ReleaseTrainApp, error) {
+	appModel := &releaseRepository.ReleaseTrainApp{
+		AppId:        appRequest.AppId,
+		CiArtifactId: appRequest.CiArtifactId,
+	}
+	if appRequest.CiArtifactId > 0 {
+		_, err := impl.ciArtifactRepository.Get(appRequest.CiArtifactId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching artifact", "ciArtifactId", appRequest.CiArtifactId, "err", err)
+			if util.IsErrNoRows(err) {
+				errMsg := fmt.Sprintf("artifact %d not found", appRequest.CiArtifactId)
+				return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+			}
+			return nil, err
+		}
+		return appModel, nil
+	}
+	if sourceEnvironmentId == 0 {
+		errMsg := fmt.Sprintf("either ciArtifactId or sourceEnvironmentId is required for app %d", appRequest.AppId)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	lastRunner, err := impl.cdWorkflowRepository.FindLastUnFailedProcessedRunner(appRequest.AppId, sourceEnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching last deployment", "appId", appRequest.AppId, "envId", sourceEnvironmentId, "err", err)
+		if util.IsErrNoRows(err) {
+			errMsg := fmt.Sprintf("app %d has no successful deployment in source environment", appRequest.AppId)
+			return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+		return nil, err
+	}
+	runner, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(lastRunner.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching workflow runner", "wfrId", lastRunner.Id, "err", err)
+		return nil, err
+	}
+	appModel.CiArtifactId = runner.CdWorkflow.CiArtifactId
+	appModel.SourcePipelineId = runner.CdWorkflow.PipelineId
+	appModel.SourceWfrId = runner.Id
+	return appModel, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) GetReleaseTrain(id int) (*releaseBean.ReleaseTrainDto, error) {
+	model, err := impl.releaseTrainRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train", "id", id, "err", err)
+		return nil, err
+	}
+	dtos, err := impl.toReleaseTrainDtos([]*releaseRepository.ReleaseTrain{model})
+	if err != nil {
+		return nil, err
+	}
+	return dtos[0], nil
+}
+
+func (impl *ReleaseTrainServiceImpl) ListReleaseTrains() ([]*releaseBean.ReleaseTrainDto, error) {
+	models, err := impl.releaseTrainRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in fetching release trains", "err", err)
+		return nil, err
+	}
+	return impl.toReleaseTrainDtos(models)
+}
+
+func (impl *ReleaseTrainServiceImpl) DeleteReleaseTrain(id int, userId int32) error {
+	model, err := impl.releaseTrainRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train", "id", id, "err", err)
+		return err
+	}
+	model.Active = false
+	model.UpdateAuditLog(userId)
+	err = impl.releaseTrainRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in deleting release train", "id", id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ReleaseTrainServiceImpl) DiffReleaseTrains(baseId, targetId int) (*releaseBean.ReleaseTrainDiff, error) {
+	base, err := impl.GetReleaseTrain(baseId)
+	if err != nil {
+		return nil, err
+	}
+	target, err := impl.GetReleaseTrain(targetId)
+	if err != nil {
+		return nil, err
+	}
+	return &releaseBean.ReleaseTrainDiff{
+		Base:   base,
+		Target: target,
+		Apps:   diffReleaseTrainApps(base.Apps, target.Apps),
+	}, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) PromoteReleaseTrain(request *releaseBean.ReleaseTrainPromoteRequest) (*releaseBean.ReleaseTrainDeploymentDto, error) {
+	releaseTrain, err := impl.releaseTrainRepository.FindById(request.ReleaseTrainId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train", "id", request.ReleaseTrainId, "err", err)
+		return nil, err
+	}
+	apps, err := impl.releaseTrainRepository.FindAppsByReleaseTrainIds([]int{releaseTrain.Id})
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train apps", "releaseTrainId", releaseTrain.Id, "err", err)
+		return nil, err
+	}
+	deploymentApps := make([]*releaseRepository.ReleaseTrainDeploymentApp, 0, len(apps))
+	specificTriggerWfrIds := make(map[int]int, len(apps))
+	for _, app := range apps {
+		pipeline, err := impl.pipelineRepository.FindActiveByAppIdAndEnvId(app.AppId, request.EnvironmentId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching cd pipeline", "appId", app.AppId, "envId", request.EnvironmentId, "err", err)
+			if util.IsErrNoRows(err) {
+				errMsg := fmt.Sprintf("app %d has no cd pipeline in environment %d", app.AppId, request.EnvironmentId)
+				return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+			}
+			return nil, err
+		}
+		// config snapshot can only be replayed on the pipeline it was captured from
+		if app.SourceWfrId > 0 && app.SourcePipelineId == pipeline.Id {
+			specificTriggerWfrIds[pipeline.Id] = app.SourceWfrId
+		}
+		deploymentApps = append(deploymentApps, &releaseRepository.ReleaseTrainDeploymentApp{
+			AppId:        app.AppId,
+			PipelineId:   pipeline.Id,
+			CiArtifactId: app.CiArtifactId,
+		})
+	}
+	deployment := &releaseRepository.ReleaseTrainDeployment{
+		ReleaseTrainId: releaseTrain.Id,
+		EnvironmentId:  request.EnvironmentId,
+	}
+	return impl.startDeployment(deployment, deploymentApps, specificTriggerWfrIds, request.UserId)
+}
+
+func (impl *ReleaseTrainServiceImpl) RollbackEnvironment(request *releaseBean.ReleaseTrainRollbackRequest) (*releaseBean.ReleaseTrainDeploymentDto, error) {
+	history, err := impl.releaseTrainDeploymentRepository.FindByEnvironmentId(request.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release history", "envId", request.EnvironmentId, "err", err)
+		return nil, err
+	}
+	rollbackSource := getRollbackTarget(history)
+	if rollbackSource == nil {
+		errMsg := "no previous successful release found in this environment to rollback to"
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	sourceApps, err := impl.releaseTrainDeploymentRepository.FindAppsByDeploymentIds([]int{rollbackSource.Id})
+	if err != nil {
+		impl.logger.Errorw("error in fetching release deployment apps", "deploymentId", rollbackSource.Id, "err", err)
+		return nil, err
+	}
+	deploymentApps := make([]*releaseRepository.ReleaseTrainDeploymentApp, 0, len(sourceApps))
+	specificTriggerWfrIds := make(map[int]int, len(sourceApps))
+	for _, sourceApp := range sourceApps {
+		if sourceApp.CdWorkflowRunnerId > 0 {
+			specificTriggerWfrIds[sourceApp.PipelineId] = sourceApp.CdWorkflowRunnerId
+		}
+		deploymentApps = append(deploymentApps, &releaseRepository.ReleaseTrainDeploymentApp{
+			AppId:        sourceApp.AppId,
+			PipelineId:   sourceApp.PipelineId,
+			CiArtifactId: sourceApp.CiArtifactId,
+		})
+	}
+	deployment := &releaseRepository.ReleaseTrainDeployment{
+		ReleaseTrainId:   rollbackSource.ReleaseTrainId,
+		EnvironmentId:    request.EnvironmentId,
+		IsRollback:       true,
+		RollbackSourceId: rollbackSource.Id,
+	}
+	return impl.startDeployment(deployment, deploymentApps, specificTriggerWfrIds, request.UserId)
+}
+
+// startDeployment persists the release train deployment and triggers its apps asynchronously.
+// specificTriggerWfrIds maps pipeline id to the cd_workflow_runner whose config has to be deployed.
+func (impl *ReleaseTrainServiceImpl) startDeployment(deployment *releaseRepository.ReleaseTrainDeployment,
+	deploymentApps []*releaseRepository.ReleaseTrainDeploymentApp, specificTriggerWfrIds map[int]int, userId int32) (*releaseBean.ReleaseTrainDeploymentDto, error) {
+	tx, err := impl.releaseTrainRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.releaseTrainRepository.RollbackTx(tx)
+	deployment.Status = string(releaseBean.ReleaseStatusInitiated)
+	deployment.AuditLog = sql.NewDefaultAuditLog(userId)
+	err = impl.releaseTrainDeploymentRepository.Save(deployment, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving release train deployment", "deployment", deployment, "err", err)
+		// the in progress deployment of an environment is unique in db, concurrent requests can not both pass
+		if isInProgressDeploymentConflict(err) {
+			errMsg := "another release is already being deployed in this environment"
+			return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+		}
+		return nil, err
+	}
+	for _, deploymentApp := range deploymentApps {
+		deploymentApp.ReleaseTrainDeploymentId = deployment.Id
+		deploymentApp.Status = string(releaseBean.ReleaseStatusInitiated)
+		deploymentApp.AuditLog = sql.NewDefaultAuditLog(userId)
+	}
+	err = impl.releaseTrainDeploymentRepository.SaveApps(deploymentApps, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving release train deployment apps", "deploymentId", deployment.Id, "err", err)
+		return nil, err
+	}
+	err = impl.releaseTrainRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "deploymentId", deployment.Id, "err", err)
+		return nil, err
+	}
+	impl.asyncRunnable.Execute(func() {
+		impl.triggerDeploymentApps(deployment, deploymentApps, specificTriggerWfrIds, userId)
+	})
+	return impl.GetReleaseTrainDeployment(deployment.Id)
+}
+
+func (impl *ReleaseTrainServiceImpl) triggerDeploymentApps(deployment *releaseRepository.ReleaseTrainDeployment,
+	deploymentApps []*releaseRepository.ReleaseTrainDeploymentApp, specificTriggerWfrIds map[int]int, userId int32) {
+	for _, deploymentApp := range deploymentApps {
+		overrideRequest := &bean3.ValuesOverrideRequest{
+			PipelineId:           deploymentApp.PipelineId,
+			AppId:                deploymentApp.AppId,
+			CiArtifactId:         deploymentApp.CiArtifactId,
+			CdWorkflowType:       bean3.CD_WORKFLOW_TYPE_DEPLOY,
+			DeploymentWithConfig: bean3.DEPLOYMENT_CONFIG_TYPE_LAST_SAVED,
+			IsRollbackDeployment: deployment.IsRollback,
+			UserId:               userId,
+		}
+		if wfrId, ok := specificTriggerWfrIds[deploymentApp.PipelineId]; ok {
+			overrideRequest.DeploymentWithConfig = bean3.DEPLOYMENT_CONFIG_TYPE_SPECIFIC_TRIGGER
+			overrideRequest.WfrIdForDeploymentWithSpecificTrigger = wfrId
+		}
+		triggerContext := triggerBean.TriggerContext{
+			Context: context.Background(),
+		}
+		_, _, _, err := impl.cdTriggerService.ManualCdTrigger(triggerContext, overrideRequest)
+		deploymentApp.CdWorkflowRunnerId = overrideRequest.WfrId
+		if err != nil {
+			impl.logger.Errorw("error in triggering release train app", "releaseTrainDeploymentId", deployment.Id, "pipelineId", deploymentApp.PipelineId, "err", err)
+			deploymentApp.Status = string(releaseBean.ReleaseStatusFailed)
+			deploymentApp.Message = err.Error()
+		} else {
+			deploymentApp.Status = string(releaseBean.ReleaseStatusProgressing)
+		}
+		deploymentApp.UpdateAuditLog(userId)
+	}
+	err := impl.releaseTrainDeploymentRepository.UpdateApps(deploymentApps)
+	if err != nil {
+		impl.logger.Errorw("error in updating release train deployment apps", "releaseTrainDeploymentId", deployment.Id, "err", err)
+	}
+	err = impl.syncDeploymentStatuses([]*releaseRepository.ReleaseTrainDeployment{deployment})
+	if err != nil {
+		impl.logger.Errorw("error in syncing release train deployment status", "releaseTrainDeploymentId", deployment.Id, "err", err)
+	}
+}
+
+// GetReleaseTrainDeployment returns the deployment with the app statuses last synced by SyncDeploymentStatuses
+func (impl *ReleaseTrainServiceImpl) GetReleaseTrainDeployment(deploymentId int) (*releaseBean.ReleaseTrainDeploymentDto, error) {
+	deployment, err := impl.releaseTrainDeploymentRepository.FindById(deploymentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train deployment", "id", deploymentId, "err", err)
+		return nil, err
+	}
+	dtos, err := impl.toReleaseTrainDeploymentDtos([]*releaseRepository.ReleaseTrainDeployment{deployment})
+	if err != nil {
+		return nil, err
+	}
+	return dtos[0], nil
+}
+
+func (impl *ReleaseTrainServiceImpl) GetEnvironmentReleaseHistory(environmentId int) ([]*releaseBean.ReleaseTrainDeploymentDto, error) {
+	deployments, err := impl.releaseTrainDeploymentRepository.FindByEnvironmentId(environmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release history", "envId", environmentId, "err", err)
+		return nil, err
+	}
+	return impl.toReleaseTrainDeploymentDtos(deployments)
+}
+
+func (impl *ReleaseTrainServiceImpl) toReleaseTrainDtos(models []*releaseRepository.ReleaseTrain) ([]*releaseBean.ReleaseTrainDto, error) {
+	releaseTrainIds := make([]int, 0, len(models))
+	for _, model := range models {
+		releaseTrainIds = append(releaseTrainIds, model.Id)
+	}
+	apps, err := impl.releaseTrainRepository.FindAppsByReleaseTrainIds(releaseTrainIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train apps", "releaseTrainIds", releaseTrainIds, "err", err)
+		return nil, err
+	}
+	appNames, err := impl.getAppNames(apps)
+	if err != nil {
+		return nil, err
+	}
+	artifactIds := make([]int, 0, len(apps))
+	for _, app := range apps {
+		artifactIds = append(artifactIds, app.CiArtifactId)
+	}
+	artifactImages := make(map[int]string, len(artifactIds))
+	if len(artifactIds) > 0 {
+		artifacts, err := impl.ciArtifactRepository.GetByIds(artifactIds)
+		if err != nil {
+			impl.logger.Errorw("error in fetching artifacts", "artifactIds", artifactIds, "err", err)
+			return nil, err
+		}
+		for _, artifact := range artifacts {
+			artifactImages[artifact.Id] = artifact.Image
+		}
+	}
+	appDtos := make(map[int][]*releaseBean.ReleaseTrainAppDto, len(models))
+	for _, app := range apps {
+		appDtos[app.ReleaseTrainId] = append(appDtos[app.ReleaseTrainId], &releaseBean.ReleaseTrainAppDto{
+			AppId:            app.AppId,
+			AppName:          appNames[app.AppId],
+			CiArtifactId:     app.CiArtifactId,
+			Image:            artifactImages[app.CiArtifactId],
+			SourcePipelineId: app.SourcePipelineId,
+			SourceWfrId:      app.SourceWfrId,
+		})
+	}
+	dtos := make([]*releaseBean.ReleaseTrainDto, 0, len(models))
+	for _, model := range models {
+		dtos = append(dtos, &releaseBean.ReleaseTrainDto{
+			Id:          model.Id,
+			Name:        model.Name,
+			Version:     model.Version,
+			Description: model.Description,
+			Apps:        appDtos[model.Id],
+			CreatedBy:   model.CreatedBy,
+			CreatedOn:   model.CreatedOn,
+		})
+	}
+	return dtos, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) toReleaseTrainDeploymentDtos(deployments []*releaseRepository.ReleaseTrainDeployment) ([]*releaseBean.ReleaseTrainDeploymentDto, error) {
+	deploymentIds := make([]int, 0, len(deployments))
+	for _, deployment := range deployments {
+		deploymentIds = append(deploymentIds, deployment.Id)
+	}
+	deploymentApps, err := impl.releaseTrainDeploymentRepository.FindAppsByDeploymentIds(deploymentIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train deployment apps", "deploymentIds", deploymentIds, "err", err)
+		return nil, err
+	}
+	appNames := make(map[int]string)
+	appIds := make([]int, 0, len(deploymentApps))
+	for _, deploymentApp := range deploymentApps {
+		appIds = append(appIds, deploymentApp.AppId)
+	}
+	if len(appIds) > 0 {
+		apps, err := impl.appRepository.FindAppAndProjectByIdsIn(appIds)
+		if err != nil {
+			impl.logger.Errorw("error in fetching apps", "appIds", appIds, "err", err)
+			return nil, err
+		}
+		for _, app := range apps {
+			appNames[app.Id] = app.AppName
+		}
+	}
+	appDtos := make(map[int][]*releaseBean.ReleaseTrainDeploymentAppDto, len(deployments))
+	for _, deploymentApp := range deploymentApps {
+		appDtos[deploymentApp.ReleaseTrainDeploymentId] = append(appDtos[deploymentApp.ReleaseTrainDeploymentId], &releaseBean.ReleaseTrainDeploymentAppDto{
+			AppId:              deploymentApp.AppId,
+			AppName:            appNames[deploymentApp.AppId],
+			PipelineId:         deploymentApp.PipelineId,
+			CiArtifactId:       deploymentApp.CiArtifactId,
+			CdWorkflowRunnerId: deploymentApp.CdWorkflowRunnerId,
+			Status:             releaseBean.ReleaseStatus(deploymentApp.Status),
+			Message:            deploymentApp.Message,
+		})
+	}
+	releaseTrains := make(map[int]*releaseRepository.ReleaseTrain)
+	environmentNames := make(map[int]string)
+	dtos := make([]*releaseBean.ReleaseTrainDeploymentDto, 0, len(deployments))
+	for _, deployment := range deployments {
+		releaseTrain, ok := releaseTrains[deployment.ReleaseTrainId]
+		if !ok {
+			// release train may have been deleted after it was deployed, history is still served
+			releaseTrain, err = impl.releaseTrainRepository.FindByIdIncludingInactive(deployment.ReleaseTrainId)
+			if err != nil && !util.IsErrNoRows(err) {
+				impl.logger.Errorw("error in fetching release train", "id", deployment.ReleaseTrainId, "err", err)
+				return nil, err
+			}
+			releaseTrains[deployment.ReleaseTrainId] = releaseTrain
+		}
+		environmentName, ok := environmentNames[deployment.EnvironmentId]
+		if !ok {
+			environment, err := impl.environmentRepository.FindById(deployment.EnvironmentId)
+			if err != nil {
+				impl.logger.Errorw("error in fetching environment", "envId", deployment.EnvironmentId, "err", err)
+				return nil, err
+			}
+			environmentName = environment.Name
+			environmentNames[deployment.EnvironmentId] = environmentName
+		}
+		dtos = append(dtos, &releaseBean.ReleaseTrainDeploymentDto{
+			Id:               deployment.Id,
+			ReleaseTrainId:   deployment.ReleaseTrainId,
+			ReleaseName:      releaseTrain.Name,
+			ReleaseVersion:   releaseTrain.Version,
+			EnvironmentId:    deployment.EnvironmentId,
+			EnvironmentName:  environmentName,
+			Status:           releaseBean.ReleaseStatus(deployment.Status),
+			IsRollback:       deployment.IsRollback,
+			RollbackSourceId: deployment.RollbackSourceId,
+			Apps:             appDtos[deployment.Id],
+			TriggeredBy:      deployment.CreatedBy,
+			TriggeredOn:      deployment.CreatedOn,
+		})
+	}
+	return dtos, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) SyncDeploymentStatuses() {
+	deployments, err := impl.releaseTrainDeploymentRepository.FindByStatuses(releaseBean.InProgressReleaseStatuses)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train deployments in progress", "err", err)
+		return
+	}
+	err = impl.syncDeploymentStatuses(deployments)
+	if err != nil {
+		impl.logger.Errorw("error in syncing release train deployment statuses", "err", err)
+	}
+}
+
+func (impl *ReleaseTrainServiceImpl) syncDeploymentStatuses(deployments []*releaseRepository.ReleaseTrainDeployment) error {
+	if len(deployments) == 0 {
+		return nil
+	}
+	deploymentIds := make([]int, 0, len(deployments))
+	for _, deployment := range deployments {
+		deploymentIds = append(deploymentIds, deployment.Id)
+	}
+	deploymentApps, err := impl.releaseTrainDeploymentRepository.FindAppsByDeploymentIds(deploymentIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train deployment apps", "deploymentIds", deploymentIds, "err", err)
+		return err
+	}
+	err = impl.refreshDeploymentAppStatuses(deploymentApps)
+	if err != nil {
+		return err
+	}
+	appStatuses := make(map[int][]releaseBean.ReleaseStatus, len(deployments))
+	for _, deploymentApp := range deploymentApps {
+		appStatuses[deploymentApp.ReleaseTrainDeploymentId] = append(appStatuses[deploymentApp.ReleaseTrainDeploymentId], releaseBean.ReleaseStatus(deploymentApp.Status))
+	}
+	for _, deployment := range deployments {
+		err = impl.updateDeploymentStatus(deployment, appStatuses[deployment.Id])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshDeploymentAppStatuses syncs non-terminal app statuses with the status of their cd workflow runners.
+// Apps never triggered, e.g. when the pod restarted before the async trigger ran, fail after the trigger timeout
+func (impl *ReleaseTrainServiceImpl) refreshDeploymentAppStatuses(deploymentApps []*releaseRepository.ReleaseTrainDeploymentApp) error {
+	updatedApps := failUntriggeredApps(deploymentApps, time.Duration(impl.config.TriggerTimeoutMins)*time.Minute, time.Now())
+	wfrIds := make([]int, 0, len(deploymentApps))
+	for _, deploymentApp := range deploymentApps {
+		if deploymentApp.CdWorkflowRunnerId > 0 && !releaseBean.ReleaseStatus(deploymentApp.Status).IsTerminal() {
+			wfrIds = append(wfrIds, deploymentApp.CdWorkflowRunnerId)
+		}
+	}
+	runnerStatuses := make(map[int]string, len(wfrIds))
+	if len(wfrIds) > 0 {
+		runners, err := impl.cdWorkflowRepository.FetchAllCdStagesLatestEntityStatus(wfrIds)
+		if err != nil {
+			impl.logger.Errorw("error in fetching workflow runner statuses", "wfrIds", wfrIds, "err", err)
+			return err
+		}
+		for _, runner := range runners {
+			runnerStatuses[runner.Id] = runner.Status
+		}
+	}
+	for _, deploymentApp := range deploymentApps {
+		runnerStatus, ok := runnerStatuses[deploymentApp.CdWorkflowRunnerId]
+		if !ok {
+			continue
+		}
+		status := getReleaseStatusFromWfrStatus(runnerStatus)
+		if string(status) != deploymentApp.Status {
+			deploymentApp.Status = string(status)
+			deploymentApp.UpdatedOn = time.Now()
+			updatedApps = append(updatedApps, deploymentApp)
+		}
+	}
+	err := impl.releaseTrainDeploymentRepository.UpdateApps(updatedApps)
+	if err != nil {
+		impl.logger.Errorw("error in updating release train deployment app statuses", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ReleaseTrainServiceImpl) updateDeploymentStatus(deployment *releaseRepository.ReleaseTrainDeployment, appStatuses []releaseBean.ReleaseStatus) error {
+	status := rollupReleaseStatus(appStatuses)
+	if status == releaseBean.ReleaseStatusInitiated || string(status) == deployment.Status {
+		return nil
+	}
+	deployment.Status = string(status)
+	deployment.UpdatedOn = time.Now()
+	err := impl.releaseTrainDeploymentRepository.Update(deployment)
+	if err != nil {
+		impl.logger.Errorw("error in updating release train deployment status", "id", deployment.Id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ReleaseTrainServiceImpl) getAppNames(apps []*releaseRepository.ReleaseTrainApp) (map[int]string, error) {
+	appNames := make(map[int]string, len(apps))
+	appIds := make([]int, 0, len(apps))
+	for _, app := range apps {
+		appIds = append(appIds, app.AppId)
+	}
+	if len(appIds) == 0 {
+		return appNames, nil
+	}
+	appModels, err := impl.appRepository.FindAppAndProjectByIdsIn(appIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching apps", "appIds", appIds, "err", err)
+		return nil, err
+	}
+	for _, app := range appModels {
+		appNames[app.Id] = app.AppName
+	}
+	return appNames, nil
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type ReleaseTrainConfig struct {
+	StatusSyncCron     string `env:"RELEASE_TRAIN_STATUS_SYNC_CRON" envDefault:"*/2 * * * *" description:"Schedule of the job syncing the statuses of release train deployments in progress with their cd workflow runners"`
+	TriggerTimeoutMins int    `env:"RELEASE_TRAIN_TRIGGER_TIMEOUT_MINS" envDefault:"30" description:"Minutes after which an app of a release train deployment not yet triggered is marked failed, releasing its environment"`
+}
+
+type ReleaseStatus string
+
+const (
+	ReleaseStatusInitiated   ReleaseStatus = "Initiated"
+	ReleaseStatusProgressing ReleaseStatus = "Progressing"
+	ReleaseStatusSucceeded   ReleaseStatus = "Succeeded"
+	ReleaseStatusFailed      ReleaseStatus = "Failed"
+	ReleaseStatusPartial     ReleaseStatus = "PartiallySucceeded"
+)
+
+// InProgressReleaseStatuses are the statuses of a release train deployment which is still being synced,
+// an environment can have one such deployment at a time
+var InProgressReleaseStatuses = []string{string(ReleaseStatusInitiated), string(ReleaseStatusProgressing)}
+
+func (status ReleaseStatus) IsTerminal() bool {
+	return status == ReleaseStatusSucceeded || status == ReleaseStatusFailed || status == ReleaseStatusPartial
+}
+
+type AppDiffType string
+
+const (
+	AppDiffAdded     AppDiffType = "ADDED"
+	AppDiffRemoved   AppDiffType = "REMOVED"
+	AppDiffModified  AppDiffType = "MODIFIED"
+	AppDiffUnchanged AppDiffType = "UNCHANGED"
+)
+
+type ReleaseTrainRequest struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name" validate:"required,max=250"`
+	Version     string `json:"version" validate:"required,max=100"`
+	Description string `json:"description"`
+	// SourceEnvironmentId when set, snapshots the artifact and config currently deployed
+	// in this environment for every app which doesn't specify an artifact explicitly
+	SourceEnvironmentId int                       `json:"sourceEnvironmentId"`
+	Apps                []*ReleaseTrainAppRequest `json:"apps" validate:"required,min=1,dive"`
+	UserId              int32                     `json:"-"`
+}
+
+type ReleaseTrainAppRequest struct {
+	AppId        int `json:"appId" validate:"required"`
+	CiArtifactId int `json:"ciArtifactId"`
+}
+
+type ReleaseTrainDto struct {
+	Id          int                   `json:"id"`
+	Name        string                `json:"name"`
+	Version     string                `json:"version"`
+	Description string                `json:"description"`
+	Apps        []*ReleaseTrainAppDto `json:"apps"`
+	CreatedBy   int32                 `json:"createdBy"`
+	CreatedOn   time.Time             `json:"createdOn"`
+}
+
+type ReleaseTrainAppDto struct {
+	AppId            int    `json:"appId"`
+	AppName          string `json:"appName"`
+	CiArtifactId     int    `json:"ciArtifactId"`
+	Image            string `json:"image"`
+	SourcePipelineId int    `json:"sourcePipelineId,omitempty"`
+	SourceWfrId      int    `json:"sourceWfrId,omitempty"`
+}
+
+type ReleaseTrainPromoteRequest struct {
+	ReleaseTrainId int   `json:"releaseTrainId" validate:"required"`
+	EnvironmentId  int   `json:"environmentId" validate:"required"`
+	UserId         int32 `json:"-"`
+}
+
+type ReleaseTrainRollbackRequest struct {
+	EnvironmentId int   `json:"environmentId" validate:"required"`
+	UserId        int32 `json:"-"`
+}
+
+type ReleaseTrainDeploymentDto struct {
+	Id               int                             `json:"id"`
+	ReleaseTrainId   int                             `json:"releaseTrainId"`
+	ReleaseName      string                          `json:"releaseName"`
+	ReleaseVersion   string                          `json:"releaseVersion"`
+	EnvironmentId    int                             `json:"environmentId"`
+	EnvironmentName  string                          `json:"environmentName"`
+	Status           ReleaseStatus                   `json:"status"`
+	IsRollback       bool                            `json:"isRollback"`
+	RollbackSourceId int                             `json:"rollbackSourceId,omitempty"`
+	Apps             []*ReleaseTrainDeploymentAppDto `json:"apps"`
+	TriggeredBy      int32                           `json:"triggeredBy"`
+	TriggeredOn      time.Time                       `json:"triggeredOn"`
+}
+
+type ReleaseTrainDeploymentAppDto struct {
+	AppId              int           `json:"appId"`
+	AppName            string        `json:"appName"`
+	PipelineId         int           `json:"pipelineId"`
+	CiArtifactId       int           `json:"ciArtifactId"`
+	CdWorkflowRunnerId int           `json:"cdWorkflowRunnerId"`
+	Status             ReleaseStatus `json:"status"`
+	Message            string        `json:"message,omitempty"`
+}
+
+type ReleaseTrainDiff struct {
+	Base   *ReleaseTrainDto       `json:"base"`
+	Target *ReleaseTrainDto       `json:"target"`
+	Apps   []*ReleaseTrainAppDiff `json:"apps"`
+}
+
+type ReleaseTrainAppDiff struct {
+	AppId              int         `json:"appId"`
+	AppName            string      `json:"appName"`
+	DiffType           AppDiffType `json:"diffType"`
+	BaseCiArtifactId   int         `json:"baseCiArtifactId,omitempty"`
+	BaseImage          string      `json:"baseImage,omitempty"`
+	TargetCiArtifactId int         `json:"targetCiArtifactId,omitempty"`
+	TargetImage        string      `json:"targetImage,omitempty"`
+	ImageChanged       bool        `json:"imageChanged"`
+	ConfigChanged      bool        `json:"configChanged"`
+}
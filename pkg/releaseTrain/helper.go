@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package releaseTrain
+
+import (
+	"fmt"
+	"github.com/devtron-labs/common-lib/utils/k8s/health"
+	"github.com/devtron-labs/devtron/client/argocdServer/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	releaseBean "github.com/devtron-labs/devtron/pkg/releaseTrain/bean"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain/repository"
+	"github.com/go-pg/pg"
+	"sort"
+	"time"
+)
+
+const inProgressDeploymentUniqueIndex = "release_train_deployment_env_in_progress_unique"
+
+// getReleaseStatusFromWfrStatus maps a cd_workflow_runner status onto the status of an app in a release train deployment
+func getReleaseStatusFromWfrStatus(wfrStatus string) releaseBean.ReleaseStatus {
+	switch wfrStatus {
+	case cdWorkflow.WorkflowSucceeded, string(health.HealthStatusHealthy), bean.HIBERNATING:
+		return releaseBean.ReleaseStatusSucceeded
+	case cdWorkflow.WorkflowFailed, cdWorkflow.WorkflowAborted, string(health.HealthStatusDegraded):
+		return releaseBean.ReleaseStatusFailed
+	default:
+		return releaseBean.ReleaseStatusProgressing
+	}
+}
+
+// failUntriggeredApps marks the apps still waiting for their trigger after the timeout as failed and returns them
+func failUntriggeredApps(deploymentApps []*repository.ReleaseTrainDeploymentApp, timeout time.Duration, now time.Time) []*repository.ReleaseTrainDeploymentApp {
+	failedApps := make([]*repository.ReleaseTrainDeploymentApp, 0)
+	for _, deploymentApp := range deploymentApps {
+		if deploymentApp.CdWorkflowRunnerId > 0 || deploymentApp.Status != string(releaseBean.ReleaseStatusInitiated) {
+			continue
+		}
+		if now.Sub(deploymentApp.UpdatedOn) < timeout {
+			continue
+		}
+		deploymentApp.Status = string(releaseBean.ReleaseStatusFailed)
+		deploymentApp.Message = fmt.Sprintf("app was not triggered within %s", timeout)
+		deploymentApp.UpdatedOn = now
+		failedApps = append(failedApps, deploymentApp)
+	}
+	return failedApps
+}
+
+// rollupReleaseStatus computes the status of a release train deployment from the statuses of its apps
+func rollupReleaseStatus(appStatuses []releaseBean.ReleaseStatus) releaseBean.ReleaseStatus {
+	if len(appStatuses) == 0 {
+		return releaseBean.ReleaseStatusInitiated
+	}
+	succeeded, failed := 0, 0
+	for _, status := range appStatuses {
+		switch status {
+		case releaseBean.ReleaseStatusSucceeded:
+			succeeded++
+		case releaseBean.ReleaseStatusFailed:
+			failed++
+		default:
+			return releaseBean.ReleaseStatusProgressing
+		}
+	}
+	if succeeded == len(appStatuses) {
+		return releaseBean.ReleaseStatusSucceeded
+	} else if failed == len(appStatuses) {
+		return releaseBean.ReleaseStatusFailed
+	}
+	return releaseBean.ReleaseStatusPartial
+}
+
+// diffReleaseTrainApps compares the apps pinned in two release trains, keyed on app id
+func diffReleaseTrainApps(baseApps, targetApps []*releaseBean.ReleaseTrainAppDto) []*releaseBean.ReleaseTrainAppDiff {
+	baseAppMap := make(map[int]*releaseBean.ReleaseTrainAppDto, len(baseApps))
+	for _, app := range baseApps {
+		baseAppMap[app.AppId] = app
+	}
+	diffs := make([]*releaseBean.ReleaseTrainAppDiff, 0, len(baseApps)+len(targetApps))
+	for _, targetApp := range targetApps {
+		diff := &releaseBean.ReleaseTrainAppDiff{
+			AppId:              targetApp.AppId,
+			AppName:            targetApp.AppName,
+			TargetCiArtifactId: targetApp.CiArtifactId,
+			TargetImage:        targetApp.Image,
+		}
+		baseApp, ok := baseAppMap[targetApp.AppId]
+		if !ok {
+			diff.DiffType = releaseBean.AppDiffAdded
+			diffs = append(diffs, diff)
+			continue
+		}
+		delete(baseAppMap, targetApp.AppId)
+		diff.BaseCiArtifactId = baseApp.CiArtifactId
+		diff.BaseImage = baseApp.Image
+		diff.ImageChanged = baseApp.CiArtifactId != targetApp.CiArtifactId
+		diff.ConfigChanged = baseApp.SourcePipelineId != targetApp.SourcePipelineId || baseApp.SourceWfrId != targetApp.SourceWfrId
+		if diff.ImageChanged || diff.ConfigChanged {
+			diff.DiffType = releaseBean.AppDiffModified
+		} else {
+			diff.DiffType = releaseBean.AppDiffUnchanged
+		}
+		diffs = append(diffs, diff)
+	}
+	for _, baseApp := range baseAppMap {
+		diffs = append(diffs, &releaseBean.ReleaseTrainAppDiff{
+			AppId:            baseApp.AppId,
+			AppName:          baseApp.AppName,
+			DiffType:         releaseBean.AppDiffRemoved,
+			BaseCiArtifactId: baseApp.CiArtifactId,
+			BaseImage:        baseApp.Image,
+		})
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].AppName < diffs[j].AppName
+	})
+	return diffs
+}
+
+// getRollbackTarget returns the last succeeded deployment in the environment history which was
+// deployed before the current one and belongs to a different release train.
+// history is expected to be sorted by id in descending order.
+func getRollbackTarget(history []*repository.ReleaseTrainDeployment) *repository.ReleaseTrainDeployment {
+	if len(history) == 0 {
+		return nil
+	}
+	current := history[0]
+	for _, deployment := range history[1:] {
+		if deployment.ReleaseTrainId != current.ReleaseTrainId &&
+			deployment.Status == string(releaseBean.ReleaseStatusSucceeded) {
+			return deployment
+		}
+	}
+	return nil
+}
+
+// isInProgressDeploymentConflict tells if saving a deployment failed as the environment already has one in progress
+func isInProgressDeploymentConflict(err error) bool {
+	pgErr, ok := err.(pg.Error)
+	return ok && pgErr.IntegrityViolation() && pgErr.Field('n') == inProgressDeploymentUniqueIndex
+}
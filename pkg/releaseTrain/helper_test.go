@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package releaseTrain
+
+import (
+	releaseBean "github.com/devtron-labs/devtron/pkg/releaseTrain/bean"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRollupReleaseStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []releaseBean.ReleaseStatus
+		want     releaseBean.ReleaseStatus
+	}{
+		{name: "no apps", statuses: nil, want: releaseBean.ReleaseStatusInitiated},
+		{name: "all succeeded", statuses: []releaseBean.ReleaseStatus{releaseBean.ReleaseStatusSucceeded, releaseBean.ReleaseStatusSucceeded}, want: releaseBean.ReleaseStatusSucceeded},
+		{name: "all failed", statuses: []releaseBean.ReleaseStatus{releaseBean.ReleaseStatusFailed}, want: releaseBean.ReleaseStatusFailed},
+		{name: "one progressing", statuses: []releaseBean.ReleaseStatus{releaseBean.ReleaseStatusSucceeded, releaseBean.ReleaseStatusProgressing}, want: releaseBean.ReleaseStatusProgressing},
+		{name: "mixed terminal", statuses: []releaseBean.ReleaseStatus{releaseBean.ReleaseStatusSucceeded, releaseBean.ReleaseStatusFailed}, want: releaseBean.ReleaseStatusPartial},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rollupReleaseStatus(tt.statuses))
+		})
+	}
+}
+
+func TestFailUntriggeredApps(t *testing.T) {
+	now := time.Now()
+	stale := now.Add(-time.Hour)
+	apps := []*repository.ReleaseTrainDeploymentApp{
+		{Id: 1, Status: string(releaseBean.ReleaseStatusInitiated), AuditLog: sql.AuditLog{UpdatedOn: stale}},
+		{Id: 2, Status: string(releaseBean.ReleaseStatusInitiated), AuditLog: sql.AuditLog{UpdatedOn: now.Add(-time.Minute)}},
+		{Id: 3, Status: string(releaseBean.ReleaseStatusInitiated), CdWorkflowRunnerId: 5, AuditLog: sql.AuditLog{UpdatedOn: stale}},
+		{Id: 4, Status: string(releaseBean.ReleaseStatusProgressing), AuditLog: sql.AuditLog{UpdatedOn: stale}},
+	}
+	failedApps := failUntriggeredApps(apps, 30*time.Minute, now)
+	if assert.Len(t, failedApps, 1) {
+		assert.Equal(t, 1, failedApps[0].Id)
+		assert.Equal(t, string(releaseBean.ReleaseStatusFailed), failedApps[0].Status)
+		assert.NotEmpty(t, failedApps[0].Message)
+	}
+	assert.Equal(t, string(releaseBean.ReleaseStatusInitiated), apps[1].Status)
+}
+
+func TestDiffReleaseTrainApps(t *testing.T) {
+	base := []*releaseBean.ReleaseTrainAppDto{
+		{AppId: 1, AppName: "a", CiArtifactId: 10},
+		{AppId: 2, AppName: "b", CiArtifactId: 20},
+		{AppId: 3, AppName: "c", CiArtifactId: 30, SourceWfrId: 5},
+	}
+	target := []*releaseBean.ReleaseTrainAppDto{
+		{AppId: 1, AppName: "a", CiArtifactId: 11},
+		{AppId: 3, AppName: "c", CiArtifactId: 30, SourceWfrId: 5},
+		{AppId: 4, AppName: "d", CiArtifactId: 40},
+	}
+	diffs := diffReleaseTrainApps(base, target)
+	assert.Len(t, diffs, 4)
+	diffTypes := make(map[int]releaseBean.AppDiffType)
+	for _, diff := range diffs {
+		diffTypes[diff.AppId] = diff.DiffType
+	}
+	assert.Equal(t, releaseBean.AppDiffModified, diffTypes[1])
+	assert.Equal(t, releaseBean.AppDiffRemoved, diffTypes[2])
+	assert.Equal(t, releaseBean.AppDiffUnchanged, diffTypes[3])
+	assert.Equal(t, releaseBean.AppDiffAdded, diffTypes[4])
+}
+
+func TestGetRollbackTarget(t *testing.T) {
+	succeeded := string(releaseBean.ReleaseStatusSucceeded)
+	history := []*repository.ReleaseTrainDeployment{
+		{Id: 4, ReleaseTrainId: 3, Status: succeeded},
+		{Id: 3, ReleaseTrainId: 3, Status: succeeded},
+		{Id: 2, ReleaseTrainId: 2, Status: string(releaseBean.ReleaseStatusFailed)},
+		{Id: 1, ReleaseTrainId: 1, Status: succeeded},
+	}
+	target := getRollbackTarget(history)
+	assert.NotNil(t, target)
+	assert.Equal(t, 1, target.Id)
+	assert.Nil(t, getRollbackTarget(history[:2]))
+}
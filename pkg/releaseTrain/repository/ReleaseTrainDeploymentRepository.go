@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type ReleaseTrainDeployment struct {
+	tableName        struct{} `sql:"release_train_deployment" pg:",discard_unknown_columns"`
+	Id               int      `sql:"id,pk"`
+	ReleaseTrainId   int      `sql:"release_train_id,notnull"`
+	EnvironmentId    int      `sql:"environment_id,notnull"`
+	Status           string   `sql:"status,notnull"`
+	IsRollback       bool     `sql:"is_rollback,notnull"`
+	RollbackSourceId int      `sql:"rollback_source_id"`
+	sql.AuditLog
+}
+
+type ReleaseTrainDeploymentApp struct {
+	tableName                struct{} `sql:"release_train_deployment_app" pg:",discard_unknown_columns"`
+	Id                       int      `sql:"id,pk"`
+	ReleaseTrainDeploymentId int      `sql:"release_train_deployment_id,notnull"`
+	AppId                    int      `sql:"app_id,notnull"`
+	PipelineId               int      `sql:"pipeline_id,notnull"`
+	CiArtifactId             int      `sql:"ci_artifact_id,notnull"`
+	CdWorkflowRunnerId       int      `sql:"cd_workflow_runner_id"`
+	Status                   string   `sql:"status,notnull"`
+	Message                  string   `sql:"message"`
+	sql.AuditLog
+}
+
+type ReleaseTrainDeploymentRepository interface {
+	Save(model *ReleaseTrainDeployment, tx *pg.Tx) error
+	Update(model *ReleaseTrainDeployment) error
+	SaveApps(models []*ReleaseTrainDeploymentApp, tx *pg.Tx) error
+	UpdateApps(models []*ReleaseTrainDeploymentApp) error
+	FindById(id int) (*ReleaseTrainDeployment, error)
+	FindByEnvironmentId(environmentId int) ([]*ReleaseTrainDeployment, error)
+	FindByReleaseTrainId(releaseTrainId int) ([]*ReleaseTrainDeployment, error)
+	FindByStatuses(statuses []string) ([]*ReleaseTrainDeployment, error)
+	FindAppsByDeploymentIds(deploymentIds []int) ([]*ReleaseTrainDeploymentApp, error)
+}
+
+type ReleaseTrainDeploymentRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewReleaseTrainDeploymentRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ReleaseTrainDeploymentRepositoryImpl {
+	return &ReleaseTrainDeploymentRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ReleaseTrainDeploymentRepositoryImpl) Save(model *ReleaseTrainDeployment, tx *pg.Tx) error {
+	return tx.Insert(model)
+}
+
+func (impl *ReleaseTrainDeploymentRepositoryImpl) Update(model *ReleaseTrainDeployment) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *ReleaseTrainDeploymentRepositoryImpl) SaveApps(models []*ReleaseTrainDeploymentApp, tx *pg.Tx) error {
+	if len(models) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&models).Insert()
+	return err
+}
+
+func (impl *ReleaseTrainDeploymentRepositoryImpl) UpdateApps(models []*ReleaseTrainDeploymentApp) error {
+	if len(models) == 0 {
+		return nil
+	}
+	_, err := impl.dbConnection.Model(&models).Update()
+	return err
+}
+
+func (impl *ReleaseTrainDeploymentRepositoryImpl) FindById(id int) (*ReleaseTrainDeployment, error) {
+	model := &ReleaseTrainDeployment{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Select()
+	return model, err
+}
+
+func (impl *ReleaseTrainDeploymentRepositoryImpl) FindByEnvironmentId(environmentId int) ([]*ReleaseTrainDeployment, error) {
+	var models []*ReleaseTrainDeployment
+	err := impl.dbConnection.Model(&models).
+		Where("environment_id = ?", environmentId).
+		Order("id DESC").
+		Select()
+	return models, err
+}
+
+func (impl *ReleaseTrainDeploymentRepositoryImpl) FindByReleaseTrainId(releaseTrainId int) ([]*ReleaseTrainDeployment, error) {
+	var models []*ReleaseTrainDeployment
+	err := impl.dbConnection.Model(&models).
+		Where("release_train_id = ?", releaseTrainId).
+		Order("id DESC").
+		Select()
+	return models, err
+}
+
+func (impl *ReleaseTrainDeploymentRepositoryImpl) FindByStatuses(statuses []string) ([]*ReleaseTrainDeployment, error) {
+	var models []*ReleaseTrainDeployment
+	if len(statuses) == 0 {
+		return models, nil
+	}
+	err := impl.dbConnection.Model(&models).
+		Where("status IN (?)", pg.In(statuses)).
+		Order("id ASC").
+		Select()
+	return models, err
+}
+
+func (impl *ReleaseTrainDeploymentRepositoryImpl) FindAppsByDeploymentIds(deploymentIds []int) ([]*ReleaseTrainDeploymentApp, error) {
+	var models []*ReleaseTrainDeploymentApp
+	if len(deploymentIds) == 0 {
+		return models, nil
+	}
+	err := impl.dbConnection.Model(&models).
+		Where("release_train_deployment_id IN (?)", pg.In(deploymentIds)).
+		Order("id ASC").
+		Select()
+	return models, err
+}
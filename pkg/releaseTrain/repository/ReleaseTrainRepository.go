@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type ReleaseTrain struct {
+	tableName   struct{} `sql:"release_train" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	Name        string   `sql:"name,notnull"`
+	Version     string   `sql:"version,notnull"`
+	Description string   `sql:"description"`
+	Active      bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type ReleaseTrainApp struct {
+	tableName        struct{} `sql:"release_train_app" pg:",discard_unknown_columns"`
+	Id               int      `sql:"id,pk"`
+	ReleaseTrainId   int      `sql:"release_train_id,notnull"`
+	AppId            int      `sql:"app_id,notnull"`
+	CiArtifactId     int      `sql:"ci_artifact_id,notnull"`
+	SourcePipelineId int      `sql:"source_pipeline_id"`
+	SourceWfrId      int      `sql:"source_wfr_id"`
+	sql.AuditLog
+}
+
+type ReleaseTrainRepository interface {
+	sql.TransactionWrapper
+	Save(model *ReleaseTrain, tx *pg.Tx) error
+	Update(model *ReleaseTrain) error
+	SaveApps(models []*ReleaseTrainApp, tx *pg.Tx) error
+	FindById(id int) (*ReleaseTrain, error)
+	// FindByIdIncludingInactive also returns deleted release trains, their deployments stay in the release history
+	FindByIdIncludingInactive(id int) (*ReleaseTrain, error)
+	FindAllActive() ([]*ReleaseTrain, error)
+	ExistsByNameAndVersion(name, version string) (bool, error)
+	FindAppsByReleaseTrainIds(releaseTrainIds []int) ([]*ReleaseTrainApp, error)
+}
+
+type ReleaseTrainRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewReleaseTrainRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger,
+	transactionUtilImpl *sql.TransactionUtilImpl) *ReleaseTrainRepositoryImpl {
+	return &ReleaseTrainRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: transactionUtilImpl,
+	}
+}
+
+func (impl *ReleaseTrainRepositoryImpl) Save(model *ReleaseTrain, tx *pg.Tx) error {
+	return tx.Insert(model)
+}
+
+func (impl *ReleaseTrainRepositoryImpl) Update(model *ReleaseTrain) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *ReleaseTrainRepositoryImpl) SaveApps(models []*ReleaseTrainApp, tx *pg.Tx) error {
+	if len(models) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&models).Insert()
+	return err
+}
+
+func (impl *ReleaseTrainRepositoryImpl) FindById(id int) (*ReleaseTrain, error) {
+	model := &ReleaseTrain{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *ReleaseTrainRepositoryImpl) FindByIdIncludingInactive(id int) (*ReleaseTrain, error) {
+	model := &ReleaseTrain{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Select()
+	return model, err
+}
+
+func (impl *ReleaseTrainRepositoryImpl) FindAllActive() ([]*ReleaseTrain, error) {
+	var models []*ReleaseTrain
+	err := impl.dbConnection.Model(&models).
+		Where("active = ?", true).
+		Order("id DESC").
+		Select()
+	return models, err
+}
+
+func (impl *ReleaseTrainRepositoryImpl) ExistsByNameAndVersion(name, version string) (bool, error) {
+	return impl.dbConnection.Model((*ReleaseTrain)(nil)).
+		Where("name = ?", name).
+		Where("version = ?", version).
+		Where("active = ?", true).
+		Exists()
+}
+
+func (impl *ReleaseTrainRepositoryImpl) FindAppsByReleaseTrainIds(releaseTrainIds []int) ([]*ReleaseTrainApp, error) {
+	var models []*ReleaseTrainApp
+	if len(releaseTrainIds) == 0 {
+		return models, nil
+	}
+	err := impl.dbConnection.Model(&models).
+		Where("release_train_id IN (?)", pg.In(releaseTrainIds)).
+		Order("id ASC").
+		Select()
+	return models, err
+}
@@ -0,0 +1,326 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourceSearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	k8sUtil "github.com/devtron-labs/common-lib/utils/k8s"
+	celUtil "github.com/devtron-labs/devtron/cel"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/resourceSearch/bean"
+	"github.com/devtron-labs/devtron/pkg/resourceSearch/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type ResourceSearchService interface {
+	// Search lists the resources matching the query from the clusters in parallel, the resources authorise
+	// rejects are left out and the clusters which can't be searched are reported in the response
+	Search(ctx context.Context, query *bean.ResourceSearchQuery, authorise func(clusterName string, resourceIdentifier k8sUtil.ResourceIdentifier) bool) (*bean.ResourceSearchResponse, error)
+	GetSavedQueries(userId int32) ([]*bean.SavedQueryDto, error)
+	GetSavedQuery(id int, userId int32) (*bean.SavedQueryDto, error)
+	// SaveQuery creates the query or updates it when it has an id, names are unique for a user
+	SaveQuery(request *bean.SavedQueryDto) (*bean.SavedQueryDto, error)
+	DeleteSavedQuery(id int, userId int32) error
+}
+
+type ResourceSearchServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	savedResourceQueryRepository repository.SavedResourceQueryRepository
+	clusterService               cluster.ClusterService
+	k8sCommonService             k8s.K8sCommonService
+	k8sUtil                      *k8sUtil.K8sServiceImpl
+	celEvaluatorService          celUtil.EvaluatorService
+	config                       *bean.ResourceSearchConfig
+}
+
+func NewResourceSearchServiceImpl(logger *zap.SugaredLogger,
+	savedResourceQueryRepository repository.SavedResourceQueryRepository,
+	clusterService cluster.ClusterService,
+	k8sCommonService k8s.K8sCommonService,
+	k8sUtil *k8sUtil.K8sServiceImpl,
+	celEvaluatorService celUtil.EvaluatorService) (*ResourceSearchServiceImpl, error) {
+	config := &bean.ResourceSearchConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing resource search config", "err", err)
+		return nil, err
+	}
+	return &ResourceSearchServiceImpl{
+		logger:                       logger,
+		savedResourceQueryRepository: savedResourceQueryRepository,
+		clusterService:               clusterService,
+		k8sCommonService:             k8sCommonService,
+		k8sUtil:                      k8sUtil,
+		celEvaluatorService:          celEvaluatorService,
+		config:                       config,
+	}, nil
+}
+
+func (impl *ResourceSearchServiceImpl) Search(ctx context.Context, query *bean.ResourceSearchQuery, authorise func(clusterName string, resourceIdentifier k8sUtil.ResourceIdentifier) bool) (*bean.ResourceSearchResponse, error) {
+	// the filters are checked once here so that a bad query fails before any cluster is called
+	_, err := parseJsonPath(query.JsonPath)
+	if err != nil {
+		impl.logger.Errorw("error in parsing json path of resource search", "jsonPath", query.JsonPath, "err", err)
+		return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("invalid json path: %s", err.Error()), err.Error())
+	}
+	program, err := compileExpression(impl.celEvaluatorService, query.Expression)
+	if err != nil {
+		impl.logger.Errorw("error in compiling expression of resource search", "expression", query.Expression, "err", err)
+		return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("invalid expression: %s", err.Error()), err.Error())
+	}
+	clusters, err := impl.getClustersToSearch(query.ClusterIds)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &bean.ResourceSearchResponse{
+		Items:            make([]*bean.ResourceSearchItem, 0),
+		ClusterErrors:    make([]*bean.ClusterSearchError, 0),
+		SearchedClusters: len(clusters),
+	}
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	concurrency := impl.config.ClusterConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	semaphore := make(chan struct{}, concurrency)
+	for i := range clusters {
+		clusterDetail := &clusters[i]
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			// the json path parser keeps state while finding results, so it is not shared between clusters
+			jsonPath, _ := parseJsonPath(query.JsonPath)
+			filter := &objectFilter{jsonPath: jsonPath, program: program}
+			items, err := impl.searchCluster(ctx, clusterDetail, query, filter, authorise)
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				response.ClusterErrors = append(response.ClusterErrors, &bean.ClusterSearchError{
+					ClusterId:   clusterDetail.Id,
+					ClusterName: clusterDetail.ClusterName,
+					Error:       err.Error(),
+				})
+				return
+			}
+			response.Items = append(response.Items, items...)
+		}()
+	}
+	wg.Wait()
+
+	sortSearchItems(response.Items)
+	if len(response.Items) > impl.config.MaxResults {
+		response.Items = response.Items[:impl.config.MaxResults]
+		response.Truncated = true
+	}
+	return response, nil
+}
+
+func (impl *ResourceSearchServiceImpl) getClustersToSearch(clusterIds []int) ([]clusterBean.ClusterBean, error) {
+	clusters, err := impl.clusterService.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in getting active clusters", "err", err)
+		return nil, err
+	}
+	requested := sets.NewInt(clusterIds...)
+	var result []clusterBean.ClusterBean
+	for _, clusterDetail := range clusters {
+		if clusterDetail.IsVirtualCluster {
+			continue
+		}
+		if requested.Len() > 0 && !requested.Has(clusterDetail.Id) {
+			continue
+		}
+		result = append(result, clusterDetail)
+	}
+	return result, nil
+}
+
+func (impl *ResourceSearchServiceImpl) searchCluster(ctx context.Context, clusterDetail *clusterBean.ClusterBean, query *bean.ResourceSearchQuery,
+	filter *objectFilter, authorise func(clusterName string, resourceIdentifier k8sUtil.ResourceIdentifier) bool) ([]*bean.ResourceSearchItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(impl.config.ClusterTimeoutSeconds)*time.Second)
+	defer cancel()
+	restConfig, _, _, err := impl.k8sCommonService.GetK8sConfigAndClients(ctx, clusterDetail)
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s config of cluster for resource search", "clusterId", clusterDetail.Id, "err", err)
+		return nil, err
+	}
+	gvk := schema.GroupVersionKind{Group: query.Group, Version: query.Version, Kind: query.Kind}
+	listOptions := &metav1.ListOptions{LabelSelector: query.LabelSelector, FieldSelector: query.FieldSelector}
+	resp, _, err := impl.k8sUtil.GetResourceList(ctx, restConfig, gvk, query.Namespace, false, listOptions)
+	if err != nil {
+		impl.logger.Errorw("error in listing resources for resource search", "clusterId", clusterDetail.Id, "gvk", gvk, "err", err)
+		return nil, err
+	}
+	var items []*bean.ResourceSearchItem
+	for i := range resp.Resources.Items {
+		object := &resp.Resources.Items[i]
+		resourceIdentifier := k8sUtil.ResourceIdentifier{
+			Name:             object.GetName(),
+			Namespace:        object.GetNamespace(),
+			GroupVersionKind: gvk,
+		}
+		if authorise != nil && !authorise(clusterDetail.ClusterName, resourceIdentifier) {
+			continue
+		}
+		matched, jsonPathValues := filter.matches(object.Object)
+		if !matched {
+			continue
+		}
+		items = append(items, newSearchItem(clusterDetail.Id, clusterDetail.ClusterName, object, jsonPathValues))
+	}
+	return items, nil
+}
+
+func (impl *ResourceSearchServiceImpl) GetSavedQueries(userId int32) ([]*bean.SavedQueryDto, error) {
+	models, err := impl.savedResourceQueryRepository.FindByUserId(userId)
+	if err != nil {
+		impl.logger.Errorw("error in getting saved resource queries", "userId", userId, "err", err)
+		return nil, err
+	}
+	dtos := make([]*bean.SavedQueryDto, 0, len(models))
+	for _, model := range models {
+		dto, err := toSavedQueryDto(model)
+		if err != nil {
+			impl.logger.Errorw("error in reading saved resource query", "id", model.Id, "err", err)
+			return nil, err
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos, nil
+}
+
+func (impl *ResourceSearchServiceImpl) GetSavedQuery(id int, userId int32) (*bean.SavedQueryDto, error) {
+	model, err := impl.getSavedQueryModel(id, userId)
+	if err != nil {
+		return nil, err
+	}
+	return toSavedQueryDto(model)
+}
+
+func (impl *ResourceSearchServiceImpl) getSavedQueryModel(id int, userId int32) (*repository.SavedResourceQuery, error) {
+	model, err := impl.savedResourceQueryRepository.FindByIdAndUserId(id, userId)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "saved query not found", fmt.Sprintf("no saved resource query %d for user %d", id, userId))
+	} else if err != nil {
+		impl.logger.Errorw("error in getting saved resource query", "id", id, "userId", userId, "err", err)
+		return nil, err
+	}
+	return model, nil
+}
+
+func (impl *ResourceSearchServiceImpl) SaveQuery(request *bean.SavedQueryDto) (*bean.SavedQueryDto, error) {
+	_, err := parseJsonPath(request.Query.JsonPath)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("invalid json path: %s", err.Error()), err.Error())
+	}
+	_, err = compileExpression(impl.celEvaluatorService, request.Query.Expression)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("invalid expression: %s", err.Error()), err.Error())
+	}
+	existing, err := impl.savedResourceQueryRepository.FindByNameAndUserId(request.Name, request.UserId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting saved resource query by name", "name", request.Name, "err", err)
+		return nil, err
+	}
+	if err == nil && existing.Id != request.Id {
+		return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("a query named %s is already saved", request.Name), "duplicate saved resource query name")
+	}
+	query, err := json.Marshal(request.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	model := &repository.SavedResourceQuery{}
+	if request.Id > 0 {
+		model, err = impl.getSavedQueryModel(request.Id, request.UserId)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		model.Active = true
+		model.AuditLog = sql.AuditLog{CreatedBy: request.UserId, CreatedOn: now}
+	}
+	model.Name = request.Name
+	model.Description = request.Description
+	model.Query = string(query)
+	model.UpdatedBy = request.UserId
+	model.UpdatedOn = now
+	if model.Id > 0 {
+		err = impl.savedResourceQueryRepository.Update(model)
+	} else {
+		err = impl.savedResourceQueryRepository.Save(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving resource query", "name", request.Name, "err", err)
+		return nil, err
+	}
+	return toSavedQueryDto(model)
+}
+
+func (impl *ResourceSearchServiceImpl) DeleteSavedQuery(id int, userId int32) error {
+	model, err := impl.getSavedQueryModel(id, userId)
+	if err != nil {
+		return err
+	}
+	model.Active = false
+	model.UpdatedBy = userId
+	model.UpdatedOn = time.Now()
+	err = impl.savedResourceQueryRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in deleting saved resource query", "id", id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func toSavedQueryDto(model *repository.SavedResourceQuery) (*bean.SavedQueryDto, error) {
+	query := &bean.ResourceSearchQuery{}
+	err := json.Unmarshal([]byte(model.Query), query)
+	if err != nil {
+		return nil, err
+	}
+	return &bean.SavedQueryDto{
+		Id:          model.Id,
+		Name:        model.Name,
+		Description: model.Description,
+		Query:       query,
+		UpdatedOn:   model.UpdatedOn,
+		UserId:      model.CreatedBy,
+	}, nil
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"time"
+)
+
+type ResourceSearchConfig struct {
+	ClusterConcurrency    int `env:"RESOURCE_SEARCH_CLUSTER_CONCURRENCY" envDefault:"10" description:"Clusters searched in parallel by a resource search"`
+	ClusterTimeoutSeconds int `env:"RESOURCE_SEARCH_CLUSTER_TIMEOUT_SECONDS" envDefault:"30" description:"Time a cluster has to list the resources of a search, clusters taking longer are reported with an error"`
+	MaxResults            int `env:"RESOURCE_SEARCH_MAX_RESULTS" envDefault:"5000" description:"Resources returned by a search, the rest are dropped and the response is marked truncated"`
+}
+
+const (
+	ExportFormatJson = "json"
+	ExportFormatCsv  = "csv"
+	// ObjectVariable is the variable the object is bound to in search expressions
+	ObjectVariable = "object"
+)
+
+type ResourceSearchQuery struct {
+	// ClusterIds are the clusters searched, all the active clusters when empty
+	ClusterIds []int  `json:"clusterIds"`
+	Group      string `json:"group"`
+	Version    string `json:"version" validate:"required"`
+	Kind       string `json:"kind" validate:"required"`
+	// Namespace is the namespace searched, all namespaces when empty
+	Namespace     string `json:"namespace"`
+	LabelSelector string `json:"labelSelector"`
+	FieldSelector string `json:"fieldSelector"`
+	// JsonPath keeps the objects the path finds a value in, like {.spec.template.spec.containers[*].image},
+	// the values found are returned with the objects
+	JsonPath string `json:"jsonPath"`
+	// Expression is a CEL expression over the object which keeps the objects it is true for,
+	// like object.data.exists(key, key.startsWith('DB_'))
+	Expression string `json:"expression"`
+}
+
+type ResourceSearchItem struct {
+	ClusterId      int               `json:"clusterId"`
+	ClusterName    string            `json:"clusterName"`
+	Namespace      string            `json:"namespace,omitempty"`
+	Name           string            `json:"name"`
+	ApiVersion     string            `json:"apiVersion"`
+	Kind           string            `json:"kind"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	CreatedOn      time.Time         `json:"createdOn"`
+	JsonPathValues []string          `json:"jsonPathValues,omitempty"`
+}
+
+type ClusterSearchError struct {
+	ClusterId   int    `json:"clusterId"`
+	ClusterName string `json:"clusterName"`
+	Error       string `json:"error"`
+}
+
+type ResourceSearchResponse struct {
+	Items            []*ResourceSearchItem `json:"items"`
+	SearchedClusters int                   `json:"searchedClusters"`
+	// ClusterErrors are the clusters the resources couldn't be listed from, the search goes on with the others
+	ClusterErrors []*ClusterSearchError `json:"clusterErrors"`
+	// Truncated is set when more than RESOURCE_SEARCH_MAX_RESULTS resources matched
+	Truncated bool `json:"truncated"`
+}
+
+type SavedQueryDto struct {
+	Id          int                  `json:"id"`
+	Name        string               `json:"name" validate:"required,max=100"`
+	Description string               `json:"description" validate:"max=300"`
+	Query       *ResourceSearchQuery `json:"query" validate:"required"`
+	UpdatedOn   time.Time            `json:"updatedOn"`
+	UserId      int32                `json:"-"`
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourceSearch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	celUtil "github.com/devtron-labs/devtron/cel"
+	"github.com/devtron-labs/devtron/pkg/resourceSearch/bean"
+	"github.com/google/cel-go/cel"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+var csvHeader = []string{"cluster", "namespace", "name", "apiVersion", "kind", "labels", "createdOn", "jsonPathValues"}
+
+// objectFilter keeps the objects matching the json path and the expression of a query,
+// a json path parser is not safe for concurrent use so every cluster search builds its own filter
+type objectFilter struct {
+	jsonPath *jsonpath.JSONPath
+	program  cel.Program
+}
+
+// toJsonPathTemplate accepts paths with and without the surrounding braces, like kubectl does
+func toJsonPathTemplate(path string) string {
+	path = strings.TrimSpace(path)
+	if strings.HasPrefix(path, "{") {
+		return path
+	}
+	if !strings.HasPrefix(path, ".") {
+		path = "." + path
+	}
+	return fmt.Sprintf("{%s}", path)
+}
+
+func parseJsonPath(path string) (*jsonpath.JSONPath, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+	parser := jsonpath.New("resourceSearch").AllowMissingKeys(true)
+	err := parser.Parse(toJsonPathTemplate(path))
+	if err != nil {
+		return nil, err
+	}
+	return parser, nil
+}
+
+func compileExpression(evaluator celUtil.EvaluatorService, expression string) (cel.Program, error) {
+	if len(expression) == 0 {
+		return nil, nil
+	}
+	request := celUtil.Request{
+		Expression: expression,
+		ExpressionMetadata: celUtil.ExpressionMetadata{
+			Params: []celUtil.ExpressionParam{{ParamName: bean.ObjectVariable, Type: celUtil.ParamTypeObject}},
+		},
+	}
+	ast, env, err := evaluator.Validate(request)
+	if err != nil {
+		return nil, err
+	}
+	return env.Program(ast)
+}
+
+// matches tells if the object passes the filter and returns the values the json path found in it,
+// objects the expression fails to evaluate on, like when it reads a missing field, don't match
+func (filter *objectFilter) matches(object map[string]interface{}) (bool, []string) {
+	var values []string
+	if filter.jsonPath != nil {
+		results, err := filter.jsonPath.FindResults(object)
+		if err != nil {
+			return false, nil
+		}
+		for _, result := range results {
+			for _, value := range result {
+				if !value.IsValid() || !value.CanInterface() || value.Interface() == nil {
+					continue
+				}
+				values = append(values, formatJsonPathValue(value.Interface()))
+			}
+		}
+		if len(values) == 0 {
+			return false, nil
+		}
+	}
+	if filter.program != nil {
+		out, _, err := filter.program.Eval(map[string]interface{}{bean.ObjectVariable: object})
+		if err != nil {
+			return false, nil
+		}
+		if matched, ok := out.Value().(bool); !ok || !matched {
+			return false, nil
+		}
+	}
+	return true, values
+}
+
+func formatJsonPathValue(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	marshalled, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(marshalled)
+}
+
+func newSearchItem(clusterId int, clusterName string, object *unstructured.Unstructured, jsonPathValues []string) *bean.ResourceSearchItem {
+	return &bean.ResourceSearchItem{
+		ClusterId:      clusterId,
+		ClusterName:    clusterName,
+		Namespace:      object.GetNamespace(),
+		Name:           object.GetName(),
+		ApiVersion:     object.GetAPIVersion(),
+		Kind:           object.GetKind(),
+		Labels:         object.GetLabels(),
+		CreatedOn:      object.GetCreationTimestamp().Time,
+		JsonPathValues: jsonPathValues,
+	}
+}
+
+func sortSearchItems(items []*bean.ResourceSearchItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].ClusterName != items[j].ClusterName {
+			return items[i].ClusterName < items[j].ClusterName
+		}
+		if items[i].Namespace != items[j].Namespace {
+			return items[i].Namespace < items[j].Namespace
+		}
+		return items[i].Name < items[j].Name
+	})
+}
+
+// WriteCsv writes the items with a header row, labels are written as k=v pairs and json path values are joined by ;
+func WriteCsv(writer io.Writer, items []*bean.ResourceSearchItem) error {
+	csvWriter := csv.NewWriter(writer)
+	err := csvWriter.Write(csvHeader)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		labels := make([]string, 0, len(item.Labels))
+		for key, value := range item.Labels {
+			labels = append(labels, key+"="+value)
+		}
+		sort.Strings(labels)
+		err = csvWriter.Write([]string{
+			item.ClusterName,
+			item.Namespace,
+			item.Name,
+			item.ApiVersion,
+			item.Kind,
+			strings.Join(labels, ","),
+			item.CreatedOn.UTC().Format(time.RFC3339),
+			strings.Join(item.JsonPathValues, ";"),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func CsvFileName(query *bean.ResourceSearchQuery) string {
+	return fmt.Sprintf("%s-%s.csv", strings.ToLower(query.Kind), strconv.FormatInt(time.Now().Unix(), 10))
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourceSearch
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	celUtil "github.com/devtron-labs/devtron/cel"
+	"github.com/devtron-labs/devtron/pkg/resourceSearch/bean"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newConfigMap(name string, data map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "default",
+			"labels":    map[string]interface{}{"team": "payments", "app": name},
+		},
+		"data": data,
+	}}
+}
+
+func TestToJsonPathTemplate(t *testing.T) {
+	assert.Equal(t, "{.data.key}", toJsonPathTemplate("{.data.key}"))
+	assert.Equal(t, "{.data.key}", toJsonPathTemplate(".data.key"))
+	assert.Equal(t, "{.data.key}", toJsonPathTemplate(" data.key "))
+}
+
+func TestObjectFilterMatches(t *testing.T) {
+	evaluator := celUtil.NewCELServiceImpl(zap.NewNop().Sugar())
+	withDb := newConfigMap("with-db", map[string]interface{}{"DB_HOST": "postgres", "PORT": "8080"}).Object
+	withoutDb := newConfigMap("without-db", map[string]interface{}{"PORT": "8080"}).Object
+
+	t.Run("no filter matches everything", func(t *testing.T) {
+		matched, values := (&objectFilter{}).matches(withoutDb)
+		assert.True(t, matched)
+		assert.Empty(t, values)
+	})
+
+	t.Run("json path matches objects it finds values in", func(t *testing.T) {
+		jsonPath, err := parseJsonPath(".data.DB_HOST")
+		assert.Nil(t, err)
+		filter := &objectFilter{jsonPath: jsonPath}
+		matched, values := filter.matches(withDb)
+		assert.True(t, matched)
+		assert.Equal(t, []string{"postgres"}, values)
+		matched, _ = filter.matches(withoutDb)
+		assert.False(t, matched)
+	})
+
+	t.Run("json path formats non string values as json", func(t *testing.T) {
+		jsonPath, err := parseJsonPath("{.metadata.labels}")
+		assert.Nil(t, err)
+		_, values := (&objectFilter{jsonPath: jsonPath}).matches(withDb)
+		assert.Equal(t, []string{`{"app":"with-db","team":"payments"}`}, values)
+	})
+
+	t.Run("expression matches objects it is true for", func(t *testing.T) {
+		program, err := compileExpression(evaluator, "object.data.exists(key, key.startsWith('DB_'))")
+		assert.Nil(t, err)
+		filter := &objectFilter{program: program}
+		matched, _ := filter.matches(withDb)
+		assert.True(t, matched)
+		matched, _ = filter.matches(withoutDb)
+		assert.False(t, matched)
+	})
+
+	t.Run("expression failing to evaluate does not match", func(t *testing.T) {
+		program, err := compileExpression(evaluator, "object.spec.replicas > 1")
+		assert.Nil(t, err)
+		matched, _ := (&objectFilter{program: program}).matches(withDb)
+		assert.False(t, matched)
+	})
+
+	t.Run("invalid filters are rejected", func(t *testing.T) {
+		_, err := parseJsonPath("{.data[")
+		assert.NotNil(t, err)
+		_, err = compileExpression(evaluator, "object.data ==")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestWriteCsv(t *testing.T) {
+	createdOn := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	items := []*bean.ResourceSearchItem{
+		newSearchItem(2, "prod", newConfigMap("b", nil), []string{"x", "y"}),
+		newSearchItem(1, "dev", newConfigMap("a", nil), nil),
+	}
+	for _, item := range items {
+		item.CreatedOn = createdOn
+	}
+	sortSearchItems(items)
+	assert.Equal(t, "dev", items[0].ClusterName)
+
+	buffer := &bytes.Buffer{}
+	err := WriteCsv(buffer, items)
+	assert.Nil(t, err)
+	expected := "cluster,namespace,name,apiVersion,kind,labels,createdOn,jsonPathValues\n" +
+		"dev,default,a,v1,ConfigMap,\"app=a,team=payments\",2024-05-01T10:00:00Z,\n" +
+		"prod,default,b,v1,ConfigMap,\"app=b,team=payments\",2024-05-01T10:00:00Z,x;y\n"
+	assert.Equal(t, expected, buffer.String())
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// SavedResourceQuery is a resource search saved by a user, the query is stored as json
+type SavedResourceQuery struct {
+	tableName   struct{} `sql:"saved_resource_query" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	Name        string   `sql:"name,notnull"`
+	Description string   `sql:"description"`
+	Query       string   `sql:"query,notnull"`
+	Active      bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type SavedResourceQueryRepository interface {
+	Save(model *SavedResourceQuery) error
+	Update(model *SavedResourceQuery) error
+	FindByIdAndUserId(id int, userId int32) (*SavedResourceQuery, error)
+	FindByNameAndUserId(name string, userId int32) (*SavedResourceQuery, error)
+	FindByUserId(userId int32) ([]*SavedResourceQuery, error)
+}
+
+type SavedResourceQueryRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewSavedResourceQueryRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *SavedResourceQueryRepositoryImpl {
+	return &SavedResourceQueryRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *SavedResourceQueryRepositoryImpl) Save(model *SavedResourceQuery) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *SavedResourceQueryRepositoryImpl) Update(model *SavedResourceQuery) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *SavedResourceQueryRepositoryImpl) FindByIdAndUserId(id int, userId int32) (*SavedResourceQuery, error) {
+	model := &SavedResourceQuery{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Where("created_by = ?", userId).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *SavedResourceQueryRepositoryImpl) FindByNameAndUserId(name string, userId int32) (*SavedResourceQuery, error) {
+	model := &SavedResourceQuery{}
+	err := impl.dbConnection.Model(model).
+		Where("name = ?", name).
+		Where("created_by = ?", userId).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *SavedResourceQueryRepositoryImpl) FindByUserId(userId int32) ([]*SavedResourceQuery, error) {
+	var models []*SavedResourceQuery
+	err := impl.dbConnection.Model(&models).
+		Where("created_by = ?", userId).
+		Where("active = ?", true).
+		Order("name").
+		Select()
+	return models, err
+}
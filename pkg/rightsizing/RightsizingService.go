@@ -0,0 +1,401 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rightsizing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	k8sUtil "github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	"github.com/devtron-labs/devtron/pkg/rightsizing/bean"
+	"github.com/devtron-labs/devtron/pkg/rightsizing/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+const (
+	sampleBatchSize        = 500
+	clusterSamplingTimeout = time.Minute
+	retentionCron          = "0 * * * *"
+)
+
+type RightsizingService interface {
+	// SampleUsage samples the usage of the running containers of every reachable cluster
+	SampleUsage()
+	DeleteExpiredSamples()
+	// GetReport recommends requests and limits for the containers of the workloads running in a cluster, namespace is optional
+	GetReport(clusterId int, namespace string) (*bean.RightsizingReport, error)
+	GetRecommendation(identifier *bean.WorkloadIdentifier) (*bean.WorkloadRecommendation, error)
+	// ApplyRecommendation sets the recommended resources in the deployment template of the app on the environment,
+	// they are rolled out with the next deployment
+	ApplyRecommendation(ctx context.Context, recommendation *bean.WorkloadRecommendation, userId int32) (*bean.ApplyRecommendationResponse, error)
+	GetPrices(clusterId int) ([]*bean.NodeGroupPriceDto, error)
+	// SavePrice creates the price of a node group or updates it when the node group has one
+	SavePrice(request *bean.NodeGroupPriceDto) (*bean.NodeGroupPriceDto, error)
+	DeletePrice(id int, userId int32) error
+}
+
+type RightsizingServiceImpl struct {
+	logger                              *zap.SugaredLogger
+	sampleRepository                    repository.WorkloadResourceSampleRepository
+	priceRepository                     repository.NodeGroupPriceRepository
+	clusterService                      cluster.ClusterService
+	k8sCommonService                    k8s.K8sCommonService
+	k8sUtil                             *k8sUtil.K8sServiceImpl
+	propertiesConfigService             pipeline.PropertiesConfigService
+	deploymentTemplateValidationService deploymentTemplate.DeploymentTemplateValidationService
+	config                              *bean.RightsizingConfig
+	samplingLock                        sync.Mutex
+}
+
+func NewRightsizingServiceImpl(logger *zap.SugaredLogger,
+	sampleRepository repository.WorkloadResourceSampleRepository,
+	priceRepository repository.NodeGroupPriceRepository,
+	clusterService cluster.ClusterService,
+	k8sCommonService k8s.K8sCommonService,
+	k8sUtil *k8sUtil.K8sServiceImpl,
+	propertiesConfigService pipeline.PropertiesConfigService,
+	deploymentTemplateValidationService deploymentTemplate.DeploymentTemplateValidationService,
+	cronLogger *cron2.CronLoggerImpl) (*RightsizingServiceImpl, error) {
+	config := &bean.RightsizingConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing rightsizing config", "err", err)
+		return nil, err
+	}
+	impl := &RightsizingServiceImpl{
+		logger:                              logger,
+		sampleRepository:                    sampleRepository,
+		priceRepository:                     priceRepository,
+		clusterService:                      clusterService,
+		k8sCommonService:                    k8sCommonService,
+		k8sUtil:                             k8sUtil,
+		propertiesConfigService:             propertiesConfigService,
+		deploymentTemplateValidationService: deploymentTemplateValidationService,
+		config:                              config,
+	}
+	samplingCron := cron.New(cron.WithChain(cron.Recover(cronLogger)))
+	_, err = samplingCron.AddFunc(config.SamplingCron, impl.SampleUsage)
+	if err != nil {
+		logger.Errorw("error in adding rightsizing sampling cron", "schedule", config.SamplingCron, "err", err)
+		return nil, err
+	}
+	_, err = samplingCron.AddFunc(retentionCron, impl.DeleteExpiredSamples)
+	if err != nil {
+		logger.Errorw("error in adding rightsizing retention cron", "err", err)
+		return nil, err
+	}
+	samplingCron.Start()
+	return impl, nil
+}
+
+func (impl *RightsizingServiceImpl) SampleUsage() {
+	// a run still sampling slow clusters is not overlapped
+	if !impl.samplingLock.TryLock() {
+		impl.logger.Infow("previous rightsizing sampling run is in progress, skipping")
+		return
+	}
+	defer impl.samplingLock.Unlock()
+	clusters, err := impl.clusterService.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in fetching active clusters", "err", err)
+		return
+	}
+	sampledOn := time.Now()
+	for i := range clusters {
+		if clusters[i].IsVirtualCluster || len(clusters[i].ErrorInConnecting) > 0 {
+			continue
+		}
+		if err = impl.sampleCluster(&clusters[i], sampledOn); err != nil {
+			impl.logger.Errorw("error in sampling cluster usage", "clusterId", clusters[i].Id, "err", err)
+		}
+	}
+}
+
+func (impl *RightsizingServiceImpl) sampleCluster(clusterBean *clusterBean.ClusterBean, sampledOn time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), clusterSamplingTimeout)
+	defer cancel()
+	restConfig, k8sHttpClient, k8sClientSet, err := impl.k8sCommonService.GetK8sConfigAndClients(ctx, clusterBean)
+	if err != nil {
+		return err
+	}
+	metricsClientSet, err := impl.k8sUtil.GetMetricsClientSet(restConfig, k8sHttpClient)
+	if err != nil {
+		return err
+	}
+	podMetrics, err := metricsClientSet.MetricsV1beta1().PodMetricses(v1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	pods, err := k8sClientSet.CoreV1().Pods(v1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("status.phase", string(v1.PodRunning)).String(),
+	})
+	if err != nil {
+		return err
+	}
+	nodes, err := impl.k8sUtil.GetNodesList(ctx, k8sClientSet)
+	if err != nil {
+		return err
+	}
+	nodeGroups := make(map[string]string, len(nodes.Items))
+	for i := range nodes.Items {
+		nodeGroups[nodes.Items[i].Name] = getNodeGroup(&nodes.Items[i])
+	}
+	samples := buildSamples(clusterBean.Id, pods.Items, podMetrics.Items, nodeGroups, sampledOn)
+	for start := 0; start < len(samples); start += sampleBatchSize {
+		end := min(start+sampleBatchSize, len(samples))
+		if err = impl.sampleRepository.SaveAll(samples[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *RightsizingServiceImpl) DeleteExpiredSamples() {
+	before := time.Now().AddDate(0, 0, -impl.config.SampleRetentionDays)
+	deleted, err := impl.sampleRepository.DeleteSampledBefore(before)
+	if err != nil {
+		impl.logger.Errorw("error in deleting expired usage samples", "before", before, "err", err)
+		return
+	}
+	impl.logger.Debugw("deleted expired usage samples", "count", deleted)
+}
+
+func (impl *RightsizingServiceImpl) GetReport(clusterId int, namespace string) (*bean.RightsizingReport, error) {
+	since := time.Now().AddDate(0, 0, -impl.config.WindowDays)
+	usages, err := impl.sampleRepository.FindUsage(clusterId, namespace, since, float64(impl.config.CpuPercentile)/100, float64(impl.config.MemoryPercentile)/100)
+	if err != nil {
+		impl.logger.Errorw("error in fetching workload usage", "clusterId", clusterId, "namespace", namespace, "err", err)
+		return nil, err
+	}
+	latestRun, err := impl.sampleRepository.FindLatestRun(clusterId, namespace)
+	if err != nil {
+		impl.logger.Errorw("error in fetching latest usage samples", "clusterId", clusterId, "namespace", namespace, "err", err)
+		return nil, err
+	}
+	prices, err := impl.priceRepository.FindActiveByClusterId(clusterId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching node group prices", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	report := &bean.RightsizingReport{
+		ClusterId:       clusterId,
+		WindowDays:      impl.config.WindowDays,
+		Recommendations: buildRecommendations(usages, latestRun, prices, impl.config),
+	}
+	for _, recommendation := range report.Recommendations {
+		if recommendation.Cost == nil {
+			continue
+		}
+		report.CurrentMonthly += recommendation.Cost.CurrentMonthly
+		if recommendation.Recommended != nil {
+			report.RecommendedMonthly += recommendation.Cost.RecommendedMonthly
+		} else {
+			report.RecommendedMonthly += recommendation.Cost.CurrentMonthly
+		}
+	}
+	report.CurrentMonthly, report.RecommendedMonthly = roundCost(report.CurrentMonthly), roundCost(report.RecommendedMonthly)
+	return report, nil
+}
+
+func (impl *RightsizingServiceImpl) GetRecommendation(identifier *bean.WorkloadIdentifier) (*bean.WorkloadRecommendation, error) {
+	report, err := impl.GetReport(identifier.ClusterId, identifier.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, recommendation := range report.Recommendations {
+		if recommendation.WorkloadIdentifier == *identifier {
+			return recommendation, nil
+		}
+	}
+	message := fmt.Sprintf("no recommendation for container %s of %s %s", identifier.ContainerName, identifier.WorkloadKind, identifier.WorkloadName)
+	return nil, util.NewApiError(http.StatusNotFound, message, message)
+}
+
+func (impl *RightsizingServiceImpl) ApplyRecommendation(ctx context.Context, recommendation *bean.WorkloadRecommendation, userId int32) (*bean.ApplyRecommendationResponse, error) {
+	if !recommendation.Applicable {
+		message := "only recommendations for the primary container of devtron apps which are not right sized can be applied"
+		return nil, util.NewApiError(http.StatusBadRequest, message, message)
+	}
+	appId, envId := recommendation.AppId, recommendation.EnvId
+	activeProperties, err := impl.propertiesConfigService.GetEnvironmentProperties(appId, envId, 0)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment properties", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	chartRefId := activeProperties.EnvironmentConfig.ChartRefId
+	if chartRefId == 0 {
+		chartRefId = activeProperties.GlobalChartRefId
+	}
+	properties, err := impl.propertiesConfigService.GetEnvironmentProperties(appId, envId, chartRefId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment properties", "appId", appId, "envId", envId, "chartRefId", chartRefId, "err", err)
+		return nil, err
+	}
+	values, mergeStrategy := properties.GlobalConfig, models.MERGE_STRATEGY_REPLACE
+	if properties.EnvironmentConfig.Id > 0 && properties.EnvironmentConfig.IsOverride {
+		envOverride, err := impl.propertiesConfigService.FetchEnvProperties(appId, envId, chartRefId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment override", "appId", appId, "envId", envId, "chartRefId", chartRefId, "err", err)
+			return nil, err
+		}
+		values = []byte(envOverride.EnvOverrideValues)
+		if len(envOverride.MergeStrategy) > 0 {
+			mergeStrategy = envOverride.MergeStrategy
+		}
+	}
+	values, err = setTemplateResources(values, recommendation.Recommended)
+	if err != nil {
+		impl.logger.Errorw("error in setting resources in deployment template", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	scope := resourceQualifiers.Scope{AppId: appId, EnvId: envId, ClusterId: recommendation.ClusterId}
+	valid, err := impl.deploymentTemplateValidationService.DeploymentTemplateValidate(ctx, values, chartRefId, scope)
+	if !valid {
+		impl.logger.Errorw("deployment template with recommended resources is invalid", "appId", appId, "envId", envId, "err", err)
+		return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("deployment template with recommended resources is invalid: %v", err), fmt.Sprint(err))
+	}
+	request := properties.EnvironmentConfig
+	request.EnvOverrideValues = values
+	request.ChartRefId = chartRefId
+	request.IsOverride = true
+	request.MergeStrategy = mergeStrategy
+	request.UserId = userId
+	var saved *pipelineBean.EnvironmentProperties
+	if request.Id > 0 {
+		saved, err = impl.propertiesConfigService.UpdateEnvironmentProperties(appId, &request, userId)
+	} else {
+		request.Namespace = properties.Namespace
+		request.EnvironmentId = envId
+		request.Active = true
+		request.ManualReviewed = true
+		saved, err = impl.propertiesConfigService.CreateEnvironmentProperties(appId, &request)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving environment override", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	return &bean.ApplyRecommendationResponse{
+		AppId:         appId,
+		EnvId:         envId,
+		EnvOverrideId: saved.Id,
+		ChartRefId:    chartRefId,
+		Recommended:   recommendation.Recommended,
+	}, nil
+}
+
+func (impl *RightsizingServiceImpl) GetPrices(clusterId int) ([]*bean.NodeGroupPriceDto, error) {
+	var prices []*repository.NodeGroupPrice
+	var err error
+	if clusterId > 0 {
+		prices, err = impl.priceRepository.FindActiveByClusterId(clusterId)
+	} else {
+		prices, err = impl.priceRepository.FindAllActive()
+	}
+	if err != nil {
+		impl.logger.Errorw("error in fetching node group prices", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	dtos := make([]*bean.NodeGroupPriceDto, 0, len(prices))
+	for _, price := range prices {
+		dtos = append(dtos, toPriceDto(price))
+	}
+	return dtos, nil
+}
+
+func (impl *RightsizingServiceImpl) SavePrice(request *bean.NodeGroupPriceDto) (*bean.NodeGroupPriceDto, error) {
+	if _, err := impl.clusterService.FindById(request.ClusterId); err != nil {
+		impl.logger.Errorw("error in fetching cluster", "clusterId", request.ClusterId, "err", err)
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "cluster not found", err.Error())
+		}
+		return nil, err
+	}
+	model, err := impl.priceRepository.FindActiveByClusterIdAndNodeGroup(request.ClusterId, request.NodeGroup)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching node group price", "clusterId", request.ClusterId, "nodeGroup", request.NodeGroup, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) {
+		model = &repository.NodeGroupPrice{
+			ClusterId: request.ClusterId,
+			NodeGroup: request.NodeGroup,
+			Active:    true,
+			AuditLog:  sql.NewDefaultAuditLog(request.UserId),
+		}
+	} else {
+		model.UpdateAuditLog(request.UserId)
+	}
+	model.CpuCoreHourlyPrice = request.CpuCoreHourlyPrice
+	model.MemoryGibHourlyPrice = request.MemoryGibHourlyPrice
+	if model.Id > 0 {
+		err = impl.priceRepository.Update(model)
+	} else {
+		err = impl.priceRepository.Save(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving node group price", "clusterId", request.ClusterId, "nodeGroup", request.NodeGroup, "err", err)
+		return nil, err
+	}
+	return toPriceDto(model), nil
+}
+
+func (impl *RightsizingServiceImpl) DeletePrice(id int, userId int32) error {
+	model, err := impl.priceRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching node group price", "id", id, "err", err)
+		if util.IsErrNoRows(err) {
+			return util.NewApiError(http.StatusNotFound, "price not found", err.Error())
+		}
+		return err
+	}
+	model.Active = false
+	model.UpdateAuditLog(userId)
+	err = impl.priceRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in deleting node group price", "id", id, "err", err)
+	}
+	return err
+}
+
+func toPriceDto(model *repository.NodeGroupPrice) *bean.NodeGroupPriceDto {
+	return &bean.NodeGroupPriceDto{
+		Id:                   model.Id,
+		ClusterId:            model.ClusterId,
+		NodeGroup:            model.NodeGroup,
+		CpuCoreHourlyPrice:   model.CpuCoreHourlyPrice,
+		MemoryGibHourlyPrice: model.MemoryGibHourlyPrice,
+	}
+}
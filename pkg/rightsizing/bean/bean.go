@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type RightsizingConfig struct {
+	SamplingCron           string `env:"RIGHTSIZING_SAMPLING_CRON" envDefault:"*/5 * * * *" description:"Schedule of the job sampling the resource usage of the containers of all the clusters"`
+	SampleRetentionDays    int    `env:"RIGHTSIZING_SAMPLE_RETENTION_DAYS" envDefault:"14" description:"Usage samples older than these many days are deleted"`
+	WindowDays             int    `env:"RIGHTSIZING_WINDOW_DAYS" envDefault:"7" description:"Recommendations are computed from the samples of these many last days"`
+	MinSamples             int    `env:"RIGHTSIZING_MIN_SAMPLES" envDefault:"12" description:"Containers with fewer samples in the window get no recommendation"`
+	CpuPercentile          int    `env:"RIGHTSIZING_CPU_PERCENTILE" envDefault:"90" description:"Percentile of the observed cpu usage the cpu request is sized to"`
+	MemoryPercentile       int    `env:"RIGHTSIZING_MEMORY_PERCENTILE" envDefault:"95" description:"Percentile of the observed memory usage the memory request is sized to"`
+	HeadroomPercent        int    `env:"RIGHTSIZING_HEADROOM_PERCENT" envDefault:"15" description:"Added on top of the observed usage for the recommended requests and memory limit"`
+	ChangeThresholdPercent int    `env:"RIGHTSIZING_CHANGE_THRESHOLD_PERCENT" envDefault:"20" description:"Requests within this much of the recommendation are reported as right sized"`
+	MinCpuMillicores       int64  `env:"RIGHTSIZING_MIN_CPU_MILLICORES" envDefault:"10" description:"Lowest recommended cpu request"`
+	MinMemoryMib           int64  `env:"RIGHTSIZING_MIN_MEMORY_MIB" envDefault:"32" description:"Lowest recommended memory request"`
+}
+
+// HoursPerMonth is the average number of hours in a month, costs are estimated per month from hourly prices
+const HoursPerMonth = 730
+
+// DefaultNodeGroup is the node group of a price applying to the nodes of a cluster without a price of their own group
+const DefaultNodeGroup = "*"
+
+// WorkloadKindPod is the kind of the samples of pods without a controller
+const WorkloadKindPod = "Pod"
+
+const (
+	DevtronAppIdLabel = "appId"
+	DevtronEnvIdLabel = "envId"
+)
+
+type RecommendationStatus string
+
+const (
+	RecommendationStatusOversized        RecommendationStatus = "Oversized"
+	RecommendationStatusUndersized       RecommendationStatus = "Undersized"
+	RecommendationStatusRightSized       RecommendationStatus = "RightSized"
+	RecommendationStatusNoRequests       RecommendationStatus = "NoRequests"
+	RecommendationStatusInsufficientData RecommendationStatus = "InsufficientData"
+)
+
+type ResourceValues struct {
+	CpuMillicores int64 `json:"cpuMillicores"`
+	MemoryBytes   int64 `json:"memoryBytes"`
+}
+
+type ContainerResources struct {
+	Requests ResourceValues `json:"requests"`
+	// Limits are 0 when not set, a cpu limit is only recommended for containers which have one
+	Limits ResourceValues `json:"limits"`
+}
+
+type ResourceUsage struct {
+	CpuPercentileMillicores int64 `json:"cpuPercentileMillicores"`
+	CpuMaxMillicores        int64 `json:"cpuMaxMillicores"`
+	MemoryPercentileBytes   int64 `json:"memoryPercentileBytes"`
+	MemoryMaxBytes          int64 `json:"memoryMaxBytes"`
+}
+
+type CostEstimate struct {
+	NodeGroup          string  `json:"nodeGroup"`
+	CurrentMonthly     float64 `json:"currentMonthly"`
+	RecommendedMonthly float64 `json:"recommendedMonthly"`
+	MonthlySavings     float64 `json:"monthlySavings"`
+}
+
+type WorkloadIdentifier struct {
+	ClusterId     int    `json:"clusterId" validate:"required,gt=0"`
+	Namespace     string `json:"namespace" validate:"required"`
+	WorkloadKind  string `json:"workloadKind" validate:"required"`
+	WorkloadName  string `json:"workloadName" validate:"required"`
+	ContainerName string `json:"containerName" validate:"required"`
+}
+
+type WorkloadRecommendation struct {
+	WorkloadIdentifier
+	// AppId and EnvId are set for the workloads of devtron apps, their recommendation can be applied to the deployment template
+	AppId         int                  `json:"appId,omitempty"`
+	EnvId         int                  `json:"envId,omitempty"`
+	NodeGroup     string               `json:"nodeGroup"`
+	Replicas      int                  `json:"replicas"`
+	SampleCount   int                  `json:"sampleCount"`
+	LastSampledOn time.Time            `json:"lastSampledOn"`
+	Status        RecommendationStatus `json:"status"`
+	Usage         *ResourceUsage       `json:"usage"`
+	Current       ContainerResources   `json:"current"`
+	Recommended   *ContainerResources  `json:"recommended,omitempty"`
+	Cost          *CostEstimate        `json:"cost,omitempty"`
+	// Applicable is set when the recommendation can be applied to the deployment template of the app on the environment
+	Applicable bool `json:"applicable"`
+}
+
+type RightsizingReport struct {
+	ClusterId       int                       `json:"clusterId"`
+	WindowDays      int                       `json:"windowDays"`
+	Recommendations []*WorkloadRecommendation `json:"recommendations"`
+	// CurrentMonthly and RecommendedMonthly add up the workloads with a cost estimate
+	CurrentMonthly     float64 `json:"currentMonthly"`
+	RecommendedMonthly float64 `json:"recommendedMonthly"`
+}
+
+type NodeGroupPriceDto struct {
+	Id                   int     `json:"id"`
+	ClusterId            int     `json:"clusterId" validate:"required,gt=0"`
+	NodeGroup            string  `json:"nodeGroup" validate:"required,max=250"`
+	CpuCoreHourlyPrice   float64 `json:"cpuCoreHourlyPrice" validate:"gte=0"`
+	MemoryGibHourlyPrice float64 `json:"memoryGibHourlyPrice" validate:"gte=0"`
+	UserId               int32   `json:"-"`
+}
+
+type ApplyRecommendationResponse struct {
+	AppId         int `json:"appId"`
+	EnvId         int `json:"envId"`
+	EnvOverrideId int `json:"envOverrideId"`
+	ChartRefId    int `json:"chartRefId"`
+	// Recommended are the resources set in the deployment template, they are deployed with the next deployment
+	Recommended *ContainerResources `json:"recommended"`
+}
@@ -0,0 +1,325 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rightsizing
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	capacityBean "github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/rightsizing/bean"
+	"github.com/devtron-labs/devtron/pkg/rightsizing/repository"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsV1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+const (
+	mebibyte = 1 << 20
+	gibibyte = 1 << 30
+
+	podTemplateHashLabel         = "pod-template-hash"
+	rolloutsPodTemplateHashLabel = "rollouts-pod-template-hash"
+)
+
+// jobs of a cron job are named after it with the scheduled time in minutes
+var cronJobJobName = regexp.MustCompile(`^(.+)-\d{8,}$`)
+
+// getWorkload returns the workload a pod belongs to, the deployment or rollout of its replica set and the cron job
+// of its job are found by their naming conventions
+func getWorkload(pod *v1.Pod) (string, string) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return bean.WorkloadKindPod, pod.Name
+	}
+	switch owner.Kind {
+	case "ReplicaSet":
+		if hash, ok := pod.Labels[rolloutsPodTemplateHashLabel]; ok && strings.HasSuffix(owner.Name, "-"+hash) {
+			return "Rollout", strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+		if hash, ok := pod.Labels[podTemplateHashLabel]; ok && strings.HasSuffix(owner.Name, "-"+hash) {
+			return "Deployment", strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+	case "Job":
+		if matches := cronJobJobName.FindStringSubmatch(owner.Name); matches != nil {
+			return "CronJob", matches[1]
+		}
+	}
+	return owner.Kind, owner.Name
+}
+
+func getNodeGroup(node *v1.Node) string {
+	nodeGroup := ""
+	for _, label := range capacityBean.NodeGroupLabels {
+		if group, ok := node.Labels[label]; ok {
+			nodeGroup = group
+		}
+	}
+	return nodeGroup
+}
+
+// getDevtronApp reads the app and environment of a pod deployed by devtron from its labels
+func getDevtronApp(pod *v1.Pod) (int, int) {
+	appId, err := strconv.Atoi(pod.Labels[bean.DevtronAppIdLabel])
+	if err != nil {
+		return 0, 0
+	}
+	envId, err := strconv.Atoi(pod.Labels[bean.DevtronEnvIdLabel])
+	if err != nil {
+		return 0, 0
+	}
+	return appId, envId
+}
+
+// buildSamples samples the running containers with metrics, the first container of a pod is its primary container
+// which is the one sized by the deployment template of devtron apps
+func buildSamples(clusterId int, pods []v1.Pod, podMetrics []metricsV1beta1.PodMetrics, nodeGroups map[string]string, sampledOn time.Time) []*repository.WorkloadResourceSample {
+	usages := make(map[string]v1.ResourceList, len(podMetrics))
+	for _, metrics := range podMetrics {
+		for _, container := range metrics.Containers {
+			usages[getContainerKey(metrics.Namespace, metrics.Name, container.Name)] = container.Usage
+		}
+	}
+	var samples []*repository.WorkloadResourceSample
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		workloadKind, workloadName := getWorkload(pod)
+		appId, envId := getDevtronApp(pod)
+		for index, container := range pod.Spec.Containers {
+			usage, ok := usages[getContainerKey(pod.Namespace, pod.Name, container.Name)]
+			if !ok {
+				continue
+			}
+			samples = append(samples, &repository.WorkloadResourceSample{
+				ClusterId:            clusterId,
+				Namespace:            pod.Namespace,
+				WorkloadKind:         workloadKind,
+				WorkloadName:         workloadName,
+				PodName:              pod.Name,
+				ContainerName:        container.Name,
+				PrimaryContainer:     index == 0,
+				NodeGroup:            nodeGroups[pod.Spec.NodeName],
+				AppId:                appId,
+				EnvId:                envId,
+				CpuUsageMillicores:   usage.Cpu().MilliValue(),
+				MemoryUsageBytes:     usage.Memory().Value(),
+				CpuRequestMillicores: container.Resources.Requests.Cpu().MilliValue(),
+				CpuLimitMillicores:   container.Resources.Limits.Cpu().MilliValue(),
+				MemoryRequestBytes:   container.Resources.Requests.Memory().Value(),
+				MemoryLimitBytes:     container.Resources.Limits.Memory().Value(),
+				SampledOn:            sampledOn,
+			})
+		}
+	}
+	return samples
+}
+
+func getContainerKey(namespace, podName, containerName string) string {
+	return fmt.Sprintf("%s/%s/%s", namespace, podName, containerName)
+}
+
+func getWorkloadKey(namespace, workloadKind, workloadName, containerName string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", namespace, workloadKind, workloadName, containerName)
+}
+
+// recommend sizes the requests to the usage percentiles with headroom, the memory limit is kept above the highest
+// usage seen and the cpu limit keeps its ratio to the request, a container without a cpu limit isn't given one
+func recommend(usage *repository.WorkloadUsage, current bean.ContainerResources, config *bean.RightsizingConfig) *bean.ContainerResources {
+	withHeadroom := func(value float64) float64 {
+		return value * float64(100+config.HeadroomPercent) / 100
+	}
+	cpuRequest := max(int64(math.Ceil(withHeadroom(usage.CpuPercentile))), config.MinCpuMillicores)
+	memoryRequest := roundUpToMebibyte(max(withHeadroom(usage.MemoryPercentile), float64(config.MinMemoryMib*mebibyte)))
+	memoryLimit := max(roundUpToMebibyte(withHeadroom(float64(usage.MemoryMax))), memoryRequest)
+	var cpuLimit int64
+	if current.Limits.CpuMillicores > 0 {
+		if current.Requests.CpuMillicores > 0 {
+			ratio := float64(current.Limits.CpuMillicores) / float64(current.Requests.CpuMillicores)
+			cpuLimit = int64(math.Ceil(float64(cpuRequest) * ratio))
+		} else {
+			cpuLimit = int64(math.Ceil(withHeadroom(float64(usage.CpuMax))))
+		}
+		cpuLimit = max(cpuLimit, cpuRequest)
+	}
+	return &bean.ContainerResources{
+		Requests: bean.ResourceValues{CpuMillicores: cpuRequest, MemoryBytes: memoryRequest},
+		Limits:   bean.ResourceValues{CpuMillicores: cpuLimit, MemoryBytes: memoryLimit},
+	}
+}
+
+func roundUpToMebibyte(bytes float64) int64 {
+	return int64(math.Ceil(bytes/mebibyte)) * mebibyte
+}
+
+func getStatus(current, recommended bean.ResourceValues, config *bean.RightsizingConfig) bean.RecommendationStatus {
+	if current.CpuMillicores == 0 || current.MemoryBytes == 0 {
+		return bean.RecommendationStatusNoRequests
+	}
+	threshold := float64(config.ChangeThresholdPercent) / 100
+	cpuRatio := float64(recommended.CpuMillicores) / float64(current.CpuMillicores)
+	memoryRatio := float64(recommended.MemoryBytes) / float64(current.MemoryBytes)
+	if cpuRatio > 1+threshold || memoryRatio > 1+threshold {
+		return bean.RecommendationStatusUndersized
+	}
+	if cpuRatio < 1-threshold || memoryRatio < 1-threshold {
+		return bean.RecommendationStatusOversized
+	}
+	return bean.RecommendationStatusRightSized
+}
+
+// estimateMonthlyCost prices the requests of the replicas, requests are what a workload reserves on its nodes
+func estimateMonthlyCost(requests bean.ResourceValues, replicas int, price *repository.NodeGroupPrice) float64 {
+	cores := float64(requests.CpuMillicores) / 1000
+	gib := float64(requests.MemoryBytes) / gibibyte
+	hourly := cores*price.CpuCoreHourlyPrice + gib*price.MemoryGibHourlyPrice
+	return roundCost(hourly * bean.HoursPerMonth * float64(replicas))
+}
+
+func roundCost(cost float64) float64 {
+	return math.Round(cost*100) / 100
+}
+
+// buildRecommendations recommends for the containers of the last sampling run, their usage is aggregated over the window
+func buildRecommendations(usages []*repository.WorkloadUsage, latestRun []*repository.WorkloadResourceSample,
+	prices []*repository.NodeGroupPrice, config *bean.RightsizingConfig) []*bean.WorkloadRecommendation {
+	usageByKey := make(map[string]*repository.WorkloadUsage, len(usages))
+	for _, usage := range usages {
+		usageByKey[getWorkloadKey(usage.Namespace, usage.WorkloadKind, usage.WorkloadName, usage.ContainerName)] = usage
+	}
+	priceByNodeGroup := make(map[string]*repository.NodeGroupPrice, len(prices))
+	for _, price := range prices {
+		priceByNodeGroup[price.NodeGroup] = price
+	}
+	recommendationByKey := make(map[string]*bean.WorkloadRecommendation)
+	primaryByKey := make(map[string]bool)
+	for _, sample := range latestRun {
+		key := getWorkloadKey(sample.Namespace, sample.WorkloadKind, sample.WorkloadName, sample.ContainerName)
+		if recommendation, ok := recommendationByKey[key]; ok {
+			recommendation.Replicas++
+			continue
+		}
+		recommendationByKey[key] = &bean.WorkloadRecommendation{
+			WorkloadIdentifier: bean.WorkloadIdentifier{
+				ClusterId:     sample.ClusterId,
+				Namespace:     sample.Namespace,
+				WorkloadKind:  sample.WorkloadKind,
+				WorkloadName:  sample.WorkloadName,
+				ContainerName: sample.ContainerName,
+			},
+			AppId:         sample.AppId,
+			EnvId:         sample.EnvId,
+			NodeGroup:     sample.NodeGroup,
+			Replicas:      1,
+			LastSampledOn: sample.SampledOn,
+			Current: bean.ContainerResources{
+				Requests: bean.ResourceValues{CpuMillicores: sample.CpuRequestMillicores, MemoryBytes: sample.MemoryRequestBytes},
+				Limits:   bean.ResourceValues{CpuMillicores: sample.CpuLimitMillicores, MemoryBytes: sample.MemoryLimitBytes},
+			},
+		}
+		primaryByKey[key] = sample.PrimaryContainer
+	}
+	recommendations := make([]*bean.WorkloadRecommendation, 0, len(recommendationByKey))
+	for key, recommendation := range recommendationByKey {
+		recommendation.Status = bean.RecommendationStatusInsufficientData
+		if usage, ok := usageByKey[key]; ok {
+			recommendation.SampleCount = usage.SampleCount
+			recommendation.Usage = &bean.ResourceUsage{
+				CpuPercentileMillicores: int64(math.Ceil(usage.CpuPercentile)),
+				CpuMaxMillicores:        usage.CpuMax,
+				MemoryPercentileBytes:   int64(math.Ceil(usage.MemoryPercentile)),
+				MemoryMaxBytes:          usage.MemoryMax,
+			}
+			if usage.SampleCount >= config.MinSamples {
+				recommendation.Recommended = recommend(usage, recommendation.Current, config)
+				recommendation.Status = getStatus(recommendation.Current.Requests, recommendation.Recommended.Requests, config)
+			}
+		}
+		recommendation.Applicable = recommendation.Recommended != nil && recommendation.AppId > 0 && recommendation.EnvId > 0 &&
+			primaryByKey[key] && recommendation.Status != bean.RecommendationStatusRightSized
+		price, ok := priceByNodeGroup[recommendation.NodeGroup]
+		if !ok {
+			price, ok = priceByNodeGroup[bean.DefaultNodeGroup]
+		}
+		if ok {
+			recommendation.Cost = &bean.CostEstimate{
+				NodeGroup:      price.NodeGroup,
+				CurrentMonthly: estimateMonthlyCost(recommendation.Current.Requests, recommendation.Replicas, price),
+			}
+			if recommendation.Recommended != nil {
+				recommendation.Cost.RecommendedMonthly = estimateMonthlyCost(recommendation.Recommended.Requests, recommendation.Replicas, price)
+				recommendation.Cost.MonthlySavings = roundCost(recommendation.Cost.CurrentMonthly - recommendation.Cost.RecommendedMonthly)
+			}
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		return getWorkloadKey(a.Namespace, a.WorkloadKind, a.WorkloadName, a.ContainerName) <
+			getWorkloadKey(b.Namespace, b.WorkloadKind, b.WorkloadName, b.ContainerName)
+	})
+	return recommendations
+}
+
+// setTemplateResources sets the resources of a deployment template, which size the primary container of the app,
+// the other values of the template are kept as they are
+func setTemplateResources(values json.RawMessage, resources *bean.ContainerResources) (json.RawMessage, error) {
+	template := make(map[string]interface{})
+	if len(values) > 0 && string(values) != "null" {
+		if err := json.Unmarshal(values, &template); err != nil {
+			return nil, err
+		}
+	}
+	templateResources, _ := template["resources"].(map[string]interface{})
+	if templateResources == nil {
+		templateResources = make(map[string]interface{})
+	}
+	requests, _ := templateResources["requests"].(map[string]interface{})
+	if requests == nil {
+		requests = make(map[string]interface{})
+	}
+	limits, _ := templateResources["limits"].(map[string]interface{})
+	if limits == nil {
+		limits = make(map[string]interface{})
+	}
+	requests["cpu"] = formatCpu(resources.Requests.CpuMillicores)
+	requests["memory"] = formatMemory(resources.Requests.MemoryBytes)
+	limits["memory"] = formatMemory(resources.Limits.MemoryBytes)
+	if resources.Limits.CpuMillicores > 0 {
+		limits["cpu"] = formatCpu(resources.Limits.CpuMillicores)
+	}
+	templateResources["requests"] = requests
+	templateResources["limits"] = limits
+	template["resources"] = templateResources
+	return json.Marshal(template)
+}
+
+func formatCpu(millicores int64) string {
+	return fmt.Sprintf("%dm", millicores)
+}
+
+func formatMemory(bytes int64) string {
+	return fmt.Sprintf("%dMi", (bytes+mebibyte-1)/mebibyte)
+}
@@ -0,0 +1,289 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rightsizing
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/rightsizing/bean"
+	"github.com/devtron-labs/devtron/pkg/rightsizing/repository"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsV1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+func getTestConfig() *bean.RightsizingConfig {
+	return &bean.RightsizingConfig{
+		MinSamples:             12,
+		HeadroomPercent:        10,
+		ChangeThresholdPercent: 20,
+		MinCpuMillicores:       10,
+		MinMemoryMib:           32,
+	}
+}
+
+func getTestPod(name string, owner *metav1.OwnerReference, labels map[string]string) *v1.Pod {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels}}
+	if owner != nil {
+		controller := true
+		owner.Controller = &controller
+		pod.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	return pod
+}
+
+func TestGetWorkload(t *testing.T) {
+	tests := []struct {
+		name         string
+		pod          *v1.Pod
+		expectedKind string
+		expectedName string
+	}{
+		{
+			name:         "standalone pod",
+			pod:          getTestPod("debug", nil, nil),
+			expectedKind: bean.WorkloadKindPod,
+			expectedName: "debug",
+		},
+		{
+			name:         "deployment",
+			pod:          getTestPod("web-5d8f7c-x2x", &metav1.OwnerReference{Kind: "ReplicaSet", Name: "web-5d8f7c"}, map[string]string{podTemplateHashLabel: "5d8f7c"}),
+			expectedKind: "Deployment",
+			expectedName: "web",
+		},
+		{
+			name:         "rollout",
+			pod:          getTestPod("web-6b9c4-x2x", &metav1.OwnerReference{Kind: "ReplicaSet", Name: "web-6b9c4"}, map[string]string{rolloutsPodTemplateHashLabel: "6b9c4"}),
+			expectedKind: "Rollout",
+			expectedName: "web",
+		},
+		{
+			name:         "replica set without deployment",
+			pod:          getTestPod("web-x2x", &metav1.OwnerReference{Kind: "ReplicaSet", Name: "web"}, nil),
+			expectedKind: "ReplicaSet",
+			expectedName: "web",
+		},
+		{
+			name:         "cron job",
+			pod:          getTestPod("backup-28700460-x2x", &metav1.OwnerReference{Kind: "Job", Name: "backup-28700460"}, nil),
+			expectedKind: "CronJob",
+			expectedName: "backup",
+		},
+		{
+			name:         "job",
+			pod:          getTestPod("migrate-x2x", &metav1.OwnerReference{Kind: "Job", Name: "migrate"}, nil),
+			expectedKind: "Job",
+			expectedName: "migrate",
+		},
+		{
+			name:         "stateful set",
+			pod:          getTestPod("db-0", &metav1.OwnerReference{Kind: "StatefulSet", Name: "db"}, nil),
+			expectedKind: "StatefulSet",
+			expectedName: "db",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, name := getWorkload(tt.pod)
+			assert.Equal(t, tt.expectedKind, kind)
+			assert.Equal(t, tt.expectedName, name)
+		})
+	}
+}
+
+func TestBuildSamples(t *testing.T) {
+	pod := getTestPod("web-5d8f7c-x2x", &metav1.OwnerReference{Kind: "ReplicaSet", Name: "web-5d8f7c"},
+		map[string]string{podTemplateHashLabel: "5d8f7c", bean.DevtronAppIdLabel: "3", bean.DevtronEnvIdLabel: "4"})
+	pod.Spec.NodeName = "node-1"
+	pod.Spec.Containers = []v1.Container{
+		{
+			Name: "web",
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m"), v1.ResourceMemory: resource.MustParse("256Mi")},
+				Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("512Mi")},
+			},
+		},
+		{Name: "envoy"},
+		{Name: "no-metrics"},
+	}
+	pod.Status.Phase = v1.PodRunning
+	pending := getTestPod("web-5d8f7c-y2y", nil, nil)
+	pending.Status.Phase = v1.PodPending
+	podMetrics := []metricsV1beta1.PodMetrics{{
+		ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		Containers: []metricsV1beta1.ContainerMetrics{
+			{Name: "web", Usage: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m"), v1.ResourceMemory: resource.MustParse("128Mi")}},
+			{Name: "envoy", Usage: v1.ResourceList{v1.ResourceCPU: resource.MustParse("5m"), v1.ResourceMemory: resource.MustParse("20Mi")}},
+		},
+	}}
+	sampledOn := time.Now()
+	samples := buildSamples(1, []v1.Pod{*pod, *pending}, podMetrics, map[string]string{"node-1": "general"}, sampledOn)
+	assert.Len(t, samples, 2)
+	assert.Equal(t, &repository.WorkloadResourceSample{
+		ClusterId:            1,
+		Namespace:            "default",
+		WorkloadKind:         "Deployment",
+		WorkloadName:         "web",
+		PodName:              pod.Name,
+		ContainerName:        "web",
+		PrimaryContainer:     true,
+		NodeGroup:            "general",
+		AppId:                3,
+		EnvId:                4,
+		CpuUsageMillicores:   100,
+		MemoryUsageBytes:     128 * mebibyte,
+		CpuRequestMillicores: 250,
+		MemoryRequestBytes:   256 * mebibyte,
+		MemoryLimitBytes:     512 * mebibyte,
+		SampledOn:            sampledOn,
+	}, samples[0])
+	assert.Equal(t, "envoy", samples[1].ContainerName)
+	assert.False(t, samples[1].PrimaryContainer)
+}
+
+func TestRecommend(t *testing.T) {
+	config := getTestConfig()
+	usage := &repository.WorkloadUsage{CpuPercentile: 100, CpuMax: 300, MemoryPercentile: 100 * mebibyte, MemoryMax: 200 * mebibyte}
+
+	t.Run("cpu limit keeps its ratio", func(t *testing.T) {
+		current := bean.ContainerResources{
+			Requests: bean.ResourceValues{CpuMillicores: 500, MemoryBytes: 512 * mebibyte},
+			Limits:   bean.ResourceValues{CpuMillicores: 1000, MemoryBytes: 512 * mebibyte},
+		}
+		recommended := recommend(usage, current, config)
+		assert.Equal(t, bean.ResourceValues{CpuMillicores: 110, MemoryBytes: 110 * mebibyte}, recommended.Requests)
+		assert.Equal(t, bean.ResourceValues{CpuMillicores: 220, MemoryBytes: 220 * mebibyte}, recommended.Limits)
+	})
+
+	t.Run("no cpu limit is added", func(t *testing.T) {
+		recommended := recommend(usage, bean.ContainerResources{}, config)
+		assert.Equal(t, int64(0), recommended.Limits.CpuMillicores)
+	})
+
+	t.Run("cpu limit without request is sized to the highest usage", func(t *testing.T) {
+		current := bean.ContainerResources{Limits: bean.ResourceValues{CpuMillicores: 1000}}
+		recommended := recommend(usage, current, config)
+		assert.Equal(t, int64(330), recommended.Limits.CpuMillicores)
+	})
+
+	t.Run("requests are kept above the minimums", func(t *testing.T) {
+		idle := &repository.WorkloadUsage{CpuPercentile: 1, CpuMax: 2, MemoryPercentile: mebibyte, MemoryMax: 2 * mebibyte}
+		recommended := recommend(idle, bean.ContainerResources{}, config)
+		assert.Equal(t, bean.ResourceValues{CpuMillicores: 10, MemoryBytes: 32 * mebibyte}, recommended.Requests)
+		assert.Equal(t, int64(32*mebibyte), recommended.Limits.MemoryBytes)
+	})
+}
+
+func TestGetStatus(t *testing.T) {
+	config := getTestConfig()
+	current := bean.ResourceValues{CpuMillicores: 100, MemoryBytes: 100 * mebibyte}
+	assert.Equal(t, bean.RecommendationStatusNoRequests, getStatus(bean.ResourceValues{MemoryBytes: mebibyte}, current, config))
+	assert.Equal(t, bean.RecommendationStatusRightSized, getStatus(current, bean.ResourceValues{CpuMillicores: 115, MemoryBytes: 85 * mebibyte}, config))
+	assert.Equal(t, bean.RecommendationStatusOversized, getStatus(current, bean.ResourceValues{CpuMillicores: 50, MemoryBytes: 100 * mebibyte}, config))
+	// a resource which needs more wins over one which needs less, the workload could be throttled or killed
+	assert.Equal(t, bean.RecommendationStatusUndersized, getStatus(current, bean.ResourceValues{CpuMillicores: 50, MemoryBytes: 200 * mebibyte}, config))
+}
+
+func TestEstimateMonthlyCost(t *testing.T) {
+	price := &repository.NodeGroupPrice{CpuCoreHourlyPrice: 0.04, MemoryGibHourlyPrice: 0.005}
+	cost := estimateMonthlyCost(bean.ResourceValues{CpuMillicores: 500, MemoryBytes: 2 * gibibyte}, 2, price)
+	// (0.5 * 0.04 + 2 * 0.005) * 730 * 2
+	assert.Equal(t, 43.8, cost)
+}
+
+func TestBuildRecommendations(t *testing.T) {
+	config := getTestConfig()
+	sampledOn := time.Now()
+	getSample := func(podName, containerName string, primary bool, appId int) *repository.WorkloadResourceSample {
+		return &repository.WorkloadResourceSample{
+			ClusterId:            1,
+			Namespace:            "default",
+			WorkloadKind:         "Deployment",
+			WorkloadName:         "web",
+			PodName:              podName,
+			ContainerName:        containerName,
+			PrimaryContainer:     primary,
+			NodeGroup:            "general",
+			AppId:                appId,
+			EnvId:                4,
+			CpuRequestMillicores: 1000,
+			MemoryRequestBytes:   gibibyte,
+			SampledOn:            sampledOn,
+		}
+	}
+	latestRun := []*repository.WorkloadResourceSample{
+		getSample("web-1", "web", true, 3),
+		getSample("web-2", "web", true, 3),
+		getSample("web-1", "envoy", false, 3),
+		getSample("web-2", "envoy", false, 3),
+	}
+	usages := []*repository.WorkloadUsage{
+		{Namespace: "default", WorkloadKind: "Deployment", WorkloadName: "web", ContainerName: "web", SampleCount: 100,
+			CpuPercentile: 200, CpuMax: 400, MemoryPercentile: 256 * mebibyte, MemoryMax: 300 * mebibyte},
+		{Namespace: "default", WorkloadKind: "Deployment", WorkloadName: "web", ContainerName: "envoy", SampleCount: 5,
+			CpuPercentile: 10, CpuMax: 20, MemoryPercentile: 20 * mebibyte, MemoryMax: 30 * mebibyte},
+	}
+	prices := []*repository.NodeGroupPrice{{NodeGroup: bean.DefaultNodeGroup, CpuCoreHourlyPrice: 0.04, MemoryGibHourlyPrice: 0.005}}
+
+	recommendations := buildRecommendations(usages, latestRun, prices, config)
+	assert.Len(t, recommendations, 2)
+	envoy, web := recommendations[0], recommendations[1]
+
+	assert.Equal(t, "web", web.ContainerName)
+	assert.Equal(t, 2, web.Replicas)
+	assert.Equal(t, bean.RecommendationStatusOversized, web.Status)
+	assert.True(t, web.Applicable)
+	assert.Equal(t, bean.ResourceValues{CpuMillicores: 220, MemoryBytes: 282 * mebibyte}, web.Recommended.Requests)
+	assert.Equal(t, bean.DefaultNodeGroup, web.Cost.NodeGroup)
+	assert.Equal(t, 65.7, web.Cost.CurrentMonthly)
+	assert.Greater(t, web.Cost.MonthlySavings, 0.0)
+
+	assert.Equal(t, "envoy", envoy.ContainerName)
+	assert.Equal(t, bean.RecommendationStatusInsufficientData, envoy.Status)
+	assert.Nil(t, envoy.Recommended)
+	assert.False(t, envoy.Applicable)
+	assert.Equal(t, 5, envoy.SampleCount)
+
+	// containers of workloads not deployed by devtron can't be applied
+	latestRun[0].AppId, latestRun[1].AppId = 0, 0
+	recommendations = buildRecommendations(usages, latestRun, nil, config)
+	assert.False(t, recommendations[1].Applicable)
+	assert.Nil(t, recommendations[1].Cost)
+}
+
+func TestSetTemplateResources(t *testing.T) {
+	values := json.RawMessage(`{"replicaCount":2,"resources":{"limits":{"cpu":"1","memory":"1Gi"},"requests":{"cpu":"500m","memory":"1Gi"}}}`)
+	resources := &bean.ContainerResources{
+		Requests: bean.ResourceValues{CpuMillicores: 220, MemoryBytes: 282 * mebibyte},
+		Limits:   bean.ResourceValues{CpuMillicores: 440, MemoryBytes: 330 * mebibyte},
+	}
+	updated, err := setTemplateResources(values, resources)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"replicaCount":2,"resources":{"limits":{"cpu":"440m","memory":"330Mi"},"requests":{"cpu":"220m","memory":"282Mi"}}}`, string(updated))
+
+	resources.Limits.CpuMillicores = 0
+	updated, err = setTemplateResources(json.RawMessage(`{"replicaCount":1}`), resources)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"replicaCount":1,"resources":{"limits":{"memory":"330Mi"},"requests":{"cpu":"220m","memory":"282Mi"}}}`, string(updated))
+
+	_, err = setTemplateResources(json.RawMessage(`[`), resources)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// NodeGroupPrice is the hourly price of the capacity of the nodes of a node group, the default node group applies
+// to the nodes of the cluster without a price of their own group
+type NodeGroupPrice struct {
+	tableName            struct{} `sql:"node_group_price" pg:",discard_unknown_columns"`
+	Id                   int      `sql:"id,pk"`
+	ClusterId            int      `sql:"cluster_id,notnull"`
+	NodeGroup            string   `sql:"node_group,notnull"`
+	CpuCoreHourlyPrice   float64  `sql:"cpu_core_hourly_price,notnull"`
+	MemoryGibHourlyPrice float64  `sql:"memory_gib_hourly_price,notnull"`
+	Active               bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type NodeGroupPriceRepository interface {
+	Save(model *NodeGroupPrice) error
+	Update(model *NodeGroupPrice) error
+	FindById(id int) (*NodeGroupPrice, error)
+	FindActiveByClusterId(clusterId int) ([]*NodeGroupPrice, error)
+	FindActiveByClusterIdAndNodeGroup(clusterId int, nodeGroup string) (*NodeGroupPrice, error)
+	FindAllActive() ([]*NodeGroupPrice, error)
+}
+
+type NodeGroupPriceRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewNodeGroupPriceRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *NodeGroupPriceRepositoryImpl {
+	return &NodeGroupPriceRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *NodeGroupPriceRepositoryImpl) Save(model *NodeGroupPrice) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *NodeGroupPriceRepositoryImpl) Update(model *NodeGroupPrice) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *NodeGroupPriceRepositoryImpl) FindById(id int) (*NodeGroupPrice, error) {
+	model := &NodeGroupPrice{}
+	err := impl.dbConnection.Model(model).Where("id = ?", id).Where("active = ?", true).Select()
+	return model, err
+}
+
+func (impl *NodeGroupPriceRepositoryImpl) FindActiveByClusterId(clusterId int) ([]*NodeGroupPrice, error) {
+	var models []*NodeGroupPrice
+	err := impl.dbConnection.Model(&models).Where("cluster_id = ?", clusterId).Where("active = ?", true).Select()
+	return models, err
+}
+
+func (impl *NodeGroupPriceRepositoryImpl) FindActiveByClusterIdAndNodeGroup(clusterId int, nodeGroup string) (*NodeGroupPrice, error) {
+	model := &NodeGroupPrice{}
+	err := impl.dbConnection.Model(model).
+		Where("cluster_id = ?", clusterId).
+		Where("node_group = ?", nodeGroup).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *NodeGroupPriceRepositoryImpl) FindAllActive() ([]*NodeGroupPrice, error) {
+	var models []*NodeGroupPrice
+	err := impl.dbConnection.Model(&models).Where("active = ?", true).Order("cluster_id").Order("node_group").Select()
+	return models, err
+}
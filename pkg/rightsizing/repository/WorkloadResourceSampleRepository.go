@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"time"
+
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// WorkloadResourceSample is the usage of a container of a pod at a sampling run along with its requests and limits,
+// the samples of a run share the sampled on time
+type WorkloadResourceSample struct {
+	tableName            struct{}  `sql:"workload_resource_sample" pg:",discard_unknown_columns"`
+	Id                   int       `sql:"id,pk"`
+	ClusterId            int       `sql:"cluster_id,notnull"`
+	Namespace            string    `sql:"namespace,notnull"`
+	WorkloadKind         string    `sql:"workload_kind,notnull"`
+	WorkloadName         string    `sql:"workload_name,notnull"`
+	PodName              string    `sql:"pod_name,notnull"`
+	ContainerName        string    `sql:"container_name,notnull"`
+	PrimaryContainer     bool      `sql:"primary_container,notnull"`
+	NodeGroup            string    `sql:"node_group"`
+	AppId                int       `sql:"app_id"`
+	EnvId                int       `sql:"env_id"`
+	CpuUsageMillicores   int64     `sql:"cpu_usage_millicores,notnull"`
+	MemoryUsageBytes     int64     `sql:"memory_usage_bytes,notnull"`
+	CpuRequestMillicores int64     `sql:"cpu_request_millicores,notnull"`
+	CpuLimitMillicores   int64     `sql:"cpu_limit_millicores,notnull"`
+	MemoryRequestBytes   int64     `sql:"memory_request_bytes,notnull"`
+	MemoryLimitBytes     int64     `sql:"memory_limit_bytes,notnull"`
+	SampledOn            time.Time `sql:"sampled_on,notnull"`
+}
+
+// WorkloadUsage is the usage of a container of a workload across its pods over a window
+type WorkloadUsage struct {
+	Namespace        string  `sql:"namespace"`
+	WorkloadKind     string  `sql:"workload_kind"`
+	WorkloadName     string  `sql:"workload_name"`
+	ContainerName    string  `sql:"container_name"`
+	SampleCount      int     `sql:"sample_count"`
+	CpuPercentile    float64 `sql:"cpu_percentile"`
+	CpuMax           int64   `sql:"cpu_max"`
+	MemoryPercentile float64 `sql:"memory_percentile"`
+	MemoryMax        int64   `sql:"memory_max"`
+}
+
+type WorkloadResourceSampleRepository interface {
+	SaveAll(models []*WorkloadResourceSample) error
+	// FindUsage aggregates the samples of every container of the workloads of a cluster, namespace is optional
+	FindUsage(clusterId int, namespace string, since time.Time, cpuPercentile, memoryPercentile float64) ([]*WorkloadUsage, error)
+	// FindLatestRun returns the samples of the last sampling run of a cluster, namespace is optional
+	FindLatestRun(clusterId int, namespace string) ([]*WorkloadResourceSample, error)
+	DeleteSampledBefore(before time.Time) (int, error)
+}
+
+type WorkloadResourceSampleRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewWorkloadResourceSampleRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *WorkloadResourceSampleRepositoryImpl {
+	return &WorkloadResourceSampleRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *WorkloadResourceSampleRepositoryImpl) SaveAll(models []*WorkloadResourceSample) error {
+	if len(models) == 0 {
+		return nil
+	}
+	_, err := impl.dbConnection.Model(&models).Insert()
+	return err
+}
+
+func (impl *WorkloadResourceSampleRepositoryImpl) FindUsage(clusterId int, namespace string, since time.Time, cpuPercentile, memoryPercentile float64) ([]*WorkloadUsage, error) {
+	var usages []*WorkloadUsage
+	query := `SELECT namespace, workload_kind, workload_name, container_name, count(*) AS sample_count,
+		percentile_cont(?0) WITHIN GROUP (ORDER BY cpu_usage_millicores) AS cpu_percentile,
+		max(cpu_usage_millicores) AS cpu_max,
+		percentile_cont(?1) WITHIN GROUP (ORDER BY memory_usage_bytes) AS memory_percentile,
+		max(memory_usage_bytes) AS memory_max
+		FROM workload_resource_sample
+		WHERE cluster_id = ?2 AND sampled_on >= ?3 AND (?4 = '' OR namespace = ?4)
+		GROUP BY namespace, workload_kind, workload_name, container_name`
+	_, err := impl.dbConnection.Query(&usages, query, cpuPercentile, memoryPercentile, clusterId, since, namespace)
+	return usages, err
+}
+
+func (impl *WorkloadResourceSampleRepositoryImpl) FindLatestRun(clusterId int, namespace string) ([]*WorkloadResourceSample, error) {
+	var models []*WorkloadResourceSample
+	query := impl.dbConnection.Model(&models).
+		Where("cluster_id = ?", clusterId).
+		Where("sampled_on = (SELECT max(sampled_on) FROM workload_resource_sample WHERE cluster_id = ?)", clusterId)
+	if len(namespace) > 0 {
+		query = query.Where("namespace = ?", namespace)
+	}
+	err := query.Select()
+	return models, err
+}
+
+func (impl *WorkloadResourceSampleRepositoryImpl) DeleteSampledBefore(before time.Time) (int, error) {
+	result, err := impl.dbConnection.Model(&WorkloadResourceSample{}).Where("sampled_on < ?", before).Delete()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commandLine
+
+import "strings"
+
+// maxLength caps a command line so that a paste of a large file doesn't bloat what is kept of it
+const maxLength = 1024
+
+// Line rebuilds the command lines typed in a terminal from the raw keystrokes. Only line editing with backspace is
+// understood, escape sequences like arrow keys are dropped, so a command recalled from the shell history or
+// completed with tab is seen only as far as it was typed. Such a line is reported as edited, the shell may run
+// something else than what was rebuilt of it
+type Line struct {
+	current []rune
+	// edited is set when the line has an escape sequence, a tab or another control key the shell may edit it with
+	edited bool
+	// afterEscape is set right after ESC, inSequence while in a control sequence started with ESC [ or ESC O
+	afterEscape bool
+	inSequence  bool
+}
+
+// Feed consumes a keystroke, completed is set when it is the enter key of a non blank or an edited command line
+func (line *Line) Feed(char rune) (command string, edited bool, completed bool) {
+	if line.afterEscape {
+		line.afterEscape = false
+		line.inSequence = char == '[' || char == 'O'
+		return "", false, false
+	}
+	if line.inSequence {
+		// the final byte of a control sequence is in the @ to ~ range
+		line.inSequence = char < '@' || char > '~'
+		return "", false, false
+	}
+	switch char {
+	case '\x1b':
+		line.afterEscape = true
+		line.edited = true
+	case '\r', '\n':
+		command, edited = strings.TrimSpace(string(line.current)), line.edited
+		line.reset()
+		return command, edited, len(command) > 0 || edited
+	case '\x7f', '\b':
+		if len(line.current) > 0 {
+			line.current = line.current[:len(line.current)-1]
+		}
+	case '\x03', '\x15':
+		// ctrl+c and ctrl+u discard the line
+		line.reset()
+	default:
+		if char < ' ' {
+			// tab completion, history search and cursor movement change the line in ways not followed here
+			line.edited = true
+		} else if len(line.current) < maxLength {
+			line.current = append(line.current, char)
+		}
+	}
+	return "", false, false
+}
+
+func (line *Line) reset() {
+	line.current = line.current[:0]
+	line.edited = false
+}
+
+// Read consumes keystrokes and returns the command lines completed by them
+func (line *Line) Read(data string) []string {
+	var commands []string
+	for _, char := range data {
+		if command, _, completed := line.Feed(char); completed && len(command) > 0 {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commandLine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		inputs   []string
+		commands []string
+	}{
+		{name: "single command", inputs: []string{"ls -la\r"}, commands: []string{"ls -la"}},
+		{name: "command typed key by key", inputs: []string{"p", "w", "d", "\r"}, commands: []string{"pwd"}},
+		{name: "multiple commands in one input", inputs: []string{"cd /tmp\rls\n"}, commands: []string{"cd /tmp", "ls"}},
+		{name: "backspace", inputs: []string{"lss", "\x7f", " /\r"}, commands: []string{"ls /"}},
+		{name: "ctrl+c discards the line", inputs: []string{"rm -rf /", "\x03", "whoami\r"}, commands: []string{"whoami"}},
+		{name: "arrow keys are dropped", inputs: []string{"\x1b[A", "\x1b[Dcat\x1bOB file\r"}, commands: []string{"cat file"}},
+		{name: "escape sequence split across inputs", inputs: []string{"echo\x1b", "[", "1;5C hi\r"}, commands: []string{"echo hi"}},
+		{name: "blank lines", inputs: []string{"\r", "   \r"}, commands: nil},
+		{name: "incomplete line", inputs: []string{"top"}, commands: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := &Line{}
+			var commands []string
+			for _, input := range tt.inputs {
+				commands = append(commands, line.Read(input)...)
+			}
+			assert.Equal(t, tt.commands, commands)
+		})
+	}
+}
+
+func TestLineReadLimitsLength(t *testing.T) {
+	line := &Line{}
+	commands := line.Read(strings.Repeat("a", maxLength+10) + "\r")
+	assert.Len(t, commands, 1)
+	assert.Len(t, commands[0], maxLength)
+}
+
+func TestLineFeed(t *testing.T) {
+	line := &Line{}
+	for _, char := range "echo hi" {
+		_, _, completed := line.Feed(char)
+		assert.False(t, completed)
+	}
+	command, edited, completed := line.Feed('\r')
+	assert.True(t, completed)
+	assert.False(t, edited)
+	assert.Equal(t, "echo hi", command)
+	_, _, completed = line.Feed('\r')
+	assert.False(t, completed)
+}
+
+func TestLineFeedEdited(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		command string
+		edited  bool
+	}{
+		{name: "history recall", input: "\x1b[A", command: "", edited: true},
+		{name: "tab completion", input: "cat fi\t", command: "cat fi", edited: true},
+		{name: "cursor moved", input: "rm -rf /tmp\x1b[D\x1b[D", command: "rm -rf /tmp", edited: true},
+		{name: "history search", input: "\x12rm", command: "rm", edited: true},
+		{name: "discarded edits", input: "\x1b[A\x03ls", command: "ls", edited: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := &Line{}
+			for _, char := range tt.input {
+				_, _, completed := line.Feed(char)
+				assert.False(t, completed)
+			}
+			command, edited, completed := line.Feed('\r')
+			assert.True(t, completed)
+			assert.Equal(t, tt.command, command)
+			assert.Equal(t, tt.edited, edited)
+		})
+	}
+}
@@ -33,6 +33,10 @@ import (
 	bean2 "github.com/devtron-labs/devtron/pkg/cluster/environment/bean"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
 	"github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/terminalPolicy"
+	terminalPolicyBean "github.com/devtron-labs/devtron/pkg/terminalPolicy/bean"
+	"github.com/devtron-labs/devtron/pkg/terminalRecording"
+	terminalRecordingBean "github.com/devtron-labs/devtron/pkg/terminalRecording/bean"
 	errors1 "github.com/juju/errors"
 	"go.uber.org/zap"
 	"io"
@@ -76,6 +80,10 @@ type TerminalSession struct {
 	namespace         string
 	clusterId         string
 	startedOn         time.Time
+	// recorder is nil when terminal recording is disabled
+	recorder *terminalRecording.Recorder
+	// commandGuard is nil when no command policy applies to the session
+	commandGuard *terminalPolicy.CommandGuard
 }
 
 // TerminalMessage is the messaging protocol between ShellController and TerminalSession.
@@ -119,8 +127,23 @@ func (t TerminalSession) Read(p []byte) (int, error) {
 
 	switch msg.Op {
 	case "stdin":
-		return copy(p, msg.Data), nil
+		if t.recorder != nil {
+			t.recorder.RecordInput(msg.Data)
+		}
+		if t.commandGuard == nil {
+			return copy(p, msg.Data), nil
+		}
+		input, messages := t.commandGuard.Filter(msg.Data)
+		for _, message := range messages {
+			if err := t.Toast(message); err != nil {
+				log.Println(err)
+			}
+		}
+		return copy(p, input), nil
 	case "resize":
+		if t.recorder != nil {
+			t.recorder.RecordResize(msg.Cols, msg.Rows)
+		}
 		t.sizeChan <- remotecommand.TerminalSize{Width: msg.Cols, Height: msg.Rows}
 		return 0, nil
 	default:
@@ -142,6 +165,9 @@ func (t TerminalSession) Write(p []byte) (int, error) {
 	if err = t.sockJSSession.Send(string(msg)); err != nil {
 		return 0, err
 	}
+	if t.recorder != nil {
+		t.recorder.RecordOutput(p)
+	}
 	return len(p), nil
 }
 
@@ -240,6 +266,10 @@ func (sm *SessionMap) Close(sessionId string, status uint32, reason string) {
 		terminalSession.contextCancelFunc()
 		close(terminalSession.bound)
 		delete(sm.Sessions, sessionId)
+		if terminalSession.recorder != nil {
+			// the recording is uploaded in the background to keep the session map unlocked
+			go terminalSession.recorder.Stop()
+		}
 	}
 
 }
@@ -457,12 +487,16 @@ type TerminalSessionHandlerImpl struct {
 	ephemeralContainerService    cluster.EphemeralContainerService
 	argoApplicationConfigService config.ArgoApplicationConfigService
 	ClusterReadService           read.ClusterReadService
+	terminalRecordingService     terminalRecording.TerminalRecordingService
+	terminalPolicyService        terminalPolicy.TerminalPolicyService
 }
 
 func NewTerminalSessionHandlerImpl(environmentService environment.EnvironmentService,
 	logger *zap.SugaredLogger, k8sUtil *k8s.K8sServiceImpl, ephemeralContainerService cluster.EphemeralContainerService,
 	argoApplicationConfigService config.ArgoApplicationConfigService,
-	ClusterReadService read.ClusterReadService) *TerminalSessionHandlerImpl {
+	ClusterReadService read.ClusterReadService,
+	terminalRecordingService terminalRecording.TerminalRecordingService,
+	terminalPolicyService terminalPolicy.TerminalPolicyService) *TerminalSessionHandlerImpl {
 	return &TerminalSessionHandlerImpl{
 		environmentService:           environmentService,
 		logger:                       logger,
@@ -470,6 +504,8 @@ func NewTerminalSessionHandlerImpl(environmentService environment.EnvironmentSer
 		ephemeralContainerService:    ephemeralContainerService,
 		argoApplicationConfigService: argoApplicationConfigService,
 		ClusterReadService:           ClusterReadService,
+		terminalRecordingService:     terminalRecordingService,
+		terminalPolicyService:        terminalPolicyService,
 	}
 }
 
@@ -501,6 +537,20 @@ func (impl *TerminalSessionHandlerImpl) GetTerminalSession(req *TerminalSessionR
 		return statusCode, nil, err
 	}
 	req.SessionId = sessionID
+	commandGuard, err := impl.terminalPolicyService.GetCommandGuard(&terminalPolicyBean.SessionIdentity{
+		SessionId:     sessionID,
+		UserId:        req.UserId,
+		ClusterId:     req.ClusterId,
+		EnvironmentId: req.EnvironmentId,
+		Namespace:     req.Namespace,
+		PodName:       req.PodName,
+		ContainerName: req.ContainerName,
+	})
+	if err != nil {
+		// a session is not opened without its policies
+		impl.logger.Errorw("error in fetching terminal command policies", "clusterId", req.ClusterId, "envId", req.EnvironmentId, "err", err)
+		return http.StatusInternalServerError, nil, err
+	}
 	sessionCtx, cancelFunc := context.WithCancel(context.Background())
 	terminalSessions.Set(sessionID, TerminalSession{
 		id:                sessionID,
@@ -512,6 +562,18 @@ func (impl *TerminalSessionHandlerImpl) GetTerminalSession(req *TerminalSessionR
 		podName:           req.PodName,
 		namespace:         req.Namespace,
 		clusterId:         strconv.Itoa(req.ClusterId),
+		recorder: impl.terminalRecordingService.StartRecording(&terminalRecordingBean.RecordingIdentity{
+			SessionId:     sessionID,
+			UserId:        req.UserId,
+			ClusterId:     req.ClusterId,
+			Namespace:     req.Namespace,
+			PodName:       req.PodName,
+			ContainerName: req.ContainerName,
+			Shell:         req.Shell,
+			AppId:         req.AppId,
+			EnvironmentId: req.EnvironmentId,
+		}),
+		commandGuard: commandGuard,
 	})
 	config, client, err := impl.getClientSetAndRestConfigForTerminalConn(req)
 
@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package terminalPolicy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devtron-labs/devtron/pkg/terminal/commandLine"
+	"github.com/devtron-labs/devtron/pkg/terminalPolicy/bean"
+)
+
+// editedCommandReason is given for a line the shell may have changed, its real command is unknown
+const editedCommandReason = "it was edited with arrow keys, history recall or tab completion, type it in full"
+
+// CommandGuard enforces the command policies of a terminal session on its input. The keystrokes of a line reach
+// the shell as they are typed, a blocked command is stopped by replacing its enter key with ctrl+c. A line edited
+// with escape sequences or tab is blocked as well, what the shell would run can't be told from the keystrokes
+type CommandGuard struct {
+	identity  *bean.SessionIdentity
+	rules     *commandRules
+	line      commandLine.Line
+	onBlocked func(identity *bean.SessionIdentity, command string, reason string)
+}
+
+// Filter returns the input to pass on to the shell and a message for every command blocked in it
+func (guard *CommandGuard) Filter(data string) (string, []string) {
+	var messages []string
+	var input strings.Builder
+	for _, char := range data {
+		command, edited, completed := guard.line.Feed(char)
+		if completed {
+			reason := guard.rules.evaluate(command)
+			if edited {
+				reason = editedCommandReason
+			}
+			if len(reason) > 0 {
+				input.WriteRune(bean.BlockedCommandReplacement)
+				messages = append(messages, fmt.Sprintf("command %q is blocked as %s", command, reason))
+				guard.onBlocked(guard.identity, command, reason)
+				continue
+			}
+		}
+		input.WriteRune(char)
+	}
+	return input.String(), messages
+}
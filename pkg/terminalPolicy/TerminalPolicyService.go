@@ -0,0 +1,210 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package terminalPolicy
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	auditLogBean "github.com/devtron-labs/devtron/pkg/auditLog/bean"
+	envRepository "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster/read"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/terminalPolicy/bean"
+	"github.com/devtron-labs/devtron/pkg/terminalPolicy/repository"
+	"go.uber.org/zap"
+)
+
+type TerminalPolicyService interface {
+	GetAll() ([]*bean.CommandPolicyDto, error)
+	Create(request *bean.CommandPolicyDto) (*bean.CommandPolicyDto, error)
+	Update(request *bean.CommandPolicyDto) (*bean.CommandPolicyDto, error)
+	Delete(id int, userId int32) error
+	// GetCommandGuard returns the guard enforcing the policies of a terminal session, nil when no policy applies to it
+	GetCommandGuard(identity *bean.SessionIdentity) (*CommandGuard, error)
+}
+
+type TerminalPolicyServiceImpl struct {
+	logger                          *zap.SugaredLogger
+	terminalCommandPolicyRepository repository.TerminalCommandPolicyRepository
+	environmentRepository           envRepository.EnvironmentRepository
+	clusterReadService              read.ClusterReadService
+	auditLogService                 auditLog.AuditLogService
+}
+
+func NewTerminalPolicyServiceImpl(logger *zap.SugaredLogger,
+	terminalCommandPolicyRepository repository.TerminalCommandPolicyRepository,
+	environmentRepository envRepository.EnvironmentRepository,
+	clusterReadService read.ClusterReadService,
+	auditLogService auditLog.AuditLogService) *TerminalPolicyServiceImpl {
+	return &TerminalPolicyServiceImpl{
+		logger:                          logger,
+		terminalCommandPolicyRepository: terminalCommandPolicyRepository,
+		environmentRepository:           environmentRepository,
+		clusterReadService:              clusterReadService,
+		auditLogService:                 auditLogService,
+	}
+}
+
+func (impl *TerminalPolicyServiceImpl) GetAll() ([]*bean.CommandPolicyDto, error) {
+	models, err := impl.terminalCommandPolicyRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in fetching terminal command policies", "err", err)
+		return nil, err
+	}
+	policies := make([]*bean.CommandPolicyDto, 0, len(models))
+	for _, model := range models {
+		policies = append(policies, toDto(model))
+	}
+	return policies, nil
+}
+
+func (impl *TerminalPolicyServiceImpl) Create(request *bean.CommandPolicyDto) (*bean.CommandPolicyDto, error) {
+	err := impl.validate(request)
+	if err != nil {
+		return nil, err
+	}
+	model := toModel(request, &repository.TerminalCommandPolicy{})
+	model.AuditLog = sql.NewDefaultAuditLog(request.UserId)
+	err = impl.terminalCommandPolicyRepository.Save(model)
+	if err != nil {
+		impl.logger.Errorw("error in saving terminal command policy", "request", request, "err", err)
+		return nil, err
+	}
+	return toDto(model), nil
+}
+
+func (impl *TerminalPolicyServiceImpl) Update(request *bean.CommandPolicyDto) (*bean.CommandPolicyDto, error) {
+	existing, err := impl.getPolicy(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.validate(request)
+	if err != nil {
+		return nil, err
+	}
+	model := toModel(request, existing)
+	model.UpdateAuditLog(request.UserId)
+	err = impl.terminalCommandPolicyRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in updating terminal command policy", "request", request, "err", err)
+		return nil, err
+	}
+	return toDto(model), nil
+}
+
+func (impl *TerminalPolicyServiceImpl) Delete(id int, userId int32) error {
+	policy, err := impl.getPolicy(id)
+	if err != nil {
+		return err
+	}
+	policy.Active = false
+	policy.UpdateAuditLog(userId)
+	err = impl.terminalCommandPolicyRepository.Update(policy)
+	if err != nil {
+		impl.logger.Errorw("error in deleting terminal command policy", "id", id, "err", err)
+	}
+	return err
+}
+
+func (impl *TerminalPolicyServiceImpl) GetCommandGuard(identity *bean.SessionIdentity) (*CommandGuard, error) {
+	if identity.ClusterId == 0 && identity.EnvironmentId > 0 {
+		environment, err := impl.environmentRepository.FindById(identity.EnvironmentId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment of terminal session", "envId", identity.EnvironmentId, "err", err)
+			return nil, err
+		}
+		identity.ClusterId = environment.ClusterId
+	}
+	policies, err := impl.terminalCommandPolicyRepository.FindBySession(identity.ClusterId, identity.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching terminal command policies of session", "clusterId", identity.ClusterId, "envId", identity.EnvironmentId, "err", err)
+		return nil, err
+	}
+	rules, err := getCommandRules(policies)
+	if err != nil {
+		impl.logger.Errorw("error in compiling terminal command policies", "clusterId", identity.ClusterId, "envId", identity.EnvironmentId, "err", err)
+		return nil, err
+	}
+	if rules.isEmpty() {
+		return nil, nil
+	}
+	return &CommandGuard{identity: identity, rules: rules, onBlocked: impl.auditBlockedCommand}, nil
+}
+
+func (impl *TerminalPolicyServiceImpl) auditBlockedCommand(identity *bean.SessionIdentity, command string, reason string) {
+	impl.logger.Infow("terminal command blocked", "sessionId", identity.SessionId, "userId", identity.UserId, "command", command, "reason", reason)
+	impl.auditLogService.Record(&auditLogBean.AuditEvent{
+		UserId:   identity.UserId,
+		Method:   bean.AuditMethodTerminalExec,
+		Path:     bean.AuditPathTerminal,
+		Resource: bean.AuditResourceTerminal,
+		ResourceIds: map[string]string{
+			"sessionId":     identity.SessionId,
+			"clusterId":     strconv.Itoa(identity.ClusterId),
+			"environmentId": strconv.Itoa(identity.EnvironmentId),
+			"namespace":     identity.Namespace,
+			"pod":           identity.PodName,
+			"container":     identity.ContainerName,
+			"command":       command,
+			"reason":        reason,
+		},
+		Action:      auditLogBean.ActionExec,
+		Outcome:     auditLogBean.OutcomeFailure,
+		StatusCode:  http.StatusForbidden,
+		RequestHash: auditLog.HashPayload([]byte(command)),
+		CreatedOn:   time.Now(),
+	})
+}
+
+func (impl *TerminalPolicyServiceImpl) validate(request *bean.CommandPolicyDto) error {
+	err := validatePolicy(request)
+	if err != nil {
+		return err
+	}
+	if request.ClusterId > 0 {
+		_, err = impl.clusterReadService.FindById(request.ClusterId)
+	} else {
+		_, err = impl.environmentRepository.FindById(request.EnvironmentId)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in fetching scope of terminal command policy", "clusterId", request.ClusterId, "envId", request.EnvironmentId, "err", err)
+		if util.IsErrNoRows(err) {
+			message := fmt.Sprintf("cluster %d or environment %d of the policy does not exist", request.ClusterId, request.EnvironmentId)
+			return util.NewApiError(http.StatusBadRequest, message, message)
+		}
+		return err
+	}
+	return nil
+}
+
+func (impl *TerminalPolicyServiceImpl) getPolicy(id int) (*repository.TerminalCommandPolicy, error) {
+	policy, err := impl.terminalCommandPolicyRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching terminal command policy", "id", id, "err", err)
+		if util.IsErrNoRows(err) {
+			message := fmt.Sprintf("terminal command policy %d does not exist", id)
+			return nil, util.NewApiError(http.StatusNotFound, message, message)
+		}
+		return nil, err
+	}
+	return policy, nil
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+const (
+	// BlockedCommandReplacement is sent to the shell instead of the enter key of a blocked command, ctrl+c discards
+	// the typed line in every shell
+	BlockedCommandReplacement = '\x03'
+	AuditMethodTerminalExec   = "EXEC"
+	AuditResourceTerminal     = "terminal/command"
+	AuditPathTerminal         = "/orchestrator/k8s/pod/exec/sockjs/ws"
+)
+
+// CommandPolicyDto restricts the commands of the terminal sessions of a cluster or of an environment, exactly one
+// of them is set. BlockedPatterns and AllowedPatterns are regular expressions matched anywhere in a command line.
+// A command matching a blocked pattern is always rejected. When ReadOnly is set or AllowedPatterns is not empty,
+// only commands matching an allowed pattern, or read only commands in read only mode, are run
+type CommandPolicyDto struct {
+	Id              int      `json:"id"`
+	Name            string   `json:"name" validate:"required,max=250"`
+	ClusterId       int      `json:"clusterId,omitempty" validate:"gte=0"`
+	EnvironmentId   int      `json:"environmentId,omitempty" validate:"gte=0"`
+	BlockedPatterns []string `json:"blockedPatterns"`
+	AllowedPatterns []string `json:"allowedPatterns"`
+	ReadOnly        bool     `json:"readOnly"`
+	UserId          int32    `json:"-"`
+}
+
+// SessionIdentity is the terminal session policies are looked up and blocked commands are audited for, ClusterId
+// is resolved from the environment when the session was opened for an environment only
+type SessionIdentity struct {
+	SessionId     string
+	UserId        int32
+	ClusterId     int
+	EnvironmentId int
+	Namespace     string
+	PodName       string
+	ContainerName string
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package terminalPolicy
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/terminalPolicy/bean"
+	"github.com/devtron-labs/devtron/pkg/terminalPolicy/repository"
+)
+
+// readOnlyCommands are the programs which can't change anything, programs able to run other commands or to write
+// files with their arguments (find -exec, sed -i, less !cmd, env cmd, xargs) are left out on purpose
+var readOnlyCommands = map[string]bool{
+	"base64": true, "cat": true, "cd": true, "clear": true, "cut": true, "date": true, "df": true, "diff": true,
+	"dig": true, "du": true, "echo": true, "exit": true, "file": true, "free": true, "grep": true, "head": true,
+	"history": true, "hostname": true, "id": true, "jq": true, "ls": true, "lsof": true, "md5sum": true,
+	"netstat": true, "nproc": true, "nslookup": true, "ping": true, "printenv": true, "ps": true, "pwd": true,
+	"sha256sum": true, "sort": true, "ss": true, "stat": true, "tail": true, "top": true, "tr": true, "tree": true,
+	"uname": true, "uniq": true, "uptime": true, "wc": true, "which": true, "whoami": true,
+}
+
+// readOnlySubCommands are the read only sub commands of the clis which can change things with other sub commands
+var readOnlySubCommands = map[string]map[string]bool{
+	"kubectl": {
+		"api-resources": true, "api-versions": true, "cluster-info": true, "describe": true, "explain": true,
+		"get": true, "logs": true, "top": true, "version": true,
+	},
+	"helm": {"get": true, "history": true, "list": true, "ls": true, "show": true, "status": true, "version": true},
+}
+
+// commandSeparators split a command line into the commands it runs
+var commandSeparators = regexp.MustCompile(`\|\||&&|[|;&]`)
+
+type commandPattern struct {
+	policyName string
+	pattern    *regexp.Regexp
+}
+
+// commandRules are the policies of a session merged together
+type commandRules struct {
+	blocked        []*commandPattern
+	allowed        []*commandPattern
+	readOnlyPolicy string
+}
+
+func getCommandRules(policies []*repository.TerminalCommandPolicy) (*commandRules, error) {
+	rules := &commandRules{}
+	for _, policy := range policies {
+		blocked, err := compilePatterns(policy.Name, policy.BlockedPatterns)
+		if err != nil {
+			return nil, err
+		}
+		allowed, err := compilePatterns(policy.Name, policy.AllowedPatterns)
+		if err != nil {
+			return nil, err
+		}
+		rules.blocked = append(rules.blocked, blocked...)
+		rules.allowed = append(rules.allowed, allowed...)
+		if policy.ReadOnly && len(rules.readOnlyPolicy) == 0 {
+			rules.readOnlyPolicy = policy.Name
+		}
+	}
+	return rules, nil
+}
+
+func compilePatterns(policyName string, patterns []string) ([]*commandPattern, error) {
+	compiled := make([]*commandPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, &commandPattern{policyName: policyName, pattern: regex})
+	}
+	return compiled, nil
+}
+
+func (rules *commandRules) isEmpty() bool {
+	return len(rules.blocked) == 0 && len(rules.allowed) == 0 && len(rules.readOnlyPolicy) == 0
+}
+
+// evaluate returns the reason a command is not allowed, empty when it is allowed
+func (rules *commandRules) evaluate(command string) string {
+	// runs of spaces would let "rm  -rf /" slip past a pattern written as "rm -rf /"
+	command = strings.Join(strings.Fields(command), " ")
+	for _, blocked := range rules.blocked {
+		if blocked.pattern.MatchString(command) {
+			return fmt.Sprintf("it matches the blocked pattern %q of the policy %s", blocked.pattern.String(), blocked.policyName)
+		}
+	}
+	if len(rules.allowed) == 0 && len(rules.readOnlyPolicy) == 0 {
+		return ""
+	}
+	for _, allowed := range rules.allowed {
+		if allowed.pattern.MatchString(command) {
+			return ""
+		}
+	}
+	if len(rules.readOnlyPolicy) > 0 {
+		if isReadOnlyCommand(command) {
+			return ""
+		}
+		return fmt.Sprintf("the terminal is read only by the policy %s", rules.readOnlyPolicy)
+	}
+	return "it doesn't match any allowed pattern"
+}
+
+// isReadOnlyCommand checks every command of a command line, output redirection and command substitution are
+// rejected as they can write files or run anything
+func isReadOnlyCommand(command string) bool {
+	if strings.ContainsAny(command, ">`") || strings.Contains(command, "$(") {
+		return false
+	}
+	for _, part := range commandSeparators.Split(command, -1) {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		if readOnlyCommands[fields[0]] {
+			continue
+		}
+		subCommands, ok := readOnlySubCommands[fields[0]]
+		if !ok || !subCommands[getSubCommand(fields[1:])] {
+			return false
+		}
+	}
+	return true
+}
+
+// getSubCommand skips the flags before the sub command, like in kubectl -n default get pods. Only the namespace,
+// context and kubeconfig flags are known to take a separate value
+func getSubCommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "-") {
+			return args[i]
+		}
+		if !strings.Contains(args[i], "=") && (args[i] == "-n" || args[i] == "--namespace" || args[i] == "--context" || args[i] == "--kubeconfig") {
+			i++
+		}
+	}
+	return ""
+}
+
+func validatePolicy(request *bean.CommandPolicyDto) error {
+	if (request.ClusterId > 0) == (request.EnvironmentId > 0) {
+		return util.NewApiError(http.StatusBadRequest, "a policy applies to either a cluster or an environment", "exactly one of clusterId and environmentId is required")
+	}
+	if len(request.BlockedPatterns) == 0 && len(request.AllowedPatterns) == 0 && !request.ReadOnly {
+		return util.NewApiError(http.StatusBadRequest, "a policy needs blocked patterns, allowed patterns or read only mode", "empty policy")
+	}
+	for _, pattern := range append(append([]string{}, request.BlockedPatterns...), request.AllowedPatterns...) {
+		if len(strings.TrimSpace(pattern)) == 0 {
+			return util.NewApiError(http.StatusBadRequest, "command patterns can't be empty", "empty pattern")
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			message := fmt.Sprintf("invalid command pattern %q: %s", pattern, err.Error())
+			return util.NewApiError(http.StatusBadRequest, message, message)
+		}
+	}
+	return nil
+}
+
+func toModel(request *bean.CommandPolicyDto, model *repository.TerminalCommandPolicy) *repository.TerminalCommandPolicy {
+	model.Name = request.Name
+	model.ClusterId = request.ClusterId
+	model.EnvironmentId = request.EnvironmentId
+	model.BlockedPatterns = request.BlockedPatterns
+	model.AllowedPatterns = request.AllowedPatterns
+	model.ReadOnly = request.ReadOnly
+	model.Active = true
+	return model
+}
+
+func toDto(model *repository.TerminalCommandPolicy) *bean.CommandPolicyDto {
+	return &bean.CommandPolicyDto{
+		Id:              model.Id,
+		Name:            model.Name,
+		ClusterId:       model.ClusterId,
+		EnvironmentId:   model.EnvironmentId,
+		BlockedPatterns: model.BlockedPatterns,
+		AllowedPatterns: model.AllowedPatterns,
+		ReadOnly:        model.ReadOnly,
+	}
+}
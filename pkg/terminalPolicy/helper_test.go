@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package terminalPolicy
+
+import (
+	"testing"
+
+	"github.com/devtron-labs/devtron/pkg/terminalPolicy/bean"
+	"github.com/devtron-labs/devtron/pkg/terminalPolicy/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReadOnlyCommand(t *testing.T) {
+	tests := []struct {
+		command  string
+		readOnly bool
+	}{
+		{command: "ls -la /tmp", readOnly: true},
+		{command: "cat /etc/hosts | grep local | wc -l", readOnly: true},
+		{command: "cd /app && ls", readOnly: true},
+		{command: "kubectl get pods -A", readOnly: true},
+		{command: "kubectl -n default describe pod web", readOnly: true},
+		{command: "kubectl --namespace=default logs web", readOnly: true},
+		{command: "helm list", readOnly: true},
+		{command: "rm -rf /tmp/cache", readOnly: false},
+		{command: "echo hi > /tmp/file", readOnly: false},
+		{command: "ls; rm file", readOnly: false},
+		{command: "cat $(rm file)", readOnly: false},
+		{command: "kubectl delete pod web", readOnly: false},
+		{command: "kubectl -n get delete pod web", readOnly: false},
+		{command: "kubectl", readOnly: false},
+		{command: "find / -delete", readOnly: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			assert.Equal(t, tt.readOnly, isReadOnlyCommand(tt.command))
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	rules, err := getCommandRules([]*repository.TerminalCommandPolicy{
+		{Name: "prod-cluster", BlockedPatterns: []string{`rm -rf /`, `^kubectl delete`}},
+		{Name: "prod-env", AllowedPatterns: []string{`^kubectl rollout status`}, ReadOnly: true},
+	})
+	assert.NoError(t, err)
+	assert.False(t, rules.isEmpty())
+	assert.Empty(t, rules.evaluate("ls -la"))
+	assert.Empty(t, rules.evaluate("kubectl rollout status deploy/web"))
+	assert.Contains(t, rules.evaluate("rm   -rf   /"), "prod-cluster")
+	assert.Contains(t, rules.evaluate("kubectl delete pod web"), "blocked pattern")
+	assert.Contains(t, rules.evaluate("touch file"), "read only by the policy prod-env")
+
+	rules, err = getCommandRules([]*repository.TerminalCommandPolicy{{Name: "allow", AllowedPatterns: []string{`^kubectl get`}}})
+	assert.NoError(t, err)
+	assert.Empty(t, rules.evaluate("kubectl get pods"))
+	assert.Equal(t, "it doesn't match any allowed pattern", rules.evaluate("ls"))
+
+	rules, err = getCommandRules(nil)
+	assert.NoError(t, err)
+	assert.True(t, rules.isEmpty())
+	assert.Empty(t, rules.evaluate("rm -rf /"))
+}
+
+func TestValidatePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *bean.CommandPolicyDto
+		valid   bool
+	}{
+		{name: "cluster policy", request: &bean.CommandPolicyDto{ClusterId: 1, BlockedPatterns: []string{"rm -rf"}}, valid: true},
+		{name: "read only environment policy", request: &bean.CommandPolicyDto{EnvironmentId: 2, ReadOnly: true}, valid: true},
+		{name: "no scope", request: &bean.CommandPolicyDto{ReadOnly: true}},
+		{name: "both scopes", request: &bean.CommandPolicyDto{ClusterId: 1, EnvironmentId: 2, ReadOnly: true}},
+		{name: "no rules", request: &bean.CommandPolicyDto{ClusterId: 1}},
+		{name: "empty pattern", request: &bean.CommandPolicyDto{ClusterId: 1, AllowedPatterns: []string{" "}}},
+		{name: "invalid pattern", request: &bean.CommandPolicyDto{ClusterId: 1, BlockedPatterns: []string{"rm ("}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePolicy(tt.request)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestCommandGuardFilter(t *testing.T) {
+	rules, err := getCommandRules([]*repository.TerminalCommandPolicy{{Name: "prod", BlockedPatterns: []string{`kubectl delete`}}})
+	assert.NoError(t, err)
+	var blocked []string
+	guard := &CommandGuard{
+		identity: &bean.SessionIdentity{SessionId: "session"},
+		rules:    rules,
+		onBlocked: func(identity *bean.SessionIdentity, command string, reason string) {
+			blocked = append(blocked, command)
+		},
+	}
+
+	input, messages := guard.Filter("kubectl get pods\r")
+	assert.Equal(t, "kubectl get pods\r", input)
+	assert.Empty(t, messages)
+
+	input, messages = guard.Filter("kubectl delete")
+	assert.Equal(t, "kubectl delete", input)
+	assert.Empty(t, messages)
+	input, messages = guard.Filter(" pod web\rls\r")
+	assert.Equal(t, " pod web\x03ls\r", input)
+	assert.Len(t, messages, 1)
+	assert.Contains(t, messages[0], "kubectl delete pod web")
+	assert.Equal(t, []string{"kubectl delete pod web"}, blocked)
+
+	// a line recalled from the history or completed with tab can't be checked and is blocked
+	input, messages = guard.Filter("\x1b[A\r")
+	assert.Equal(t, "\x1b[A\x03", input)
+	assert.Len(t, messages, 1)
+	input, messages = guard.Filter("kubectl get po\t\r")
+	assert.Equal(t, "kubectl get po\t\x03", input)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, []string{"kubectl delete pod web", "", "kubectl get po"}, blocked)
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+)
+
+type TerminalCommandPolicy struct {
+	tableName       struct{} `sql:"terminal_command_policy" pg:",discard_unknown_columns"`
+	Id              int      `sql:"id,pk"`
+	Name            string   `sql:"name,notnull"`
+	ClusterId       int      `sql:"cluster_id"`
+	EnvironmentId   int      `sql:"environment_id"`
+	BlockedPatterns []string `sql:"blocked_patterns" pg:",array"`
+	AllowedPatterns []string `sql:"allowed_patterns" pg:",array"`
+	ReadOnly        bool     `sql:"read_only,notnull"`
+	Active          bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type TerminalCommandPolicyRepository interface {
+	Save(model *TerminalCommandPolicy) error
+	Update(model *TerminalCommandPolicy) error
+	FindById(id int) (*TerminalCommandPolicy, error)
+	FindAllActive() ([]*TerminalCommandPolicy, error)
+	// FindBySession returns the active policies of the cluster and of the environment of a session
+	FindBySession(clusterId int, environmentId int) ([]*TerminalCommandPolicy, error)
+}
+
+type TerminalCommandPolicyRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewTerminalCommandPolicyRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *TerminalCommandPolicyRepositoryImpl {
+	return &TerminalCommandPolicyRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *TerminalCommandPolicyRepositoryImpl) Save(model *TerminalCommandPolicy) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *TerminalCommandPolicyRepositoryImpl) Update(model *TerminalCommandPolicy) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *TerminalCommandPolicyRepositoryImpl) FindById(id int) (*TerminalCommandPolicy, error) {
+	model := &TerminalCommandPolicy{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *TerminalCommandPolicyRepositoryImpl) FindAllActive() ([]*TerminalCommandPolicy, error) {
+	var models []*TerminalCommandPolicy
+	err := impl.dbConnection.Model(&models).
+		Where("active = ?", true).
+		Order("id").
+		Select()
+	return models, err
+}
+
+func (impl *TerminalCommandPolicyRepositoryImpl) FindBySession(clusterId int, environmentId int) ([]*TerminalCommandPolicy, error) {
+	var models []*TerminalCommandPolicy
+	query := impl.dbConnection.Model(&models).
+		Where("active = ?", true)
+	if environmentId > 0 {
+		query = query.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.Where("cluster_id = ?", clusterId).WhereOr("environment_id = ?", environmentId), nil
+		})
+	} else {
+		query = query.Where("cluster_id = ?", clusterId)
+	}
+	err := query.Order("id").Select()
+	return models, err
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package terminalRecording
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/terminal/commandLine"
+	"github.com/devtron-labs/devtron/pkg/terminalRecording/bean"
+	"go.uber.org/zap"
+)
+
+// Recorder records a terminal session in asciicast v2 format. Events are appended to a temporary file as they
+// happen, the recording is put together when the session stops because its header needs the terminal size which
+// is known only after the first resize
+type Recorder struct {
+	logger    *zap.SugaredLogger
+	identity  *bean.RecordingIdentity
+	startedOn time.Time
+	onStop    func(recorder *Recorder, endedOn time.Time)
+
+	lock       sync.Mutex
+	eventFile  *os.File
+	eventCount int
+	width      uint16
+	height     uint16
+	input      commandLine.Line
+	commands   []string
+	failed     bool
+	stopped    bool
+}
+
+func newRecorder(logger *zap.SugaredLogger, identity *bean.RecordingIdentity, onStop func(recorder *Recorder, endedOn time.Time)) *Recorder {
+	return &Recorder{
+		logger:    logger,
+		identity:  identity,
+		startedOn: time.Now(),
+		onStop:    onStop,
+	}
+}
+
+func (r *Recorder) RecordOutput(data []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.record(bean.EventCodeOutput, string(data))
+}
+
+func (r *Recorder) RecordInput(data string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.commands = append(r.commands, r.input.Read(data)...)
+	r.record(bean.EventCodeInput, data)
+}
+
+func (r *Recorder) RecordResize(cols uint16, rows uint16) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	// the first size is the initial size of the terminal
+	if r.width == 0 {
+		r.width, r.height = cols, rows
+	}
+	r.record(bean.EventCodeResize, fmt.Sprintf("%dx%d", cols, rows))
+}
+
+// Stop ends the recording, the recording is saved by the stop callback
+func (r *Recorder) Stop() {
+	r.lock.Lock()
+	if r.stopped {
+		r.lock.Unlock()
+		return
+	}
+	r.stopped = true
+	if r.eventFile != nil {
+		err := r.eventFile.Close()
+		if err != nil {
+			r.logger.Errorw("error in closing terminal recording events", "sessionId", r.identity.SessionId, "err", err)
+			r.failed = true
+		}
+	}
+	r.lock.Unlock()
+	r.onStop(r, time.Now())
+}
+
+func (r *Recorder) record(code string, data string) {
+	if r.stopped || r.failed {
+		return
+	}
+	var err error
+	if r.eventFile == nil {
+		r.eventFile, err = os.CreateTemp("", "terminal-recording-*.events")
+		if err != nil {
+			r.logger.Errorw("error in creating terminal recording events, recording stopped", "sessionId", r.identity.SessionId, "err", err)
+			r.failed = true
+			return
+		}
+	}
+	event, err := getAsciicastEvent(time.Since(r.startedOn), code, data)
+	if err == nil {
+		_, err = r.eventFile.Write(event)
+	}
+	if err != nil {
+		r.logger.Errorw("error in writing terminal recording event, recording stopped", "sessionId", r.identity.SessionId, "err", err)
+		r.failed = true
+		return
+	}
+	r.eventCount++
+}
+
+// hasEvents is false for sessions which never got bound or whose recording failed, nothing is saved for them
+func (r *Recorder) hasEvents() bool {
+	return !r.failed && r.eventCount > 0
+}
+
+// writeRecording writes the asciicast recording, the header followed by the events, and returns its size
+func (r *Recorder) writeRecording(destination io.Writer) (int64, error) {
+	width, height := r.width, r.height
+	if width == 0 {
+		width, height = bean.DefaultTerminalWidth, bean.DefaultTerminalHeight
+	}
+	header, err := getAsciicastHeader(r.identity, width, height, r.startedOn)
+	if err != nil {
+		return 0, err
+	}
+	writer := bufio.NewWriter(destination)
+	headerSize, err := writer.Write(header)
+	if err != nil {
+		return 0, err
+	}
+	events, err := os.Open(r.eventFile.Name())
+	if err != nil {
+		return 0, err
+	}
+	defer events.Close()
+	eventsSize, err := io.Copy(writer, events)
+	if err != nil {
+		return 0, err
+	}
+	return int64(headerSize) + eventsSize, writer.Flush()
+}
+
+func (r *Recorder) removeEvents() {
+	if r.eventFile != nil {
+		_ = os.Remove(r.eventFile.Name())
+	}
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package terminalRecording
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/devtron-labs/devtron/pkg/terminalRecording/bean"
+)
+
+// recordingStorage keeps the recordings, keys are relative paths like 2024/01/31/<session id>.cast
+type recordingStorage interface {
+	Save(key string, sourcePath string) error
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+func newRecordingStorage(config *bean.TerminalRecordingConfig) (recordingStorage, error) {
+	switch config.StorageType {
+	case bean.StorageTypeLocal:
+		err := os.MkdirAll(config.LocalPath, 0750)
+		if err != nil {
+			return nil, err
+		}
+		return &localRecordingStorage{root: config.LocalPath}, nil
+	case bean.StorageTypeS3:
+		if len(config.S3BucketName) == 0 {
+			return nil, fmt.Errorf("s3 bucket name is not configured")
+		}
+		awsConfig := &aws.Config{Region: aws.String(config.S3Region)}
+		if len(config.S3AccessKey) > 0 {
+			awsConfig.Credentials = credentials.NewStaticCredentials(config.S3AccessKey, config.S3SecretKey, "")
+		}
+		if len(config.S3Endpoint) > 0 {
+			// s3 compatible storage
+			awsConfig.Endpoint = aws.String(config.S3Endpoint)
+			awsConfig.DisableSSL = aws.Bool(config.S3Insecure)
+			awsConfig.S3ForcePathStyle = aws.Bool(true)
+		}
+		s3Session, err := session.NewSession(awsConfig)
+		if err != nil {
+			return nil, err
+		}
+		return &s3RecordingStorage{session: s3Session, bucketName: config.S3BucketName}, nil
+	default:
+		return nil, fmt.Errorf("unsupported terminal recording storage %s", config.StorageType)
+	}
+}
+
+type localRecordingStorage struct {
+	root string
+}
+
+func (impl *localRecordingStorage) getPath(key string) string {
+	// cleaning the key as an absolute path keeps it inside the root
+	return filepath.Join(impl.root, filepath.Clean("/"+key))
+}
+
+func (impl *localRecordingStorage) Save(key string, sourcePath string) error {
+	destinationPath := impl.getPath(key)
+	err := os.MkdirAll(filepath.Dir(destinationPath), 0750)
+	if err != nil {
+		return err
+	}
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	destination, err := os.OpenFile(destinationPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(destination, source)
+	if closeErr := destination.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (impl *localRecordingStorage) Open(key string) (io.ReadCloser, error) {
+	return os.Open(impl.getPath(key))
+}
+
+func (impl *localRecordingStorage) Delete(key string) error {
+	err := os.Remove(impl.getPath(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+type s3RecordingStorage struct {
+	session    *session.Session
+	bucketName string
+}
+
+func (impl *s3RecordingStorage) Save(key string, sourcePath string) error {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	_, err = s3manager.NewUploader(impl.session).Upload(&s3manager.UploadInput{
+		Bucket:      aws.String(impl.bucketName),
+		Key:         aws.String(key),
+		Body:        source,
+		ContentType: aws.String(bean.AsciicastContentType),
+	})
+	return err
+}
+
+func (impl *s3RecordingStorage) Open(key string) (io.ReadCloser, error) {
+	output, err := s3.New(impl.session).GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(impl.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Body, nil
+}
+
+func (impl *s3RecordingStorage) Delete(key string) error {
+	_, err := s3.New(impl.session).DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(impl.bucketName),
+		Key:    aws.String(key),
+	})
+	return err
+}
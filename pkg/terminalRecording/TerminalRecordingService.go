@@ -0,0 +1,238 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package terminalRecording
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/util"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/devtron-labs/devtron/pkg/terminalRecording/bean"
+	"github.com/devtron-labs/devtron/pkg/terminalRecording/repository"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// retentionBatchSize is the number of expired recordings deleted per query
+const retentionBatchSize = 500
+
+type TerminalRecordingService interface {
+	// StartRecording returns the recorder of a new terminal session, nil when recording is disabled
+	StartRecording(identity *bean.RecordingIdentity) *Recorder
+	GetAll(filter *bean.RecordingFilter) (*bean.RecordingListResponse, error)
+	GetById(id int) (*bean.RecordingDto, error)
+	// GetRecording returns the asciicast recording of a session, the caller has to close it
+	GetRecording(id int) (io.ReadCloser, error)
+	DeleteExpiredRecordings()
+}
+
+type TerminalRecordingServiceImpl struct {
+	logger                             *zap.SugaredLogger
+	terminalSessionRecordingRepository repository.TerminalSessionRecordingRepository
+	userRepository                     userRepository.UserRepository
+	storage                            recordingStorage
+	config                             *bean.TerminalRecordingConfig
+}
+
+func NewTerminalRecordingServiceImpl(logger *zap.SugaredLogger,
+	terminalSessionRecordingRepository repository.TerminalSessionRecordingRepository,
+	userRepository userRepository.UserRepository,
+	cronLogger *cron2.CronLoggerImpl) (*TerminalRecordingServiceImpl, error) {
+	config := &bean.TerminalRecordingConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing terminal recording config", "err", err)
+		return nil, err
+	}
+	impl := &TerminalRecordingServiceImpl{
+		logger:                             logger,
+		terminalSessionRecordingRepository: terminalSessionRecordingRepository,
+		userRepository:                     userRepository,
+		config:                             config,
+	}
+	if !config.Enabled {
+		return impl, nil
+	}
+	impl.storage, err = newRecordingStorage(config)
+	if err != nil {
+		// a misconfigured storage must not keep terminals from working
+		logger.Errorw("error in initialising terminal recording storage, terminal sessions will not be recorded", "storageType", config.StorageType, "err", err)
+		config.Enabled = false
+		return impl, nil
+	}
+	if config.RetentionDays > 0 {
+		retentionCron := cron.New(cron.WithChain(cron.Recover(cronLogger)))
+		_, err = retentionCron.AddFunc(config.RetentionCron, impl.DeleteExpiredRecordings)
+		if err != nil {
+			logger.Errorw("error in adding terminal recording retention cron", "schedule", config.RetentionCron, "err", err)
+			return nil, err
+		}
+		retentionCron.Start()
+	}
+	return impl, nil
+}
+
+func (impl *TerminalRecordingServiceImpl) StartRecording(identity *bean.RecordingIdentity) *Recorder {
+	if !impl.config.Enabled {
+		return nil
+	}
+	return newRecorder(impl.logger, identity, impl.saveRecording)
+}
+
+func (impl *TerminalRecordingServiceImpl) GetAll(filter *bean.RecordingFilter) (*bean.RecordingListResponse, error) {
+	models, totalCount, err := impl.terminalSessionRecordingRepository.FindAll(getListFilter(filter))
+	if err != nil {
+		impl.logger.Errorw("error in fetching terminal recordings", "filter", filter, "err", err)
+		return nil, err
+	}
+	response := &bean.RecordingListResponse{Recordings: make([]*bean.RecordingDto, 0, len(models)), TotalCount: totalCount}
+	for _, model := range models {
+		response.Recordings = append(response.Recordings, getRecordingDto(model))
+	}
+	return response, nil
+}
+
+func (impl *TerminalRecordingServiceImpl) GetById(id int) (*bean.RecordingDto, error) {
+	model, err := impl.getRecordingModel(id)
+	if err != nil {
+		return nil, err
+	}
+	return getRecordingDto(model), nil
+}
+
+func (impl *TerminalRecordingServiceImpl) GetRecording(id int) (io.ReadCloser, error) {
+	model, err := impl.getRecordingModel(id)
+	if err != nil {
+		return nil, err
+	}
+	if impl.storage == nil {
+		return nil, util.NewApiError(http.StatusServiceUnavailable, "terminal recording storage is not configured", "storage not configured")
+	}
+	recording, err := impl.storage.Open(model.StorageKey)
+	if err != nil {
+		impl.logger.Errorw("error in opening terminal recording", "id", id, "storageKey", model.StorageKey, "err", err)
+		return nil, util.NewApiError(http.StatusNotFound, "recording not found in storage, it may have been deleted", err.Error())
+	}
+	return recording, nil
+}
+
+func (impl *TerminalRecordingServiceImpl) DeleteExpiredRecordings() {
+	before := time.Now().AddDate(0, 0, -impl.config.RetentionDays)
+	for {
+		models, err := impl.terminalSessionRecordingRepository.FindStartedBefore(before, retentionBatchSize)
+		if err != nil {
+			impl.logger.Errorw("error in fetching expired terminal recordings", "before", before, "err", err)
+			return
+		}
+		if len(models) == 0 {
+			return
+		}
+		ids := make([]int, 0, len(models))
+		for _, model := range models {
+			err = impl.storage.Delete(model.StorageKey)
+			if err != nil {
+				// the row is kept so that the deletion is retried on the next run
+				impl.logger.Errorw("error in deleting expired terminal recording", "id", model.Id, "storageKey", model.StorageKey, "err", err)
+				continue
+			}
+			ids = append(ids, model.Id)
+		}
+		err = impl.terminalSessionRecordingRepository.DeleteByIds(ids)
+		if err != nil {
+			impl.logger.Errorw("error in deleting expired terminal recordings", "ids", ids, "err", err)
+			return
+		}
+		impl.logger.Infow("deleted expired terminal recordings", "count", len(ids), "before", before)
+		if len(ids) < len(models) || len(models) < retentionBatchSize {
+			return
+		}
+	}
+}
+
+func (impl *TerminalRecordingServiceImpl) getRecordingModel(id int) (*repository.TerminalSessionRecording, error) {
+	model, err := impl.terminalSessionRecordingRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching terminal recording", "id", id, "err", err)
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "recording not found", err.Error())
+		}
+		return nil, err
+	}
+	return model, nil
+}
+
+// saveRecording is called when a session ends, it puts the recording together, stores it and saves its metadata
+func (impl *TerminalRecordingServiceImpl) saveRecording(recorder *Recorder, endedOn time.Time) {
+	defer recorder.removeEvents()
+	if !recorder.hasEvents() {
+		return
+	}
+	identity := recorder.identity
+	recordingFile, err := os.CreateTemp("", "terminal-recording-*"+bean.AsciicastExtension)
+	if err != nil {
+		impl.logger.Errorw("error in creating terminal recording", "sessionId", identity.SessionId, "err", err)
+		return
+	}
+	defer os.Remove(recordingFile.Name())
+	size, err := recorder.writeRecording(recordingFile)
+	if closeErr := recordingFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		impl.logger.Errorw("error in writing terminal recording", "sessionId", identity.SessionId, "err", err)
+		return
+	}
+	storageKey := getStorageKey(identity.SessionId, recorder.startedOn)
+	err = impl.storage.Save(storageKey, recordingFile.Name())
+	if err != nil {
+		impl.logger.Errorw("error in storing terminal recording", "sessionId", identity.SessionId, "storageKey", storageKey, "err", err)
+		return
+	}
+	model := &repository.TerminalSessionRecording{
+		SessionId:     identity.SessionId,
+		UserId:        identity.UserId,
+		ClusterId:     identity.ClusterId,
+		Namespace:     identity.Namespace,
+		PodName:       identity.PodName,
+		ContainerName: identity.ContainerName,
+		Shell:         identity.Shell,
+		AppId:         identity.AppId,
+		EnvironmentId: identity.EnvironmentId,
+		StorageKey:    storageKey,
+		SizeBytes:     size,
+		Commands:      strings.Join(recorder.commands, "\n"),
+		StartedOn:     recorder.startedOn,
+		EndedOn:       endedOn,
+	}
+	// the email is kept with the recording as users can be deleted before their recordings expire
+	user, err := impl.userRepository.GetByIdIncludeDeleted(identity.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user of terminal recording", "userId", identity.UserId, "err", err)
+	} else {
+		model.UserEmail = user.EmailId
+	}
+	err = impl.terminalSessionRecordingRepository.Save(model)
+	if err != nil {
+		impl.logger.Errorw("error in saving terminal recording", "sessionId", identity.SessionId, "storageKey", storageKey, "err", err)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type StorageType string
+
+const (
+	StorageTypeLocal StorageType = "LOCAL"
+	// StorageTypeS3 works with any s3 compatible storage through TERMINAL_RECORDING_S3_ENDPOINT
+	StorageTypeS3 StorageType = "S3"
+)
+
+const (
+	AsciicastVersion      = 2
+	AsciicastContentType  = "application/x-asciicast"
+	AsciicastExtension    = ".cast"
+	DefaultTerminalWidth  = 80
+	DefaultTerminalHeight = 24
+	DefaultListLimit      = 20
+	MaxListLimit          = 100
+)
+
+// asciicast v2 event codes
+const (
+	EventCodeOutput = "o"
+	EventCodeInput  = "i"
+	EventCodeResize = "r"
+)
+
+type TerminalRecordingConfig struct {
+	Enabled       bool        `env:"TERMINAL_RECORDING_ENABLED" envDefault:"true" description:"Record pod and cluster terminal sessions in asciicast v2 format"`
+	StorageType   StorageType `env:"TERMINAL_RECORDING_STORAGE_TYPE" envDefault:"LOCAL" description:"LOCAL or S3"`
+	LocalPath     string      `env:"TERMINAL_RECORDING_LOCAL_PATH" envDefault:"/var/lib/devtron/terminal-recordings"`
+	S3BucketName  string      `env:"TERMINAL_RECORDING_S3_BUCKET_NAME"`
+	S3Region      string      `env:"TERMINAL_RECORDING_S3_REGION"`
+	S3Endpoint    string      `env:"TERMINAL_RECORDING_S3_ENDPOINT" description:"Endpoint of s3 compatible storages like minio, empty for aws s3"`
+	S3AccessKey   string      `env:"TERMINAL_RECORDING_S3_ACCESS_KEY"`
+	S3SecretKey   string      `env:"TERMINAL_RECORDING_S3_SECRET_KEY"`
+	S3Insecure    bool        `env:"TERMINAL_RECORDING_S3_INSECURE" envDefault:"false"`
+	RetentionDays int         `env:"TERMINAL_RECORDING_RETENTION_DAYS" envDefault:"90" description:"Recordings older than these many days are deleted, 0 keeps them forever"`
+	RetentionCron string      `env:"TERMINAL_RECORDING_RETENTION_CRON" envDefault:"0 2 * * *"`
+}
+
+// RecordingIdentity is who opened a terminal session on which container
+type RecordingIdentity struct {
+	SessionId     string
+	UserId        int32
+	ClusterId     int
+	Namespace     string
+	PodName       string
+	ContainerName string
+	Shell         string
+	AppId         int
+	EnvironmentId int
+}
+
+// AsciicastHeader is the first line of an asciicast v2 recording
+type AsciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     uint16            `json:"width"`
+	Height    uint16            `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+type RecordingFilter struct {
+	UserId    int32
+	ClusterId int
+	Namespace string
+	PodName   string
+	// Command matches the recordings with a command containing it
+	Command string
+	From    *time.Time
+	To      *time.Time
+	Offset  int
+	Limit   int
+}
+
+type RecordingDto struct {
+	Id            int       `json:"id"`
+	SessionId     string    `json:"sessionId"`
+	UserId        int32     `json:"userId"`
+	UserEmail     string    `json:"userEmail"`
+	ClusterId     int       `json:"clusterId"`
+	Namespace     string    `json:"namespace"`
+	PodName       string    `json:"podName"`
+	ContainerName string    `json:"containerName"`
+	Shell         string    `json:"shell,omitempty"`
+	AppId         int       `json:"appId,omitempty"`
+	EnvironmentId int       `json:"environmentId,omitempty"`
+	SizeBytes     int64     `json:"sizeBytes"`
+	StartedOn     time.Time `json:"startedOn"`
+	EndedOn       time.Time `json:"endedOn"`
+	// Commands are the command lines typed in the session, reconstructed from the input
+	Commands []string `json:"commands,omitempty"`
+}
+
+type RecordingListResponse struct {
+	Recordings []*RecordingDto `json:"recordings"`
+	TotalCount int             `json:"totalCount"`
+}
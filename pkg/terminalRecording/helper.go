@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package terminalRecording
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/terminalRecording/bean"
+	"github.com/devtron-labs/devtron/pkg/terminalRecording/repository"
+)
+
+func getAsciicastEvent(elapsed time.Duration, code string, data string) ([]byte, error) {
+	event, err := json.Marshal([]interface{}{elapsed.Seconds(), code, data})
+	if err != nil {
+		return nil, err
+	}
+	return append(event, '\n'), nil
+}
+
+func getAsciicastHeader(identity *bean.RecordingIdentity, width, height uint16, startedOn time.Time) ([]byte, error) {
+	header := bean.AsciicastHeader{
+		Version:   bean.AsciicastVersion,
+		Width:     width,
+		Height:    height,
+		Timestamp: startedOn.Unix(),
+		Title:     fmt.Sprintf("%s/%s/%s", identity.Namespace, identity.PodName, identity.ContainerName),
+		Env:       map[string]string{"TERM": "xterm"},
+	}
+	if len(identity.Shell) > 0 {
+		header.Env["SHELL"] = identity.Shell
+	}
+	headerJson, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	return append(headerJson, '\n'), nil
+}
+
+// getStorageKey spreads recordings over day prefixes which keeps listing and retention cheap on object storages
+func getStorageKey(sessionId string, startedOn time.Time) string {
+	return fmt.Sprintf("%s/%s%s", startedOn.UTC().Format("2006/01/02"), sessionId, bean.AsciicastExtension)
+}
+
+func getRecordingDto(model *repository.TerminalSessionRecording) *bean.RecordingDto {
+	dto := &bean.RecordingDto{
+		Id:            model.Id,
+		SessionId:     model.SessionId,
+		UserId:        model.UserId,
+		UserEmail:     model.UserEmail,
+		ClusterId:     model.ClusterId,
+		Namespace:     model.Namespace,
+		PodName:       model.PodName,
+		ContainerName: model.ContainerName,
+		Shell:         model.Shell,
+		AppId:         model.AppId,
+		EnvironmentId: model.EnvironmentId,
+		SizeBytes:     model.SizeBytes,
+		StartedOn:     model.StartedOn,
+		EndedOn:       model.EndedOn,
+	}
+	if len(model.Commands) > 0 {
+		dto.Commands = strings.Split(model.Commands, "\n")
+	}
+	return dto
+}
+
+func getListFilter(filter *bean.RecordingFilter) *repository.RecordingListFilter {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = bean.DefaultListLimit
+	} else if limit > bean.MaxListLimit {
+		limit = bean.MaxListLimit
+	}
+	return &repository.RecordingListFilter{
+		UserId:    filter.UserId,
+		ClusterId: filter.ClusterId,
+		Namespace: filter.Namespace,
+		PodName:   filter.PodName,
+		Command:   filter.Command,
+		From:      filter.From,
+		To:        filter.To,
+		Offset:    filter.Offset,
+		Limit:     limit,
+	}
+}
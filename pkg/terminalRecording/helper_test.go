@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package terminalRecording
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/terminalRecording/bean"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestGetStorageKey(t *testing.T) {
+	startedOn := time.Date(2024, 3, 9, 23, 30, 0, 0, time.FixedZone("IST", 5*60*60+30*60))
+	assert.Equal(t, "2024/03/09/abc.cast", getStorageKey("abc", startedOn))
+}
+
+func TestGetListFilter(t *testing.T) {
+	assert.Equal(t, bean.DefaultListLimit, getListFilter(&bean.RecordingFilter{}).Limit)
+	assert.Equal(t, bean.MaxListLimit, getListFilter(&bean.RecordingFilter{Limit: bean.MaxListLimit + 1}).Limit)
+	filter := getListFilter(&bean.RecordingFilter{UserId: 2, Command: "kubectl", Offset: 40, Limit: 10})
+	assert.Equal(t, int32(2), filter.UserId)
+	assert.Equal(t, "kubectl", filter.Command)
+	assert.Equal(t, 40, filter.Offset)
+	assert.Equal(t, 10, filter.Limit)
+}
+
+func TestRecorder(t *testing.T) {
+	identity := &bean.RecordingIdentity{SessionId: "session", Namespace: "default", PodName: "pod", ContainerName: "app", Shell: "sh"}
+	var stopped *Recorder
+	recorder := newRecorder(zap.NewNop().Sugar(), identity, func(recorder *Recorder, endedOn time.Time) {
+		stopped = recorder
+	})
+	defer recorder.removeEvents()
+	assert.False(t, recorder.hasEvents())
+
+	recorder.RecordResize(120, 40)
+	recorder.RecordResize(100, 30)
+	recorder.RecordInput("ls\r")
+	recorder.RecordOutput([]byte("file\r\n"))
+	recorder.Stop()
+	recorder.Stop()
+	recorder.RecordOutput([]byte("after stop"))
+	assert.Equal(t, recorder, stopped)
+	assert.True(t, recorder.hasEvents())
+	assert.Equal(t, []string{"ls"}, recorder.commands)
+
+	var recording bytes.Buffer
+	size, err := recorder.writeRecording(&recording)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(recording.Len()), size)
+
+	lines := strings.Split(strings.TrimSuffix(recording.String(), "\n"), "\n")
+	assert.Len(t, lines, 5)
+	var header bean.AsciicastHeader
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &header))
+	assert.Equal(t, bean.AsciicastVersion, header.Version)
+	assert.Equal(t, uint16(120), header.Width)
+	assert.Equal(t, uint16(40), header.Height)
+	assert.Equal(t, "default/pod/app", header.Title)
+	assert.Equal(t, "sh", header.Env["SHELL"])
+
+	expectedEvents := [][]string{{"r", "120x40"}, {"r", "100x30"}, {"i", "ls\r"}, {"o", "file\r\n"}}
+	for i, line := range lines[1:] {
+		var event []interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &event))
+		assert.Len(t, event, 3)
+		assert.GreaterOrEqual(t, event[0].(float64), 0.0)
+		assert.Equal(t, expectedEvents[i][0], event[1])
+		assert.Equal(t, expectedEvents[i][1], event[2])
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"time"
+
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// TerminalSessionRecording is the metadata of a recorded terminal session, the recording is kept in the configured storage
+type TerminalSessionRecording struct {
+	tableName     struct{}  `sql:"terminal_session_recording" pg:",discard_unknown_columns"`
+	Id            int       `sql:"id,pk"`
+	SessionId     string    `sql:"session_id,notnull"`
+	UserId        int32     `sql:"user_id,notnull"`
+	UserEmail     string    `sql:"user_email"`
+	ClusterId     int       `sql:"cluster_id"`
+	Namespace     string    `sql:"namespace"`
+	PodName       string    `sql:"pod_name"`
+	ContainerName string    `sql:"container_name"`
+	Shell         string    `sql:"shell"`
+	AppId         int       `sql:"app_id"`
+	EnvironmentId int       `sql:"environment_id"`
+	StorageKey    string    `sql:"storage_key,notnull"`
+	SizeBytes     int64     `sql:"size_bytes,notnull"`
+	Commands      string    `sql:"commands"`
+	StartedOn     time.Time `sql:"started_on,notnull"`
+	EndedOn       time.Time `sql:"ended_on,notnull"`
+}
+
+type RecordingListFilter struct {
+	UserId    int32
+	ClusterId int
+	Namespace string
+	PodName   string
+	Command   string
+	From      *time.Time
+	To        *time.Time
+	Offset    int
+	Limit     int
+}
+
+type TerminalSessionRecordingRepository interface {
+	Save(model *TerminalSessionRecording) error
+	FindById(id int) (*TerminalSessionRecording, error)
+	FindAll(filter *RecordingListFilter) ([]*TerminalSessionRecording, int, error)
+	FindStartedBefore(before time.Time, limit int) ([]*TerminalSessionRecording, error)
+	DeleteByIds(ids []int) error
+}
+
+type TerminalSessionRecordingRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewTerminalSessionRecordingRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *TerminalSessionRecordingRepositoryImpl {
+	return &TerminalSessionRecordingRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *TerminalSessionRecordingRepositoryImpl) Save(model *TerminalSessionRecording) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *TerminalSessionRecordingRepositoryImpl) FindById(id int) (*TerminalSessionRecording, error) {
+	model := &TerminalSessionRecording{}
+	err := impl.dbConnection.Model(model).Where("id = ?", id).Select()
+	return model, err
+}
+
+func (impl *TerminalSessionRecordingRepositoryImpl) FindAll(filter *RecordingListFilter) ([]*TerminalSessionRecording, int, error) {
+	var models []*TerminalSessionRecording
+	query := impl.dbConnection.Model(&models)
+	if filter.UserId > 0 {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+	if filter.ClusterId > 0 {
+		query = query.Where("cluster_id = ?", filter.ClusterId)
+	}
+	if len(filter.Namespace) > 0 {
+		query = query.Where("namespace = ?", filter.Namespace)
+	}
+	if len(filter.PodName) > 0 {
+		query = query.Where("pod_name = ?", filter.PodName)
+	}
+	if len(filter.Command) > 0 {
+		query = query.Where("commands ILIKE ?", "%"+filter.Command+"%")
+	}
+	if filter.From != nil {
+		query = query.Where("started_on >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("started_on <= ?", *filter.To)
+	}
+	totalCount, err := query.Order("started_on desc").Offset(filter.Offset).Limit(filter.Limit).SelectAndCount()
+	return models, totalCount, err
+}
+
+func (impl *TerminalSessionRecordingRepositoryImpl) FindStartedBefore(before time.Time, limit int) ([]*TerminalSessionRecording, error) {
+	var models []*TerminalSessionRecording
+	err := impl.dbConnection.Model(&models).
+		Where("started_on < ?", before).
+		Order("started_on asc").
+		Limit(limit).
+		Select()
+	return models, err
+}
+
+func (impl *TerminalSessionRecordingRepositoryImpl) DeleteByIds(ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := impl.dbConnection.Model(&TerminalSessionRecording{}).Where("id IN (?)", pg.In(ids)).Delete()
+	return err
+}
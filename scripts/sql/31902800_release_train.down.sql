@@ -0,0 +1,13 @@
+-- Begin Transaction
+BEGIN;
+
+DROP TABLE IF EXISTS public.release_train_deployment_app;
+DROP SEQUENCE IF EXISTS public.id_seq_release_train_deployment_app;
+DROP TABLE IF EXISTS public.release_train_deployment;
+DROP SEQUENCE IF EXISTS public.id_seq_release_train_deployment;
+DROP TABLE IF EXISTS public.release_train_app;
+DROP SEQUENCE IF EXISTS public.id_seq_release_train_app;
+DROP TABLE IF EXISTS public.release_train;
+DROP SEQUENCE IF EXISTS public.id_seq_release_train;
+
+COMMIT;
@@ -0,0 +1,96 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_release_train;
+
+CREATE TABLE IF NOT EXISTS public.release_train
+(
+    id          INTEGER      NOT NULL DEFAULT nextval('public.id_seq_release_train'::regclass),
+    name        VARCHAR(250) NOT NULL,
+    version     VARCHAR(100) NOT NULL,
+    description TEXT,
+    active      BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_on  TIMESTAMPTZ  NOT NULL,
+    created_by  INT4         NOT NULL,
+    updated_on  TIMESTAMPTZ  NOT NULL,
+    updated_by  INT4         NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS release_train_name_version_unique
+    ON public.release_train (name, version)
+    WHERE active = TRUE;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_release_train_app;
+
+-- pins an app to an artifact and, optionally, to the config deployed by a specific cd_workflow_runner
+CREATE TABLE IF NOT EXISTS public.release_train_app
+(
+    id                 INTEGER     NOT NULL DEFAULT nextval('public.id_seq_release_train_app'::regclass),
+    release_train_id   INTEGER     NOT NULL,
+    app_id             INTEGER     NOT NULL,
+    ci_artifact_id     INTEGER     NOT NULL,
+    source_pipeline_id INTEGER,
+    source_wfr_id      INTEGER,
+    created_on         TIMESTAMPTZ NOT NULL,
+    created_by         INT4        NOT NULL,
+    updated_on         TIMESTAMPTZ NOT NULL,
+    updated_by         INT4        NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT release_train_app_release_train_id_fkey FOREIGN KEY (release_train_id) REFERENCES public.release_train (id),
+    CONSTRAINT release_train_app_app_id_fkey FOREIGN KEY (app_id) REFERENCES public.app (id),
+    CONSTRAINT release_train_app_ci_artifact_id_fkey FOREIGN KEY (ci_artifact_id) REFERENCES public.ci_artifact (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS release_train_app_release_train_id_app_id_unique
+    ON public.release_train_app (release_train_id, app_id);
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_release_train_deployment;
+
+CREATE TABLE IF NOT EXISTS public.release_train_deployment
+(
+    id                     INTEGER     NOT NULL DEFAULT nextval('public.id_seq_release_train_deployment'::regclass),
+    release_train_id       INTEGER     NOT NULL,
+    environment_id         INTEGER     NOT NULL,
+    status                 VARCHAR(50) NOT NULL,
+    is_rollback            BOOLEAN     NOT NULL DEFAULT FALSE,
+    rollback_source_id     INTEGER,
+    created_on             TIMESTAMPTZ NOT NULL,
+    created_by             INT4        NOT NULL,
+    updated_on             TIMESTAMPTZ NOT NULL,
+    updated_by             INT4        NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT release_train_deployment_release_train_id_fkey FOREIGN KEY (release_train_id) REFERENCES public.release_train (id),
+    CONSTRAINT release_train_deployment_environment_id_fkey FOREIGN KEY (environment_id) REFERENCES public.environment (id)
+);
+
+CREATE INDEX IF NOT EXISTS release_train_deployment_env_id_idx
+    ON public.release_train_deployment (environment_id);
+
+-- at most one release can be in progress in an environment
+CREATE UNIQUE INDEX IF NOT EXISTS release_train_deployment_env_in_progress_unique
+    ON public.release_train_deployment (environment_id)
+    WHERE status IN ('Initiated', 'Progressing');
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_release_train_deployment_app;
+
+CREATE TABLE IF NOT EXISTS public.release_train_deployment_app
+(
+    id                          INTEGER     NOT NULL DEFAULT nextval('public.id_seq_release_train_deployment_app'::regclass),
+    release_train_deployment_id INTEGER     NOT NULL,
+    app_id                      INTEGER     NOT NULL,
+    pipeline_id                 INTEGER     NOT NULL,
+    ci_artifact_id              INTEGER     NOT NULL,
+    cd_workflow_runner_id       INTEGER,
+    status                      VARCHAR(50) NOT NULL,
+    message                     TEXT,
+    created_on                  TIMESTAMPTZ NOT NULL,
+    created_by                  INT4        NOT NULL,
+    updated_on                  TIMESTAMPTZ NOT NULL,
+    updated_by                  INT4        NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT release_train_deployment_app_deployment_id_fkey FOREIGN KEY (release_train_deployment_id) REFERENCES public.release_train_deployment (id),
+    CONSTRAINT release_train_deployment_app_pipeline_id_fkey FOREIGN KEY (pipeline_id) REFERENCES public.pipeline (id)
+);
+
+COMMIT;
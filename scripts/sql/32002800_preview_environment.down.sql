@@ -0,0 +1,9 @@
+-- Begin Transaction
+BEGIN;
+
+DROP TABLE IF EXISTS public.preview_environment;
+DROP SEQUENCE IF EXISTS public.id_seq_preview_environment;
+DROP TABLE IF EXISTS public.preview_environment_config;
+DROP SEQUENCE IF EXISTS public.id_seq_preview_environment_config;
+
+COMMIT;
@@ -0,0 +1,64 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_preview_environment_config;
+
+-- builds of pull requests on the ci pipeline of the template cd pipeline are deployed in preview environments
+CREATE TABLE IF NOT EXISTS public.preview_environment_config
+(
+    id                      INTEGER     NOT NULL DEFAULT nextval('public.id_seq_preview_environment_config'::regclass),
+    app_id                  INTEGER     NOT NULL,
+    ci_pipeline_id          INTEGER     NOT NULL,
+    template_cd_pipeline_id INTEGER     NOT NULL,
+    ttl_hours               INTEGER     NOT NULL,
+    active                  BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_on              TIMESTAMPTZ NOT NULL,
+    created_by              INT4        NOT NULL,
+    updated_on              TIMESTAMPTZ NOT NULL,
+    updated_by              INT4        NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT preview_environment_config_app_id_fkey FOREIGN KEY (app_id) REFERENCES public.app (id),
+    CONSTRAINT preview_environment_config_ci_pipeline_id_fkey FOREIGN KEY (ci_pipeline_id) REFERENCES public.ci_pipeline (id),
+    CONSTRAINT preview_environment_config_template_cd_pipeline_id_fkey FOREIGN KEY (template_cd_pipeline_id) REFERENCES public.pipeline (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS preview_environment_config_ci_pipeline_id_unique
+    ON public.preview_environment_config (ci_pipeline_id)
+    WHERE active = TRUE;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_preview_environment;
+
+CREATE TABLE IF NOT EXISTS public.preview_environment
+(
+    id                            INTEGER      NOT NULL DEFAULT nextval('public.id_seq_preview_environment'::regclass),
+    preview_environment_config_id INTEGER      NOT NULL,
+    app_id                        INTEGER      NOT NULL,
+    git_material_id               INTEGER      NOT NULL,
+    pull_request_id               VARCHAR(250) NOT NULL,
+    source_branch                 VARCHAR(250),
+    pull_request_url              TEXT,
+    environment_id                INTEGER,
+    cd_pipeline_id                INTEGER,
+    ci_artifact_id                INTEGER,
+    status                        VARCHAR(50)  NOT NULL,
+    message                       TEXT,
+    last_activity_on              TIMESTAMPTZ  NOT NULL,
+    created_on                    TIMESTAMPTZ  NOT NULL,
+    created_by                    INT4         NOT NULL,
+    updated_on                    TIMESTAMPTZ  NOT NULL,
+    updated_by                    INT4         NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT preview_environment_config_id_fkey FOREIGN KEY (preview_environment_config_id) REFERENCES public.preview_environment_config (id),
+    CONSTRAINT preview_environment_app_id_fkey FOREIGN KEY (app_id) REFERENCES public.app (id),
+    CONSTRAINT preview_environment_git_material_id_fkey FOREIGN KEY (git_material_id) REFERENCES public.git_material (id)
+);
+
+-- pull request ids are unique only within a repo, a pull request has at most one live preview per app
+CREATE UNIQUE INDEX IF NOT EXISTS preview_environment_pull_request_unique
+    ON public.preview_environment (app_id, git_material_id, pull_request_id)
+    WHERE status != 'Deleted';
+
+CREATE INDEX IF NOT EXISTS preview_environment_pull_request_id_idx
+    ON public.preview_environment (pull_request_id);
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Begin Transaction
+BEGIN;
+
+DROP TABLE IF EXISTS public.hibernation_schedule_execution;
+DROP SEQUENCE IF EXISTS public.id_seq_hibernation_schedule_execution;
+DROP TABLE IF EXISTS public.hibernation_schedule;
+DROP SEQUENCE IF EXISTS public.id_seq_hibernation_schedule;
+
+COMMIT;
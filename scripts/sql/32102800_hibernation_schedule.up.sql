@@ -0,0 +1,55 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_hibernation_schedule;
+
+-- an environment or a resource group is hibernated at sleep_time and un-hibernated at wake_time
+-- on the configured week days, both times are evaluated in the configured timezone
+CREATE TABLE IF NOT EXISTS public.hibernation_schedule
+(
+    id                INTEGER      NOT NULL DEFAULT nextval('public.id_seq_hibernation_schedule'::regclass),
+    name              VARCHAR(250) NOT NULL,
+    environment_id    INTEGER,
+    resource_group_id INTEGER,
+    timezone          VARCHAR(100) NOT NULL,
+    sleep_time        VARCHAR(5)   NOT NULL,
+    wake_time         VARCHAR(5)   NOT NULL,
+    week_days         INTEGER[],
+    excluded_app_ids  INTEGER[],
+    keep_awake_until  TIMESTAMPTZ,
+    state             VARCHAR(50)  NOT NULL,
+    last_event_on     TIMESTAMPTZ  NOT NULL,
+    active            BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_on        TIMESTAMPTZ  NOT NULL,
+    created_by        INT4         NOT NULL,
+    updated_on        TIMESTAMPTZ  NOT NULL,
+    updated_by        INT4         NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT hibernation_schedule_environment_id_fkey FOREIGN KEY (environment_id) REFERENCES public.environment (id),
+    CONSTRAINT hibernation_schedule_resource_group_id_fkey FOREIGN KEY (resource_group_id) REFERENCES public.resource_group (id)
+);
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_hibernation_schedule_execution;
+
+CREATE TABLE IF NOT EXISTS public.hibernation_schedule_execution
+(
+    id                      INTEGER     NOT NULL DEFAULT nextval('public.id_seq_hibernation_schedule_execution'::regclass),
+    hibernation_schedule_id INTEGER     NOT NULL,
+    environment_id          INTEGER     NOT NULL,
+    action                  VARCHAR(50) NOT NULL,
+    trigger                 VARCHAR(50) NOT NULL,
+    status                  VARCHAR(50) NOT NULL,
+    app_response            TEXT,
+    message                 TEXT,
+    created_on              TIMESTAMPTZ NOT NULL,
+    created_by              INT4        NOT NULL,
+    updated_on              TIMESTAMPTZ NOT NULL,
+    updated_by              INT4        NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT hibernation_schedule_execution_schedule_id_fkey FOREIGN KEY (hibernation_schedule_id) REFERENCES public.hibernation_schedule (id)
+);
+
+CREATE INDEX IF NOT EXISTS hibernation_schedule_execution_schedule_id_idx
+    ON public.hibernation_schedule_execution (hibernation_schedule_id);
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Begin Transaction
+BEGIN;
+
+DROP TABLE IF EXISTS public.scim_group;
+DROP SEQUENCE IF EXISTS public.id_seq_scim_group;
+DROP TABLE IF EXISTS public.scim_user;
+DROP SEQUENCE IF EXISTS public.id_seq_scim_user;
+
+COMMIT;
@@ -0,0 +1,43 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_scim_user;
+
+-- attributes set by the identity provider on a user which are not stored on the user,
+-- a row exists for every user provisioned or deactivated through scim
+CREATE TABLE IF NOT EXISTS public.scim_user
+(
+    id           INTEGER      NOT NULL DEFAULT nextval('public.id_seq_scim_user'::regclass),
+    user_id      INTEGER      NOT NULL,
+    external_id  VARCHAR(250),
+    display_name VARCHAR(250),
+    given_name   VARCHAR(250),
+    family_name  VARCHAR(250),
+    created_on   TIMESTAMPTZ  NOT NULL,
+    created_by   INT4         NOT NULL,
+    updated_on   TIMESTAMPTZ  NOT NULL,
+    updated_by   INT4         NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT scim_user_user_id_key UNIQUE (user_id),
+    CONSTRAINT scim_user_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users (id)
+);
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_scim_group;
+
+-- role groups linked to the identity provider, provisioned is set for role groups created through scim
+CREATE TABLE IF NOT EXISTS public.scim_group
+(
+    id            INTEGER      NOT NULL DEFAULT nextval('public.id_seq_scim_group'::regclass),
+    role_group_id INTEGER      NOT NULL,
+    external_id   VARCHAR(250),
+    provisioned   BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_on    TIMESTAMPTZ  NOT NULL,
+    created_by    INT4         NOT NULL,
+    updated_on    TIMESTAMPTZ  NOT NULL,
+    updated_by    INT4         NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT scim_group_role_group_id_key UNIQUE (role_group_id),
+    CONSTRAINT scim_group_role_group_id_fkey FOREIGN KEY (role_group_id) REFERENCES public.role_group (id)
+);
+
+COMMIT;
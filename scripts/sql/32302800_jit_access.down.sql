@@ -0,0 +1,9 @@
+-- Begin Transaction
+BEGIN;
+
+DROP TABLE IF EXISTS public.jit_access_audit;
+DROP SEQUENCE IF EXISTS public.id_seq_jit_access_audit;
+DROP TABLE IF EXISTS public.jit_access_request;
+DROP SEQUENCE IF EXISTS public.id_seq_jit_access_request;
+
+COMMIT;
@@ -0,0 +1,51 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_jit_access_request;
+
+-- time bound access to a role filter requested by a user, granted_role_ids are the roles the user got
+-- on approval which are revoked on expiry, roles the user already had are not part of it
+CREATE TABLE IF NOT EXISTS public.jit_access_request
+(
+    id               INTEGER      NOT NULL DEFAULT nextval('public.id_seq_jit_access_request'::regclass),
+    user_id          INTEGER      NOT NULL,
+    role_filter      TEXT         NOT NULL,
+    duration_minutes INTEGER      NOT NULL,
+    reason           TEXT         NOT NULL,
+    status           VARCHAR(50)  NOT NULL,
+    approved_by      INTEGER,
+    approved_on      TIMESTAMPTZ,
+    expires_on       TIMESTAMPTZ,
+    closed_on        TIMESTAMPTZ,
+    granted_role_ids INTEGER[],
+    created_on       TIMESTAMPTZ  NOT NULL,
+    created_by       INT4         NOT NULL,
+    updated_on       TIMESTAMPTZ  NOT NULL,
+    updated_by       INT4         NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT jit_access_request_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users (id)
+);
+
+CREATE INDEX IF NOT EXISTS jit_access_request_status_expires_on_idx
+    ON public.jit_access_request (status, expires_on);
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_jit_access_audit;
+
+CREATE TABLE IF NOT EXISTS public.jit_access_audit
+(
+    id                    INTEGER     NOT NULL DEFAULT nextval('public.id_seq_jit_access_audit'::regclass),
+    jit_access_request_id INTEGER     NOT NULL,
+    action                VARCHAR(50) NOT NULL,
+    comment               TEXT,
+    created_on            TIMESTAMPTZ NOT NULL,
+    created_by            INT4        NOT NULL,
+    updated_on            TIMESTAMPTZ NOT NULL,
+    updated_by            INT4        NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT jit_access_audit_request_id_fkey FOREIGN KEY (jit_access_request_id) REFERENCES public.jit_access_request (id)
+);
+
+CREATE INDEX IF NOT EXISTS jit_access_audit_request_id_idx
+    ON public.jit_access_audit (jit_access_request_id);
+
+COMMIT;
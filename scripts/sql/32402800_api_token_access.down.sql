@@ -0,0 +1,13 @@
+-- Begin Transaction
+BEGIN;
+
+ALTER TABLE public.api_token
+    DROP COLUMN IF EXISTS scopes,
+    DROP COLUMN IF EXISTS allowed_cidrs,
+    DROP COLUMN IF EXISTS previous_version,
+    DROP COLUMN IF EXISTS previous_version_valid_until,
+    DROP COLUMN IF EXISTS last_used_at,
+    DROP COLUMN IF EXISTS last_used_by_ip,
+    DROP COLUMN IF EXISTS disabled_on;
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- Begin Transaction
+BEGIN;
+
+-- scopes and allowed_cidrs restrict a token further than the roles of its user, previous_version stays valid
+-- till previous_version_valid_until after a rotation and disabled_on is set for tokens not used for long
+ALTER TABLE public.api_token
+    ADD COLUMN IF NOT EXISTS scopes                       JSONB,
+    ADD COLUMN IF NOT EXISTS allowed_cidrs                TEXT[],
+    ADD COLUMN IF NOT EXISTS previous_version             INTEGER,
+    ADD COLUMN IF NOT EXISTS previous_version_valid_until TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS last_used_at                 TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS last_used_by_ip              VARCHAR(250),
+    ADD COLUMN IF NOT EXISTS disabled_on                  TIMESTAMPTZ;
+
+COMMIT;
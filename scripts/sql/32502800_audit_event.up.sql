@@ -0,0 +1,33 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_audit_event;
+
+-- every mutating api call, resource is the route template of the call and resource_ids its path variables.
+-- before_hash and after_hash are set for calls whose handler knows the state of the resource around the change
+CREATE TABLE IF NOT EXISTS public.audit_event
+(
+    id             INTEGER      NOT NULL DEFAULT nextval('public.id_seq_audit_event'::regclass),
+    user_id        INTEGER,
+    email_id       VARCHAR(250),
+    api_token_id   INTEGER,
+    method         VARCHAR(10)  NOT NULL,
+    path           TEXT         NOT NULL,
+    resource       TEXT         NOT NULL,
+    resource_ids   JSONB,
+    action         VARCHAR(50)  NOT NULL,
+    outcome        VARCHAR(50)  NOT NULL,
+    status_code    INTEGER,
+    client_ip      VARCHAR(250),
+    request_hash   VARCHAR(64),
+    before_hash    VARCHAR(64),
+    after_hash     VARCHAR(64),
+    duration_in_ms BIGINT,
+    created_on     TIMESTAMPTZ  NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS audit_event_created_on_idx ON public.audit_event (created_on);
+CREATE INDEX IF NOT EXISTS audit_event_user_id_idx ON public.audit_event (user_id);
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Begin Transaction
+BEGIN;
+
+DROP TABLE IF EXISTS public.project_guardrail;
+DROP SEQUENCE IF EXISTS public.id_seq_project_guardrail;
+
+COMMIT;
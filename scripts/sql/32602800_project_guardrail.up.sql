@@ -0,0 +1,30 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_project_guardrail;
+
+-- limits of a project, a zero limit or an empty array means no restriction.
+-- an environment is allowed if it is listed or belongs to a listed cluster
+CREATE TABLE IF NOT EXISTS public.project_guardrail
+(
+    id                          INTEGER     NOT NULL DEFAULT nextval('public.id_seq_project_guardrail'::regclass),
+    team_id                     INTEGER     NOT NULL,
+    max_apps                    INTEGER     NOT NULL DEFAULT 0,
+    max_environments            INTEGER     NOT NULL DEFAULT 0,
+    allowed_cluster_ids         INTEGER[],
+    allowed_environment_ids     INTEGER[],
+    allowed_chart_ref_ids       INTEGER[],
+    allowed_docker_registry_ids VARCHAR(250)[],
+    mandatory_app_labels        VARCHAR(250)[],
+    active                      BOOLEAN     NOT NULL,
+    created_on                  TIMESTAMPTZ NOT NULL,
+    created_by                  INTEGER     NOT NULL,
+    updated_on                  TIMESTAMPTZ NOT NULL,
+    updated_by                  INTEGER     NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT project_guardrail_team_id_fkey FOREIGN KEY (team_id) REFERENCES public.team (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS project_guardrail_team_id_active_idx ON public.project_guardrail (team_id) WHERE active = true;
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- Begin Transaction
+BEGIN;
+
+DROP TABLE IF EXISTS public.deployment_approval_action;
+DROP SEQUENCE IF EXISTS public.id_seq_deployment_approval_action;
+DROP TABLE IF EXISTS public.deployment_approval_request;
+DROP SEQUENCE IF EXISTS public.id_seq_deployment_approval_request;
+DROP TABLE IF EXISTS public.environment_protection_rule;
+DROP SEQUENCE IF EXISTS public.id_seq_environment_protection_rule;
+
+COMMIT;
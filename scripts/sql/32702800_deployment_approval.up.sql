@@ -0,0 +1,74 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_environment_protection_rule;
+
+-- deployments to a protected environment need approvals from members of the approver role groups
+CREATE TABLE IF NOT EXISTS public.environment_protection_rule
+(
+    id                   INTEGER     NOT NULL DEFAULT nextval('public.id_seq_environment_protection_rule'::regclass),
+    environment_id       INTEGER     NOT NULL,
+    required_approvals   INTEGER     NOT NULL,
+    approver_role_groups VARCHAR(250)[],
+    request_ttl_minutes  INTEGER     NOT NULL DEFAULT 0,
+    active               BOOLEAN     NOT NULL,
+    created_on           TIMESTAMPTZ NOT NULL,
+    created_by           INTEGER     NOT NULL,
+    updated_on           TIMESTAMPTZ NOT NULL,
+    updated_by           INTEGER     NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT environment_protection_rule_environment_id_fkey FOREIGN KEY (environment_id) REFERENCES public.environment (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS environment_protection_rule_environment_id_active_idx ON public.environment_protection_rule (environment_id) WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_deployment_approval_request;
+
+-- an approval is valid for the artifact and config snapshot it was requested with only
+CREATE TABLE IF NOT EXISTS public.deployment_approval_request
+(
+    id                   INTEGER     NOT NULL DEFAULT nextval('public.id_seq_deployment_approval_request'::regclass),
+    pipeline_id          INTEGER     NOT NULL,
+    app_id               INTEGER     NOT NULL,
+    environment_id       INTEGER     NOT NULL,
+    ci_artifact_id       INTEGER     NOT NULL,
+    config_snapshot_hash VARCHAR(64) NOT NULL,
+    override_request     TEXT        NOT NULL,
+    status               VARCHAR(50) NOT NULL,
+    required_approvals   INTEGER     NOT NULL,
+    approver_role_groups VARCHAR(250)[],
+    requested_by         INTEGER     NOT NULL,
+    expires_on           TIMESTAMPTZ NOT NULL,
+    closed_on            TIMESTAMPTZ,
+    failure_reason       TEXT,
+    created_on           TIMESTAMPTZ NOT NULL,
+    created_by           INTEGER     NOT NULL,
+    updated_on           TIMESTAMPTZ NOT NULL,
+    updated_by           INTEGER     NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT deployment_approval_request_pipeline_id_fkey FOREIGN KEY (pipeline_id) REFERENCES public.pipeline (id),
+    CONSTRAINT deployment_approval_request_ci_artifact_id_fkey FOREIGN KEY (ci_artifact_id) REFERENCES public.ci_artifact (id)
+);
+
+CREATE INDEX IF NOT EXISTS deployment_approval_request_pipeline_id_artifact_idx ON public.deployment_approval_request (pipeline_id, ci_artifact_id);
+CREATE INDEX IF NOT EXISTS deployment_approval_request_status_idx ON public.deployment_approval_request (status);
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_deployment_approval_action;
+
+CREATE TABLE IF NOT EXISTS public.deployment_approval_action
+(
+    id                             INTEGER     NOT NULL DEFAULT nextval('public.id_seq_deployment_approval_action'::regclass),
+    deployment_approval_request_id INTEGER     NOT NULL,
+    action                         VARCHAR(50) NOT NULL,
+    comment                        TEXT,
+    created_on                     TIMESTAMPTZ NOT NULL,
+    created_by                     INTEGER     NOT NULL,
+    updated_on                     TIMESTAMPTZ NOT NULL,
+    updated_by                     INTEGER     NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT deployment_approval_action_request_id_fkey FOREIGN KEY (deployment_approval_request_id) REFERENCES public.deployment_approval_request (id)
+);
+
+CREATE INDEX IF NOT EXISTS deployment_approval_action_request_id_idx ON public.deployment_approval_action (deployment_approval_request_id);
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- Begin Transaction
+BEGIN;
+
+-- only a single sso config can be active without connector ids
+UPDATE public.sso_login_config SET active = false WHERE active = true AND id <> (SELECT id FROM public.sso_login_config WHERE active = true ORDER BY updated_on DESC LIMIT 1);
+
+DROP INDEX IF EXISTS public.idx_sso_login_config_connector_id;
+ALTER TABLE public.sso_login_config DROP COLUMN IF EXISTS provisioning_policy;
+ALTER TABLE public.sso_login_config DROP COLUMN IF EXISTS connector_id;
+
+COMMIT;
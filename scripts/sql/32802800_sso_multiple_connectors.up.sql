@@ -0,0 +1,12 @@
+-- Begin Transaction
+BEGIN;
+
+ALTER TABLE public.sso_login_config ADD COLUMN IF NOT EXISTS connector_id VARCHAR(250);
+ALTER TABLE public.sso_login_config ADD COLUMN IF NOT EXISTS provisioning_policy TEXT;
+
+-- connector id is the dex connector id held in the stored config
+UPDATE public.sso_login_config SET connector_id = config::jsonb ->> 'id' WHERE connector_id IS NULL AND config IS NOT NULL AND config <> '';
+
+CREATE INDEX IF NOT EXISTS idx_sso_login_config_connector_id ON public.sso_login_config (connector_id) WHERE active = true;
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Begin Transaction
+BEGIN;
+
+DROP TABLE IF EXISTS public.cluster_connection_health;
+DROP SEQUENCE IF EXISTS public.id_seq_cluster_connection_health;
+
+COMMIT;
@@ -0,0 +1,23 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_cluster_connection_health;
+
+-- one row per periodic connectivity check of a cluster, rows older than the retention period are deleted
+CREATE TABLE IF NOT EXISTS public.cluster_connection_health
+(
+    id             INTEGER     NOT NULL DEFAULT nextval('public.id_seq_cluster_connection_health'::regclass),
+    cluster_id     INTEGER     NOT NULL,
+    healthy        BOOLEAN     NOT NULL,
+    latency_ms     BIGINT      NOT NULL,
+    server_version VARCHAR(100),
+    error_message  TEXT,
+    checked_on     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT cluster_connection_health_cluster_id_fkey FOREIGN KEY (cluster_id) REFERENCES public.cluster (id)
+);
+
+CREATE INDEX IF NOT EXISTS cluster_connection_health_cluster_id_checked_on_idx ON public.cluster_connection_health (cluster_id, checked_on);
+CREATE INDEX IF NOT EXISTS cluster_connection_health_checked_on_idx ON public.cluster_connection_health (checked_on);
+
+COMMIT;
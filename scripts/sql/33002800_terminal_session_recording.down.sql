@@ -0,0 +1,7 @@
+-- Begin Transaction
+BEGIN;
+
+DROP TABLE IF EXISTS public.terminal_session_recording;
+DROP SEQUENCE IF EXISTS public.id_seq_terminal_session_recording;
+
+COMMIT;
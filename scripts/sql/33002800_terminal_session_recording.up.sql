@@ -0,0 +1,32 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_terminal_session_recording;
+
+-- metadata of recorded terminal sessions, the asciicast recordings are kept in the configured storage
+CREATE TABLE IF NOT EXISTS public.terminal_session_recording
+(
+    id             INTEGER      NOT NULL DEFAULT nextval('public.id_seq_terminal_session_recording'::regclass),
+    session_id     VARCHAR(100) NOT NULL,
+    user_id        INTEGER      NOT NULL,
+    user_email     VARCHAR(250),
+    cluster_id     INTEGER,
+    namespace      VARCHAR(250),
+    pod_name       VARCHAR(250),
+    container_name VARCHAR(250),
+    shell          VARCHAR(50),
+    app_id         INTEGER,
+    environment_id INTEGER,
+    storage_key    VARCHAR(500) NOT NULL,
+    size_bytes     BIGINT       NOT NULL,
+    -- commands typed in the session separated by new lines, searched with ILIKE
+    commands       TEXT,
+    started_on     TIMESTAMPTZ  NOT NULL,
+    ended_on       TIMESTAMPTZ  NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS terminal_session_recording_started_on_idx ON public.terminal_session_recording (started_on);
+CREATE INDEX IF NOT EXISTS terminal_session_recording_user_id_idx ON public.terminal_session_recording (user_id);
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Begin Transaction
+BEGIN;
+
+DROP TABLE IF EXISTS public.terminal_command_policy;
+DROP SEQUENCE IF EXISTS public.id_seq_terminal_command_policy;
+
+COMMIT;
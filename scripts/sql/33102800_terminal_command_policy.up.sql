@@ -0,0 +1,27 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_terminal_command_policy;
+
+-- commands allowed in the terminal sessions of a cluster or of an environment, exactly one of them is set
+CREATE TABLE IF NOT EXISTS public.terminal_command_policy
+(
+    id               INTEGER      NOT NULL DEFAULT nextval('public.id_seq_terminal_command_policy'::regclass),
+    name             VARCHAR(250) NOT NULL,
+    cluster_id       INTEGER,
+    environment_id   INTEGER,
+    blocked_patterns TEXT[],
+    allowed_patterns TEXT[],
+    read_only        BOOLEAN      NOT NULL DEFAULT FALSE,
+    active           BOOLEAN      NOT NULL,
+    created_on       TIMESTAMPTZ  NOT NULL,
+    created_by       INTEGER      NOT NULL,
+    updated_on       TIMESTAMPTZ  NOT NULL,
+    updated_by       INTEGER      NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS terminal_command_policy_cluster_id_idx ON public.terminal_command_policy (cluster_id) WHERE active = true;
+CREATE INDEX IF NOT EXISTS terminal_command_policy_environment_id_idx ON public.terminal_command_policy (environment_id) WHERE active = true;
+
+COMMIT;
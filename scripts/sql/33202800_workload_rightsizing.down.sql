@@ -0,0 +1,9 @@
+-- Begin Transaction
+BEGIN;
+
+DROP TABLE IF EXISTS public.node_group_price;
+DROP SEQUENCE IF EXISTS public.id_seq_node_group_price;
+DROP TABLE IF EXISTS public.workload_resource_sample;
+DROP SEQUENCE IF EXISTS public.id_seq_workload_resource_sample;
+
+COMMIT;
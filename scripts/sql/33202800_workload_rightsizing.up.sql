@@ -0,0 +1,53 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_workload_resource_sample;
+
+-- usage of the running containers sampled from the metrics server, requests and limits are the ones at sampling time
+CREATE TABLE IF NOT EXISTS public.workload_resource_sample
+(
+    id                     INTEGER      NOT NULL DEFAULT nextval('public.id_seq_workload_resource_sample'::regclass),
+    cluster_id             INTEGER      NOT NULL,
+    namespace              VARCHAR(250) NOT NULL,
+    workload_kind          VARCHAR(100) NOT NULL,
+    workload_name          VARCHAR(250) NOT NULL,
+    pod_name               VARCHAR(250) NOT NULL,
+    container_name         VARCHAR(250) NOT NULL,
+    primary_container      BOOLEAN      NOT NULL,
+    node_group             VARCHAR(250),
+    app_id                 INTEGER,
+    env_id                 INTEGER,
+    cpu_usage_millicores   BIGINT       NOT NULL,
+    memory_usage_bytes     BIGINT       NOT NULL,
+    cpu_request_millicores BIGINT       NOT NULL,
+    cpu_limit_millicores   BIGINT       NOT NULL,
+    memory_request_bytes   BIGINT       NOT NULL,
+    memory_limit_bytes     BIGINT       NOT NULL,
+    sampled_on             TIMESTAMPTZ  NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS workload_resource_sample_cluster_id_sampled_on_idx ON public.workload_resource_sample (cluster_id, sampled_on);
+CREATE INDEX IF NOT EXISTS workload_resource_sample_sampled_on_idx ON public.workload_resource_sample (sampled_on);
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_node_group_price;
+
+-- hourly price of the resources of the nodes of a node group, node group * prices the nodes of the other groups
+CREATE TABLE IF NOT EXISTS public.node_group_price
+(
+    id                      INTEGER          NOT NULL DEFAULT nextval('public.id_seq_node_group_price'::regclass),
+    cluster_id              INTEGER          NOT NULL,
+    node_group              VARCHAR(250)     NOT NULL,
+    cpu_core_hourly_price   DOUBLE PRECISION NOT NULL,
+    memory_gib_hourly_price DOUBLE PRECISION NOT NULL,
+    active                  BOOLEAN          NOT NULL,
+    created_on              TIMESTAMPTZ      NOT NULL,
+    created_by              INTEGER          NOT NULL,
+    updated_on              TIMESTAMPTZ      NOT NULL,
+    updated_by              INTEGER          NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS node_group_price_cluster_id_node_group_idx ON public.node_group_price (cluster_id, node_group) WHERE active = true;
+
+COMMIT;
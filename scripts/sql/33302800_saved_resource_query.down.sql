@@ -0,0 +1,7 @@
+-- Begin Transaction
+BEGIN;
+
+DROP TABLE IF EXISTS public.saved_resource_query;
+DROP SEQUENCE IF EXISTS public.id_seq_saved_resource_query;
+
+COMMIT;
@@ -0,0 +1,23 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_saved_resource_query;
+
+-- resource browser searches saved by users, query holds the search request as json
+CREATE TABLE IF NOT EXISTS public.saved_resource_query
+(
+    id          INTEGER      NOT NULL DEFAULT nextval('public.id_seq_saved_resource_query'::regclass),
+    name        VARCHAR(100) NOT NULL,
+    description VARCHAR(300),
+    query       TEXT         NOT NULL,
+    active      BOOLEAN      NOT NULL,
+    created_on  TIMESTAMPTZ  NOT NULL,
+    created_by  INTEGER      NOT NULL,
+    updated_on  TIMESTAMPTZ  NOT NULL,
+    updated_by  INTEGER      NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS saved_resource_query_created_by_name_unique_idx ON public.saved_resource_query (created_by, name) WHERE active = true;
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- Begin Transaction
+BEGIN;
+
+ALTER TABLE public.ephemeral_container DROP COLUMN IF EXISTS cleaned_up_on;
+ALTER TABLE public.ephemeral_container DROP COLUMN IF EXISTS expires_on;
+ALTER TABLE public.ephemeral_container DROP COLUMN IF EXISTS debug_profile_id;
+DROP TABLE IF EXISTS public.debug_profile;
+DROP SEQUENCE IF EXISTS public.id_seq_debug_profile;
+
+COMMIT;
@@ -0,0 +1,29 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_debug_profile;
+
+-- admin defined templates of ephemeral debug containers, security_context holds the json of the container security context
+CREATE TABLE IF NOT EXISTS public.debug_profile
+(
+    id               INTEGER      NOT NULL DEFAULT nextval('public.id_seq_debug_profile'::regclass),
+    name             VARCHAR(250) NOT NULL,
+    description      TEXT,
+    images           TEXT[]       NOT NULL,
+    startup_commands TEXT[],
+    security_context TEXT,
+    environment_ids  INTEGER[],
+    ttl_seconds      INTEGER      NOT NULL DEFAULT 0,
+    active           BOOLEAN      NOT NULL,
+    created_on       TIMESTAMPTZ  NOT NULL,
+    created_by       INTEGER      NOT NULL,
+    updated_on       TIMESTAMPTZ  NOT NULL,
+    updated_by       INTEGER      NOT NULL,
+    PRIMARY KEY (id)
+);
+
+ALTER TABLE public.ephemeral_container ADD COLUMN IF NOT EXISTS debug_profile_id INTEGER;
+ALTER TABLE public.ephemeral_container ADD COLUMN IF NOT EXISTS expires_on TIMESTAMPTZ;
+ALTER TABLE public.ephemeral_container ADD COLUMN IF NOT EXISTS cleaned_up_on TIMESTAMPTZ;
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Begin Transaction
+BEGIN;
+
+DROP TABLE IF EXISTS public.k8s_event_archive;
+DROP SEQUENCE IF EXISTS public.id_seq_k8s_event_archive;
+
+COMMIT;
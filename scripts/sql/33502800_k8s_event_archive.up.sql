@@ -0,0 +1,32 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_k8s_event_archive;
+
+-- kubernetes events of the namespaces of environments kept beyond the retention of the api server,
+-- app_id is 0 for events of objects which are not of a devtron app
+CREATE TABLE IF NOT EXISTS public.k8s_event_archive
+(
+    id             INTEGER      NOT NULL DEFAULT nextval('public.id_seq_k8s_event_archive'::regclass),
+    cluster_id     INTEGER      NOT NULL,
+    environment_id INTEGER      NOT NULL,
+    app_id         INTEGER      NOT NULL DEFAULT 0,
+    namespace      VARCHAR(250) NOT NULL,
+    event_uid      VARCHAR(100) NOT NULL,
+    involved_kind  VARCHAR(100),
+    involved_name  VARCHAR(500),
+    reason         VARCHAR(250),
+    message        TEXT,
+    type           VARCHAR(50),
+    count          INTEGER      NOT NULL DEFAULT 1,
+    first_seen     TIMESTAMPTZ  NOT NULL,
+    last_seen      TIMESTAMPTZ  NOT NULL,
+    source         VARCHAR(250),
+    PRIMARY KEY (id),
+    UNIQUE (cluster_id, event_uid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_k8s_event_archive_app_env_last_seen ON public.k8s_event_archive (app_id, environment_id, last_seen);
+CREATE INDEX IF NOT EXISTS idx_k8s_event_archive_last_seen ON public.k8s_event_archive (last_seen);
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Begin Transaction
+BEGIN;
+
+DROP TABLE IF EXISTS public.namespace_template_environment;
+DROP SEQUENCE IF EXISTS public.id_seq_namespace_template_environment;
+DROP TABLE IF EXISTS public.namespace_template;
+DROP SEQUENCE IF EXISTS public.id_seq_namespace_template;
+
+COMMIT;
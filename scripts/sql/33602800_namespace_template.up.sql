@@ -0,0 +1,47 @@
+-- Begin Transaction
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_namespace_template;
+
+-- baseline objects applied to the namespace of an environment, manifest can hold scoped variables and
+-- image pull secrets of registry_ids are synced into the namespace and added to its service accounts
+CREATE TABLE IF NOT EXISTS public.namespace_template
+(
+    id           INTEGER      NOT NULL DEFAULT nextval('public.id_seq_namespace_template'::regclass),
+    name         VARCHAR(250) NOT NULL,
+    description  TEXT,
+    manifest     TEXT         NOT NULL,
+    registry_ids TEXT[],
+    is_default   BOOLEAN      NOT NULL DEFAULT FALSE,
+    active       BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_on   TIMESTAMPTZ  NOT NULL,
+    created_by   INTEGER      NOT NULL,
+    updated_on   TIMESTAMPTZ  NOT NULL,
+    updated_by   INTEGER      NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE SEQUENCE IF NOT EXISTS public.id_seq_namespace_template_environment;
+
+-- template last applied to the namespace of an environment and the objects applied from it
+CREATE TABLE IF NOT EXISTS public.namespace_template_environment
+(
+    id               INTEGER     NOT NULL DEFAULT nextval('public.id_seq_namespace_template_environment'::regclass),
+    template_id      INTEGER     NOT NULL,
+    environment_id   INTEGER     NOT NULL,
+    applied_manifest TEXT,
+    status           VARCHAR(50) NOT NULL,
+    message          TEXT,
+    applied_on       TIMESTAMPTZ NOT NULL,
+    created_on       TIMESTAMPTZ NOT NULL,
+    created_by       INTEGER     NOT NULL,
+    updated_on       TIMESTAMPTZ NOT NULL,
+    updated_by       INTEGER     NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (environment_id),
+    CONSTRAINT namespace_template_environment_template_id_fkey FOREIGN KEY (template_id) REFERENCES public.namespace_template (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_namespace_template_environment_template_id ON public.namespace_template_environment (template_id);
+
+COMMIT;
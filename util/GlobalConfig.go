@@ -48,6 +48,10 @@ type GlobalEnvVariables struct {
 
 type GlobalClusterConfig struct {
 	ClusterStatusCronTime int `env:"CLUSTER_STATUS_CRON_TIME" envDefault:"15"`
+	// ClusterHealthRetentionDays is how long the connectivity checks of clusters are kept
+	ClusterHealthRetentionDays int `env:"CLUSTER_HEALTH_RETENTION_DAYS" envDefault:"7"`
+	// ClusterHealthFlapThreshold is the number of health changes in a window after which a cluster is flapping
+	ClusterHealthFlapThreshold int `env:"CLUSTER_HEALTH_FLAP_THRESHOLD" envDefault:"3"`
 }
 
 type DevtronSecretConfig struct {
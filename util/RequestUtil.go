@@ -18,6 +18,7 @@ package util
 
 import (
 	"net/http"
+	"strings"
 )
 
 const xForwardedForHeaderName = "X-Forwarded-For"
@@ -31,3 +32,27 @@ func GetClientIP(r *http.Request) string {
 	}
 	return r.RemoteAddr
 }
+
+// GetTrustedClientIP returns the address of the client as seen by the outermost of the trustedProxyCount reverse
+// proxies in front of the server. Every proxy appends the address it got the request from to the forwarded-for
+// header, entries left of the one added by the outermost proxy come from the client and are not trusted.
+// The remote address is returned when no proxy is trusted
+func GetTrustedClientIP(r *http.Request, trustedProxyCount int) string {
+	if trustedProxyCount <= 0 {
+		return r.RemoteAddr
+	}
+	forwarded := make([]string, 0)
+	for _, header := range r.Header.Values(xForwardedForHeaderName) {
+		for _, address := range strings.Split(header, ",") {
+			forwarded = append(forwarded, strings.TrimSpace(address))
+		}
+	}
+	if len(forwarded) == 0 {
+		return r.RemoteAddr
+	}
+	if len(forwarded) < trustedProxyCount {
+		// every entry was added by a trusted proxy, the left-most one is the client
+		return forwarded[0]
+	}
+	return forwarded[len(forwarded)-trustedProxyCount]
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2020-2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestGetTrustedClientIP(t *testing.T) {
+	newRequest := func(forwardedFor ...string) *http.Request {
+		r := &http.Request{RemoteAddr: "10.0.0.5:41000", Header: http.Header{}}
+		for _, header := range forwardedFor {
+			r.Header.Add(xForwardedForHeaderName, header)
+		}
+		return r
+	}
+	tests := []struct {
+		name              string
+		request           *http.Request
+		trustedProxyCount int
+		want              string
+	}{
+		{name: "no trusted proxy ignores the header", request: newRequest("1.1.1.1"), want: "10.0.0.5:41000"},
+		{name: "no forwarded address", request: newRequest(), trustedProxyCount: 1, want: "10.0.0.5:41000"},
+		{name: "address spoofed by the client", request: newRequest("1.1.1.1, 203.0.113.7"), trustedProxyCount: 1, want: "203.0.113.7"},
+		{name: "two proxies", request: newRequest("1.1.1.1, 203.0.113.7", "10.0.0.9"), trustedProxyCount: 2, want: "203.0.113.7"},
+		{name: "fewer entries than proxies", request: newRequest("203.0.113.7"), trustedProxyCount: 2, want: "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetTrustedClientIP(tt.request, tt.trustedProxyCount))
+		})
+	}
+}